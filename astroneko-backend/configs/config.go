@@ -3,6 +3,7 @@ package configs
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
@@ -14,6 +15,12 @@ type Config struct {
 	Postgres    `mapstructure:"postgres"`
 	Firebase    `mapstructure:"firebase"`
 	ExternalURL `mapstructure:"external_url"`
+	DataExport  `mapstructure:"data_export"`
+	CRM         `mapstructure:"crm"`
+	Referral    `mapstructure:"referral"`
+	WaitingList `mapstructure:"waiting_list"`
+	Mail        `mapstructure:"mail"`
+	Proxy       `mapstructure:"proxy"`
 }
 
 // App struct
@@ -46,6 +53,124 @@ type ExternalURL struct {
 	Token        string `mapstructure:"token"`
 }
 
+// DataExport struct
+type DataExport struct {
+	// SigningKey signs download links; every instance must use the same key
+	SigningKey string `mapstructure:"signing_key"`
+}
+
+// CRM struct
+type CRM struct {
+	// BootstrapToken allows creating the first CRM admin; leave empty to disable bootstrapping
+	BootstrapToken string `mapstructure:"bootstrap_token"`
+	JWT            CRMJWT `mapstructure:"jwt"`
+	// RequireTwoFactor makes every CRM user enroll a TOTP authenticator before they can log in
+	RequireTwoFactor bool `mapstructure:"require_two_factor"`
+}
+
+// CRMJWT struct
+type CRMJWT struct {
+	// ActiveKeyID names the key new access tokens are signed with. To rotate, add a new
+	// key, make it active, and remove the old one once its tokens have expired.
+	ActiveKeyID string          `mapstructure:"active_key_id"`
+	Keys        []CRMSigningKey `mapstructure:"keys"`
+}
+
+// CRMSigningKey struct
+type CRMSigningKey struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret"`
+}
+
+// Referral struct
+type Referral struct {
+	// Rewards decides what inviters earn when their codes are redeemed; leave out for the defaults
+	Rewards *ReferralRewards `mapstructure:"rewards"`
+	// UserCodes decides how many codes users hold and when they are replaced; leave out for the defaults
+	UserCodes *ReferralUserCodes `mapstructure:"user_codes"`
+	// Vanity decides who can claim a code of their choosing; leave out for the defaults
+	Vanity *ReferralVanity `mapstructure:"vanity"`
+	// Links configures deep links and QR codes; leave out to disable them
+	Links *ReferralLinks `mapstructure:"links"`
+}
+
+// ReferralLinks struct
+type ReferralLinks struct {
+	// BaseURL is the public URL this API is reachable at; deep links are BaseURL/r/{code}
+	BaseURL string `mapstructure:"base_url"`
+	// LandingURL is the app page deep links redirect to, with ref and click_id added
+	LandingURL string `mapstructure:"landing_url"`
+}
+
+// ReferralVanity struct
+type ReferralVanity struct {
+	// MinInvites is how many redemptions of a user's codes unlock a vanity code
+	MinInvites int `mapstructure:"min_invites"`
+	MinLength  int `mapstructure:"min_length"`
+	MaxLength  int `mapstructure:"max_length"`
+	// BlockedWords are rejected anywhere in a vanity code, on top of the built-in list
+	BlockedWords []string `mapstructure:"blocked_words"`
+}
+
+// ReferralUserCodes struct
+type ReferralUserCodes struct {
+	// CodesPerTier maps a tier to the number of codes its users hold; other tiers get DefaultCodes
+	CodesPerTier map[string]int `mapstructure:"codes_per_tier"`
+	DefaultCodes int            `mapstructure:"default_codes"`
+	// TopUpAfter is how many codes must be redeemed or expired before they are replaced, 0 for never
+	TopUpAfter int `mapstructure:"top_up_after"`
+	// ExpiresIn is how long an unused code stays valid, for example 720h; 0 for never
+	ExpiresIn time.Duration `mapstructure:"expires_in"`
+	// Length is the number of random characters before the check character
+	Length int `mapstructure:"length"`
+}
+
+// ReferralRewards struct
+type ReferralRewards struct {
+	// BonusReplies are credited once per redemption and spent after the daily limit
+	BonusReplies int `mapstructure:"bonus_replies"`
+	// DailyReplies raise the daily limit per redemption, up to MaxDailyReplies in total
+	DailyReplies    int                   `mapstructure:"daily_replies"`
+	MaxDailyReplies int                   `mapstructure:"max_daily_replies"`
+	Badges          []ReferralRewardBadge `mapstructure:"badges"`
+}
+
+// ReferralRewardBadge struct
+type ReferralRewardBadge struct {
+	Badge   string `mapstructure:"badge"`
+	Invites int    `mapstructure:"invites"`
+}
+
+// WaitingList struct
+type WaitingList struct {
+	// StatusTokenKey signs the status links emailed on joining; every instance must use the same key
+	StatusTokenKey string `mapstructure:"status_token_key"`
+	// StatusTokenTTL is how long a status link works, for example 2160h; 0 for the default
+	StatusTokenTTL time.Duration `mapstructure:"status_token_ttl"`
+	// DisposableDomains are refused on top of the built-in list of disposable email domains
+	DisposableDomains []string `mapstructure:"disposable_domains"`
+}
+
+// Mail struct
+type Mail struct {
+	// Host is the SMTP server; leave empty to log emails instead of sending them
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// Proxy struct
+type Proxy struct {
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers in front of the
+	// API. Header is only read on requests from them; leave empty to use the peer address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// Header carries the client IP set by the trusted proxies, for example X-Real-IP. It must
+	// be a header they overwrite rather than append to.
+	Header string `mapstructure:"header"`
+}
+
 var config Config
 
 // InitViper func
@@ -37,6 +37,11 @@ func (g *GormAdapter) Rollback() error {
 	return g.db.Rollback().Error
 }
 
+// Error returns the error of the call that returned this adapter
+func (g *GormAdapter) Error() error {
+	return g.db.Error
+}
+
 // Create inserts a new record
 func (g *GormAdapter) Create(value any) error {
 	return g.db.Create(value).Error
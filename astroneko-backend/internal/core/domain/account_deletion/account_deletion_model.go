@@ -0,0 +1,97 @@
+package account_deletion
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of an account deletion request
+type Status string
+
+const (
+	// StatusAwaitingConfirmation means the user asked to delete but has not confirmed yet
+	StatusAwaitingConfirmation Status = "awaiting_confirmation"
+	// StatusScheduled means the deletion is confirmed and waits for the grace period to end
+	StatusScheduled Status = "scheduled"
+	StatusCancelled Status = "cancelled"
+	// StatusPurged means the owned data is gone but the Firebase account still has to be deleted
+	StatusPurged    Status = "purged"
+	StatusCompleted Status = "completed"
+)
+
+// Audit events recorded for a deletion request
+const (
+	EventRequested       = "requested"
+	EventConfirmed       = "confirmed"
+	EventAdminScheduled  = "admin_scheduled"
+	EventCancelled       = "cancelled"
+	EventDataPurged      = "data_purged"
+	EventPurgeFailed     = "purge_failed"
+	EventFirebaseDeleted = "firebase_deleted"
+	EventFirebaseFailed  = "firebase_delete_failed"
+)
+
+const (
+	// GracePeriod is how long a confirmed deletion can still be cancelled
+	GracePeriod = 7 * 24 * time.Hour
+	// ConfirmationTTL is how long the confirmation token stays valid
+	ConfirmationTTL = 15 * time.Minute
+)
+
+// DeletionRequest tracks one account deletion from request to Firebase removal.
+// It outlives the user row so the run stays auditable; no personal data is kept
+// once the deletion completes.
+type DeletionRequest struct {
+	shared.NoDeletedModel
+	UserID                uuid.UUID  `json:"user_id" gorm:"type:uuid;index:idx_account_deletions_user_id;not null"`
+	FirebaseUID           *string    `json:"-" gorm:"type:varchar(128)"`
+	Status                Status     `json:"status" gorm:"type:varchar(32);not null"`
+	ConfirmationTokenHash *string    `json:"-" gorm:"type:varchar(64)"`
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at"`
+	ConfirmedAt           *time.Time `json:"confirmed_at"`
+	ScheduledFor          *time.Time `json:"scheduled_for"`
+	CancelledAt           *time.Time `json:"cancelled_at"`
+	PurgedAt              *time.Time `json:"purged_at"`
+	CompletedAt           *time.Time `json:"completed_at"`
+	Attempts              int        `json:"attempts" gorm:"not null;default:0"`
+	LastError             *string    `json:"last_error"`
+}
+
+func (DeletionRequest) TableName() string {
+	return "astroneko_account_deletions"
+}
+
+// IsActive reports whether the request still blocks a new one
+func (r *DeletionRequest) IsActive() bool {
+	return r.Status == StatusAwaitingConfirmation || r.Status == StatusScheduled
+}
+
+// AuditEvent is an append-only record of a step of a deletion run
+type AuditEvent struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	DeletionRequestID uuid.UUID `json:"deletion_request_id" gorm:"type:uuid;index:idx_account_deletion_audit_request_id;not null"`
+	UserID            uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Event             string    `json:"event" gorm:"type:varchar(32);not null"`
+	Detail            string    `json:"detail" gorm:"type:text"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (AuditEvent) TableName() string {
+	return "astroneko_account_deletion_audit"
+}
+
+// PurgeSummary counts the rows removed or anonymized for a user
+type PurgeSummary struct {
+	Messages          int64 `json:"messages"`
+	Sessions          int64 `json:"sessions"`
+	People            int64 `json:"people"`
+	ReferralLogs      int64 `json:"referral_logs"`
+	AnonymizedRedeems int64 `json:"anonymized_redemptions"`
+	UserReferralCodes int64 `json:"user_referral_codes"`
+	GuestUsageRecords int64 `json:"guest_usage_records"`
+	DataExports       int64 `json:"data_exports"`
+	Users             int64 `json:"users"`
+}
@@ -0,0 +1,7 @@
+package account_deletion
+
+// DeleteAccountRequest starts or confirms an account deletion.
+// Without a token a confirmation token is issued; sending it back schedules the deletion.
+type DeleteAccountRequest struct {
+	ConfirmationToken string `json:"confirmation_token" validate:"omitempty,len=64,hexadecimal"`
+}
@@ -0,0 +1,22 @@
+package account_deletion
+
+import "time"
+
+type DeletionStatusResponse struct {
+	Status Status `json:"status"`
+	// ConfirmationToken is only returned when the deletion is first requested
+	ConfirmationToken     string     `json:"confirmation_token,omitempty"`
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+	ScheduledFor          *time.Time `json:"scheduled_for,omitempty"`
+	RequestedAt           time.Time  `json:"requested_at"`
+}
+
+// ToStatusResponse converts a deletion request to its API representation
+func (r *DeletionRequest) ToStatusResponse() *DeletionStatusResponse {
+	return &DeletionStatusResponse{
+		Status:                r.Status,
+		ConfirmationExpiresAt: r.ConfirmationExpiresAt,
+		ScheduledFor:          r.ScheduledFor,
+		RequestedAt:           r.CreatedAt,
+	}
+}
@@ -1,5 +1,10 @@
 package agent
 
+import (
+	"astroneko-backend/internal/core/domain/compatibility"
+	"astroneko-backend/internal/core/domain/numerology"
+)
+
 type ClearStateRequest struct {
 	SessionID string `json:"session_id"`
 }
@@ -8,4 +13,79 @@ type ReplyRequest struct {
 	Text      string `json:"text" validate:"required"`
 	UserID    string `json:"user_id,omitempty"`
 	SessionID string `json:"session_id,omitempty"`
+
+	// Numerology asks the server to compute numerology numbers for this reply
+	Numerology *NumerologyInput `json:"numerology,omitempty"`
+
+	// The contexts below are only ever set by the server and are never read from a request body.
+	// They reach the agent through ToAPIRequest.
+
+	// AstrologyContext is attached by the server for users with a birth profile
+	AstrologyContext *AstrologyContext `json:"-"`
+
+	// NumerologyContext is attached by the server when Numerology is requested
+	NumerologyContext *NumerologyContext `json:"-"`
+
+	// CompatibilityContext is attached by the server for compatibility readings
+	CompatibilityContext *CompatibilityContext `json:"-"`
+}
+
+// ReplyRequestToAPI is the reply payload sent to the agent API
+type ReplyRequestToAPI struct {
+	Text                 string                `json:"text"`
+	UserID               string                `json:"user_id,omitempty"`
+	SessionID            string                `json:"session_id,omitempty"`
+	AstrologyContext     *AstrologyContext     `json:"astrology_context,omitempty"`
+	NumerologyContext    *NumerologyContext    `json:"numerology_context,omitempty"`
+	CompatibilityContext *CompatibilityContext `json:"compatibility_context,omitempty"`
+}
+
+func (r *ReplyRequest) ToAPIRequest() *ReplyRequestToAPI {
+	return &ReplyRequestToAPI{
+		Text:                 r.Text,
+		UserID:               r.UserID,
+		SessionID:            r.SessionID,
+		AstrologyContext:     r.AstrologyContext,
+		NumerologyContext:    r.NumerologyContext,
+		CompatibilityContext: r.CompatibilityContext,
+	}
+}
+
+// AstrologyContext summarizes a user's natal chart for personalized readings
+type AstrologyContext struct {
+	SunSign    string               `json:"sun_sign"`
+	MoonSign   string               `json:"moon_sign"`
+	RisingSign string               `json:"rising_sign,omitempty"`
+	Placements []AstrologyPlacement `json:"placements"`
+}
+
+// AstrologyPlacement represents a body's sign and house in the natal chart
+type AstrologyPlacement struct {
+	Body       string `json:"body"`
+	Sign       string `json:"sign"`
+	House      int    `json:"house,omitempty"`
+	Retrograde bool   `json:"retrograde,omitempty"`
+}
+
+// NumerologyInput names what to analyze. An empty input still adds the
+// life path number of a signed-in user with a birth profile.
+type NumerologyInput struct {
+	Name  string `json:"name,omitempty" validate:"omitempty,max=200"`
+	Phone string `json:"phone,omitempty" validate:"omitempty,max=32"`
+}
+
+// NumerologyContext carries numbers computed by the server so the agent only interprets them
+type NumerologyContext struct {
+	Name     *numerology.NameAnalysis     `json:"name,omitempty"`
+	LifePath *numerology.LifePathAnalysis `json:"life_path,omitempty"`
+	Phone    *numerology.PhoneAnalysis    `json:"phone,omitempty"`
+}
+
+// CompatibilityContext describes the other person of a compatibility reading and
+// the computed breakdown, so the agent only interprets the scores
+type CompatibilityContext struct {
+	PersonName      string                   `json:"person_name"`
+	Relationship    string                   `json:"relationship"`
+	PersonAstrology *AstrologyContext        `json:"person_astrology"`
+	Breakdown       *compatibility.Breakdown `json:"breakdown"`
 }
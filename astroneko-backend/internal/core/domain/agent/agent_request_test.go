@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplyRequest_IgnoresServerContextsInBody(t *testing.T) {
+	body := `{
+		"text": "hello",
+		"session_id": "session_123",
+		"astrology_context": {"sun_sign": "leo", "moon_sign": "leo"},
+		"numerology_context": {"life_path": {"number": 7}},
+		"compatibility_context": {"person_name": "Ploy", "relationship": "partner"}
+	}`
+
+	var request ReplyRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &request))
+
+	assert.Equal(t, "hello", request.Text)
+	assert.Equal(t, "session_123", request.SessionID)
+	assert.Nil(t, request.AstrologyContext)
+	assert.Nil(t, request.NumerologyContext)
+	assert.Nil(t, request.CompatibilityContext)
+}
+
+func TestReplyRequest_ToAPIRequest(t *testing.T) {
+	request := ReplyRequest{
+		Text:                 "hello",
+		UserID:               "user_123",
+		SessionID:            "session_123",
+		Numerology:           &NumerologyInput{Name: "Mali"},
+		AstrologyContext:     &AstrologyContext{SunSign: "leo", MoonSign: "virgo"},
+		CompatibilityContext: &CompatibilityContext{PersonName: "Ploy", Relationship: "partner"},
+	}
+
+	payload, err := json.Marshal(request.ToAPIRequest())
+	require.NoError(t, err)
+
+	var sent map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &sent))
+	assert.Equal(t, "hello", sent["text"])
+	assert.Equal(t, "user_123", sent["user_id"])
+	assert.Equal(t, "session_123", sent["session_id"])
+	assert.Contains(t, sent, "astrology_context")
+	assert.Contains(t, sent, "compatibility_context")
+	assert.NotContains(t, sent, "numerology_context")
+	assert.NotContains(t, sent, "numerology")
+}
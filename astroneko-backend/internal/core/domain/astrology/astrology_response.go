@@ -0,0 +1,38 @@
+package astrology
+
+import "time"
+
+// PlanetPosition represents a body's placement in the natal chart
+type PlanetPosition struct {
+	Body       Body    `json:"body"`
+	Longitude  float64 `json:"longitude"`
+	Sign       Sign    `json:"sign"`
+	Degree     float64 `json:"degree"`
+	Retrograde bool    `json:"retrograde"`
+	House      *int    `json:"house,omitempty"`
+}
+
+// HouseCusp represents the starting point of a house
+type HouseCusp struct {
+	House     int     `json:"house"`
+	Longitude float64 `json:"longitude"`
+	Sign      Sign    `json:"sign"`
+	Degree    float64 `json:"degree"`
+}
+
+// NatalChart represents a computed birth chart.
+// Rising sign, angles and houses are only present when birth time and place are known.
+type NatalChart struct {
+	BirthDateTimeUTC time.Time        `json:"birth_datetime_utc"`
+	Timezone         string           `json:"timezone"`
+	TimeKnown        bool             `json:"time_known"`
+	LocationKnown    bool             `json:"location_known"`
+	SunSign          Sign             `json:"sun_sign"`
+	MoonSign         Sign             `json:"moon_sign"`
+	RisingSign       *Sign            `json:"rising_sign,omitempty"`
+	Ascendant        *float64         `json:"ascendant,omitempty"`
+	Midheaven        *float64         `json:"midheaven,omitempty"`
+	HouseSystem      HouseSystem      `json:"house_system,omitempty"`
+	Planets          []PlanetPosition `json:"planets"`
+	Houses           []HouseCusp      `json:"houses,omitempty"`
+}
@@ -0,0 +1,115 @@
+package astrology
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	// Bundle the timezone database so charts can be computed without system zoneinfo
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is used when a birth profile has no timezone
+const DefaultTimezone = "Asia/Bangkok"
+
+// BirthData is the input for computing a natal chart
+type BirthData struct {
+	// Date holds the local calendar date of birth; its time and location are ignored
+	Date time.Time
+	// Time is the local clock time of birth in "15:04" format, nil when unknown
+	Time      *string
+	Timezone  string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// BuildNatalChart computes a natal chart from birth data.
+// When the birth time is unknown, positions are computed for local noon and no
+// rising sign or houses are returned. Houses also require latitude and longitude.
+func BuildNatalChart(birth BirthData) (*NatalChart, error) {
+	timezone := birth.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+
+	hour, minute := 12, 0
+	timeKnown := birth.Time != nil && *birth.Time != ""
+	if timeKnown {
+		clock, err := time.Parse("15:04", *birth.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid birth time %q: %w", *birth.Time, err)
+		}
+		hour, minute = clock.Hour(), clock.Minute()
+	}
+
+	local := time.Date(birth.Date.Year(), birth.Date.Month(), birth.Date.Day(), hour, minute, 0, 0, location)
+	jd := JulianDay(local)
+
+	chart := &NatalChart{
+		BirthDateTimeUTC: local.UTC(),
+		Timezone:         timezone,
+		TimeKnown:        timeKnown,
+		LocationKnown:    birth.Latitude != nil && birth.Longitude != nil,
+		Planets:          make([]PlanetPosition, 0, len(Bodies)),
+	}
+
+	var cusps [12]float64
+	hasHouses := chart.TimeKnown && chart.LocationKnown
+	if hasHouses {
+		obliquity := MeanObliquity(jd)
+		ramc := LocalSiderealTime(jd, *birth.Longitude)
+
+		var system HouseSystem
+		cusps, system = HouseCusps(ramc, obliquity, *birth.Latitude)
+		asc := roundDegrees(cusps[0])
+		mc := roundDegrees(cusps[9])
+		rising := SignFromLongitude(cusps[0])
+
+		chart.Ascendant = &asc
+		chart.Midheaven = &mc
+		chart.RisingSign = &rising
+		chart.HouseSystem = system
+		chart.Houses = make([]HouseCusp, 0, 12)
+		for index, cusp := range cusps {
+			chart.Houses = append(chart.Houses, HouseCusp{
+				House:     index + 1,
+				Longitude: roundDegrees(cusp),
+				Sign:      SignFromLongitude(cusp),
+				Degree:    roundDegrees(math.Mod(cusp, 30)),
+			})
+		}
+	}
+
+	for _, body := range Bodies {
+		longitude := PlanetLongitude(body, jd)
+		position := PlanetPosition{
+			Body:       body,
+			Longitude:  roundDegrees(longitude),
+			Sign:       SignFromLongitude(longitude),
+			Degree:     roundDegrees(math.Mod(longitude, 30)),
+			Retrograde: IsRetrograde(body, jd),
+		}
+		if hasHouses {
+			house := HouseOf(longitude, cusps)
+			position.House = &house
+		}
+		chart.Planets = append(chart.Planets, position)
+
+		switch body {
+		case BodySun:
+			chart.SunSign = position.Sign
+		case BodyMoon:
+			chart.MoonSign = position.Sign
+		}
+	}
+
+	return chart, nil
+}
+
+func roundDegrees(value float64) float64 {
+	return math.Round(value*100) / 100
+}
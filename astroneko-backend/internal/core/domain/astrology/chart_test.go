@@ -0,0 +1,63 @@
+package astrology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildNatalChart_FullProfile(t *testing.T) {
+	// Arrange
+	birthTime := "08:30"
+	latitude, longitude := 13.7563, 100.5018
+	birth := BirthData{
+		Date:      time.Date(1990, 8, 15, 0, 0, 0, 0, time.UTC),
+		Time:      &birthTime,
+		Timezone:  "Asia/Bangkok",
+		Latitude:  &latitude,
+		Longitude: &longitude,
+	}
+
+	// Act
+	chart, err := BuildNatalChart(birth)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1990, 8, 15, 1, 30, 0, 0, time.UTC), chart.BirthDateTimeUTC)
+	assert.True(t, chart.TimeKnown)
+	assert.True(t, chart.LocationKnown)
+	assert.Equal(t, "leo", chart.SunSign.Key)
+	assert.NotNil(t, chart.RisingSign)
+	assert.Equal(t, HouseSystemPlacidus, chart.HouseSystem)
+	assert.Len(t, chart.Houses, 12)
+	assert.Len(t, chart.Planets, len(Bodies))
+	for _, planet := range chart.Planets {
+		assert.NotNil(t, planet.House, string(planet.Body))
+	}
+}
+
+func TestBuildNatalChart_UnknownTimeAndPlace(t *testing.T) {
+	// Act
+	chart, err := BuildNatalChart(BirthData{Date: time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTimezone, chart.Timezone)
+	assert.False(t, chart.TimeKnown)
+	assert.Equal(t, "capricorn", chart.SunSign.Key)
+	assert.Nil(t, chart.RisingSign)
+	assert.Nil(t, chart.Ascendant)
+	assert.Empty(t, chart.Houses)
+	assert.Nil(t, chart.Planets[0].House)
+}
+
+func TestBuildNatalChart_InvalidInput(t *testing.T) {
+	invalidTime := "25:99"
+
+	_, err := BuildNatalChart(BirthData{Date: time.Now(), Timezone: "Mars/Olympus"})
+	assert.Error(t, err)
+
+	_, err = BuildNatalChart(BirthData{Date: time.Now(), Time: &invalidTime})
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package astrology
+
+import (
+	"math"
+	"time"
+)
+
+// The ephemeris below is a set of offline approximations:
+//   - Sun: Meeus, Astronomical Algorithms ch. 25 (low accuracy, ~0.01°)
+//   - Moon: Meeus ch. 47 with the largest periodic terms (~0.05°)
+//   - Planets: JPL Keplerian elements (Standish) valid 1800-2050 (~0.1-1°)
+//
+// This is more than enough for sign and house placement. Time is treated as UT;
+// the difference to dynamical time (about a minute) is ignored.
+
+const (
+	degToRad = math.Pi / 180
+	radToDeg = 180 / math.Pi
+
+	// j2000 is the Julian day of 2000-01-01 12:00 TT
+	j2000 = 2451545.0
+
+	// precessionPerCentury is the general precession in longitude used to
+	// move J2000 planetary positions to the equinox of date
+	precessionPerCentury = 1.396971
+)
+
+// Body identifies a celestial body in the chart
+type Body string
+
+const (
+	BodySun     Body = "sun"
+	BodyMoon    Body = "moon"
+	BodyMercury Body = "mercury"
+	BodyVenus   Body = "venus"
+	BodyMars    Body = "mars"
+	BodyJupiter Body = "jupiter"
+	BodySaturn  Body = "saturn"
+	BodyUranus  Body = "uranus"
+	BodyNeptune Body = "neptune"
+	BodyPluto   Body = "pluto"
+)
+
+// Bodies lists all supported bodies in traditional chart order
+var Bodies = []Body{
+	BodySun, BodyMoon, BodyMercury, BodyVenus, BodyMars,
+	BodyJupiter, BodySaturn, BodyUranus, BodyNeptune, BodyPluto,
+}
+
+// JulianDay converts a time instant to a Julian day number
+func JulianDay(t time.Time) float64 {
+	return float64(t.UTC().UnixNano())/float64(24*time.Hour) + 2440587.5
+}
+
+// julianCenturies returns Julian centuries since J2000
+func julianCenturies(jd float64) float64 {
+	return (jd - j2000) / 36525
+}
+
+// MeanObliquity returns the mean obliquity of the ecliptic in degrees
+func MeanObliquity(jd float64) float64 {
+	t := julianCenturies(jd)
+	return 23.439291 - 0.0130042*t - 1.64e-7*t*t + 5.04e-7*t*t*t
+}
+
+// GreenwichSiderealTime returns the mean sidereal time at Greenwich in degrees
+func GreenwichSiderealTime(jd float64) float64 {
+	t := julianCenturies(jd)
+	return normalizeDegrees(280.46061837 + 360.98564736629*(jd-j2000) + 0.000387933*t*t - t*t*t/38710000)
+}
+
+// SunLongitude returns the apparent geocentric ecliptic longitude of the Sun in degrees
+func SunLongitude(jd float64) float64 {
+	t := julianCenturies(jd)
+	l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+	m := (357.52911 + 35999.05029*t - 0.0001537*t*t) * degToRad
+	c := (1.914602-0.004817*t-0.000014*t*t)*math.Sin(m) +
+		(0.019993-0.000101*t)*math.Sin(2*m) +
+		0.000289*math.Sin(3*m)
+	omega := (125.04 - 1934.136*t) * degToRad
+	return normalizeDegrees(l0 + c - 0.00569 - 0.00478*math.Sin(omega))
+}
+
+// moonTerm is a periodic term of the lunar longitude (Meeus table 47.A)
+type moonTerm struct {
+	d, m, mp, f float64
+	coefficient float64
+}
+
+var moonLongitudeTerms = []moonTerm{
+	{0, 0, 1, 0, 6288774},
+	{2, 0, -1, 0, 1274027},
+	{2, 0, 0, 0, 658314},
+	{0, 0, 2, 0, 213618},
+	{0, 1, 0, 0, -185116},
+	{0, 0, 0, 2, -114332},
+	{2, 0, -2, 0, 58793},
+	{2, -1, -1, 0, 57066},
+	{2, 0, 1, 0, 53322},
+	{2, -1, 0, 0, 45758},
+	{0, 1, -1, 0, -40923},
+	{1, 0, 0, 0, -34720},
+	{0, 1, 1, 0, -30383},
+	{2, 0, 0, -2, 15327},
+	{0, 0, 1, 2, -12528},
+	{0, 0, 1, -2, 10980},
+	{4, 0, -1, 0, 10675},
+	{0, 0, 3, 0, 10034},
+	{4, 0, -2, 0, 8548},
+	{2, 1, -1, 0, -7888},
+	{2, 1, 0, 0, -6766},
+	{1, 0, -1, 0, -5163},
+	{1, 1, 0, 0, 4987},
+	{2, -1, 1, 0, 4036},
+	{2, 0, 2, 0, 3994},
+	{4, 0, 0, 0, 3861},
+	{2, 0, -3, 0, 3665},
+	{0, 1, -2, 0, -2689},
+	{2, 0, -1, 2, -2602},
+	{2, -1, -2, 0, 2390},
+	{1, 0, 1, 0, -2348},
+	{2, -2, 0, 0, 2236},
+	{0, 1, 2, 0, -2120},
+	{0, 2, 0, 0, -2069},
+	{2, -2, -1, 0, 2048},
+	{2, 0, 1, -2, -1773},
+	{2, 0, 0, 2, -1595},
+	{4, -1, -1, 0, 1215},
+	{0, 0, 2, 2, -1110},
+}
+
+// MoonLongitude returns the geocentric ecliptic longitude of the Moon in degrees
+func MoonLongitude(jd float64) float64 {
+	t := julianCenturies(jd)
+	lp := 218.3164477 + 481267.88123421*t - 0.0015786*t*t
+	d := (297.8501921 + 445267.1114034*t - 0.0018819*t*t) * degToRad
+	m := (357.5291092 + 35999.0502909*t - 0.0001536*t*t) * degToRad
+	mp := (134.9633964 + 477198.8675055*t + 0.0087414*t*t) * degToRad
+	f := (93.2720950 + 483202.0175233*t - 0.0036539*t*t) * degToRad
+	e := 1 - 0.002516*t - 0.0000074*t*t
+
+	var sum float64
+	for _, term := range moonLongitudeTerms {
+		value := term.coefficient * math.Sin(term.d*d+term.m*m+term.mp*mp+term.f*f)
+		switch math.Abs(term.m) {
+		case 1:
+			value *= e
+		case 2:
+			value *= e * e
+		}
+		sum += value
+	}
+
+	a1 := (119.75 + 131.849*t) * degToRad
+	a2 := (53.09 + 479264.290*t) * degToRad
+	sum += 3958*math.Sin(a1) + 1962*math.Sin(lp*degToRad-f) + 318*math.Sin(a2)
+
+	return normalizeDegrees(lp + sum/1e6)
+}
+
+// MeanLunarNode returns the longitude of the Moon's mean ascending node in degrees
+func MeanLunarNode(jd float64) float64 {
+	t := julianCenturies(jd)
+	return normalizeDegrees(125.0445479 - 1934.1362891*t + 0.0020754*t*t)
+}
+
+// orbitalElements are Keplerian elements at J2000 with rates per Julian century
+type orbitalElements struct {
+	a, e, i, l, perihelion, node             float64
+	aRate, eRate, iRate, lRate, pRate, nRate float64
+}
+
+// keplerianElements holds JPL approximate elements (Standish, table 1)
+var keplerianElements = map[Body]orbitalElements{
+	BodyMercury: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+	BodyVenus: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+	"earth": {1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.0,
+		0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.0},
+	BodyMars: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+	BodyJupiter: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+	BodySaturn: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+	BodyUranus: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+	BodyNeptune: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+	BodyPluto: {39.48211675, 0.24882730, 17.14001206, 238.92903833, 224.06891629, 110.30393684,
+		-0.00031596, 0.00005170, 0.00004818, 145.20780515, -0.04062942, -0.01183482},
+}
+
+// heliocentricPosition returns J2000 ecliptic rectangular coordinates in AU
+func heliocentricPosition(elements orbitalElements, t float64) (x, y, z float64) {
+	a := elements.a + elements.aRate*t
+	e := elements.e + elements.eRate*t
+	i := (elements.i + elements.iRate*t) * degToRad
+	l := elements.l + elements.lRate*t
+	perihelion := elements.perihelion + elements.pRate*t
+	node := elements.node + elements.nRate*t
+
+	omega := (perihelion - node) * degToRad
+	meanAnomaly := normalizeDegrees(l-perihelion) * degToRad
+	node *= degToRad
+
+	// Solve Kepler's equation with Newton iterations
+	eccentricAnomaly := meanAnomaly + e*math.Sin(meanAnomaly)
+	for iteration := 0; iteration < 20; iteration++ {
+		delta := (eccentricAnomaly - e*math.Sin(eccentricAnomaly) - meanAnomaly) / (1 - e*math.Cos(eccentricAnomaly))
+		eccentricAnomaly -= delta
+		if math.Abs(delta) < 1e-10 {
+			break
+		}
+	}
+
+	xp := a * (math.Cos(eccentricAnomaly) - e)
+	yp := a * math.Sqrt(1-e*e) * math.Sin(eccentricAnomaly)
+
+	cosW, sinW := math.Cos(omega), math.Sin(omega)
+	cosN, sinN := math.Cos(node), math.Sin(node)
+	cosI, sinI := math.Cos(i), math.Sin(i)
+
+	x = (cosW*cosN-sinW*sinN*cosI)*xp + (-sinW*cosN-cosW*sinN*cosI)*yp
+	y = (cosW*sinN+sinW*cosN*cosI)*xp + (-sinW*sinN+cosW*cosN*cosI)*yp
+	z = (sinW*sinI)*xp + (cosW*sinI)*yp
+	return x, y, z
+}
+
+// PlanetLongitude returns the geocentric ecliptic longitude of a planet in degrees
+func PlanetLongitude(body Body, jd float64) float64 {
+	switch body {
+	case BodySun:
+		return SunLongitude(jd)
+	case BodyMoon:
+		return MoonLongitude(jd)
+	}
+
+	elements, ok := keplerianElements[body]
+	if !ok {
+		return 0
+	}
+
+	t := julianCenturies(jd)
+	px, py, _ := heliocentricPosition(elements, t)
+	ex, ey, _ := heliocentricPosition(keplerianElements["earth"], t)
+
+	longitude := math.Atan2(py-ey, px-ex) * radToDeg
+	return normalizeDegrees(longitude + precessionPerCentury*t)
+}
+
+// IsRetrograde reports whether a body appears to move backwards along the ecliptic
+func IsRetrograde(body Body, jd float64) bool {
+	if body == BodySun || body == BodyMoon {
+		return false
+	}
+	motion := PlanetLongitude(body, jd+0.5) - PlanetLongitude(body, jd-0.5)
+	if motion > 180 {
+		motion -= 360
+	} else if motion < -180 {
+		motion += 360
+	}
+	return motion < 0
+}
@@ -0,0 +1,73 @@
+package astrology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// angularDistance returns the smallest difference between two angles in degrees
+func angularDistance(a, b float64) float64 {
+	d := normalizeDegrees(a - b)
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
+
+func TestJulianDay(t *testing.T) {
+	assert.InDelta(t, 2451545.0, JulianDay(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)), 1e-9)
+	assert.InDelta(t, 2446895.5, JulianDay(time.Date(1987, 4, 10, 0, 0, 0, 0, time.UTC)), 1e-9)
+}
+
+func TestGreenwichSiderealTime(t *testing.T) {
+	// Meeus example 12.a: 1987-04-10 0h UT = 13h10m46.3668s
+	assert.InDelta(t, 197.693195, GreenwichSiderealTime(2446895.5), 1e-4)
+}
+
+func TestSunLongitude(t *testing.T) {
+	// Meeus example 25.a: 1992-10-13 0h TD
+	assert.InDelta(t, 199.90895, SunLongitude(2448908.5), 0.01)
+}
+
+func TestMoonLongitude(t *testing.T) {
+	// Meeus example 47.a: 1992-04-12 0h TD
+	assert.InDelta(t, 133.162655, MoonLongitude(2448724.5), 0.05)
+}
+
+func TestPlanetLongitude_J2000(t *testing.T) {
+	// Geocentric ecliptic longitudes at 2000-01-01 12:00
+	expected := map[Body]float64{
+		BodyMercury: 271.89,
+		BodyVenus:   241.57,
+		BodyMars:    327.96,
+		BodyJupiter: 25.25,
+		BodySaturn:  40.40,
+		BodyUranus:  314.81,
+		BodyNeptune: 303.19,
+		BodyPluto:   251.45,
+	}
+
+	for body, longitude := range expected {
+		t.Run(string(body), func(t *testing.T) {
+			actual := PlanetLongitude(body, j2000)
+			assert.LessOrEqual(t, angularDistance(actual, longitude), 1.0, "got %.2f", actual)
+		})
+	}
+}
+
+func TestIsRetrograde(t *testing.T) {
+	// Mercury was retrograde from 2023-12-13 to 2024-01-01
+	assert.True(t, IsRetrograde(BodyMercury, JulianDay(time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC))))
+	assert.False(t, IsRetrograde(BodyMercury, JulianDay(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC))))
+	assert.False(t, IsRetrograde(BodySun, j2000))
+}
+
+func TestSignFromLongitude(t *testing.T) {
+	assert.Equal(t, "aries", SignFromLongitude(0).Key)
+	assert.Equal(t, "taurus", SignFromLongitude(30).Key)
+	assert.Equal(t, "pisces", SignFromLongitude(359.99).Key)
+	assert.Equal(t, "pisces", SignFromLongitude(-0.5).Key)
+	assert.Equal(t, "aries", SignFromLongitude(360).Key)
+}
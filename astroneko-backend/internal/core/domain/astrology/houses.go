@@ -0,0 +1,143 @@
+package astrology
+
+import "math"
+
+// HouseSystem identifies how house cusps are divided
+type HouseSystem string
+
+const (
+	HouseSystemPlacidus HouseSystem = "placidus"
+	// HouseSystemPorphyry is used as a fallback near the poles where Placidus is undefined
+	HouseSystemPorphyry HouseSystem = "porphyry"
+)
+
+// LocalSiderealTime returns the local sidereal time in degrees (RAMC).
+// Longitude is in degrees, east positive.
+func LocalSiderealTime(jd float64, longitude float64) float64 {
+	return normalizeDegrees(GreenwichSiderealTime(jd) + longitude)
+}
+
+// Ascendant returns the ecliptic longitude rising on the eastern horizon
+func Ascendant(ramc, obliquity, latitude float64) float64 {
+	r := ramc * degToRad
+	e := obliquity * degToRad
+	phi := latitude * degToRad
+	return normalizeDegrees(math.Atan2(math.Cos(r), -(math.Sin(r)*math.Cos(e)+math.Tan(phi)*math.Sin(e))) * radToDeg)
+}
+
+// Midheaven returns the ecliptic longitude culminating on the meridian
+func Midheaven(ramc, obliquity float64) float64 {
+	r := ramc * degToRad
+	e := obliquity * degToRad
+	return normalizeDegrees(math.Atan2(math.Sin(r), math.Cos(r)*math.Cos(e)) * radToDeg)
+}
+
+// HouseCusps returns the twelve house cusp longitudes (index 0 is the first house).
+// Placidus is used where defined; otherwise the cusps fall back to Porphyry.
+func HouseCusps(ramc, obliquity, latitude float64) ([12]float64, HouseSystem) {
+	asc := Ascendant(ramc, obliquity, latitude)
+	mc := Midheaven(ramc, obliquity)
+
+	if cusps, ok := placidusCusps(ramc, obliquity, latitude, asc, mc); ok {
+		return cusps, HouseSystemPlacidus
+	}
+	return porphyryCusps(asc, mc), HouseSystemPorphyry
+}
+
+// placidusCusps trisects the semi-arcs of each cusp's own declination iteratively
+func placidusCusps(ramc, obliquity, latitude, asc, mc float64) ([12]float64, bool) {
+	var cusps [12]float64
+	if math.Abs(latitude) >= 90-obliquity {
+		return cusps, false
+	}
+
+	e := obliquity * degToRad
+	tanPhi := math.Tan(latitude * degToRad)
+
+	// cusp computes a cusp whose right ascension is offset(sda) from the RAMC
+	cusp := func(offset func(sda float64) float64) (float64, bool) {
+		ra := ramc + offset(90)
+		var longitude float64
+		for iteration := 0; iteration < 50; iteration++ {
+			longitude = normalizeDegrees(math.Atan2(math.Sin(ra*degToRad), math.Cos(ra*degToRad)*math.Cos(e)) * radToDeg)
+			declination := math.Asin(math.Sin(e) * math.Sin(longitude*degToRad))
+			x := -tanPhi * math.Tan(declination)
+			if x < -1 || x > 1 {
+				return 0, false
+			}
+			next := ramc + offset(math.Acos(x)*radToDeg)
+			if math.Abs(next-ra) < 1e-7 {
+				ra = next
+				break
+			}
+			ra = next
+		}
+		return normalizeDegrees(math.Atan2(math.Sin(ra*degToRad), math.Cos(ra*degToRad)*math.Cos(e)) * radToDeg), true
+	}
+
+	offsets := map[int]func(sda float64) float64{
+		10: func(sda float64) float64 { return sda / 3 },
+		11: func(sda float64) float64 { return 2 * sda / 3 },
+		1:  func(sda float64) float64 { return 180 - 2*(180-sda)/3 },
+		2:  func(sda float64) float64 { return 180 - (180-sda)/3 },
+	}
+	for index, offset := range offsets {
+		value, ok := cusp(offset)
+		if !ok {
+			return cusps, false
+		}
+		cusps[index] = value
+	}
+
+	cusps[0] = asc
+	cusps[9] = mc
+
+	// Opposite houses share the same axis
+	cusps[3] = normalizeDegrees(mc + 180)
+	cusps[6] = normalizeDegrees(asc + 180)
+	cusps[7] = normalizeDegrees(cusps[1] + 180)
+	cusps[8] = normalizeDegrees(cusps[2] + 180)
+	cusps[4] = normalizeDegrees(cusps[10] + 180)
+	cusps[5] = normalizeDegrees(cusps[11] + 180)
+
+	return cusps, true
+}
+
+// porphyryCusps trisects the ecliptic quadrants between the angles
+func porphyryCusps(asc, mc float64) [12]float64 {
+	var cusps [12]float64
+	ic := normalizeDegrees(mc + 180)
+	dsc := normalizeDegrees(asc + 180)
+
+	cusps[0] = asc
+	cusps[3] = ic
+	cusps[6] = dsc
+	cusps[9] = mc
+
+	quadrants := [][3]float64{
+		{asc, ic, 0},
+		{ic, dsc, 3},
+		{dsc, mc, 6},
+		{mc, asc, 9},
+	}
+	for _, q := range quadrants {
+		arc := normalizeDegrees(q[1] - q[0])
+		start := int(q[2])
+		cusps[start+1] = normalizeDegrees(q[0] + arc/3)
+		cusps[start+2] = normalizeDegrees(q[0] + 2*arc/3)
+	}
+	return cusps
+}
+
+// HouseOf returns the 1-based house number containing a longitude
+func HouseOf(longitude float64, cusps [12]float64) int {
+	longitude = normalizeDegrees(longitude)
+	for index := 0; index < 12; index++ {
+		start := cusps[index]
+		end := cusps[(index+1)%12]
+		if normalizeDegrees(longitude-start) < normalizeDegrees(end-start) {
+			return index + 1
+		}
+	}
+	return 1
+}
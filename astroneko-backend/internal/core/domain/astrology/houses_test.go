@@ -0,0 +1,62 @@
+package astrology
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAscendantAndMidheaven(t *testing.T) {
+	// With the vernal point on the meridian at the equator, 0° Cancer rises
+	assert.InDelta(t, 90.0, Ascendant(0, 23.44, 0), 1e-9)
+	assert.InDelta(t, 0.0, Midheaven(0, 23.44), 1e-9)
+
+	// Six sidereal hours later, 0° Cancer culminates and 0° Libra rises
+	assert.InDelta(t, 180.0, Ascendant(90, 23.44, 0), 1e-9)
+	assert.InDelta(t, 90.0, Midheaven(90, 23.44), 1e-9)
+}
+
+func TestHouseCusps_Placidus(t *testing.T) {
+	// Arrange: Bangkok
+	ramc, obliquity, latitude := 123.4, 23.44, 13.75
+
+	// Act
+	cusps, system := HouseCusps(ramc, obliquity, latitude)
+
+	// Assert
+	assert.Equal(t, HouseSystemPlacidus, system)
+	assert.InDelta(t, Ascendant(ramc, obliquity, latitude), cusps[0], 1e-9)
+	assert.InDelta(t, Midheaven(ramc, obliquity), cusps[9], 1e-9)
+
+	// Cusps must advance counter-clockwise around the zodiac
+	var total float64
+	for index := 0; index < 12; index++ {
+		arc := normalizeDegrees(cusps[(index+1)%12] - cusps[index])
+		assert.Greater(t, arc, 0.0)
+		assert.Less(t, arc, 90.0)
+		total += arc
+	}
+	assert.InDelta(t, 360.0, total, 1e-6)
+
+	// Opposite cusps share the same axis
+	for index := 0; index < 6; index++ {
+		assert.InDelta(t, 180.0, normalizeDegrees(cusps[index+6]-cusps[index]), 1e-6)
+	}
+}
+
+func TestHouseCusps_PorphyryFallbackNearPole(t *testing.T) {
+	cusps, system := HouseCusps(200, 23.44, 70)
+
+	assert.Equal(t, HouseSystemPorphyry, system)
+	assert.InDelta(t, Ascendant(200, 23.44, 70), cusps[0], 1e-9)
+	assert.InDelta(t, Midheaven(200, 23.44), cusps[9], 1e-9)
+}
+
+func TestHouseOf(t *testing.T) {
+	cusps := [12]float64{350, 20, 50, 80, 110, 140, 170, 200, 230, 260, 290, 320}
+
+	assert.Equal(t, 1, HouseOf(355, cusps))
+	assert.Equal(t, 1, HouseOf(5, cusps))
+	assert.Equal(t, 2, HouseOf(20, cusps))
+	assert.Equal(t, 12, HouseOf(349.9, cusps))
+}
@@ -0,0 +1,75 @@
+package astrology
+
+import (
+	"math"
+	"strings"
+)
+
+// Element represents the classical element of a zodiac sign
+type Element string
+
+const (
+	ElementFire  Element = "fire"
+	ElementEarth Element = "earth"
+	ElementAir   Element = "air"
+	ElementWater Element = "water"
+)
+
+// Modality represents the quality of a zodiac sign
+type Modality string
+
+const (
+	ModalityCardinal Modality = "cardinal"
+	ModalityFixed    Modality = "fixed"
+	ModalityMutable  Modality = "mutable"
+)
+
+// Sign represents a tropical zodiac sign
+type Sign struct {
+	Key      string   `json:"key"`
+	NameEN   string   `json:"name_en"`
+	NameTH   string   `json:"name_th"`
+	Element  Element  `json:"element"`
+	Modality Modality `json:"modality"`
+}
+
+// Signs lists the zodiac signs in ecliptic order starting at 0° Aries
+var Signs = [12]Sign{
+	{Key: "aries", NameEN: "Aries", NameTH: "เมษ", Element: ElementFire, Modality: ModalityCardinal},
+	{Key: "taurus", NameEN: "Taurus", NameTH: "พฤษภ", Element: ElementEarth, Modality: ModalityFixed},
+	{Key: "gemini", NameEN: "Gemini", NameTH: "เมถุน", Element: ElementAir, Modality: ModalityMutable},
+	{Key: "cancer", NameEN: "Cancer", NameTH: "กรกฎ", Element: ElementWater, Modality: ModalityCardinal},
+	{Key: "leo", NameEN: "Leo", NameTH: "สิงห์", Element: ElementFire, Modality: ModalityFixed},
+	{Key: "virgo", NameEN: "Virgo", NameTH: "กันย์", Element: ElementEarth, Modality: ModalityMutable},
+	{Key: "libra", NameEN: "Libra", NameTH: "ตุลย์", Element: ElementAir, Modality: ModalityCardinal},
+	{Key: "scorpio", NameEN: "Scorpio", NameTH: "พิจิก", Element: ElementWater, Modality: ModalityFixed},
+	{Key: "sagittarius", NameEN: "Sagittarius", NameTH: "ธนู", Element: ElementFire, Modality: ModalityMutable},
+	{Key: "capricorn", NameEN: "Capricorn", NameTH: "มังกร", Element: ElementEarth, Modality: ModalityCardinal},
+	{Key: "aquarius", NameEN: "Aquarius", NameTH: "กุมภ์", Element: ElementAir, Modality: ModalityFixed},
+	{Key: "pisces", NameEN: "Pisces", NameTH: "มีน", Element: ElementWater, Modality: ModalityMutable},
+}
+
+// SignFromLongitude returns the sign containing an ecliptic longitude in degrees
+func SignFromLongitude(longitude float64) Sign {
+	return Signs[int(normalizeDegrees(longitude)/30)%12]
+}
+
+// SignByKey looks up a sign by its key (e.g. "leo"), case-insensitively
+func SignByKey(key string) (Sign, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, sign := range Signs {
+		if sign.Key == key {
+			return sign, true
+		}
+	}
+	return Sign{}, false
+}
+
+// normalizeDegrees maps an angle into [0, 360)
+func normalizeDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
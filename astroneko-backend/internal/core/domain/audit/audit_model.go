@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor types
+const (
+	ActorCRMUser = "crm_user"
+	ActorSystem  = "system"
+)
+
+// Target types
+const (
+	TargetUser         = "user"
+	TargetUserLimit    = "user_limit"
+	TargetReferralCode = "referral_code"
+	TargetCRMUser      = "crm_user"
+	TargetWaitingList  = "waiting_list"
+	TargetWaitingEntry = "waiting_list_entry"
+)
+
+// Actions on app users performed from the CRM
+const (
+	ActionUserCreated          = "user.created"
+	ActionUserUpdated          = "user.updated"
+	ActionUserDeletionSchedule = "user.deletion_scheduled"
+	ActionUserLoginUnlocked    = "user.login_unlocked"
+)
+
+// Actions on settings, referral codes and CRM accounts
+const (
+	ActionUserLimitUpdated    = "user_limit.updated"
+	ActionReferralCodeCreated = "referral_code.created"
+	ActionReferralCodeUpdated = "referral_code.updated"
+	ActionReferralCodeDeleted = "referral_code.deleted"
+	// Bulk actions record one entry listing every code created
+	ActionReferralCodesGenerated = "referral_code.bulk_generated"
+	ActionReferralCodesImported  = "referral_code.imported"
+	ActionCRMUserCreated         = "crm_user.created"
+	ActionCRMUserRolesAssigned   = "crm_user.roles_assigned"
+	ActionCRMUserDisabled        = "crm_user.disabled"
+	ActionCRMUserEnabled         = "crm_user.enabled"
+	ActionCRMUserPasswordReset   = "crm_user.password_reset_issued"
+	ActionCRMUserTwoFactorReset  = "crm_user.two_factor_reset"
+	ActionCRMUserUnlocked        = "crm_user.login_unlocked"
+	// The bootstrapped admin is recorded as its own actor, as no one is signed in yet
+	ActionCRMAdminBootstrapped = "crm_user.admin_bootstrapped"
+)
+
+// Actions on waiting lists performed from the CRM
+const (
+	ActionWaitingListCreated      = "waiting_list.created"
+	ActionWaitingListUpdated      = "waiting_list.updated"
+	ActionWaitingListEntryDeleted = "waiting_list_entry.deleted"
+)
+
+// Actor identifies who made a change and where the request came from
+type Actor struct {
+	Type      string
+	ID        string
+	Name      string
+	IPAddress string
+	RequestID string
+}
+
+// Entry records one administrative change. Entries are only ever inserted, and each
+// one carries the hash of the entry before it, so editing or removing an entry breaks
+// the chain from that point on.
+type Entry struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	// Sequence orders the hash chain; the repository assigns it when appending
+	Sequence   int64  `json:"sequence" gorm:"not null;uniqueIndex:idx_audit_logs_sequence"`
+	ActorType  string `json:"actor_type" gorm:"type:varchar(32);not null"`
+	ActorID    string `json:"actor_id" gorm:"type:varchar(64)"`
+	ActorName  string `json:"actor_name" gorm:"type:varchar(100)"`
+	Action     string `json:"action" gorm:"type:varchar(64);not null"`
+	TargetType string `json:"target_type" gorm:"type:varchar(32);not null"`
+	TargetID   string `json:"target_id" gorm:"type:varchar(64);index:idx_audit_logs_target;not null"`
+	// Changes is a JSON object of the fields that changed, see Diff
+	Changes   string    `json:"changes" gorm:"type:text"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(64)"`
+	RequestID string    `json:"request_id" gorm:"type:varchar(64)"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:now()"`
+	// PrevHash is the Hash of the previous entry, empty for the first one
+	PrevHash string `json:"prev_hash" gorm:"type:varchar(64)"`
+	Hash     string `json:"hash" gorm:"type:varchar(64)"`
+}
+
+func (Entry) TableName() string {
+	return "astroneko_audit_logs"
+}
+
+// NewEntry builds an entry for a change of the target from before to after.
+// Pass nil as before for a creation and nil as after for a deletion.
+func NewEntry(actor Actor, action string, targetType string, targetID string, before any, after any) (*Entry, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	return &Entry{
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		ActorName:  actor.Name,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    string(encoded),
+		IPAddress:  actor.IPAddress,
+		RequestID:  actor.RequestID,
+	}, nil
+}
+
+// hashedFields is the canonical form of an entry that its hash covers. Struct field
+// order fixes the JSON encoding, so do not reorder or rename these.
+type hashedFields struct {
+	Sequence   int64  `json:"sequence"`
+	PrevHash   string `json:"prev_hash"`
+	ActorType  string `json:"actor_type"`
+	ActorID    string `json:"actor_id"`
+	ActorName  string `json:"actor_name"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Changes    string `json:"changes"`
+	IPAddress  string `json:"ip_address"`
+	RequestID  string `json:"request_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ComputeHash returns the SHA-256 of the entry's content and PrevHash. CreatedAt
+// is hashed at the microsecond precision the database keeps.
+func (e *Entry) ComputeHash() string {
+	encoded, _ := json.Marshal(hashedFields{
+		Sequence:   e.Sequence,
+		PrevHash:   e.PrevHash,
+		ActorType:  e.ActorType,
+		ActorID:    e.ActorID,
+		ActorName:  e.ActorName,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		Changes:    e.Changes,
+		IPAddress:  e.IPAddress,
+		RequestID:  e.RequestID,
+		CreatedAt:  e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain links the entry after prev, which is nil when the log is empty, and seals it
+func (e *Entry) Chain(prev *Entry, now time.Time) {
+	e.Sequence = 1
+	e.PrevHash = ""
+	if prev != nil {
+		e.Sequence = prev.Sequence + 1
+		e.PrevHash = prev.Hash
+	}
+	e.CreatedAt = now.UTC().Truncate(time.Microsecond)
+	e.Hash = e.ComputeHash()
+}
+
+// CheckLink reports why the entry does not follow prev in the chain, or an empty
+// string when it does. prev is nil for the first hashed entry.
+func (e *Entry) CheckLink(prev *Entry) string {
+	if e.Hash != e.ComputeHash() {
+		return "entry content does not match its hash"
+	}
+	if prev == nil {
+		if e.PrevHash != "" {
+			return "first entry points at a previous hash"
+		}
+		return ""
+	}
+	if e.Sequence != prev.Sequence+1 {
+		return fmt.Sprintf("sequence jumps from %d to %d", prev.Sequence, e.Sequence)
+	}
+	if e.PrevHash != prev.Hash {
+		return "previous hash does not match the entry before"
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildChain(t *testing.T, n int) []*Entry {
+	t.Helper()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 123456789, time.UTC)
+	var chain []*Entry
+	var prev *Entry
+	for i := 0; i < n; i++ {
+		entry, err := NewEntry(Actor{Type: ActorCRMUser, ID: "crm-1", Name: "ploy", IPAddress: "10.0.0.1", RequestID: "req"},
+			ActionReferralCodeUpdated, TargetReferralCode, "code-1",
+			map[string]any{"referral_code": "OLD"}, map[string]any{"referral_code": "NEW"})
+		assert.NoError(t, err)
+		entry.Chain(prev, start.Add(time.Duration(i)*time.Minute))
+		chain = append(chain, entry)
+		prev = entry
+	}
+	return chain
+}
+
+func TestEntry_Chain(t *testing.T) {
+	chain := buildChain(t, 3)
+
+	assert.Equal(t, int64(1), chain[0].Sequence)
+	assert.Empty(t, chain[0].PrevHash)
+	assert.Equal(t, int64(3), chain[2].Sequence)
+	assert.Equal(t, chain[1].Hash, chain[2].PrevHash)
+	assert.Len(t, chain[0].Hash, 64)
+	assert.NotEqual(t, chain[0].Hash, chain[1].Hash)
+	assert.Equal(t, 0, chain[0].CreatedAt.Nanosecond()%1000, "created_at is kept at microsecond precision")
+}
+
+func TestEntry_CheckLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(chain []*Entry)
+		reason string
+	}{
+		{
+			name:   "intact chain",
+			tamper: func(chain []*Entry) {},
+		},
+		{
+			name:   "edited changes",
+			tamper: func(chain []*Entry) { chain[1].Changes = `{"referral_code":{"before":"OLD","after":"FREE"}}` },
+			reason: "entry content does not match its hash",
+		},
+		{
+			name: "rehashed entry no longer matches the next link",
+			tamper: func(chain []*Entry) {
+				chain[1].ActorName = "someone else"
+				chain[1].Hash = chain[1].ComputeHash()
+			},
+			reason: "previous hash does not match the entry before",
+		},
+		{
+			name: "removed entry",
+			tamper: func(chain []*Entry) {
+				chain[1] = chain[0]
+			},
+			reason: "sequence jumps from 1 to 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			chain := buildChain(t, 3)
+			tt.tamper(chain)
+
+			// Act
+			var reason string
+			var prev *Entry
+			for _, entry := range chain {
+				if entry == prev {
+					continue
+				}
+				if reason = entry.CheckLink(prev); reason != "" {
+					break
+				}
+				prev = entry
+			}
+
+			// Assert
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestEntry_CheckLink_FirstEntryWithPrevHash(t *testing.T) {
+	chain := buildChain(t, 2)
+
+	assert.Equal(t, "first entry points at a previous hash", chain[1].CheckLink(nil))
+}
@@ -0,0 +1,15 @@
+package audit
+
+import "time"
+
+// ListFilter narrows the audit log listing. Zero values match everything.
+type ListFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
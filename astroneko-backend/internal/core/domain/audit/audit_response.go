@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EntryResponse struct {
+	ID         string          `json:"id"`
+	Sequence   int64           `json:"sequence"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	IPAddress  string          `json:"ip_address"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Hash       string          `json:"hash"`
+}
+
+// ToResponse converts an entry to its API representation, embedding the stored
+// changes as JSON rather than as an escaped string
+func (e *Entry) ToResponse() *EntryResponse {
+	changes := json.RawMessage(e.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+	return &EntryResponse{
+		ID:         e.ID.String(),
+		Sequence:   e.Sequence,
+		ActorType:  e.ActorType,
+		ActorID:    e.ActorID,
+		ActorName:  e.ActorName,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		Changes:    changes,
+		IPAddress:  e.IPAddress,
+		RequestID:  e.RequestID,
+		CreatedAt:  e.CreatedAt,
+		Hash:       e.Hash,
+	}
+}
+
+type ListEntriesResponse struct {
+	Entries []EntryResponse `json:"entries"`
+	Total   int64           `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+// ChainVerification is the result of walking the hash chain from the first entry
+type ChainVerification struct {
+	Verified     bool   `json:"verified"`
+	Checked      int64  `json:"checked"`
+	HeadSequence int64  `json:"head_sequence"`
+	HeadHash     string `json:"head_hash"`
+	// BrokenAtSequence and Reason point at the first entry that does not match
+	BrokenAtSequence *int64 `json:"broken_at_sequence,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Change is the value of one field before and after a change
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// ignoredFields change on every write and say nothing about what the actor did
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+	"UpdatedAt":  true,
+}
+
+// RedactedValue stands in for the value of a personal data field
+const RedactedValue = "[redacted]"
+
+// redactedFields hold personal data. Entries can never be edited or removed, so only
+// the fact that such a field changed is recorded, never its value.
+var redactedFields = map[string]bool{
+	"email":             true,
+	"firebase_uid":      true,
+	"display_name":      true,
+	"profile_image_url": true,
+	"birth_date":        true,
+	"birth_time":        true,
+	"birth_place":       true,
+	"birth_latitude":    true,
+	"birth_longitude":   true,
+	"birth_timezone":    true,
+	"phone":             true,
+	"fingerprint":       true,
+	"fields":            true,
+}
+
+// Diff compares the JSON form of two values and returns the fields that differ.
+// Either side may be nil, in which case every field of the other side is reported.
+// Values of personal data fields are replaced by RedactedValue.
+func Diff(before any, after any) (map[string]Change, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for key, beforeValue := range beforeFields {
+		afterValue, ok := afterFields[key]
+		if ignoredFields[key] || (ok && reflect.DeepEqual(beforeValue, afterValue)) {
+			continue
+		}
+		changes[key] = Change{Before: beforeValue, After: afterValue}
+	}
+	for key, afterValue := range afterFields {
+		if _, ok := beforeFields[key]; ok || ignoredFields[key] {
+			continue
+		}
+		changes[key] = Change{Before: nil, After: afterValue}
+	}
+
+	for key, change := range changes {
+		if redactedFields[key] {
+			changes[key] = Change{Before: redact(change.Before), After: redact(change.After)}
+		}
+	}
+
+	return changes, nil
+}
+
+// redact hides a value but keeps nil, so a field being set or cleared still shows
+func redact(value any) any {
+	if value == nil {
+		return nil
+	}
+	return RedactedValue
+}
+
+func toFields(value any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return fields, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit value: %w", err)
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("audit value must encode as a JSON object: %w", err)
+	}
+
+	return fields, nil
+}
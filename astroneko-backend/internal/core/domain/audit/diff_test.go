@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffTestRecord struct {
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	Note      *string   `json:"note"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func TestDiff(t *testing.T) {
+	note := "vip"
+	before := &diffTestRecord{Name: "Mali", Active: false, UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	after := &diffTestRecord{Name: "Mali", Active: true, Note: &note, UpdatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("reports changed fields only", func(t *testing.T) {
+		changes, err := Diff(before, after)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Change{
+			"active": {Before: false, After: true},
+			"note":   {Before: nil, After: "vip"},
+		}, changes)
+	})
+
+	t.Run("creation reports every field", func(t *testing.T) {
+		changes, err := Diff(nil, after)
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 3)
+		assert.Equal(t, Change{Before: nil, After: "Mali"}, changes["name"])
+	})
+
+	t.Run("nil pointer counts as nil", func(t *testing.T) {
+		var missing *diffTestRecord
+		changes, err := Diff(before, missing)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Change{Before: "Mali", After: nil}, changes["name"])
+		assert.NotContains(t, changes, "updated_at")
+	})
+
+	t.Run("redacts personal data", func(t *testing.T) {
+		type profile struct {
+			Email       string  `json:"email"`
+			DisplayName *string `json:"display_name"`
+			BonusQuota  int     `json:"bonus_quota"`
+		}
+		name := "Mali"
+		changes, err := Diff(&profile{Email: "old@example.com", BonusQuota: 1}, &profile{Email: "new@example.com", DisplayName: &name, BonusQuota: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Change{
+			"email":        {Before: RedactedValue, After: RedactedValue},
+			"display_name": {Before: nil, After: RedactedValue},
+			"bonus_quota":  {Before: float64(1), After: float64(2)},
+		}, changes)
+	})
+
+	t.Run("rejects non-object values", func(t *testing.T) {
+		_, err := Diff("text", after)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	actor := Actor{Type: ActorCRMUser, ID: "crm-1", Name: "admin", IPAddress: "10.0.0.1", RequestID: "req-1"}
+	before := &diffTestRecord{Name: "Mali"}
+	after := &diffTestRecord{Name: "Malee"}
+
+	entry, err := NewEntry(actor, ActionUserUpdated, TargetUser, "user-1", before, after)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "crm-1", entry.ActorID)
+	assert.Equal(t, "admin", entry.ActorName)
+	assert.Equal(t, "10.0.0.1", entry.IPAddress)
+	assert.Equal(t, "req-1", entry.RequestID)
+
+	var changes map[string]Change
+	assert.NoError(t, json.Unmarshal([]byte(entry.Changes), &changes))
+	assert.Equal(t, Change{Before: "Mali", After: "Malee"}, changes["name"])
+}
@@ -0,0 +1,258 @@
+package compatibility
+
+import (
+	"math"
+
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/numerology"
+)
+
+// Aspect is the angular relationship between two signs
+type Aspect string
+
+const (
+	AspectConjunction Aspect = "conjunction"
+	AspectSemiSextile Aspect = "semi_sextile"
+	AspectSextile     Aspect = "sextile"
+	AspectSquare      Aspect = "square"
+	AspectTrine       Aspect = "trine"
+	AspectQuincunx    Aspect = "quincunx"
+	AspectOpposition  Aspect = "opposition"
+)
+
+// ElementRelation describes how two elements combine
+type ElementRelation string
+
+const (
+	ElementSame          ElementRelation = "same"
+	ElementComplementary ElementRelation = "complementary"
+	ElementNeutral       ElementRelation = "neutral"
+	ElementChallenging   ElementRelation = "challenging"
+)
+
+// NumberRelation describes how two numerology numbers combine
+type NumberRelation string
+
+const (
+	NumberSame       NumberRelation = "same"
+	NumberHarmonious NumberRelation = "harmonious"
+	NumberNeutral    NumberRelation = "neutral"
+)
+
+// Rating summarizes a compatibility score
+type Rating string
+
+const (
+	RatingExcellent   Rating = "excellent"
+	RatingGood        Rating = "good"
+	RatingFair        Rating = "fair"
+	RatingChallenging Rating = "challenging"
+)
+
+// aspectBySteps maps the number of signs between two signs to their aspect
+var aspectBySteps = [7]Aspect{
+	AspectConjunction, AspectSemiSextile, AspectSextile, AspectSquare, AspectTrine, AspectQuincunx, AspectOpposition,
+}
+
+// aspectScores rates each aspect for compatibility
+var aspectScores = map[Aspect]int{
+	AspectConjunction: 80,
+	AspectSemiSextile: 55,
+	AspectSextile:     85,
+	AspectSquare:      40,
+	AspectTrine:       95,
+	AspectQuincunx:    45,
+	AspectOpposition:  65,
+}
+
+// numberGroups are the root numbers that naturally harmonize
+var numberGroups = map[int]int{1: 0, 5: 0, 7: 0, 2: 1, 4: 1, 8: 1, 3: 2, 6: 2, 9: 2}
+
+// Weights of each pairing in the overall score
+const (
+	weightSun       = 30
+	weightMoon      = 25
+	weightRising    = 10
+	weightVenusMars = 10
+	weightLifePath  = 20
+	weightName      = 5
+)
+
+// Profile is one side of a compatibility comparison
+type Profile struct {
+	Name  string
+	Chart *astrology.NatalChart
+	// LifePath and NameNumbers are optional
+	LifePath    *numerology.LifePathAnalysis
+	NameNumbers *numerology.NameAnalysis
+}
+
+// Compare computes the compatibility breakdown of two profiles
+func Compare(a, b Profile) *Breakdown {
+	breakdown := &Breakdown{
+		PersonA:   a.Name,
+		PersonB:   b.Name,
+		ElementsA: elementBalance(a.Chart),
+		ElementsB: elementBalance(b.Chart),
+	}
+
+	breakdown.Signs = append(breakdown.Signs,
+		pairSigns("sun", a.Chart.SunSign, b.Chart.SunSign, weightSun),
+		pairSigns("moon", a.Chart.MoonSign, b.Chart.MoonSign, weightMoon),
+	)
+	if a.Chart.RisingSign != nil && b.Chart.RisingSign != nil {
+		breakdown.Signs = append(breakdown.Signs, pairSigns("rising", *a.Chart.RisingSign, *b.Chart.RisingSign, weightRising))
+	}
+
+	// Attraction reads one person's Venus against the other's Mars, both ways
+	venusA, marsA, okA := venusAndMars(a.Chart)
+	venusB, marsB, okB := venusAndMars(b.Chart)
+	if okA && okB {
+		breakdown.Signs = append(breakdown.Signs,
+			pairSigns("venus_mars", venusA, marsB, weightVenusMars),
+			pairSigns("mars_venus", marsA, venusB, weightVenusMars),
+		)
+	}
+
+	if a.LifePath != nil && b.LifePath != nil {
+		breakdown.Numbers = append(breakdown.Numbers, pairNumbers("life_path", a.LifePath.LifePath, b.LifePath.LifePath, weightLifePath))
+	}
+	if a.NameNumbers != nil && b.NameNumbers != nil {
+		breakdown.Numbers = append(breakdown.Numbers, pairNumbers("name", a.NameNumbers.Total, b.NameNumbers.Total, weightName))
+	}
+
+	totalWeight, weightedScore := 0, 0
+	for _, pairing := range breakdown.Signs {
+		totalWeight += pairing.Weight
+		weightedScore += pairing.Score * pairing.Weight
+	}
+	for _, pairing := range breakdown.Numbers {
+		totalWeight += pairing.Weight
+		weightedScore += pairing.Score * pairing.Weight
+	}
+	breakdown.Score = int(math.Round(float64(weightedScore) / float64(totalWeight)))
+	breakdown.Rating = ratingOf(breakdown.Score)
+
+	return breakdown
+}
+
+// pairSigns compares two signs by aspect and element
+func pairSigns(key string, a, b astrology.Sign, weight int) SignPairing {
+	aspect := aspectBetween(a, b)
+	return SignPairing{
+		Key:             key,
+		SignA:           a.Key,
+		SignB:           b.Key,
+		ElementA:        a.Element,
+		ElementB:        b.Element,
+		Aspect:          aspect,
+		ElementRelation: elementRelation(a.Element, b.Element),
+		Score:           aspectScores[aspect],
+		Weight:          weight,
+	}
+}
+
+// aspectBetween returns the whole-sign aspect between two signs
+func aspectBetween(a, b astrology.Sign) Aspect {
+	steps := signIndex(b) - signIndex(a)
+	if steps < 0 {
+		steps = -steps
+	}
+	if steps > 6 {
+		steps = 12 - steps
+	}
+	return aspectBySteps[steps]
+}
+
+func signIndex(sign astrology.Sign) int {
+	for index, candidate := range astrology.Signs {
+		if candidate.Key == sign.Key {
+			return index
+		}
+	}
+	return 0
+}
+
+// elementRelation classifies two elements: fire feeds air and earth holds water,
+// while fire with water and earth with air pull against each other
+func elementRelation(a, b astrology.Element) ElementRelation {
+	if a == b {
+		return ElementSame
+	}
+	pair := map[astrology.Element]bool{a: true, b: true}
+	switch {
+	case pair[astrology.ElementFire] && pair[astrology.ElementAir],
+		pair[astrology.ElementEarth] && pair[astrology.ElementWater]:
+		return ElementComplementary
+	case pair[astrology.ElementFire] && pair[astrology.ElementWater],
+		pair[astrology.ElementEarth] && pair[astrology.ElementAir]:
+		return ElementChallenging
+	default:
+		return ElementNeutral
+	}
+}
+
+// pairNumbers compares the roots of two numerology numbers
+func pairNumbers(key string, a, b int, weight int) NumberPairing {
+	rootA, rootB := numerology.Root(a), numerology.Root(b)
+
+	pairing := NumberPairing{Key: key, NumberA: a, NumberB: b, Weight: weight}
+	switch {
+	case rootA == rootB:
+		pairing.Relation = NumberSame
+		pairing.Score = 80
+	case numberGroups[rootA] == numberGroups[rootB]:
+		pairing.Relation = NumberHarmonious
+		pairing.Score = 90
+	default:
+		pairing.Relation = NumberNeutral
+		pairing.Score = 55
+	}
+	return pairing
+}
+
+// venusAndMars returns the signs of Venus and Mars in a chart
+func venusAndMars(chart *astrology.NatalChart) (venus, mars astrology.Sign, ok bool) {
+	var foundVenus, foundMars bool
+	for _, planet := range chart.Planets {
+		switch planet.Body {
+		case astrology.BodyVenus:
+			venus, foundVenus = planet.Sign, true
+		case astrology.BodyMars:
+			mars, foundMars = planet.Sign, true
+		}
+	}
+	return venus, mars, foundVenus && foundMars
+}
+
+// elementBalance counts the chart's planets per element
+func elementBalance(chart *astrology.NatalChart) ElementBalance {
+	var balance ElementBalance
+	for _, planet := range chart.Planets {
+		switch planet.Sign.Element {
+		case astrology.ElementFire:
+			balance.Fire++
+		case astrology.ElementEarth:
+			balance.Earth++
+		case astrology.ElementAir:
+			balance.Air++
+		case astrology.ElementWater:
+			balance.Water++
+		}
+	}
+	return balance
+}
+
+// ratingOf buckets a compatibility score
+func ratingOf(score int) Rating {
+	switch {
+	case score >= 80:
+		return RatingExcellent
+	case score >= 65:
+		return RatingGood
+	case score >= 50:
+		return RatingFair
+	default:
+		return RatingChallenging
+	}
+}
@@ -0,0 +1,7 @@
+package compatibility
+
+// ReadingRequest asks for a compatibility reading with a saved person
+type ReadingRequest struct {
+	PersonID string `json:"person_id" validate:"required,uuid"`
+	Question string `json:"question" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,62 @@
+package compatibility
+
+import (
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/person"
+
+	"github.com/google/uuid"
+)
+
+// SignPairing compares the signs two people have for the same placement
+type SignPairing struct {
+	Key             string            `json:"key"`
+	SignA           string            `json:"sign_a"`
+	SignB           string            `json:"sign_b"`
+	ElementA        astrology.Element `json:"element_a"`
+	ElementB        astrology.Element `json:"element_b"`
+	Aspect          Aspect            `json:"aspect"`
+	ElementRelation ElementRelation   `json:"element_relation"`
+	Score           int               `json:"score"`
+	Weight          int               `json:"weight"`
+}
+
+// ElementBalance counts the planets a chart has in each element
+type ElementBalance struct {
+	Fire  int `json:"fire"`
+	Earth int `json:"earth"`
+	Air   int `json:"air"`
+	Water int `json:"water"`
+}
+
+// NumberPairing compares the numerology numbers of two people
+type NumberPairing struct {
+	Key      string         `json:"key"`
+	NumberA  int            `json:"number_a"`
+	NumberB  int            `json:"number_b"`
+	Relation NumberRelation `json:"relation"`
+	Score    int            `json:"score"`
+	Weight   int            `json:"weight"`
+}
+
+// Breakdown is the deterministic compatibility of two birth profiles.
+// The score is the weighted average of the sign and number pairings.
+type Breakdown struct {
+	PersonA   string          `json:"person_a"`
+	PersonB   string          `json:"person_b"`
+	Signs     []SignPairing   `json:"signs"`
+	ElementsA ElementBalance  `json:"elements_a"`
+	ElementsB ElementBalance  `json:"elements_b"`
+	Numbers   []NumberPairing `json:"numbers"`
+	Score     int             `json:"score"`
+	Rating    Rating          `json:"rating"`
+}
+
+// ReadingResponse is the agent's interpretation of a compatibility breakdown
+type ReadingResponse struct {
+	SessionID uuid.UUID             `json:"session_id"`
+	Person    person.PersonResponse `json:"person"`
+	Breakdown *Breakdown            `json:"breakdown"`
+	Message   string                `json:"message"`
+	Card      string                `json:"card,omitempty"`
+	Meaning   string                `json:"meaning,omitempty"`
+}
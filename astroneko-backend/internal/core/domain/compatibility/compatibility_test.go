@@ -0,0 +1,102 @@
+package compatibility
+
+import (
+	"testing"
+
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/numerology"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestChart(sun, moon, venus, mars string) *astrology.NatalChart {
+	sign := func(key string) astrology.Sign {
+		s, _ := astrology.SignByKey(key)
+		return s
+	}
+
+	return &astrology.NatalChart{
+		SunSign:  sign(sun),
+		MoonSign: sign(moon),
+		Planets: []astrology.PlanetPosition{
+			{Body: astrology.BodySun, Sign: sign(sun)},
+			{Body: astrology.BodyMoon, Sign: sign(moon)},
+			{Body: astrology.BodyVenus, Sign: sign(venus)},
+			{Body: astrology.BodyMars, Sign: sign(mars)},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Arrange
+	a := Profile{
+		Name:     "A",
+		Chart:    buildTestChart("aries", "cancer", "taurus", "leo"),
+		LifePath: &numerology.LifePathAnalysis{LifePath: 3},
+	}
+	b := Profile{
+		Name:     "B",
+		Chart:    buildTestChart("leo", "capricorn", "libra", "scorpio"),
+		LifePath: &numerology.LifePathAnalysis{LifePath: 6},
+	}
+
+	// Act
+	breakdown := Compare(a, b)
+
+	// Assert
+	assert.Equal(t, "A", breakdown.PersonA)
+	assert.Len(t, breakdown.Signs, 4)
+	assert.Equal(t, SignPairing{
+		Key: "sun", SignA: "aries", SignB: "leo",
+		ElementA: astrology.ElementFire, ElementB: astrology.ElementFire,
+		Aspect: AspectTrine, ElementRelation: ElementSame, Score: 95, Weight: 30,
+	}, breakdown.Signs[0])
+	assert.Equal(t, AspectOpposition, breakdown.Signs[1].Aspect)
+	assert.Equal(t, ElementComplementary, breakdown.Signs[1].ElementRelation)
+	assert.Equal(t, "venus_mars", breakdown.Signs[2].Key)
+	assert.Equal(t, AspectOpposition, breakdown.Signs[2].Aspect)
+	assert.Equal(t, AspectSextile, breakdown.Signs[3].Aspect)
+
+	assert.Equal(t, []NumberPairing{
+		{Key: "life_path", NumberA: 3, NumberB: 6, Relation: NumberHarmonious, Score: 90, Weight: 20},
+	}, breakdown.Numbers)
+	assert.Equal(t, ElementBalance{Fire: 2, Earth: 1, Water: 1}, breakdown.ElementsA)
+
+	// (95*30 + 65*25 + 65*10 + 85*10 + 90*20) / 95
+	assert.Equal(t, 82, breakdown.Score)
+	assert.Equal(t, RatingExcellent, breakdown.Rating)
+}
+
+func TestCompare_IsSymmetricInScore(t *testing.T) {
+	a := Profile{Chart: buildTestChart("gemini", "pisces", "cancer", "virgo")}
+	b := Profile{Chart: buildTestChart("sagittarius", "virgo", "aquarius", "aries")}
+
+	assert.Equal(t, Compare(a, b).Score, Compare(b, a).Score)
+}
+
+func TestAspectBetween(t *testing.T) {
+	sign := func(key string) astrology.Sign {
+		s, _ := astrology.SignByKey(key)
+		return s
+	}
+
+	assert.Equal(t, AspectConjunction, aspectBetween(sign("leo"), sign("leo")))
+	assert.Equal(t, AspectSemiSextile, aspectBetween(sign("pisces"), sign("aries")))
+	assert.Equal(t, AspectSquare, aspectBetween(sign("aries"), sign("capricorn")))
+	assert.Equal(t, AspectQuincunx, aspectBetween(sign("aries"), sign("virgo")))
+	assert.Equal(t, AspectOpposition, aspectBetween(sign("libra"), sign("aries")))
+}
+
+func TestElementRelation(t *testing.T) {
+	assert.Equal(t, ElementComplementary, elementRelation(astrology.ElementAir, astrology.ElementFire))
+	assert.Equal(t, ElementComplementary, elementRelation(astrology.ElementWater, astrology.ElementEarth))
+	assert.Equal(t, ElementChallenging, elementRelation(astrology.ElementFire, astrology.ElementWater))
+	assert.Equal(t, ElementNeutral, elementRelation(astrology.ElementFire, astrology.ElementEarth))
+}
+
+func TestPairNumbers_MasterNumbersReduce(t *testing.T) {
+	pairing := pairNumbers("life_path", 11, 2, 20)
+
+	assert.Equal(t, NumberSame, pairing.Relation)
+	assert.Equal(t, 80, pairing.Score)
+}
@@ -0,0 +1,144 @@
+package credential_attempt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/google/uuid"
+)
+
+// Scopes are the endpoints that accept a secret and are tracked separately
+const (
+	ScopeCRMLogin         = "crm_login"
+	ScopeUserLogin        = "user_login"
+	ScopeReferralValidate = "referral_validate"
+)
+
+// Key types. Each failed attempt counts against both the account and the client IP.
+const (
+	KeyAccount = "account"
+	KeyIP      = "ip"
+)
+
+// Policy decides how long a key is blocked after a number of consecutive failures.
+// The first FreeAttempts failures cost nothing, each further failure doubles the
+// delay starting from BaseDelay up to MaxDelay, and LockoutAfter failures lock the
+// key for LockoutDuration. Failures older than Window are forgotten.
+type Policy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// AccountPolicy is strict because one account rarely has more than one person typing
+var AccountPolicy = Policy{
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAfter:    10,
+	LockoutDuration: 15 * time.Minute,
+	Window:          time.Hour,
+}
+
+// IPPolicy is looser than AccountPolicy since offices and mobile carriers share addresses
+var IPPolicy = Policy{
+	FreeAttempts:    20,
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Minute,
+	LockoutAfter:    100,
+	LockoutDuration: time.Hour,
+	Window:          time.Hour,
+}
+
+// PolicyFor returns the policy applied to a key type
+func PolicyFor(keyType string) Policy {
+	if keyType == KeyIP {
+		return IPPolicy
+	}
+	return AccountPolicy
+}
+
+// Block returns how long the key is blocked after the given number of failures and
+// whether that block is a lockout rather than a backoff delay
+func (p Policy) Block(failures int) (time.Duration, bool) {
+	if failures >= p.LockoutAfter {
+		return p.LockoutDuration, true
+	}
+	if failures <= p.FreeAttempts {
+		return 0, false
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay, false
+}
+
+// Attempt counts consecutive failures for one key in one scope. Keys are stored as
+// SHA-256 hashes so the table holds no usernames, emails or addresses.
+type Attempt struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Scope         string     `json:"scope" gorm:"type:varchar(32);not null"`
+	KeyType       string     `json:"key_type" gorm:"type:varchar(16);not null"`
+	KeyHash       string     `json:"-" gorm:"type:varchar(64);not null"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt time.Time  `json:"last_failure_at" gorm:"not null"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (Attempt) TableName() string {
+	return "astroneko_credential_attempts"
+}
+
+// RetryAfter returns how long the key is still blocked, or zero
+func (a *Attempt) RetryAfter(now time.Time) time.Duration {
+	if a == nil || a.LockedUntil == nil || !a.LockedUntil.After(now) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now)
+}
+
+// NormalizeAccount makes usernames and emails that differ only in case or
+// surrounding spaces count against the same key
+func NormalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// BlockedError is returned while a key is blocked and tells the caller when to retry
+type BlockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", shared.ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *BlockedError) Unwrap() error {
+	return shared.ErrTooManyAttempts
+}
+
+// RetryAfterSeconds rounds the wait up so that clients never retry too early
+func (e *BlockedError) RetryAfterSeconds() int {
+	seconds := int(e.RetryAfter / time.Second)
+	if e.RetryAfter%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// UnlockResponse reports whether the account had failed attempts to clear
+type UnlockResponse struct {
+	Unlocked bool `json:"unlocked"`
+}
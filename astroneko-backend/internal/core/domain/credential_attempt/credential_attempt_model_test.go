@@ -0,0 +1,68 @@
+package credential_attempt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Block(t *testing.T) {
+	tests := []struct {
+		failures    int
+		wantDelay   time.Duration
+		wantLockout bool
+	}{
+		{failures: 1, wantDelay: 0},
+		{failures: 3, wantDelay: 0},
+		{failures: 4, wantDelay: time.Second},
+		{failures: 5, wantDelay: 2 * time.Second},
+		{failures: 9, wantDelay: 32 * time.Second},
+		{failures: 10, wantDelay: 15 * time.Minute, wantLockout: true},
+		{failures: 25, wantDelay: 15 * time.Minute, wantLockout: true},
+	}
+
+	for _, tt := range tests {
+		delay, lockout := AccountPolicy.Block(tt.failures)
+
+		assert.Equal(t, tt.wantDelay, delay, "failures=%d", tt.failures)
+		assert.Equal(t, tt.wantLockout, lockout, "failures=%d", tt.failures)
+	}
+}
+
+func TestPolicy_Block_CapsDelay(t *testing.T) {
+	policy := Policy{FreeAttempts: 0, BaseDelay: time.Second, MaxDelay: 10 * time.Second, LockoutAfter: 1000, LockoutDuration: time.Hour}
+
+	delay, lockout := policy.Block(999)
+
+	assert.Equal(t, 10*time.Second, delay)
+	assert.False(t, lockout)
+}
+
+func TestAttempt_RetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(90 * time.Second)
+	earlier := now.Add(-time.Second)
+
+	var missing *Attempt
+	assert.Zero(t, missing.RetryAfter(now))
+	assert.Zero(t, (&Attempt{}).RetryAfter(now))
+	assert.Zero(t, (&Attempt{LockedUntil: &earlier}).RetryAfter(now))
+	assert.Equal(t, 90*time.Second, (&Attempt{LockedUntil: &later}).RetryAfter(now))
+}
+
+func TestBlockedError(t *testing.T) {
+	err := error(&BlockedError{RetryAfter: 1500 * time.Millisecond})
+
+	var blocked *BlockedError
+	assert.True(t, errors.Is(err, shared.ErrTooManyAttempts))
+	assert.True(t, errors.As(err, &blocked))
+	assert.Equal(t, 2, blocked.RetryAfterSeconds())
+}
+
+func TestNormalizeAccount(t *testing.T) {
+	assert.Equal(t, "admin@example.com", NormalizeAccount("  Admin@Example.com "))
+}
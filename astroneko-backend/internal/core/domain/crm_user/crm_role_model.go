@@ -0,0 +1,87 @@
+package crm_user
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Built-in roles, seeded by migration 013
+const (
+	RoleViewer    = "viewer"
+	RoleSupport   = "support"
+	RoleMarketing = "marketing"
+	RoleAdmin     = "admin"
+)
+
+// Permissions checked by CRMAuthMiddleware.RequirePermission
+const (
+	PermissionAppUsersRead       = "app_users:read"
+	PermissionAppUsersWrite      = "app_users:write"
+	PermissionUserLimitWrite     = "user_limit:write"
+	PermissionReferralCodesRead  = "referral_codes:read"
+	PermissionReferralCodesWrite = "referral_codes:write"
+	PermissionCRMUsersManage     = "crm_users:manage"
+	PermissionAuditLogRead       = "audit_log:read"
+	PermissionWaitingListsRead   = "waiting_lists:read"
+	PermissionWaitingListsWrite  = "waiting_lists:write"
+)
+
+// Role is a named set of permissions that can be assigned to CRM users
+type Role struct {
+	Name        string   `json:"name" gorm:"type:varchar(32);primaryKey"`
+	Description string   `json:"description" gorm:"type:varchar(255);not null"`
+	Permissions []string `json:"permissions" gorm:"-"`
+}
+
+func (Role) TableName() string {
+	return "astroneko_crm_roles"
+}
+
+// RolePermission grants one permission to a role
+type RolePermission struct {
+	Role       string `json:"role" gorm:"type:varchar(32);primaryKey"`
+	Permission string `json:"permission" gorm:"type:varchar(64);primaryKey"`
+}
+
+func (RolePermission) TableName() string {
+	return "astroneko_crm_role_permissions"
+}
+
+// UserRole assigns a role to a CRM user
+type UserRole struct {
+	CRMUserID uuid.UUID  `json:"crm_user_id" gorm:"type:uuid;primaryKey"`
+	Role      string     `json:"role" gorm:"type:varchar(32);primaryKey"`
+	GrantedBy *uuid.UUID `json:"granted_by" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (UserRole) TableName() string {
+	return "astroneko_crm_user_roles"
+}
+
+// HasAllPermissions reports whether granted contains every required permission
+func HasAllPermissions(granted []string, required ...string) bool {
+	for _, permission := range required {
+		if !slices.Contains(granted, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+type AssignRolesRequest struct {
+	// Roles replaces the user's current roles; an empty list removes all of them
+	Roles []string `json:"roles" validate:"dive,required,max=32"`
+}
+
+type BootstrapAdminRequest struct {
+	Username       string `json:"username" validate:"required,min=3,max=50"`
+	Password       string `json:"password" validate:"required,max=72"`
+	BootstrapToken string `json:"bootstrap_token" validate:"required"`
+}
+
+type ListRolesResponse struct {
+	Roles []Role `json:"roles"`
+}
@@ -1,6 +1,9 @@
 package crm_user
 
 import (
+	"sync"
+	"time"
+
 	"astroneko-backend/internal/core/domain/shared"
 
 	"github.com/google/uuid"
@@ -11,12 +14,40 @@ type CRMUser struct {
 	shared.NoDeletedModel
 	Username string `json:"username" gorm:"not null;unique"`
 	Password string `json:"-" gorm:"not null"`
+	// DisabledAt is set while the user is blocked from logging in
+	DisabledAt        *time.Time `json:"disabled_at"`
+	PasswordChangedAt *time.Time `json:"password_changed_at"`
+	// SessionVersion is copied into every token; bumping it revokes all issued tokens
+	SessionVersion int `json:"-" gorm:"not null;default:0"`
+	// TOTPSecret is set at setup; two-factor authentication is on once TOTPEnabledAt is set too
+	TOTPSecret    string     `json:"-" gorm:"type:varchar(64)"`
+	TOTPEnabledAt *time.Time `json:"-"`
+	// TOTPLastStep is the time step of the last accepted code, so codes cannot be replayed
+	TOTPLastStep int64 `json:"-" gorm:"not null;default:0"`
+	// Roles and Permissions are loaded from the role tables by the repository
+	Roles       []string `json:"roles" gorm:"-"`
+	Permissions []string `json:"permissions" gorm:"-"`
 }
 
 func (CRMUser) TableName() string {
 	return "astroneko_crm_users"
 }
 
+// Columns that updates of a loaded user may write. SessionVersion is only ever bumped in
+// SQL, so a change that revokes sessions cannot be undone by a row read before it.
+const (
+	ColumnDisabledAt = "disabled_at"
+	ColumnTOTPSecret = "totp_secret"
+	// ColumnTOTPEnabledAt is written once setup is confirmed
+	ColumnTOTPEnabledAt = "totp_enabled_at"
+)
+
+// PasswordColumns are the columns set together by SetPassword
+var PasswordColumns = []string{"password", "password_changed_at"}
+
+// TwoFactorColumns are the columns set together by ClearTwoFactor
+var TwoFactorColumns = []string{ColumnTOTPSecret, ColumnTOTPEnabledAt, "totp_last_step"}
+
 func (u *CRMUser) HashPassword(password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -26,43 +57,139 @@ func (u *CRMUser) HashPassword(password string) error {
 	return nil
 }
 
+// dummyPasswordHash is what logins for unknown usernames are checked against
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// CheckDummyPassword spends as long as CheckPassword without matching anything, so a
+// login for a username that does not exist is not answered noticeably faster
+func CheckDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+}
+
 func (u *CRMUser) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	return err == nil
 }
 
+// IsDisabled reports whether the user is blocked from logging in
+func (u *CRMUser) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsTwoFactorEnabled reports whether logins need a TOTP or recovery code
+func (u *CRMUser) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil && u.TOTPSecret != ""
+}
+
+// ClearTwoFactor turns two-factor authentication off and forgets the secret
+func (u *CRMUser) ClearTwoFactor() {
+	u.TOTPSecret = ""
+	u.TOTPEnabledAt = nil
+	u.TOTPLastStep = 0
+}
+
+// SetPassword hashes the new password. Saving it with the repository's
+// UpdateAndRevokeSessions revokes every token issued before the change.
+func (u *CRMUser) SetPassword(password string, now time.Time) error {
+	if err := u.HashPassword(password); err != nil {
+		return err
+	}
+	u.PasswordChangedAt = &now
+	return nil
+}
+
+// HasPermission reports whether any of the user's roles grants the permission
+func (u *CRMUser) HasPermission(permission string) bool {
+	return HasAllPermissions(u.Permissions, permission)
+}
+
 type CreateCRMUserRequest struct {
-	Username string `json:"username" validate:"required,min=3,max=50"`
-	Password string `json:"password" validate:"required,min=8"`
+	Username string   `json:"username" validate:"required,min=3,max=50"`
+	Password string   `json:"password" validate:"required,max=72"`
+	Roles    []string `json:"roles" validate:"dive,required,max=32"`
 }
 
 type CRMLoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	// ClientIP is filled in by the handler for failed attempt tracking
+	ClientIP string `json:"-"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,max=72"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,max=72"`
 }
 
 type CRMUserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	CreatedAt string    `json:"created_at"`
-	UpdatedAt string    `json:"updated_at"`
+	ID          uuid.UUID `json:"id"`
+	Username    string    `json:"username"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+	Disabled    bool      `json:"disabled"`
+	DisabledAt  *string   `json:"disabled_at,omitempty"`
+	// TwoFactorEnabled is true once a TOTP authenticator is enrolled
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
 }
 
 func (u *CRMUser) ToResponse() *CRMUserResponse {
-	return &CRMUserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		CreatedAt: u.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt: u.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	response := &CRMUserResponse{
+		ID:               u.ID,
+		Username:         u.Username,
+		Roles:            u.Roles,
+		Permissions:      u.Permissions,
+		Disabled:         u.IsDisabled(),
+		TwoFactorEnabled: u.IsTwoFactorEnabled(),
+		CreatedAt:        u.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        u.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+	if u.DisabledAt != nil {
+		disabledAt := u.DisabledAt.Format("2006-01-02T15:04:05Z")
+		response.DisabledAt = &disabledAt
+	}
+	return response
 }
 
+type ListCRMUsersResponse struct {
+	Users  []CRMUserResponse `json:"users"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// CRMLoginResponse carries a new session, or a two-factor challenge in its place
 type CRMLoginResponse struct {
-	User  *CRMUserResponse `json:"user"`
-	Token string           `json:"token"`
+	User                  *CRMUserResponse    `json:"user"`
+	Token                 string              `json:"token,omitempty"`
+	ExpiresAt             *time.Time          `json:"expires_at,omitempty"`
+	RefreshToken          string              `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt *time.Time          `json:"refresh_token_expires_at,omitempty"`
+	TwoFactor             *TwoFactorChallenge `json:"two_factor,omitempty"`
+	// RecoveryCodes are returned once, when two-factor authentication is enabled while logging in
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 type JWTClaims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
+	ID             string    `json:"jti"`
+	UserID         uuid.UUID `json:"user_id"`
+	Username       string    `json:"username"`
+	Roles          []string  `json:"roles"`
+	Permissions    []string  `json:"permissions"`
+	SessionVersion int       `json:"session_version"`
+	ExpiresAt      time.Time `json:"exp"`
+}
+
+// HasPermission reports whether the token was issued with the permission
+func (c *JWTClaims) HasPermission(permission string) bool {
+	return HasAllPermissions(c.Permissions, permission)
 }
@@ -0,0 +1,69 @@
+package crm_user
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+// Password policy for CRM users
+const (
+	MinPasswordLength = 12
+	// MaxPasswordBytes is the most bcrypt will hash; anything longer is silently truncated
+	MaxPasswordBytes = 72
+	// MinPasswordClasses of lowercase, uppercase, digits and symbols must appear
+	MinPasswordClasses = 3
+)
+
+// commonPasswords are rejected even when they meet the length and class rules
+var commonPasswords = []string{
+	"password", "passw0rd", "qwerty", "letmein", "welcome", "admin", "astroneko", "123456", "iloveyou",
+}
+
+// CheckPasswordPolicy returns shared.ErrWeakPassword with the reason when the password
+// is not strong enough for a CRM account
+func CheckPasswordPolicy(password string, username string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return fmt.Errorf("%w: must be at least %d characters", shared.ErrWeakPassword, MinPasswordLength)
+	}
+	if len(password) > MaxPasswordBytes {
+		return fmt.Errorf("%w: must be at most %d bytes", shared.ErrWeakPassword, MaxPasswordBytes)
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+	classes := 0
+	for _, present := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < MinPasswordClasses {
+		return fmt.Errorf("%w: must mix at least %d of lowercase, uppercase, digits and symbols", shared.ErrWeakPassword, MinPasswordClasses)
+	}
+
+	lowered := strings.ToLower(password)
+	if username != "" && strings.Contains(lowered, strings.ToLower(username)) {
+		return fmt.Errorf("%w: must not contain the username", shared.ErrWeakPassword)
+	}
+	for _, common := range commonPasswords {
+		if strings.Contains(lowered, common) {
+			return fmt.Errorf("%w: must not contain a common password", shared.ErrWeakPassword)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package crm_user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		username string
+		wantErr  bool
+	}{
+		{"strong password", "Nebula-Orbit-2048", "alice", false},
+		{"three classes without symbols", "NebulaOrbit2048", "alice", false},
+		{"too short", "Neb-Orb-20", "alice", true},
+		{"too long for bcrypt", "Aa1-" + strings.Repeat("x", MaxPasswordBytes), "alice", true},
+		{"two classes", "nebulaorbit2048", "alice", true},
+		{"contains username", "Alice-Orbit-2048", "alice", true},
+		{"contains common password", "MyPassword-2048", "alice", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPasswordPolicy(tt.password, tt.username)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, shared.ErrWeakPassword)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package crm_user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetTTL is how long an admin-issued reset token stays valid
+const PasswordResetTTL = time.Hour
+
+// PasswordReset is a one-time token an admin issues so a CRM user can set a new password.
+// Only the SHA-256 hash of the token is stored.
+type PasswordReset struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CRMUserID uuid.UUID  `json:"crm_user_id" gorm:"type:uuid;not null;index:idx_crm_password_resets_user"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:idx_crm_password_resets_token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedBy *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (PasswordReset) TableName() string {
+	return "astroneko_crm_password_resets"
+}
+
+// IsUsable reports whether the token can still be redeemed
+func (r *PasswordReset) IsUsable(now time.Time) bool {
+	return r.UsedAt == nil && now.Before(r.ExpiresAt)
+}
+
+// PasswordResetResponse carries the plain token. It is shown once and never stored.
+type PasswordResetResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
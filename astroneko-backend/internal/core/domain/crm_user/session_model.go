@@ -0,0 +1,59 @@
+package crm_user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lifetimes of the tokens issued at login
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 14 * 24 * time.Hour
+)
+
+// RefreshToken lets a client get a new access token without logging in again. Each
+// refresh replaces the token with a new one in the same family; presenting a replaced
+// token again means it was stolen, and the whole family is revoked. Only the SHA-256
+// hash of the token is stored.
+type RefreshToken struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CRMUserID      uuid.UUID  `json:"crm_user_id" gorm:"type:uuid;not null;index:idx_crm_refresh_tokens_user"`
+	FamilyID       uuid.UUID  `json:"family_id" gorm:"type:uuid;not null;index:idx_crm_refresh_tokens_family"`
+	TokenHash      string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:idx_crm_refresh_tokens_token_hash"`
+	SessionVersion int        `json:"-" gorm:"not null"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	ReplacedBy     *uuid.UUID `json:"replaced_by" gorm:"type:uuid"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (RefreshToken) TableName() string {
+	return "astroneko_crm_refresh_tokens"
+}
+
+// IsActive reports whether the token can still be exchanged
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RevokedAccessToken denies an access token by its jti until the token would have expired anyway
+type RevokedAccessToken struct {
+	JTI       string    `json:"jti" gorm:"type:varchar(64);primaryKey"`
+	CRMUserID uuid.UUID `json:"crm_user_id" gorm:"type:uuid;not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index:idx_crm_revoked_access_tokens_expires_at"`
+	RevokedAt time.Time `json:"revoked_at" gorm:"not null;default:now()"`
+}
+
+func (RevokedAccessToken) TableName() string {
+	return "astroneko_crm_revoked_access_tokens"
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutRequest struct {
+	// RefreshToken, when given, is revoked along with every token rotated from it
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,64 @@
+package crm_user
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MinSigningSecretBytes is the shortest secret accepted for signing CRM tokens with HS256
+const MinSigningSecretBytes = 32
+
+// SigningKey is a secret that signs CRM access tokens. Its ID goes in the token's kid header.
+type SigningKey struct {
+	ID     string
+	Secret string
+}
+
+// KeyRing holds every key that CRM access tokens may be verified with. New tokens are
+// signed with the active key; the others keep tokens they signed valid until they expire,
+// so a key can be rotated without logging everyone out.
+type KeyRing struct {
+	active  SigningKey
+	secrets map[string][]byte
+}
+
+// NewKeyRing validates the keys and picks the active one
+func NewKeyRing(activeID string, keys []SigningKey) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+
+	ring := &KeyRing{secrets: make(map[string][]byte, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("signing key id is required")
+		}
+		if _, exists := ring.secrets[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		if len(key.Secret) < MinSigningSecretBytes {
+			return nil, fmt.Errorf("signing key %q must be at least %d bytes", key.ID, MinSigningSecretBytes)
+		}
+		ring.secrets[key.ID] = []byte(key.Secret)
+		if key.ID == activeID {
+			ring.active = key
+		}
+	}
+
+	if ring.active.ID == "" {
+		return nil, fmt.Errorf("active signing key %q is not configured", activeID)
+	}
+
+	return ring, nil
+}
+
+// Active returns the key new tokens are signed with
+func (r *KeyRing) Active() (string, []byte) {
+	return r.active.ID, r.secrets[r.active.ID]
+}
+
+// Secret returns the secret of the key with the ID, if it is still configured
+func (r *KeyRing) Secret(id string) ([]byte, bool) {
+	secret, ok := r.secrets[id]
+	return secret, ok
+}
@@ -0,0 +1,54 @@
+package crm_user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyRing(t *testing.T) {
+	secret := strings.Repeat("s", MinSigningSecretBytes)
+
+	tests := []struct {
+		name     string
+		activeID string
+		keys     []SigningKey
+		wantErr  string
+	}{
+		{"no keys", "k1", nil, "at least one signing key"},
+		{"missing id", "k1", []SigningKey{{Secret: secret}}, "id is required"},
+		{"duplicate id", "k1", []SigningKey{{ID: "k1", Secret: secret}, {ID: "k1", Secret: secret}}, "duplicate"},
+		{"short secret", "k1", []SigningKey{{ID: "k1", Secret: "short"}}, "at least"},
+		{"active key not listed", "k2", []SigningKey{{ID: "k1", Secret: secret}}, "not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := NewKeyRing(tt.activeID, tt.keys)
+			assert.Nil(t, ring)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	oldSecret := strings.Repeat("o", MinSigningSecretBytes)
+	newSecret := strings.Repeat("n", MinSigningSecretBytes)
+
+	ring, err := NewKeyRing("new", []SigningKey{{ID: "old", Secret: oldSecret}, {ID: "new", Secret: newSecret}})
+	require.NoError(t, err)
+
+	activeID, activeSecret := ring.Active()
+	assert.Equal(t, "new", activeID)
+	assert.Equal(t, []byte(newSecret), activeSecret)
+
+	retired, ok := ring.Secret("old")
+	assert.True(t, ok)
+	assert.Equal(t, []byte(oldSecret), retired)
+
+	_, ok = ring.Secret("removed")
+	assert.False(t, ok)
+}
@@ -0,0 +1,119 @@
+package crm_user
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// TwoFactorChallengeTTL is how long the user has to enter a code after the password step
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// RecoveryCodeCount codes are issued each time two-factor authentication is enabled
+	RecoveryCodeCount = 10
+)
+
+// Steps of a two-factor login challenge
+const (
+	TwoFactorStepVerify = "verify"
+	TwoFactorStepEnroll = "enroll"
+)
+
+// recoveryCodeAlphabet leaves out characters that are easy to confuse when read aloud or typed
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the authenticator
+// is lost. Only the SHA-256 hash of the normalized code is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CRMUserID uuid.UUID  `json:"crm_user_id" gorm:"type:uuid;not null;index:idx_crm_recovery_codes_user"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:now()"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "astroneko_crm_recovery_codes"
+}
+
+// GenerateRecoveryCodes returns count random codes formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	buf := make([]byte, 10)
+	for i := 0; i < count; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		var code strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				code.WriteByte('-')
+			}
+			code.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, code.String())
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode lowercases the code and drops separators, so codes typed with
+// different casing or without the dash still match
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// TwoFactorChallenge replaces the tokens in a login response when the password was
+// right but a second step is needed
+type TwoFactorChallenge struct {
+	// Step is "verify" to enter a code, or "enroll" when two-factor authentication is required but not set up yet
+	Step           string    `json:"step"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// Code is the current TOTP code; RecoveryCode may be sent instead
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	ClientIP     string `json:"-"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	// QRCodePNG encodes the provisioning URI; it is base64 in JSON
+	QRCodePNG []byte `json:"qr_code_png" swaggertype:"string" format:"base64"`
+}
+
+type RecoveryCodesResponse struct {
+	// RecoveryCodes are shown once; each works a single time
+	RecoveryCodes []string `json:"recovery_codes"`
+}
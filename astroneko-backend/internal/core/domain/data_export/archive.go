@@ -0,0 +1,318 @@
+package data_export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/guest_usage"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// SessionMessages is a history session with its messages
+type SessionMessages struct {
+	Session  history.Session
+	Messages []history.Message
+}
+
+// Contents is everything collected for one user's export
+type Contents struct {
+	GeneratedAt   time.Time
+	User          *user.User
+	Sessions      []SessionMessages
+	People        []person.Person
+	ReferralCodes []*referral_code.UserReferralCode
+	Redemptions   []*referral_code.ReferralLog
+	Rewards       []*referral_reward.Reward
+	Clicks        []*referral_link.Click
+	QuotaUsage    []*guest_usage.GuestAPIUsage
+}
+
+type profileFile struct {
+	ID                  uuid.UUID  `json:"id"`
+	Email               string     `json:"email"`
+	DisplayName         *string    `json:"display_name"`
+	ProfileImageURL     *string    `json:"profile_image_url"`
+	FirebaseUID         string     `json:"firebase_uid"`
+	IsActivatedReferral bool       `json:"is_activated_referral"`
+	BirthDate           *string    `json:"birth_date"`
+	BirthTime           *string    `json:"birth_time"`
+	BirthPlace          *string    `json:"birth_place"`
+	BirthLatitude       *float64   `json:"birth_latitude"`
+	BirthLongitude      *float64   `json:"birth_longitude"`
+	BirthTimezone       *string    `json:"birth_timezone"`
+	LatestLoginAt       *time.Time `json:"latest_login_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type sessionFile struct {
+	ID          uuid.UUID     `json:"id"`
+	HistoryName string        `json:"history_name"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Messages    []messageFile `json:"messages"`
+}
+
+type messageFile struct {
+	ID         uuid.UUID `json:"id"`
+	Role       string    `json:"role"`
+	Message    string    `json:"message"`
+	Card       string    `json:"card,omitempty"`
+	Meaning    string    `json:"meaning,omitempty"`
+	UsedTokens int       `json:"used_tokens"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type referralsFile struct {
+	CodesGenerated []generatedCodeFile `json:"codes_generated"`
+	CodesRedeemed  []redeemedCodeFile  `json:"codes_redeemed"`
+}
+
+type generatedCodeFile struct {
+	ReferralCode string    `json:"referral_code"`
+	IsActivated  bool      `json:"is_activated"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type redeemedCodeFile struct {
+	CodeType   string    `json:"code_type"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+type rewardFile struct {
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount"`
+	Badge     *string   `json:"badge,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type clickFile struct {
+	CodeType    string     `json:"code_type"`
+	UTMSource   *string    `json:"utm_source"`
+	UTMMedium   *string    `json:"utm_medium"`
+	UTMCampaign *string    `json:"utm_campaign"`
+	UTMTerm     *string    `json:"utm_term"`
+	UTMContent  *string    `json:"utm_content"`
+	Referrer    *string    `json:"referrer"`
+	UserAgent   *string    `json:"user_agent"`
+	IsNewUser   bool       `json:"is_new_user"`
+	ClickedAt   time.Time  `json:"clicked_at"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+}
+
+type quotaUsageFile struct {
+	Endpoint      string    `json:"endpoint"`
+	UsageCount    int       `json:"usage_count"`
+	DailyLimit    int       `json:"daily_limit"`
+	WindowResetAt time.Time `json:"window_reset_at"`
+	LastRequestAt time.Time `json:"last_request_at"`
+}
+
+const readme = `Astroneko personal data export
+Generated at %s
+
+profile.json           your account and birth profile
+sessions.json          every conversation with its messages; tarot cards drawn are listed separately from the message text
+people.json            people you saved for compatibility readings
+referrals.json         referral codes generated for you and codes you redeemed
+referral_rewards.json  rewards credited to you when others redeemed your codes
+referral_clicks.json   referral links you followed before signing up or logging in
+quota_usage.json       daily request counts recorded against your account
+`
+
+// BuildArchive writes the contents as a ZIP of JSON files
+func BuildArchive(contents *Contents) ([]byte, error) {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", newProfileFile(contents.User)},
+		{"sessions.json", newSessionFiles(contents.Sessions)},
+		{"people.json", newPeopleFile(contents.People)},
+		{"referrals.json", newReferralsFile(contents.ReferralCodes, contents.Redemptions)},
+		{"referral_rewards.json", newRewardsFile(contents.Rewards)},
+		{"referral_clicks.json", newClicksFile(contents.Clicks)},
+		{"quota_usage.json", newQuotaUsageFile(contents.QuotaUsage)},
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	if err := writeZipFile(writer, "README.txt", contents.GeneratedAt, []byte(fmt.Sprintf(readme, contents.GeneratedAt.UTC().Format(time.RFC3339)))); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		if err := writeZipFile(writer, file.name, contents.GeneratedAt, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(writer *zip.Writer, name string, modified time.Time, data []byte) error {
+	w, err := writer.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to export archive: %w", name, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to export archive: %w", name, err)
+	}
+
+	return nil
+}
+
+func newProfileFile(u *user.User) profileFile {
+	profile := profileFile{
+		ID:                  u.ID,
+		Email:               u.Email,
+		DisplayName:         u.DisplayName,
+		ProfileImageURL:     u.ProfileImageURL,
+		FirebaseUID:         u.FirebaseUID,
+		IsActivatedReferral: u.IsActivatedReferral,
+		BirthTime:           u.BirthTime,
+		BirthPlace:          u.BirthPlace,
+		BirthLatitude:       u.BirthLatitude,
+		BirthLongitude:      u.BirthLongitude,
+		BirthTimezone:       u.BirthTimezone,
+		LatestLoginAt:       u.LatestLoginAt,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
+	}
+	if u.BirthDate != nil {
+		birthDate := u.BirthDate.Format("2006-01-02")
+		profile.BirthDate = &birthDate
+	}
+	return profile
+}
+
+func newSessionFiles(sessions []SessionMessages) []sessionFile {
+	files := make([]sessionFile, 0, len(sessions))
+	for _, s := range sessions {
+		messages := make([]messageFile, 0, len(s.Messages))
+		for _, m := range s.Messages {
+			cleaned, card, meaning := history.ExtractJSONFromMessage(m.Message)
+			messages = append(messages, messageFile{
+				ID:         m.ID,
+				Role:       m.Role,
+				Message:    cleaned,
+				Card:       card,
+				Meaning:    meaning,
+				UsedTokens: m.UsedTokens,
+				CreatedAt:  m.CreatedAt,
+			})
+		}
+
+		files = append(files, sessionFile{
+			ID:          s.Session.ID,
+			HistoryName: s.Session.HistoryName,
+			CreatedAt:   s.Session.CreatedAt,
+			UpdatedAt:   s.Session.UpdatedAt,
+			Messages:    messages,
+		})
+	}
+	return files
+}
+
+func newPeopleFile(people []person.Person) []person.PersonResponse {
+	files := make([]person.PersonResponse, 0, len(people))
+	for i := range people {
+		files = append(files, people[i].ToResponse())
+	}
+	return files
+}
+
+func newReferralsFile(codes []*referral_code.UserReferralCode, redemptions []*referral_code.ReferralLog) referralsFile {
+	file := referralsFile{
+		CodesGenerated: make([]generatedCodeFile, 0, len(codes)),
+		CodesRedeemed:  make([]redeemedCodeFile, 0, len(redemptions)),
+	}
+	for _, code := range codes {
+		file.CodesGenerated = append(file.CodesGenerated, generatedCodeFile{
+			ReferralCode: code.ReferralCode,
+			IsActivated:  code.IsActivated,
+			CreatedAt:    code.CreatedAt,
+		})
+	}
+	// Redemptions only say what kind of code was used; the code itself may belong to someone else
+	for _, log := range redemptions {
+		file.CodesRedeemed = append(file.CodesRedeemed, redeemedCodeFile{
+			CodeType:   log.CodeType,
+			RedeemedAt: log.CreatedAt,
+		})
+	}
+	return file
+}
+
+// newRewardsFile leaves out the redemption each reward was credited for, which belongs to the invitee
+func newRewardsFile(rewards []*referral_reward.Reward) []rewardFile {
+	files := make([]rewardFile, 0, len(rewards))
+	for _, reward := range rewards {
+		files = append(files, rewardFile{
+			Type:      reward.Type,
+			Amount:    reward.Amount,
+			Badge:     reward.Badge,
+			CreatedAt: reward.CreatedAt,
+		})
+	}
+	return files
+}
+
+// newClicksFile leaves out the code each click was for, which may belong to someone else
+func newClicksFile(clicks []*referral_link.Click) []clickFile {
+	files := make([]clickFile, 0, len(clicks))
+	for _, click := range clicks {
+		files = append(files, clickFile{
+			CodeType:    click.CodeType,
+			UTMSource:   click.UTMSource,
+			UTMMedium:   click.UTMMedium,
+			UTMCampaign: click.UTMCampaign,
+			UTMTerm:     click.UTMTerm,
+			UTMContent:  click.UTMContent,
+			Referrer:    click.Referrer,
+			UserAgent:   click.UserAgent,
+			IsNewUser:   click.IsNewUser,
+			ClickedAt:   click.CreatedAt,
+			ClaimedAt:   click.ClaimedAt,
+		})
+	}
+	return files
+}
+
+func newQuotaUsageFile(usage []*guest_usage.GuestAPIUsage) []quotaUsageFile {
+	files := make([]quotaUsageFile, 0, len(usage))
+	for _, u := range usage {
+		files = append(files, quotaUsageFile{
+			Endpoint:      u.Endpoint,
+			UsageCount:    u.UsageCount,
+			DailyLimit:    u.DailyLimit,
+			WindowResetAt: u.WindowResetAt,
+			LastRequestAt: u.LastRequestAt,
+		})
+	}
+	return files
+}
@@ -0,0 +1,132 @@
+package data_export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/guest_usage"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[file.Name] = data
+	}
+	return files
+}
+
+func TestBuildArchive_ContainsEveryFile(t *testing.T) {
+	// Arrange
+	birthDate := time.Date(1995, 3, 14, 0, 0, 0, 0, time.UTC)
+	u := &user.User{Email: "neko@example.com", FirebaseUID: "firebase-uid", BirthDate: &birthDate}
+	u.ID = uuid.New()
+
+	sessionID := uuid.New()
+	badge := "first_invite"
+	source := "instagram"
+	contents := &Contents{
+		GeneratedAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+		User:        u,
+		Sessions: []SessionMessages{{
+			Session: history.Session{ID: sessionID, UserID: u.ID, HistoryName: "ดูดวงความรัก"},
+			Messages: []history.Message{
+				{ID: uuid.New(), SessionID: sessionID, Role: history.MessageRoleUser, Message: "ความรักปีนี้เป็นอย่างไร"},
+				{ID: uuid.New(), SessionID: sessionID, Role: history.MessageRoleAssistant,
+					Message: history.EmbedCardInMessage("ไพ่ของท่านคือ THE_LOVERS", "THE_LOVERS", "Love and choices")},
+			},
+		}},
+		ReferralCodes: []*referral_code.UserReferralCode{{ReferralCode: "NEKO1234", IsActivated: true}},
+		Redemptions:   []*referral_code.ReferralLog{{RedeemedByUserID: u.ID, CodeType: "general"}},
+		Rewards:       []*referral_reward.Reward{{UserID: u.ID, ReferralLogID: uuid.New(), Type: referral_reward.TypeBadge, Badge: &badge}},
+		Clicks:        []*referral_link.Click{{CodeType: "user", ReferralCodeID: uuid.New(), UTMSource: &source, UserID: &u.ID}},
+		QuotaUsage:    []*guest_usage.GuestAPIUsage{{Endpoint: "/api/v1/agent/reply", UsageCount: 2, DailyLimit: 3}},
+	}
+
+	// Act
+	archive, err := BuildArchive(contents)
+
+	// Assert
+	require.NoError(t, err)
+	files := readArchive(t, archive)
+	for _, name := range []string{"README.txt", "profile.json", "sessions.json", "people.json", "referrals.json", "referral_rewards.json", "referral_clicks.json", "quota_usage.json"} {
+		assert.Contains(t, files, name)
+	}
+
+	var profile map[string]any
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, "neko@example.com", profile["email"])
+	assert.Equal(t, "1995-03-14", profile["birth_date"])
+
+	var sessions []sessionFile
+	require.NoError(t, json.Unmarshal(files["sessions.json"], &sessions))
+	require.Len(t, sessions, 1)
+	require.Len(t, sessions[0].Messages, 2)
+	assert.Equal(t, "ไพ่ของท่านคือ THE_LOVERS", sessions[0].Messages[1].Message)
+	assert.Equal(t, "THE_LOVERS", sessions[0].Messages[1].Card)
+	assert.Equal(t, "Love and choices", sessions[0].Messages[1].Meaning)
+
+	var referrals referralsFile
+	require.NoError(t, json.Unmarshal(files["referrals.json"], &referrals))
+	require.Len(t, referrals.CodesGenerated, 1)
+	assert.Equal(t, "NEKO1234", referrals.CodesGenerated[0].ReferralCode)
+	require.Len(t, referrals.CodesRedeemed, 1)
+	assert.Equal(t, "general", referrals.CodesRedeemed[0].CodeType)
+
+	var rewards []map[string]any
+	require.NoError(t, json.Unmarshal(files["referral_rewards.json"], &rewards))
+	require.Len(t, rewards, 1)
+	assert.Equal(t, "first_invite", rewards[0]["badge"])
+	assert.NotContains(t, rewards[0], "referral_log_id")
+
+	var clicks []map[string]any
+	require.NoError(t, json.Unmarshal(files["referral_clicks.json"], &clicks))
+	require.Len(t, clicks, 1)
+	assert.Equal(t, "instagram", clicks[0]["utm_source"])
+	assert.NotContains(t, clicks[0], "referral_code_id")
+
+	var usage []quotaUsageFile
+	require.NoError(t, json.Unmarshal(files["quota_usage.json"], &usage))
+	require.Len(t, usage, 1)
+	assert.Equal(t, 2, usage[0].UsageCount)
+}
+
+func TestBuildArchive_EmptyListsAreArrays(t *testing.T) {
+	// Arrange
+	u := &user.User{Email: "neko@example.com"}
+	contents := &Contents{GeneratedAt: time.Now(), User: u}
+
+	// Act
+	archive, err := BuildArchive(contents)
+
+	// Assert
+	require.NoError(t, err)
+	files := readArchive(t, archive)
+	assert.JSONEq(t, "[]", string(files["sessions.json"]))
+	assert.JSONEq(t, "[]", string(files["people.json"]))
+	assert.JSONEq(t, `{"codes_generated": [], "codes_redeemed": []}`, string(files["referrals.json"]))
+	assert.JSONEq(t, "[]", string(files["referral_rewards.json"]))
+	assert.JSONEq(t, "[]", string(files["referral_clicks.json"]))
+}
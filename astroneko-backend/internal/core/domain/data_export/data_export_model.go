@@ -0,0 +1,67 @@
+package data_export
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of an export job
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusProcessing Status = "processing"
+	StatusReady      Status = "ready"
+	StatusFailed     Status = "failed"
+	// StatusExpired means the archive passed its retention period and was removed
+	StatusExpired Status = "expired"
+)
+
+const (
+	// DownloadLinkTTL is how long a signed download link stays valid
+	DownloadLinkTTL = 15 * time.Minute
+	// RetentionPeriod is how long a finished archive is kept before it is removed
+	RetentionPeriod = 7 * 24 * time.Hour
+	// RateLimitWindow and MaxExportsPerWindow cap how many exports a user can request
+	RateLimitWindow     = 24 * time.Hour
+	MaxExportsPerWindow = 3
+	// MaxAttempts is how many times a failing job is retried before it is marked failed
+	MaxAttempts = 3
+)
+
+// ExportJob is a queued request for a copy of a user's personal data.
+// The finished ZIP is stored with the job until it expires.
+type ExportJob struct {
+	shared.NoDeletedModel
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;index:idx_data_exports_user_id;not null"`
+	Status      Status     `json:"status" gorm:"type:varchar(32);not null"`
+	Archive     []byte     `json:"-" gorm:"type:bytea"`
+	ArchiveSize int64      `json:"archive_size" gorm:"not null;default:0"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	LastError   *string    `json:"last_error"`
+}
+
+func (ExportJob) TableName() string {
+	return "astroneko_data_exports"
+}
+
+// IsActive reports whether the job is still queued or running
+func (j *ExportJob) IsActive() bool {
+	return j.Status == StatusPending || j.Status == StatusProcessing
+}
+
+// IsDownloadable reports whether the archive can be downloaded at the given time
+func (j *ExportJob) IsDownloadable(now time.Time) bool {
+	return j.Status == StatusReady && j.ExpiresAt != nil && now.Before(*j.ExpiresAt)
+}
+
+// FileName is the name the archive is downloaded as
+func (j *ExportJob) FileName() string {
+	return "astroneko-export-" + j.CreatedAt.UTC().Format("20060102") + ".zip"
+}
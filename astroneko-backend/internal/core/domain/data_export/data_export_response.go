@@ -0,0 +1,35 @@
+package data_export
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportJobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      Status     `json:"status"`
+	ArchiveSize int64      `json:"archive_size,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// DownloadURL is a signed link returned while the archive is ready; it expires after DownloadLinkTTL
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+type ListExportJobsResponse struct {
+	Exports []ExportJobResponse `json:"exports"`
+}
+
+// ToResponse converts an export job to its API representation without a download link
+func (j *ExportJob) ToResponse() ExportJobResponse {
+	return ExportJobResponse{
+		ID:          j.ID,
+		Status:      j.Status,
+		ArchiveSize: j.ArchiveSize,
+		RequestedAt: j.CreatedAt,
+		CompletedAt: j.CompletedAt,
+		ExpiresAt:   j.ExpiresAt,
+	}
+}
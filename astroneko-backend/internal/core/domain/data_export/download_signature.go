@@ -0,0 +1,40 @@
+package data_export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignDownload signs a download of the job's archive that is valid until expiresAt
+func SignDownload(key []byte, jobID uuid.UUID, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(downloadPayload(jobID, expiresAt.Unix())))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDownload reports whether the signature matches the job and expiry and has not expired
+func VerifyDownload(key []byte, jobID uuid.UUID, expiresUnix int64, signature string, now time.Time) bool {
+	if len(key) == 0 || now.Unix() > expiresUnix {
+		return false
+	}
+
+	expected, err := hex.DecodeString(SignDownload(key, jobID, time.Unix(expiresUnix, 0)))
+	if err != nil {
+		return false
+	}
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(expected, given)
+}
+
+func downloadPayload(jobID uuid.UUID, expiresUnix int64) string {
+	return jobID.String() + "." + strconv.FormatInt(expiresUnix, 10)
+}
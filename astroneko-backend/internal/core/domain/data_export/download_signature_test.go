@@ -0,0 +1,41 @@
+package data_export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyDownload(t *testing.T) {
+	key := []byte("test-signing-key")
+	jobID := uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(DownloadLinkTTL)
+	signature := SignDownload(key, jobID, expiresAt)
+
+	tests := []struct {
+		name      string
+		key       []byte
+		jobID     uuid.UUID
+		expires   int64
+		signature string
+		now       time.Time
+		want      bool
+	}{
+		{"valid link", key, jobID, expiresAt.Unix(), signature, now, true},
+		{"expired link", key, jobID, expiresAt.Unix(), signature, expiresAt.Add(time.Second), false},
+		{"other job", key, uuid.New(), expiresAt.Unix(), signature, now, false},
+		{"extended expiry", key, jobID, expiresAt.Add(time.Hour).Unix(), signature, now, false},
+		{"other key", []byte("other-key"), jobID, expiresAt.Unix(), signature, now, false},
+		{"missing key", nil, jobID, expiresAt.Unix(), signature, now, false},
+		{"malformed signature", key, jobID, expiresAt.Unix(), "not-hex", now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VerifyDownload(tt.key, tt.jobID, tt.expires, tt.signature, tt.now))
+		})
+	}
+}
@@ -47,6 +47,21 @@ func ExtractJSONFromMessage(message string) (cleanedMessage string, card string,
 	return cleanedMessage, card, meaning
 }
 
+// EmbedCardInMessage appends the card as a JSON block the way the agent stores it,
+// so ExtractJSONFromMessage can recover it when the history is read back
+func EmbedCardInMessage(message string, card string, meaning string) string {
+	if card == "" && meaning == "" {
+		return message
+	}
+
+	cardJSON, err := json.Marshal(CardData{Card: card, Meaning: meaning})
+	if err != nil {
+		return message
+	}
+
+	return message + "\n\n```json\n" + string(cardJSON) + "\n```"
+}
+
 // tryFixMalformedJSON attempts to fix common JSON formatting issues
 func tryFixMalformedJSON(jsonContent string) string {
 	// Remove any leading/trailing non-JSON characters
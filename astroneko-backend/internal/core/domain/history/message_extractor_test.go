@@ -120,3 +120,15 @@ func TestExtractJSONFromMessage_WithMalformedJSONMissingBraces(t *testing.T) {
 	assert.Equal(t, "Abundance, nurturing, and creativity", meaning)
 	assert.NotContains(t, cleanedMessage, "```json")
 }
+
+func TestEmbedCardInMessage_RoundTrip(t *testing.T) {
+	// Act
+	message := EmbedCardInMessage("ดวงของท่านทั้งสองเข้ากันได้ดี", "THE_LOVERS", "Harmony and choice")
+	cleanedMessage, card, meaning := ExtractJSONFromMessage(message)
+
+	// Assert
+	assert.Equal(t, "ดวงของท่านทั้งสองเข้ากันได้ดี", cleanedMessage)
+	assert.Equal(t, "THE_LOVERS", card)
+	assert.Equal(t, "Harmony and choice", meaning)
+	assert.Equal(t, "no card", EmbedCardInMessage("no card", "", ""))
+}
@@ -6,6 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// Message roles
+const (
+	MessageRoleUser      = "user"
+	MessageRoleAssistant = "assistant"
+)
+
 // Message represents a single message in a conversation history
 type Message struct {
 	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -24,3 +30,9 @@ type Message struct {
 func (Message) TableName() string {
 	return "astroneko_message_histories"
 }
+
+// MessageStats summarizes the messages across all active sessions of a user
+type MessageStats struct {
+	MessageCount    int64      `gorm:"column:message_count"`
+	LatestMessageAt *time.Time `gorm:"column:latest_message_at"`
+}
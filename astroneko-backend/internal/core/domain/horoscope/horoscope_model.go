@@ -0,0 +1,26 @@
+package horoscope
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+// DateLayout is the format of horoscope dates in requests and storage
+const DateLayout = "2006-01-02"
+
+// DefaultTimezone defines which calendar day "today" refers to
+const DefaultTimezone = "Asia/Bangkok"
+
+// DailyHoroscope is the generated horoscope of one zodiac sign for one day
+type DailyHoroscope struct {
+	shared.NoDeletedModel
+	Sign          string    `json:"sign" gorm:"type:varchar(20);not null;uniqueIndex:idx_daily_horoscopes_sign_date"`
+	HoroscopeDate time.Time `json:"horoscope_date" gorm:"type:date;not null;uniqueIndex:idx_daily_horoscopes_sign_date"`
+	ContentTH     string    `json:"content_th" gorm:"type:text;not null"`
+	ContentEN     string    `json:"content_en" gorm:"type:text;not null"`
+}
+
+func (DailyHoroscope) TableName() string {
+	return "astroneko_daily_horoscopes"
+}
@@ -0,0 +1,26 @@
+package horoscope
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/astrology"
+)
+
+// DailyHoroscopeResponse represents a daily horoscope in Thai and English
+type DailyHoroscopeResponse struct {
+	Sign        astrology.Sign `json:"sign"`
+	Date        string         `json:"date"`
+	ContentTH   string         `json:"content_th"`
+	ContentEN   string         `json:"content_en"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
+
+func (h *DailyHoroscope) ToResponse(sign astrology.Sign) *DailyHoroscopeResponse {
+	return &DailyHoroscopeResponse{
+		Sign:        sign,
+		Date:        h.HoroscopeDate.Format(DateLayout),
+		ContentTH:   h.ContentTH,
+		ContentEN:   h.ContentEN,
+		GeneratedAt: h.UpdatedAt,
+	}
+}
@@ -0,0 +1,184 @@
+package insight
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultTopCards is the number of most frequent cards returned
+	DefaultTopCards = 5
+	// DefaultRecentWeeks is the number of weekly buckets returned
+	DefaultRecentWeeks = 12
+)
+
+// BuildUserInsights aggregates card readings into user insights.
+// All day and week boundaries are computed in UTC relative to now.
+func BuildUserInsights(readings []CardReading, now time.Time, topCards int, recentWeeks int) *UserInsightsResponse {
+	now = now.UTC()
+	result := &UserInsightsResponse{
+		TopCards:    []CardFrequency{},
+		RecentWeeks: buildRecentWeeks(readings, now, recentWeeks),
+		GeneratedAt: now,
+	}
+
+	if len(readings) == 0 {
+		return result
+	}
+
+	frequencies := make(map[string]*CardFrequency)
+	var first, last time.Time
+
+	for i, reading := range readings {
+		readAt := reading.ReadAt.UTC()
+		if i == 0 || readAt.Before(first) {
+			first = readAt
+		}
+		if i == 0 || readAt.After(last) {
+			last = readAt
+		}
+
+		card := NormalizeCardName(reading.Card)
+		arcana := ClassifyCard(card)
+		switch arcana {
+		case ArcanaMajor:
+			result.Arcana.Major++
+		case ArcanaMinor:
+			result.Arcana.Minor++
+		default:
+			result.Arcana.Unknown++
+		}
+
+		frequency, exists := frequencies[card]
+		if !exists {
+			frequency = &CardFrequency{Card: card, Arcana: arcana}
+			frequencies[card] = frequency
+		}
+		frequency.Count++
+		if readAt.After(frequency.LastDrawAt) {
+			frequency.LastDrawAt = readAt
+		}
+	}
+
+	result.TotalReadings = len(readings)
+	result.DistinctCards = len(frequencies)
+	result.FirstReadingAt = &first
+	result.LastReadingAt = &last
+
+	classified := result.Arcana.Major + result.Arcana.Minor
+	if classified > 0 {
+		result.Arcana.MajorRatio = roundRatio(float64(result.Arcana.Major) / float64(classified))
+		result.Arcana.MinorRatio = roundRatio(float64(result.Arcana.Minor) / float64(classified))
+	}
+
+	// Sort by count desc, then most recent draw, then name for stable output
+	sorted := make([]CardFrequency, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		sorted = append(sorted, *frequency)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		if !sorted[i].LastDrawAt.Equal(sorted[j].LastDrawAt) {
+			return sorted[i].LastDrawAt.After(sorted[j].LastDrawAt)
+		}
+		return sorted[i].Card < sorted[j].Card
+	})
+	if topCards > 0 && len(sorted) > topCards {
+		sorted = sorted[:topCards]
+	}
+	result.TopCards = sorted
+
+	// Average readings per week since the week of the first reading
+	weeks := int(startOfWeek(now).Sub(startOfWeek(first)).Hours()/(24*7)) + 1
+	if weeks < 1 {
+		weeks = 1
+	}
+	result.ReadingsPerWeek = roundRatio(float64(len(readings)) / float64(weeks))
+
+	result.Streaks = calculateStreaks(readings, now)
+
+	return result
+}
+
+// buildRecentWeeks returns reading counts for the last N weeks, oldest first
+func buildRecentWeeks(readings []CardReading, now time.Time, recentWeeks int) []WeeklyReadings {
+	if recentWeeks <= 0 {
+		return []WeeklyReadings{}
+	}
+
+	currentWeek := startOfWeek(now)
+	buckets := make([]WeeklyReadings, recentWeeks)
+	for i := 0; i < recentWeeks; i++ {
+		buckets[i].WeekStart = currentWeek.AddDate(0, 0, -7*(recentWeeks-1-i))
+	}
+
+	oldest := buckets[0].WeekStart
+	for _, reading := range readings {
+		week := startOfWeek(reading.ReadAt.UTC())
+		if week.Before(oldest) || week.After(currentWeek) {
+			continue
+		}
+		index := int(week.Sub(oldest).Hours() / (24 * 7))
+		buckets[index].Count++
+	}
+
+	return buckets
+}
+
+// calculateStreaks returns the current and longest runs of consecutive reading days.
+// The current streak stays alive until a full day without readings has passed.
+func calculateStreaks(readings []CardReading, now time.Time) Streaks {
+	days := make(map[time.Time]bool)
+	for _, reading := range readings {
+		days[startOfDay(reading.ReadAt.UTC())] = true
+	}
+
+	sortedDays := make([]time.Time, 0, len(days))
+	for day := range days {
+		sortedDays = append(sortedDays, day)
+	}
+	sort.Slice(sortedDays, func(i, j int) bool {
+		return sortedDays[i].Before(sortedDays[j])
+	})
+
+	var streaks Streaks
+	run := 0
+	for i, day := range sortedDays {
+		if i > 0 && day.Sub(sortedDays[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > streaks.LongestDays {
+			streaks.LongestDays = run
+		}
+	}
+
+	today := startOfDay(now)
+	if len(sortedDays) > 0 {
+		lastDay := sortedDays[len(sortedDays)-1]
+		if lastDay.Equal(today) || lastDay.Equal(today.AddDate(0, 0, -1)) {
+			streaks.CurrentDays = run
+		}
+	}
+
+	return streaks
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek returns the Monday of the week containing t
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func roundRatio(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,127 @@
+package insight
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestReading(card string, readAt time.Time) CardReading {
+	return CardReading{Card: card, ReadAt: readAt}
+}
+
+func TestNormalizeCardName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"THE_FOOL", "THE_FOOL"},
+		{"The Fool", "THE_FOOL"},
+		{"ace-of-cups", "ACE_OF_CUPS"},
+		{"Strength", "THE_STRENGTH"},
+		{"THE_JUDGEMENT", "THE_JUDGMENT"},
+		{"  king of wands ", "KING_OF_WANDS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeCardName(tt.input))
+		})
+	}
+}
+
+func TestClassifyCard(t *testing.T) {
+	assert.Equal(t, ArcanaMajor, ClassifyCard("THE_WHEEL_OF_FORTUNE"))
+	assert.Equal(t, ArcanaMinor, ClassifyCard("QUEEN_OF_PENTACLES"))
+	assert.Equal(t, ArcanaUnknown, ClassifyCard("CAT_OF_LUCK"))
+}
+
+func TestBuildUserInsights_Empty(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	result := BuildUserInsights(nil, now, DefaultTopCards, DefaultRecentWeeks)
+
+	// Assert
+	assert.Equal(t, 0, result.TotalReadings)
+	assert.Empty(t, result.TopCards)
+	assert.Len(t, result.RecentWeeks, DefaultRecentWeeks)
+	assert.Nil(t, result.FirstReadingAt)
+	assert.Nil(t, result.LastReadingAt)
+	assert.Equal(t, Streaks{}, result.Streaks)
+}
+
+func TestBuildUserInsights_Aggregates(t *testing.T) {
+	// Arrange
+	// 2026-03-11 is a Wednesday
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	readings := []CardReading{
+		buildTestReading("THE_FOOL", time.Date(2026, 2, 25, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("ACE_OF_CUPS", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("THE_FOOL", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("The Fool", time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("TWO_OF_SWORDS", time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC)),
+		buildTestReading("THE_SUN", time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)),
+	}
+
+	// Act
+	result := BuildUserInsights(readings, now, 2, 3)
+
+	// Assert
+	assert.Equal(t, 6, result.TotalReadings)
+	assert.Equal(t, 4, result.DistinctCards)
+
+	assert.Len(t, result.TopCards, 2)
+	assert.Equal(t, "THE_FOOL", result.TopCards[0].Card)
+	assert.Equal(t, 3, result.TopCards[0].Count)
+	assert.Equal(t, ArcanaMajor, result.TopCards[0].Arcana)
+	// Ties are broken by most recent draw
+	assert.Equal(t, "THE_SUN", result.TopCards[1].Card)
+
+	assert.Equal(t, 4, result.Arcana.Major)
+	assert.Equal(t, 2, result.Arcana.Minor)
+	assert.Equal(t, 0.67, result.Arcana.MajorRatio)
+	assert.Equal(t, 0.33, result.Arcana.MinorRatio)
+
+	// Weeks of 2026-02-23, 2026-03-02 and 2026-03-09
+	assert.Equal(t, 2.0, result.ReadingsPerWeek)
+	assert.Equal(t, []WeeklyReadings{
+		{WeekStart: time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), Count: 1},
+		{WeekStart: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Count: 1},
+		{WeekStart: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), Count: 4},
+	}, result.RecentWeeks)
+
+	assert.Equal(t, Streaks{CurrentDays: 3, LongestDays: 3}, result.Streaks)
+	assert.Equal(t, readings[0].ReadAt, *result.FirstReadingAt)
+	assert.Equal(t, readings[5].ReadAt, *result.LastReadingAt)
+}
+
+func TestBuildUserInsights_BrokenStreak(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	readings := []CardReading{
+		buildTestReading("THE_MOON", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("THE_MOON", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("THE_MOON", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)),
+		buildTestReading("THE_STAR", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)),
+	}
+
+	// Act
+	result := BuildUserInsights(readings, now, DefaultTopCards, DefaultRecentWeeks)
+
+	// Assert
+	assert.Equal(t, Streaks{CurrentDays: 0, LongestDays: 3}, result.Streaks)
+}
+
+func TestWatermark_Equal(t *testing.T) {
+	latest := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	later := latest.Add(time.Second)
+
+	assert.True(t, Watermark{}.Equal(Watermark{}))
+	assert.True(t, Watermark{MessageCount: 2, LatestMessageAt: &latest}.Equal(Watermark{MessageCount: 2, LatestMessageAt: &latest}))
+	assert.False(t, Watermark{MessageCount: 2, LatestMessageAt: &latest}.Equal(Watermark{MessageCount: 2, LatestMessageAt: &later}))
+	assert.False(t, Watermark{MessageCount: 2, LatestMessageAt: &latest}.Equal(Watermark{MessageCount: 3, LatestMessageAt: &latest}))
+	assert.False(t, Watermark{MessageCount: 0}.Equal(Watermark{MessageCount: 0, LatestMessageAt: &latest}))
+}
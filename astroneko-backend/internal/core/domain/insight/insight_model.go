@@ -0,0 +1,97 @@
+package insight
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Arcana represents the tarot arcana a card belongs to
+type Arcana string
+
+const (
+	ArcanaMajor   Arcana = "major"
+	ArcanaMinor   Arcana = "minor"
+	ArcanaUnknown Arcana = "unknown"
+)
+
+// CardReading is a single card drawn for a user, extracted from message history
+type CardReading struct {
+	Card   string
+	ReadAt time.Time
+}
+
+// Watermark summarizes the state of a user's message history.
+// Cached insights are only valid while the watermark is unchanged.
+type Watermark struct {
+	MessageCount    int64      `json:"message_count"`
+	LatestMessageAt *time.Time `json:"latest_message_at"`
+}
+
+// Equal reports whether two watermarks describe the same history state
+func (w Watermark) Equal(other Watermark) bool {
+	if w.MessageCount != other.MessageCount {
+		return false
+	}
+	if w.LatestMessageAt == nil || other.LatestMessageAt == nil {
+		return w.LatestMessageAt == nil && other.LatestMessageAt == nil
+	}
+	return w.LatestMessageAt.Equal(*other.LatestMessageAt)
+}
+
+// majorArcana lists the 22 major arcana using the same naming as the card assets
+var majorArcana = map[string]bool{
+	"THE_FOOL":             true,
+	"THE_MAGICIAN":         true,
+	"THE_HIGH_PRIESTESS":   true,
+	"THE_EMPRESS":          true,
+	"THE_EMPEROR":          true,
+	"THE_HIEROPHANT":       true,
+	"THE_LOVERS":           true,
+	"THE_CHARIOT":          true,
+	"THE_STRENGTH":         true,
+	"THE_HERMIT":           true,
+	"THE_WHEEL_OF_FORTUNE": true,
+	"THE_JUSTICE":          true,
+	"THE_HANGED_MAN":       true,
+	"THE_DEATH":            true,
+	"THE_TEMPERANCE":       true,
+	"THE_DEVIL":            true,
+	"THE_TOWER":            true,
+	"THE_STAR":             true,
+	"THE_MOON":             true,
+	"THE_SUN":              true,
+	"THE_JUDGMENT":         true,
+	"THE_WORLD":            true,
+}
+
+var (
+	minorArcanaRegex   = regexp.MustCompile(`^(ACE|TWO|THREE|FOUR|FIVE|SIX|SEVEN|EIGHT|NINE|TEN|PAGE|KNIGHT|QUEEN|KING)_OF_(CUPS|WANDS|SWORDS|PENTACLES)$`)
+	cardSeparatorRegex = regexp.MustCompile(`[\s\-]+`)
+)
+
+// NormalizeCardName converts card names such as "The Fool" or "ace-of-cups"
+// into the canonical asset form (THE_FOOL, ACE_OF_CUPS)
+func NormalizeCardName(card string) string {
+	normalized := strings.ToUpper(strings.TrimSpace(card))
+	normalized = cardSeparatorRegex.ReplaceAllString(normalized, "_")
+	normalized = strings.ReplaceAll(normalized, "JUDGEMENT", "JUDGMENT")
+
+	// Major arcana are sometimes returned without the leading article
+	if !strings.HasPrefix(normalized, "THE_") && majorArcana["THE_"+normalized] {
+		normalized = "THE_" + normalized
+	}
+
+	return normalized
+}
+
+// ClassifyCard returns the arcana of a normalized card name
+func ClassifyCard(card string) Arcana {
+	if majorArcana[card] {
+		return ArcanaMajor
+	}
+	if minorArcanaRegex.MatchString(card) {
+		return ArcanaMinor
+	}
+	return ArcanaUnknown
+}
@@ -0,0 +1,46 @@
+package insight
+
+import "time"
+
+// CardFrequency represents how often a card has been drawn
+type CardFrequency struct {
+	Card       string    `json:"card"`
+	Arcana     Arcana    `json:"arcana"`
+	Count      int       `json:"count"`
+	LastDrawAt time.Time `json:"last_draw_at"`
+}
+
+// ArcanaRatio represents the major vs minor arcana split of all draws
+type ArcanaRatio struct {
+	Major      int     `json:"major"`
+	Minor      int     `json:"minor"`
+	Unknown    int     `json:"unknown"`
+	MajorRatio float64 `json:"major_ratio"`
+	MinorRatio float64 `json:"minor_ratio"`
+}
+
+// WeeklyReadings represents the number of readings in an ISO week starting on Monday (UTC)
+type WeeklyReadings struct {
+	WeekStart time.Time `json:"week_start"`
+	Count     int       `json:"count"`
+}
+
+// Streaks represents consecutive days (UTC) with at least one reading
+type Streaks struct {
+	CurrentDays int `json:"current_days"`
+	LongestDays int `json:"longest_days"`
+}
+
+// UserInsightsResponse represents the aggregated reading statistics of a user
+type UserInsightsResponse struct {
+	TotalReadings   int              `json:"total_readings"`
+	DistinctCards   int              `json:"distinct_cards"`
+	TopCards        []CardFrequency  `json:"top_cards"`
+	Arcana          ArcanaRatio      `json:"arcana"`
+	ReadingsPerWeek float64          `json:"readings_per_week"`
+	RecentWeeks     []WeeklyReadings `json:"recent_weeks"`
+	Streaks         Streaks          `json:"streaks"`
+	FirstReadingAt  *time.Time       `json:"first_reading_at"`
+	LastReadingAt   *time.Time       `json:"last_reading_at"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
@@ -0,0 +1,64 @@
+package numerology
+
+// thaiLetterValues is the Thai name numerology table (เลขศาสตร์) covering
+// consonants, vowels and tone marks. Characters not listed carry no value.
+var thaiLetterValues = map[rune]int{
+	// 1
+	'ก': 1, 'ด': 1, 'ถ': 1, 'ท': 1, 'ภ': 1, 'ฤ': 1, 'า': 1, 'ำ': 1, 'ุ': 1, '่': 1,
+	// 2
+	'ข': 2, 'ฃ': 2, 'ช': 2, 'ง': 2, 'บ': 2, 'ป': 2, 'เ': 2, 'แ': 2, 'ู': 2, '้': 2,
+	// 3
+	'ฆ': 3, 'ต': 3, 'ฑ': 3, 'ฒ': 3, '๋': 3,
+	// 4
+	'ค': 4, 'ฅ': 4, 'ธ': 4, 'ญ': 4, 'ร': 4, 'ษ': 4, 'ะ': 4, 'โ': 4, 'ั': 4, 'ิ': 4,
+	// 5
+	'ฉ': 5, 'ณ': 5, 'ฌ': 5, 'น': 5, 'ม': 5, 'ห': 5, 'ฎ': 5, 'ฮ': 5, 'ฬ': 5, 'ึ': 5,
+	// 6
+	'จ': 6, 'ล': 6, 'ว': 6, 'อ': 6, 'ใ': 6, 'ฦ': 6,
+	// 7
+	'ซ': 7, 'ศ': 7, 'ส': 7, 'ี': 7, 'ื': 7, '๊': 7,
+	// 8
+	'ย': 8, 'ผ': 8, 'ฝ': 8, 'พ': 8, 'ฟ': 8, '็': 8,
+	// 9
+	'ฏ': 9, 'ฐ': 9, 'ไ': 9, '์': 9,
+}
+
+// latinLetterValues is the Chaldean table used for names written in English
+var latinLetterValues = map[rune]int{
+	'A': 1, 'I': 1, 'J': 1, 'Q': 1, 'Y': 1,
+	'B': 2, 'K': 2, 'R': 2,
+	'C': 3, 'G': 3, 'L': 3, 'S': 3,
+	'D': 4, 'M': 4, 'T': 4,
+	'E': 5, 'H': 5, 'N': 5, 'X': 5,
+	'U': 6, 'V': 6, 'W': 6,
+	'O': 7, 'Z': 7,
+	'F': 8, 'P': 8,
+}
+
+// Planet is the ruling body of a root number in Thai numerology
+type Planet string
+
+// rootPlanets maps root numbers 1-9 to their ruling body
+var rootPlanets = [10]Planet{
+	1: "sun",
+	2: "moon",
+	3: "mars",
+	4: "mercury",
+	5: "jupiter",
+	6: "venus",
+	7: "saturn",
+	8: "rahu",
+	9: "ketu",
+}
+
+// LetterValue returns the numerology value of a character and whether it has one
+func LetterValue(letter rune) (int, bool) {
+	if value, ok := thaiLetterValues[letter]; ok {
+		return value, true
+	}
+	if letter >= 'a' && letter <= 'z' {
+		letter -= 'a' - 'A'
+	}
+	value, ok := latinLetterValues[letter]
+	return value, ok
+}
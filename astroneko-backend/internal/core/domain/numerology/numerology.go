@@ -0,0 +1,157 @@
+package numerology
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+// DateLayout is the birth date format accepted by the life path calculation
+const DateLayout = "2006-01-02"
+
+// Phone numbers are accepted with this many digits after normalization
+const (
+	minPhoneDigits = 6
+	maxPhoneDigits = 15
+)
+
+// Root reduces a positive number to a single digit by repeatedly summing its digits
+func Root(number int) int {
+	if number <= 0 {
+		return 0
+	}
+	return 1 + (number-1)%9
+}
+
+// PlanetOf returns the ruling body of a number's root
+func PlanetOf(number int) Planet {
+	return rootPlanets[Root(number)]
+}
+
+// sumDigits adds the decimal digits of a number
+func sumDigits(number int) int {
+	sum := 0
+	for ; number > 0; number /= 10 {
+		sum += number % 10
+	}
+	return sum
+}
+
+// reduceKeepingMasters sums digits until one digit remains, stopping at 11, 22 or 33
+func reduceKeepingMasters(number int) int {
+	for number > 9 && number != 11 && number != 22 && number != 33 {
+		number = sumDigits(number)
+	}
+	return number
+}
+
+// AnalyzeName sums the letter values of each word of a name and of the whole name
+func AnalyzeName(name string) (*NameAnalysis, error) {
+	name = strings.Join(strings.Fields(name), " ")
+
+	analysis := &NameAnalysis{Name: name}
+	for _, word := range strings.Fields(name) {
+		part := NamePart{Text: word}
+		for _, letter := range word {
+			value, ok := LetterValue(letter)
+			if !ok {
+				analysis.Ignored = append(analysis.Ignored, string(letter))
+				continue
+			}
+			part.Letters = append(part.Letters, Letter{Letter: string(letter), Value: value})
+			part.Sum += value
+		}
+		if part.Sum == 0 {
+			continue
+		}
+		part.Root = Root(part.Sum)
+		analysis.Parts = append(analysis.Parts, part)
+		analysis.Total += part.Sum
+	}
+
+	if analysis.Total == 0 {
+		return nil, fmt.Errorf("%w: name has no Thai or English letters", shared.ErrInvalidNumerologyInput)
+	}
+
+	analysis.Root = Root(analysis.Total)
+	analysis.Planet = PlanetOf(analysis.Total)
+	return analysis, nil
+}
+
+// AnalyzeLifePath computes the life path number of a birth date
+func AnalyzeLifePath(birthDate time.Time) *LifePathAnalysis {
+	digits := birthDate.Format("20060102")
+
+	digitSum := 0
+	for _, digit := range digits {
+		digitSum += int(digit - '0')
+	}
+
+	lifePath := reduceKeepingMasters(digitSum)
+	return &LifePathAnalysis{
+		BirthDate:      birthDate.Format(DateLayout),
+		DigitSum:       digitSum,
+		LifePath:       lifePath,
+		IsMasterNumber: lifePath > 9,
+		BirthDayNumber: reduceKeepingMasters(birthDate.Day()),
+		Planet:         PlanetOf(lifePath),
+	}
+}
+
+// NormalizePhone strips formatting from a phone number and rewrites the Thai
+// country code +66 to the leading zero used in domestic numbers
+func NormalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for _, char := range phone {
+		switch {
+		case char >= '0' && char <= '9':
+			digits.WriteRune(char)
+		case char >= '๐' && char <= '๙':
+			// Thai digits
+			digits.WriteRune('0' + (char - '๐'))
+		case char == '+' || char == '-' || char == ' ' || char == '(' || char == ')' || char == '.':
+		default:
+			return "", fmt.Errorf("%w: phone number contains %q", shared.ErrInvalidNumerologyInput, char)
+		}
+	}
+
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "66") && len(normalized) == 11 {
+		normalized = "0" + normalized[2:]
+	}
+	if len(normalized) < minPhoneDigits || len(normalized) > maxPhoneDigits {
+		return "", fmt.Errorf("%w: phone number must have %d to %d digits", shared.ErrInvalidNumerologyInput, minPhoneDigits, maxPhoneDigits)
+	}
+	return normalized, nil
+}
+
+// AnalyzePhone sums the digits of a phone number and lists its adjacent digit pairs
+func AnalyzePhone(phone string) (*PhoneAnalysis, error) {
+	normalized, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &PhoneAnalysis{Phone: normalized}
+	for _, digit := range normalized {
+		value := int(digit - '0')
+		analysis.Sum += value
+		analysis.DigitCounts[value]++
+	}
+	analysis.Root = Root(analysis.Sum)
+	analysis.Planet = PlanetOf(analysis.Sum)
+
+	// The leading zero is a dialling prefix and is not read as part of a pair
+	paired := strings.TrimPrefix(normalized, "0")
+	for index := 0; index+1 < len(paired); index++ {
+		analysis.Pairs = append(analysis.Pairs, DigitPair{
+			Position: index + 1,
+			Pair:     paired[index : index+2],
+			Sum:      int(paired[index]-'0') + int(paired[index+1]-'0'),
+		})
+	}
+
+	return analysis, nil
+}
@@ -0,0 +1,13 @@
+package numerology
+
+type AnalyzeNameRequest struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+type AnalyzeLifePathRequest struct {
+	BirthDate string `json:"birth_date" validate:"required,datetime=2006-01-02"`
+}
+
+type AnalyzePhoneRequest struct {
+	Phone string `json:"phone" validate:"required,max=32"`
+}
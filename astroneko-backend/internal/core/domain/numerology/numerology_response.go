@@ -0,0 +1,57 @@
+package numerology
+
+// Letter is one character of a name with its numerology value
+type Letter struct {
+	Letter string `json:"letter"`
+	Value  int    `json:"value"`
+}
+
+// NamePart is the sum of one word of a name, such as the first name or surname
+type NamePart struct {
+	Text    string   `json:"text"`
+	Sum     int      `json:"sum"`
+	Root    int      `json:"root"`
+	Letters []Letter `json:"letters"`
+}
+
+// NameAnalysis is the numerology of a full name
+type NameAnalysis struct {
+	Name   string     `json:"name"`
+	Parts  []NamePart `json:"parts"`
+	Total  int        `json:"total"`
+	Root   int        `json:"root"`
+	Planet Planet     `json:"planet"`
+	// Ignored lists characters that carry no value, such as punctuation
+	Ignored []string `json:"ignored,omitempty"`
+}
+
+// LifePathAnalysis is the numerology of a birth date
+type LifePathAnalysis struct {
+	BirthDate string `json:"birth_date"`
+	// DigitSum is the plain sum of every digit in YYYYMMDD
+	DigitSum int `json:"digit_sum"`
+	// LifePath keeps the master numbers 11, 22 and 33 unreduced
+	LifePath       int    `json:"life_path"`
+	IsMasterNumber bool   `json:"is_master_number"`
+	BirthDayNumber int    `json:"birth_day_number"`
+	Planet         Planet `json:"planet"`
+}
+
+// DigitPair is two adjacent digits of a phone number
+type DigitPair struct {
+	Position int    `json:"position"`
+	Pair     string `json:"pair"`
+	Sum      int    `json:"sum"`
+}
+
+// PhoneAnalysis is the numerology of a phone number
+type PhoneAnalysis struct {
+	Phone  string `json:"phone"`
+	Sum    int    `json:"sum"`
+	Root   int    `json:"root"`
+	Planet Planet `json:"planet"`
+	// Pairs are the adjacent digit pairs after the leading zero
+	Pairs []DigitPair `json:"pairs"`
+	// DigitCounts counts how often each digit 0-9 appears
+	DigitCounts [10]int `json:"digit_counts"`
+}
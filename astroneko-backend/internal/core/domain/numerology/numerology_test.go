@@ -0,0 +1,120 @@
+package numerology
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLetterValue_ThaiTableIsComplete(t *testing.T) {
+	// Every Thai consonant from ก to ฮ has a value
+	for letter := 'ก'; letter <= 'ฮ'; letter++ {
+		value, ok := LetterValue(letter)
+		assert.True(t, ok, string(letter))
+		assert.GreaterOrEqual(t, value, 1)
+		assert.LessOrEqual(t, value, 9)
+	}
+}
+
+func TestAnalyzeName_Thai(t *testing.T) {
+	// Act
+	analysis, err := AnalyzeName("  สมชาย   ใจดี ")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "สมชาย ใจดี", analysis.Name)
+	assert.Len(t, analysis.Parts, 2)
+	assert.Equal(t, 23, analysis.Parts[0].Sum)
+	assert.Equal(t, 5, analysis.Parts[0].Root)
+	assert.Equal(t, []Letter{{"ส", 7}, {"ม", 5}, {"ช", 2}, {"า", 1}, {"ย", 8}}, analysis.Parts[0].Letters)
+	assert.Equal(t, 20, analysis.Parts[1].Sum)
+	assert.Equal(t, 43, analysis.Total)
+	assert.Equal(t, 7, analysis.Root)
+	assert.Equal(t, Planet("saturn"), analysis.Planet)
+	assert.Empty(t, analysis.Ignored)
+}
+
+func TestAnalyzeName_EnglishIgnoresPunctuation(t *testing.T) {
+	// Act
+	analysis, err := AnalyzeName("john-doe")
+
+	// Assert
+	assert.NoError(t, err)
+	// J1 O7 H5 N5 D4 O7 E5
+	assert.Equal(t, 34, analysis.Total)
+	assert.Equal(t, 7, analysis.Root)
+	assert.Equal(t, []string{"-"}, analysis.Ignored)
+}
+
+func TestAnalyzeName_NoLetters(t *testing.T) {
+	_, err := AnalyzeName("1234 !!")
+	assert.True(t, errors.Is(err, shared.ErrInvalidNumerologyInput))
+}
+
+func TestAnalyzeLifePath(t *testing.T) {
+	tests := []struct {
+		birthDate string
+		digitSum  int
+		lifePath  int
+		master    bool
+		dayNumber int
+	}{
+		{"1990-08-15", 33, 33, true, 6},
+		{"1995-02-28", 36, 9, false, 1},
+		{"2000-01-29", 14, 5, false, 11},
+		{"1989-12-31", 34, 7, false, 4},
+	}
+
+	for _, tt := range tests {
+		birthDate, _ := time.Parse(DateLayout, tt.birthDate)
+
+		analysis := AnalyzeLifePath(birthDate)
+
+		assert.Equal(t, tt.birthDate, analysis.BirthDate)
+		assert.Equal(t, tt.digitSum, analysis.DigitSum, tt.birthDate)
+		assert.Equal(t, tt.lifePath, analysis.LifePath, tt.birthDate)
+		assert.Equal(t, tt.master, analysis.IsMasterNumber, tt.birthDate)
+		assert.Equal(t, tt.dayNumber, analysis.BirthDayNumber, tt.birthDate)
+	}
+}
+
+func TestAnalyzePhone(t *testing.T) {
+	// Act
+	analysis, err := AnalyzePhone("+66 81-234-5678")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "0812345678", analysis.Phone)
+	assert.Equal(t, 44, analysis.Sum)
+	assert.Equal(t, 8, analysis.Root)
+	assert.Equal(t, Planet("rahu"), analysis.Planet)
+	assert.Len(t, analysis.Pairs, 8)
+	assert.Equal(t, DigitPair{Position: 1, Pair: "81", Sum: 9}, analysis.Pairs[0])
+	assert.Equal(t, DigitPair{Position: 8, Pair: "78", Sum: 15}, analysis.Pairs[7])
+	assert.Equal(t, 2, analysis.DigitCounts[8])
+	assert.Equal(t, 0, analysis.DigitCounts[9])
+}
+
+func TestNormalizePhone(t *testing.T) {
+	normalized, err := NormalizePhone("๐๘๙ ๙๙๙ ๙๙๙๙")
+	assert.NoError(t, err)
+	assert.Equal(t, "0899999999", normalized)
+
+	_, err = NormalizePhone("08x-123")
+	assert.True(t, errors.Is(err, shared.ErrInvalidNumerologyInput))
+
+	_, err = NormalizePhone("123")
+	assert.True(t, errors.Is(err, shared.ErrInvalidNumerologyInput))
+}
+
+func TestRoot(t *testing.T) {
+	assert.Equal(t, 0, Root(0))
+	assert.Equal(t, 9, Root(9))
+	assert.Equal(t, 1, Root(10))
+	assert.Equal(t, 9, Root(99))
+	assert.Equal(t, 7, Root(43))
+}
@@ -0,0 +1,71 @@
+package person
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/google/uuid"
+)
+
+// Relationship describes who a saved person is to the user
+const (
+	RelationshipPartner = "partner"
+	RelationshipFriend  = "friend"
+	RelationshipFamily  = "family"
+	RelationshipOther   = "other"
+)
+
+// MaxPeoplePerUser caps how many people a user can save
+const MaxPeoplePerUser = 20
+
+// Person is a birth profile a user saves for someone else, such as a partner or friend
+type Person struct {
+	shared.NoDeletedModel
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;index:idx_people_user_id;not null"`
+	Name           string    `json:"name" gorm:"type:varchar(100);not null"`
+	Relationship   string    `json:"relationship" gorm:"type:varchar(20);not null"`
+	BirthDate      time.Time `json:"birth_date" gorm:"type:date;not null"`
+	BirthTime      *string   `json:"birth_time" gorm:"type:varchar(5)"`
+	BirthPlace     *string   `json:"birth_place"`
+	BirthLatitude  *float64  `json:"birth_latitude"`
+	BirthLongitude *float64  `json:"birth_longitude"`
+	BirthTimezone  *string   `json:"birth_timezone"`
+}
+
+func (Person) TableName() string {
+	return "astroneko_people"
+}
+
+// ToResponse converts a person to its API representation
+func (p *Person) ToResponse() PersonResponse {
+	return PersonResponse{
+		ID:             p.ID,
+		Name:           p.Name,
+		Relationship:   p.Relationship,
+		BirthDate:      p.BirthDate.Format("2006-01-02"),
+		BirthTime:      p.BirthTime,
+		BirthPlace:     p.BirthPlace,
+		BirthLatitude:  p.BirthLatitude,
+		BirthLongitude: p.BirthLongitude,
+		BirthTimezone:  p.BirthTimezone,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+// BirthData returns the person's birth profile for chart computation
+func (p *Person) BirthData() astrology.BirthData {
+	birth := astrology.BirthData{
+		Date:      p.BirthDate,
+		Time:      p.BirthTime,
+		Timezone:  astrology.DefaultTimezone,
+		Latitude:  p.BirthLatitude,
+		Longitude: p.BirthLongitude,
+	}
+	if p.BirthTimezone != nil {
+		birth.Timezone = *p.BirthTimezone
+	}
+	return birth
+}
@@ -0,0 +1,14 @@
+package person
+
+// SavePersonRequest creates or replaces a saved person's birth profile.
+// Birth time and coordinates are optional but required for rising sign and houses.
+type SavePersonRequest struct {
+	Name           string   `json:"name" validate:"required,max=100"`
+	Relationship   string   `json:"relationship" validate:"required,oneof=partner friend family other"`
+	BirthDate      string   `json:"birth_date" validate:"required,datetime=2006-01-02"`
+	BirthTime      *string  `json:"birth_time" validate:"omitempty,datetime=15:04"`
+	BirthPlace     *string  `json:"birth_place" validate:"omitempty,max=255"`
+	BirthLatitude  *float64 `json:"birth_latitude" validate:"required_with=BirthLongitude,omitempty,gte=-90,lte=90"`
+	BirthLongitude *float64 `json:"birth_longitude" validate:"required_with=BirthLatitude,omitempty,gte=-180,lte=180"`
+	BirthTimezone  *string  `json:"birth_timezone" validate:"omitempty,timezone"`
+}
@@ -0,0 +1,26 @@
+package person
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PersonResponse struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Relationship   string    `json:"relationship"`
+	BirthDate      string    `json:"birth_date"`
+	BirthTime      *string   `json:"birth_time,omitempty"`
+	BirthPlace     *string   `json:"birth_place,omitempty"`
+	BirthLatitude  *float64  `json:"birth_latitude,omitempty"`
+	BirthLongitude *float64  `json:"birth_longitude,omitempty"`
+	BirthTimezone  *string   `json:"birth_timezone,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ListPeopleResponse struct {
+	People []PersonResponse `json:"people"`
+	Total  int              `json:"total"`
+}
@@ -0,0 +1,24 @@
+package referral_code
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeCode is the form referral codes are stored and looked up in. NFKC turns
+// full-width and other compatibility characters that phone keyboards produce into their
+// plain forms, white space and invisible formatting characters picked up when copying a
+// code are dropped, and the result is case-folded and upper-cased.
+func NormalizeCode(code string) string {
+	code = norm.NFKC.String(code)
+	code = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, code)
+	return strings.ToUpper(cases.Fold().String(code))
+}
@@ -0,0 +1,35 @@
+package referral_code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "astroneko!", expected: "ASTRONEKO!"},
+		{input: "  Summer10 \n", expected: "SUMMER10"},
+		{input: "ABCD EFGH", expected: "ABCDEFGH"},
+		{input: "ＡＳＴＲＯ１０！", expected: "ASTRO10!"},
+		{input: "neko\u200bcode", expected: "NEKOCODE"},
+		{input: "straße", expected: "STRASSE"},
+		{input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeCode(tt.input))
+		})
+	}
+}
+
+func TestNormalizeCode_Idempotent(t *testing.T) {
+	for _, code := range []string{"ＡＳＴＲＯ１０！", "straße", "K7MPQ2XZB"} {
+		once := NormalizeCode(code)
+		assert.Equal(t, once, NormalizeCode(once))
+	}
+}
@@ -0,0 +1,245 @@
+package referral_code
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Charsets bulk generated codes can be drawn from
+var Charsets = map[string]string{
+	"alphanumeric": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	"letters":      "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"digits":       "0123456789",
+}
+
+// MaxImportRows caps the size of an import, which is written in a single transaction
+const MaxImportRows = 5000
+
+// GenerateReferralCodesRequest creates Count codes of the form Prefix + Length random
+// characters from Charset, all with the same campaign settings
+type GenerateReferralCodesRequest struct {
+	Count   int    `json:"count" validate:"required,min=1,max=1000"`
+	Prefix  string `json:"prefix" validate:"omitempty,max=32,alphanum"`
+	Charset string `json:"charset" validate:"omitempty,oneof=alphanumeric letters digits"`
+	Length  int    `json:"length" validate:"required,min=4,max=32"`
+	CodeSettings
+}
+
+// CharsetChars returns the characters of the requested charset, alphanumeric by default
+func (r *GenerateReferralCodesRequest) CharsetChars() string {
+	if chars, ok := Charsets[r.Charset]; ok {
+		return chars
+	}
+	return Charsets["alphanumeric"]
+}
+
+// Validate rejects requests whose code space is too small to draw Count codes without
+// running out of retries. Asking for at most a tenth of the space keeps collisions rare.
+func (r *GenerateReferralCodesRequest) Validate() error {
+	space := math.Pow(float64(len(r.CharsetChars())), float64(r.Length))
+	if float64(r.Count)*10 > space {
+		return fmt.Errorf("%d codes of length %d do not fit the charset; use a longer length", r.Count, r.Length)
+	}
+	return r.CodeSettings.Validate()
+}
+
+type GenerateReferralCodesResponse struct {
+	Codes []ReferralCodeResponse `json:"codes"`
+}
+
+// CSV columns shared by import and export. Export adds the read-only columns, which
+// import ignores, so an exported file can be edited and imported again.
+var (
+	csvSettingsColumns = []string{"referral_code", "starts_at", "ends_at", "max_redemptions", "campaign", "channel", "notes", "grant_type", "grant_tier", "bonus_quota"}
+	ExportCSVHeader    = append([]string{"id"}, append(csvSettingsColumns, "used_count", "created_at")...)
+)
+
+// ImportRow is one data row of an imported CSV. Line is the 1-based line in the file.
+type ImportRow struct {
+	Line int
+	CreateReferralCodeRequest
+}
+
+// RowError explains why a row of an imported CSV was rejected
+type RowError struct {
+	Line         int    `json:"line"`
+	ReferralCode string `json:"referral_code,omitempty"`
+	Error        string `json:"error"`
+}
+
+// ImportResult reports how many codes an import created, or why it created none
+type ImportResult struct {
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors"`
+}
+
+// ParseImportCSV reads the header and data rows of an import file. Rows with values that
+// cannot be parsed are reported as row errors; an unreadable file, a header without
+// referral_code or more than MaxImportRows rows fail the whole import.
+func ParseImportCSV(r io.Reader) ([]ImportRow, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["referral_code"]; !ok {
+		return nil, nil, errors.New("the header must include a referral_code column")
+	}
+
+	var rows []ImportRow
+	var rowErrors []RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows)+len(rowErrors) >= MaxImportRows {
+			return nil, nil, fmt.Errorf("the file has more than %d rows", MaxImportRows)
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, RowError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := ImportRow{Line: line}
+		row.ReferralCode = value("referral_code")
+		if err := parseSettings(&row.CodeSettings, value); err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, ReferralCode: row.ReferralCode, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseSettings(s *CodeSettings, value func(string) string) error {
+	var err error
+	if s.StartsAt, err = parseCSVTime("starts_at", value("starts_at")); err != nil {
+		return err
+	}
+	if s.EndsAt, err = parseCSVTime("ends_at", value("ends_at")); err != nil {
+		return err
+	}
+	if raw := value("max_redemptions"); raw != "" {
+		maxRedemptions, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("max_redemptions %q is not a number", raw)
+		}
+		s.MaxRedemptions = &maxRedemptions
+	}
+	if raw := value("bonus_quota"); raw != "" {
+		if s.BonusQuota, err = strconv.Atoi(raw); err != nil {
+			return fmt.Errorf("bonus_quota %q is not a number", raw)
+		}
+	}
+	s.Campaign = optionalString(value("campaign"))
+	s.Channel = optionalString(value("channel"))
+	s.Notes = optionalString(value("notes"))
+	s.GrantType = value("grant_type")
+	s.GrantTier = optionalString(value("grant_tier"))
+	return nil
+}
+
+// parseCSVTime accepts RFC 3339 timestamps and plain dates, which are read as UTC midnight
+func parseCSVTime(column, raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %q is not an RFC 3339 time or a YYYY-MM-DD date", column, raw)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// CodeUsage is a general referral code with the number of times it was redeemed
+type CodeUsage struct {
+	ReferralCode `gorm:"embedded"`
+	UsedCount    int64
+}
+
+// ExportCursor is the position after the last exported code, ordered by creation time and ID
+type ExportCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CSVRecord formats the code as a row under ExportCSVHeader
+func (u *CodeUsage) CSVRecord() []string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	maxRedemptions := ""
+	if u.MaxRedemptions != nil {
+		maxRedemptions = strconv.Itoa(*u.MaxRedemptions)
+	}
+	bonusQuota := ""
+	if u.BonusQuota > 0 {
+		bonusQuota = strconv.Itoa(u.BonusQuota)
+	}
+
+	return []string{
+		u.ID.String(),
+		u.ReferralCode.ReferralCode,
+		formatTime(u.StartsAt),
+		formatTime(u.EndsAt),
+		maxRedemptions,
+		deref(u.Campaign),
+		deref(u.Channel),
+		deref(u.Notes),
+		u.GrantType,
+		deref(u.GrantTier),
+		bonusQuota,
+		strconv.FormatInt(u.UsedCount, 10),
+		formatTime(&u.CreatedAt),
+	}
+}
@@ -0,0 +1,93 @@
+package referral_code
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	file := "\ufeffReferral_Code,campaign,max_redemptions,starts_at,grant_type,bonus_quota,used_count\n" +
+		"SUMMER1,songkran,100,2026-04-13,bonus_quota,20,3\n" +
+		"SUMMER2,,,2026-04-13T00:00:00+07:00,,,\n" +
+		"SUMMER3,,many,,,,\n" +
+		"SUMMER4,,,13/04/2026,,,\n"
+
+	rows, rowErrors, err := ParseImportCSV(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "SUMMER1", rows[0].ReferralCode)
+	assert.Equal(t, "songkran", *rows[0].Campaign)
+	assert.Equal(t, 100, *rows[0].MaxRedemptions)
+	assert.Equal(t, time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC), *rows[0].StartsAt)
+	assert.Equal(t, GrantBonusQuota, rows[0].GrantType)
+	assert.Equal(t, 20, rows[0].BonusQuota)
+
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Nil(t, rows[1].Campaign)
+	assert.Nil(t, rows[1].MaxRedemptions)
+	assert.True(t, rows[1].StartsAt.Equal(time.Date(2026, 4, 12, 17, 0, 0, 0, time.UTC)))
+
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, RowError{Line: 4, ReferralCode: "SUMMER3", Error: `max_redemptions "many" is not a number`}, rowErrors[0])
+	assert.Equal(t, 5, rowErrors[1].Line)
+	assert.Contains(t, rowErrors[1].Error, "starts_at")
+}
+
+func TestParseImportCSV_RejectsFile(t *testing.T) {
+	_, _, err := ParseImportCSV(strings.NewReader(""))
+	assert.Error(t, err)
+
+	_, _, err = ParseImportCSV(strings.NewReader("code,campaign\nA,b\n"))
+	assert.ErrorContains(t, err, "referral_code")
+
+	var tooMany strings.Builder
+	tooMany.WriteString("referral_code\n")
+	for range MaxImportRows + 1 {
+		tooMany.WriteString("CODE\n")
+	}
+	_, _, err = ParseImportCSV(strings.NewReader(tooMany.String()))
+	assert.ErrorContains(t, err, "more than")
+}
+
+func TestParseImportCSV_ReadsExport(t *testing.T) {
+	campaign := "songkran"
+	usage := &CodeUsage{
+		ReferralCode: ReferralCode{ReferralCode: "SUMMER1", Campaign: &campaign, GrantType: GrantUnlimited},
+		UsedCount:    12,
+	}
+	usage.ID = uuid.New()
+	usage.CreatedAt = time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC)
+
+	record := usage.CSVRecord()
+	require.Len(t, record, len(ExportCSVHeader))
+	assert.Equal(t, "12", record[len(record)-2])
+	assert.Equal(t, "2026-04-01T09:30:00Z", record[len(record)-1])
+
+	file := strings.Join(ExportCSVHeader, ",") + "\n" + strings.Join(record, ",") + "\n"
+	rows, rowErrors, err := ParseImportCSV(strings.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "SUMMER1", rows[0].ReferralCode)
+	assert.Equal(t, &campaign, rows[0].Campaign)
+	assert.Equal(t, GrantUnlimited, rows[0].GrantType)
+}
+
+func TestGenerateReferralCodesRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&GenerateReferralCodesRequest{Count: 1000, Length: 8}).Validate())
+	assert.NoError(t, (&GenerateReferralCodesRequest{Count: 100, Charset: "digits", Length: 4}).Validate())
+	assert.Error(t, (&GenerateReferralCodesRequest{Count: 1001, Charset: "digits", Length: 4}).Validate())
+
+	grantTier := "gold"
+	assert.Error(t, (&GenerateReferralCodesRequest{Count: 1, Length: 8, CodeSettings: CodeSettings{GrantType: GrantTier, GrantTier: &grantTier}}).Validate())
+	assert.Equal(t, Charsets["letters"], (&GenerateReferralCodesRequest{Charset: "letters"}).CharsetChars())
+	assert.Equal(t, Charsets["alphanumeric"], (&GenerateReferralCodesRequest{}).CharsetChars())
+}
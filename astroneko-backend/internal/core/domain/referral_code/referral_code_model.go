@@ -1,43 +1,197 @@
 package referral_code
 
 import (
+	"errors"
+	"time"
+
 	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
 
 	"github.com/google/uuid"
 )
 
+// Grant types decide what redeeming a general referral code gives the user
+const (
+	GrantUnlimited  = "unlimited"
+	GrantTier       = "tier"
+	GrantBonusQuota = "bonus_quota"
+)
+
+// Reasons a general referral code cannot be redeemed right now
+var (
+	ErrCodeNotStarted = errors.New("referral code is not active yet")
+	ErrCodeExpired    = errors.New("referral code has expired")
+	ErrCodeExhausted  = errors.New("referral code has reached its redemption limit")
+)
+
+// ErrCodeTaken means another general or user referral code already holds the code
+var ErrCodeTaken = errors.New("referral code is already taken")
+
+// ErrCodeSpaceExhausted means bulk generation kept drawing codes that were already taken
+var ErrCodeSpaceExhausted = errors.New("could not generate enough unique referral codes; use a longer length or another prefix")
+
+// ReferralCode is a general code that many users can redeem, usually tied to a
+// marketing campaign. A code without dates or a cap never expires.
 type ReferralCode struct {
 	shared.NoDeletedModel
-	ReferralCode string `json:"referral_code" gorm:"not null"`
+	ReferralCode   string     `json:"referral_code" gorm:"not null"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxRedemptions *int       `json:"max_redemptions"`
+	Campaign       *string    `json:"campaign" gorm:"type:varchar(100)"`
+	Channel        *string    `json:"channel" gorm:"type:varchar(50)"`
+	Notes          *string    `json:"notes" gorm:"type:text"`
+	GrantType      string     `json:"grant_type" gorm:"type:varchar(32);not null;default:unlimited"`
+	GrantTier      *string    `json:"grant_tier" gorm:"type:varchar(32)"`
+	BonusQuota     int        `json:"bonus_quota" gorm:"not null;default:0"`
 }
 
 func (ReferralCode) TableName() string {
 	return "astroneko_general_referral_codes"
 }
 
+// CheckRedeemable returns why the code cannot be redeemed at now after the given
+// number of redemptions, or nil
+func (r *ReferralCode) CheckRedeemable(now time.Time, redemptions int64) error {
+	if r.StartsAt != nil && now.Before(*r.StartsAt) {
+		return ErrCodeNotStarted
+	}
+	if r.EndsAt != nil && !now.Before(*r.EndsAt) {
+		return ErrCodeExpired
+	}
+	if r.MaxRedemptions != nil && redemptions >= int64(*r.MaxRedemptions) {
+		return ErrCodeExhausted
+	}
+	return nil
+}
+
+// ReasonMistyped is the activation reason for a user code whose check character does not match
+const ReasonMistyped = "mistyped"
+
+// UnavailableReason names a CheckRedeemable error for API clients
+func UnavailableReason(err error) string {
+	switch {
+	case errors.Is(err, ErrCodeNotStarted):
+		return "not_started"
+	case errors.Is(err, ErrCodeExpired):
+		return "expired"
+	case errors.Is(err, ErrCodeExhausted):
+		return "exhausted"
+	}
+	return ""
+}
+
+// Grant returns the tier a redeeming user moves to and the bonus requests they receive
+func (r *ReferralCode) Grant() (string, int) {
+	switch r.GrantType {
+	case GrantTier:
+		if r.GrantTier != nil {
+			return *r.GrantTier, 0
+		}
+	case GrantBonusQuota:
+		return user.TierFree, r.BonusQuota
+	}
+	return user.TierUnlimited, 0
+}
+
+// CodeSettings are the campaign fields shared by the create and update requests
+type CodeSettings struct {
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxRedemptions *int       `json:"max_redemptions" validate:"omitempty,min=1"`
+	Campaign       *string    `json:"campaign" validate:"omitempty,max=100"`
+	Channel        *string    `json:"channel" validate:"omitempty,max=50"`
+	Notes          *string    `json:"notes" validate:"omitempty,max=2000"`
+	// GrantType defaults to unlimited. A tier grant needs GrantTier, a bonus_quota grant needs BonusQuota.
+	GrantType  string  `json:"grant_type" validate:"omitempty,oneof=unlimited tier bonus_quota"`
+	GrantTier  *string `json:"grant_tier" validate:"omitempty,oneof=free plus unlimited"`
+	BonusQuota int     `json:"bonus_quota" validate:"omitempty,min=1,max=100000"`
+}
+
+// Validate checks the rules that span more than one field
+func (s *CodeSettings) Validate() error {
+	if s.StartsAt != nil && s.EndsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	switch s.GrantType {
+	case GrantTier:
+		if s.GrantTier == nil || !user.IsValidTier(*s.GrantTier) {
+			return errors.New("grant_tier is required for a tier grant")
+		}
+	case GrantBonusQuota:
+		if s.BonusQuota <= 0 {
+			return errors.New("bonus_quota is required for a bonus_quota grant")
+		}
+	}
+	return nil
+}
+
+// ApplyTo replaces the code's campaign fields with the settings
+func (s *CodeSettings) ApplyTo(code *ReferralCode) {
+	code.StartsAt = s.StartsAt
+	code.EndsAt = s.EndsAt
+	code.MaxRedemptions = s.MaxRedemptions
+	code.Campaign = s.Campaign
+	code.Channel = s.Channel
+	code.Notes = s.Notes
+	code.GrantType = s.GrantType
+	if code.GrantType == "" {
+		code.GrantType = GrantUnlimited
+	}
+	code.GrantTier = nil
+	code.BonusQuota = 0
+	switch code.GrantType {
+	case GrantTier:
+		code.GrantTier = s.GrantTier
+	case GrantBonusQuota:
+		code.BonusQuota = s.BonusQuota
+	}
+}
+
 type CreateReferralCodeRequest struct {
 	ReferralCode string `json:"referral_code" validate:"required"`
+	CodeSettings
 }
 
+// UpdateReferralCodeRequest replaces every setting of the code; omitted fields are cleared
 type UpdateReferralCodeRequest struct {
 	ReferralCode string `json:"referral_code" validate:"required"`
+	CodeSettings
 }
 
 type ReferralCodeResponse struct {
-	ID           uuid.UUID `json:"id"`
-	ReferralCode string    `json:"referral_code"`
-	UsedCount    int64     `json:"used_count"`
-	CreatedAt    string    `json:"created_at"`
-	UpdatedAt    string    `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	ReferralCode   string     `json:"referral_code"`
+	UsedCount      int64      `json:"used_count"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxRedemptions *int       `json:"max_redemptions"`
+	Campaign       *string    `json:"campaign"`
+	Channel        *string    `json:"channel"`
+	Notes          *string    `json:"notes"`
+	GrantType      string     `json:"grant_type"`
+	GrantTier      *string    `json:"grant_tier,omitempty"`
+	BonusQuota     int        `json:"bonus_quota,omitempty"`
+	CreatedAt      string     `json:"created_at"`
+	UpdatedAt      string     `json:"updated_at"`
 }
 
 func (r *ReferralCode) ToResponse(usedCount int64) *ReferralCodeResponse {
 	return &ReferralCodeResponse{
-		ID:           r.ID,
-		ReferralCode: r.ReferralCode,
-		UsedCount:    usedCount,
-		CreatedAt:    r.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:    r.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:             r.ID,
+		ReferralCode:   r.ReferralCode,
+		UsedCount:      usedCount,
+		StartsAt:       r.StartsAt,
+		EndsAt:         r.EndsAt,
+		MaxRedemptions: r.MaxRedemptions,
+		Campaign:       r.Campaign,
+		Channel:        r.Channel,
+		Notes:          r.Notes,
+		GrantType:      r.GrantType,
+		GrantTier:      r.GrantTier,
+		BonusQuota:     r.BonusQuota,
+		CreatedAt:      r.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      r.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
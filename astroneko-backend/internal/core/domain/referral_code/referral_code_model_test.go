@@ -0,0 +1,104 @@
+package referral_code
+
+import (
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferralCode_CheckRedeemable(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	starts := now.Add(-time.Hour)
+	ends := now.Add(time.Hour)
+	maxRedemptions := 3
+
+	tests := []struct {
+		name        string
+		code        ReferralCode
+		now         time.Time
+		redemptions int64
+		want        error
+	}{
+		{name: "no limits", code: ReferralCode{}, now: now, redemptions: 1000},
+		{name: "inside window", code: ReferralCode{StartsAt: &starts, EndsAt: &ends}, now: now},
+		{name: "before start", code: ReferralCode{StartsAt: &starts}, now: starts.Add(-time.Second), want: ErrCodeNotStarted},
+		{name: "at start", code: ReferralCode{StartsAt: &starts}, now: starts},
+		{name: "at end", code: ReferralCode{EndsAt: &ends}, now: ends, want: ErrCodeExpired},
+		{name: "under cap", code: ReferralCode{MaxRedemptions: &maxRedemptions}, now: now, redemptions: 2},
+		{name: "at cap", code: ReferralCode{MaxRedemptions: &maxRedemptions}, now: now, redemptions: 3, want: ErrCodeExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.code.CheckRedeemable(tt.now, tt.redemptions)
+
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestReferralCode_Grant(t *testing.T) {
+	plus := user.TierPlus
+
+	tests := []struct {
+		name      string
+		settings  CodeSettings
+		wantTier  string
+		wantBonus int
+	}{
+		{name: "default is unlimited", settings: CodeSettings{}, wantTier: user.TierUnlimited},
+		{name: "tier", settings: CodeSettings{GrantType: GrantTier, GrantTier: &plus}, wantTier: user.TierPlus},
+		{name: "bonus quota", settings: CodeSettings{GrantType: GrantBonusQuota, BonusQuota: 50}, wantTier: user.TierFree, wantBonus: 50},
+		{name: "bonus ignored for tier grant", settings: CodeSettings{GrantType: GrantTier, GrantTier: &plus, BonusQuota: 50}, wantTier: user.TierPlus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := &ReferralCode{}
+			tt.settings.ApplyTo(code)
+
+			tier, bonus := code.Grant()
+
+			assert.Equal(t, tt.wantTier, tier)
+			assert.Equal(t, tt.wantBonus, bonus)
+		})
+	}
+}
+
+func TestCodeSettings_Validate(t *testing.T) {
+	starts := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	ends := starts.Add(24 * time.Hour)
+	plus := user.TierPlus
+	unknown := "gold"
+
+	tests := []struct {
+		name     string
+		settings CodeSettings
+		wantErr  bool
+	}{
+		{name: "empty", settings: CodeSettings{}},
+		{name: "window", settings: CodeSettings{StartsAt: &starts, EndsAt: &ends}},
+		{name: "ends before starts", settings: CodeSettings{StartsAt: &ends, EndsAt: &starts}, wantErr: true},
+		{name: "ends at starts", settings: CodeSettings{StartsAt: &starts, EndsAt: &starts}, wantErr: true},
+		{name: "tier grant", settings: CodeSettings{GrantType: GrantTier, GrantTier: &plus}},
+		{name: "tier grant without tier", settings: CodeSettings{GrantType: GrantTier}, wantErr: true},
+		{name: "tier grant with unknown tier", settings: CodeSettings{GrantType: GrantTier, GrantTier: &unknown}, wantErr: true},
+		{name: "bonus grant", settings: CodeSettings{GrantType: GrantBonusQuota, BonusQuota: 10}},
+		{name: "bonus grant without quota", settings: CodeSettings{GrantType: GrantBonusQuota}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -19,9 +19,18 @@ func (ReferralLog) TableName() string {
 
 type ActivateReferralRequest struct {
 	ReferralCode string `json:"referral_code" validate:"required"`
+	// ClickID is the click_id of the deep link the user followed, if any, so the
+	// redemption is attributed to it
+	ClickID *uuid.UUID `json:"click_id"`
 }
 
 type ActivateReferralResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	// Reason is set when a general code exists but cannot be redeemed: not_started, expired or
+	// exhausted, or when a user code fails its check character: mistyped
+	Reason string `json:"reason,omitempty"`
+	// Tier and BonusQuota are what a successful redemption granted
+	Tier       string `json:"tier,omitempty"`
+	BonusQuota int    `json:"bonus_quota,omitempty"`
 }
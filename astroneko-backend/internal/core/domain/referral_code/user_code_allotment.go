@@ -0,0 +1,142 @@
+package referral_code
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/user"
+)
+
+// UserCodeAlphabet is the alphabet of user referral codes. It leaves out 0, O, 1, I and L,
+// which are easily confused when a code is read aloud or copied by hand. Its length is
+// prime, which the check character relies on.
+const UserCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// maxUserCodeLength keeps every position weight of the check character below the
+// alphabet size
+const maxUserCodeLength = len(UserCodeAlphabet) - 2
+
+// checkChar computes the check character of a code body as the position-weighted sum of
+// its characters modulo the alphabet size. Changing any one character or swapping two
+// adjacent ones changes the sum. It reports false when body has characters outside the
+// alphabet.
+func checkChar(body string) (byte, bool) {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		value := strings.IndexByte(UserCodeAlphabet, body[i])
+		if value < 0 {
+			return 0, false
+		}
+		sum += (i + 1) * value
+	}
+	return UserCodeAlphabet[sum%len(UserCodeAlphabet)], true
+}
+
+// WithCheckChar appends the check character to a code body drawn from UserCodeAlphabet
+func WithCheckChar(body string) string {
+	check, _ := checkChar(body)
+	return body + string(check)
+}
+
+// IsMistypedUserCode reports whether code is written in UserCodeAlphabet but its last
+// character does not match the rest, which means it was mistyped. Codes from before the
+// check character was added are not reported, since they are looked up first.
+func IsMistypedUserCode(code string) bool {
+	code = strings.ToUpper(code)
+	if len(code) < 2 || len(code) > maxUserCodeLength+1 {
+		return false
+	}
+	body := code[:len(code)-1]
+	check, ok := checkChar(body)
+	if !ok || strings.IndexByte(UserCodeAlphabet, code[len(code)-1]) < 0 {
+		return false
+	}
+	return check != code[len(code)-1]
+}
+
+// AllotmentPolicy decides how many user referral codes a user holds, when used-up codes
+// are replaced and how long unused codes stay valid
+type AllotmentPolicy struct {
+	// CodesPerTier is the allotment of each tier; tiers not listed get DefaultCodes
+	CodesPerTier map[string]int
+	DefaultCodes int
+	// TopUpAfter is how many of a user's codes must be redeemed or expired before they are
+	// replaced. With 0 codes are never replaced and a user only ever receives the allotment.
+	TopUpAfter int
+	// ExpiresIn is how long a new code stays valid; 0 never expires
+	ExpiresIn time.Duration
+	// Length is the number of random characters before the check character
+	Length int
+}
+
+// DefaultAllotmentPolicy gives every user five codes that never expire and are not replaced
+var DefaultAllotmentPolicy = AllotmentPolicy{
+	DefaultCodes: 5,
+	Length:       8,
+}
+
+// Validate rejects negative allotments and code lengths the check character cannot cover
+func (p AllotmentPolicy) Validate() error {
+	if p.DefaultCodes < 0 || p.TopUpAfter < 0 || p.ExpiresIn < 0 {
+		return errors.New("allotment settings must not be negative")
+	}
+	for tier, codes := range p.CodesPerTier {
+		if !user.IsValidTier(tier) {
+			return fmt.Errorf("unknown tier %q", tier)
+		}
+		if codes < 0 {
+			return fmt.Errorf("the allotment of tier %q must not be negative", tier)
+		}
+	}
+	if p.Length < 6 || p.Length > maxUserCodeLength {
+		return fmt.Errorf("code length must be between 6 and %d", maxUserCodeLength)
+	}
+	return nil
+}
+
+// CodesFor returns the allotment of a tier
+func (p AllotmentPolicy) CodesFor(tier string) int {
+	if codes, ok := p.CodesPerTier[tier]; ok {
+		return codes
+	}
+	return p.DefaultCodes
+}
+
+// CodesToIssue returns how many new codes a user of the tier who holds codes should be
+// issued at now. Without top-ups the user is issued codes until they have held the
+// allotment in total. With top-ups, codes that were redeemed or expired are replaced
+// once at least TopUpAfter of them are missing from the allotment. Vanity codes do not
+// count towards the allotment.
+func (p AllotmentPolicy) CodesToIssue(codes []*UserReferralCode, tier string, now time.Time) int {
+	held, usable := 0, 0
+	for _, code := range codes {
+		if code.IsVanity {
+			continue
+		}
+		held++
+		if code.IsUsable(now) {
+			usable++
+		}
+	}
+
+	allotment := p.CodesFor(tier)
+	if p.TopUpAfter == 0 {
+		return max(allotment-held, 0)
+	}
+	missing := allotment - usable
+	if missing <= 0 || (held > 0 && missing < p.TopUpAfter) {
+		return 0
+	}
+	return missing
+}
+
+// ExpiryFrom returns the expiry of a code issued at now, or nil when codes do not expire
+func (p AllotmentPolicy) ExpiryFrom(now time.Time) *time.Time {
+	if p.ExpiresIn == 0 {
+		return nil
+	}
+	expiresAt := now.Add(p.ExpiresIn)
+	return &expiresAt
+}
@@ -0,0 +1,105 @@
+package referral_code
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCheckChar_CatchesTypos(t *testing.T) {
+	code := WithCheckChar("K7MPQ2XZ")
+	assert.Len(t, code, 9)
+	assert.False(t, IsMistypedUserCode(code))
+	assert.False(t, IsMistypedUserCode(strings.ToLower(code)))
+
+	for i := 0; i < len(code); i++ {
+		for j := 0; j < len(UserCodeAlphabet); j++ {
+			if UserCodeAlphabet[j] == code[i] {
+				continue
+			}
+			typo := code[:i] + string(UserCodeAlphabet[j]) + code[i+1:]
+			assert.True(t, IsMistypedUserCode(typo), "substitution %s", typo)
+		}
+	}
+	for i := 0; i+2 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		swapped := code[:i] + string(code[i+1]) + string(code[i]) + code[i+2:]
+		assert.True(t, IsMistypedUserCode(swapped), "transposition %s", swapped)
+	}
+}
+
+func TestIsMistypedUserCode_IgnoresOtherCodes(t *testing.T) {
+	// Characters outside the alphabet mean a general code or a code from before check characters
+	assert.False(t, IsMistypedUserCode("SUMMER10"))
+	assert.False(t, IsMistypedUserCode("A"))
+	assert.False(t, IsMistypedUserCode(""))
+}
+
+func buildAllotmentCodes(usable, activated, expired int, now time.Time) []*UserReferralCode {
+	past := now.Add(-time.Hour)
+	var codes []*UserReferralCode
+	for range usable {
+		codes = append(codes, &UserReferralCode{})
+	}
+	for range activated {
+		codes = append(codes, &UserReferralCode{IsActivated: true})
+	}
+	for range expired {
+		codes = append(codes, &UserReferralCode{ExpiresAt: &past})
+	}
+	return codes
+}
+
+func TestAllotmentPolicy_CodesToIssue(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	topUp := AllotmentPolicy{CodesPerTier: map[string]int{user.TierPlus: 8}, DefaultCodes: 3, TopUpAfter: 2, Length: 8}
+
+	tests := []struct {
+		name   string
+		policy AllotmentPolicy
+		codes  []*UserReferralCode
+		tier   string
+		want   int
+	}{
+		{name: "new user gets the default allotment", policy: DefaultAllotmentPolicy, tier: user.TierFree, want: 5},
+		{name: "without top-ups used codes are not replaced", policy: DefaultAllotmentPolicy, codes: buildAllotmentCodes(2, 3, 0, now), tier: user.TierFree, want: 0},
+		{name: "without top-ups a partial allotment is completed", policy: DefaultAllotmentPolicy, codes: buildAllotmentCodes(2, 1, 0, now), tier: user.TierFree, want: 2},
+		{name: "tier allotment", policy: topUp, tier: user.TierPlus, want: 8},
+		{name: "one spent code is not enough for a top-up", policy: topUp, codes: buildAllotmentCodes(2, 1, 0, now), tier: user.TierFree, want: 0},
+		{name: "redeemed and expired codes are topped up together", policy: topUp, codes: buildAllotmentCodes(1, 1, 1, now), tier: user.TierFree, want: 2},
+		{name: "a tier upgrade counts as missing codes", policy: topUp, codes: buildAllotmentCodes(3, 0, 0, now), tier: user.TierPlus, want: 5},
+		{name: "a vanity code is not part of the allotment", policy: DefaultAllotmentPolicy, codes: []*UserReferralCode{{IsVanity: true}}, tier: user.TierFree, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.CodesToIssue(tt.codes, tt.tier, now))
+		})
+	}
+}
+
+func TestAllotmentPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultAllotmentPolicy.Validate())
+	assert.Error(t, AllotmentPolicy{DefaultCodes: -1, Length: 8}.Validate())
+	assert.Error(t, AllotmentPolicy{CodesPerTier: map[string]int{"gold": 3}, Length: 8}.Validate())
+	assert.Error(t, AllotmentPolicy{DefaultCodes: 5, Length: 4}.Validate())
+	assert.Error(t, AllotmentPolicy{DefaultCodes: 5, Length: 30}.Validate())
+}
+
+func TestAllotmentPolicy_ExpiryFrom(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, DefaultAllotmentPolicy.ExpiryFrom(now))
+
+	expiresAt := AllotmentPolicy{ExpiresIn: 30 * 24 * time.Hour}.ExpiryFrom(now)
+	assert.Equal(t, now.AddDate(0, 0, 30), *expiresAt)
+
+	code := &UserReferralCode{ExpiresAt: expiresAt}
+	assert.True(t, code.IsUsable(expiresAt.Add(-time.Second)))
+	assert.False(t, code.IsUsable(*expiresAt))
+}
@@ -1,6 +1,8 @@
 package referral_code
 
 import (
+	"time"
+
 	"astroneko-backend/internal/core/domain/shared"
 
 	"github.com/google/uuid"
@@ -11,16 +13,35 @@ type UserReferralCode struct {
 	UserID       uuid.UUID `json:"user_id" gorm:"not null;type:uuid"`
 	ReferralCode string    `json:"referral_code" gorm:"not null"`
 	IsActivated  bool      `json:"is_activated" gorm:"default:false;not null"`
+	// ExpiresAt is when an unused code stops working; nil codes never expire
+	ExpiresAt *time.Time `json:"expires_at"`
+	// IsVanity marks a code the user chose. It can be redeemed any number of times and is
+	// not part of the allotment.
+	IsVanity bool `json:"is_vanity" gorm:"default:false;not null"`
 }
 
 func (UserReferralCode) TableName() string {
 	return "astroneko_user_referral_codes"
 }
 
+// IsExpired reports whether the code's expiry has passed at now
+func (u *UserReferralCode) IsExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
+}
+
+// IsUsable reports whether the code can still be redeemed at now
+func (u *UserReferralCode) IsUsable(now time.Time) bool {
+	return !u.IsActivated && !u.IsExpired(now)
+}
+
 type UserReferralCodeResponse struct {
-	ID           uuid.UUID `json:"id"`
-	ReferralCode string    `json:"referral_code"`
-	IsActivated  bool      `json:"is_activated"`
+	ID           uuid.UUID  `json:"id"`
+	ReferralCode string     `json:"referral_code"`
+	IsActivated  bool       `json:"is_activated"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	IsVanity     bool       `json:"is_vanity"`
+	// Link is the code's deep link, empty when links are not configured
+	Link string `json:"link,omitempty"`
 }
 
 func (u *UserReferralCode) ToResponse() *UserReferralCodeResponse {
@@ -28,6 +49,8 @@ func (u *UserReferralCode) ToResponse() *UserReferralCodeResponse {
 		ID:           u.ID,
 		ReferralCode: u.ReferralCode,
 		IsActivated:  u.IsActivated,
+		ExpiresAt:    u.ExpiresAt,
+		IsVanity:     u.IsVanity,
 	}
 }
 
@@ -0,0 +1,94 @@
+package referral_code
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reasons a vanity code claim is refused
+var (
+	ErrVanityNotEligible    = errors.New("user is not eligible for a vanity referral code")
+	ErrVanityAlreadyClaimed = errors.New("user already has a vanity referral code")
+	ErrVanityCodeTaken      = errors.New("referral code is already taken")
+	ErrVanityCodeRejected   = errors.New("referral code is not allowed")
+)
+
+// reservedCodes cannot be claimed as they are
+var reservedCodes = []string{"CRM", "NULL", "ROOT", "SYSTEM", "TEST", "UNDEFINED"}
+
+// blockedWords cannot appear anywhere in a vanity code: words that would pass a user off
+// as the service or its staff, and profanity and slurs in English and romanized Thai.
+// Words that are commonly part of harmless ones are left out.
+var blockedWords = []string{
+	"ADMIN", "ASTRONEKO", "HELPDESK", "MODERATOR", "OFFICIAL", "STAFF", "SUPPORT",
+	"ASSHOLE", "BITCH", "CUNT", "FAGGOT", "FUCK", "NAZI", "NIGGA", "NIGGER",
+	"PORN", "PUSSY", "RETARD", "SHIT", "SLUT", "WHORE", "KHUAY", "KUAY",
+}
+
+// lookalikes undoes the digit and symbol swaps used to slip words past a filter
+var lookalikes = strings.NewReplacer("0", "O", "1", "I", "3", "E", "4", "A", "5", "S", "7", "T", "8", "B", "@", "A", "$", "S")
+
+// VanityPolicy decides who can claim a vanity code and what it may look like
+type VanityPolicy struct {
+	// MinInvites is how many redemptions of their codes a user needs before claiming one
+	MinInvites int
+	MinLength  int
+	MaxLength  int
+	// ExtraBlockedWords are added to the built-in blocked words
+	ExtraBlockedWords []string
+}
+
+// DefaultVanityPolicy applies when the referral.vanity config section is missing
+var DefaultVanityPolicy = VanityPolicy{
+	MinInvites: 3,
+	MinLength:  4,
+	MaxLength:  16,
+}
+
+// Validate rejects lengths no code could satisfy
+func (p VanityPolicy) Validate() error {
+	if p.MinInvites < 0 {
+		return errors.New("min_invites must not be negative")
+	}
+	if p.MinLength < 3 || p.MaxLength < p.MinLength || p.MaxLength > 32 {
+		return errors.New("vanity code lengths must satisfy 3 <= min_length <= max_length <= 32")
+	}
+	return nil
+}
+
+// CheckCode returns why a normalized vanity code may not be claimed, or nil. Codes are
+// ASCII letters and digits only, so anyone can type them, and must not contain a blocked
+// word, including one spelled with lookalike digits.
+func (p VanityPolicy) CheckCode(code string) error {
+	if len(code) < p.MinLength || len(code) > p.MaxLength {
+		return fmt.Errorf("%w: use %d to %d characters", ErrVanityCodeRejected, p.MinLength, p.MaxLength)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("%w: use only letters A-Z and digits", ErrVanityCodeRejected)
+		}
+	}
+
+	for _, reserved := range reservedCodes {
+		if code == reserved {
+			return fmt.Errorf("%w: it is reserved", ErrVanityCodeRejected)
+		}
+	}
+
+	spelled := lookalikes.Replace(code)
+	for _, words := range [][]string{blockedWords, p.ExtraBlockedWords} {
+		for _, word := range words {
+			word = NormalizeCode(word)
+			if word != "" && (strings.Contains(code, word) || strings.Contains(spelled, word)) {
+				return fmt.Errorf("%w: it contains a blocked word", ErrVanityCodeRejected)
+			}
+		}
+	}
+	return nil
+}
+
+// ClaimVanityCodeRequest asks for a personalized user referral code
+type ClaimVanityCodeRequest struct {
+	ReferralCode string `json:"referral_code" validate:"required,max=64"`
+}
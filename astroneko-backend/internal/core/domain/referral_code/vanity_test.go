@@ -0,0 +1,47 @@
+package referral_code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVanityPolicy_CheckCode(t *testing.T) {
+	policy := VanityPolicy{MinLength: 4, MaxLength: 12, ExtraBlockedWords: []string{"rival"}}
+
+	tests := []struct {
+		code    string
+		allowed bool
+	}{
+		{code: "NEKOLOVER", allowed: true},
+		{code: "MOON2026", allowed: true},
+		{code: "ABC", allowed: false},
+		{code: "THIRTEENCHARS", allowed: false},
+		{code: "NEKO-LOVER", allowed: false},
+		{code: "ADMIN", allowed: false},
+		{code: "TEST", allowed: false},
+		{code: "TESTER", allowed: true},
+		{code: "SH1TCODE", allowed: false},
+		{code: "4STRONEKO", allowed: false},
+		{code: "RIVALFAN", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := policy.CheckCode(tt.code)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrVanityCodeRejected)
+			}
+		})
+	}
+}
+
+func TestVanityPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultVanityPolicy.Validate())
+	assert.Error(t, VanityPolicy{MinInvites: -1, MinLength: 4, MaxLength: 16}.Validate())
+	assert.Error(t, VanityPolicy{MinLength: 2, MaxLength: 16}.Validate())
+	assert.Error(t, VanityPolicy{MinLength: 8, MaxLength: 6}.Validate())
+	assert.Error(t, VanityPolicy{MinLength: 4, MaxLength: 40}.Validate())
+}
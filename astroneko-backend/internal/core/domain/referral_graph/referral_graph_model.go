@@ -0,0 +1,118 @@
+package referral_graph
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ActiveWindow is how recently an invitee must have logged in to count as an active user
+const ActiveWindow = 30 * 24 * time.Hour
+
+// MaxTreeDepth bounds how many invite levels are followed below a user
+const MaxTreeDepth = 10
+
+// Edge is one "inviter invited invitee" link. It joins a redemption of a user referral
+// code in astroneko_referral_logs with the owner of that code.
+type Edge struct {
+	InviterID            uuid.UUID  `json:"inviter_id"`
+	InviteeID            uuid.UUID  `json:"invitee_id"`
+	RedeemedAt           time.Time  `json:"redeemed_at"`
+	InviteeEmail         *string    `json:"invitee_email"`
+	InviteeDisplayName   *string    `json:"invitee_display_name"`
+	InviteeLatestLoginAt *time.Time `json:"invitee_latest_login_at"`
+}
+
+// IsActive reports whether the invitee logged in within ActiveWindow before now
+func (e *Edge) IsActive(now time.Time) bool {
+	return e.InviteeLatestLoginAt != nil && !e.InviteeLatestLoginAt.Before(now.Add(-ActiveWindow))
+}
+
+// LeaderboardFilter narrows the leaderboard to invites made in [From, To). Nil bounds are open.
+type LeaderboardFilter struct {
+	From        *time.Time
+	To          *time.Time
+	ActiveSince time.Time
+	Limit       int
+	Offset      int
+}
+
+// LeaderboardRow is one inviter's direct invite counts as aggregated by the repository
+type LeaderboardRow struct {
+	InviterID     uuid.UUID
+	InviterEmail  *string
+	Invites       int64
+	ActiveInvites int64
+	FirstInviteAt time.Time
+	LastInviteAt  time.Time
+}
+
+// Downline summarizes everyone below a user in the invite tree
+type Downline struct {
+	Size  int
+	Depth int
+}
+
+// ConversionRate is the share of invitees that are active, rounded to four decimals
+func ConversionRate(active, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(active*10000/total) / 10000
+}
+
+// AnonymizeInvitee shows only the first letter of an invitee's display name, or of the
+// email when there is no name, so a user can recognize friends without learning who they are
+func AnonymizeInvitee(displayName, email *string) string {
+	for _, source := range []*string{displayName, email} {
+		if source == nil {
+			continue
+		}
+		value := strings.TrimSpace(*source)
+		if value == "" {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(value)
+		return string(unicode.ToUpper(first)) + "***"
+	}
+	return "Anonymous"
+}
+
+// Children groups edges by inviter
+type Children map[uuid.UUID][]Edge
+
+// Add records the edges under their inviters
+func (c Children) Add(edges []Edge) {
+	for _, edge := range edges {
+		c[edge.InviterID] = append(c[edge.InviterID], edge)
+	}
+}
+
+// Downline counts the users below root and how many levels deep the tree goes, up to
+// MaxTreeDepth. Each user is counted once even if the data holds a loop.
+func (c Children) Downline(root uuid.UUID) Downline {
+	var downline Downline
+	seen := map[uuid.UUID]bool{root: true}
+	level := []uuid.UUID{root}
+	for depth := 1; depth <= MaxTreeDepth && len(level) > 0; depth++ {
+		var next []uuid.UUID
+		for _, inviter := range level {
+			for _, edge := range c[inviter] {
+				if seen[edge.InviteeID] {
+					continue
+				}
+				seen[edge.InviteeID] = true
+				next = append(next, edge.InviteeID)
+			}
+		}
+		if len(next) > 0 {
+			downline.Size += len(next)
+			downline.Depth = depth
+		}
+		level = next
+	}
+	return downline
+}
@@ -0,0 +1,88 @@
+package referral_graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestAnonymizeInvitee(t *testing.T) {
+	tests := []struct {
+		name        string
+		displayName *string
+		email       *string
+		expected    string
+	}{
+		{name: "display name", displayName: stringPtr("nattapong"), email: stringPtr("x@example.com"), expected: "N***"},
+		{name: "thai display name", displayName: stringPtr("  สมชาย"), expected: "ส***"},
+		{name: "blank name falls back to email", displayName: stringPtr(" "), email: stringPtr("mali@example.com"), expected: "M***"},
+		{name: "nothing known", expected: "Anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AnonymizeInvitee(tt.displayName, tt.email))
+		})
+	}
+}
+
+func TestConversionRate(t *testing.T) {
+	assert.Equal(t, 0.0, ConversionRate(0, 0))
+	assert.Equal(t, 0.5, ConversionRate(2, 4))
+	assert.Equal(t, 0.3333, ConversionRate(1, 3))
+}
+
+func TestEdge_IsActive(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-ActiveWindow)
+	stale := recent.Add(-time.Second)
+
+	assert.True(t, (&Edge{InviteeLatestLoginAt: &recent}).IsActive(now))
+	assert.False(t, (&Edge{InviteeLatestLoginAt: &stale}).IsActive(now))
+	assert.False(t, (&Edge{}).IsActive(now))
+}
+
+func TestChildren_Downline(t *testing.T) {
+	root, a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	children := Children{}
+	children.Add([]Edge{
+		{InviterID: root, InviteeID: a},
+		{InviterID: root, InviteeID: b},
+		{InviterID: a, InviteeID: c},
+		{InviterID: c, InviteeID: d},
+	})
+
+	assert.Equal(t, Downline{Size: 4, Depth: 3}, children.Downline(root))
+	assert.Equal(t, Downline{Size: 2, Depth: 2}, children.Downline(a))
+	assert.Equal(t, Downline{}, children.Downline(b))
+}
+
+func TestChildren_Downline_IgnoresLoops(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	children := Children{}
+	children.Add([]Edge{
+		{InviterID: a, InviteeID: b},
+		{InviterID: b, InviteeID: a},
+	})
+
+	assert.Equal(t, Downline{Size: 1, Depth: 1}, children.Downline(a))
+}
+
+func TestChildren_Downline_StopsAtMaxDepth(t *testing.T) {
+	chain := make([]uuid.UUID, MaxTreeDepth+5)
+	for i := range chain {
+		chain[i] = uuid.New()
+	}
+	children := Children{}
+	for i := 1; i < len(chain); i++ {
+		children.Add([]Edge{{InviterID: chain[i-1], InviteeID: chain[i]}})
+	}
+
+	assert.Equal(t, Downline{Size: MaxTreeDepth, Depth: MaxTreeDepth}, children.Downline(chain[0]))
+}
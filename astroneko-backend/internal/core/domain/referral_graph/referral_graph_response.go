@@ -0,0 +1,47 @@
+package referral_graph
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteeResponse is one person the user invited, without anything that identifies them
+type InviteeResponse struct {
+	Name        string    `json:"name"`
+	JoinedAt    time.Time `json:"joined_at"`
+	IsActive    bool      `json:"is_active"`
+	InviteCount int       `json:"invite_count"`
+}
+
+// MyReferralsResponse is the user's own place in the referral graph
+type MyReferralsResponse struct {
+	InviteCount       int               `json:"invite_count"`
+	ActiveInviteCount int               `json:"active_invite_count"`
+	ConversionRate    float64           `json:"conversion_rate"`
+	DownlineCount     int               `json:"downline_count"`
+	TreeDepth         int               `json:"tree_depth"`
+	Invitees          []InviteeResponse `json:"invitees"`
+}
+
+// LeaderboardEntryResponse ranks one inviter. Invite counts follow the date filter,
+// the downline and tree depth cover all time.
+type LeaderboardEntryResponse struct {
+	Rank              int       `json:"rank"`
+	UserID            uuid.UUID `json:"user_id"`
+	Email             *string   `json:"email"`
+	InviteCount       int64     `json:"invite_count"`
+	ActiveInviteCount int64     `json:"active_invite_count"`
+	ConversionRate    float64   `json:"conversion_rate"`
+	DownlineCount     int       `json:"downline_count"`
+	TreeDepth         int       `json:"tree_depth"`
+	FirstInviteAt     time.Time `json:"first_invite_at"`
+	LastInviteAt      time.Time `json:"last_invite_at"`
+}
+
+type LeaderboardResponse struct {
+	Entries []LeaderboardEntryResponse `json:"entries"`
+	Total   int64                      `json:"total"`
+	Limit   int                        `json:"limit"`
+	Offset  int                        `json:"offset"`
+}
@@ -0,0 +1,240 @@
+package referral_link
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrLinksDisabled is returned when the referral.links config section is missing
+var ErrLinksDisabled = errors.New("referral links are not configured")
+
+// ErrCodeNotFound is returned for QR requests for a code that does not exist or is not the user's
+var ErrCodeNotFound = errors.New("referral code not found")
+
+// Code types a click can point at, matching astroneko_referral_logs.code_type
+const (
+	CodeTypeGeneral = "general"
+	CodeTypeUser    = "user"
+)
+
+// ClaimWindow is how long after a click a user can still claim it as theirs
+const ClaimWindow = 30 * 24 * time.Hour
+
+// maxUTMLength and maxHeaderLength cap what is stored from a visitor's request
+const (
+	maxUTMLength    = 255
+	maxHeaderLength = 512
+)
+
+// Click is one visit to a referral deep link. A click is claimed by the user who signed
+// up or logged in after following it, and attributed to the referral log of their
+// redemption of the same code, which gives the click -> signup -> activation funnel.
+type Click struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CodeType       string     `json:"code_type" gorm:"type:varchar(16);not null"`
+	ReferralCodeID uuid.UUID  `json:"referral_code_id" gorm:"type:uuid;not null"`
+	UTMSource      *string    `json:"utm_source" gorm:"type:varchar(255)"`
+	UTMMedium      *string    `json:"utm_medium" gorm:"type:varchar(255)"`
+	UTMCampaign    *string    `json:"utm_campaign" gorm:"type:varchar(255)"`
+	UTMTerm        *string    `json:"utm_term" gorm:"type:varchar(255)"`
+	UTMContent     *string    `json:"utm_content" gorm:"type:varchar(255)"`
+	Referrer       *string    `json:"referrer" gorm:"type:varchar(512)"`
+	UserAgent      *string    `json:"user_agent" gorm:"type:varchar(512)"`
+	UserID         *uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	// IsNewUser is set when the claiming user's account was created after the click
+	IsNewUser     bool       `json:"is_new_user" gorm:"not null;default:false"`
+	ClaimedAt     *time.Time `json:"claimed_at"`
+	ReferralLogID *uuid.UUID `json:"referral_log_id" gorm:"type:uuid"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (Click) TableName() string {
+	return "astroneko_referral_clicks"
+}
+
+// UTMParams are the campaign parameters of a link
+type UTMParams struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+}
+
+// UTMFromQuery reads the utm_* parameters with the given lookup, typically a request's query
+func UTMFromQuery(get func(key string) string) UTMParams {
+	return UTMParams{
+		Source:   get("utm_source"),
+		Medium:   get("utm_medium"),
+		Campaign: get("utm_campaign"),
+		Term:     get("utm_term"),
+		Content:  get("utm_content"),
+	}
+}
+
+// Encode adds the non-empty parameters to values
+func (p UTMParams) Encode(values url.Values) {
+	for key, value := range map[string]string{
+		"utm_source":   p.Source,
+		"utm_medium":   p.Medium,
+		"utm_campaign": p.Campaign,
+		"utm_term":     p.Term,
+		"utm_content":  p.Content,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+}
+
+// NewClick records a visit to the code's link. Values are trimmed to what the table holds.
+func NewClick(codeType string, referralCodeID uuid.UUID, utm UTMParams, referrer, userAgent string) *Click {
+	return &Click{
+		CodeType:       codeType,
+		ReferralCodeID: referralCodeID,
+		UTMSource:      truncated(utm.Source, maxUTMLength),
+		UTMMedium:      truncated(utm.Medium, maxUTMLength),
+		UTMCampaign:    truncated(utm.Campaign, maxUTMLength),
+		UTMTerm:        truncated(utm.Term, maxUTMLength),
+		UTMContent:     truncated(utm.Content, maxUTMLength),
+		Referrer:       truncated(referrer, maxHeaderLength),
+		UserAgent:      truncated(userAgent, maxHeaderLength),
+	}
+}
+
+func truncated(value string, limit int) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if runes := []rune(value); len(runes) > limit {
+		value = string(runes[:limit])
+	}
+	return &value
+}
+
+// LinkPolicy builds deep links. BaseURL is where this API's /r/{code} redirect is reachable
+// from the internet; LandingURL is the page the redirect sends visitors to.
+type LinkPolicy struct {
+	BaseURL    string
+	LandingURL string
+}
+
+// Enabled reports whether deep links are configured
+func (p LinkPolicy) Enabled() bool {
+	return p.BaseURL != "" && p.LandingURL != ""
+}
+
+// Validate accepts an empty policy, which disables links, or two absolute http(s) URLs
+func (p LinkPolicy) Validate() error {
+	if p.BaseURL == "" && p.LandingURL == "" {
+		return nil
+	}
+	for _, setting := range []struct{ name, value string }{{"base_url", p.BaseURL}, {"landing_url", p.LandingURL}} {
+		parsed, err := url.Parse(setting.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an absolute http or https URL", setting.name)
+		}
+	}
+	return nil
+}
+
+// DeepLink is the short link for the code, carrying the given UTM parameters. It is empty
+// when links are disabled.
+func (p LinkPolicy) DeepLink(code string, utm UTMParams) string {
+	if !p.Enabled() {
+		return ""
+	}
+	link := strings.TrimRight(p.BaseURL, "/") + "/r/" + url.PathEscape(code)
+	values := url.Values{}
+	utm.Encode(values)
+	if len(values) > 0 {
+		link += "?" + values.Encode()
+	}
+	return link
+}
+
+// LandingLink is where a visitor of the code's deep link is sent. The app reads ref to
+// prefill the code and hands click_id back when the visitor signs up and redeems. The
+// click ID is left out when the click was not recorded.
+func (p LinkPolicy) LandingLink(code string, clickID *uuid.UUID, utm UTMParams) string {
+	landing, err := url.Parse(p.LandingURL)
+	if err != nil {
+		return p.LandingURL
+	}
+	values := landing.Query()
+	if code != "" {
+		values.Set("ref", code)
+	}
+	if clickID != nil {
+		values.Set("click_id", clickID.String())
+	}
+	utm.Encode(values)
+	landing.RawQuery = values.Encode()
+	return landing.String()
+}
+
+// QR code output formats
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// QR code size bounds in pixels, for PNG output and the SVG's width and height
+const (
+	DefaultQRSize = 256
+	MinQRSize     = 128
+	MaxQRSize     = 1024
+)
+
+// QRCode is a rendered QR code of a deep link
+type QRCode struct {
+	ContentType string
+	Image       []byte
+}
+
+// FunnelFilter narrows the funnel to clicks in [From, To) and to the given code type and
+// UTM source and campaign. Empty values match everything.
+type FunnelFilter struct {
+	CodeType    string
+	UTMSource   string
+	UTMCampaign string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+// FunnelCounts are the click, signup and activation counts of a set of clicks
+type FunnelCounts struct {
+	Clicks      int64 `json:"clicks"`
+	Signups     int64 `json:"signups"`
+	Activations int64 `json:"activations"`
+}
+
+// FunnelRow is one code's counts as aggregated by the repository
+type FunnelRow struct {
+	CodeType       string
+	ReferralCodeID uuid.UUID
+	ReferralCode   *string
+	FunnelCounts
+}
+
+// FunnelTotals sums the counts over every code matching the filter
+type FunnelTotals struct {
+	Codes int64
+	FunnelCounts
+}
+
+// Rate is the share of part in total, rounded to four decimals
+func Rate(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part*10000/total) / 10000
+}
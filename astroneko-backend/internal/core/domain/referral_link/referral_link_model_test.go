@@ -0,0 +1,57 @@
+package referral_link
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkPolicy_Validate(t *testing.T) {
+	assert.NoError(t, LinkPolicy{}.Validate())
+	assert.NoError(t, LinkPolicy{BaseURL: "https://api.astroneko.com", LandingURL: "https://astroneko.com/join"}.Validate())
+	assert.Error(t, LinkPolicy{BaseURL: "https://api.astroneko.com"}.Validate())
+	assert.Error(t, LinkPolicy{BaseURL: "api.astroneko.com", LandingURL: "https://astroneko.com/join"}.Validate())
+	assert.Error(t, LinkPolicy{BaseURL: "https://api.astroneko.com", LandingURL: "ftp://astroneko.com"}.Validate())
+}
+
+func TestLinkPolicy_DeepLink(t *testing.T) {
+	policy := LinkPolicy{BaseURL: "https://api.astroneko.com/", LandingURL: "https://astroneko.com/join"}
+
+	assert.Equal(t, "https://api.astroneko.com/r/NEKO2026", policy.DeepLink("NEKO2026", UTMParams{}))
+	assert.Equal(t, "https://api.astroneko.com/r/NEKO2026?utm_campaign=launch&utm_medium=qr",
+		policy.DeepLink("NEKO2026", UTMParams{Medium: "qr", Campaign: "launch"}))
+	assert.Empty(t, LinkPolicy{}.DeepLink("NEKO2026", UTMParams{}))
+}
+
+func TestLinkPolicy_LandingLink(t *testing.T) {
+	policy := LinkPolicy{BaseURL: "https://api.astroneko.com", LandingURL: "https://astroneko.com/join?lang=th"}
+	clickID := uuid.MustParse("6f1c2b8e-0d3a-4c55-9a7e-2f4b1c9d8e70")
+
+	assert.Equal(t, "https://astroneko.com/join?click_id=6f1c2b8e-0d3a-4c55-9a7e-2f4b1c9d8e70&lang=th&ref=NEKO2026&utm_source=instagram",
+		policy.LandingLink("NEKO2026", &clickID, UTMParams{Source: "instagram"}))
+	assert.Equal(t, "https://astroneko.com/join?lang=th&ref=NEKO2026", policy.LandingLink("NEKO2026", nil, UTMParams{}))
+}
+
+func TestNewClick(t *testing.T) {
+	codeID := uuid.New()
+
+	click := NewClick(CodeTypeUser, codeID, UTMParams{Source: " newsletter ", Campaign: strings.Repeat("x", 300)}, "", strings.Repeat("u", 600))
+
+	assert.Equal(t, CodeTypeUser, click.CodeType)
+	assert.Equal(t, codeID, click.ReferralCodeID)
+	require.NotNil(t, click.UTMSource)
+	assert.Equal(t, "newsletter", *click.UTMSource)
+	assert.Nil(t, click.UTMMedium)
+	assert.Len(t, *click.UTMCampaign, maxUTMLength)
+	assert.Nil(t, click.Referrer)
+	assert.Len(t, *click.UserAgent, maxHeaderLength)
+}
+
+func TestRate(t *testing.T) {
+	assert.Equal(t, 0.0, Rate(3, 0))
+	assert.Equal(t, 0.25, Rate(1, 4))
+	assert.Equal(t, 0.3333, Rate(1, 3))
+}
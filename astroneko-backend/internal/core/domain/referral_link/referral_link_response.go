@@ -0,0 +1,39 @@
+package referral_link
+
+import "github.com/google/uuid"
+
+// LinkResponse is a code's deep link
+type LinkResponse struct {
+	ReferralCode string `json:"referral_code"`
+	Link         string `json:"link"`
+}
+
+// FunnelEntryResponse is one code's funnel. Rates are relative to clicks.
+type FunnelEntryResponse struct {
+	CodeType       string    `json:"code_type"`
+	ReferralCodeID uuid.UUID `json:"referral_code_id"`
+	// ReferralCode is empty when the code was deleted after it was clicked
+	ReferralCode   string  `json:"referral_code"`
+	Clicks         int64   `json:"clicks"`
+	Signups        int64   `json:"signups"`
+	Activations    int64   `json:"activations"`
+	SignupRate     float64 `json:"signup_rate"`
+	ActivationRate float64 `json:"activation_rate"`
+}
+
+type FunnelResponse struct {
+	Entries []FunnelEntryResponse `json:"entries"`
+	Totals  FunnelCounts          `json:"totals"`
+	Total   int64                 `json:"total"`
+	Limit   int                   `json:"limit"`
+	Offset  int                   `json:"offset"`
+}
+
+// ClaimClickRequest hands the click_id the app received from the landing link back to the API
+type ClaimClickRequest struct {
+	ClickID uuid.UUID `json:"click_id" validate:"required"`
+}
+
+type ClaimClickResponse struct {
+	Claimed bool `json:"claimed"`
+}
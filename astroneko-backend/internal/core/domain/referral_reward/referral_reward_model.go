@@ -0,0 +1,137 @@
+package referral_reward
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reward types. Bonus replies are spent once the daily limit is used up, daily replies
+// raise the daily limit for good, and badges are shown on the user's rewards page.
+const (
+	TypeBonusReplies = "bonus_replies"
+	TypeDailyReplies = "daily_replies"
+	TypeBadge        = "badge"
+)
+
+// Reward is one ledger entry crediting an inviter for a redemption of one of their
+// user referral codes. A redemption credits each reward type at most once, and a user
+// earns each badge at most once; unique indexes enforce both.
+type Reward struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	ReferralLogID uuid.UUID `json:"referral_log_id" gorm:"type:uuid;not null"`
+	Type          string    `json:"type" gorm:"type:varchar(32);not null"`
+	Amount        int       `json:"amount" gorm:"not null;default:0"`
+	Badge         *string   `json:"badge" gorm:"type:varchar(64)"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (Reward) TableName() string {
+	return "astroneko_referral_rewards"
+}
+
+// BadgeRule awards Badge once the user's codes have been redeemed Invites times
+type BadgeRule struct {
+	Badge   string
+	Invites int
+}
+
+// Policy decides what an inviter earns for each redemption of their codes
+type Policy struct {
+	BonusReplies    int
+	DailyReplies    int
+	MaxDailyReplies int
+	Badges          []BadgeRule
+}
+
+// DefaultPolicy applies when the referral.rewards config section is missing
+var DefaultPolicy = Policy{
+	BonusReplies:    5,
+	DailyReplies:    1,
+	MaxDailyReplies: 10,
+	Badges: []BadgeRule{
+		{Badge: "first_invite", Invites: 1},
+		{Badge: "connector", Invites: 5},
+		{Badge: "ambassador", Invites: 20},
+	},
+}
+
+// Validate rejects negative amounts and badges that could never be earned
+func (p Policy) Validate() error {
+	if p.BonusReplies < 0 || p.DailyReplies < 0 || p.MaxDailyReplies < 0 {
+		return errors.New("reward amounts must not be negative")
+	}
+	seen := make(map[string]bool, len(p.Badges))
+	for _, rule := range p.Badges {
+		if rule.Badge == "" || rule.Invites < 1 {
+			return fmt.Errorf("badge %q needs a name and at least one invite", rule.Badge)
+		}
+		if seen[rule.Badge] {
+			return fmt.Errorf("badge %q is listed twice", rule.Badge)
+		}
+		seen[rule.Badge] = true
+	}
+	return nil
+}
+
+// dailyRepliesAfter is the daily limit raise after the given number of invites
+func (p Policy) dailyRepliesAfter(invites int64) int {
+	total := int64(p.DailyReplies) * invites
+	if total > int64(p.MaxDailyReplies) {
+		return p.MaxDailyReplies
+	}
+	return int(total)
+}
+
+// RewardsFor lists what the inviter earns for a redemption that brought their invite
+// count to invites. Daily replies stop once MaxDailyReplies is reached. Every badge whose
+// threshold has been reached is listed, so badges added to the policy later are still
+// awarded on the next invite; ones the user already has are skipped by the ledger.
+func (p Policy) RewardsFor(inviterID, referralLogID uuid.UUID, invites int64) []*Reward {
+	var rewards []*Reward
+	newReward := func(rewardType string, amount int, badge *string) {
+		rewards = append(rewards, &Reward{
+			UserID:        inviterID,
+			ReferralLogID: referralLogID,
+			Type:          rewardType,
+			Amount:        amount,
+			Badge:         badge,
+		})
+	}
+
+	if p.BonusReplies > 0 {
+		newReward(TypeBonusReplies, p.BonusReplies, nil)
+	}
+	if raise := p.dailyRepliesAfter(invites) - p.dailyRepliesAfter(invites-1); raise > 0 {
+		newReward(TypeDailyReplies, raise, nil)
+	}
+	for _, rule := range p.Badges {
+		if invites >= int64(rule.Invites) {
+			badge := rule.Badge
+			newReward(TypeBadge, 0, &badge)
+		}
+	}
+	return rewards
+}
+
+// BadgesOnly is the policy for inviters with unlimited access. They have no daily limit
+// to raise or run past, so bonus and daily replies would be credited but never used.
+func (p Policy) BadgesOnly() Policy {
+	return Policy{Badges: p.Badges}
+}
+
+// NextBadge returns the badge with the lowest threshold above invites, or nil
+func (p Policy) NextBadge(invites int64) *BadgeRule {
+	rules := append([]BadgeRule(nil), p.Badges...)
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Invites < rules[j].Invites })
+	for _, rule := range rules {
+		if int64(rule.Invites) > invites {
+			return &rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package referral_reward
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rewardTypes(rewards []*Reward) []string {
+	types := make([]string, 0, len(rewards))
+	for _, reward := range rewards {
+		if reward.Badge != nil {
+			types = append(types, reward.Type+":"+*reward.Badge)
+		} else {
+			types = append(types, reward.Type)
+		}
+	}
+	return types
+}
+
+func TestPolicy_RewardsFor(t *testing.T) {
+	policy := Policy{
+		BonusReplies:    5,
+		DailyReplies:    2,
+		MaxDailyReplies: 5,
+		Badges:          []BadgeRule{{Badge: "first_invite", Invites: 1}, {Badge: "connector", Invites: 3}},
+	}
+	inviterID, logID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		invites   int64
+		types     []string
+		dailyRise int
+	}{
+		{invites: 1, types: []string{TypeBonusReplies, TypeDailyReplies, "badge:first_invite"}, dailyRise: 2},
+		{invites: 2, types: []string{TypeBonusReplies, TypeDailyReplies, "badge:first_invite"}, dailyRise: 2},
+		{invites: 3, types: []string{TypeBonusReplies, TypeDailyReplies, "badge:first_invite", "badge:connector"}, dailyRise: 1},
+		{invites: 4, types: []string{TypeBonusReplies, "badge:first_invite", "badge:connector"}},
+	}
+
+	for _, tt := range tests {
+		rewards := policy.RewardsFor(inviterID, logID, tt.invites)
+
+		assert.Equal(t, tt.types, rewardTypes(rewards), "invites=%d", tt.invites)
+		for _, reward := range rewards {
+			assert.Equal(t, inviterID, reward.UserID)
+			assert.Equal(t, logID, reward.ReferralLogID)
+			switch reward.Type {
+			case TypeBonusReplies:
+				assert.Equal(t, 5, reward.Amount)
+			case TypeDailyReplies:
+				assert.Equal(t, tt.dailyRise, reward.Amount, "invites=%d", tt.invites)
+			}
+		}
+	}
+}
+
+func TestPolicy_RewardsFor_EmptyPolicy(t *testing.T) {
+	assert.Empty(t, Policy{}.RewardsFor(uuid.New(), uuid.New(), 3))
+}
+
+func TestPolicy_BadgesOnly(t *testing.T) {
+	rewards := DefaultPolicy.BadgesOnly().RewardsFor(uuid.New(), uuid.New(), 1)
+
+	assert.Equal(t, []string{"badge:first_invite"}, rewardTypes(rewards))
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultPolicy.Validate())
+	assert.NoError(t, Policy{}.Validate())
+	assert.Error(t, Policy{BonusReplies: -1}.Validate())
+	assert.Error(t, Policy{Badges: []BadgeRule{{Badge: "", Invites: 1}}}.Validate())
+	assert.Error(t, Policy{Badges: []BadgeRule{{Badge: "zero", Invites: 0}}}.Validate())
+	assert.Error(t, Policy{Badges: []BadgeRule{{Badge: "twice", Invites: 1}, {Badge: "twice", Invites: 2}}}.Validate())
+}
+
+func TestPolicy_NextBadge(t *testing.T) {
+	policy := Policy{Badges: []BadgeRule{{Badge: "ambassador", Invites: 20}, {Badge: "first_invite", Invites: 1}, {Badge: "connector", Invites: 5}}}
+
+	next := policy.NextBadge(0)
+	require.NotNil(t, next)
+	assert.Equal(t, "first_invite", next.Badge)
+
+	next = policy.NextBadge(5)
+	require.NotNil(t, next)
+	assert.Equal(t, "ambassador", next.Badge)
+
+	assert.Nil(t, policy.NextBadge(20))
+}
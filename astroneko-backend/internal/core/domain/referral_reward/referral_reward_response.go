@@ -0,0 +1,44 @@
+package referral_reward
+
+import "time"
+
+type RewardResponse struct {
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount,omitempty"`
+	Badge     *string   `json:"badge,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *Reward) ToResponse() RewardResponse {
+	return RewardResponse{
+		Type:      r.Type,
+		Amount:    r.Amount,
+		Badge:     r.Badge,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// PerInviteResponse tells users what the next redemption of their codes earns them
+type PerInviteResponse struct {
+	BonusReplies int `json:"bonus_replies"`
+	DailyReplies int `json:"daily_replies"`
+}
+
+type NextBadgeResponse struct {
+	Badge       string `json:"badge"`
+	Invites     int    `json:"invites"`
+	InvitesToGo int64  `json:"invites_to_go"`
+}
+
+// MyRewardsResponse shows what the user earned by inviting others and what is left of it.
+// Users with UnlimitedReplies earn only badges, so PerInvite is zero for them.
+type MyRewardsResponse struct {
+	InviteCount           int64              `json:"invite_count"`
+	UnlimitedReplies      bool               `json:"unlimited_replies"`
+	BonusRepliesRemaining int                `json:"bonus_replies_remaining"`
+	ExtraDailyReplies     int                `json:"extra_daily_replies"`
+	Badges                []string           `json:"badges"`
+	NextBadge             *NextBadgeResponse `json:"next_badge,omitempty"`
+	PerInvite             PerInviteResponse  `json:"per_invite"`
+	History               []RewardResponse   `json:"history"`
+}
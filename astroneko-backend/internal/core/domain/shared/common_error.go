@@ -37,6 +37,9 @@ var (
 	ErrEmailNotFoundInToken         = errors.New("email not found in token")
 	ErrGoogleTokenInvalid           = errors.New("invalid google token")
 	ErrGoogleTokenEmailMissing      = errors.New("email not found in google token")
+	ErrForbidden                    = errors.New("not allowed to access this resource")
+	ErrInvalidCredentials           = errors.New("invalid credentials")
+	ErrTooManyAttempts              = errors.New("too many failed attempts")
 
 	// User related errors
 	ErrUserNotFound                  = errors.New("user not found")
@@ -49,6 +52,35 @@ var (
 	ErrReferralAlreadyActivated      = errors.New("referral already activated")
 	ErrWaitingListUserAlreadyExists  = errors.New("user already exists in waiting list")
 	ErrWaitingListUserCreationFailed = errors.New("failed to add user to waiting list")
+	ErrBirthProfileNotSet            = errors.New("birth profile not set")
+	ErrInvalidBirthProfile           = errors.New("invalid birth profile")
+	ErrInvalidZodiacSign             = errors.New("invalid zodiac sign")
+	ErrHoroscopeNotFound             = errors.New("horoscope not found")
+	ErrHoroscopeGenerationFailed     = errors.New("failed to generate horoscope")
+	ErrLunarDateOutOfRange           = errors.New("date is outside the supported lunar calendar range")
+	ErrInvalidNumerologyInput        = errors.New("invalid numerology input")
+	ErrPersonNotFound                = errors.New("person not found")
+	ErrPeopleLimitReached            = errors.New("saved people limit reached")
+	ErrDeletionRequestNotFound       = errors.New("no pending account deletion")
+	ErrInvalidDeletionConfirmation   = errors.New("invalid or expired deletion confirmation")
+	ErrExportNotFound                = errors.New("data export not found")
+	ErrExportRateLimited             = errors.New("too many data export requests")
+	ErrInvalidDownloadLink           = errors.New("invalid or expired download link")
+
+	// CRM related errors
+	ErrCRMUserNotFound           = errors.New("CRM user not found")
+	ErrUnknownCRMRole            = errors.New("unknown CRM role")
+	ErrLastCRMAdmin              = errors.New("cannot remove the last CRM admin")
+	ErrCRMBootstrapClosed        = errors.New("CRM bootstrap is disabled or an admin already exists")
+	ErrCRMUserDisabled           = errors.New("CRM user is disabled")
+	ErrCRMSessionRevoked         = errors.New("CRM session has been revoked")
+	ErrWeakPassword              = errors.New("password does not meet the password policy")
+	ErrInvalidResetToken         = errors.New("invalid or expired password reset token")
+	ErrCRMTokenRevoked           = errors.New("CRM access token has been revoked")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp         = errors.New("two-factor authentication is not set up")
 
 	// General errors
 	ErrInvalidRequest           = errors.New("invalid request")
@@ -276,6 +308,168 @@ var errorRegistry = map[string]CustomError{
 		Module:     "waiting_list",
 		Message:    "Failed to add user to waiting list",
 		Details:    "Error adding user to waiting list in database"},
+	"ERR_1036": {
+		HTTPStatus: http.StatusTooManyRequests,
+		Code:       "ERR_1036",
+		Module:     "data_export",
+		Message:    "Too many data export requests",
+		Details:    "Data export request limit reached, please try again later"},
+	"ERR_1037": {
+		HTTPStatus: http.StatusForbidden,
+		Code:       "ERR_1037",
+		Module:     "auth",
+		Message:    "Forbidden",
+		Details:    "You are not allowed to access this resource"},
+	"ERR_1038": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1038",
+		Module:     "crm",
+		Message:    "Unknown CRM role",
+		Details:    "One or more roles do not exist"},
+	"ERR_1039": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1039",
+		Module:     "crm",
+		Message:    "Cannot remove the last CRM admin",
+		Details:    "Assign the admin role to another CRM user first"},
+	"ERR_1040": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1040",
+		Module:     "crm",
+		Message:    "CRM bootstrap unavailable",
+		Details:    "Bootstrapping is disabled or an admin already exists"},
+	"ERR_1041": {
+		HTTPStatus: http.StatusForbidden,
+		Code:       "ERR_1041",
+		Module:     "crm",
+		Message:    "CRM user disabled",
+		Details:    "This CRM account has been disabled"},
+	"ERR_1042": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1042",
+		Module:     "crm",
+		Message:    "Weak password",
+		Details:    "The password does not meet the password policy"},
+	"ERR_1043": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1043",
+		Module:     "crm",
+		Message:    "Invalid password reset token",
+		Details:    "The password reset token is invalid, used or expired"},
+	"ERR_1044": {
+		HTTPStatus: http.StatusUnauthorized,
+		Code:       "ERR_1044",
+		Module:     "crm",
+		Message:    "Invalid refresh token",
+		Details:    "The refresh token is invalid, expired or revoked; log in again"},
+	"ERR_1045": {
+		HTTPStatus: http.StatusUnauthorized,
+		Code:       "ERR_1045",
+		Module:     "crm",
+		Message:    "Invalid two-factor code",
+		Details:    "The authentication or recovery code is wrong or was already used"},
+	"ERR_1046": {
+		HTTPStatus: http.StatusUnauthorized,
+		Code:       "ERR_1046",
+		Module:     "crm",
+		Message:    "Invalid two-factor challenge",
+		Details:    "The login challenge is invalid or expired; log in again"},
+	"ERR_1047": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1047",
+		Module:     "crm",
+		Message:    "Two-factor authentication already enabled",
+		Details:    "Disable two-factor authentication or ask an admin to reset it first"},
+	"ERR_1048": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1048",
+		Module:     "crm",
+		Message:    "Two-factor authentication not set up",
+		Details:    "Start two-factor setup first"},
+	"ERR_1049": {
+		HTTPStatus: http.StatusTooManyRequests,
+		Code:       "ERR_1049",
+		Module:     "auth",
+		Message:    "Too many failed attempts",
+		Details:    "Try again later"},
+	"ERR_1050": {
+		HTTPStatus: http.StatusUnauthorized,
+		Code:       "ERR_1050",
+		Module:     "auth",
+		Message:    "Invalid credentials",
+		Details:    "The credentials are incorrect"},
+	"ERR_1051": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1051",
+		Module:     "user",
+		Message:    "Referral code unavailable",
+		Details:    "The referral code cannot be redeemed right now"},
+	"ERR_1052": {
+		HTTPStatus: http.StatusServiceUnavailable,
+		Code:       "ERR_1052",
+		Module:     "user",
+		Message:    "Referral links unavailable",
+		Details:    "Referral deep links are not configured"},
+	"ERR_1053": {
+		HTTPStatus: http.StatusNotFound,
+		Code:       "ERR_1053",
+		Module:     "waiting_list",
+		Message:    "Waiting list not found",
+		Details:    "No waiting list exists with this slug"},
+	"ERR_1054": {
+		HTTPStatus: http.StatusForbidden,
+		Code:       "ERR_1054",
+		Module:     "waiting_list",
+		Message:    "Waiting list closed",
+		Details:    "The waiting list is not accepting new entries"},
+	"ERR_1055": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1055",
+		Module:     "waiting_list",
+		Message:    "Waiting list full",
+		Details:    "The waiting list has reached its capacity"},
+	"ERR_1056": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1056",
+		Module:     "waiting_list",
+		Message:    "Invalid waiting list fields",
+		Details:    "Required fields are missing or fields are invalid"},
+	"ERR_1057": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1057",
+		Module:     "waiting_list",
+		Message:    "Waiting list already exists",
+		Details:    "A waiting list with this slug already exists"},
+	"ERR_1058": {
+		HTTPStatus: http.StatusBadRequest,
+		Code:       "ERR_1058",
+		Module:     "waiting_list",
+		Message:    "Disposable email",
+		Details:    "Disposable email addresses cannot join the waiting list"},
+	"ERR_1059": {
+		HTTPStatus: http.StatusUnauthorized,
+		Code:       "ERR_1059",
+		Module:     "waiting_list",
+		Message:    "Invalid status token",
+		Details:    "The waiting list status link is invalid or has expired"},
+	"ERR_1060": {
+		HTTPStatus: http.StatusForbidden,
+		Code:       "ERR_1060",
+		Module:     "user",
+		Message:    "Vanity referral code not available",
+		Details:    "Invite more users to claim a vanity referral code"},
+	"ERR_1061": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1061",
+		Module:     "user",
+		Message:    "Referral code taken",
+		Details:    "The referral code is already taken"},
+	"ERR_1062": {
+		HTTPStatus: http.StatusConflict,
+		Code:       "ERR_1062",
+		Module:     "user",
+		Message:    "Vanity referral code already claimed",
+		Details:    "The user already has a vanity referral code"},
 }
 
 func NewErrorResponse(code string, detailOverride ...string) (int, ResponseBody) {
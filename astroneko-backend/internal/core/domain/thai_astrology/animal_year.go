@@ -0,0 +1,36 @@
+package thai_astrology
+
+// AnimalYear is a year of the Thai twelve-year animal cycle (ปีนักษัตร)
+type AnimalYear struct {
+	Key    string `json:"key"`
+	NameTH string `json:"name_th"`
+	NameEN string `json:"name_en"`
+}
+
+// AnimalYears lists the cycle starting from the year of the rat (ปีชวด)
+var AnimalYears = [12]AnimalYear{
+	{Key: "rat", NameTH: "ชวด", NameEN: "Rat"},
+	{Key: "ox", NameTH: "ฉลู", NameEN: "Ox"},
+	{Key: "tiger", NameTH: "ขาล", NameEN: "Tiger"},
+	{Key: "rabbit", NameTH: "เถาะ", NameEN: "Rabbit"},
+	{Key: "dragon", NameTH: "มะโรง", NameEN: "Dragon"},
+	{Key: "snake", NameTH: "มะเส็ง", NameEN: "Snake"},
+	{Key: "horse", NameTH: "มะเมีย", NameEN: "Horse"},
+	{Key: "goat", NameTH: "มะแม", NameEN: "Goat"},
+	{Key: "monkey", NameTH: "วอก", NameEN: "Monkey"},
+	{Key: "rooster", NameTH: "ระกา", NameEN: "Rooster"},
+	{Key: "dog", NameTH: "จอ", NameEN: "Dog"},
+	{Key: "pig", NameTH: "กุน", NameEN: "Pig"},
+}
+
+// animalYearOf returns the animal year of a lunar month. The animal changes
+// on the first waxing day of the fifth month, so months one to four still
+// belong to the previous animal.
+func animalYearOf(chulaSakarat int, month int) AnimalYear {
+	year := chulaSakarat
+	if month < 5 {
+		year--
+	}
+	// Chula Sakarat 1382 (2020) was a year of the rat
+	return AnimalYears[((year-1382)%12+12)%12]
+}
@@ -0,0 +1,84 @@
+package thai_astrology
+
+import "time"
+
+// positionPoints weighs the Thaksa position a day's planet takes for the birth day
+var positionPoints = map[string]int{
+	"si":       30,
+	"det":      25,
+	"montri":   20,
+	"ayu":      15,
+	"boriwan":  10,
+	"utsaha":   10,
+	"mula":     5,
+	"kalakini": -40,
+}
+
+const (
+	baseScore         = 50
+	waxingPoints      = 5
+	waningPoints      = -5
+	wanPhraMajorBonus = 10
+	wanPhraMinorBonus = 5
+	observanceBonus   = 5
+)
+
+// ScoreDay rates how auspicious a date is for someone born on birthDay.
+// The score starts at 50 and is adjusted by the Thaksa position of the
+// date's weekday, the moon phase and Buddhist holy days, clamped to 0-100.
+func ScoreDay(birthDay Day, date time.Time) (*AuspiciousDay, error) {
+	lunar, err := ToLunarDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	position := ThaksaPositionOf(birthDay, lunar.Weekday)
+	factors := []ScoreFactor{{Key: "thaksa_" + position.Key, Points: positionPoints[position.Key]}}
+
+	// Beginnings are favoured while the moon is waxing
+	if lunar.Phase == PhaseWaxing {
+		factors = append(factors, ScoreFactor{Key: "waxing_moon", Points: waxingPoints})
+	} else {
+		factors = append(factors, ScoreFactor{Key: "waning_moon", Points: waningPoints})
+	}
+
+	if lunar.IsWanPhra() {
+		if lunar.Day == 8 {
+			factors = append(factors, ScoreFactor{Key: "wan_phra", Points: wanPhraMinorBonus})
+		} else {
+			factors = append(factors, ScoreFactor{Key: "wan_phra_major", Points: wanPhraMajorBonus})
+		}
+		if observance := ObservanceOf(lunar); observance != nil {
+			factors = append(factors, ScoreFactor{Key: observance.Key, Points: observanceBonus})
+		}
+	}
+
+	score := baseScore
+	for _, factor := range factors {
+		score += factor.Points
+	}
+	score = max(0, min(100, score))
+
+	return &AuspiciousDay{
+		Date:     lunar.Date,
+		Score:    score,
+		Rating:   ratingOf(score),
+		Position: position,
+		Lunar:    *lunar,
+		Factors:  factors,
+	}, nil
+}
+
+// ratingOf buckets a score
+func ratingOf(score int) Rating {
+	switch {
+	case score >= 80:
+		return RatingExcellent
+	case score >= 60:
+		return RatingGood
+	case score >= 40:
+		return RatingFair
+	default:
+		return RatingPoor
+	}
+}
@@ -0,0 +1,298 @@
+package thai_astrology
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+// DateLayout is the date format used by Thai astrology requests and responses
+const DateLayout = "2006-01-02"
+
+// DefaultTimezone decides what "today" is for the Thai calendar
+const DefaultTimezone = "Asia/Bangkok"
+
+// Gregorian range supported by the lunar calendar tables
+var (
+	MinSupportedDate = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	MaxSupportedDate = time.Date(2199, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// YearType classifies a Thai lunar year by its intercalation
+type YearType string
+
+const (
+	// YearTypeNormal has twelve months and 354 days
+	YearTypeNormal YearType = "normal"
+	// YearTypeAdhikamasa (อธิกมาส) repeats the eighth month and has 384 days
+	YearTypeAdhikamasa YearType = "adhikamasa"
+	// YearTypeAdhikavara (อธิกวาร) adds a day to the seventh month and has 355 days
+	YearTypeAdhikavara YearType = "adhikavara"
+)
+
+// Phase is the half of the lunar month a day belongs to
+type Phase string
+
+const (
+	// PhaseWaxing (ข้างขึ้น) runs from the new moon to the full moon, always 15 days
+	PhaseWaxing Phase = "waxing"
+	// PhaseWaning (ข้างแรม) runs from the full moon to the new moon, 14 or 15 days
+	PhaseWaning Phase = "waning"
+)
+
+const (
+	// lunarEpochYear is a Chula Sakarat year whose first day is known, used to anchor the tables
+	lunarEpochYear = 1388
+	// buddhistEraToCE is the offset between Buddhist Era and Common Era years
+	buddhistEraToCE = 543
+)
+
+// lunarEpochStart is the first waxing day of the first month (เดือนอ้าย) of lunarEpochYear
+var lunarEpochStart = time.Date(2025, time.November, 21, 0, 0, 0, 0, time.UTC)
+
+var monthNamesTH = [12]string{"อ้าย", "ยี่", "สาม", "สี่", "ห้า", "หก", "เจ็ด", "แปด", "เก้า", "สิบ", "สิบเอ็ด", "สิบสอง"}
+
+// lunarYear is one row of the precomputed calendar table
+type lunarYear struct {
+	chulaSakarat int
+	yearType     YearType
+	start        int64 // days since the Unix epoch of ขึ้น 1 ค่ำ เดือนอ้าย
+}
+
+// lunarMonth is one month in the layout of a lunar year
+type lunarMonth struct {
+	number int
+	second bool
+	days   int
+}
+
+var (
+	lunarTableOnce sync.Once
+	lunarTable     []lunarYear
+)
+
+// horakhun returns the day count (อหรคุณ) at the start of a Chula Sakarat year
+// in the Suriyayatra reckoning, together with its avoman (อวมาน) and tithi (ดิถี)
+func horakhun(year int) (days, avoman, tithi int64) {
+	y := int64(year)
+	days = (y*292207+499)/800 + 1
+	avoman = (days*11 + 650) % 692
+	tithi = (days + (days*11+650)/692) % 30
+	return days, avoman, tithi
+}
+
+// kammacapon returns the solar remainder (กัมมัชพล) of a Chula Sakarat year
+func kammacapon(year int) int64 {
+	return 800 - (int64(year)*292207+499)%800
+}
+
+// adhikamasaCandidate reports whether the year's tithi falls in the leap-month window
+func adhikamasaCandidate(year int) bool {
+	_, _, tithi := horakhun(year)
+	return tithi >= 24 || tithi <= 5
+}
+
+// isAdhikamasa reports whether the year repeats the eighth month.
+// When two consecutive years qualify, the later one takes the extra month.
+func isAdhikamasa(year int) bool {
+	return adhikamasaCandidate(year) && !adhikamasaCandidate(year+1)
+}
+
+// needsAdhikavara reports whether the year's avoman calls for an extra day
+func needsAdhikavara(year int) bool {
+	_, avoman, _ := horakhun(year)
+	if kammacapon(year) <= 207 {
+		// Solar leap years use a tighter threshold
+		return avoman < 127
+	}
+	return avoman < 137
+}
+
+// isAdhikavara reports whether the year lengthens the seventh month.
+// A leap month year cannot also take the extra day, so it moves to the next year.
+func isAdhikavara(year int) bool {
+	if isAdhikamasa(year) {
+		return false
+	}
+	return needsAdhikavara(year) || (isAdhikamasa(year-1) && needsAdhikavara(year-1))
+}
+
+// yearTypeOf classifies a Chula Sakarat year
+func yearTypeOf(year int) YearType {
+	switch {
+	case isAdhikamasa(year):
+		return YearTypeAdhikamasa
+	case isAdhikavara(year):
+		return YearTypeAdhikavara
+	default:
+		return YearTypeNormal
+	}
+}
+
+// yearLength returns the number of days in a lunar year of the given type
+func yearLength(yearType YearType) int64 {
+	switch yearType {
+	case YearTypeAdhikamasa:
+		return 384
+	case YearTypeAdhikavara:
+		return 355
+	default:
+		return 354
+	}
+}
+
+// monthsOf lays out the months of a lunar year. Odd months have 29 days and even
+// months 30, except for the extra day and the repeated eighth month.
+func monthsOf(yearType YearType) []lunarMonth {
+	months := make([]lunarMonth, 0, 13)
+	for number := 1; number <= 12; number++ {
+		days := 29
+		if number%2 == 0 {
+			days = 30
+		}
+		if number == 7 && yearType == YearTypeAdhikavara {
+			days = 30
+		}
+		months = append(months, lunarMonth{number: number, days: days})
+		if number == 8 && yearType == YearTypeAdhikamasa {
+			months = append(months, lunarMonth{number: 8, second: true, days: 30})
+		}
+	}
+	return months
+}
+
+// buildLunarTable walks from the epoch year in both directions until the supported range is covered
+func buildLunarTable() []lunarYear {
+	minDay := daysSinceEpoch(MinSupportedDate)
+	maxDay := daysSinceEpoch(MaxSupportedDate)
+
+	epoch := lunarYear{chulaSakarat: lunarEpochYear, yearType: yearTypeOf(lunarEpochYear), start: daysSinceEpoch(lunarEpochStart)}
+
+	var before []lunarYear
+	for current := epoch; current.start > minDay; {
+		year := current.chulaSakarat - 1
+		yearType := yearTypeOf(year)
+		current = lunarYear{chulaSakarat: year, yearType: yearType, start: current.start - yearLength(yearType)}
+		before = append(before, current)
+	}
+
+	table := make([]lunarYear, 0, len(before)+320)
+	for index := len(before) - 1; index >= 0; index-- {
+		table = append(table, before[index])
+	}
+	table = append(table, epoch)
+
+	for current := epoch; current.start+yearLength(current.yearType) <= maxDay; {
+		year := current.chulaSakarat + 1
+		current = lunarYear{chulaSakarat: year, yearType: yearTypeOf(year), start: current.start + yearLength(current.yearType)}
+		table = append(table, current)
+	}
+
+	return table
+}
+
+// daysSinceEpoch counts calendar days since 1970-01-01, ignoring time of day and location
+func daysSinceEpoch(date time.Time) int64 {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// findLunarYear returns the table row containing the day
+func findLunarYear(day int64) (lunarYear, bool) {
+	lunarTableOnce.Do(func() {
+		lunarTable = buildLunarTable()
+	})
+
+	low, high := 0, len(lunarTable)-1
+	for low <= high {
+		middle := (low + high) / 2
+		row := lunarTable[middle]
+		switch {
+		case day < row.start:
+			high = middle - 1
+		case day >= row.start+yearLength(row.yearType):
+			low = middle + 1
+		default:
+			return row, true
+		}
+	}
+	return lunarYear{}, false
+}
+
+// ToLunarDate converts a Gregorian calendar date to the Thai lunar calendar.
+// Only the date part is used; the time of day and location are ignored.
+func ToLunarDate(date time.Time) (*LunarDate, error) {
+	calendarDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if calendarDate.Before(MinSupportedDate) || calendarDate.After(MaxSupportedDate) {
+		return nil, shared.ErrLunarDateOutOfRange
+	}
+
+	day := daysSinceEpoch(calendarDate)
+	year, ok := findLunarYear(day)
+	if !ok {
+		return nil, shared.ErrLunarDateOutOfRange
+	}
+
+	offset := int(day - year.start)
+	for _, month := range monthsOf(year.yearType) {
+		if offset >= month.days {
+			offset -= month.days
+			continue
+		}
+
+		lunar := &LunarDate{
+			Date:         calendarDate.Format(DateLayout),
+			Weekday:      DayForWeekday(calendarDate.Weekday()),
+			Month:        month.number,
+			SecondMonth:  month.second,
+			MonthDays:    month.days,
+			Phase:        PhaseWaxing,
+			Day:          offset + 1,
+			BuddhistEra:  calendarDate.Year() + buddhistEraToCE,
+			ChulaSakarat: year.chulaSakarat,
+			YearType:     year.yearType,
+			AnimalYear:   animalYearOf(year.chulaSakarat, month.number),
+		}
+		if offset >= 15 {
+			lunar.Phase = PhaseWaning
+			lunar.Day = offset - 14
+		}
+		lunar.TextTH = lunar.thaiText()
+		return lunar, nil
+	}
+
+	// Unreachable: the offset always falls inside the year's months
+	return nil, fmt.Errorf("lunar day offset %d exceeds year length", offset)
+}
+
+// IsWanPhra reports whether the lunar day is a Buddhist holy day:
+// the 8th and 15th of the waxing moon, the 8th of the waning moon and the last day of the month
+func (l *LunarDate) IsWanPhra() bool {
+	if l.Day == 8 {
+		return true
+	}
+	if l.Phase == PhaseWaxing {
+		return l.Day == 15
+	}
+	return l.Day == l.MonthDays-15
+}
+
+// thaiText renders the lunar date the way Thai calendars print it
+func (l *LunarDate) thaiText() string {
+	phase := "ขึ้น"
+	if l.Phase == PhaseWaning {
+		phase = "แรม"
+	}
+
+	month := "เดือน" + monthNamesTH[l.Month-1]
+	if l.Month == 8 && l.YearType == YearTypeAdhikamasa {
+		if l.SecondMonth {
+			month = "เดือนแปดหลัง"
+		} else {
+			month = "เดือนแปดแรก"
+		}
+	}
+
+	return fmt.Sprintf("%s %d ค่ำ %s ปี%s", phase, l.Day, month, l.AnimalYear.NameTH)
+}
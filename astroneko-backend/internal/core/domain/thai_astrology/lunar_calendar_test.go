@@ -0,0 +1,166 @@
+package thai_astrology
+
+import (
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Official Thai Buddhist holiday dates (full moon, ขึ้น 15 ค่ำ)
+var buddhistHolidays = []struct {
+	year         int
+	yearType     YearType
+	makhaBucha   time.Time
+	visakhaBucha time.Time
+	asarnhaBucha time.Time
+}{
+	{2009, YearTypeAdhikavara, date(2009, 2, 9), date(2009, 5, 8), date(2009, 7, 7)},
+	{2010, YearTypeAdhikamasa, date(2010, 2, 28), date(2010, 5, 28), date(2010, 7, 26)},
+	{2011, YearTypeNormal, date(2011, 2, 18), date(2011, 5, 17), date(2011, 7, 15)},
+	{2012, YearTypeAdhikamasa, date(2012, 3, 7), date(2012, 6, 4), date(2012, 8, 2)},
+	{2013, YearTypeNormal, date(2013, 2, 25), date(2013, 5, 24), date(2013, 7, 22)},
+	{2014, YearTypeNormal, date(2014, 2, 14), date(2014, 5, 13), date(2014, 7, 11)},
+	{2015, YearTypeAdhikamasa, date(2015, 3, 4), date(2015, 6, 1), date(2015, 7, 30)},
+	{2016, YearTypeAdhikavara, date(2016, 2, 22), date(2016, 5, 20), date(2016, 7, 19)},
+	{2017, YearTypeNormal, date(2017, 2, 11), date(2017, 5, 10), date(2017, 7, 8)},
+	{2018, YearTypeAdhikamasa, date(2018, 3, 1), date(2018, 5, 29), date(2018, 7, 27)},
+	{2019, YearTypeNormal, date(2019, 2, 19), date(2019, 5, 18), date(2019, 7, 16)},
+	{2020, YearTypeAdhikavara, date(2020, 2, 8), date(2020, 5, 6), date(2020, 7, 5)},
+	{2021, YearTypeAdhikamasa, date(2021, 2, 26), date(2021, 5, 26), date(2021, 7, 24)},
+	{2022, YearTypeNormal, date(2022, 2, 16), date(2022, 5, 15), date(2022, 7, 13)},
+	{2023, YearTypeAdhikamasa, date(2023, 3, 6), date(2023, 6, 3), date(2023, 8, 1)},
+	{2024, YearTypeNormal, date(2024, 2, 24), date(2024, 5, 22), date(2024, 7, 20)},
+	{2025, YearTypeAdhikavara, date(2025, 2, 12), date(2025, 5, 11), date(2025, 7, 10)},
+	{2026, YearTypeAdhikamasa, date(2026, 3, 3), date(2026, 5, 31), date(2026, 7, 29)},
+}
+
+func TestToLunarDate_BuddhistHolidays(t *testing.T) {
+	for _, holiday := range buddhistHolidays {
+		for expected, day := range map[string]time.Time{
+			ObservanceMakhaBucha.Key:   holiday.makhaBucha,
+			ObservanceVisakhaBucha.Key: holiday.visakhaBucha,
+			ObservanceAsarnhaBucha.Key: holiday.asarnhaBucha,
+		} {
+			lunar, err := ToLunarDate(day)
+
+			assert.NoError(t, err)
+			assert.Equal(t, PhaseWaxing, lunar.Phase, "%s %d", expected, holiday.year)
+			assert.Equal(t, 15, lunar.Day, "%s %d", expected, holiday.year)
+			assert.Equal(t, holiday.yearType, lunar.YearType, "%s %d", expected, holiday.year)
+			if assert.NotNil(t, ObservanceOf(lunar), "%s %d", expected, holiday.year) {
+				assert.Equal(t, expected, ObservanceOf(lunar).Key, "%d", holiday.year)
+			}
+		}
+
+		// Khao Phansa, the start of Buddhist Lent, is the day after Asarnha Bucha
+		lent, err := ToLunarDate(holiday.asarnhaBucha.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+		assert.Equal(t, PhaseWaning, lent.Phase)
+		assert.Equal(t, 1, lent.Day)
+		assert.Equal(t, 8, lent.Month)
+	}
+}
+
+func TestToLunarDate_LoyKrathong(t *testing.T) {
+	for _, day := range []time.Time{date(2023, 11, 27), date(2024, 11, 15), date(2025, 11, 5)} {
+		lunar, err := ToLunarDate(day)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, lunar.Month)
+		assert.Equal(t, 15, lunar.Day)
+		assert.Equal(t, &ObservanceLoyKrathong, ObservanceOf(lunar))
+	}
+}
+
+func TestToLunarDate_Details(t *testing.T) {
+	// Act
+	lunar, err := ToLunarDate(time.Date(2026, 7, 29, 22, 30, 0, 0, time.FixedZone("ICT", 7*3600)))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-07-29", lunar.Date)
+	assert.Equal(t, "wednesday", lunar.Weekday.Key)
+	assert.Equal(t, 8, lunar.Month)
+	assert.True(t, lunar.SecondMonth)
+	assert.Equal(t, 30, lunar.MonthDays)
+	assert.Equal(t, 2569, lunar.BuddhistEra)
+	assert.Equal(t, 1388, lunar.ChulaSakarat)
+	assert.Equal(t, "horse", lunar.AnimalYear.Key)
+	assert.Equal(t, "ขึ้น 15 ค่ำ เดือนแปดหลัง ปีมะเมีย", lunar.TextTH)
+}
+
+func TestToLunarDate_AnimalYearChangesInFifthMonth(t *testing.T) {
+	// Makha Bucha 2026 is still in the year of the snake
+	before, err := ToLunarDate(date(2026, 3, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, "snake", before.AnimalYear.Key)
+	assert.Equal(t, "ขึ้น 15 ค่ำ เดือนสี่ ปีมะเส็ง", before.TextTH)
+
+	after, err := ToLunarDate(date(2026, 5, 31))
+	assert.NoError(t, err)
+	assert.Equal(t, "horse", after.AnimalYear.Key)
+}
+
+func TestToLunarDate_MonthsAreContinuous(t *testing.T) {
+	previous, err := ToLunarDate(MinSupportedDate)
+	assert.NoError(t, err)
+
+	for day := MinSupportedDate.AddDate(0, 0, 1); !day.After(MaxSupportedDate); day = day.AddDate(0, 0, 1) {
+		current, err := ToLunarDate(day)
+		if !assert.NoError(t, err, day.Format(DateLayout)) {
+			return
+		}
+
+		lastDay := previous.Phase == PhaseWaning && previous.Day == previous.MonthDays-15
+		switch {
+		case previous.Phase == PhaseWaxing && previous.Day == 15:
+			assert.Equal(t, PhaseWaning, current.Phase, current.Date)
+			assert.Equal(t, 1, current.Day, current.Date)
+		case lastDay:
+			assert.Equal(t, PhaseWaxing, current.Phase, current.Date)
+			assert.Equal(t, 1, current.Day, current.Date)
+		default:
+			assert.Equal(t, previous.Phase, current.Phase, current.Date)
+			assert.Equal(t, previous.Day+1, current.Day, current.Date)
+		}
+		previous = current
+	}
+}
+
+func TestToLunarDate_OutOfRange(t *testing.T) {
+	_, err := ToLunarDate(date(1899, 12, 31))
+	assert.Equal(t, shared.ErrLunarDateOutOfRange, err)
+
+	_, err = ToLunarDate(date(2200, 1, 1))
+	assert.Equal(t, shared.ErrLunarDateOutOfRange, err)
+}
+
+func TestWanPhraDays(t *testing.T) {
+	// Act
+	days, err := WanPhraDays(2025, time.May)
+
+	// Assert
+	assert.NoError(t, err)
+	dates := make([]string, 0, len(days))
+	for _, day := range days {
+		dates = append(dates, day.Date)
+	}
+	assert.Equal(t, []string{"2025-05-04", "2025-05-11", "2025-05-19", "2025-05-26"}, dates)
+	assert.Equal(t, WanPhraMinor, days[0].Kind)
+	assert.Equal(t, WanPhraMajor, days[1].Kind)
+	assert.Equal(t, &ObservanceVisakhaBucha, days[1].Observance)
+	assert.Nil(t, days[3].Observance)
+	assert.Equal(t, 15, days[3].Lunar.Day)
+}
+
+func TestWanPhraDays_OutOfRange(t *testing.T) {
+	_, err := WanPhraDays(1850, time.January)
+	assert.Equal(t, shared.ErrLunarDateOutOfRange, err)
+}
@@ -0,0 +1,92 @@
+package thai_astrology
+
+// LunarDate is a date in the Thai lunar calendar
+type LunarDate struct {
+	Date    string `json:"date"`
+	Weekday Day    `json:"weekday"`
+	Month   int    `json:"month"`
+	// SecondMonth marks the repeated eighth month (เดือนแปดหลัง) of an adhikamasa year
+	SecondMonth  bool       `json:"second_month"`
+	MonthDays    int        `json:"month_days"`
+	Phase        Phase      `json:"phase"`
+	Day          int        `json:"day"`
+	BuddhistEra  int        `json:"buddhist_era"`
+	ChulaSakarat int        `json:"chula_sakarat"`
+	YearType     YearType   `json:"year_type"`
+	AnimalYear   AnimalYear `json:"animal_year"`
+	TextTH       string     `json:"text_th"`
+}
+
+// ThaksaEntry is the day and colour holding a Thaksa position
+type ThaksaEntry struct {
+	Position ThaksaPosition `json:"position"`
+	Day      string         `json:"day"`
+	Color    Color          `json:"color"`
+}
+
+// DayColorTable lists the auspicious and inauspicious colours of a weekday
+type DayColorTable struct {
+	Day    Day           `json:"day"`
+	Colors []ThaksaEntry `json:"colors"`
+}
+
+// Observance is a Buddhist holiday that falls on a holy day
+type Observance struct {
+	Key    string `json:"key"`
+	NameTH string `json:"name_th"`
+	NameEN string `json:"name_en"`
+}
+
+// WanPhraKind distinguishes the quarter-moon holy days from the full and new moon ones
+type WanPhraKind string
+
+const (
+	// WanPhraMinor falls on the 8th day of either phase
+	WanPhraMinor WanPhraKind = "minor"
+	// WanPhraMajor falls on the full moon or the last day of the month
+	WanPhraMajor WanPhraKind = "major"
+)
+
+// WanPhraDay is a Buddhist holy day (วันพระ)
+type WanPhraDay struct {
+	Date       string      `json:"date"`
+	Kind       WanPhraKind `json:"kind"`
+	Lunar      LunarDate   `json:"lunar"`
+	Observance *Observance `json:"observance,omitempty"`
+}
+
+// ScoreFactor is one contribution to an auspicious-day score
+type ScoreFactor struct {
+	Key    string `json:"key"`
+	Points int    `json:"points"`
+}
+
+// Rating summarizes an auspicious-day score
+type Rating string
+
+const (
+	RatingExcellent Rating = "excellent"
+	RatingGood      Rating = "good"
+	RatingFair      Rating = "fair"
+	RatingPoor      Rating = "poor"
+)
+
+// AuspiciousDay is the score of a date for someone born on a given day
+type AuspiciousDay struct {
+	Date     string         `json:"date"`
+	Score    int            `json:"score"`
+	Rating   Rating         `json:"rating"`
+	Position ThaksaPosition `json:"position"`
+	Lunar    LunarDate      `json:"lunar"`
+	Factors  []ScoreFactor  `json:"factors"`
+}
+
+// AuspiciousDaysResponse scores a range of dates for a user's birth day
+type AuspiciousDaysResponse struct {
+	BirthDay Day `json:"birth_day"`
+	// Colors is the Thaksa colour table of the birth day
+	Colors []ThaksaEntry   `json:"colors"`
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Days   []AuspiciousDay `json:"days"`
+}
@@ -0,0 +1,152 @@
+package thai_astrology
+
+import "time"
+
+// Planet is one of the eight celestial bodies of the Thaksa (ทักษา) system
+type Planet string
+
+const (
+	PlanetSun     Planet = "sun"
+	PlanetMoon    Planet = "moon"
+	PlanetMars    Planet = "mars"
+	PlanetMercury Planet = "mercury"
+	PlanetJupiter Planet = "jupiter"
+	PlanetVenus   Planet = "venus"
+	PlanetSaturn  Planet = "saturn"
+	PlanetRahu    Planet = "rahu"
+)
+
+// Color is a traditional Thai colour
+type Color struct {
+	Key    string `json:"key"`
+	NameTH string `json:"name_th"`
+	NameEN string `json:"name_en"`
+	Hex    string `json:"hex"`
+}
+
+// Day is a Thai astrological day. Wednesday is split into day and night,
+// the night belonging to Rahu, which gives eight days for the eight planets.
+type Day struct {
+	Key    string `json:"key"`
+	NameTH string `json:"name_th"`
+	NameEN string `json:"name_en"`
+	Planet Planet `json:"planet"`
+	Color  Color  `json:"color"`
+}
+
+// Days lists the astrological days in weekday order, Wednesday night after Wednesday
+var Days = [8]Day{
+	{Key: "sunday", NameTH: "วันอาทิตย์", NameEN: "Sunday", Planet: PlanetSun, Color: Color{Key: "red", NameTH: "แดง", NameEN: "Red", Hex: "#E53935"}},
+	{Key: "monday", NameTH: "วันจันทร์", NameEN: "Monday", Planet: PlanetMoon, Color: Color{Key: "yellow", NameTH: "เหลือง", NameEN: "Yellow", Hex: "#FDD835"}},
+	{Key: "tuesday", NameTH: "วันอังคาร", NameEN: "Tuesday", Planet: PlanetMars, Color: Color{Key: "pink", NameTH: "ชมพู", NameEN: "Pink", Hex: "#EC407A"}},
+	{Key: "wednesday", NameTH: "วันพุธ (กลางวัน)", NameEN: "Wednesday", Planet: PlanetMercury, Color: Color{Key: "green", NameTH: "เขียว", NameEN: "Green", Hex: "#43A047"}},
+	{Key: "wednesday_night", NameTH: "วันพุธ (กลางคืน)", NameEN: "Wednesday night", Planet: PlanetRahu, Color: Color{Key: "grey", NameTH: "เทา", NameEN: "Grey", Hex: "#757575"}},
+	{Key: "thursday", NameTH: "วันพฤหัสบดี", NameEN: "Thursday", Planet: PlanetJupiter, Color: Color{Key: "orange", NameTH: "ส้ม", NameEN: "Orange", Hex: "#FB8C00"}},
+	{Key: "friday", NameTH: "วันศุกร์", NameEN: "Friday", Planet: PlanetVenus, Color: Color{Key: "light_blue", NameTH: "ฟ้า", NameEN: "Light blue", Hex: "#29B6F6"}},
+	{Key: "saturday", NameTH: "วันเสาร์", NameEN: "Saturday", Planet: PlanetSaturn, Color: Color{Key: "purple", NameTH: "ม่วง", NameEN: "Purple", Hex: "#8E24AA"}},
+}
+
+// WednesdayNightFrom is the clock time from which a Wednesday birth counts as Wednesday night
+const WednesdayNightFrom = "18:00"
+
+// ThaksaPosition is one of the eight houses a planet takes relative to a birth day
+type ThaksaPosition struct {
+	Key    string `json:"key"`
+	NameTH string `json:"name_th"`
+	NameEN string `json:"name_en"`
+}
+
+// ThaksaPositions in order, starting from the birth day's own planet
+var ThaksaPositions = [8]ThaksaPosition{
+	{Key: "boriwan", NameTH: "บริวาร", NameEN: "Entourage"},
+	{Key: "ayu", NameTH: "อายุ", NameEN: "Longevity"},
+	{Key: "det", NameTH: "เดช", NameEN: "Power"},
+	{Key: "si", NameTH: "ศรี", NameEN: "Fortune"},
+	{Key: "mula", NameTH: "มูละ", NameEN: "Foundation"},
+	{Key: "utsaha", NameTH: "อุตสาหะ", NameEN: "Diligence"},
+	{Key: "montri", NameTH: "มนตรี", NameEN: "Patronage"},
+	{Key: "kalakini", NameTH: "กาลกิณี", NameEN: "Misfortune"},
+}
+
+// thaksaCircle is the order the planets take around the Thaksa wheel
+var thaksaCircle = [8]Planet{PlanetSun, PlanetMoon, PlanetMars, PlanetMercury, PlanetSaturn, PlanetJupiter, PlanetRahu, PlanetVenus}
+
+// DayByKey looks up an astrological day by its key
+func DayByKey(key string) (Day, bool) {
+	for _, day := range Days {
+		if day.Key == key {
+			return day, true
+		}
+	}
+	return Day{}, false
+}
+
+// DayForWeekday returns the daytime astrological day of a weekday
+func DayForWeekday(weekday time.Weekday) Day {
+	if weekday <= time.Wednesday {
+		return Days[weekday]
+	}
+	// Skip Wednesday night
+	return Days[weekday+1]
+}
+
+// BirthDay returns the astrological day of a birth date.
+// Births on Wednesday from WednesdayNightFrom onwards belong to Wednesday night;
+// birthTime is "15:04" and may be nil when unknown.
+func BirthDay(birthDate time.Time, birthTime *string) Day {
+	day := DayForWeekday(birthDate.Weekday())
+	if day.Planet == PlanetMercury && birthTime != nil && *birthTime >= WednesdayNightFrom {
+		night, _ := DayByKey("wednesday_night")
+		return night
+	}
+	return day
+}
+
+// dayForPlanet returns the astrological day ruled by a planet
+func dayForPlanet(planet Planet) Day {
+	for _, day := range Days {
+		if day.Planet == planet {
+			return day
+		}
+	}
+	return Days[0]
+}
+
+// circleIndex returns the position of a planet on the Thaksa wheel
+func circleIndex(planet Planet) int {
+	for index, candidate := range thaksaCircle {
+		if candidate == planet {
+			return index
+		}
+	}
+	return 0
+}
+
+// ThaksaPositionOf returns the position another day's planet takes for someone born on base
+func ThaksaPositionOf(base Day, other Day) ThaksaPosition {
+	steps := (circleIndex(other.Planet) - circleIndex(base.Planet) + len(thaksaCircle)) % len(thaksaCircle)
+	return ThaksaPositions[steps]
+}
+
+// ThaksaOf lists the day and colour holding each Thaksa position for a base day
+func ThaksaOf(base Day) []ThaksaEntry {
+	entries := make([]ThaksaEntry, 0, len(ThaksaPositions))
+	start := circleIndex(base.Planet)
+	for steps, position := range ThaksaPositions {
+		day := dayForPlanet(thaksaCircle[(start+steps)%len(thaksaCircle)])
+		entries = append(entries, ThaksaEntry{Position: position, Day: day.Key, Color: day.Color})
+	}
+	return entries
+}
+
+// DayColorTables returns the colour table of every weekday.
+// Wearing the colour of a favourable position on that day brings its blessing;
+// the กาลกิณี colour should be avoided.
+func DayColorTables() []DayColorTable {
+	tables := make([]DayColorTable, 0, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		day := DayForWeekday(weekday)
+		tables = append(tables, DayColorTable{Day: day, Colors: ThaksaOf(day)})
+	}
+	return tables
+}
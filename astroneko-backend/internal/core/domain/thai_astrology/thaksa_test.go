@@ -0,0 +1,109 @@
+package thai_astrology
+
+import (
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThaksaOf_Kalakini(t *testing.T) {
+	// The colour each birth day must avoid (กาลกิณี)
+	expected := map[string]string{
+		"sunday":          "friday",
+		"monday":          "sunday",
+		"tuesday":         "monday",
+		"wednesday":       "tuesday",
+		"wednesday_night": "thursday",
+		"thursday":        "saturday",
+		"friday":          "wednesday_night",
+		"saturday":        "wednesday",
+	}
+
+	for _, day := range Days {
+		entries := ThaksaOf(day)
+
+		assert.Len(t, entries, 8)
+		assert.Equal(t, "boriwan", entries[0].Position.Key)
+		assert.Equal(t, day.Key, entries[0].Day)
+		assert.Equal(t, "kalakini", entries[7].Position.Key)
+		assert.Equal(t, expected[day.Key], entries[7].Day, day.Key)
+	}
+}
+
+func TestThaksaOf_SundayTable(t *testing.T) {
+	// Act
+	entries := ThaksaOf(Days[0])
+
+	// Assert
+	got := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		got[entry.Position.Key] = entry.Color.Key
+	}
+	assert.Equal(t, map[string]string{
+		"boriwan":  "red",
+		"ayu":      "yellow",
+		"det":      "pink",
+		"si":       "green",
+		"mula":     "purple",
+		"utsaha":   "orange",
+		"montri":   "grey",
+		"kalakini": "light_blue",
+	}, got)
+}
+
+func TestThaksaPositionOf(t *testing.T) {
+	monday, _ := DayByKey("monday")
+	saturday, _ := DayByKey("saturday")
+
+	assert.Equal(t, "si", ThaksaPositionOf(monday, saturday).Key)
+	assert.Equal(t, "kalakini", ThaksaPositionOf(monday, Days[0]).Key)
+	assert.Equal(t, "boriwan", ThaksaPositionOf(monday, monday).Key)
+}
+
+func TestBirthDay(t *testing.T) {
+	wednesday := date(1990, 8, 15)
+	morning, evening := "09:15", "19:40"
+
+	assert.Equal(t, "wednesday", BirthDay(wednesday, nil).Key)
+	assert.Equal(t, "wednesday", BirthDay(wednesday, &morning).Key)
+	assert.Equal(t, "wednesday_night", BirthDay(wednesday, &evening).Key)
+	assert.Equal(t, "thursday", BirthDay(wednesday.AddDate(0, 0, 1), &evening).Key)
+}
+
+func TestDayColorTables(t *testing.T) {
+	// Act
+	tables := DayColorTables()
+
+	// Assert
+	assert.Len(t, tables, 7)
+	for index, table := range tables {
+		assert.Equal(t, DayForWeekday(time.Weekday(index)).Key, table.Day.Key)
+		assert.Len(t, table.Colors, 8)
+	}
+	assert.Equal(t, "red", tables[1].Colors[7].Color.Key)
+}
+
+func TestScoreDay(t *testing.T) {
+	monday, _ := DayByKey("monday")
+
+	// Saturday 2025-05-10 is ศรี for Monday-born, waxing 14th
+	best, err := ScoreDay(monday, date(2025, 5, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, "si", best.Position.Key)
+	assert.Equal(t, 85, best.Score)
+	assert.Equal(t, RatingExcellent, best.Rating)
+
+	// Sunday 2025-05-11 is กาลกิณี for Monday-born but Visakha Bucha
+	holiday, err := ScoreDay(monday, date(2025, 5, 11))
+	assert.NoError(t, err)
+	assert.Equal(t, "kalakini", holiday.Position.Key)
+	assert.Equal(t, 30, holiday.Score)
+	assert.Equal(t, RatingPoor, holiday.Rating)
+	assert.Len(t, holiday.Factors, 4)
+
+	_, err = ScoreDay(monday, date(1800, 1, 1))
+	assert.Equal(t, shared.ErrLunarDateOutOfRange, err)
+}
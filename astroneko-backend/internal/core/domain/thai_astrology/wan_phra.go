@@ -0,0 +1,77 @@
+package thai_astrology
+
+import (
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+)
+
+// Buddhist holidays observed on a full moon
+var (
+	ObservanceMakhaBucha   = Observance{Key: "makha_bucha", NameTH: "วันมาฆบูชา", NameEN: "Makha Bucha"}
+	ObservanceVisakhaBucha = Observance{Key: "visakha_bucha", NameTH: "วันวิสาขบูชา", NameEN: "Visakha Bucha"}
+	ObservanceAsarnhaBucha = Observance{Key: "asarnha_bucha", NameTH: "วันอาสาฬหบูชา", NameEN: "Asarnha Bucha"}
+	ObservanceOkPhansa     = Observance{Key: "ok_phansa", NameTH: "วันออกพรรษา", NameEN: "End of Buddhist Lent"}
+	ObservanceLoyKrathong  = Observance{Key: "loy_krathong", NameTH: "วันลอยกระทง", NameEN: "Loy Krathong"}
+)
+
+// ObservanceOf returns the Buddhist holiday on a lunar date, if any.
+// In an adhikamasa year Makha Bucha and Visakha Bucha move one month later
+// and Asarnha Bucha falls in the second eighth month.
+func ObservanceOf(lunar *LunarDate) *Observance {
+	if lunar.Phase != PhaseWaxing || lunar.Day != 15 {
+		return nil
+	}
+
+	leap := lunar.YearType == YearTypeAdhikamasa
+	shift := 0
+	if leap {
+		shift = 1
+	}
+
+	switch {
+	case lunar.Month == 3+shift:
+		return &ObservanceMakhaBucha
+	case lunar.Month == 6+shift:
+		return &ObservanceVisakhaBucha
+	case lunar.Month == 8 && lunar.SecondMonth == leap:
+		return &ObservanceAsarnhaBucha
+	case lunar.Month == 11:
+		return &ObservanceOkPhansa
+	case lunar.Month == 12:
+		return &ObservanceLoyKrathong
+	}
+	return nil
+}
+
+// WanPhraDays lists the Buddhist holy days in a Gregorian month
+func WanPhraDays(year int, month time.Month) ([]WanPhraDay, error) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if first.Before(MinSupportedDate) || first.After(MaxSupportedDate) {
+		return nil, shared.ErrLunarDateOutOfRange
+	}
+
+	days := make([]WanPhraDay, 0, 5)
+	for date := first; date.Month() == month; date = date.AddDate(0, 0, 1) {
+		lunar, err := ToLunarDate(date)
+		if err != nil {
+			return nil, err
+		}
+		if !lunar.IsWanPhra() {
+			continue
+		}
+
+		kind := WanPhraMajor
+		if lunar.Day == 8 {
+			kind = WanPhraMinor
+		}
+		days = append(days, WanPhraDay{
+			Date:       lunar.Date,
+			Kind:       kind,
+			Lunar:      *lunar,
+			Observance: ObservanceOf(lunar),
+		})
+	}
+
+	return days, nil
+}
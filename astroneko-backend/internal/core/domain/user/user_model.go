@@ -14,8 +14,85 @@ type User struct {
 	FirebaseUID         string     `json:"firebase_uid" gorm:"not null"`
 	ProfileImageURL     *string    `json:"profile_image_url"`
 	DisplayName         *string    `json:"display_name"`
+
+	// What the redeemed referral code granted, see Tier, plus bonus requests earned by inviting others
+	ReferralTier *string `json:"referral_tier" gorm:"type:varchar(32)"`
+	BonusQuota   int     `json:"bonus_quota" gorm:"not null;default:0"`
+	// RewardDailyReplies raises the daily request limit, earned by inviting others
+	RewardDailyReplies int `json:"reward_daily_replies" gorm:"not null;default:0"`
+
+	// Astrology birth profile
+	BirthDate      *time.Time `json:"birth_date" gorm:"type:date"`
+	BirthTime      *string    `json:"birth_time" gorm:"type:varchar(5)"`
+	BirthPlace     *string    `json:"birth_place"`
+	BirthLatitude  *float64   `json:"birth_latitude"`
+	BirthLongitude *float64   `json:"birth_longitude"`
+	BirthTimezone  *string    `json:"birth_timezone"`
+}
+
+// Columns that updates of a loaded user may write. The referral and quota columns are
+// changed by their own queries, so writing a whole loaded row back would undo any
+// change made to them since it was read.
+const (
+	ColumnIsActivatedReferral = "is_activated_referral"
+	ColumnLatestLoginAt       = "latest_login_at"
+	ColumnProfileImageURL     = "profile_image_url"
+	ColumnDisplayName         = "display_name"
+)
+
+// BirthProfileColumns are the columns set together by a birth profile update
+var BirthProfileColumns = []string{"birth_date", "birth_time", "birth_place", "birth_latitude", "birth_longitude", "birth_timezone"}
+
+// HasBirthProfile reports whether the user has set at least a birth date
+func (u *User) HasBirthProfile() bool {
+	return u.BirthDate != nil
 }
 
 func (User) TableName() string {
 	return "astroneko_auth_users"
 }
+
+// Tiers decide how many agent requests a signed-in user gets per day
+const (
+	TierFree      = "free"
+	TierPlus      = "plus"
+	TierUnlimited = "unlimited"
+)
+
+// tierDailyLimits are the limited tiers above the free daily allowance
+var tierDailyLimits = map[string]int{
+	TierPlus: 20,
+}
+
+// IsValidTier reports whether tier is one of the known tiers
+func IsValidTier(tier string) bool {
+	_, limited := tierDailyLimits[tier]
+	return limited || tier == TierFree || tier == TierUnlimited
+}
+
+// Tier returns the user's tier. Users who activated a referral before tiers
+// existed have no tier recorded and keep unlimited access.
+func (u *User) Tier() string {
+	if u.ReferralTier != nil {
+		return *u.ReferralTier
+	}
+	if u.IsActivatedReferral {
+		return TierUnlimited
+	}
+	return TierFree
+}
+
+// HasUnlimitedAccess reports whether the user skips the daily request limit
+func (u *User) HasUnlimitedAccess() bool {
+	return u.Tier() == TierUnlimited
+}
+
+// DailyRequestLimit returns the user's daily request limit, given the free allowance,
+// including the extra requests earned through referral rewards
+func (u *User) DailyRequestLimit(freeLimit int) int {
+	limit, ok := tierDailyLimits[u.Tier()]
+	if !ok {
+		limit = freeLimit
+	}
+	return limit + u.RewardDailyReplies
+}
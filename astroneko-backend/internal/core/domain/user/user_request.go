@@ -14,6 +14,12 @@ type UpdateUserRequest struct {
 	DisplayName         *string    `json:"display_name"`
 }
 
+// UpdateProfileRequest holds the fields users may change on their own account
+type UpdateProfileRequest struct {
+	ProfileImageURL *string `json:"profile_image_url" validate:"omitempty,url,max=2048"`
+	DisplayName     *string `json:"display_name" validate:"omitempty,min=1,max=100"`
+}
+
 type GetUserByFirebaseUIDRequest struct {
 	FirebaseUID string `json:"firebase_uid" validate:"required"`
 }
@@ -21,6 +27,8 @@ type GetUserByFirebaseUIDRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// ClientIP is filled in by the handler for failed attempt tracking
+	ClientIP string `json:"-"`
 }
 
 type RefreshTokenRequest struct {
@@ -35,3 +43,14 @@ type GoogleLoginRequest struct {
 type ActivateReferralRequest struct {
 	ReferralCode string `json:"referral_code" validate:"required"`
 }
+
+// UpdateBirthProfileRequest sets the astrology birth profile of the current user.
+// Birth time and coordinates are optional but required for rising sign and houses.
+type UpdateBirthProfileRequest struct {
+	BirthDate      string   `json:"birth_date" validate:"required,datetime=2006-01-02"`
+	BirthTime      *string  `json:"birth_time" validate:"omitempty,datetime=15:04"`
+	BirthPlace     *string  `json:"birth_place" validate:"omitempty,max=255"`
+	BirthLatitude  *float64 `json:"birth_latitude" validate:"required_with=BirthLongitude,omitempty,gte=-90,lte=90"`
+	BirthLongitude *float64 `json:"birth_longitude" validate:"required_with=BirthLatitude,omitempty,gte=-180,lte=180"`
+	BirthTimezone  *string  `json:"birth_timezone" validate:"omitempty,timezone"`
+}
@@ -7,15 +7,19 @@ import (
 )
 
 type UserResponse struct {
-	ID                  string     `json:"id"`
-	Email               string     `json:"email"`
-	IsActivatedReferral bool       `json:"is_activated_referral"`
-	LatestLoginAt       *time.Time `json:"latest_login_at"`
-	FirebaseUID         string     `json:"firebase_uid"`
-	ProfileImageURL     *string    `json:"profile_image_url"`
-	DisplayName         *string    `json:"display_name"`
-	CreatedAt           time.Time  `json:"created_at"`
-	UpdatedAt           time.Time  `json:"updated_at"`
+	ID                  string                `json:"id"`
+	Email               string                `json:"email"`
+	IsActivatedReferral bool                  `json:"is_activated_referral"`
+	Tier                string                `json:"tier"`
+	BonusQuota          int                   `json:"bonus_quota"`
+	RewardDailyReplies  int                   `json:"reward_daily_replies"`
+	LatestLoginAt       *time.Time            `json:"latest_login_at"`
+	FirebaseUID         string                `json:"firebase_uid"`
+	ProfileImageURL     *string               `json:"profile_image_url"`
+	DisplayName         *string               `json:"display_name"`
+	BirthProfile        *BirthProfileResponse `json:"birth_profile,omitempty"`
+	CreatedAt           time.Time             `json:"created_at"`
+	UpdatedAt           time.Time             `json:"updated_at"`
 }
 
 func (u *User) ToResponse() *UserResponse {
@@ -23,15 +27,44 @@ func (u *User) ToResponse() *UserResponse {
 		ID:                  u.ID.String(),
 		Email:               u.Email,
 		IsActivatedReferral: u.IsActivatedReferral,
+		Tier:                u.Tier(),
+		BonusQuota:          u.BonusQuota,
+		RewardDailyReplies:  u.RewardDailyReplies,
 		LatestLoginAt:       u.LatestLoginAt,
 		FirebaseUID:         u.FirebaseUID,
 		ProfileImageURL:     u.ProfileImageURL,
 		DisplayName:         u.DisplayName,
+		BirthProfile:        u.ToBirthProfileResponse(),
 		CreatedAt:           u.CreatedAt,
 		UpdatedAt:           u.UpdatedAt,
 	}
 }
 
+// BirthProfileResponse represents the astrology birth profile of a user
+type BirthProfileResponse struct {
+	BirthDate      string   `json:"birth_date"`
+	BirthTime      *string  `json:"birth_time"`
+	BirthPlace     *string  `json:"birth_place"`
+	BirthLatitude  *float64 `json:"birth_latitude"`
+	BirthLongitude *float64 `json:"birth_longitude"`
+	BirthTimezone  *string  `json:"birth_timezone"`
+}
+
+// ToBirthProfileResponse returns nil when the user has no birth profile
+func (u *User) ToBirthProfileResponse() *BirthProfileResponse {
+	if !u.HasBirthProfile() {
+		return nil
+	}
+	return &BirthProfileResponse{
+		BirthDate:      u.BirthDate.Format("2006-01-02"),
+		BirthTime:      u.BirthTime,
+		BirthPlace:     u.BirthPlace,
+		BirthLatitude:  u.BirthLatitude,
+		BirthLongitude: u.BirthLongitude,
+		BirthTimezone:  u.BirthTimezone,
+	}
+}
+
 type CreateUserResponse struct {
 	shared.ResponseBody
 }
@@ -66,3 +99,10 @@ type ActivateReferralResponse struct {
 type GetTotalUsersResponse struct {
 	TotalUsers int64 `json:"total_users"`
 }
+
+type ListUsersResponse struct {
+	Users  []UserResponse `json:"users"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
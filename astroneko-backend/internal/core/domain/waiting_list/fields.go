@@ -0,0 +1,85 @@
+package waiting_list
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on the extra fields of a join, so a list cannot be used as free storage
+const (
+	MaxFields         = 20
+	MaxFieldLength    = 500
+	MaxRequiredFields = 10
+)
+
+var (
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+)
+
+// ValidateSlug accepts lowercase words joined by single hyphens, up to 64 characters
+func ValidateSlug(slug string) error {
+	if len(slug) > 64 || !slugPattern.MatchString(slug) {
+		return fmt.Errorf("%w: slug %q must be lowercase letters and digits joined by single hyphens, at most 64 characters", ErrInvalidSettings, slug)
+	}
+	return nil
+}
+
+// ValidateRequiredFields checks the field names a list asks for
+func ValidateRequiredFields(names []string) error {
+	if len(names) > MaxRequiredFields {
+		return fmt.Errorf("%w: at most %d required fields are allowed", ErrInvalidSettings, MaxRequiredFields)
+	}
+	for _, name := range names {
+		if !fieldNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: required field %q must be lowercase snake_case, at most 32 characters", ErrInvalidSettings, name)
+		}
+	}
+	return nil
+}
+
+// CleanFields trims the values of a join's fields, drops the empty ones and checks what
+// is left against the field limits and the list's required fields
+func (l *WaitingList) CleanFields(fields map[string]string) (map[string]string, error) {
+	cleaned := make(map[string]string, len(fields))
+	for name, value := range fields {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !fieldNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: field name %q must be lowercase snake_case, at most 32 characters", ErrInvalidFields, name)
+		}
+		if utf8.RuneCountInString(value) > MaxFieldLength {
+			return nil, fmt.Errorf("%w: field %q is longer than %d characters", ErrInvalidFields, name, MaxFieldLength)
+		}
+		cleaned[name] = value
+	}
+	if len(cleaned) > MaxFields {
+		return nil, fmt.Errorf("%w: at most %d fields are allowed", ErrInvalidFields, MaxFields)
+	}
+
+	var missing []string
+	for _, name := range l.RequiredFields {
+		if _, ok := cleaned[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("%w: %s", ErrMissingFields, strings.Join(missing, ", "))
+	}
+	return cleaned, nil
+}
+
+// SpotsLeft is how many more entries the list takes, or nil when it has no capacity
+func (l *WaitingList) SpotsLeft(entries int64) *int64 {
+	if l.Capacity == nil {
+		return nil
+	}
+	left := max(int64(*l.Capacity)-entries, 0)
+	return &left
+}
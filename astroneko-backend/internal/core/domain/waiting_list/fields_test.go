@@ -0,0 +1,78 @@
+package waiting_list
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSlug(t *testing.T) {
+	for _, slug := range []string{"astroneko", "astro-boxing", "tarot-2027"} {
+		assert.NoError(t, ValidateSlug(slug), slug)
+	}
+	for _, slug := range []string{"", "Astro", "astro--boxing", "-astro", "astro_boxing", strings.Repeat("a", 65)} {
+		assert.ErrorIs(t, ValidateSlug(slug), ErrInvalidSettings, slug)
+	}
+}
+
+func TestWaitingList_CleanFields(t *testing.T) {
+	list := &WaitingList{RequiredFields: []string{"name", "country"}}
+
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:   "trims values and drops empty ones",
+			fields: map[string]string{"name": " Nok ", "country": "TH", "instagram": "  "},
+			want:   map[string]string{"name": "Nok", "country": "TH"},
+		},
+		{
+			name:    "blank required field is missing",
+			fields:  map[string]string{"name": "Nok", "country": " "},
+			wantErr: ErrMissingFields,
+		},
+		{
+			name:    "invalid field name",
+			fields:  map[string]string{"name": "Nok", "country": "TH", "Favourite Sign": "Leo"},
+			wantErr: ErrInvalidFields,
+		},
+		{
+			name:    "value too long",
+			fields:  map[string]string{"name": strings.Repeat("n", MaxFieldLength+1), "country": "TH"},
+			wantErr: ErrInvalidFields,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := list.CleanFields(tt.fields)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWaitingList_CleanFields_ListsMissingFields(t *testing.T) {
+	list := &WaitingList{RequiredFields: []string{"name", "country"}}
+
+	_, err := list.CleanFields(nil)
+
+	assert.EqualError(t, err, "required waiting list fields are missing: country, name")
+}
+
+func TestWaitingList_SpotsLeft(t *testing.T) {
+	capacity := 10
+
+	assert.Nil(t, (&WaitingList{}).SpotsLeft(3))
+	assert.Equal(t, int64(7), *(&WaitingList{Capacity: &capacity}).SpotsLeft(3))
+	assert.Equal(t, int64(0), *(&WaitingList{Capacity: &capacity}).SpotsLeft(12))
+}
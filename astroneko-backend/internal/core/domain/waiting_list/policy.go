@@ -0,0 +1,112 @@
+package waiting_list
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultStatusTokenTTL is how long status links work unless configured otherwise
+const DefaultStatusTokenTTL = 90 * 24 * time.Hour
+
+// Policy decides how long status links work and which email domains may not join
+type Policy struct {
+	// StatusTokenKey signs status tokens and must be shared by every instance
+	StatusTokenKey []byte
+	// StatusTokenTTL is how long a status token is accepted after joining
+	StatusTokenTTL time.Duration
+	// DisposableDomains are refused on top of the built-in list
+	DisposableDomains []string
+}
+
+// Validate checks the policy is usable
+func (p Policy) Validate() error {
+	if len(p.StatusTokenKey) == 0 {
+		return errors.New("status_token_key is required")
+	}
+	if p.StatusTokenTTL <= 0 {
+		return errors.New("status_token_ttl must be positive")
+	}
+	return nil
+}
+
+// IsDisposableEmail reports whether the email's domain, or a domain it is a subdomain of,
+// hands out throwaway inboxes
+func (p Policy) IsDisposableEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+	for domain != "" {
+		if _, ok := disposableDomains[domain]; ok {
+			return true
+		}
+		for _, extra := range p.DisposableDomains {
+			if strings.EqualFold(strings.TrimSpace(extra), domain) {
+				return true
+			}
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			break
+		}
+		domain = domain[dot+1:]
+	}
+	return false
+}
+
+// disposableDomains are well-known providers of temporary inboxes. The list is not meant to
+// be complete; add to it through the disposable_domains setting.
+var disposableDomains = map[string]struct{}{
+	"10minutemail.com":       {},
+	"10minutemail.net":       {},
+	"1secmail.com":           {},
+	"1secmail.net":           {},
+	"1secmail.org":           {},
+	"20minutemail.com":       {},
+	"burnermail.io":          {},
+	"discard.email":          {},
+	"dispostable.com":        {},
+	"emailfake.com":          {},
+	"emailondeck.com":        {},
+	"fakeinbox.com":          {},
+	"fakemail.net":           {},
+	"getnada.com":            {},
+	"grr.la":                 {},
+	"guerrillamail.biz":      {},
+	"guerrillamail.com":      {},
+	"guerrillamail.de":       {},
+	"guerrillamail.net":      {},
+	"guerrillamail.org":      {},
+	"guerrillamailblock.com": {},
+	"inboxkitten.com":        {},
+	"mailcatch.com":          {},
+	"maildrop.cc":            {},
+	"mailinator.com":         {},
+	"mailnesia.com":          {},
+	"mailpoof.com":           {},
+	"minuteinbox.com":        {},
+	"mintemail.com":          {},
+	"moakt.com":              {},
+	"mohmal.com":             {},
+	"mytemp.email":           {},
+	"pokemail.net":           {},
+	"sharklasers.com":        {},
+	"spam4.me":               {},
+	"spamgourmet.com":        {},
+	"temp-mail.io":           {},
+	"temp-mail.org":          {},
+	"tempail.com":            {},
+	"tempmail.com":           {},
+	"tempmailo.com":          {},
+	"tempr.email":            {},
+	"throwawaymail.com":      {},
+	"tmpmail.net":            {},
+	"tmpmail.org":            {},
+	"trash-mail.com":         {},
+	"trashmail.com":          {},
+	"yopmail.com":            {},
+	"yopmail.fr":             {},
+	"yopmail.net":            {},
+}
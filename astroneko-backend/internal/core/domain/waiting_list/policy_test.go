@@ -0,0 +1,18 @@
+package waiting_list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_IsDisposableEmail(t *testing.T) {
+	policy := Policy{DisposableDomains: []string{"Burner.Example"}}
+
+	for _, email := range []string{"fan@mailinator.com", "fan@YOPMAIL.com", "fan@eu.guerrillamail.com", "fan@burner.example"} {
+		assert.True(t, policy.IsDisposableEmail(email), email)
+	}
+	for _, email := range []string{"fan@gmail.com", "fan@notmailinator.com", "fan@mailinator.com.example.org", "not-an-email"} {
+		assert.False(t, policy.IsDisposableEmail(email), email)
+	}
+}
@@ -0,0 +1,101 @@
+package waiting_list
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+// DefaultReferralBoost is the boost of lists created without one
+const DefaultReferralBoost = 5
+
+// MaxReferralBoost bounds the boost, so a handful of referrals cannot empty the queue ahead
+const MaxReferralBoost = 1000
+
+// InviteCodeLength is the length of new invite codes. Codes drawn from inviteCodeCharset
+// leave out 0/O and 1/I/L, which are easily mistyped when read off a screen.
+const InviteCodeLength = 10
+
+const inviteCodeCharset = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// NewInviteCode draws a random invite code with crypto/rand
+func NewInviteCode() (string, error) {
+	code := make([]byte, InviteCodeLength)
+	for i := range code {
+		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteCodeCharset))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteCodeCharset[num.Int64()]
+	}
+	return string(code), nil
+}
+
+// NormalizeInviteCode uppercases a typed or pasted code
+func NormalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Join is an email joining a list, with where the request came from
+type Join struct {
+	Email  string
+	Fields map[string]string
+	// InviteCode is the code of the invite link the joiner followed, if any
+	InviteCode string
+	// Fingerprint identifies the joiner's device; see utils.DeviceFingerprint
+	Fingerprint string
+}
+
+// QueuePosition is an entry's place in its list, 1 being the front
+type QueuePosition struct {
+	Position int64
+	Total    int64
+}
+
+// ValidateReferralBoost checks a list's referral boost
+func ValidateReferralBoost(boost int) error {
+	if boost < 0 || boost > MaxReferralBoost {
+		return fmt.Errorf("%w: referral_boost must be between 0 and %d", ErrInvalidSettings, MaxReferralBoost)
+	}
+	return nil
+}
+
+// ValidateLandingURL accepts an empty URL, which leaves links out, or an absolute http(s) URL
+func ValidateLandingURL(landingURL string) error {
+	if landingURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(landingURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: landing_url must be an absolute http or https URL", ErrInvalidSettings)
+	}
+	return nil
+}
+
+// InviteLink is the list's landing page with the invite code as the invite parameter, or
+// empty when the list has no landing page
+func (l *WaitingList) InviteLink(inviteCode string) string {
+	return l.landingLink("invite", inviteCode)
+}
+
+// StatusLink is the list's landing page with the status token as the status_token
+// parameter, or empty when the list has no landing page
+func (l *WaitingList) StatusLink(token string) string {
+	return l.landingLink("status_token", token)
+}
+
+func (l *WaitingList) landingLink(key, value string) string {
+	if l.LandingURL == "" {
+		return ""
+	}
+	landing, err := url.Parse(l.LandingURL)
+	if err != nil {
+		return ""
+	}
+	values := landing.Query()
+	values.Set(key, value)
+	landing.RawQuery = values.Encode()
+	return landing.String()
+}
@@ -0,0 +1,34 @@
+package waiting_list
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInviteCode(t *testing.T) {
+	code, err := NewInviteCode()
+
+	require.NoError(t, err)
+	assert.Len(t, code, InviteCodeLength)
+	for _, char := range code {
+		assert.True(t, strings.ContainsRune(inviteCodeCharset, char), "unexpected character %q", char)
+	}
+}
+
+func TestWaitingList_Links(t *testing.T) {
+	list := &WaitingList{LandingURL: "https://astrofight.ai/join?utm_source=email"}
+
+	assert.Equal(t, "https://astrofight.ai/join?invite=ABCDEFGH23&utm_source=email", list.InviteLink("ABCDEFGH23"))
+	assert.Equal(t, "https://astrofight.ai/join?status_token=a.b.c&utm_source=email", list.StatusLink("a.b.c"))
+	assert.Empty(t, (&WaitingList{}).InviteLink("ABCDEFGH23"))
+}
+
+func TestValidateLandingURL(t *testing.T) {
+	assert.NoError(t, ValidateLandingURL(""))
+	assert.NoError(t, ValidateLandingURL("https://astroneko.com/waitlist"))
+	assert.ErrorIs(t, ValidateLandingURL("astroneko.com/waitlist"), ErrInvalidSettings)
+	assert.ErrorIs(t, ValidateLandingURL("javascript:alert(1)"), ErrInvalidSettings)
+}
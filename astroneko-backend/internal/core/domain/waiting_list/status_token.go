@@ -0,0 +1,53 @@
+package waiting_list
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignStatusToken signs a token that shows the entry's place in its list until expiresAt.
+// The token is "{entry id}.{expiry unix}.{signature}".
+func SignStatusToken(key []byte, entryID uuid.UUID, expiresAt time.Time) string {
+	payload := statusPayload(entryID, expiresAt.Unix())
+	return payload + "." + statusSignature(key, payload)
+}
+
+// VerifyStatusToken returns the entry a token was signed for, or ErrInvalidStatusToken when
+// it is malformed, forged or expired
+func VerifyStatusToken(key []byte, token string, now time.Time) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(key) == 0 || len(parts) != 3 {
+		return uuid.Nil, ErrInvalidStatusToken
+	}
+	entryID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, ErrInvalidStatusToken
+	}
+	expiresUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expiresUnix {
+		return uuid.Nil, ErrInvalidStatusToken
+	}
+
+	expected, _ := hex.DecodeString(statusSignature(key, statusPayload(entryID, expiresUnix)))
+	given, err := hex.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(expected, given) {
+		return uuid.Nil, ErrInvalidStatusToken
+	}
+	return entryID, nil
+}
+
+func statusPayload(entryID uuid.UUID, expiresUnix int64) string {
+	return entryID.String() + "." + strconv.FormatInt(expiresUnix, 10)
+}
+
+func statusSignature(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("waiting_list_status." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
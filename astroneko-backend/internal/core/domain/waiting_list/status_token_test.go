@@ -0,0 +1,38 @@
+package waiting_list
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusToken(t *testing.T) {
+	key := []byte("status-token-key")
+	entryID := uuid.New()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	token := SignStatusToken(key, entryID, now.Add(time.Hour))
+
+	got, err := VerifyStatusToken(key, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, entryID, got)
+
+	otherEntry := SignStatusToken(key, uuid.New(), now.Add(time.Hour))
+	tampered := entryID.String() + otherEntry[36:]
+	for name, token := range map[string]string{
+		"expired":      SignStatusToken(key, entryID, now.Add(-time.Second)),
+		"other key":    SignStatusToken([]byte("another key"), entryID, now.Add(time.Hour)),
+		"tampered":     tampered,
+		"malformed":    "not-a-token",
+		"empty":        "",
+		"bad encoding": entryID.String() + ".1.zz",
+	} {
+		_, err := VerifyStatusToken(key, token, now)
+		assert.ErrorIs(t, err, ErrInvalidStatusToken, name)
+	}
+
+	_, err = VerifyStatusToken(nil, token, now)
+	assert.ErrorIs(t, err, ErrInvalidStatusToken, "no key")
+}
@@ -1,13 +1,84 @@
 package waiting_list
 
 import (
+	"errors"
+	"time"
+
 	"astroneko-backend/internal/core/domain/shared"
 	"github.com/google/uuid"
 )
 
+// Slugs of the lists the original per-product endpoints are aliases for
+const (
+	DefaultListSlug     = "astroneko"
+	AstroBoxingListSlug = "astro-boxing"
+)
+
+// Reasons a join or list change is refused
+var (
+	ErrListNotFound       = errors.New("waiting list not found")
+	ErrEntryNotFound      = errors.New("waiting list entry not found")
+	ErrListClosed         = errors.New("waiting list is closed")
+	ErrListFull           = errors.New("waiting list is full")
+	ErrMissingFields      = errors.New("required waiting list fields are missing")
+	ErrInvalidFields      = errors.New("waiting list fields are invalid")
+	ErrListAlreadyExists  = errors.New("waiting list already exists")
+	ErrInvalidSettings    = errors.New("waiting list settings are invalid")
+	ErrDisposableEmail    = errors.New("disposable email addresses cannot join waiting lists")
+	ErrInvalidStatusToken = errors.New("waiting list status token is invalid or expired")
+)
+
+// WaitingList is one product's waiting list. Emails are unique per list.
+type WaitingList struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Slug   string    `json:"slug" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Name   string    `json:"name" gorm:"type:varchar(255);not null"`
+	IsOpen bool      `json:"is_open" gorm:"not null"`
+	// Capacity caps the number of entries; nil means unlimited
+	Capacity *int `json:"capacity"`
+	// RequiredFields must be present and non-empty in the fields of every join
+	RequiredFields []string `json:"required_fields" gorm:"type:jsonb;serializer:json;not null"`
+	// ReferralBoost is how many places an entry moves up for each person who joins through
+	// its invite link; 0 keeps the list in joining order
+	ReferralBoost int `json:"referral_boost" gorm:"not null"`
+	// LandingURL is the product page invite and status links open; without it the join
+	// response and email carry the invite code but no links
+	LandingURL string    `json:"landing_url" gorm:"type:varchar(512);not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (WaitingList) TableName() string {
+	return "astroneko_waiting_lists"
+}
+
+// Columns of the settings an update of a waiting list may write
+const (
+	ColumnName           = "name"
+	ColumnIsOpen         = "is_open"
+	ColumnCapacity       = "capacity"
+	ColumnRequiredFields = "required_fields"
+	ColumnReferralBoost  = "referral_boost"
+	ColumnLandingURL     = "landing_url"
+)
+
+// WaitingListUser is one email on a waiting list, with the extra fields given when joining.
+// Entries are queued by QueueNumber, less the boost earned by their referrals.
 type WaitingListUser struct {
 	shared.NoDeletedModel
-	Email string `json:"email" gorm:"not null"`
+	ListID uuid.UUID         `json:"list_id" gorm:"type:uuid;not null"`
+	Email  string            `json:"email" gorm:"not null"`
+	Fields map[string]string `json:"fields" gorm:"type:jsonb;serializer:json"`
+	// QueueNumber is the order of joining within the list, starting at 1
+	QueueNumber int64 `json:"queue_number" gorm:"not null"`
+	// InviteCode identifies the entry's invite link
+	InviteCode string `json:"invite_code" gorm:"type:varchar(32);not null;uniqueIndex"`
+	// ReferredByID is the entry whose invite link was followed, when the referral was credited
+	ReferredByID *uuid.UUID `json:"referred_by_id" gorm:"type:uuid"`
+	// ReferralCount is the number of credited referrals
+	ReferralCount int `json:"referral_count" gorm:"not null"`
+	// Fingerprint is a hash of the joiner's IP address and browser, kept to spot self-referrals
+	Fingerprint string `json:"-" gorm:"type:varchar(64);not null"`
 }
 
 func (w *WaitingListUser) SetID(id uuid.UUID) {
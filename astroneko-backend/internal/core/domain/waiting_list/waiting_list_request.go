@@ -2,8 +2,37 @@ package waiting_list
 
 type JoinWaitingListRequest struct {
 	Email string `json:"email" validate:"required,email"`
+	// Fields holds the list's required fields and any other details the product asks for
+	Fields map[string]string `json:"fields,omitempty"`
+	// InviteCode is the invite parameter of the link the joiner followed
+	InviteCode string `json:"invite_code,omitempty" validate:"omitempty,max=32"`
 }
 
 type CheckWaitingListRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
+
+// CreateWaitingListRequest opens a new list. Lists are open unless is_open is false, take
+// any number of entries unless capacity is set, and boost referrers by DefaultReferralBoost
+// places unless referral_boost is set.
+type CreateWaitingListRequest struct {
+	Slug           string   `json:"slug" validate:"required,max=64"`
+	Name           string   `json:"name" validate:"required,max=255"`
+	IsOpen         *bool    `json:"is_open"`
+	Capacity       *int     `json:"capacity" validate:"omitempty,min=1"`
+	RequiredFields []string `json:"required_fields"`
+	ReferralBoost  *int     `json:"referral_boost" validate:"omitempty,min=0"`
+	LandingURL     string   `json:"landing_url" validate:"omitempty,max=512"`
+}
+
+// UpdateWaitingListRequest changes the settings that are present. A capacity of 0 removes
+// the cap. Entries already on the list are kept when the settings tighten.
+type UpdateWaitingListRequest struct {
+	Name           *string   `json:"name" validate:"omitempty,max=255"`
+	IsOpen         *bool     `json:"is_open"`
+	Capacity       *int      `json:"capacity" validate:"omitempty,min=0"`
+	RequiredFields *[]string `json:"required_fields"`
+	ReferralBoost  *int      `json:"referral_boost" validate:"omitempty,min=0"`
+	// LandingURL set to an empty string removes the links
+	LandingURL *string `json:"landing_url" validate:"omitempty,max=512"`
+}
@@ -6,6 +6,9 @@ import (
 
 type WaitingListUserResponse struct {
 	shared.EmailResponse
+	Fields        map[string]string `json:"fields,omitempty"`
+	InviteCode    string            `json:"invite_code"`
+	ReferralCount int               `json:"referral_count"`
 }
 
 func (w *WaitingListUser) ToResponse() *WaitingListUserResponse {
@@ -16,9 +19,31 @@ func (w *WaitingListUser) ToResponse() *WaitingListUserResponse {
 			w.CreatedAt,
 			w.UpdatedAt,
 		),
+		Fields:        w.Fields,
+		InviteCode:    w.InviteCode,
+		ReferralCount: w.ReferralCount,
 	}
 }
 
+// JoinedWaitingListResponse is the entry that just joined with its place in the list
+type JoinedWaitingListResponse struct {
+	WaitingListUserResponse
+	Position int64 `json:"position"`
+	Total    int64 `json:"total"`
+	// InviteLink is omitted for lists without a landing page
+	InviteLink string `json:"invite_link,omitempty"`
+}
+
+// WaitingListStatusResponse is what the holder of a status token sees of their entry
+type WaitingListStatusResponse struct {
+	List          string `json:"list"`
+	Position      int64  `json:"position"`
+	Total         int64  `json:"total"`
+	ReferralCount int    `json:"referral_count"`
+	InviteCode    string `json:"invite_code"`
+	InviteLink    string `json:"invite_link,omitempty"`
+}
+
 type JoinWaitingListResponse struct {
 	shared.ResponseBody
 }
@@ -36,3 +61,32 @@ func NewIsInWaitingListResponse(isInWaitingList bool) *IsInWaitingListResponse {
 		StatusResponse: *shared.NewStatusResponse(true, message),
 	}
 }
+
+// CheckWaitingListResponse is the answer of the GET check endpoints
+type CheckWaitingListResponse struct {
+	IsInWaitingList bool `json:"is_in_waiting_list"`
+}
+
+// WaitingListInfoResponse is what the public sees of a list before joining
+type WaitingListInfoResponse struct {
+	Slug           string   `json:"slug"`
+	Name           string   `json:"name"`
+	IsOpen         bool     `json:"is_open"`
+	RequiredFields []string `json:"required_fields"`
+	// SpotsLeft is omitted for lists without a capacity
+	SpotsLeft *int64 `json:"spots_left,omitempty"`
+}
+
+// WaitingListResponse is a list with its settings and entry count, for the CRM
+type WaitingListResponse struct {
+	WaitingList
+	EntryCount int64  `json:"entry_count"`
+	SpotsLeft  *int64 `json:"spots_left,omitempty"`
+}
+
+type WaitingListEntriesResponse struct {
+	Entries []*WaitingListUserResponse `json:"entries"`
+	Total   int64                      `json:"total"`
+	Limit   int                        `json:"limit"`
+	Offset  int                        `json:"offset"`
+}
@@ -0,0 +1,27 @@
+package account_deletion
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for account deletion data operations
+type RepositoryInterface interface {
+	Create(ctx context.Context, request *account_deletion.DeletionRequest) error
+	Update(ctx context.Context, request *account_deletion.DeletionRequest) error
+	// GetActiveByUserID returns nil without error when the user has no pending deletion
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*account_deletion.DeletionRequest, error)
+	// ListDue returns scheduled deletions whose grace period ended before now
+	ListDue(ctx context.Context, now time.Time, limit int) ([]account_deletion.DeletionRequest, error)
+	// ListAwaitingFirebase returns purged deletions whose Firebase account is not deleted yet
+	ListAwaitingFirebase(ctx context.Context, limit int) ([]account_deletion.DeletionRequest, error)
+	AddAuditEvent(ctx context.Context, event *account_deletion.AuditEvent) error
+
+	// PurgeUserData removes or anonymizes every row the user owns, marks the request
+	// purged and records the audit event in a single transaction
+	PurgeUserData(ctx context.Context, request *account_deletion.DeletionRequest) (*account_deletion.PurgeSummary, error)
+}
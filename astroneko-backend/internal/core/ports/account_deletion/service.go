@@ -0,0 +1,21 @@
+package account_deletion
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/user"
+)
+
+// ServiceInterface defines the contract for account deletion business logic
+type ServiceInterface interface {
+	// RequestDeletion issues a confirmation token, or schedules the deletion when the token is given
+	RequestDeletion(ctx context.Context, u *user.User, req *account_deletion.DeleteAccountRequest) (*account_deletion.DeletionStatusResponse, error)
+	GetDeletionStatus(ctx context.Context, u *user.User) (*account_deletion.DeletionStatusResponse, error)
+	CancelDeletion(ctx context.Context, u *user.User) error
+	// ScheduleImmediateDeletion skips confirmation and the grace period; for administrators only
+	ScheduleImmediateDeletion(ctx context.Context, u *user.User, detail string) (*account_deletion.DeletionStatusResponse, error)
+
+	// ProcessDueDeletions purges accounts whose grace period ended and retries Firebase deletions
+	ProcessDueDeletions(ctx context.Context) error
+}
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/audit"
+)
+
+// RepositoryInterface defines the contract for audit log data operations
+type RepositoryInterface interface {
+	// Create appends the entry to the hash chain, setting its sequence and hashes
+	Create(ctx context.Context, entry *audit.Entry) error
+	List(ctx context.Context, filter audit.ListFilter) ([]*audit.Entry, int64, error)
+	// ListChain returns up to limit entries after the given sequence, oldest first
+	ListChain(ctx context.Context, afterSequence int64, limit int) ([]*audit.Entry, error)
+}
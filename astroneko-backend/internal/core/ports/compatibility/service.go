@@ -0,0 +1,14 @@
+package compatibility
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/compatibility"
+	"astroneko-backend/internal/core/domain/user"
+)
+
+// ServiceInterface defines the contract for compatibility readings
+type ServiceInterface interface {
+	// CreateReading compares the user with a saved person and asks the agent to interpret it
+	CreateReading(ctx context.Context, u *user.User, req *compatibility.ReadingRequest) (*compatibility.ReadingResponse, error)
+}
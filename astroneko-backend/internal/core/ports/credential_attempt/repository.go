@@ -0,0 +1,24 @@
+package credential_attempt
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/credential_attempt"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for failed credential attempt counters
+type RepositoryInterface interface {
+	// Get returns the counter for a key, or nil when the key has no recent failures
+	Get(ctx context.Context, scope, keyType, keyHash string) (*credential_attempt.Attempt, error)
+	// RecordFailure atomically adds a failure to the key's counter, starting over
+	// when the previous failure is older than windowStart, and returns the counter
+	RecordFailure(ctx context.Context, scope, keyType, keyHash string, now, windowStart time.Time) (*credential_attempt.Attempt, error)
+	LockUntil(ctx context.Context, id uuid.UUID, until time.Time) error
+	// Reset deletes the key's counter and reports whether there was one
+	Reset(ctx context.Context, scope, keyType, keyHash string) (bool, error)
+	// DeleteStale removes counters with no failure since before that are not locked
+	DeleteStale(ctx context.Context, before time.Time, now time.Time) error
+}
@@ -0,0 +1,13 @@
+package credential_attempt
+
+import "context"
+
+// ServiceInterface is the credential attempt tracker used by every endpoint that checks a secret
+type ServiceInterface interface {
+	// Check returns a *credential_attempt.BlockedError while the account or the IP is blocked
+	Check(ctx context.Context, scope, account, clientIP string) error
+	RecordFailure(ctx context.Context, scope, account, clientIP string) error
+	RecordSuccess(ctx context.Context, scope, account string) error
+	// Unlock clears the account's counter and reports whether it had one
+	Unlock(ctx context.Context, scope, account string) (bool, error)
+}
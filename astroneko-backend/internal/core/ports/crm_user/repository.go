@@ -2,14 +2,45 @@ package crm_user
 
 import (
 	"context"
+	"time"
 
 	"astroneko-backend/internal/core/domain/crm_user"
+
+	"github.com/google/uuid"
 )
 
 type RepositoryInterface interface {
 	Create(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMUser, error)
 	GetByID(ctx context.Context, id string) (*crm_user.CRMUser, error)
 	GetByUsername(ctx context.Context, username string) (*crm_user.CRMUser, error)
-	Update(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMUser, error)
+	// Update writes only the named columns of the user, see the crm_user.Column constants
+	Update(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error)
+	// UpdateAndRevokeSessions writes the named columns and bumps the session version in one
+	// transaction, then refreshes the user from the updated row
+	UpdateAndRevokeSessions(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error)
 	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*crm_user.CRMUser, int64, error)
+
+	// Roles
+	ListRoles(ctx context.Context) ([]crm_user.Role, error)
+	SetRoles(ctx context.Context, userID uuid.UUID, roles []string, grantedBy *uuid.UUID) error
+	CountByRole(ctx context.Context, role string) (int64, error)
+
+	// Password resets
+	CreatePasswordReset(ctx context.Context, reset *crm_user.PasswordReset) error
+	GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*crm_user.PasswordReset, error)
+	ConsumePasswordReset(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+
+	// Sessions
+	CreateRefreshToken(ctx context.Context, token *crm_user.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*crm_user.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, currentID uuid.UUID, next *crm_user.RefreshToken, now time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error
+	RevokeAccessToken(ctx context.Context, revoked *crm_user.RevokedAccessToken) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+
+	// Two-factor authentication
+	RecordTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []crm_user.RecoveryCode) error
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) (bool, error)
 }
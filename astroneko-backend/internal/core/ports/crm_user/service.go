@@ -3,12 +3,35 @@ package crm_user
 import (
 	"context"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/crm_user"
+
+	"github.com/google/uuid"
 )
 
 type ServiceInterface interface {
 	CreateUser(ctx context.Context, req *crm_user.CreateCRMUserRequest) (*crm_user.CRMUser, error)
 	Login(ctx context.Context, req *crm_user.CRMLoginRequest) (*crm_user.CRMLoginResponse, error)
 	GetUserByID(ctx context.Context, id string) (*crm_user.CRMUser, error)
-	ValidateToken(token string) (*crm_user.JWTClaims, error)
+	ValidateToken(ctx context.Context, token string) (*crm_user.JWTClaims, error)
+	ListRoles(ctx context.Context) ([]crm_user.Role, error)
+	AssignRoles(ctx context.Context, actorID uuid.UUID, userID string, roles []string) (*crm_user.CRMUser, error)
+	BootstrapAdmin(ctx context.Context, req *crm_user.BootstrapAdminRequest) (*crm_user.CRMUser, error)
+	Authenticate(ctx context.Context, token string) (*crm_user.CRMUser, *crm_user.JWTClaims, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*crm_user.CRMUser, int64, error)
+	SetDisabled(ctx context.Context, actorID uuid.UUID, userID string, disabled bool) (*crm_user.CRMUser, error)
+	ChangePassword(ctx context.Context, userID string, req *crm_user.ChangePasswordRequest) (*crm_user.CRMLoginResponse, error)
+	IssuePasswordReset(ctx context.Context, actorID uuid.UUID, userID string) (*crm_user.PasswordResetResponse, error)
+	ResetPassword(ctx context.Context, req *crm_user.ResetPasswordRequest) error
+	Refresh(ctx context.Context, req *crm_user.RefreshTokenRequest) (*crm_user.CRMLoginResponse, error)
+	Logout(ctx context.Context, claims *crm_user.JWTClaims, refreshToken string) error
+	VerifyTwoFactorLogin(ctx context.Context, req *crm_user.TwoFactorLoginRequest) (*crm_user.CRMLoginResponse, error)
+	SetupTwoFactor(ctx context.Context, user *crm_user.CRMUser) (*crm_user.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, user *crm_user.CRMUser, code string) ([]string, error)
+	SetupTwoFactorForLogin(ctx context.Context, req *crm_user.TwoFactorChallengeRequest) (*crm_user.TwoFactorSetupResponse, error)
+	EnableTwoFactorForLogin(ctx context.Context, req *crm_user.TwoFactorEnrollRequest) (*crm_user.CRMLoginResponse, error)
+	DisableTwoFactor(ctx context.Context, user *crm_user.CRMUser, req *crm_user.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, user *crm_user.CRMUser, code string) ([]string, error)
+	ResetTwoFactor(ctx context.Context, actorID uuid.UUID, userID string) (*crm_user.CRMUser, error)
+	UnlockUser(ctx context.Context, actorID uuid.UUID, userID string) (*credential_attempt.UnlockResponse, error)
 }
@@ -0,0 +1,35 @@
+package data_export
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/data_export"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for data export job operations.
+// Only SaveArchive and GetWithArchive touch the stored ZIP.
+type RepositoryInterface interface {
+	Create(ctx context.Context, job *data_export.ExportJob) error
+	// Update saves the job's fields except the archive
+	Update(ctx context.Context, job *data_export.ExportJob) error
+	// SaveArchive saves the job together with its archive
+	SaveArchive(ctx context.Context, job *data_export.ExportJob) error
+	// GetByID returns shared.ErrExportNotFound unless the job belongs to the user
+	GetByID(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (*data_export.ExportJob, error)
+	// GetWithArchive returns the job and its archive, or shared.ErrExportNotFound
+	GetWithArchive(ctx context.Context, jobID uuid.UUID) (*data_export.ExportJob, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]data_export.ExportJob, error)
+	CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	// WithUserLock runs fn in a transaction that holds a lock on the user's export jobs, so
+	// concurrent requests from one user are checked and queued one at a time
+	WithUserLock(ctx context.Context, userID uuid.UUID, fn func(tx RepositoryInterface) error) error
+
+	// ClaimPending marks up to limit pending jobs, and processing jobs started before
+	// staleBefore, as processing and returns them. Jobs claimed by another instance are skipped.
+	ClaimPending(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]data_export.ExportJob, error)
+	// ExpireArchives removes archives past their expiry and returns how many were removed
+	ExpireArchives(ctx context.Context, now time.Time) (int64, error)
+}
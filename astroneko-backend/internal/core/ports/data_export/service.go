@@ -0,0 +1,24 @@
+package data_export
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/data_export"
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// ServiceInterface defines the contract for personal data export business logic
+type ServiceInterface interface {
+	// RequestExport queues an export, or returns the one already queued
+	RequestExport(ctx context.Context, u *user.User) (*data_export.ExportJobResponse, error)
+	// GetExport returns the job with a fresh signed download link once it is ready
+	GetExport(ctx context.Context, u *user.User, jobID uuid.UUID) (*data_export.ExportJobResponse, error)
+	ListExports(ctx context.Context, u *user.User) (*data_export.ListExportJobsResponse, error)
+	// OpenDownload checks a signed link and returns the job with its archive
+	OpenDownload(ctx context.Context, jobID uuid.UUID, expires int64, signature string) (*data_export.ExportJob, error)
+
+	// ProcessPendingExports builds the archives of queued jobs and removes expired ones
+	ProcessPendingExports(ctx context.Context) error
+}
@@ -11,6 +11,8 @@ type DatabaseInterface interface {
 	Begin() DatabaseInterface
 	Commit() error
 	Rollback() error
+	// Error reports a failure of the call that returned this instance, such as Begin
+	Error() error
 
 	// CRUD operations
 	Create(value any) error
@@ -23,6 +23,9 @@ type Repository interface {
 	// GetByIPAddress gets all usage records for an IP (for abuse detection)
 	GetByIPAddress(ctx context.Context, ipAddress string, since string) ([]*guest_usage.GuestAPIUsage, error)
 
+	// ListByCompositeKey gets every usage record for a composite key across endpoints and windows
+	ListByCompositeKey(ctx context.Context, compositeKey string) ([]*guest_usage.GuestAPIUsage, error)
+
 	// BlockGuest blocks a guest from making requests
 	BlockGuest(ctx context.Context, compositeKey string, reason string) error
 
@@ -15,7 +15,11 @@ type RepositoryInterface interface {
 	GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*history.Session, error)
 	ValidateSessionOwnership(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (bool, error)
 	DeleteSession(ctx context.Context, sessionID uuid.UUID) error
+	// CreateSessionWithMessages saves a session and its messages in one transaction
+	CreateSessionWithMessages(ctx context.Context, session *history.Session, messages []history.Message) error
 
 	// Message operations
 	GetMessagesBySessionID(ctx context.Context, sessionID uuid.UUID, sortOrder string) ([]history.Message, error)
+	GetMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]history.Message, error)
+	GetMessageStatsByUserID(ctx context.Context, userID uuid.UUID) (*history.MessageStats, error)
 }
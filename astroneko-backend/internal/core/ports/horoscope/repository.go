@@ -0,0 +1,17 @@
+package horoscope
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/horoscope"
+)
+
+// RepositoryInterface defines the contract for daily horoscope data operations
+type RepositoryInterface interface {
+	// GetBySignAndDate returns nil without error when no horoscope exists yet
+	GetBySignAndDate(ctx context.Context, sign string, date time.Time) (*horoscope.DailyHoroscope, error)
+	ListByDate(ctx context.Context, date time.Time) ([]horoscope.DailyHoroscope, error)
+	// Upsert inserts the horoscope or replaces the content of an existing one
+	Upsert(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error
+}
@@ -0,0 +1,17 @@
+package horoscope
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/horoscope"
+)
+
+// ServiceInterface defines the contract for daily horoscope business logic
+type ServiceInterface interface {
+	// GetDailyHoroscope serves a stored horoscope, generating it on demand when missing
+	GetDailyHoroscope(ctx context.Context, sign string, date time.Time) (*horoscope.DailyHoroscopeResponse, error)
+
+	// GenerateDailyHoroscopes generates all missing signs for a date
+	GenerateDailyHoroscopes(ctx context.Context, date time.Time) error
+}
@@ -0,0 +1,16 @@
+package insight
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/insight"
+
+	"github.com/google/uuid"
+)
+
+// ServiceInterface defines the contract for reading insight business logic
+type ServiceInterface interface {
+	// GetUserInsights returns aggregated card statistics for the user.
+	// Results are cached per user until new messages arrive.
+	GetUserInsights(ctx context.Context, userID uuid.UUID) (*insight.UserInsightsResponse, error)
+}
@@ -0,0 +1,20 @@
+package person
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/person"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for saved people data operations
+type RepositoryInterface interface {
+	Create(ctx context.Context, p *person.Person) error
+	// GetByID returns shared.ErrPersonNotFound unless the person belongs to the user
+	GetByID(ctx context.Context, userID uuid.UUID, personID uuid.UUID) (*person.Person, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]person.Person, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Update(ctx context.Context, p *person.Person) error
+	Delete(ctx context.Context, userID uuid.UUID, personID uuid.UUID) error
+}
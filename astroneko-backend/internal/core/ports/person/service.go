@@ -0,0 +1,17 @@
+package person
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/person"
+
+	"github.com/google/uuid"
+)
+
+// ServiceInterface defines the contract for saved people business logic
+type ServiceInterface interface {
+	ListPeople(ctx context.Context, userID uuid.UUID) (*person.ListPeopleResponse, error)
+	CreatePerson(ctx context.Context, userID uuid.UUID, req *person.SavePersonRequest) (*person.PersonResponse, error)
+	UpdatePerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, req *person.SavePersonRequest) (*person.PersonResponse, error)
+	DeletePerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID) error
+}
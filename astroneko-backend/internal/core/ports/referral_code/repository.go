@@ -4,10 +4,13 @@ import (
 	"context"
 
 	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_reward"
+
 	"github.com/google/uuid"
 )
 
-// RepositoryInterface defines the contract for referral code data operations
+// RepositoryInterface defines the contract for referral code data operations. Codes are
+// normalized with referral_code.NormalizeCode on every write and lookup.
 type RepositoryInterface interface {
 	// General referral codes
 	Create(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error)
@@ -17,9 +20,20 @@ type RepositoryInterface interface {
 	Update(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error)
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, limit, offset int) ([]*referral_code.ReferralCode, int64, error)
+	// CreateReferralCodes inserts the codes in batches
+	CreateReferralCodes(ctx context.Context, referralCodes []*referral_code.ReferralCode) error
+	// ExistingReferralCodes returns, normalized, those of the codes already taken by a general
+	// or a user referral code
+	ExistingReferralCodes(ctx context.Context, codes []string) ([]string, error)
+	// ListUsageAfter returns up to limit codes with their redemption counts, ordered by creation
+	// time and ID, starting after the cursor or from the first code when it is nil
+	ListUsageAfter(ctx context.Context, after *referral_code.ExportCursor, limit int) ([]*referral_code.CodeUsage, error)
 
 	// User referral codes
 	CreateUserReferralCode(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error)
+	// CreateUserReferralCodeIfAbsent inserts the code unless a general or user code already
+	// holds it, which it reports as false
+	CreateUserReferralCodeIfAbsent(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (bool, error)
 	GetUserReferralCodesByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.UserReferralCode, error)
 	GetUserReferralCodeByCode(ctx context.Context, code string) (*referral_code.UserReferralCode, error)
 	UpdateUserReferralCode(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error)
@@ -28,4 +42,27 @@ type RepositoryInterface interface {
 	// Referral logs
 	CreateReferralLog(ctx context.Context, referralLog *referral_code.ReferralLog) (*referral_code.ReferralLog, error)
 	GetReferralCodeUsageCount(ctx context.Context, referralCode string) (int64, error)
+	GetReferralLogsByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.ReferralLog, error)
+	// AttributeClick links the redeemer's latest claimed deep link click on the redeemed code to the log
+	AttributeClick(ctx context.Context, referralLog *referral_code.ReferralLog) error
+
+	// Transactions
+	// WithTx runs fn with a repository bound to a single transaction, committing when fn
+	// returns nil and rolling back otherwise. Calls must not be nested.
+	WithTx(ctx context.Context, fn func(tx RepositoryInterface) error) error
+	// GetByReferralCodeForUpdate and GetUserReferralCodeByCodeForUpdate lock the code row until the transaction ends
+	GetByReferralCodeForUpdate(ctx context.Context, code string) (*referral_code.ReferralCode, error)
+	GetUserReferralCodeByCodeForUpdate(ctx context.Context, code string) (*referral_code.UserReferralCode, error)
+	// CountRedemptions counts the referral logs of a general code
+	CountRedemptions(ctx context.Context, referralCodeID uuid.UUID) (int64, error)
+	// MarkUserReferralActivated flags the user as having redeemed a code, sets their tier and
+	// adds the bonus requests. It reports false when the flag was already set.
+	MarkUserReferralActivated(ctx context.Context, userID uuid.UUID, tier string, bonusQuota int) (bool, error)
+	// LockCodeOwner locks the user's row until the transaction ends
+	LockCodeOwner(ctx context.Context, userID uuid.UUID) error
+	// CountInvitesForUpdate locks the inviter's user row and counts the redemptions of their codes
+	CountInvitesForUpdate(ctx context.Context, inviterID uuid.UUID) (int64, error)
+	// CreditReward records the reward in the ledger and applies it to the user. It reports
+	// false when the ledger already holds it.
+	CreditReward(ctx context.Context, reward *referral_reward.Reward) (bool, error)
 }
@@ -11,7 +11,8 @@ import (
 type ServiceInterface interface {
 	// General referral codes
 	IsValidReferralCode(ctx context.Context, code string) (bool, error)
-	CreateReferralCode(ctx context.Context, code string) (*referral_code.ReferralCode, error)
+	ValidateReferralCode(ctx context.Context, code, account, clientIP string) (bool, error)
+	CreateReferralCode(ctx context.Context, req *referral_code.CreateReferralCodeRequest) (*referral_code.ReferralCode, error)
 	GetReferralCodeByCode(ctx context.Context, code string) (*referral_code.ReferralCode, error)
 	DeleteReferralCode(ctx context.Context, id string) error
 	ListReferralCodes(ctx context.Context, limit, offset int) ([]*referral_code.ReferralCode, int64, error)
@@ -19,4 +20,5 @@ type ServiceInterface interface {
 	// User referral codes
 	GetOrGenerateUserReferralCodes(ctx context.Context, userID uuid.UUID) ([]*referral_code.UserReferralCode, error)
 	ActivateReferralCode(ctx context.Context, userID uuid.UUID, code string) (*referral_code.ActivateReferralResponse, error)
+	ClaimVanityCode(ctx context.Context, userID uuid.UUID, code string) (*referral_code.UserReferralCode, error)
 }
@@ -0,0 +1,17 @@
+package referral_graph
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for reading who invited whom
+type RepositoryInterface interface {
+	// ListInvitees returns the edges from the given inviters to the users who redeemed their codes, newest first
+	ListInvitees(ctx context.Context, inviterIDs []uuid.UUID) ([]referral_graph.Edge, error)
+	// Leaderboard ranks inviters by direct invites made in the filter's date range
+	Leaderboard(ctx context.Context, filter referral_graph.LeaderboardFilter) ([]referral_graph.LeaderboardRow, int64, error)
+}
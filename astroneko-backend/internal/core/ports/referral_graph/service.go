@@ -0,0 +1,16 @@
+package referral_graph
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+
+	"github.com/google/uuid"
+)
+
+// ServiceInterface defines the contract for referral graph analytics
+type ServiceInterface interface {
+	// GetMyReferrals returns the user's anonymized invitees and the size of their invite tree
+	GetMyReferrals(ctx context.Context, userID uuid.UUID) (*referral_graph.MyReferralsResponse, error)
+	GetLeaderboard(ctx context.Context, filter referral_graph.LeaderboardFilter) (*referral_graph.LeaderboardResponse, error)
+}
@@ -0,0 +1,23 @@
+package referral_link
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_link"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for referral link click tracking
+type RepositoryInterface interface {
+	CreateClick(ctx context.Context, click *referral_link.Click) error
+	// ClaimClick assigns an unclaimed click made at or after notBefore to the user, marking
+	// it as a signup when the user's account is newer than the click. It reports false when
+	// there is no such click.
+	ClaimClick(ctx context.Context, clickID, userID uuid.UUID, notBefore time.Time) (bool, error)
+	// ListClicksByUserID returns the clicks claimed by the user, oldest first
+	ListClicksByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_link.Click, error)
+	// Funnel aggregates clicks per code, ordered by clicks, and sums them over all matching codes
+	Funnel(ctx context.Context, filter referral_link.FunnelFilter) ([]referral_link.FunnelRow, *referral_link.FunnelTotals, error)
+}
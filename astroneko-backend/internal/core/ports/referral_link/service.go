@@ -0,0 +1,23 @@
+package referral_link
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/referral_link"
+
+	"github.com/google/uuid"
+)
+
+// ServiceInterface defines the contract for referral deep links, QR codes and click tracking
+type ServiceInterface interface {
+	// FollowLink records a click on the code's deep link and returns where to send the visitor
+	FollowLink(ctx context.Context, code string, utm referral_link.UTMParams, referrer, userAgent string) (string, error)
+	// LandingLink returns where to send the visitor without recording a click
+	LandingLink(code string, utm referral_link.UTMParams) (string, error)
+	DeepLink(code string, utm referral_link.UTMParams) string
+	GetUserCodeQR(ctx context.Context, userID uuid.UUID, code, format string, size int) (*referral_link.QRCode, error)
+	GetGeneralCodeLink(ctx context.Context, id string) (*referral_link.LinkResponse, error)
+	GetGeneralCodeQR(ctx context.Context, id, format string, size int) (*referral_link.QRCode, error)
+	ClaimClick(ctx context.Context, userID, clickID uuid.UUID) (bool, error)
+	GetFunnel(ctx context.Context, filter referral_link.FunnelFilter) (*referral_link.FunnelResponse, error)
+}
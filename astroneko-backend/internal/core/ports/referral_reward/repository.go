@@ -0,0 +1,18 @@
+package referral_reward
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/referral_reward"
+
+	"github.com/google/uuid"
+)
+
+// RepositoryInterface defines the contract for reading the referral rewards ledger.
+// Rewards are credited by the referral code repository inside the redemption transaction.
+type RepositoryInterface interface {
+	// ListByUserID returns the user's ledger, newest first
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_reward.Reward, error)
+	// CountInvites counts the redemptions of the user's referral codes
+	CountInvites(ctx context.Context, userID uuid.UUID) (int64, error)
+}
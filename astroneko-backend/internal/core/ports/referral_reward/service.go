@@ -0,0 +1,13 @@
+package referral_reward
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+)
+
+// ServiceInterface defines the contract for showing users their referral rewards
+type ServiceInterface interface {
+	GetMyRewards(ctx context.Context, u *user.User) (*referral_reward.MyRewardsResponse, error)
+}
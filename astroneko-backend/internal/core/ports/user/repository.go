@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/google/uuid"
 )
 
 // RepositoryInterface defines the contract for user data operations
@@ -12,8 +14,11 @@ type RepositoryInterface interface {
 	GetByID(ctx context.Context, id string) (*user.User, error)
 	GetByFirebaseUID(ctx context.Context, firebaseUID string) (*user.User, error)
 	GetByEmail(ctx context.Context, email string) (*user.User, error)
-	Update(ctx context.Context, user *user.User) (*user.User, error)
+	// Update writes only the named columns of the user, see the user.Column constants
+	Update(ctx context.Context, user *user.User, columns []string) (*user.User, error)
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, limit, offset int) ([]*user.User, int64, error)
 	GetTotalUsers(ctx context.Context) (int64, error)
+	// ConsumeBonusQuota takes one request off the user's bonus quota, reporting false when none is left
+	ConsumeBonusQuota(ctx context.Context, id uuid.UUID) (bool, error)
 }
@@ -13,6 +13,7 @@ type ServiceInterface interface {
 	GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*user.User, error)
 	GetUserByID(ctx context.Context, id string) (*user.User, error)
 	UpdateUser(ctx context.Context, id string, req *user.UpdateUserRequest) (*user.User, error)
+	UpdateProfile(ctx context.Context, id string, req *user.UpdateProfileRequest) (*user.User, error)
 	DeleteUser(ctx context.Context, id string) error
 	VerifyFirebaseToken(ctx context.Context, idToken string) (*auth.Token, error)
 	CreateUserFromToken(ctx context.Context, token *auth.Token) (*user.User, error)
@@ -4,13 +4,38 @@ import (
 	"context"
 
 	"astroneko-backend/internal/core/domain/waiting_list"
+
+	"github.com/google/uuid"
 )
 
 // RepositoryInterface defines the contract for waiting list data operations
 type RepositoryInterface interface {
+	// WithTx runs fn in a transaction, committing when it returns nil
+	WithTx(ctx context.Context, fn func(tx RepositoryInterface) error) error
+
+	GetListBySlug(ctx context.Context, slug string) (*waiting_list.WaitingList, error)
+	GetListByID(ctx context.Context, id uuid.UUID) (*waiting_list.WaitingList, error)
+	// GetListBySlugForUpdate also locks the list, so joins are counted against its capacity one at a time
+	GetListBySlugForUpdate(ctx context.Context, slug string) (*waiting_list.WaitingList, error)
+	ListLists(ctx context.Context) ([]*waiting_list.WaitingList, error)
+	CreateList(ctx context.Context, list *waiting_list.WaitingList) error
+	// UpdateList writes only the given columns of the list, so concurrent updates of other
+	// settings are kept
+	UpdateList(ctx context.Context, list *waiting_list.WaitingList, columns []string) error
+	CountEntries(ctx context.Context, listID uuid.UUID) (int64, error)
+
 	Create(ctx context.Context, waitingListUser *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error)
-	GetByEmail(ctx context.Context, email string) (*waiting_list.WaitingListUser, error)
+	GetByEmail(ctx context.Context, listID uuid.UUID, email string) (*waiting_list.WaitingListUser, error)
 	GetByID(ctx context.Context, id string) (*waiting_list.WaitingListUser, error)
-	List(ctx context.Context, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error)
+	List(ctx context.Context, listID uuid.UUID, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error)
 	Delete(ctx context.Context, id string) error
+
+	// NextQueueNumber is one past the highest queue number on the list; call it with the list locked
+	NextQueueNumber(ctx context.Context, listID uuid.UUID) (int64, error)
+	GetByInviteCode(ctx context.Context, listID uuid.UUID, inviteCode string) (*waiting_list.WaitingListUser, error)
+	// HasReferralFromFingerprint reports whether a credited referral of the entry came from the device
+	HasReferralFromFingerprint(ctx context.Context, referrerID uuid.UUID, fingerprint string) (bool, error)
+	CreditReferral(ctx context.Context, referrerID uuid.UUID) error
+	// GetPosition ranks the entry on its list by queue number less boost places per referral
+	GetPosition(ctx context.Context, entry *waiting_list.WaitingListUser, boost int) (*waiting_list.QueuePosition, error)
 }
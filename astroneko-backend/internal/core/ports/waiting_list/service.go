@@ -6,10 +6,18 @@ import (
 	"astroneko-backend/internal/core/domain/waiting_list"
 )
 
-// ServiceInterface defines the contract for waiting list business logic
+// ServiceInterface defines the contract for waiting list business logic. Every list is
+// addressed by its slug.
 type ServiceInterface interface {
-	JoinWaitingList(ctx context.Context, email string) (*waiting_list.WaitingListUser, error)
-	GetWaitingListUsers(ctx context.Context, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error)
-	GetWaitingListUserByEmail(ctx context.Context, email string) (*waiting_list.WaitingListUser, error)
-	IsInWaitingListByEmail(ctx context.Context, email string) (bool, error)
+	JoinWaitingList(ctx context.Context, slug string, join *waiting_list.Join) (*waiting_list.JoinedWaitingListResponse, error)
+	GetStatus(ctx context.Context, token string) (*waiting_list.WaitingListStatusResponse, error)
+	GetWaitingListUsers(ctx context.Context, slug string, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error)
+	GetWaitingListUserByEmail(ctx context.Context, slug, email string) (*waiting_list.WaitingListUser, error)
+	IsInWaitingListByEmail(ctx context.Context, slug, email string) (bool, error)
+	DeleteUser(ctx context.Context, slug, id string) error
+
+	GetListInfo(ctx context.Context, slug string) (*waiting_list.WaitingListInfoResponse, error)
+	ListLists(ctx context.Context) ([]*waiting_list.WaitingListResponse, error)
+	CreateList(ctx context.Context, req *waiting_list.CreateWaitingListRequest) (*waiting_list.WaitingListResponse, error)
+	UpdateList(ctx context.Context, slug string, req *waiting_list.UpdateWaitingListRequest) (*waiting_list.WaitingListResponse, error)
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccountDeletionHTTPHandler struct {
+	accountDeletionService *services.AccountDeletionService
+	validator              validator.Validator
+}
+
+// NewAccountDeletionHTTPHandler creates a new account deletion HTTP handler
+func NewAccountDeletionHTTPHandler(accountDeletionService *services.AccountDeletionService, validator validator.Validator) *AccountDeletionHTTPHandler {
+	return &AccountDeletionHTTPHandler{
+		accountDeletionService: accountDeletionService,
+		validator:              validator,
+	}
+}
+
+// DeleteMe godoc
+// @Summary Delete my account
+// @Description Two-step account deletion. Called without a body it returns a confirmation token valid for 15 minutes. Called again with that token it schedules the deletion after a 7 day grace period, during which it can be cancelled. All sessions, messages, saved people, referral codes, referral logs and usage records are then purged and the Firebase account is deleted.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body account_deletion.DeleteAccountRequest false "Confirmation token from the first call"
+// @Success 200 {object} account_deletion.DeletionStatusResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me [delete]
+func (h *AccountDeletionHTTPHandler) DeleteMe(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	// The first step has no body
+	var req account_deletion.DeleteAccountRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+			return c.Status(status).JSON(response)
+		}
+
+		if err := h.validator.ValidateStruct(&req); err != nil {
+			status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+			return c.Status(status).JSON(response)
+		}
+	}
+
+	deletion, err := h.accountDeletionService.RequestDeletion(c.Context(), userEntity, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = deletion
+	return c.Status(status).JSON(response)
+}
+
+// GetMyDeletion godoc
+// @Summary Get pending account deletion
+// @Description Return the authenticated user's account deletion that is awaiting confirmation or scheduled.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Success 200 {object} account_deletion.DeletionStatusResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/deletion [get]
+func (h *AccountDeletionHTTPHandler) GetMyDeletion(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	deletion, err := h.accountDeletionService.GetDeletionStatus(c.Context(), userEntity)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = deletion
+	return c.Status(status).JSON(response)
+}
+
+// CancelMyDeletion godoc
+// @Summary Cancel account deletion
+// @Description Cancel the authenticated user's account deletion before the grace period ends.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Success 200 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/deletion [delete]
+func (h *AccountDeletionHTTPHandler) CancelMyDeletion(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.accountDeletionService.CancelDeletion(c.Context(), userEntity); err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+func (h *AccountDeletionHTTPHandler) handleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, shared.ErrInvalidDeletionConfirmation):
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrDeletionRequestNotFound):
+		status, response := shared.NewErrorResponse("ERR_404", "No pending account deletion")
+		return c.Status(status).JSON(response)
+	default:
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to process account deletion")
+		return c.Status(status).JSON(response)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strings"
 
 	"astroneko-backend/internal/core/domain/agent"
@@ -13,14 +14,18 @@ import (
 )
 
 type AgentHTTPHandler struct {
-	agentService *services.AgentService
-	validator    validator.Validator
+	agentService      *services.AgentService
+	astrologyService  *services.AstrologyService
+	numerologyService *services.NumerologyService
+	validator         validator.Validator
 }
 
-func NewAgentHTTPHandler(agentService *services.AgentService, validator validator.Validator) *AgentHTTPHandler {
+func NewAgentHTTPHandler(agentService *services.AgentService, astrologyService *services.AstrologyService, numerologyService *services.NumerologyService, validator validator.Validator) *AgentHTTPHandler {
 	return &AgentHTTPHandler{
-		agentService: agentService,
-		validator:    validator,
+		agentService:      agentService,
+		astrologyService:  astrologyService,
+		numerologyService: numerologyService,
+		validator:         validator,
 	}
 }
 
@@ -73,7 +78,7 @@ func (h *AgentHTTPHandler) ClearState(c *fiber.Ctx) error {
 
 // Reply godoc
 // @Summary Send message to agent and get reply
-// @Description Send a message to the cat fortune agent and receive a response. Works for both authenticated users (unlimited) and guests (3 requests/day). For authenticated users, user_id is automatically extracted from auth token. For guests, session fingerprint is used. session_id is optional.
+// @Description Send a message to the cat fortune agent and receive a response. Works for both authenticated users (unlimited) and guests (3 requests/day). For authenticated users, user_id is automatically extracted from auth token. For guests, session fingerprint is used. session_id is optional. Set numerology to have name, phone and life path numbers computed by the server and passed to the agent.
 // @Tags agent
 // @Accept json
 // @Produce json
@@ -91,6 +96,7 @@ func (h *AgentHTTPHandler) Reply(c *fiber.Ctx) error {
 	// Determine user ID (use authenticated user ID or generate guest session ID)
 	var userID string
 	var isGuest bool
+	var userEntity *user.User
 
 	if userFromContext == nil {
 		// Guest user OR logged-in user without activated referral (both treated as guest with 3/day limit)
@@ -105,11 +111,12 @@ func (h *AgentHTTPHandler) Reply(c *fiber.Ctx) error {
 		}
 	} else {
 		// Authenticated user with activated referral (unlimited access)
-		userEntity, ok := userFromContext.(*user.User)
+		authenticatedUser, ok := userFromContext.(*user.User)
 		if !ok {
 			status, response := shared.NewErrorResponse("ERR_401", "Invalid user data in context")
 			return c.Status(status).JSON(response)
 		}
+		userEntity = authenticatedUser
 		userID = userEntity.ID.String()
 		isGuest = false
 	}
@@ -135,6 +142,26 @@ func (h *AgentHTTPHandler) Reply(c *fiber.Ctx) error {
 	// For guest users: use guest fingerprint
 	req.UserID = userID
 
+	// Personal context is only ever set server-side from the user's own profile
+	req.AstrologyContext = nil
+	req.NumerologyContext = nil
+	req.CompatibilityContext = nil
+	if userEntity != nil {
+		req.AstrologyContext = h.astrologyService.BuildAgentContext(userEntity)
+	}
+
+	numerologyContext, err := h.numerologyService.BuildAgentContext(userEntity, req.Numerology)
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidNumerologyInput) {
+			status, response := shared.NewErrorResponse("ERR_400", err.Error())
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to compute numerology")
+		return c.Status(status).JSON(response)
+	}
+	req.Numerology = nil
+	req.NumerologyContext = numerologyContext
+
 	agentResponse, err := h.agentService.Reply(c.Context(), userID, req)
 	if err != nil {
 		if strings.Contains(err.Error(), "failed to make request") {
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AstrologyHTTPHandler struct {
+	astrologyService *services.AstrologyService
+	validator        validator.Validator
+}
+
+// NewAstrologyHTTPHandler creates a new astrology HTTP handler
+func NewAstrologyHTTPHandler(astrologyService *services.AstrologyService, validator validator.Validator) *AstrologyHTTPHandler {
+	return &AstrologyHTTPHandler{
+		astrologyService: astrologyService,
+		validator:        validator,
+	}
+}
+
+// UpdateMyBirthProfile godoc
+// @Summary Update birth profile
+// @Description Set the authenticated user's birth date, time, place and timezone. Birth time and coordinates are optional but required for the rising sign and houses. Timezone defaults to Asia/Bangkok.
+// @Tags astrology
+// @Accept json
+// @Produce json
+// @Param profile body user.UpdateBirthProfileRequest true "Birth profile"
+// @Success 200 {object} user.BirthProfileResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/birth-profile [put]
+func (h *AstrologyHTTPHandler) UpdateMyBirthProfile(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	var req user.UpdateBirthProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	updatedUser, err := h.astrologyService.UpdateBirthProfile(c.Context(), userEntity.ID.String(), &req)
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidBirthProfile) {
+			status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to update birth profile")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = updatedUser.ToBirthProfileResponse()
+	return c.Status(status).JSON(response)
+}
+
+// GetMyChart godoc
+// @Summary Get natal chart
+// @Description Compute the authenticated user's natal chart offline: sun, moon and rising signs, planetary positions and house cusps. Rising sign and houses require birth time and coordinates.
+// @Tags astrology
+// @Accept json
+// @Produce json
+// @Success 200 {object} astrology.NatalChart
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/chart [get]
+func (h *AstrologyHTTPHandler) GetMyChart(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	chart, err := h.astrologyService.GetNatalChart(userEntity)
+	if err != nil {
+		if errors.Is(err, shared.ErrBirthProfileNotSet) {
+			status, response := shared.NewErrorResponse("ERR_404", "Birth profile not set")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to compute natal chart")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = chart
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"astroneko-backend/internal/core/domain/audit"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogHTTPHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditLogHTTPHandler(auditService *services.AuditService) *AuditLogHTTPHandler {
+	return &AuditLogHTTPHandler{
+		auditService: auditService,
+	}
+}
+
+// parseQueryTime reads an optional RFC 3339 timestamp from the query string
+func parseQueryTime(c *fiber.Ctx, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// ListAuditLog godoc
+// @Summary List audit log entries
+// @Description Get a paginated list of administrative changes made from the CRM, newest first. Each entry shows who made the change, the fields before and after, the client IP and the request ID. Requires the audit_log:read permission.
+// @Tags crm-audit
+// @Accept json
+// @Produce json
+// @Param actor_id query string false "Only changes made by this CRM user"
+// @Param action query string false "Only this action, e.g. referral_code.updated"
+// @Param target_type query string false "Only this target type, e.g. user or referral_code"
+// @Param target_id query string false "Only changes to this target"
+// @Param from query string false "Only changes at or after this time (RFC 3339)"
+// @Param to query string false "Only changes before this time (RFC 3339)"
+// @Param limit query int false "Number of items to return (default: 50, max: 200)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} audit.ListEntriesResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/audit-log [get]
+func (h *AuditLogHTTPHandler) ListAuditLog(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	from, err := parseQueryTime(c, "from")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "from must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+	to, err := parseQueryTime(c, "to")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "to must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+
+	filter := audit.ListFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		From:       from,
+		To:         to,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	entries, total, err := h.auditService.List(c.Context(), filter)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to list audit log")
+		return c.Status(status).JSON(response)
+	}
+
+	entryResponses := make([]audit.EntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entryResponses = append(entryResponses, *entry.ToResponse())
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = audit.ListEntriesResponse{
+		Entries: entryResponses,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	return c.Status(status).JSON(response)
+}
+
+// VerifyAuditLog godoc
+// @Summary Verify the audit log hash chain
+// @Description Recompute the hash of every audit entry and check that each one links to the entry before it. Reports the first broken entry, or the head sequence and hash to keep as a checkpoint. Requires the audit_log:read permission.
+// @Tags crm-audit
+// @Accept json
+// @Produce json
+// @Success 200 {object} audit.ChainVerification
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/audit-log/verify [get]
+func (h *AuditLogHTTPHandler) VerifyAuditLog(c *fiber.Ctx) error {
+	result, err := h.auditService.VerifyChain(c.Context())
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to verify audit log")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = result
+	return c.Status(status).JSON(response)
+}
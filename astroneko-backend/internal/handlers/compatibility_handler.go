@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"astroneko-backend/internal/core/domain/compatibility"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompatibilityHTTPHandler struct {
+	compatibilityService *services.CompatibilityService
+	validator            validator.Validator
+}
+
+// NewCompatibilityHTTPHandler creates a new compatibility HTTP handler
+func NewCompatibilityHTTPHandler(compatibilityService *services.CompatibilityService, validator validator.Validator) *CompatibilityHTTPHandler {
+	return &CompatibilityHTTPHandler{
+		compatibilityService: compatibilityService,
+		validator:            validator,
+	}
+}
+
+// CreateCompatibilityReading godoc
+// @Summary Compatibility reading
+// @Description Compare the authenticated user's birth profile with a saved person. The sign, element and numerology breakdown is computed by the server and interpreted by the agent, and the reading is saved as a history session.
+// @Tags readings
+// @Accept json
+// @Produce json
+// @Param request body compatibility.ReadingRequest true "Person to compare with"
+// @Success 200 {object} compatibility.ReadingResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Failure 502 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/readings/compatibility [post]
+func (h *CompatibilityHTTPHandler) CreateCompatibilityReading(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	var req compatibility.ReadingRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	reading, err := h.compatibilityService.CreateReading(c.Context(), userEntity, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrInvalidRequest), errors.Is(err, shared.ErrInvalidBirthProfile):
+			status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrBirthProfileNotSet):
+			status, response := shared.NewErrorResponse("ERR_404", "Birth profile not set")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrPersonNotFound):
+			status, response := shared.NewErrorResponse("ERR_404", "Person not found")
+			return c.Status(status).JSON(response)
+		case strings.Contains(err.Error(), "failed to make request"):
+			status, response := shared.NewErrorResponse("ERR_502", "External service unavailable")
+			return c.Status(status).JSON(response)
+		default:
+			status, response := shared.NewErrorResponse("ERR_500", "Failed to get compatibility reading")
+			return c.Status(status).JSON(response)
+		}
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = reading
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	"astroneko-backend/internal/core/domain/shared"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// respondTooManyAttempts answers a request refused by the credential attempt tracker,
+// telling the client in Retry-After how many seconds to wait
+func respondTooManyAttempts(c *fiber.Ctx, err error) error {
+	var blocked *credential_attempt.BlockedError
+	if errors.As(err, &blocked) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(blocked.RetryAfterSeconds()))
+	}
+
+	status, response := shared.NewErrorResponse("ERR_1049")
+	return c.Status(status).JSON(response)
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 
+	"astroneko-backend/internal/core/domain/audit"
 	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
 	"astroneko-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,27 +17,32 @@ import (
 
 type CRMUserHTTPHandler struct {
 	crmUserService *services.CRMUserService
+	auditService   *services.AuditService
 	validator      validator.Validator
 }
 
-func NewCRMUserHTTPHandler(crmUserService *services.CRMUserService, validator validator.Validator) *CRMUserHTTPHandler {
+func NewCRMUserHTTPHandler(crmUserService *services.CRMUserService, auditService *services.AuditService, validator validator.Validator) *CRMUserHTTPHandler {
 	return &CRMUserHTTPHandler{
 		crmUserService: crmUserService,
+		auditService:   auditService,
 		validator:      validator,
 	}
 }
 
 // CreateCRMUser godoc
 // @Summary Create a new CRM user
-// @Description Create a new CRM user with username and password
+// @Description Create a new CRM user with username, password and optional roles. Requires the crm_users:manage permission.
 // @Tags crm-auth
 // @Accept json
 // @Produce json
 // @Param user body crm_user.CreateCRMUserRequest true "CRM user data"
 // @Success 201 {object} crm_user.CRMUserResponse
 // @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
 // @Failure 409 {object} shared.ResponseBody
 // @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
 // @Router /v1/api/crm/users [post]
 func (h *CRMUserHTTPHandler) CreateCRMUser(c *fiber.Ctx) error {
 	var req crm_user.CreateCRMUserRequest
@@ -49,6 +58,9 @@ func (h *CRMUserHTTPHandler) CreateCRMUser(c *fiber.Ctx) error {
 
 	newUser, err := h.crmUserService.CreateUser(c.Context(), &req)
 	if err != nil {
+		if errors.Is(err, shared.ErrUnknownCRMRole) || errors.Is(err, shared.ErrWeakPassword) {
+			return h.handleError(c, err)
+		}
 		if strings.Contains(err.Error(), "username already exists") {
 			status, response := shared.NewErrorResponse("ERR_409", "Username already exists")
 			return c.Status(status).JSON(response)
@@ -57,14 +69,17 @@ func (h *CRMUserHTTPHandler) CreateCRMUser(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	created := newUser.ToResponse()
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionCRMUserCreated, audit.TargetCRMUser, newUser.ID.String(), nil, created)
+
 	status, response := shared.NewSuccessResponse("SUC_201")
-	response.Data = newUser.ToResponse()
+	response.Data = created
 	return c.Status(status).JSON(response)
 }
 
 // CRMLogin godoc
 // @Summary Login with CRM credentials
-// @Description Authenticate CRM user with username and password, returns JWT token
+// @Description Authenticate CRM user with username and password. Returns a 15-minute access token and a refresh token for POST /crm/auth/refresh. Users with two-factor authentication get a two_factor challenge instead, to complete with POST /crm/auth/login/2fa; when two-factor authentication is required and not yet set up, the challenge step is "enroll" and POST /crm/auth/login/2fa/setup starts enrollment. Repeated failures for a username or from an IP are answered with 429 and a Retry-After header, growing to a temporary lockout.
 // @Tags crm-auth
 // @Accept json
 // @Produce json
@@ -72,6 +87,8 @@ func (h *CRMUserHTTPHandler) CreateCRMUser(c *fiber.Ctx) error {
 // @Success 200 {object} crm_user.CRMLoginResponse
 // @Failure 400 {object} shared.ResponseBody
 // @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 429 {object} shared.ResponseBody
 // @Failure 500 {object} shared.ResponseBody
 // @Router /v1/api/crm/auth/login [post]
 func (h *CRMUserHTTPHandler) CRMLogin(c *fiber.Ctx) error {
@@ -86,10 +103,18 @@ func (h *CRMUserHTTPHandler) CRMLogin(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	req.ClientIP = c.IP()
 	loginResp, err := h.crmUserService.Login(c.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "invalid credentials") {
-			status, response := shared.NewErrorResponse("ERR_401", "Invalid username or password")
+		if errors.Is(err, shared.ErrCRMUserDisabled) {
+			status, response := shared.NewErrorResponse("ERR_1041")
+			return c.Status(status).JSON(response)
+		}
+		if errors.Is(err, shared.ErrTooManyAttempts) {
+			return respondTooManyAttempts(c, err)
+		}
+		if errors.Is(err, shared.ErrInvalidCredentials) {
+			status, response := shared.NewErrorResponse("ERR_1050", "Invalid username or password")
 			return c.Status(status).JSON(response)
 		}
 		status, response := shared.NewErrorResponse("ERR_500", "Login failed")
@@ -129,3 +154,803 @@ func (h *CRMUserHTTPHandler) GetCRMMe(c *fiber.Ctx) error {
 	response.Data = userEntity.ToResponse()
 	return c.Status(status).JSON(response)
 }
+
+// GetCRMUser godoc
+// @Summary Get a CRM user
+// @Description Get a CRM user with their roles and permissions. Requires the crm_users:manage permission.
+// @Tags crm-roles
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 200 {object} crm_user.CRMUserResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id} [get]
+func (h *CRMUserHTTPHandler) GetCRMUser(c *fiber.Ctx) error {
+	found, err := h.crmUserService.GetUserByID(c.Context(), c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", "CRM user not found")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = found.ToResponse()
+	return c.Status(status).JSON(response)
+}
+
+// ListRoles godoc
+// @Summary List CRM roles
+// @Description Get every CRM role with the permissions it grants. Requires the crm_users:manage permission.
+// @Tags crm-roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} crm_user.ListRolesResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/roles [get]
+func (h *CRMUserHTTPHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.crmUserService.ListRoles(c.Context())
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to list roles")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = crm_user.ListRolesResponse{Roles: roles}
+	return c.Status(status).JSON(response)
+}
+
+// AssignRoles godoc
+// @Summary Assign roles to a CRM user
+// @Description Replace the roles of a CRM user. The last admin cannot lose the admin role. Users must log in again to use newly granted permissions; removed permissions stop working at once. Requires the crm_users:manage permission.
+// @Tags crm-roles
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Param roles body crm_user.AssignRolesRequest true "New roles"
+// @Success 200 {object} crm_user.CRMUserResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/roles [put]
+func (h *CRMUserHTTPHandler) AssignRoles(c *fiber.Ctx) error {
+	var req crm_user.AssignRolesRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	before := h.auditSnapshot(c, c.Params("id"))
+	updated, err := h.crmUserService.AssignRoles(c.Context(), actor.ID, c.Params("id"), req.Roles)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	after := updated.ToResponse()
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionCRMUserRolesAssigned, audit.TargetCRMUser, updated.ID.String(), before, after)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = after
+	return c.Status(status).JSON(response)
+}
+
+// BootstrapAdmin godoc
+// @Summary Bootstrap the first CRM admin
+// @Description Create the first CRM admin, or promote an existing CRM user whose password matches. Only works while no admin exists and with the configured bootstrap token.
+// @Tags crm-roles
+// @Accept json
+// @Produce json
+// @Param request body crm_user.BootstrapAdminRequest true "Admin credentials and bootstrap token"
+// @Success 201 {object} crm_user.CRMUserResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Router /v1/api/crm/bootstrap [post]
+func (h *CRMUserHTTPHandler) BootstrapAdmin(c *fiber.Ctx) error {
+	var req crm_user.BootstrapAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	admin, err := h.crmUserService.BootstrapAdmin(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	after := admin.ToResponse()
+	actor := middleware.CRMActor(c)
+	actor.ID = admin.ID.String()
+	actor.Name = admin.Username
+	h.auditService.Record(c.Context(), actor, audit.ActionCRMAdminBootstrapped, audit.TargetCRMUser, admin.ID.String(), nil, after)
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = after
+	return c.Status(status).JSON(response)
+}
+
+// ListCRMUsers godoc
+// @Summary List CRM users
+// @Description Get a paginated list of CRM users with their roles and whether they are disabled. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 20, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} crm_user.ListCRMUsersResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users [get]
+func (h *CRMUserHTTPHandler) ListCRMUsers(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := h.crmUserService.ListUsers(c.Context(), limit, offset)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to list CRM users")
+		return c.Status(status).JSON(response)
+	}
+
+	userResponses := make([]crm_user.CRMUserResponse, 0, len(users))
+	for _, u := range users {
+		userResponses = append(userResponses, *u.ToResponse())
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = crm_user.ListCRMUsersResponse{
+		Users:  userResponses,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	return c.Status(status).JSON(response)
+}
+
+// DisableCRMUser godoc
+// @Summary Disable a CRM user
+// @Description Block a CRM user from logging in and revoke their sessions. Users cannot disable themselves and the last enabled admin cannot be disabled. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 200 {object} crm_user.CRMUserResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/disable [post]
+func (h *CRMUserHTTPHandler) DisableCRMUser(c *fiber.Ctx) error {
+	return h.setDisabled(c, true)
+}
+
+// EnableCRMUser godoc
+// @Summary Enable a CRM user
+// @Description Allow a disabled CRM user to log in again. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 200 {object} crm_user.CRMUserResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/enable [post]
+func (h *CRMUserHTTPHandler) EnableCRMUser(c *fiber.Ctx) error {
+	return h.setDisabled(c, false)
+}
+
+func (h *CRMUserHTTPHandler) setDisabled(c *fiber.Ctx, disabled bool) error {
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	before := h.auditSnapshot(c, c.Params("id"))
+	updated, err := h.crmUserService.SetDisabled(c.Context(), actor.ID, c.Params("id"), disabled)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	action := audit.ActionCRMUserEnabled
+	if disabled {
+		action = audit.ActionCRMUserDisabled
+	}
+	after := updated.ToResponse()
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), action, audit.TargetCRMUser, updated.ID.String(), before, after)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = after
+	return c.Status(status).JSON(response)
+}
+
+// IssuePasswordReset godoc
+// @Summary Issue a CRM password reset token
+// @Description Create a one-time password reset token for a CRM user, valid for one hour. Older unused tokens for the user stop working. The token is only shown in this response. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 201 {object} crm_user.PasswordResetResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/password-reset [post]
+func (h *CRMUserHTTPHandler) IssuePasswordReset(c *fiber.Ctx) error {
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	reset, err := h.crmUserService.IssuePasswordReset(c.Context(), actor.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	// The token itself must never reach the log
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionCRMUserPasswordReset, audit.TargetCRMUser, c.Params("id"), nil,
+		map[string]any{"expires_at": reset.ExpiresAt})
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = reset
+	return c.Status(status).JSON(response)
+}
+
+// ChangeMyPassword godoc
+// @Summary Change own CRM password
+// @Description Change the authenticated CRM user's password. All other sessions are signed out; the returned tokens replace the current ones.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} crm_user.CRMLoginResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/password [put]
+func (h *CRMUserHTTPHandler) ChangeMyPassword(c *fiber.Ctx) error {
+	var req crm_user.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	loginResp, err := h.crmUserService.ChangePassword(c.Context(), actor.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = loginResp
+	return c.Status(status).JSON(response)
+}
+
+// ResetPassword godoc
+// @Summary Reset CRM password with a token
+// @Description Set a new CRM password using a one-time token issued by an admin. All sessions of the user are signed out.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} shared.ResponseBody
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/crm/auth/password-reset [post]
+func (h *CRMUserHTTPHandler) ResetPassword(c *fiber.Ctx) error {
+	var req crm_user.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.crmUserService.ResetPassword(c.Context(), &req); err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+// RefreshCRMToken godoc
+// @Summary Refresh CRM access token
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token works once; reusing one signs out every session started from the same login.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} crm_user.CRMLoginResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Router /v1/api/crm/auth/refresh [post]
+func (h *CRMUserHTTPHandler) RefreshCRMToken(c *fiber.Ctx) error {
+	var req crm_user.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	loginResp, err := h.crmUserService.Refresh(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = loginResp
+	return c.Status(status).JSON(response)
+}
+
+// CRMLogout godoc
+// @Summary Log out of the CRM
+// @Description Revoke the current access token and, when given, the session's refresh token
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/logout [post]
+func (h *CRMUserHTTPHandler) CRMLogout(c *fiber.Ctx) error {
+	var req crm_user.LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+			return c.Status(status).JSON(response)
+		}
+	}
+
+	claims, ok := c.Locals("crm_claims").(*crm_user.JWTClaims)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.crmUserService.Logout(c.Context(), claims, req.RefreshToken); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to log out")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+// VerifyTwoFactorLogin godoc
+// @Summary Complete a CRM login with a two-factor code
+// @Description Second login step for users with two-factor authentication. Send the challenge token from POST /crm/auth/login with the current authenticator code, or with an unused recovery code instead. Wrong codes count as failed logins.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} crm_user.CRMLoginResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 429 {object} shared.ResponseBody
+// @Router /v1/api/crm/auth/login/2fa [post]
+func (h *CRMUserHTTPHandler) VerifyTwoFactorLogin(c *fiber.Ctx) error {
+	var req crm_user.TwoFactorLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	req.ClientIP = c.IP()
+	loginResp, err := h.crmUserService.VerifyTwoFactorLogin(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = loginResp
+	return c.Status(status).JSON(response)
+}
+
+// SetupTwoFactorForLogin godoc
+// @Summary Start two-factor enrollment during login
+// @Description For users who must enroll two-factor authentication before logging in. Send the enroll challenge token from POST /crm/auth/login to get a TOTP secret, provisioning URI and QR code.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.TwoFactorChallengeRequest true "Challenge token"
+// @Success 200 {object} crm_user.TwoFactorSetupResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Router /v1/api/crm/auth/login/2fa/setup [post]
+func (h *CRMUserHTTPHandler) SetupTwoFactorForLogin(c *fiber.Ctx) error {
+	var req crm_user.TwoFactorChallengeRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	setup, err := h.crmUserService.SetupTwoFactorForLogin(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = setup
+	return c.Status(status).JSON(response)
+}
+
+// EnableTwoFactorForLogin godoc
+// @Summary Finish two-factor enrollment and log in
+// @Description Confirm the authenticator set up with POST /crm/auth/login/2fa/setup. Returns a session together with the recovery codes, which are only shown once.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.TwoFactorEnrollRequest true "Challenge token and code"
+// @Success 200 {object} crm_user.CRMLoginResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Router /v1/api/crm/auth/login/2fa/enable [post]
+func (h *CRMUserHTTPHandler) EnableTwoFactorForLogin(c *fiber.Ctx) error {
+	var req crm_user.TwoFactorEnrollRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	loginResp, err := h.crmUserService.EnableTwoFactorForLogin(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = loginResp
+	return c.Status(status).JSON(response)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret for the authenticated CRM user, with its provisioning URI and a QR code PNG. Two-factor authentication is turned on by POST /crm/auth/2fa/enable.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} crm_user.TwoFactorSetupResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/2fa/setup [post]
+func (h *CRMUserHTTPHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	setup, err := h.crmUserService.SetupTwoFactor(c.Context(), actor)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = setup
+	return c.Status(status).JSON(response)
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm the authenticator with a current code. Returns recovery codes, which are only shown once.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} crm_user.RecoveryCodesResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/2fa/enable [post]
+func (h *CRMUserHTTPHandler) EnableTwoFactor(c *fiber.Ctx) error {
+	var req crm_user.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	codes, err := h.crmUserService.EnableTwoFactor(c.Context(), actor, req.Code)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = crm_user.RecoveryCodesResponse{RecoveryCodes: codes}
+	return c.Status(status).JSON(response)
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off for the authenticated CRM user. Needs the password and a current code. Not allowed while two-factor authentication is required for all CRM users.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.DisableTwoFactorRequest true "Password and authenticator code"
+// @Success 200 {object} shared.ResponseBody
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/2fa/disable [post]
+func (h *CRMUserHTTPHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	var req crm_user.DisableTwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.crmUserService.DisableTwoFactor(c.Context(), actor, &req); err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the authenticated CRM user's recovery codes. The old codes stop working and the new ones are only shown once.
+// @Tags crm-auth
+// @Accept json
+// @Produce json
+// @Param request body crm_user.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} crm_user.RecoveryCodesResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/auth/2fa/recovery-codes [post]
+func (h *CRMUserHTTPHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req crm_user.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	codes, err := h.crmUserService.RegenerateRecoveryCodes(c.Context(), actor, req.Code)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = crm_user.RecoveryCodesResponse{RecoveryCodes: codes}
+	return c.Status(status).JSON(response)
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset a CRM user's two-factor authentication
+// @Description Remove the authenticator and recovery codes of a CRM user who lost them, and revoke their sessions. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 200 {object} crm_user.CRMUserResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/2fa/reset [post]
+func (h *CRMUserHTTPHandler) ResetTwoFactor(c *fiber.Ctx) error {
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	before := h.auditSnapshot(c, c.Params("id"))
+	updated, err := h.crmUserService.ResetTwoFactor(c.Context(), actor.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	after := updated.ToResponse()
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionCRMUserTwoFactorReset, audit.TargetCRMUser, updated.ID.String(), before, after)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = after
+	return c.Status(status).JSON(response)
+}
+
+// UnlockCRMUser godoc
+// @Summary Unlock a CRM user's login
+// @Description Clear the failed login count of a CRM user, ending a backoff or lockout before it expires. Blocks on the IP addresses used are not affected. Requires the crm_users:manage permission.
+// @Tags crm-users
+// @Accept json
+// @Produce json
+// @Param id path string true "CRM user ID"
+// @Success 200 {object} credential_attempt.UnlockResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/users/{id}/unlock [post]
+func (h *CRMUserHTTPHandler) UnlockCRMUser(c *fiber.Ctx) error {
+	actor, ok := c.Locals("crm_user").(*crm_user.CRMUser)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+		return c.Status(status).JSON(response)
+	}
+
+	result, err := h.crmUserService.UnlockUser(c.Context(), actor.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionCRMUserUnlocked, audit.TargetCRMUser, c.Params("id"), nil, result)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = result
+	return c.Status(status).JSON(response)
+}
+
+// auditSnapshot returns a CRM user's state before a change for the audit log, or nil
+// when it cannot be read, in which case the change itself reports the error
+func (h *CRMUserHTTPHandler) auditSnapshot(c *fiber.Ctx, id string) *crm_user.CRMUserResponse {
+	found, err := h.crmUserService.GetUserByID(c.Context(), id)
+	if err != nil {
+		return nil
+	}
+	return found.ToResponse()
+}
+
+func (h *CRMUserHTTPHandler) handleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, shared.ErrTooManyAttempts):
+		return respondTooManyAttempts(c, err)
+	case errors.Is(err, shared.ErrInvalidTwoFactorCode):
+		status, response := shared.NewErrorResponse("ERR_1045")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrInvalidTwoFactorChallenge):
+		status, response := shared.NewErrorResponse("ERR_1046")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrTwoFactorAlreadyEnabled):
+		status, response := shared.NewErrorResponse("ERR_1047")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrTwoFactorNotSetUp):
+		status, response := shared.NewErrorResponse("ERR_1048")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrInvalidRefreshToken):
+		status, response := shared.NewErrorResponse("ERR_1044")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrCRMUserDisabled):
+		status, response := shared.NewErrorResponse("ERR_1041")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrWeakPassword):
+		status, response := shared.NewErrorResponse("ERR_1042", err.Error())
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrInvalidPassword):
+		status, response := shared.NewErrorResponse("ERR_1017", "Current password is incorrect")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrInvalidResetToken):
+		status, response := shared.NewErrorResponse("ERR_1043")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrUnknownCRMRole):
+		status, response := shared.NewErrorResponse("ERR_1038", err.Error())
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrLastCRMAdmin):
+		status, response := shared.NewErrorResponse("ERR_1039")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrCRMBootstrapClosed):
+		status, response := shared.NewErrorResponse("ERR_1040")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrForbidden):
+		status, response := shared.NewErrorResponse("ERR_1037", err.Error())
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrCRMUserNotFound):
+		status, response := shared.NewErrorResponse("ERR_1023", "CRM user not found")
+		return c.Status(status).JSON(response)
+	default:
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to update CRM user")
+		return c.Status(status).JSON(response)
+	}
+}
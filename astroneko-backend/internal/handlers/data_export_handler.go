@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type DataExportHTTPHandler struct {
+	dataExportService *services.DataExportService
+}
+
+// NewDataExportHTTPHandler creates a new data export HTTP handler
+func NewDataExportHTTPHandler(dataExportService *services.DataExportService) *DataExportHTTPHandler {
+	return &DataExportHTTPHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+// RequestMyExport godoc
+// @Summary Request a personal data export
+// @Description Queue a ZIP export of the authenticated user's profile, sessions and messages with their tarot cards, saved people, referral codes and quota usage. Poll the export until it is ready to get a download link. If an export is already queued it is returned instead. Up to 3 exports can be requested per 24 hours.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Success 202 {object} data_export.ExportJobResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 429 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/export [post]
+func (h *DataExportHTTPHandler) RequestMyExport(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	export, err := h.dataExportService.RequestExport(c.Context(), userEntity)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_202")
+	response.Data = export
+	return c.Status(status).JSON(response)
+}
+
+// ListMyExports godoc
+// @Summary List personal data exports
+// @Description List the authenticated user's recent data exports. Ready exports include a download link valid for 15 minutes.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Success 200 {object} data_export.ListExportJobsResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/export [get]
+func (h *DataExportHTTPHandler) ListMyExports(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	exports, err := h.dataExportService.ListExports(c.Context(), userEntity)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = exports
+	return c.Status(status).JSON(response)
+}
+
+// GetMyExport godoc
+// @Summary Get a personal data export
+// @Description Return the status of one of the authenticated user's data exports. Once ready it includes a download link valid for 15 minutes; call again for a new link.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Export ID"
+// @Success 200 {object} data_export.ExportJobResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/export/{id} [get]
+func (h *DataExportHTTPHandler) GetMyExport(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid export ID")
+		return c.Status(status).JSON(response)
+	}
+
+	export, err := h.dataExportService.GetExport(c.Context(), userEntity, jobID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = export
+	return c.Status(status).JSON(response)
+}
+
+// DownloadExport godoc
+// @Summary Download a personal data export
+// @Description Download the ZIP archive through the signed link returned by the export status. The link needs no bearer token and expires after 15 minutes.
+// @Tags account
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Param expires query int true "Link expiry as a Unix timestamp"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/me/export/{id}/download [get]
+func (h *DataExportHTTPHandler) DownloadExport(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return h.handleError(c, shared.ErrInvalidDownloadLink)
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return h.handleError(c, shared.ErrInvalidDownloadLink)
+	}
+
+	job, err := h.dataExportService.OpenDownload(c.Context(), jobID, expires, c.Query("signature"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+job.FileName()+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).Send(job.Archive)
+}
+
+func (h *DataExportHTTPHandler) handleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, shared.ErrExportRateLimited):
+		status, response := shared.NewErrorResponse("ERR_1036")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrInvalidDownloadLink):
+		status, response := shared.NewErrorResponse("ERR_403", "Download link is invalid or has expired")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrExportNotFound):
+		status, response := shared.NewErrorResponse("ERR_404", "Export not found")
+		return c.Status(status).JSON(response)
+	default:
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to process data export")
+		return c.Status(status).JSON(response)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"astroneko-backend/internal/core/domain/horoscope"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HoroscopeHTTPHandler struct {
+	horoscopeService *services.HoroscopeService
+}
+
+// NewHoroscopeHTTPHandler creates a new horoscope HTTP handler
+func NewHoroscopeHTTPHandler(horoscopeService *services.HoroscopeService) *HoroscopeHTTPHandler {
+	return &HoroscopeHTTPHandler{
+		horoscopeService: horoscopeService,
+	}
+}
+
+// GetDailyHoroscope godoc
+// @Summary Get daily horoscope for a zodiac sign
+// @Description Serve the daily horoscope of a zodiac sign in Thai and English. Horoscopes are generated once per sign per day; missing recent days are generated on demand.
+// @Tags horoscope
+// @Accept json
+// @Produce json
+// @Param sign path string true "Zodiac sign key (aries, taurus, gemini, cancer, leo, virgo, libra, scorpio, sagittarius, capricorn, aquarius, pisces)"
+// @Param date query string false "Date in YYYY-MM-DD (default: today in Asia/Bangkok)"
+// @Success 200 {object} horoscope.DailyHoroscopeResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 502 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/horoscope/{sign} [get]
+func (h *HoroscopeHTTPHandler) GetDailyHoroscope(c *fiber.Ctx) error {
+	sign := c.Params("sign")
+	if sign == "" {
+		status, response := shared.NewErrorResponse("ERR_400", "Zodiac sign is required")
+		return c.Status(status).JSON(response)
+	}
+
+	date := h.horoscopeService.Today()
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsed, err := time.Parse(horoscope.DateLayout, dateParam)
+		if err != nil {
+			status, response := shared.NewErrorResponse("ERR_400", "Invalid date format, expected YYYY-MM-DD")
+			return c.Status(status).JSON(response)
+		}
+		date = parsed
+	}
+
+	dailyHoroscope, err := h.horoscopeService.GetDailyHoroscope(c.Context(), sign, date)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrInvalidZodiacSign):
+			status, response := shared.NewErrorResponse("ERR_400", "Invalid zodiac sign")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrHoroscopeNotFound):
+			status, response := shared.NewErrorResponse("ERR_404", "Horoscope not found for this date")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrHoroscopeGenerationFailed):
+			status, response := shared.NewErrorResponse("ERR_502", "Horoscope is not available right now")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to retrieve horoscope")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = dailyHoroscope
+	return c.Status(status).JSON(response)
+}
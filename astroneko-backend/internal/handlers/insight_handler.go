@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InsightHTTPHandler struct {
+	insightService *services.InsightService
+}
+
+// NewInsightHTTPHandler creates a new insight HTTP handler
+func NewInsightHTTPHandler(insightService *services.InsightService) *InsightHTTPHandler {
+	return &InsightHTTPHandler{
+		insightService: insightService,
+	}
+}
+
+// GetMyInsights godoc
+// @Summary Get personal reading insights
+// @Description Aggregate the authenticated user's card readings across all sessions: most frequent cards, major vs minor arcana ratio, readings per week, streaks and first/last reading dates. Results are cached until new messages arrive.
+// @Tags insights
+// @Accept json
+// @Produce json
+// @Success 200 {object} insight.UserInsightsResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/insights [get]
+func (h *InsightHTTPHandler) GetMyInsights(c *fiber.Ctx) error {
+	userFromContext := c.Locals("user")
+	if userFromContext == nil {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	userEntity, ok := userFromContext.(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrInvalidUserDataInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	insights, err := h.insightService.GetUserInsights(c.Context(), userEntity.ID)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to retrieve insights")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = insights
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+
+	"astroneko-backend/internal/core/domain/numerology"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NumerologyHTTPHandler struct {
+	numerologyService *services.NumerologyService
+	validator         validator.Validator
+}
+
+// NewNumerologyHTTPHandler creates a new numerology HTTP handler
+func NewNumerologyHTTPHandler(numerologyService *services.NumerologyService, validator validator.Validator) *NumerologyHTTPHandler {
+	return &NumerologyHTTPHandler{
+		numerologyService: numerologyService,
+		validator:         validator,
+	}
+}
+
+// AnalyzeName godoc
+// @Summary Analyze a name
+// @Description Sum the Thai (เลขศาสตร์) or English (Chaldean) letter values of each word of a name and of the whole name.
+// @Tags numerology
+// @Accept json
+// @Produce json
+// @Param request body numerology.AnalyzeNameRequest true "Name to analyze"
+// @Success 200 {object} numerology.NameAnalysis
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/numerology/name [post]
+func (h *NumerologyHTTPHandler) AnalyzeName(c *fiber.Ctx) error {
+	var req numerology.AnalyzeNameRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	analysis, err := h.numerologyService.AnalyzeName(req.Name)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = analysis
+	return c.Status(status).JSON(response)
+}
+
+// AnalyzeLifePath godoc
+// @Summary Compute a life path number
+// @Description Compute the life path number of a birth date, keeping the master numbers 11, 22 and 33.
+// @Tags numerology
+// @Accept json
+// @Produce json
+// @Param request body numerology.AnalyzeLifePathRequest true "Birth date"
+// @Success 200 {object} numerology.LifePathAnalysis
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/numerology/life-path [post]
+func (h *NumerologyHTTPHandler) AnalyzeLifePath(c *fiber.Ctx) error {
+	var req numerology.AnalyzeLifePathRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	analysis, err := h.numerologyService.AnalyzeLifePath(req.BirthDate)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = analysis
+	return c.Status(status).JSON(response)
+}
+
+// AnalyzePhone godoc
+// @Summary Analyze a phone number
+// @Description Sum the digits of a phone number and list its adjacent digit pairs. Numbers with the +66 country code are read as domestic Thai numbers.
+// @Tags numerology
+// @Accept json
+// @Produce json
+// @Param request body numerology.AnalyzePhoneRequest true "Phone number"
+// @Success 200 {object} numerology.PhoneAnalysis
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/numerology/phone [post]
+func (h *NumerologyHTTPHandler) AnalyzePhone(c *fiber.Ctx) error {
+	var req numerology.AnalyzePhoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	analysis, err := h.numerologyService.AnalyzePhone(req.Phone)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = analysis
+	return c.Status(status).JSON(response)
+}
+
+func (h *NumerologyHTTPHandler) handleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, shared.ErrInvalidNumerologyInput) {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+	status, response := shared.NewErrorResponse("ERR_500", "Failed to compute numerology")
+	return c.Status(status).JSON(response)
+}
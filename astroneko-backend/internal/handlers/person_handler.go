@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PersonHTTPHandler struct {
+	personService *services.PersonService
+	validator     validator.Validator
+}
+
+// NewPersonHTTPHandler creates a new saved people HTTP handler
+func NewPersonHTTPHandler(personService *services.PersonService, validator validator.Validator) *PersonHTTPHandler {
+	return &PersonHTTPHandler{
+		personService: personService,
+		validator:     validator,
+	}
+}
+
+// ListMyPeople godoc
+// @Summary List saved people
+// @Description List the partners, friends and family the authenticated user saved for compatibility readings.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Success 200 {object} person.ListPeopleResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/people [get]
+func (h *PersonHTTPHandler) ListMyPeople(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	people, err := h.personService.ListPeople(c.Context(), userEntity.ID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = people
+	return c.Status(status).JSON(response)
+}
+
+// CreateMyPerson godoc
+// @Summary Save a person
+// @Description Save the birth profile of a partner, friend or family member. Birth time and coordinates are optional but required for the rising sign. Timezone defaults to Asia/Bangkok.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param person body person.SavePersonRequest true "Person"
+// @Success 200 {object} person.PersonResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/people [post]
+func (h *PersonHTTPHandler) CreateMyPerson(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	var req person.SavePersonRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	savedPerson, err := h.personService.CreatePerson(c.Context(), userEntity.ID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = savedPerson
+	return c.Status(status).JSON(response)
+}
+
+// UpdateMyPerson godoc
+// @Summary Update a saved person
+// @Description Replace the name, relationship and birth profile of a saved person.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param id path string true "Person ID"
+// @Param person body person.SavePersonRequest true "Person"
+// @Success 200 {object} person.PersonResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/people/{id} [put]
+func (h *PersonHTTPHandler) UpdateMyPerson(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid person ID")
+		return c.Status(status).JSON(response)
+	}
+
+	var req person.SavePersonRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	savedPerson, err := h.personService.UpdatePerson(c.Context(), userEntity.ID, personID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = savedPerson
+	return c.Status(status).JSON(response)
+}
+
+// DeleteMyPerson godoc
+// @Summary Delete a saved person
+// @Description Delete a saved person. Past compatibility readings stay in the history.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param id path string true "Person ID"
+// @Success 200 {object} shared.ResponseBody
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/people/{id} [delete]
+func (h *PersonHTTPHandler) DeleteMyPerson(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid person ID")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.personService.DeletePerson(c.Context(), userEntity.ID, personID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+func (h *PersonHTTPHandler) handleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, shared.ErrInvalidBirthProfile):
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrPeopleLimitReached):
+		status, response := shared.NewErrorResponse("ERR_1029", "You can save up to 20 people")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, shared.ErrPersonNotFound):
+		status, response := shared.NewErrorResponse("ERR_404", "Person not found")
+		return c.Status(status).JSON(response)
+	default:
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to manage saved people")
+		return c.Status(status).JSON(response)
+	}
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"strconv"
+	"time"
 
+	"astroneko-backend/internal/core/domain/audit"
 	"astroneko-backend/internal/core/domain/referral_code"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
 	"astroneko-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,21 +23,26 @@ const (
 	ErrReferralCodeNotFound         = "Referral code not found"
 )
 
+// referralCodeExportTimeout bounds how long a CSV export may keep a connection busy
+const referralCodeExportTimeout = 5 * time.Minute
+
 type ReferralCodeHTTPHandler struct {
 	referralCodeService *services.ReferralCodeService
+	auditService        *services.AuditService
 	validator           validator.Validator
 }
 
-func NewReferralCodeHTTPHandler(referralCodeService *services.ReferralCodeService, validator validator.Validator) *ReferralCodeHTTPHandler {
+func NewReferralCodeHTTPHandler(referralCodeService *services.ReferralCodeService, auditService *services.AuditService, validator validator.Validator) *ReferralCodeHTTPHandler {
 	return &ReferralCodeHTTPHandler{
 		referralCodeService: referralCodeService,
+		auditService:        auditService,
 		validator:           validator,
 	}
 }
 
 // CreateReferralCode godoc
 // @Summary Create a new general referral code
-// @Description Create a new general referral code (admin only)
+// @Description Create a new general referral code (admin only). Optional campaign settings limit when and how often the code can be redeemed and decide whether it grants unlimited access, a tier or bonus quota.
 // @Tags referral-codes
 // @Accept json
 // @Produce json
@@ -54,6 +65,11 @@ func (h *ReferralCodeHTTPHandler) CreateReferralCode(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	if err := req.CodeSettings.Validate(); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
 	isValid, err := h.referralCodeService.IsValidReferralCode(c.Context(), req.ReferralCode)
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_500", ErrFailedToValidateReferralCode)
@@ -65,7 +81,11 @@ func (h *ReferralCodeHTTPHandler) CreateReferralCode(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
-	newReferralCode, err := h.referralCodeService.CreateReferralCode(c.Context(), req.ReferralCode)
+	newReferralCode, err := h.referralCodeService.CreateReferralCode(c.Context(), &req)
+	if errors.Is(err, referral_code.ErrCodeTaken) {
+		status, response := shared.NewErrorResponse("ERR_1061")
+		return c.Status(status).JSON(response)
+	}
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_500", "Failed to create referral code")
 		return c.Status(status).JSON(response)
@@ -77,8 +97,11 @@ func (h *ReferralCodeHTTPHandler) CreateReferralCode(c *fiber.Ctx) error {
 		usageCount = 0 // Default to 0 if we can't get the count
 	}
 
+	created := newReferralCode.ToResponse(usageCount)
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionReferralCodeCreated, audit.TargetReferralCode, newReferralCode.ID.String(), nil, created)
+
 	status, response := shared.NewSuccessResponse("SUC_201")
-	response.Data = newReferralCode.ToResponse(usageCount)
+	response.Data = created
 	return c.Status(status).JSON(response)
 }
 
@@ -156,7 +179,7 @@ func (h *ReferralCodeHTTPHandler) GetReferralCodeByCode(c *fiber.Ctx) error {
 
 // UpdateReferralCode godoc
 // @Summary Update referral code
-// @Description Update a general referral code
+// @Description Update a general referral code and its campaign settings. Settings left out of the body are cleared.
 // @Tags referral-codes
 // @Accept json
 // @Produce json
@@ -186,6 +209,11 @@ func (h *ReferralCodeHTTPHandler) UpdateReferralCode(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	if err := req.CodeSettings.Validate(); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
 	existingReferralCode, err := h.referralCodeService.GetReferralCodeByID(c.Context(), id)
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_404", ErrReferralCodeNotFound)
@@ -205,8 +233,14 @@ func (h *ReferralCodeHTTPHandler) UpdateReferralCode(c *fiber.Ctx) error {
 		}
 	}
 
+	before := *existingReferralCode
 	existingReferralCode.ReferralCode = req.ReferralCode
+	req.ApplyTo(existingReferralCode)
 	updatedReferralCode, err := h.referralCodeService.UpdateReferralCode(c.Context(), existingReferralCode)
+	if errors.Is(err, referral_code.ErrCodeTaken) {
+		status, response := shared.NewErrorResponse("ERR_1061")
+		return c.Status(status).JSON(response)
+	}
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_500", "Failed to update referral code")
 		return c.Status(status).JSON(response)
@@ -218,6 +252,8 @@ func (h *ReferralCodeHTTPHandler) UpdateReferralCode(c *fiber.Ctx) error {
 		usageCount = 0 // Default to 0 if we can't get the count
 	}
 
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionReferralCodeUpdated, audit.TargetReferralCode, updatedReferralCode.ID.String(), before, updatedReferralCode)
+
 	status, response := shared.NewSuccessResponse("SUC_200")
 	response.Data = updatedReferralCode.ToResponse(usageCount)
 	return c.Status(status).JSON(response)
@@ -242,11 +278,19 @@ func (h *ReferralCodeHTTPHandler) DeleteReferralCode(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	existingReferralCode, err := h.referralCodeService.GetReferralCodeByID(c.Context(), id)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_404", ErrReferralCodeNotFound)
+		return c.Status(status).JSON(response)
+	}
+
 	if err := h.referralCodeService.DeleteReferralCode(c.Context(), id); err != nil {
 		status, response := shared.NewErrorResponse("ERR_404", ErrReferralCodeNotFound)
 		return c.Status(status).JSON(response)
 	}
 
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionReferralCodeDeleted, audit.TargetReferralCode, existingReferralCode.ID.String(), existingReferralCode, nil)
+
 	status, response := shared.NewSuccessResponse("SUC_204")
 	return c.Status(status).JSON(response)
 }
@@ -306,9 +350,154 @@ func (h *ReferralCodeHTTPHandler) ListReferralCodes(c *fiber.Ctx) error {
 	return c.Status(status).JSON(response)
 }
 
+// GenerateReferralCodes godoc
+// @Summary Bulk generate general referral codes
+// @Description Generate up to 1000 unique general referral codes made of a prefix and random characters from a charset (alphanumeric by default), all sharing the same campaign settings. Codes that would collide with an existing code are drawn again.
+// @Tags referral-codes
+// @Accept json
+// @Produce json
+// @Param request body referral_code.GenerateReferralCodesRequest true "Generation settings"
+// @Success 201 {object} referral_code.GenerateReferralCodesResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/referral-codes/bulk [post]
+func (h *ReferralCodeHTTPHandler) GenerateReferralCodes(c *fiber.Ctx) error {
+	var req referral_code.GenerateReferralCodesRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Invalid request body")
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	if err := req.Validate(); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	created, err := h.referralCodeService.GenerateReferralCodes(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, referral_code.ErrCodeSpaceExhausted) {
+			status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to generate referral codes")
+		return c.Status(status).JSON(response)
+	}
+
+	codes := make([]referral_code.ReferralCodeResponse, 0, len(created))
+	for _, code := range created {
+		codes = append(codes, *code.ToResponse(0))
+	}
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionReferralCodesGenerated, audit.TargetReferralCode, "", nil, bulkAuditRecord(created))
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = referral_code.GenerateReferralCodesResponse{Codes: codes}
+	return c.Status(status).JSON(response)
+}
+
+// ImportReferralCodes godoc
+// @Summary Import general referral codes from CSV
+// @Description Create general referral codes from an uploaded CSV. The header must include referral_code and may include starts_at, ends_at, max_redemptions, campaign, channel, notes, grant_type, grant_tier and bonus_quota; other columns are ignored, so an export can be imported again. Nothing is created unless every row is valid, and the response lists the errors by line.
+// @Tags referral-codes
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Success 201 {object} referral_code.ImportResult
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/referral-codes/import [post]
+func (h *ReferralCodeHTTPHandler) ImportReferralCodes(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "A CSV file is required in the file field")
+		return c.Status(status).JSON(response)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "Failed to read the uploaded file")
+		return c.Status(status).JSON(response)
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := referral_code.ParseImportCSV(file)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	valid := make([]referral_code.ImportRow, 0, len(rows))
+	for _, row := range rows {
+		err := h.validator.ValidateStruct(&row.CreateReferralCodeRequest)
+		if err == nil {
+			err = row.CodeSettings.Validate()
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, referral_code.RowError{Line: row.Line, ReferralCode: row.ReferralCode, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	result, err := h.referralCodeService.ImportReferralCodes(c.Context(), valid, rowErrors)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to import referral codes")
+		return c.Status(status).JSON(response)
+	}
+
+	if len(result.Errors) > 0 {
+		status, response := shared.NewErrorResponse("ERR_1029", "The file has invalid rows; no referral codes were imported")
+		response.Data = result
+		return c.Status(status).JSON(response)
+	}
+
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionReferralCodesImported, audit.TargetReferralCode, "", nil, fiber.Map{"count": result.Imported, "file": fileHeader.Filename})
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = result
+	return c.Status(status).JSON(response)
+}
+
+// ExportReferralCodes godoc
+// @Summary Export general referral codes as CSV
+// @Description Download every general referral code with its settings and usage count as CSV. The file is streamed page by page.
+// @Tags referral-codes
+// @Produce text/csv
+// @Success 200 {file} file
+// @Security BearerAuth
+// @Router /v1/api/referral-codes/export [get]
+func (h *ReferralCodeHTTPHandler) ExportReferralCodes(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="referral-codes-`+time.Now().UTC().Format("20060102-150405")+`.csv"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// The writer runs after the handler returns, when the request context is no longer valid
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), referralCodeExportTimeout)
+		defer cancel()
+		_ = h.referralCodeService.ExportReferralCodes(ctx, w)
+	})
+	return nil
+}
+
+// bulkAuditRecord is the audit record of codes created together
+func bulkAuditRecord(created []*referral_code.ReferralCode) fiber.Map {
+	codes := make([]string, 0, len(created))
+	for _, code := range created {
+		codes = append(codes, code.ReferralCode)
+	}
+	return fiber.Map{"count": len(codes), "codes": codes}
+}
+
 // ValidateReferralCode godoc
 // @Summary Validate referral code
-// @Description Check if a referral code is valid
+// @Description Check if a referral code is valid. Repeated invalid codes from a CRM user or an IP are answered with 429 and a Retry-After header, growing to a temporary lockout.
 // @Tags referral-codes
 // @Accept json
 // @Produce json
@@ -316,6 +505,7 @@ func (h *ReferralCodeHTTPHandler) ListReferralCodes(c *fiber.Ctx) error {
 // @Success 200 {object} shared.ResponseBody
 // @Failure 400 {object} shared.ResponseBody
 // @Failure 404 {object} shared.ResponseBody
+// @Failure 429 {object} shared.ResponseBody
 // @Security BearerAuth
 // @Router /v1/api/referral-codes/validate/{code} [get]
 func (h *ReferralCodeHTTPHandler) ValidateReferralCode(c *fiber.Ctx) error {
@@ -325,8 +515,12 @@ func (h *ReferralCodeHTTPHandler) ValidateReferralCode(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
-	isValid, err := h.referralCodeService.IsValidReferralCode(c.Context(), code)
+	crmUserID, _ := c.Locals("crm_user_id").(string)
+	isValid, err := h.referralCodeService.ValidateReferralCode(c.Context(), code, crmUserID, c.IP())
 	if err != nil {
+		if errors.Is(err, shared.ErrTooManyAttempts) {
+			return respondTooManyAttempts(c, err)
+		}
 		status, response := shared.NewErrorResponse("ERR_500", ErrFailedToValidateReferralCode)
 		return c.Status(status).JSON(response)
 	}
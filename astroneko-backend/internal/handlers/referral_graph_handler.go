@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"strconv"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReferralGraphHTTPHandler struct {
+	referralGraphService *services.ReferralGraphService
+}
+
+// NewReferralGraphHTTPHandler creates a new referral graph HTTP handler
+func NewReferralGraphHTTPHandler(referralGraphService *services.ReferralGraphService) *ReferralGraphHTTPHandler {
+	return &ReferralGraphHTTPHandler{
+		referralGraphService: referralGraphService,
+	}
+}
+
+// GetMyReferrals godoc
+// @Summary Get my referrals
+// @Description List the people who redeemed the authenticated user's referral codes, newest first. Invitees are anonymized to the first letter of their name. Also returns how many of them are active (logged in within the last 30 days), how many people they in turn invited, and the size and depth of the user's whole invite tree (up to 10 levels).
+// @Tags users/referral
+// @Accept json
+// @Produce json
+// @Success 200 {object} referral_graph.MyReferralsResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/referrals [get]
+func (h *ReferralGraphHTTPHandler) GetMyReferrals(c *fiber.Ctx) error {
+	userFromContext := c.Locals("user")
+	if userFromContext == nil {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	userEntity, ok := userFromContext.(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrInvalidUserDataInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	referrals, err := h.referralGraphService.GetMyReferrals(c.Context(), userEntity.ID)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to retrieve referrals")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = referrals
+	return c.Status(status).JSON(response)
+}
+
+// GetLeaderboard godoc
+// @Summary Referral leaderboard
+// @Description Rank app users by how many people redeemed their referral codes in the date range, with how many of those invitees are active and the all-time size and depth of each inviter's tree. Requires the referral_codes:read and app_users:read permissions.
+// @Tags crm-referrals
+// @Accept json
+// @Produce json
+// @Param from query string false "Only invites at or after this time (RFC 3339)"
+// @Param to query string false "Only invites before this time (RFC 3339)"
+// @Param limit query int false "Number of items to return (default: 20, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} referral_graph.LeaderboardResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/referrals/leaderboard [get]
+func (h *ReferralGraphHTTPHandler) GetLeaderboard(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	from, err := parseQueryTime(c, "from")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "from must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+	to, err := parseQueryTime(c, "to")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "to must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		status, response := shared.NewErrorResponse("ERR_1029", "to must be after from")
+		return c.Status(status).JSON(response)
+	}
+
+	leaderboard, err := h.referralGraphService.GetLeaderboard(c.Context(), referral_graph.LeaderboardFilter{
+		From:   from,
+		To:     to,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to load referral leaderboard")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = leaderboard
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReferralLinkHTTPHandler struct {
+	referralLinkService *services.ReferralLinkService
+	validator           validator.Validator
+}
+
+// NewReferralLinkHTTPHandler creates a new referral link HTTP handler
+func NewReferralLinkHTTPHandler(referralLinkService *services.ReferralLinkService, validator validator.Validator) *ReferralLinkHTTPHandler {
+	return &ReferralLinkHTTPHandler{
+		referralLinkService: referralLinkService,
+		validator:           validator,
+	}
+}
+
+// FollowLink godoc
+// @Summary Follow a referral deep link
+// @Description Record a click on the code's deep link with its utm_* parameters, referrer and user agent, then redirect to the landing page with ref (the code), click_id and the same utm_* parameters. Unknown codes are redirected without a click_id. At most 5 visits per IP address and code are recorded every 10 minutes; further visits are redirected without a click_id.
+// @Tags referral
+// @Param code path string true "Referral code"
+// @Param utm_source query string false "UTM source"
+// @Param utm_medium query string false "UTM medium"
+// @Param utm_campaign query string false "UTM campaign"
+// @Param utm_term query string false "UTM term"
+// @Param utm_content query string false "UTM content"
+// @Success 302
+// @Failure 503 {object} shared.ResponseBody
+// @Router /r/{code} [get]
+func (h *ReferralLinkHTTPHandler) FollowLink(c *fiber.Ctx) error {
+	utm := referral_link.UTMFromQuery(func(key string) string {
+		return c.Query(key)
+	})
+
+	var landing string
+	var err error
+	if throttled, _ := c.Locals(middleware.ReferralClickThrottledKey).(bool); throttled {
+		landing, err = h.referralLinkService.LandingLink(c.Params("code"), utm)
+	} else {
+		landing, err = h.referralLinkService.FollowLink(c.Context(), c.Params("code"), utm, c.Get(fiber.HeaderReferer), c.Get(fiber.HeaderUserAgent))
+	}
+	if err != nil {
+		if errors.Is(err, referral_link.ErrLinksDisabled) {
+			status, response := shared.NewErrorResponse("ERR_1052")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to follow referral link")
+		return c.Status(status).JSON(response)
+	}
+
+	// Every visit must reach the server to be counted
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Redirect(landing, fiber.StatusFound)
+}
+
+// GetMyCodeQR godoc
+// @Summary Get a QR code for my referral code
+// @Description Render the deep link of one of the authenticated user's referral codes as a QR code. The encoded link carries utm_medium=qr, so scans show up separately in the funnel.
+// @Tags referral
+// @Produce png
+// @Produce image/svg+xml
+// @Param code path string true "Referral code"
+// @Param format query string false "png (default) or svg"
+// @Param size query int false "Width and height in pixels (default: 256, min: 128, max: 1024)"
+// @Success 200 {file} binary
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 503 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/auth/referral/codes/{code}/qr [get]
+func (h *ReferralLinkHTTPHandler) GetMyCodeQR(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	format, size, err := parseQRQuery(c)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	qrCode, err := h.referralLinkService.GetUserCodeQR(c.Context(), userEntity.ID, c.Params("code"), format, size)
+	if err != nil {
+		return h.linkError(c, err)
+	}
+	return sendQRCode(c, qrCode)
+}
+
+// ClaimClick godoc
+// @Summary Claim a referral link click
+// @Description Tell the API that the authenticated user arrived through the deep link click with this click_id, as handed to the landing page. Call it right after signup; a user created after the click counts as a signup in the funnel. Clicks older than 30 days or already claimed are not claimed again.
+// @Tags referral
+// @Accept json
+// @Produce json
+// @Param click body referral_link.ClaimClickRequest true "Click to claim"
+// @Success 200 {object} referral_link.ClaimClickResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/auth/referral/clicks/claim [post]
+func (h *ReferralLinkHTTPHandler) ClaimClick(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	var req referral_link.ClaimClickRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	claimed, err := h.referralLinkService.ClaimClick(c.Context(), userEntity.ID, req.ClickID)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to claim referral link click")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = referral_link.ClaimClickResponse{Claimed: claimed}
+	return c.Status(status).JSON(response)
+}
+
+// GetGeneralCodeLink godoc
+// @Summary Get the deep link of a general referral code
+// @Description Get the short deep link of a general referral code for sharing. Requires the referral_codes:read permission.
+// @Tags crm-referral-links
+// @Produce json
+// @Param id path string true "Referral code ID"
+// @Success 200 {object} referral_link.LinkResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 503 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/referral-links/general/{id} [get]
+func (h *ReferralLinkHTTPHandler) GetGeneralCodeLink(c *fiber.Ctx) error {
+	link, err := h.referralLinkService.GetGeneralCodeLink(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.linkError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = link
+	return c.Status(status).JSON(response)
+}
+
+// GetGeneralCodeQR godoc
+// @Summary Get a QR code for a general referral code
+// @Description Render the deep link of a general referral code as a QR code for print or posters. The encoded link carries utm_medium=qr. Requires the referral_codes:read permission.
+// @Tags crm-referral-links
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "Referral code ID"
+// @Param format query string false "png (default) or svg"
+// @Param size query int false "Width and height in pixels (default: 256, min: 128, max: 1024)"
+// @Success 200 {file} binary
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 503 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/referral-links/general/{id}/qr [get]
+func (h *ReferralLinkHTTPHandler) GetGeneralCodeQR(c *fiber.Ctx) error {
+	format, size, err := parseQRQuery(c)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	qrCode, err := h.referralLinkService.GetGeneralCodeQR(c.Context(), c.Params("id"), format, size)
+	if err != nil {
+		return h.linkError(c, err)
+	}
+	return sendQRCode(c, qrCode)
+}
+
+// GetFunnel godoc
+// @Summary Referral link funnel
+// @Description Per code, count deep link clicks, signups (clicks claimed by a user created after the click) and activations (clicks attributed to a redemption of the code), with signup and activation rates relative to clicks. Codes are ordered by clicks. Requires the referral_codes:read permission.
+// @Tags crm-referral-links
+// @Produce json
+// @Param code_type query string false "general or user"
+// @Param utm_source query string false "Only clicks with this utm_source"
+// @Param utm_campaign query string false "Only clicks with this utm_campaign"
+// @Param from query string false "Only clicks at or after this time (RFC 3339)"
+// @Param to query string false "Only clicks before this time (RFC 3339)"
+// @Param limit query int false "Number of items to return (default: 20, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} referral_link.FunnelResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/referral-links/funnel [get]
+func (h *ReferralLinkHTTPHandler) GetFunnel(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	codeType := c.Query("code_type")
+	if codeType != "" && codeType != referral_link.CodeTypeGeneral && codeType != referral_link.CodeTypeUser {
+		status, response := shared.NewErrorResponse("ERR_1029", "code_type must be general or user")
+		return c.Status(status).JSON(response)
+	}
+
+	from, err := parseQueryTime(c, "from")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "from must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+	to, err := parseQueryTime(c, "to")
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", "to must be an RFC 3339 timestamp")
+		return c.Status(status).JSON(response)
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		status, response := shared.NewErrorResponse("ERR_1029", "to must be after from")
+		return c.Status(status).JSON(response)
+	}
+
+	funnel, err := h.referralLinkService.GetFunnel(c.Context(), referral_link.FunnelFilter{
+		CodeType:    codeType,
+		UTMSource:   c.Query("utm_source"),
+		UTMCampaign: c.Query("utm_campaign"),
+		From:        from,
+		To:          to,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to load referral funnel")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = funnel
+	return c.Status(status).JSON(response)
+}
+
+// parseQRQuery reads the format and size query parameters; a size of 0 means the default
+func parseQRQuery(c *fiber.Ctx) (string, int, error) {
+	format := c.Query("format", referral_link.QRFormatPNG)
+	if format != referral_link.QRFormatPNG && format != referral_link.QRFormatSVG {
+		return "", 0, errors.New("format must be png or svg")
+	}
+
+	size := 0
+	if raw := c.Query("size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", 0, errors.New("size must be a number")
+		}
+		size = parsed
+	}
+	return format, size, nil
+}
+
+func sendQRCode(c *fiber.Ctx, qrCode *referral_link.QRCode) error {
+	c.Set(fiber.HeaderContentType, qrCode.ContentType)
+	return c.Status(fiber.StatusOK).Send(qrCode.Image)
+}
+
+func (h *ReferralLinkHTTPHandler) linkError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, referral_link.ErrLinksDisabled):
+		status, response := shared.NewErrorResponse("ERR_1052")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, referral_link.ErrCodeNotFound):
+		status, response := shared.NewErrorResponse("ERR_404", "Referral code not found")
+		return c.Status(status).JSON(response)
+	}
+	status, response := shared.NewErrorResponse("ERR_500", "Failed to build referral link")
+	return c.Status(status).JSON(response)
+}
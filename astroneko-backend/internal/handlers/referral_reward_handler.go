@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReferralRewardHTTPHandler struct {
+	referralRewardService *services.ReferralRewardService
+}
+
+// NewReferralRewardHTTPHandler creates a new referral reward HTTP handler
+func NewReferralRewardHTTPHandler(referralRewardService *services.ReferralRewardService) *ReferralRewardHTTPHandler {
+	return &ReferralRewardHTTPHandler{
+		referralRewardService: referralRewardService,
+	}
+}
+
+// GetMyRewards godoc
+// @Summary Get my referral rewards
+// @Description Show what the authenticated user earned when their referral codes were redeemed: bonus replies (used after the daily limit), extra daily replies, badges and the full reward history, plus what the next invite earns and the next badge to unlock.
+// @Tags users/referral
+// @Accept json
+// @Produce json
+// @Success 200 {object} referral_reward.MyRewardsResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/rewards [get]
+func (h *ReferralRewardHTTPHandler) GetMyRewards(c *fiber.Ctx) error {
+	userFromContext := c.Locals("user")
+	if userFromContext == nil {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	userEntity, ok := userFromContext.(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrInvalidUserDataInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	rewards, err := h.referralRewardService.GetMyRewards(c.Context(), userEntity)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to retrieve rewards")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = rewards
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/thai_astrology"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ThaiAstrologyHTTPHandler struct {
+	thaiAstrologyService *services.ThaiAstrologyService
+}
+
+// NewThaiAstrologyHTTPHandler creates a new Thai astrology HTTP handler
+func NewThaiAstrologyHTTPHandler(thaiAstrologyService *services.ThaiAstrologyService) *ThaiAstrologyHTTPHandler {
+	return &ThaiAstrologyHTTPHandler{
+		thaiAstrologyService: thaiAstrologyService,
+	}
+}
+
+// GetLunarDate godoc
+// @Summary Convert a date to the Thai lunar calendar
+// @Description Convert a Gregorian date to the Thai lunar calendar (ขึ้น/แรม ค่ำ เดือน), with Buddhist Era, animal year and the year's intercalation. Supported from 1900 to 2199.
+// @Tags thai-astrology
+// @Accept json
+// @Produce json
+// @Param date query string false "Date in YYYY-MM-DD (default: today in Asia/Bangkok)"
+// @Success 200 {object} thai_astrology.LunarDate
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/thai-astrology/lunar-date [get]
+func (h *ThaiAstrologyHTTPHandler) GetLunarDate(c *fiber.Ctx) error {
+	date, ok := h.parseDate(c, "date")
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid date format, expected YYYY-MM-DD")
+		return c.Status(status).JSON(response)
+	}
+
+	lunarDate, err := h.thaiAstrologyService.GetLunarDate(date)
+	if err != nil {
+		return h.handleCalendarError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = lunarDate
+	return c.Status(status).JSON(response)
+}
+
+// GetDayColors godoc
+// @Summary Get weekday colour tables
+// @Description List the auspicious colours of each weekday by Thaksa position, including the กาลกิณี colour to avoid.
+// @Tags thai-astrology
+// @Accept json
+// @Produce json
+// @Success 200 {array} thai_astrology.DayColorTable
+// @Router /v1/api/thai-astrology/day-colors [get]
+func (h *ThaiAstrologyHTTPHandler) GetDayColors(c *fiber.Ctx) error {
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = h.thaiAstrologyService.GetDayColors()
+	return c.Status(status).JSON(response)
+}
+
+// ListWanPhra godoc
+// @Summary List Buddhist holy days
+// @Description List the wan phra (วันพระ) of a Gregorian month, marking Buddhist holidays such as Makha Bucha and Visakha Bucha.
+// @Tags thai-astrology
+// @Accept json
+// @Produce json
+// @Param year query int false "Gregorian year (default: current year)"
+// @Param month query int false "Month 1-12 (default: current month)"
+// @Success 200 {array} thai_astrology.WanPhraDay
+// @Failure 400 {object} shared.ResponseBody
+// @Router /v1/api/thai-astrology/wan-phra [get]
+func (h *ThaiAstrologyHTTPHandler) ListWanPhra(c *fiber.Ctx) error {
+	today := h.thaiAstrologyService.Today()
+
+	year, err := strconv.Atoi(c.Query("year", strconv.Itoa(today.Year())))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid year")
+		return c.Status(status).JSON(response)
+	}
+
+	month, err := strconv.Atoi(c.Query("month", strconv.Itoa(int(today.Month()))))
+	if err != nil || month < 1 || month > 12 {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid month, expected 1-12")
+		return c.Status(status).JSON(response)
+	}
+
+	days, err := h.thaiAstrologyService.ListWanPhra(year, time.Month(month))
+	if err != nil {
+		return h.handleCalendarError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = days
+	return c.Status(status).JSON(response)
+}
+
+// GetMyAuspiciousDays godoc
+// @Summary Get auspicious days
+// @Description Score upcoming days for the authenticated user's birth weekday using Thaksa positions, the moon phase and Buddhist holy days. Requires a birth profile.
+// @Tags thai-astrology
+// @Accept json
+// @Produce json
+// @Param from query string false "First date in YYYY-MM-DD (default: today in Asia/Bangkok)"
+// @Param days query int false "Number of days to score (default: 30, max: 90)"
+// @Success 200 {object} thai_astrology.AuspiciousDaysResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/me/auspicious-days [get]
+func (h *ThaiAstrologyHTTPHandler) GetMyAuspiciousDays(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	from, ok := h.parseDate(c, "from")
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid date format, expected YYYY-MM-DD")
+		return c.Status(status).JSON(response)
+	}
+
+	days, err := strconv.Atoi(c.Query("days", strconv.Itoa(services.DefaultAuspiciousDays)))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid days")
+		return c.Status(status).JSON(response)
+	}
+
+	auspiciousDays, err := h.thaiAstrologyService.GetAuspiciousDays(userEntity, from, days)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrBirthProfileNotSet):
+			status, response := shared.NewErrorResponse("ERR_404", "Birth profile not set")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrInvalidRequest):
+			status, response := shared.NewErrorResponse("ERR_400", err.Error())
+			return c.Status(status).JSON(response)
+		}
+		return h.handleCalendarError(c, err)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = auspiciousDays
+	return c.Status(status).JSON(response)
+}
+
+// parseDate reads an optional YYYY-MM-DD query parameter, defaulting to today
+func (h *ThaiAstrologyHTTPHandler) parseDate(c *fiber.Ctx, key string) (time.Time, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return h.thaiAstrologyService.Today(), true
+	}
+
+	date, err := time.Parse(thai_astrology.DateLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func (h *ThaiAstrologyHTTPHandler) handleCalendarError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, shared.ErrLunarDateOutOfRange) {
+		status, response := shared.NewErrorResponse("ERR_400", "Date must be between 1900-01-01 and 2199-12-31")
+		return c.Status(status).JSON(response)
+	}
+	status, response := shared.NewErrorResponse("ERR_500", "Failed to compute the Thai calendar")
+	return c.Status(status).JSON(response)
+}
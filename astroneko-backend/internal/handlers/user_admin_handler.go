@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
+	"astroneko-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserAdminHTTPHandler struct {
+	userAdminService *services.UserAdminService
+	validator        validator.Validator
+}
+
+// NewUserAdminHTTPHandler creates a new CRM app user management HTTP handler
+func NewUserAdminHTTPHandler(userAdminService *services.UserAdminService, validator validator.Validator) *UserAdminHTTPHandler {
+	return &UserAdminHTTPHandler{
+		userAdminService: userAdminService,
+		validator:        validator,
+	}
+}
+
+// ListUsers godoc
+// @Summary List app users
+// @Description Get a paginated list of app users
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default: 10, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} user.ListUsersResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users [get]
+func (h *UserAdminHTTPHandler) ListUsers(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := h.userAdminService.ListUsers(c.Context(), limit, offset)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1030", "Failed to list users")
+		return c.Status(status).JSON(response)
+	}
+
+	userResponses := make([]user.UserResponse, 0, len(users))
+	for _, u := range users {
+		userResponses = append(userResponses, *u.ToResponse())
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = user.ListUsersResponse{
+		Users:  userResponses,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	return c.Status(status).JSON(response)
+}
+
+// GetUser godoc
+// @Summary Get app user
+// @Description Get any app user by ID
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} user.GetUserResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users/{id} [get]
+func (h *UserAdminHTTPHandler) GetUser(c *fiber.Ctx) error {
+	found, err := h.userAdminService.GetUser(c.Context(), c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = found.ToResponse()
+	return c.Status(status).JSON(response)
+}
+
+// CreateUser godoc
+// @Summary Create app user
+// @Description Create an app user with a Firebase email and password account. The change is recorded in the audit log.
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param user body user.CreateUserRequest true "User data"
+// @Success 201 {object} user.CreateUserResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users [post]
+func (h *UserAdminHTTPHandler) CreateUser(c *fiber.Ctx) error {
+	var req user.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	created, err := h.userAdminService.CreateUser(c.Context(), middleware.CRMActor(c), &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			status, response := shared.NewErrorResponse("ERR_1028")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_1025")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = created.ToResponse()
+	return c.Status(status).JSON(response)
+}
+
+// UpdateUser godoc
+// @Summary Update app user
+// @Description Update any field of an app user, including the referral activation. The change is recorded in the audit log.
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body user.UpdateUserRequest true "Updated user data"
+// @Success 200 {object} user.UpdateUserResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users/{id} [put]
+func (h *UserAdminHTTPHandler) UpdateUser(c *fiber.Ctx) error {
+	var req user.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	updated, err := h.userAdminService.UpdateUser(c.Context(), middleware.CRMActor(c), c.Params("id"), &req)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = updated.ToResponse()
+	return c.Status(status).JSON(response)
+}
+
+// DeleteUser godoc
+// @Summary Delete app user
+// @Description Schedule an app user's account for deletion on the next purge run, without the grace period of a self-service deletion. All owned data and the Firebase account are removed. The change is recorded in the audit log.
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 202 {object} account_deletion.DeletionStatusResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users/{id} [delete]
+func (h *UserAdminHTTPHandler) DeleteUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := h.userAdminService.GetUser(c.Context(), id); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
+		return c.Status(status).JSON(response)
+	}
+
+	deletion, err := h.userAdminService.DeleteUser(c.Context(), middleware.CRMActor(c), id)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1027", "Failed to schedule user deletion")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_202")
+	response.Data = deletion
+	return c.Status(status).JSON(response)
+}
+
+// UnlockLogin godoc
+// @Summary Unlock app user login
+// @Description Clear the failed login count of an app user, ending a backoff or lockout before it expires. Blocks on the IP addresses used are not affected. The change is recorded in the audit log.
+// @Tags crm-app-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} credential_attempt.UnlockResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/app-users/{id}/unlock [post]
+func (h *UserAdminHTTPHandler) UnlockLogin(c *fiber.Ctx) error {
+	result, err := h.userAdminService.UnlockLogin(c.Context(), middleware.CRMActor(c), c.Params("id"))
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = result
+	return c.Status(status).JSON(response)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"strings"
 
 	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/user"
 	"astroneko-backend/internal/services"
@@ -16,65 +18,32 @@ import (
 type UserHTTPHandler struct {
 	userService         *services.UserService
 	referralCodeService *services.ReferralCodeService
+	referralLinkService *services.ReferralLinkService
 	validator           validator.Validator
 }
 
-func NewUserHTTPHandler(userService *services.UserService, referralCodeService *services.ReferralCodeService, validator validator.Validator) *UserHTTPHandler {
+func NewUserHTTPHandler(userService *services.UserService, referralCodeService *services.ReferralCodeService, referralLinkService *services.ReferralLinkService, validator validator.Validator) *UserHTTPHandler {
 	return &UserHTTPHandler{
 		userService:         userService,
 		referralCodeService: referralCodeService,
+		referralLinkService: referralLinkService,
 		validator:           validator,
 	}
 }
 
-// CreateUser godoc
-// @Summary Create a new user
-// @Description Create a new user with Firebase authentication
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param user body user.CreateUserRequest true "User data"
-// @Success 201 {object} user.CreateUserResponse
-// @Failure 400 {object} shared.ResponseBody
-// @Failure 500 {object} shared.ResponseBody
-// @Router /v1/api/users [post]
-func (h *UserHTTPHandler) CreateUser(c *fiber.Ctx) error {
-	var req user.CreateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
-		return c.Status(status).JSON(response)
-	}
-
-	if err := h.validator.ValidateStruct(&req); err != nil {
-		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
-		return c.Status(status).JSON(response)
-	}
-
-	newUser, err := h.userService.CreateUser(c.Context(), &req)
-	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			status, response := shared.NewErrorResponse("ERR_409", err.Error())
-			return c.Status(status).JSON(response)
-		}
-		status, response := shared.NewErrorResponse("ERR_500", "Failed to create user")
-		return c.Status(status).JSON(response)
-	}
-
-	status, response := shared.NewSuccessResponse("SUC_201")
-	response.Data = newUser.ToResponse()
-	return c.Status(status).JSON(response)
-}
-
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Get user information by user ID
+// @Description Get the authenticated user's information. The ID must be the caller's own user ID; CRM staff use /v1/api/crm/app-users/{id}.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} user.GetUserResponse
 // @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
 // @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
 // @Router /v1/api/users/{id} [get]
 func (h *UserHTTPHandler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -85,7 +54,7 @@ func (h *UserHTTPHandler) GetUserByID(c *fiber.Ctx) error {
 
 	user, err := h.userService.GetUserByID(c.Context(), id)
 	if err != nil {
-		status, response := shared.NewErrorResponse("ERR_404", ErrUserNotFound)
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
 		return c.Status(status).JSON(response)
 	}
 
@@ -96,15 +65,18 @@ func (h *UserHTTPHandler) GetUserByID(c *fiber.Ctx) error {
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update user information
+// @Description Update the authenticated user's display name and profile image. The ID must be the caller's own user ID; other fields can only be changed by CRM staff.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Param user body user.UpdateUserRequest true "Updated user data"
+// @Param user body user.UpdateProfileRequest true "Updated profile"
 // @Success 200 {object} user.UpdateUserResponse
 // @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
 // @Failure 404 {object} shared.ResponseBody
+// @Security BearerAuth
 // @Router /v1/api/users/{id} [put]
 func (h *UserHTTPHandler) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -113,47 +85,25 @@ func (h *UserHTTPHandler) UpdateUser(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
-	var req user.UpdateUserRequest
+	var req user.UpdateProfileRequest
 	if err := c.BodyParser(&req); err != nil {
 		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
 		return c.Status(status).JSON(response)
 	}
 
-	updatedUser, err := h.userService.UpdateUser(c.Context(), id, &req)
-	if err != nil {
-		status, response := shared.NewErrorResponse("ERR_404", ErrUserNotFound)
-		return c.Status(status).JSON(response)
-	}
-
-	status, response := shared.NewSuccessResponse("SUC_200")
-	response.Data = updatedUser.ToResponse()
-	return c.Status(status).JSON(response)
-}
-
-// DeleteUser godoc
-// @Summary Delete user
-// @Description Delete a user
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path string true "User ID"
-// @Success 204 {object} user.DeleteUserResponse
-// @Failure 400 {object} shared.ResponseBody
-// @Failure 404 {object} shared.ResponseBody
-// @Router /v1/api/users/{id} [delete]
-func (h *UserHTTPHandler) DeleteUser(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		status, response := shared.NewErrorResponse("ERR_1029", ErrUserIDRequired)
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
 		return c.Status(status).JSON(response)
 	}
 
-	if err := h.userService.DeleteUser(c.Context(), id); err != nil {
-		status, response := shared.NewErrorResponse("ERR_404", ErrUserNotFound)
+	updatedUser, err := h.userService.UpdateProfile(c.Context(), id, &req)
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_1023", ErrUserNotFound)
 		return c.Status(status).JSON(response)
 	}
 
-	status, response := shared.NewSuccessResponse("SUC_204")
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = updatedUser.ToResponse()
 	return c.Status(status).JSON(response)
 }
 
@@ -198,7 +148,7 @@ func (h *UserHTTPHandler) RefreshToken(c *fiber.Ctx) error {
 
 // Login godoc
 // @Summary Login with email and password
-// @Description Authenticate user with email and password, check both database and Firebase
+// @Description Authenticate user with email and password, check both database and Firebase. Repeated failures for an email or from an IP are answered with 429 and a Retry-After header, growing to a temporary lockout.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -206,6 +156,7 @@ func (h *UserHTTPHandler) RefreshToken(c *fiber.Ctx) error {
 // @Success 200 {object} user.RefreshTokenResponse
 // @Failure 400 {object} shared.ResponseBody
 // @Failure 401 {object} shared.ResponseBody
+// @Failure 429 {object} shared.ResponseBody
 // @Failure 500 {object} shared.ResponseBody
 // @Router /v1/api/auth/login [post]
 func (h *UserHTTPHandler) Login(c *fiber.Ctx) error {
@@ -220,14 +171,15 @@ func (h *UserHTTPHandler) Login(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	req.ClientIP = c.IP()
 	authResp, err := h.userService.Login(c.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "user not found") {
-			status, response := shared.NewErrorResponse("ERR_401", "Invalid email or password")
-			return c.Status(status).JSON(response)
+		if errors.Is(err, shared.ErrTooManyAttempts) {
+			return respondTooManyAttempts(c, err)
 		}
-		if strings.Contains(err.Error(), "authentication failed") {
-			status, response := shared.NewErrorResponse("ERR_401", "Invalid email or password")
+		// Unknown email and wrong password look the same so accounts cannot be enumerated
+		if errors.Is(err, shared.ErrUserNotFound) || errors.Is(err, shared.ErrFirebaseAuthFailed) {
+			status, response := shared.NewErrorResponse("ERR_1050", "Invalid email or password")
 			return c.Status(status).JSON(response)
 		}
 		status, response := shared.NewErrorResponse("ERR_500", "Login failed")
@@ -355,7 +307,7 @@ func (h *UserHTTPHandler) Logout(c *fiber.Ctx) error {
 
 // GetUserReferralCodes godoc
 // @Summary Get user referral codes
-// @Description Get or generate user referral codes (5 codes with 8 characters each). Requires user to have is_activated_referral = true
+// @Description Get the user referral codes, issuing new ones when the allotment policy says the user is due some (by default 5 codes of 8 characters plus a check character). Unused codes past their expiry are left out. Each code comes with its deep link when links are configured. Requires user to have is_activated_referral = true
 // @Tags referral
 // @Accept json
 // @Produce json
@@ -397,7 +349,9 @@ func (h *UserHTTPHandler) GetUserReferralCodes(c *fiber.Ctx) error {
 	// Convert to response format
 	var codeResponses []referral_code.UserReferralCodeResponse
 	for _, code := range userReferralCodes {
-		codeResponses = append(codeResponses, *code.ToResponse())
+		codeResponse := code.ToResponse()
+		codeResponse.Link = h.referralLinkService.DeepLink(code.ReferralCode, referral_link.UTMParams{})
+		codeResponses = append(codeResponses, *codeResponse)
 	}
 
 	responseData := referral_code.GetUserReferralCodesResponse{
@@ -411,7 +365,7 @@ func (h *UserHTTPHandler) GetUserReferralCodes(c *fiber.Ctx) error {
 
 // ActivateReferral godoc
 // @Summary Activate referral code for authenticated user
-// @Description Activate referral code with improved validation and logging. Sets is_activated_referral = true in user table. Returns error if user has already activated a referral code.
+// @Description Activate referral code with improved validation and logging. Sets is_activated_referral = true in user table and applies the tier or bonus quota the code grants. Pass the click_id from the deep link landing page to attribute the redemption to the click. Returns error if user has already activated a referral code, and ERR_1051 with a reason when a general code has not started, has expired or has reached its redemption limit, or when a user code fails its check character.
 // @Tags referral
 // @Accept json
 // @Produce json
@@ -451,6 +405,11 @@ func (h *UserHTTPHandler) ActivateReferral(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	// Attribution is best effort, a click that cannot be claimed does not block the redemption
+	if req.ClickID != nil {
+		_, _ = h.referralLinkService.ClaimClick(c.Context(), userID, *req.ClickID)
+	}
+
 	activationResponse, err := h.referralCodeService.ActivateReferralCode(c.Context(), userID, req.ReferralCode)
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_500", "Failed to activate referral code")
@@ -463,6 +422,10 @@ func (h *UserHTTPHandler) ActivateReferral(c *fiber.Ctx) error {
 			status, response := shared.NewErrorResponse("ERR_1033")
 			return c.Status(status).JSON(response)
 		}
+		if activationResponse.Reason != "" {
+			status, response := shared.NewErrorResponse("ERR_1051", activationResponse.Message)
+			return c.Status(status).JSON(response)
+		}
 		// Default to invalid referral code for other failures
 		status, response := shared.NewErrorResponse("ERR_1032")
 		return c.Status(status).JSON(response)
@@ -473,6 +436,65 @@ func (h *UserHTTPHandler) ActivateReferral(c *fiber.Ctx) error {
 	return c.Status(status).JSON(response)
 }
 
+// ClaimVanityCode godoc
+// @Summary Claim a vanity referral code
+// @Description Claim a referral code of your choosing once enough people have redeemed your codes. The code is normalized (spaces removed, upper-cased), must be letters A-Z and digits only, and must not be reserved or contain a blocked word. A vanity code can be redeemed any number of times and does not count towards your code allotment. Each user can claim one.
+// @Tags referral
+// @Accept json
+// @Produce json
+// @Param referral body referral_code.ClaimVanityCodeRequest true "Vanity code"
+// @Success 201 {object} referral_code.UserReferralCodeResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/auth/referral/vanity [post]
+func (h *UserHTTPHandler) ClaimVanityCode(c *fiber.Ctx) error {
+	userEntity, ok := c.Locals("user").(*user.User)
+	if !ok {
+		status, response := shared.NewErrorResponse("ERR_401", ErrUserNotFoundInContext)
+		return c.Status(status).JSON(response)
+	}
+
+	var req referral_code.ClaimVanityCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	vanityCode, err := h.referralCodeService.ClaimVanityCode(c.Context(), userEntity.ID, req.ReferralCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, referral_code.ErrVanityCodeRejected):
+			status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+			return c.Status(status).JSON(response)
+		case errors.Is(err, referral_code.ErrVanityNotEligible):
+			status, response := shared.NewErrorResponse("ERR_1060", err.Error())
+			return c.Status(status).JSON(response)
+		case errors.Is(err, referral_code.ErrVanityCodeTaken):
+			status, response := shared.NewErrorResponse("ERR_1061")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, referral_code.ErrVanityAlreadyClaimed):
+			status, response := shared.NewErrorResponse("ERR_1062")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to claim vanity referral code")
+		return c.Status(status).JSON(response)
+	}
+
+	codeResponse := vanityCode.ToResponse()
+	codeResponse.Link = h.referralLinkService.DeepLink(vanityCode.ReferralCode, referral_link.UTMParams{})
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = codeResponse
+	return c.Status(status).JSON(response)
+}
+
 // GetTotalUsers godoc
 // @Summary Get total number of users
 // @Description Get the total count of users in the system (CRM access required)
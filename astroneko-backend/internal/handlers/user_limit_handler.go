@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"astroneko-backend/internal/core/domain/audit"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/user_limit"
 	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
 	"astroneko-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,12 +13,14 @@ import (
 
 type UserLimitHTTPHandler struct {
 	userLimitService *services.UserLimitService
+	auditService     *services.AuditService
 	validator        validator.Validator
 }
 
-func NewUserLimitHTTPHandler(userLimitService *services.UserLimitService, validator validator.Validator) *UserLimitHTTPHandler {
+func NewUserLimitHTTPHandler(userLimitService *services.UserLimitService, auditService *services.AuditService, validator validator.Validator) *UserLimitHTTPHandler {
 	return &UserLimitHTTPHandler{
 		userLimitService: userLimitService,
+		auditService:     auditService,
 		validator:        validator,
 	}
 }
@@ -68,12 +72,19 @@ func (h *UserLimitHTTPHandler) UpdateUserLimit(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
+	var before *user_limit.UserLimitResponse
+	if existing, err := h.userLimitService.GetUserLimit(c.Context()); err == nil {
+		before = existing.ToResponse()
+	}
+
 	updatedUserLimit, err := h.userLimitService.UpdateUserLimit(c.Context(), &req)
 	if err != nil {
 		status, response := shared.NewErrorResponse("ERR_500", "Failed to update user limit")
 		return c.Status(status).JSON(response)
 	}
 
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionUserLimitUpdated, audit.TargetUserLimit, updatedUserLimit.ID.String(), before, updatedUserLimit.ToResponse())
+
 	status, response := shared.NewSuccessResponse("SUC_200")
 	response.Data = updatedUserLimit.ToResponse()
 	return c.Status(status).JSON(response)
@@ -68,7 +68,7 @@ func (h *UserReferralHandler) ActivateReferral(c *fiber.Ctx) error {
 
 // GetUserReferralCodes godoc
 // @Summary Get user referral codes
-// @Description Get all referral codes for a user
+// @Description Get the referral codes of a user, issuing new ones when the allotment policy says the user is due some
 // @Tags users/referral
 // @Accept json
 // @Produce json
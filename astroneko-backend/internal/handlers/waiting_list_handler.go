@@ -1,11 +1,15 @@
 package handlers
 
 import (
-	"strings"
+	"errors"
+	"strconv"
 
+	"astroneko-backend/internal/core/domain/audit"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/waiting_list"
 	"astroneko-backend/internal/services"
+	"astroneko-backend/pkg/middleware"
+	"astroneko-backend/pkg/utils"
 	"astroneko-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,30 +17,48 @@ import (
 
 type WaitingListHTTPHandler struct {
 	waitingListService *services.WaitingListService
+	auditService       *services.AuditService
 	validator          validator.Validator
 }
 
-func NewWaitingListHTTPHandler(waitingListService *services.WaitingListService, validator validator.Validator) *WaitingListHTTPHandler {
+func NewWaitingListHTTPHandler(waitingListService *services.WaitingListService, auditService *services.AuditService, validator validator.Validator) *WaitingListHTTPHandler {
 	return &WaitingListHTTPHandler{
 		waitingListService: waitingListService,
+		auditService:       auditService,
 		validator:          validator,
 	}
 }
 
 // JoinWaitingList godoc
 // @Summary Join waiting list
-// @Description Add user to the waiting list by email
+// @Description Add user to the waiting list by email. Alias of /v1/api/waiting-lists/astroneko/join.
 // @Tags waiting_list
 // @Accept json
 // @Produce json
 // @Param request body waiting_list.JoinWaitingListRequest true "Join waiting list request"
 // @Success 201 {object} waiting_list.JoinWaitingListResponse
 // @Failure 400 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
 // @Failure 409 {object} shared.ResponseBody
 // @Failure 500 {object} shared.ResponseBody
 // @Router /v1/api/waiting-list/join [post]
 func (h *WaitingListHTTPHandler) JoinWaitingList(c *fiber.Ctx) error {
-	var req waiting_list.JoinWaitingListRequest
+	return h.join(c, waiting_list.DefaultListSlug)
+}
+
+// IsInWaitingListByEmail godoc
+// @Summary Check if email is in waiting list
+// @Description Check if an email exists in the waiting list. Alias of /v1/api/waiting-lists/astroneko/check.
+// @Tags waiting_list
+// @Accept json
+// @Produce json
+// @Param request body waiting_list.CheckWaitingListRequest true "Check waiting list request"
+// @Success 200 {object} waiting_list.IsInWaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/waiting-list/check [post]
+func (h *WaitingListHTTPHandler) IsInWaitingListByEmail(c *fiber.Ctx) error {
+	var req waiting_list.CheckWaitingListRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		status, response := shared.NewErrorResponse("ERR_400", "Invalid request body")
@@ -48,38 +70,138 @@ func (h *WaitingListHTTPHandler) JoinWaitingList(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
-	waitingListUser, err := h.waitingListService.JoinWaitingList(c.Context(), req.Email)
+	isInWaitingList, err := h.waitingListService.IsInWaitingListByEmail(c.Context(), waiting_list.DefaultListSlug, req.Email)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists in waiting list") {
-			status, response := shared.NewErrorResponse("ERR_1034")
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to check waiting list status")
+		return c.Status(status).JSON(response)
+	}
+
+	response := waiting_list.NewIsInWaitingListResponse(isInWaitingList)
+	status, resp := shared.NewSuccessResponse("SUC_200")
+	resp.Data = response
+	return c.Status(status).JSON(resp)
+}
+
+// GetStatus godoc
+// @Summary Get waiting list position
+// @Description Get the position, total and invite link of the entry a status token was emailed for. Works for entries of every waiting list.
+// @Tags waiting_list
+// @Produce json
+// @Param token query string true "Status token from the join email"
+// @Success 200 {object} waiting_list.WaitingListStatusResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/waiting-list/status [get]
+func (h *WaitingListHTTPHandler) GetStatus(c *fiber.Ctx) error {
+	statusResponse, err := h.waitingListService.GetStatus(c.Context(), c.Query("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, waiting_list.ErrInvalidStatusToken):
+			status, response := shared.NewErrorResponse("ERR_1059")
 			return c.Status(status).JSON(response)
-		}
-		if strings.Contains(err.Error(), "failed to add user to waiting list") {
-			status, response := shared.NewErrorResponse("ERR_1035")
+		case errors.Is(err, waiting_list.ErrEntryNotFound):
+			status, response := shared.NewErrorResponse("ERR_404", "Waiting list entry not found")
 			return c.Status(status).JSON(response)
 		}
-		status, response := shared.NewErrorResponse("ERR_500", "Failed to join waiting list")
-		return c.Status(status).JSON(response)
+		return h.listError(c, err, "Failed to get waiting list status")
 	}
 
-	status, response := shared.NewSuccessResponse("SUC_201")
-	response.Data = waitingListUser.ToResponse()
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = statusResponse
 	return c.Status(status).JSON(response)
 }
 
-// IsInWaitingListByEmail godoc
-// @Summary Check if email is in waiting list
-// @Description Check if an email exists in the waiting list
+// JoinAstroBoxingWaitingList godoc
+// @Summary Join astro boxing waiting list
+// @Description Add user to the astro boxing waiting list. Alias of /v1/api/waiting-lists/astro-boxing/join.
+// @Tags astro_boxing_waiting_list
+// @Accept json
+// @Produce json
+// @Param request body waiting_list.JoinWaitingListRequest true "Join request"
+// @Success 201 {object} waiting_list.JoinWaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/astro-boxing-waiting-list/join [post]
+func (h *WaitingListHTTPHandler) JoinAstroBoxingWaitingList(c *fiber.Ctx) error {
+	return h.join(c, waiting_list.AstroBoxingListSlug)
+}
+
+// IsInAstroBoxingWaitingListByEmail godoc
+// @Summary Check if email is in astro boxing waiting list
+// @Description Check if an email exists in the astro boxing waiting list. Alias of /v1/api/waiting-lists/astro-boxing/check.
+// @Tags astro_boxing_waiting_list
+// @Accept json
+// @Produce json
+// @Param email query string true "Email to check"
+// @Success 200 {object} waiting_list.CheckWaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/astro-boxing-waiting-list/check [get]
+func (h *WaitingListHTTPHandler) IsInAstroBoxingWaitingListByEmail(c *fiber.Ctx) error {
+	return h.check(c, waiting_list.AstroBoxingListSlug)
+}
+
+// GetListInfo godoc
+// @Summary Get a waiting list
+// @Description Get whether a waiting list is open, the fields it requires when joining and, for lists with a capacity, how many spots are left
+// @Tags waiting_list
+// @Produce json
+// @Param slug path string true "Waiting list slug"
+// @Success 200 {object} waiting_list.WaitingListInfoResponse
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/waiting-lists/{slug} [get]
+func (h *WaitingListHTTPHandler) GetListInfo(c *fiber.Ctx) error {
+	info, err := h.waitingListService.GetListInfo(c.Context(), c.Params("slug"))
+	if err != nil {
+		return h.listError(c, err, "Failed to get waiting list")
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = info
+	return c.Status(status).JSON(response)
+}
+
+// JoinList godoc
+// @Summary Join a waiting list
+// @Description Add an email to the waiting list with the given slug. fields must hold every field the list requires. invite_code credits the entry whose invite link was followed. The response carries the new entry's position and invite link, and a status link is emailed to the joiner.
 // @Tags waiting_list
 // @Accept json
 // @Produce json
-// @Param request body waiting_list.CheckWaitingListRequest true "Check waiting list request"
-// @Success 200 {object} waiting_list.IsInWaitingListResponse
+// @Param slug path string true "Waiting list slug"
+// @Param request body waiting_list.JoinWaitingListRequest true "Join waiting list request"
+// @Success 201 {object} waiting_list.JoinWaitingListResponse
 // @Failure 400 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
 // @Failure 500 {object} shared.ResponseBody
-// @Router /v1/api/waiting-list/check [post]
-func (h *WaitingListHTTPHandler) IsInWaitingListByEmail(c *fiber.Ctx) error {
-	var req waiting_list.CheckWaitingListRequest
+// @Router /v1/api/waiting-lists/{slug}/join [post]
+func (h *WaitingListHTTPHandler) JoinList(c *fiber.Ctx) error {
+	return h.join(c, c.Params("slug"))
+}
+
+// CheckList godoc
+// @Summary Check if email is in a waiting list
+// @Description Check if an email exists in the waiting list with the given slug
+// @Tags waiting_list
+// @Produce json
+// @Param slug path string true "Waiting list slug"
+// @Param email query string true "Email to check"
+// @Success 200 {object} waiting_list.CheckWaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Router /v1/api/waiting-lists/{slug}/check [get]
+func (h *WaitingListHTTPHandler) CheckList(c *fiber.Ctx) error {
+	return h.check(c, c.Params("slug"))
+}
+
+func (h *WaitingListHTTPHandler) join(c *fiber.Ctx, slug string) error {
+	var req waiting_list.JoinWaitingListRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		status, response := shared.NewErrorResponse("ERR_400", "Invalid request body")
@@ -91,14 +213,269 @@ func (h *WaitingListHTTPHandler) IsInWaitingListByEmail(c *fiber.Ctx) error {
 		return c.Status(status).JSON(response)
 	}
 
-	isInWaitingList, err := h.waitingListService.IsInWaitingListByEmail(c.Context(), req.Email)
+	joined, err := h.waitingListService.JoinWaitingList(c.Context(), slug, &waiting_list.Join{
+		Email:       req.Email,
+		Fields:      req.Fields,
+		InviteCode:  req.InviteCode,
+		Fingerprint: utils.DeviceFingerprint(c),
+	})
 	if err != nil {
-		status, response := shared.NewErrorResponse("ERR_500", "Failed to check waiting list status")
+		switch {
+		case errors.Is(err, shared.ErrWaitingListUserAlreadyExists):
+			status, response := shared.NewErrorResponse("ERR_1034")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, shared.ErrWaitingListUserCreationFailed):
+			status, response := shared.NewErrorResponse("ERR_1035")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, waiting_list.ErrListClosed):
+			status, response := shared.NewErrorResponse("ERR_1054")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, waiting_list.ErrListFull):
+			status, response := shared.NewErrorResponse("ERR_1055")
+			return c.Status(status).JSON(response)
+		case errors.Is(err, waiting_list.ErrMissingFields), errors.Is(err, waiting_list.ErrInvalidFields):
+			status, response := shared.NewErrorResponse("ERR_1056", err.Error())
+			return c.Status(status).JSON(response)
+		case errors.Is(err, waiting_list.ErrDisposableEmail):
+			status, response := shared.NewErrorResponse("ERR_1058")
+			return c.Status(status).JSON(response)
+		}
+		return h.listError(c, err, "Failed to join waiting list")
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = joined
+	return c.Status(status).JSON(response)
+}
+
+func (h *WaitingListHTTPHandler) check(c *fiber.Ctx, slug string) error {
+	email := c.Query("email")
+	if email == "" {
+		status, response := shared.NewErrorResponse("ERR_400", "Email query parameter is required")
 		return c.Status(status).JSON(response)
 	}
 
-	response := waiting_list.NewIsInWaitingListResponse(isInWaitingList)
-	status, resp := shared.NewSuccessResponse("SUC_200")
-	resp.Data = response
-	return c.Status(status).JSON(resp)
+	type emailValidation struct {
+		Email string `validate:"required,email"`
+	}
+	emailStruct := emailValidation{Email: email}
+	if err := h.validator.ValidateStruct(&emailStruct); err != nil {
+		status, response := shared.NewErrorResponse("ERR_400", "Invalid email format")
+		return c.Status(status).JSON(response)
+	}
+
+	isInWaitingList, err := h.waitingListService.IsInWaitingListByEmail(c.Context(), slug, email)
+	if err != nil {
+		return h.listError(c, err, "Failed to check waiting list status")
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = waiting_list.CheckWaitingListResponse{
+		IsInWaitingList: isInWaitingList,
+	}
+	return c.Status(status).JSON(response)
+}
+
+// ListLists godoc
+// @Summary List waiting lists
+// @Description List every waiting list with its settings and number of entries. Requires the waiting_lists:read permission.
+// @Tags crm-waiting-lists
+// @Produce json
+// @Success 200 {array} waiting_list.WaitingListResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/waiting-lists [get]
+func (h *WaitingListHTTPHandler) ListLists(c *fiber.Ctx) error {
+	lists, err := h.waitingListService.ListLists(c.Context())
+	if err != nil {
+		status, response := shared.NewErrorResponse("ERR_500", "Failed to list waiting lists")
+		return c.Status(status).JSON(response)
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = lists
+	return c.Status(status).JSON(response)
+}
+
+// CreateList godoc
+// @Summary Create a waiting list
+// @Description Create a waiting list for a new product. The slug is used in the list's public URLs and cannot be changed. Requires the waiting_lists:write permission.
+// @Tags crm-waiting-lists
+// @Accept json
+// @Produce json
+// @Param request body waiting_list.CreateWaitingListRequest true "Waiting list"
+// @Success 201 {object} waiting_list.WaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 409 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/waiting-lists [post]
+func (h *WaitingListHTTPHandler) CreateList(c *fiber.Ctx) error {
+	var req waiting_list.CreateWaitingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	list, err := h.waitingListService.CreateList(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, waiting_list.ErrListAlreadyExists) {
+			status, response := shared.NewErrorResponse("ERR_1057")
+			return c.Status(status).JSON(response)
+		}
+		return h.listError(c, err, "Failed to create waiting list")
+	}
+
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionWaitingListCreated, audit.TargetWaitingList, list.ID.String(), nil, list)
+
+	status, response := shared.NewSuccessResponse("SUC_201")
+	response.Data = list
+	return c.Status(status).JSON(response)
+}
+
+// UpdateList godoc
+// @Summary Update a waiting list
+// @Description Open or close a waiting list or change its name, capacity or required fields. A capacity of 0 removes the cap. Requires the waiting_lists:write permission.
+// @Tags crm-waiting-lists
+// @Accept json
+// @Produce json
+// @Param slug path string true "Waiting list slug"
+// @Param request body waiting_list.UpdateWaitingListRequest true "Settings to change"
+// @Success 200 {object} waiting_list.WaitingListResponse
+// @Failure 400 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/waiting-lists/{slug} [put]
+func (h *WaitingListHTTPHandler) UpdateList(c *fiber.Ctx) error {
+	var req waiting_list.UpdateWaitingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", ErrInvalidRequestBody)
+		return c.Status(status).JSON(response)
+	}
+
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+
+	before, err := h.waitingListService.GetList(c.Context(), c.Params("slug"))
+	if err != nil {
+		return h.listError(c, err, "Failed to update waiting list")
+	}
+
+	list, err := h.waitingListService.UpdateList(c.Context(), c.Params("slug"), &req)
+	if err != nil {
+		return h.listError(c, err, "Failed to update waiting list")
+	}
+
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionWaitingListUpdated, audit.TargetWaitingList, list.ID.String(), before, list)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = list
+	return c.Status(status).JSON(response)
+}
+
+// GetListEntries godoc
+// @Summary List the entries of a waiting list
+// @Description Get the emails on a waiting list with the fields given when joining, newest first. Requires the waiting_lists:read permission.
+// @Tags crm-waiting-lists
+// @Produce json
+// @Param slug path string true "Waiting list slug"
+// @Param limit query int false "Number of items to return (default: 20, max: 100)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} waiting_list.WaitingListEntriesResponse
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/waiting-lists/{slug}/entries [get]
+func (h *WaitingListHTTPHandler) GetListEntries(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := h.waitingListService.GetWaitingListUsers(c.Context(), c.Params("slug"), limit, offset)
+	if err != nil {
+		return h.listError(c, err, "Failed to get waiting list entries")
+	}
+
+	entries := make([]*waiting_list.WaitingListUserResponse, 0, len(users))
+	for _, user := range users {
+		entries = append(entries, user.ToResponse())
+	}
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	response.Data = waiting_list.WaitingListEntriesResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	return c.Status(status).JSON(response)
+}
+
+// DeleteListEntry godoc
+// @Summary Delete a waiting list entry
+// @Description Remove an email from a waiting list. Requires the waiting_lists:write permission.
+// @Tags crm-waiting-lists
+// @Produce json
+// @Param slug path string true "Waiting list slug"
+// @Param id path string true "Entry ID"
+// @Success 200 {object} shared.ResponseBody
+// @Failure 401 {object} shared.ResponseBody
+// @Failure 403 {object} shared.ResponseBody
+// @Failure 404 {object} shared.ResponseBody
+// @Failure 500 {object} shared.ResponseBody
+// @Security BearerAuth
+// @Router /v1/api/crm/waiting-lists/{slug}/entries/{id} [delete]
+func (h *WaitingListHTTPHandler) DeleteListEntry(c *fiber.Ctx) error {
+	deleted, err := h.waitingListService.DeleteUser(c.Context(), c.Params("slug"), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, waiting_list.ErrEntryNotFound) {
+			status, response := shared.NewErrorResponse("ERR_404", "Waiting list entry not found")
+			return c.Status(status).JSON(response)
+		}
+		return h.listError(c, err, "Failed to delete waiting list entry")
+	}
+
+	h.auditService.Record(c.Context(), middleware.CRMActor(c), audit.ActionWaitingListEntryDeleted, audit.TargetWaitingEntry, deleted.ID.String(), deleted.ToResponse(), nil)
+
+	status, response := shared.NewSuccessResponse("SUC_200")
+	return c.Status(status).JSON(response)
+}
+
+// listError answers the errors every list endpoint can hit, and a 500 with the given detail otherwise
+func (h *WaitingListHTTPHandler) listError(c *fiber.Ctx, err error, detail string) error {
+	switch {
+	case errors.Is(err, waiting_list.ErrListNotFound):
+		status, response := shared.NewErrorResponse("ERR_1053")
+		return c.Status(status).JSON(response)
+	case errors.Is(err, waiting_list.ErrInvalidSettings):
+		status, response := shared.NewErrorResponse("ERR_1029", err.Error())
+		return c.Status(status).JSON(response)
+	}
+	status, response := shared.NewErrorResponse("ERR_500", detail)
+	return c.Status(status).JSON(response)
 }
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/ports"
+	accountDeletionPorts "astroneko-backend/internal/core/ports/account_deletion"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type accountDeletionRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewAccountDeletionRepository creates a new account deletion repository instance
+func NewAccountDeletionRepository(db ports.DatabaseInterface) accountDeletionPorts.RepositoryInterface {
+	return &accountDeletionRepository{
+		db: db,
+	}
+}
+
+// Create saves a new deletion request
+func (r *accountDeletionRepository) Create(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	if err := r.db.WithContext(ctx).Create(request); err != nil {
+		return fmt.Errorf("failed to create deletion request for user %s: %w", request.UserID, err)
+	}
+
+	return nil
+}
+
+// Update saves all fields of a deletion request
+func (r *accountDeletionRepository) Update(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	if err := r.db.WithContext(ctx).Save(request); err != nil {
+		return fmt.Errorf("failed to update deletion request %s: %w", request.ID, err)
+	}
+
+	return nil
+}
+
+// GetActiveByUserID retrieves the user's deletion request that is awaiting confirmation or scheduled
+func (r *accountDeletionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*account_deletion.DeletionRequest, error) {
+	var request account_deletion.DeletionRequest
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []account_deletion.Status{
+			account_deletion.StatusAwaitingConfirmation,
+			account_deletion.StatusScheduled,
+		}).
+		Order("created_at DESC").
+		First(&request)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deletion request for user %s: %w", userID, err)
+	}
+
+	return &request, nil
+}
+
+// ListDue retrieves scheduled deletions whose grace period has ended
+func (r *accountDeletionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]account_deletion.DeletionRequest, error) {
+	var requests []account_deletion.DeletionRequest
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", account_deletion.StatusScheduled, now).
+		Order("scheduled_for ASC").
+		Limit(limit).
+		Find(&requests)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due deletions: %w", err)
+	}
+
+	return requests, nil
+}
+
+// ListAwaitingFirebase retrieves purged deletions whose Firebase account still exists
+func (r *accountDeletionRepository) ListAwaitingFirebase(ctx context.Context, limit int) ([]account_deletion.DeletionRequest, error) {
+	var requests []account_deletion.DeletionRequest
+
+	err := r.db.WithContext(ctx).
+		Where("status = ?", account_deletion.StatusPurged).
+		Order("purged_at ASC").
+		Limit(limit).
+		Find(&requests)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deletions awaiting firebase: %w", err)
+	}
+
+	return requests, nil
+}
+
+// AddAuditEvent appends an audit event
+func (r *accountDeletionRepository) AddAuditEvent(ctx context.Context, event *account_deletion.AuditEvent) error {
+	if err := r.db.WithContext(ctx).Create(event); err != nil {
+		return fmt.Errorf("failed to record %s for deletion %s: %w", event.Event, event.DeletionRequestID, err)
+	}
+
+	return nil
+}
+
+// purgeStatement deletes or anonymizes one kind of owned row. Statements run in
+// order, so children are removed before the rows they reference.
+type purgeStatement struct {
+	query string
+	count func(summary *account_deletion.PurgeSummary) *int64
+}
+
+var purgeStatements = []purgeStatement{
+	{
+		query: `WITH deleted AS (
+			DELETE FROM astroneko_message_histories
+			WHERE session_id IN (SELECT id FROM astroneko_sessions WHERE user_id = @user_id)
+			RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.Messages },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_sessions WHERE user_id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.Sessions },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_people WHERE user_id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.People },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_referral_logs WHERE redeemed_by_user_id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.ReferralLogs },
+	},
+	{
+		// Other users' redemptions of this user's codes are theirs to keep, minus the link back
+		query: `WITH updated AS (
+			UPDATE astroneko_referral_logs SET referral_code_id = NULL, updated_at = NOW()
+			WHERE referral_code_id IN (SELECT id FROM astroneko_user_referral_codes WHERE user_id = @user_id)
+			RETURNING 1)
+			SELECT COUNT(*) FROM updated`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.AnonymizedRedeems },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_user_referral_codes WHERE user_id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.UserReferralCodes },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_guest_api_usage WHERE composite_key = @guest_key RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.GuestUsageRecords },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_data_exports WHERE user_id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.DataExports },
+	},
+	{
+		query: `WITH deleted AS (DELETE FROM astroneko_auth_users WHERE id = @user_id RETURNING 1)
+			SELECT COUNT(*) FROM deleted`,
+		count: func(s *account_deletion.PurgeSummary) *int64 { return &s.Users },
+	},
+}
+
+// PurgeUserData removes every row the user owns in one transaction and marks the request purged
+func (r *accountDeletionRepository) PurgeUserData(ctx context.Context, request *account_deletion.DeletionRequest) (*account_deletion.PurgeSummary, error) {
+	guestKey := ""
+	if request.FirebaseUID != nil {
+		guestKey = "user_" + *request.FirebaseUID
+	}
+	args := map[string]any{"user_id": request.UserID, "guest_key": guestKey}
+
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return nil, fmt.Errorf("failed to begin purge of user %s: %w", request.UserID, err)
+	}
+	defer func() {
+		// Roll back the half-done purge before the panic carries on up
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	summary := &account_deletion.PurgeSummary{}
+	for _, statement := range purgeStatements {
+		if err := tx.Raw(statement.query, args).Scan(statement.count(summary)); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("failed to purge data of user %s: %w", request.UserID, err)
+		}
+	}
+
+	// Work on a copy so a rolled back purge leaves the caller's request untouched
+	now := time.Now()
+	purged := *request
+	purged.Status = account_deletion.StatusPurged
+	purged.PurgedAt = &now
+	if err := tx.Save(&purged); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed to update deletion request %s: %w", request.ID, err)
+	}
+
+	detail, _ := json.Marshal(summary)
+	if err := tx.Create(&account_deletion.AuditEvent{
+		DeletionRequestID: request.ID,
+		UserID:            request.UserID,
+		Event:             account_deletion.EventDataPurged,
+		Detail:            string(detail),
+	}); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed to record purge of user %s: %w", request.UserID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge of user %s: %w", request.UserID, err)
+	}
+
+	*request = purged
+	return summary, nil
+}
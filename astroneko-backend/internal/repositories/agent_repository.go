@@ -78,7 +78,7 @@ func (r *agentRepository) ClearState(ctx context.Context, request agent.ClearSta
 func (r *agentRepository) Reply(ctx context.Context, request agent.ReplyRequest) (*agent.ReplyResponse, error) {
 	uri := fmt.Sprintf("%s/api/cat-fortune/reply", r.agentBaseURL)
 
-	payload, err := json.Marshal(request)
+	payload, err := json.Marshal(request.ToAPIRequest())
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/audit"
+	"astroneko-backend/internal/core/ports"
+	auditPorts "astroneko-backend/internal/core/ports/audit"
+
+	"gorm.io/gorm"
+)
+
+// auditChainLockKey serializes appends so that two entries never claim the same predecessor
+const auditChainLockKey = 4_171_040
+
+type auditRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewAuditRepository creates a new audit log repository instance
+func NewAuditRepository(db ports.DatabaseInterface) auditPorts.RepositoryInterface {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// Create appends an audit entry to the end of the hash chain
+func (r *auditRepository) Create(ctx context.Context, entry *audit.Entry) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	var prev *audit.Entry
+	var last audit.Entry
+	err := tx.Order("sequence DESC").First(&last)
+	switch {
+	case err == nil:
+		prev = &last
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to read audit log head: %w", err)
+	}
+
+	entry.Chain(prev, time.Now())
+	if err := tx.Create(entry); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to record %s on %s %s: %w", entry.Action, entry.TargetType, entry.TargetID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *auditRepository) filtered(ctx context.Context, filter audit.ListFilter) ports.DatabaseInterface {
+	query := r.db.WithContext(ctx).Model(&audit.Entry{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+// List returns a page of matching entries, newest first
+func (r *auditRepository) List(ctx context.Context, filter audit.ListFilter) ([]*audit.Entry, int64, error) {
+	var total int64
+	if err := r.filtered(ctx, filter).Count(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	var entries []*audit.Entry
+	if err := r.filtered(ctx, filter).Order("sequence DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&entries); err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+func (r *auditRepository) ListChain(ctx context.Context, afterSequence int64, limit int) ([]*audit.Entry, error) {
+	var entries []*audit.Entry
+	err := r.db.WithContext(ctx).
+		Where("sequence > ?", afterSequence).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain after %d: %w", afterSequence, err)
+	}
+
+	return entries, nil
+}
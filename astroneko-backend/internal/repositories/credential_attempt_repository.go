@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	"astroneko-backend/internal/core/ports"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type credentialAttemptRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewCredentialAttemptRepository creates a new credential attempt repository instance
+func NewCredentialAttemptRepository(db ports.DatabaseInterface) credentialAttemptPorts.RepositoryInterface {
+	return &credentialAttemptRepository{
+		db: db,
+	}
+}
+
+func (r *credentialAttemptRepository) Get(ctx context.Context, scope, keyType, keyHash string) (*credential_attempt.Attempt, error) {
+	var attempt credential_attempt.Attempt
+	err := r.db.WithContext(ctx).
+		Where("scope = ? AND key_type = ? AND key_hash = ?", scope, keyType, keyHash).
+		First(&attempt)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s attempts: %w", scope, keyType, err)
+	}
+
+	return &attempt, nil
+}
+
+// RecordFailure upserts the counter in one statement so that parallel failures
+// for the same key are all counted
+func (r *credentialAttemptRepository) RecordFailure(ctx context.Context, scope, keyType, keyHash string, now, windowStart time.Time) (*credential_attempt.Attempt, error) {
+	var attempts []credential_attempt.Attempt
+	err := r.db.WithContext(ctx).
+		Raw(`INSERT INTO astroneko_credential_attempts (scope, key_type, key_hash, failures, last_failure_at, created_at, updated_at)
+			VALUES (?, ?, ?, 1, ?, ?, ?)
+			ON CONFLICT (scope, key_type, key_hash) DO UPDATE SET
+				failures = CASE WHEN astroneko_credential_attempts.last_failure_at < ? THEN 1
+					ELSE astroneko_credential_attempts.failures + 1 END,
+				locked_until = CASE WHEN astroneko_credential_attempts.last_failure_at < ? THEN NULL
+					ELSE astroneko_credential_attempts.locked_until END,
+				last_failure_at = EXCLUDED.last_failure_at,
+				updated_at = EXCLUDED.updated_at
+			RETURNING *`,
+			scope, keyType, keyHash, now, now, now, windowStart, windowStart).
+		Scan(&attempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s %s failure: %w", scope, keyType, err)
+	}
+	if len(attempts) != 1 {
+		return nil, fmt.Errorf("failed to record %s %s failure: no counter returned", scope, keyType)
+	}
+
+	return &attempts[0], nil
+}
+
+func (r *credentialAttemptRepository) LockUntil(ctx context.Context, id uuid.UUID, until time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&credential_attempt.Attempt{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"locked_until": until, "updated_at": time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to lock credential attempt counter %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *credentialAttemptRepository) Reset(ctx context.Context, scope, keyType, keyHash string) (bool, error) {
+	var deleted []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("DELETE FROM astroneko_credential_attempts WHERE scope = ? AND key_type = ? AND key_hash = ? RETURNING id", scope, keyType, keyHash).
+		Scan(&deleted)
+	if err != nil {
+		return false, fmt.Errorf("failed to reset %s %s attempts: %w", scope, keyType, err)
+	}
+	return len(deleted) > 0, nil
+}
+
+func (r *credentialAttemptRepository) DeleteStale(ctx context.Context, before time.Time, now time.Time) error {
+	err := r.db.WithContext(ctx).
+		Exec("DELETE FROM astroneko_credential_attempts WHERE last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, now)
+	if err != nil {
+		return fmt.Errorf("failed to delete stale credential attempts: %w", err)
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/core/ports"
 	crmUserPorts "astroneko-backend/internal/core/ports/crm_user"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type crmUserRepository struct {
@@ -38,6 +41,9 @@ func (r *crmUserRepository) GetByID(ctx context.Context, id string) (*crm_user.C
 	if err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user); err != nil {
 		return nil, err
 	}
+	if err := r.loadAccess(ctx, &user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -46,16 +52,63 @@ func (r *crmUserRepository) GetByUsername(ctx context.Context, username string)
 	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user); err != nil {
 		return nil, err
 	}
+	if err := r.loadAccess(ctx, &user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
-func (r *crmUserRepository) Update(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMUser, error) {
-	if err := r.db.WithContext(ctx).Save(user); err != nil {
+func (r *crmUserRepository) Update(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error) {
+	if len(columns) == 0 {
+		return user, nil
+	}
+
+	selected := append(append([]string{}, columns...), "updated_at")
+	if err := r.db.WithContext(ctx).Model(user).Select(selected).Updates(user); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
+// UpdateAndRevokeSessions writes the columns and bumps session_version in SQL. The user is
+// refreshed from the row, so it carries the new version and any change made since it was read.
+func (r *crmUserRepository) UpdateAndRevokeSessions(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return nil, fmt.Errorf("failed to begin CRM user transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	if len(columns) > 0 {
+		selected := append(append([]string{}, columns...), "updated_at")
+		if err := tx.Model(user).Select(selected).Updates(user); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	var updated []crm_user.CRMUser
+	err := tx.Raw("UPDATE astroneko_crm_users SET session_version = session_version + 1, updated_at = ? WHERE id = ? RETURNING *",
+		time.Now(), user.ID).Scan(&updated)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if len(updated) == 0 {
+		_ = tx.Rollback()
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit CRM user update: %w", err)
+	}
+
+	roles, permissions := user.Roles, user.Permissions
+	*user = updated[0]
+	user.Roles, user.Permissions = roles, permissions
+	return user, nil
+}
+
 func (r *crmUserRepository) Delete(ctx context.Context, id string) error {
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -64,3 +117,359 @@ func (r *crmUserRepository) Delete(ctx context.Context, id string) error {
 
 	return r.db.WithContext(ctx).Where("id = ?", userID).Delete(&crm_user.CRMUser{})
 }
+
+// userPermission is one permission granted to a CRM user through any of their roles
+type userPermission struct {
+	CRMUserID  uuid.UUID
+	Permission string
+}
+
+// loadAccess fills in the roles of each user and the permissions those roles grant
+func (r *crmUserRepository) loadAccess(ctx context.Context, users ...*crm_user.CRMUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(users))
+	byID := make(map[uuid.UUID]*crm_user.CRMUser, len(users))
+	for _, user := range users {
+		user.Roles = []string{}
+		user.Permissions = []string{}
+		ids = append(ids, user.ID)
+		byID[user.ID] = user
+	}
+
+	var assignments []crm_user.UserRole
+	err := r.db.WithContext(ctx).
+		Raw("SELECT crm_user_id, role FROM astroneko_crm_user_roles WHERE crm_user_id IN ? ORDER BY role", ids).
+		Scan(&assignments)
+	if err != nil {
+		return fmt.Errorf("failed to get CRM user roles: %w", err)
+	}
+
+	var grants []userPermission
+	err = r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ur.crm_user_id, rp.permission
+			FROM astroneko_crm_role_permissions rp
+			JOIN astroneko_crm_user_roles ur ON ur.role = rp.role
+			WHERE ur.crm_user_id IN ?
+			ORDER BY rp.permission`, ids).
+		Scan(&grants)
+	if err != nil {
+		return fmt.Errorf("failed to get CRM user permissions: %w", err)
+	}
+
+	for _, assignment := range assignments {
+		if user, ok := byID[assignment.CRMUserID]; ok {
+			user.Roles = append(user.Roles, assignment.Role)
+		}
+	}
+	for _, grant := range grants {
+		if user, ok := byID[grant.CRMUserID]; ok {
+			user.Permissions = append(user.Permissions, grant.Permission)
+		}
+	}
+
+	return nil
+}
+
+// List returns a page of CRM users ordered by username, with their roles
+func (r *crmUserRepository) List(ctx context.Context, limit, offset int) ([]*crm_user.CRMUser, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&crm_user.CRMUser{}).Count(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count CRM users: %w", err)
+	}
+
+	var users []*crm_user.CRMUser
+	if err := r.db.WithContext(ctx).Order("username ASC").Limit(limit).Offset(offset).Find(&users); err != nil {
+		return nil, 0, fmt.Errorf("failed to list CRM users: %w", err)
+	}
+
+	if err := r.loadAccess(ctx, users...); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// ListRoles returns every role with its permissions, ordered by name
+func (r *crmUserRepository) ListRoles(ctx context.Context) ([]crm_user.Role, error) {
+	var roles []crm_user.Role
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&roles); err != nil {
+		return nil, fmt.Errorf("failed to list CRM roles: %w", err)
+	}
+
+	var grants []crm_user.RolePermission
+	if err := r.db.WithContext(ctx).Order("permission ASC").Find(&grants); err != nil {
+		return nil, fmt.Errorf("failed to list CRM role permissions: %w", err)
+	}
+
+	permissionsByRole := make(map[string][]string)
+	for _, grant := range grants {
+		permissionsByRole[grant.Role] = append(permissionsByRole[grant.Role], grant.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = permissionsByRole[roles[i].Name]
+		if roles[i].Permissions == nil {
+			roles[i].Permissions = []string{}
+		}
+	}
+
+	return roles, nil
+}
+
+// SetRoles replaces the user's role assignments in one transaction
+func (r *crmUserRepository) SetRoles(ctx context.Context, userID uuid.UUID, roles []string, grantedBy *uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin role transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	if err := tx.Where("crm_user_id = ?", userID).Delete(&crm_user.UserRole{}); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to clear roles for CRM user %s: %w", userID, err)
+	}
+
+	for _, role := range roles {
+		assignment := &crm_user.UserRole{
+			CRMUserID: userID,
+			Role:      role,
+			GrantedBy: grantedBy,
+		}
+		if err := tx.Create(assignment); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to assign role %s to CRM user %s: %w", role, userID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit roles for CRM user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// CountByRole counts the enabled CRM users holding the role
+func (r *crmUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&crm_user.UserRole{}).
+		Where("role = ? AND crm_user_id IN (SELECT id FROM astroneko_crm_users WHERE disabled_at IS NULL)", role).
+		Count(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count CRM users with role %s: %w", role, err)
+	}
+	return count, nil
+}
+
+// CreatePasswordReset stores a new reset token and voids any unused older ones for the user
+func (r *crmUserRepository) CreatePasswordReset(ctx context.Context, reset *crm_user.PasswordReset) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin password reset transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	if err := tx.Exec("UPDATE astroneko_crm_password_resets SET used_at = NOW() WHERE crm_user_id = ? AND used_at IS NULL", reset.CRMUserID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to void password resets for CRM user %s: %w", reset.CRMUserID, err)
+	}
+
+	if err := tx.Create(reset); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to create password reset for CRM user %s: %w", reset.CRMUserID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit password reset for CRM user %s: %w", reset.CRMUserID, err)
+	}
+
+	return nil
+}
+
+// GetPasswordResetByTokenHash returns the reset with the token hash, or nil if there is none
+func (r *crmUserRepository) GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*crm_user.PasswordReset, error) {
+	var resets []crm_user.PasswordReset
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Limit(1).Find(&resets); err != nil {
+		return nil, fmt.Errorf("failed to get password reset: %w", err)
+	}
+	if len(resets) == 0 {
+		return nil, nil
+	}
+	return &resets[0], nil
+}
+
+// ConsumePasswordReset marks the reset as used. It reports false when the reset was
+// already used or has expired, so a token can only ever be redeemed once.
+func (r *crmUserRepository) ConsumePasswordReset(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	var consumed []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE astroneko_crm_password_resets SET used_at = ? WHERE id = ? AND used_at IS NULL AND expires_at > ? RETURNING id", now, id, now).
+		Scan(&consumed)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume password reset %s: %w", id, err)
+	}
+	return len(consumed) == 1, nil
+}
+
+func (r *crmUserRepository) CreateRefreshToken(ctx context.Context, token *crm_user.RefreshToken) error {
+	if err := r.db.WithContext(ctx).Create(token); err != nil {
+		return fmt.Errorf("failed to create refresh token for CRM user %s: %w", token.CRMUserID, err)
+	}
+	return nil
+}
+
+// GetRefreshTokenByHash returns the refresh token with the hash, or nil if there is none
+func (r *crmUserRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*crm_user.RefreshToken, error) {
+	var tokens []crm_user.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Limit(1).Find(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return &tokens[0], nil
+}
+
+// RotateRefreshToken revokes the current token and stores its replacement in one
+// transaction. It reports false when the current token was already revoked, so two
+// concurrent refreshes with the same token cannot both succeed.
+func (r *crmUserRepository) RotateRefreshToken(ctx context.Context, currentID uuid.UUID, next *crm_user.RefreshToken, now time.Time) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return false, fmt.Errorf("failed to begin refresh token transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	var revoked []uuid.UUID
+	err := tx.Raw("UPDATE astroneko_crm_refresh_tokens SET revoked_at = ?, replaced_by = ? WHERE id = ? AND revoked_at IS NULL RETURNING id", now, next.ID, currentID).
+		Scan(&revoked)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, fmt.Errorf("failed to revoke refresh token %s: %w", currentID, err)
+	}
+	if len(revoked) == 0 {
+		_ = tx.Rollback()
+		return false, nil
+	}
+
+	if err := tx.Create(next); err != nil {
+		_ = tx.Rollback()
+		return false, fmt.Errorf("failed to create refresh token for CRM user %s: %w", next.CRMUserID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeRefreshTokenFamily revokes every token rotated from the same login
+func (r *crmUserRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error {
+	err := r.db.WithContext(ctx).
+		Exec("UPDATE astroneko_crm_refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL", now, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family %s: %w", familyID, err)
+	}
+	return nil
+}
+
+// RevokeAccessToken adds the token to the denylist. Entries whose tokens have expired
+// are pruned on the way, which keeps the denylist no larger than the access token TTL allows.
+func (r *crmUserRepository) RevokeAccessToken(ctx context.Context, revoked *crm_user.RevokedAccessToken) error {
+	if err := r.db.WithContext(ctx).Exec("DELETE FROM astroneko_crm_revoked_access_tokens WHERE expires_at < NOW()"); err != nil {
+		return fmt.Errorf("failed to prune revoked access tokens: %w", err)
+	}
+
+	err := r.db.WithContext(ctx).
+		Exec(`INSERT INTO astroneko_crm_revoked_access_tokens (jti, crm_user_id, expires_at, revoked_at)
+			VALUES (?, ?, ?, ?) ON CONFLICT (jti) DO NOTHING`,
+			revoked.JTI, revoked.CRMUserID, revoked.ExpiresAt, revoked.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token %s: %w", revoked.JTI, err)
+	}
+	return nil
+}
+
+func (r *crmUserRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&crm_user.RevokedAccessToken{}).
+		Where("jti = ?", jti).
+		Count(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check access token revocation: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RecordTOTPStep stores the time step of an accepted TOTP code. It reports false when
+// a code from the same or a later step was already accepted, so each code works once
+// even when two requests race.
+func (r *crmUserRepository) RecordTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	var updated []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE astroneko_crm_users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ? RETURNING id", step, userID, step).
+		Scan(&updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to record TOTP step for CRM user %s: %w", userID, err)
+	}
+	return len(updated) == 1, nil
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores the new set
+func (r *crmUserRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []crm_user.RecoveryCode) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin recovery code transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	if err := tx.Where("crm_user_id = ?", userID).Delete(&crm_user.RecoveryCode{}); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to delete recovery codes for CRM user %s: %w", userID, err)
+	}
+
+	if len(codes) > 0 {
+		if err := tx.Create(&codes); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to create recovery codes for CRM user %s: %w", userID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes for CRM user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode marks the user's unused code with the hash as used. It reports
+// false when there is no such code.
+func (r *crmUserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	var consumed []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw(`UPDATE astroneko_crm_recovery_codes SET used_at = ?
+			WHERE id = (
+				SELECT id FROM astroneko_crm_recovery_codes
+				WHERE crm_user_id = ? AND code_hash = ? AND used_at IS NULL
+				LIMIT 1
+			) AND used_at IS NULL
+			RETURNING id`, now, userID, codeHash).
+		Scan(&consumed)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code for CRM user %s: %w", userID, err)
+	}
+	return len(consumed) == 1, nil
+}
+
+// rollbackOnPanic rolls tx back when the caller panics, releasing its locks before the
+// panic carries on up. It must be deferred directly.
+func rollbackOnPanic(tx ports.DatabaseInterface) {
+	if p := recover(); p != nil {
+		_ = tx.Rollback()
+		panic(p)
+	}
+}
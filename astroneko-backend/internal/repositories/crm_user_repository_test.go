@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
@@ -43,6 +45,28 @@ func hashPassword(password string) string {
 	return string(hashedPassword)
 }
 
+// expectCRMUserAccess sets up the role and permission lookups that follow a successful user query
+func expectCRMUserAccess(mockDB *mock_ports.MockDatabaseInterface, ctx context.Context, userID uuid.UUID, roles []string, permissions []string) {
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB).Times(2)
+	mockDB.EXPECT().Raw(gomock.Any(), gomock.Any()).Return(mockDB).Times(2)
+	gomock.InOrder(
+		mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest interface{}) error {
+			assignments := dest.(*[]crm_user.UserRole)
+			for _, role := range roles {
+				*assignments = append(*assignments, crm_user.UserRole{CRMUserID: userID, Role: role})
+			}
+			return nil
+		}),
+		mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest interface{}) error {
+			grants := dest.(*[]userPermission)
+			for _, permission := range permissions {
+				*grants = append(*grants, userPermission{CRMUserID: userID, Permission: permission})
+			}
+			return nil
+		}),
+	)
+}
+
 func TestCRMUserRepository_Create_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -118,6 +142,7 @@ func TestCRMUserRepository_GetByUsername_Success(t *testing.T) {
 		}
 		return nil
 	})
+	expectCRMUserAccess(mockDB, ctx, expectedUser.ID, []string{crm_user.RoleViewer}, []string{crm_user.PermissionAppUsersRead})
 
 	// Act
 	result, err := repository.GetByUsername(ctx, username)
@@ -127,6 +152,8 @@ func TestCRMUserRepository_GetByUsername_Success(t *testing.T) {
 	require.NotNil(t, result)
 	assert.Equal(t, expectedUser.ID, result.ID)
 	assert.Equal(t, expectedUser.Username, result.Username)
+	assert.Equal(t, []string{crm_user.RoleViewer}, result.Roles)
+	assert.True(t, result.HasPermission(crm_user.PermissionAppUsersRead))
 }
 
 func TestCRMUserRepository_GetByUsername_NotFound(t *testing.T) {
@@ -173,6 +200,7 @@ func TestCRMUserRepository_GetByID_Success(t *testing.T) {
 		}
 		return nil
 	})
+	expectCRMUserAccess(mockDB, ctx, expectedUser.ID, []string{crm_user.RoleViewer}, []string{crm_user.PermissionAppUsersRead})
 
 	// Act
 	result, err := repository.GetByID(ctx, testID)
@@ -239,10 +267,12 @@ func TestCRMUserRepository_Update_Success(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-	mockDB.EXPECT().Save(testUser).Return(nil)
+	mockDB.EXPECT().Model(testUser).Return(mockDB)
+	mockDB.EXPECT().Select([]string{crm_user.ColumnDisabledAt, "updated_at"}).Return(mockDB)
+	mockDB.EXPECT().Updates(testUser).Return(nil)
 
 	// Act
-	result, err := repository.Update(ctx, testUser)
+	result, err := repository.Update(ctx, testUser, []string{crm_user.ColumnDisabledAt})
 
 	// Assert
 	require.NoError(t, err)
@@ -264,10 +294,12 @@ func TestCRMUserRepository_Update_DatabaseError(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-	mockDB.EXPECT().Save(testUser).Return(dbError)
+	mockDB.EXPECT().Model(testUser).Return(mockDB)
+	mockDB.EXPECT().Select(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Updates(testUser).Return(dbError)
 
 	// Act
-	result, err := repository.Update(ctx, testUser)
+	result, err := repository.Update(ctx, testUser, crm_user.TwoFactorColumns)
 
 	// Assert
 	assert.Error(t, err)
@@ -275,6 +307,73 @@ func TestCRMUserRepository_Update_DatabaseError(t *testing.T) {
 	assert.Equal(t, dbError, err)
 }
 
+func TestCRMUserRepository_UpdateAndRevokeSessions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewCRMUserRepository(mockDB)
+	ctx := context.Background()
+	testUser := buildCRMUserWithID("123e4567-e89b-12d3-a456-426614174000")
+	testUser.Roles = []string{crm_user.RoleAdmin}
+	disabledAt := time.Now()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Model(testUser).Return(mockTx)
+	mockTx.EXPECT().Select(append(append([]string{}, crm_user.PasswordColumns...), "updated_at")).Return(mockTx)
+	mockTx.EXPECT().Updates(testUser).Return(nil)
+	mockTx.EXPECT().Raw(gomock.Any(), gomock.Any(), testUser.ID).DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+		assert.Contains(t, sql, "session_version = session_version + 1")
+		return mockTx
+	})
+	mockTx.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+		// The row was disabled by someone else since testUser was read
+		row := *testUser
+		row.SessionVersion = 3
+		row.DisabledAt = &disabledAt
+		row.Roles = nil
+		*dest.(*[]crm_user.CRMUser) = []crm_user.CRMUser{row}
+		return nil
+	})
+	mockTx.EXPECT().Commit().Return(nil)
+
+	// Act
+	result, err := repository.UpdateAndRevokeSessions(ctx, testUser, crm_user.PasswordColumns)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.SessionVersion)
+	assert.True(t, result.IsDisabled())
+	assert.Equal(t, []string{crm_user.RoleAdmin}, result.Roles)
+}
+
+func TestCRMUserRepository_UpdateAndRevokeSessions_BeginFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewCRMUserRepository(mockDB)
+	ctx := context.Background()
+	testUser := buildCRMUserWithID("123e4567-e89b-12d3-a456-426614174000")
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(errors.New("connection refused"))
+
+	// Act
+	result, err := repository.UpdateAndRevokeSessions(ctx, testUser, nil)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
 func TestCRMUserRepository_Delete_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -394,6 +493,7 @@ func TestCRMUserRepository_GetByUsername_InvalidUsernames(t *testing.T) {
 					}
 					return nil
 				})
+				expectCRMUserAccess(mockDB, ctx, expectedUser.ID, []string{crm_user.RoleViewer}, []string{crm_user.PermissionAppUsersRead})
 			}
 
 			// Act
@@ -527,6 +627,7 @@ func TestCRMUserRepository_UsernameFormats(t *testing.T) {
 					}
 					return nil
 				})
+				expectCRMUserAccess(mockDB, ctx, expectedUser.ID, []string{crm_user.RoleViewer}, []string{crm_user.PermissionAppUsersRead})
 			} else {
 				// Setup expectations for not found
 				mockDB.EXPECT().WithContext(ctx).Return(mockDB)
@@ -550,3 +651,50 @@ func TestCRMUserRepository_UsernameFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestCRMUserRepository_SetRoles_RollsBackOnPanic(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewCRMUserRepository(mockDB)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Where("crm_user_id = ?", userID).Return(mockTx)
+	mockTx.EXPECT().Delete(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Create(gomock.Any()).DoAndReturn(func(any) error { panic("driver bug") })
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "driver bug", func() {
+		_ = repository.SetRoles(ctx, userID, []string{crm_user.RoleAdmin}, nil)
+	})
+}
+
+func TestCRMUserRepository_RotateRefreshToken_BeginFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewCRMUserRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(errors.New("too many connections"))
+
+	// Act
+	rotated, err := repository.RotateRefreshToken(ctx, uuid.New(), &crm_user.RefreshToken{ID: uuid.New()}, time.Now())
+
+	// Assert
+	assert.False(t, rotated)
+	assert.ErrorContains(t, err, "too many connections")
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/data_export"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/ports"
+	dataExportPorts "astroneko-backend/internal/core/ports/data_export"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type dataExportRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewDataExportRepository creates a new data export repository instance
+func NewDataExportRepository(db ports.DatabaseInterface) dataExportPorts.RepositoryInterface {
+	return &dataExportRepository{
+		db: db,
+	}
+}
+
+// Create saves a new export job
+func (r *dataExportRepository) Create(ctx context.Context, job *data_export.ExportJob) error {
+	if err := r.db.WithContext(ctx).Create(job); err != nil {
+		return fmt.Errorf("failed to create export job for user %s: %w", job.UserID, err)
+	}
+
+	return nil
+}
+
+// Update saves the job's fields, leaving the stored archive as it is
+func (r *dataExportRepository) Update(ctx context.Context, job *data_export.ExportJob) error {
+	if err := r.db.WithContext(ctx).Omit("archive").Save(job); err != nil {
+		return fmt.Errorf("failed to update export job %s: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+// SaveArchive saves the job including its archive
+func (r *dataExportRepository) SaveArchive(ctx context.Context, job *data_export.ExportJob) error {
+	if err := r.db.WithContext(ctx).Save(job); err != nil {
+		return fmt.Errorf("failed to save archive of export job %s: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an export job owned by the user, without its archive
+func (r *dataExportRepository) GetByID(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (*data_export.ExportJob, error) {
+	var job data_export.ExportJob
+
+	err := r.db.WithContext(ctx).
+		Omit("archive").
+		Where("id = ? AND user_id = ?", jobID, userID).
+		First(&job)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get export job %s: %w", jobID, err)
+	}
+
+	return &job, nil
+}
+
+// GetWithArchive retrieves an export job together with its archive
+func (r *dataExportRepository) GetWithArchive(ctx context.Context, jobID uuid.UUID) (*data_export.ExportJob, error) {
+	var job data_export.ExportJob
+
+	err := r.db.WithContext(ctx).
+		Where("id = ?", jobID).
+		First(&job)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get archive of export job %s: %w", jobID, err)
+	}
+
+	return &job, nil
+}
+
+// ListByUserID retrieves the user's most recent export jobs, without their archives
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]data_export.ExportJob, error) {
+	var jobs []data_export.ExportJob
+
+	err := r.db.WithContext(ctx).
+		Omit("archive").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs for user %s: %w", userID, err)
+	}
+
+	return jobs, nil
+}
+
+// CountCreatedSince counts the export jobs the user requested since the given time
+func (r *dataExportRepository) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&data_export.ExportJob{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count export jobs for user %s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+// WithUserLock runs fn in a transaction holding an advisory lock on the user's export jobs
+func (r *dataExportRepository) WithUserLock(ctx context.Context, userID uuid.UUID, fn func(tx dataExportPorts.RepositoryInterface) error) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin export transaction for user %s: %w", userID, err)
+	}
+	defer func() {
+		// Release the lock before the panic carries on up
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "data_export:"+userID.String()); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to lock export jobs of user %s: %w", userID, err)
+	}
+
+	if err := fn(&dataExportRepository{db: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit export transaction for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ClaimPending moves queued jobs, and jobs abandoned mid-run, to processing.
+// SKIP LOCKED lets several instances run the worker without building the same job twice.
+func (r *dataExportRepository) ClaimPending(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]data_export.ExportJob, error) {
+	var jobs []data_export.ExportJob
+
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE astroneko_data_exports
+		SET status = @processing, started_at = @now, updated_at = @now
+		WHERE id IN (
+			SELECT id FROM astroneko_data_exports
+			WHERE status = @pending OR (status = @processing AND started_at < @stale_before)
+			ORDER BY created_at ASC
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, user_id, status, archive_size, started_at, completed_at, expires_at,
+			attempts, last_error, created_at, updated_at`,
+		map[string]any{
+			"processing":   data_export.StatusProcessing,
+			"pending":      data_export.StatusPending,
+			"now":          now,
+			"stale_before": staleBefore,
+			"limit":        limit,
+		}).Scan(&jobs)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending export jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ExpireArchives drops the archives of ready jobs past their expiry
+func (r *dataExportRepository) ExpireArchives(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH expired AS (
+			UPDATE astroneko_data_exports
+			SET status = @expired, archive = NULL, updated_at = @now
+			WHERE status = @ready AND expires_at <= @now
+			RETURNING 1)
+		SELECT COUNT(*) FROM expired`,
+		map[string]any{
+			"expired": data_export.StatusExpired,
+			"ready":   data_export.StatusReady,
+			"now":     now,
+		}).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire export archives: %w", err)
+	}
+
+	return count, nil
+}
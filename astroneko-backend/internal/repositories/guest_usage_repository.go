@@ -185,6 +185,30 @@ func (r *GuestUsageRepository) GetByIPAddress(ctx context.Context, ipAddress str
 	return usages, nil
 }
 
+// ListByCompositeKey retrieves all usage records for a composite key, newest first
+func (r *GuestUsageRepository) ListByCompositeKey(ctx context.Context, compositeKey string) ([]*guest_usage.GuestAPIUsage, error) {
+	var models []guestUsageModel
+
+	err := r.db.WithContext(ctx).
+		Where("composite_key = ?", compositeKey).
+		Order("window_reset_at DESC").
+		Find(&models)
+
+	if err != nil {
+		r.logger.Error("Failed to list usage by composite key",
+			logger.Field{Key: "composite_key", Value: compositeKey},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	usages := make([]*guest_usage.GuestAPIUsage, len(models))
+	for i, model := range models {
+		usages[i] = model.toDomain()
+	}
+
+	return usages, nil
+}
+
 // BlockGuest blocks a guest from making requests
 func (r *GuestUsageRepository) BlockGuest(ctx context.Context, compositeKey string, reason string) error {
 	err := r.db.WithContext(ctx).
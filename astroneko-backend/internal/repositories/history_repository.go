@@ -163,3 +163,64 @@ func (r *historyRepository) DeleteSession(ctx context.Context, sessionID uuid.UU
 
 	return nil
 }
+
+// CreateSessionWithMessages saves a session and its messages in one transaction.
+// The session ID is assigned to every message.
+func (r *historyRepository) CreateSessionWithMessages(ctx context.Context, session *history.Session, messages []history.Message) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := tx.Create(session); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	for i := range messages {
+		messages[i].SessionID = session.ID
+		if err := tx.Create(&messages[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to create message for session %s: %w", session.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session %s: %w", session.ID, err)
+	}
+
+	return nil
+}
+
+// GetMessagesByUserID retrieves all messages across the user's non-deleted sessions
+// in chronological order
+func (r *historyRepository) GetMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]history.Message, error) {
+	var messages []history.Message
+
+	err := r.db.WithContext(ctx).
+		Where("session_id IN (SELECT id FROM astroneko_sessions WHERE user_id = ? AND deleted_at IS NULL)", userID).
+		Order("created_at ASC").
+		Find(&messages)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages for user %s: %w", userID, err)
+	}
+
+	return messages, nil
+}
+
+// GetMessageStatsByUserID returns the message count and latest message time across
+// the user's non-deleted sessions. Used as a cheap change marker for cached aggregates.
+func (r *historyRepository) GetMessageStatsByUserID(ctx context.Context, userID uuid.UUID) (*history.MessageStats, error) {
+	var stats history.MessageStats
+
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT COUNT(m.id) AS message_count, MAX(m.created_at) AS latest_message_at
+			FROM astroneko_message_histories m
+			JOIN astroneko_sessions s ON s.id = m.session_id
+			WHERE s.user_id = ? AND s.deleted_at IS NULL`, userID).
+		Scan(&stats)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message stats for user %s: %w", userID, err)
+	}
+
+	return &stats, nil
+}
@@ -593,3 +593,122 @@ func TestHistoryRepository_DeleteSession_DatabaseError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to delete session")
 }
+
+// GetMessagesByUserID Tests
+func TestHistoryRepository_GetMessagesByUserID_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewHistoryRepository(mockDB)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	expectedMessages := []history.Message{*buildTestMessage(uuid.New())}
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().
+		Where("session_id IN (SELECT id FROM astroneko_sessions WHERE user_id = ? AND deleted_at IS NULL)", userID).
+		Return(mockDB)
+	mockDB.EXPECT().Order("created_at ASC").Return(mockDB)
+	mockDB.EXPECT().
+		Find(gomock.Any()).
+		DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+			messages := dest.(*[]history.Message)
+			*messages = expectedMessages
+			return nil
+		})
+
+	// Act
+	messages, err := repo.GetMessagesByUserID(ctx, userID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMessages, messages)
+}
+
+// GetMessageStatsByUserID Tests
+func TestHistoryRepository_GetMessageStatsByUserID_DatabaseError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewHistoryRepository(mockDB)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), userID).Return(mockDB)
+	mockDB.EXPECT().Scan(gomock.Any()).Return(errors.New("database connection error"))
+
+	// Act
+	stats, err := repo.GetMessageStatsByUserID(ctx, userID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.Contains(t, err.Error(), "failed to get message stats")
+}
+
+// CreateSessionWithMessages Tests
+func TestHistoryRepository_CreateSessionWithMessages_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewHistoryRepository(mockDB)
+
+	ctx := context.Background()
+	session := buildTestSession(uuid.New())
+	messages := []history.Message{
+		{Message: "question", Role: history.MessageRoleUser},
+		{Message: "answer", Role: history.MessageRoleAssistant},
+	}
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Create(session).Return(nil)
+	mockTx.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	// Act
+	err := repo.CreateSessionWithMessages(ctx, session, messages)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, messages[0].SessionID)
+	assert.Equal(t, session.ID, messages[1].SessionID)
+}
+
+func TestHistoryRepository_CreateSessionWithMessages_RollsBackOnMessageError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewHistoryRepository(mockDB)
+
+	ctx := context.Background()
+	session := buildTestSession(uuid.New())
+	messages := []history.Message{{Message: "question", Role: history.MessageRoleUser}}
+	dbError := errors.New("insert failed")
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Create(session).Return(nil)
+	mockTx.EXPECT().Create(gomock.Any()).Return(dbError)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act
+	err := repo.CreateSessionWithMessages(ctx, session, messages)
+
+	// Assert
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, dbError))
+}
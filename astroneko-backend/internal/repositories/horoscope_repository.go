@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/horoscope"
+	"astroneko-backend/internal/core/ports"
+	horoscopePorts "astroneko-backend/internal/core/ports/horoscope"
+
+	"gorm.io/gorm"
+)
+
+type horoscopeRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewHoroscopeRepository creates a new daily horoscope repository instance
+func NewHoroscopeRepository(db ports.DatabaseInterface) horoscopePorts.RepositoryInterface {
+	return &horoscopeRepository{
+		db: db,
+	}
+}
+
+// GetBySignAndDate retrieves the horoscope of a sign for a calendar date
+func (r *horoscopeRepository) GetBySignAndDate(ctx context.Context, sign string, date time.Time) (*horoscope.DailyHoroscope, error) {
+	var dailyHoroscope horoscope.DailyHoroscope
+
+	err := r.db.WithContext(ctx).
+		Where("sign = ? AND horoscope_date = ?", sign, date.Format(horoscope.DateLayout)).
+		First(&dailyHoroscope)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get horoscope for %s on %s: %w", sign, date.Format(horoscope.DateLayout), err)
+	}
+
+	return &dailyHoroscope, nil
+}
+
+// ListByDate retrieves all horoscopes generated for a calendar date
+func (r *horoscopeRepository) ListByDate(ctx context.Context, date time.Time) ([]horoscope.DailyHoroscope, error) {
+	var horoscopes []horoscope.DailyHoroscope
+
+	err := r.db.WithContext(ctx).
+		Where("horoscope_date = ?", date.Format(horoscope.DateLayout)).
+		Find(&horoscopes)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horoscopes on %s: %w", date.Format(horoscope.DateLayout), err)
+	}
+
+	return horoscopes, nil
+}
+
+// Upsert inserts a horoscope or replaces the content for the same sign and date
+func (r *horoscopeRepository) Upsert(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error {
+	err := r.db.WithContext(ctx).Exec(
+		`INSERT INTO astroneko_daily_horoscopes (sign, horoscope_date, content_th, content_en)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (sign, horoscope_date)
+		DO UPDATE SET content_th = EXCLUDED.content_th, content_en = EXCLUDED.content_en, updated_at = NOW()`,
+		dailyHoroscope.Sign,
+		dailyHoroscope.HoroscopeDate.Format(horoscope.DateLayout),
+		dailyHoroscope.ContentTH,
+		dailyHoroscope.ContentEN,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save horoscope for %s: %w", dailyHoroscope.Sign, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/ports"
+	personPorts "astroneko-backend/internal/core/ports/person"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type personRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewPersonRepository creates a new saved people repository instance
+func NewPersonRepository(db ports.DatabaseInterface) personPorts.RepositoryInterface {
+	return &personRepository{
+		db: db,
+	}
+}
+
+// Create saves a new person
+func (r *personRepository) Create(ctx context.Context, p *person.Person) error {
+	if err := r.db.WithContext(ctx).Create(p); err != nil {
+		return fmt.Errorf("failed to create person for user %s: %w", p.UserID, err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a person owned by the user
+func (r *personRepository) GetByID(ctx context.Context, userID uuid.UUID, personID uuid.UUID) (*person.Person, error) {
+	var p person.Person
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", personID, userID).
+		First(&p)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrPersonNotFound
+		}
+		return nil, fmt.Errorf("failed to get person %s: %w", personID, err)
+	}
+
+	return &p, nil
+}
+
+// ListByUserID retrieves the people saved by a user, oldest first
+func (r *personRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]person.Person, error) {
+	var people []person.Person
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&people)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list people for user %s: %w", userID, err)
+	}
+
+	return people, nil
+}
+
+// CountByUserID counts the people saved by a user
+func (r *personRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&person.Person{}).
+		Where("user_id = ?", userID).
+		Count(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count people for user %s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+// Update saves all fields of a person
+func (r *personRepository) Update(ctx context.Context, p *person.Person) error {
+	if err := r.db.WithContext(ctx).Save(p); err != nil {
+		return fmt.Errorf("failed to update person %s: %w", p.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes a person owned by the user
+func (r *personRepository) Delete(ctx context.Context, userID uuid.UUID, personID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&person.Person{}, personID)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete person %s: %w", personID, err)
+	}
+
+	return nil
+}
@@ -2,17 +2,24 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_reward"
 	"astroneko-backend/internal/core/ports"
 	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
 )
 
 const (
 	WhereClauseReferralCodeEqual                = "LOWER(referral_code) = LOWER(?)"
-	WhereClauseReferralCodeEqualAndNotActivated = "LOWER(referral_code) = LOWER(?) AND is_activated = false"
+	WhereClauseReferralCodeEqualAndNotActivated = "LOWER(referral_code) = LOWER(?) AND is_activated = false AND (expires_at IS NULL OR expires_at > NOW())"
 )
 
 type referralCodeRepository struct {
@@ -25,10 +32,21 @@ func NewReferralCodeRepository(db ports.DatabaseInterface) referralCodePorts.Rep
 	}
 }
 
+// codeTakenOr reports a unique violation, raised by the code indexes or by the namespace
+// general and user codes share, as referral_code.ErrCodeTaken
+func codeTakenOr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return referral_code.ErrCodeTaken
+	}
+	return err
+}
+
 func (r *referralCodeRepository) Create(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error) {
 	referralCode.ID = uuid.New()
+	referralCode.ReferralCode = referral_code.NormalizeCode(referralCode.ReferralCode)
 	if err := r.db.WithContext(ctx).Create(referralCode); err != nil {
-		return nil, err
+		return nil, codeTakenOr(err)
 	}
 	return referralCode, nil
 }
@@ -48,7 +66,7 @@ func (r *referralCodeRepository) GetByID(ctx context.Context, id string) (*refer
 
 func (r *referralCodeRepository) GetByReferralCode(ctx context.Context, code string) (*referral_code.ReferralCode, error) {
 	var referralCode referral_code.ReferralCode
-	if err := r.db.WithContext(ctx).Where(WhereClauseReferralCodeEqual, code).First(&referralCode); err != nil {
+	if err := r.db.WithContext(ctx).Where(WhereClauseReferralCodeEqual, referral_code.NormalizeCode(code)).First(&referralCode); err != nil {
 		return nil, err
 	}
 	return &referralCode, nil
@@ -56,15 +74,16 @@ func (r *referralCodeRepository) GetByReferralCode(ctx context.Context, code str
 
 func (r *referralCodeRepository) IsValidReferralCode(ctx context.Context, code string) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&referral_code.ReferralCode{}).Where(WhereClauseReferralCodeEqual, code).Count(&count); err != nil {
+	if err := r.db.WithContext(ctx).Model(&referral_code.ReferralCode{}).Where(WhereClauseReferralCodeEqual, referral_code.NormalizeCode(code)).Count(&count); err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
 func (r *referralCodeRepository) Update(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error) {
+	referralCode.ReferralCode = referral_code.NormalizeCode(referralCode.ReferralCode)
 	if err := r.db.WithContext(ctx).Save(referralCode); err != nil {
-		return nil, err
+		return nil, codeTakenOr(err)
 	}
 	return referralCode, nil
 }
@@ -93,15 +112,115 @@ func (r *referralCodeRepository) List(ctx context.Context, limit, offset int) ([
 	return referralCodes, count, nil
 }
 
+// createBatchSize keeps a multi-row insert well under the Postgres parameter limit
+const createBatchSize = 500
+
+func (r *referralCodeRepository) CreateReferralCodes(ctx context.Context, referralCodes []*referral_code.ReferralCode) error {
+	for start := 0; start < len(referralCodes); start += createBatchSize {
+		batch := referralCodes[start:min(start+createBatchSize, len(referralCodes))]
+		for _, referralCode := range batch {
+			referralCode.ID = uuid.New()
+			referralCode.ReferralCode = referral_code.NormalizeCode(referralCode.ReferralCode)
+		}
+		if err := r.db.WithContext(ctx).Create(&batch); err != nil {
+			return fmt.Errorf("failed to create referral codes: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *referralCodeRepository) ExistingReferralCodes(ctx context.Context, codes []string) ([]string, error) {
+	byLowered := make(map[string]string, len(codes))
+	lowered := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalized := referral_code.NormalizeCode(code)
+		byLowered[strings.ToLower(normalized)] = normalized
+		lowered = append(lowered, strings.ToLower(normalized))
+	}
+
+	var existing []string
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT LOWER(referral_code) FROM astroneko_general_referral_codes WHERE LOWER(referral_code) IN ?
+			UNION
+			SELECT LOWER(referral_code) FROM astroneko_user_referral_codes WHERE LOWER(referral_code) IN ?`, lowered, lowered).
+		Scan(&existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing referral codes: %w", err)
+	}
+
+	taken := make([]string, 0, len(existing))
+	for _, code := range existing {
+		taken = append(taken, byLowered[code])
+	}
+	return taken, nil
+}
+
+// ListUsageAfter counts redemptions the same way as GetReferralCodeUsageCount, for a page
+// of codes at a time
+func (r *referralCodeRepository) ListUsageAfter(ctx context.Context, after *referral_code.ExportCursor, limit int) ([]*referral_code.CodeUsage, error) {
+	query := `SELECT c.*, COUNT(l.id) AS used_count
+		FROM astroneko_general_referral_codes c
+		LEFT JOIN astroneko_referral_logs l ON l.referral_code_id = c.id AND l.code_type = 'general'`
+	args := []any{}
+	if after != nil {
+		query += " WHERE (c.created_at, c.id) > (?, ?)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query += " GROUP BY c.id ORDER BY c.created_at, c.id LIMIT ?"
+	args = append(args, limit)
+
+	var usages []*referral_code.CodeUsage
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&usages); err != nil {
+		return nil, fmt.Errorf("failed to list referral code usage: %w", err)
+	}
+	return usages, nil
+}
+
 // CreateUserReferralCode creates a new user referral code
 func (r *referralCodeRepository) CreateUserReferralCode(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error) {
 	userReferralCode.ID = uuid.New()
+	userReferralCode.ReferralCode = referral_code.NormalizeCode(userReferralCode.ReferralCode)
 	if err := r.db.WithContext(ctx).Create(userReferralCode); err != nil {
 		return nil, err
 	}
 	return userReferralCode, nil
 }
 
+// CreateUserReferralCodeIfAbsent first reserves the code in the namespace general and user
+// codes share. The reservation waits for a concurrent one of the same code to finish, so two
+// users drawing the same code at once, or a CRM user creating it as a general code, cannot
+// both get it.
+func (r *referralCodeRepository) CreateUserReferralCodeIfAbsent(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (bool, error) {
+	now := time.Now()
+	id := uuid.New()
+	userReferralCode.ReferralCode = referral_code.NormalizeCode(userReferralCode.ReferralCode)
+
+	var reserved []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw(`INSERT INTO astroneko_referral_code_namespace (code, source_table, code_id)
+			VALUES (LOWER(?), 'user', ?)
+			ON CONFLICT DO NOTHING
+			RETURNING code_id`, userReferralCode.ReferralCode, id).
+		Scan(&reserved)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve user referral code: %w", err)
+	}
+	if len(reserved) == 0 {
+		return false, nil
+	}
+
+	err = r.db.WithContext(ctx).
+		Exec(`INSERT INTO astroneko_user_referral_codes (id, user_id, referral_code, is_activated, expires_at, is_vanity, created_at, updated_at)
+			VALUES (?, ?, ?, false, ?, ?, ?, ?)`, id, userReferralCode.UserID, userReferralCode.ReferralCode, userReferralCode.ExpiresAt, userReferralCode.IsVanity, now, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to create user referral code: %w", err)
+	}
+	userReferralCode.ID = id
+	userReferralCode.CreatedAt = now
+	userReferralCode.UpdatedAt = now
+	return true, nil
+}
+
 // GetUserReferralCodesByUserID gets all user referral codes for a specific user, sorted by created_at
 func (r *referralCodeRepository) GetUserReferralCodesByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.UserReferralCode, error) {
 	var userReferralCodes []*referral_code.UserReferralCode
@@ -114,7 +233,7 @@ func (r *referralCodeRepository) GetUserReferralCodesByUserID(ctx context.Contex
 // GetUserReferralCodeByCode gets a user referral code by its code
 func (r *referralCodeRepository) GetUserReferralCodeByCode(ctx context.Context, code string) (*referral_code.UserReferralCode, error) {
 	var userReferralCode referral_code.UserReferralCode
-	if err := r.db.WithContext(ctx).Where(WhereClauseReferralCodeEqual, code).First(&userReferralCode); err != nil {
+	if err := r.db.WithContext(ctx).Where(WhereClauseReferralCodeEqual, referral_code.NormalizeCode(code)).First(&userReferralCode); err != nil {
 		return nil, err
 	}
 	return &userReferralCode, nil
@@ -122,6 +241,7 @@ func (r *referralCodeRepository) GetUserReferralCodeByCode(ctx context.Context,
 
 // UpdateUserReferralCode updates a user referral code
 func (r *referralCodeRepository) UpdateUserReferralCode(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error) {
+	userReferralCode.ReferralCode = referral_code.NormalizeCode(userReferralCode.ReferralCode)
 	if err := r.db.WithContext(ctx).Save(userReferralCode); err != nil {
 		return nil, err
 	}
@@ -131,7 +251,7 @@ func (r *referralCodeRepository) UpdateUserReferralCode(ctx context.Context, use
 // IsValidUserReferralCode checks if a user referral code is valid and not activated
 func (r *referralCodeRepository) IsValidUserReferralCode(ctx context.Context, code string) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&referral_code.UserReferralCode{}).Where(WhereClauseReferralCodeEqualAndNotActivated, code).Count(&count); err != nil {
+	if err := r.db.WithContext(ctx).Model(&referral_code.UserReferralCode{}).Where(WhereClauseReferralCodeEqualAndNotActivated, referral_code.NormalizeCode(code)).Count(&count); err != nil {
 		return false, err
 	}
 	return count > 0, nil
@@ -146,6 +266,23 @@ func (r *referralCodeRepository) CreateReferralLog(ctx context.Context, referral
 	return referralLog, nil
 }
 
+// AttributeClick links the redeemer's latest claimed, not yet attributed click on the
+// redeemed code to the redemption's log. Redemptions without such a click change nothing.
+func (r *referralCodeRepository) AttributeClick(ctx context.Context, referralLog *referral_code.ReferralLog) error {
+	err := r.db.WithContext(ctx).
+		Exec(`UPDATE astroneko_referral_clicks SET referral_log_id = ?
+			WHERE id = (
+				SELECT id FROM astroneko_referral_clicks
+				WHERE user_id = ? AND code_type = ? AND referral_code_id = ? AND referral_log_id IS NULL
+				ORDER BY created_at DESC
+				LIMIT 1
+			)`, referralLog.ID, referralLog.RedeemedByUserID, referralLog.CodeType, referralLog.ReferralCodeID)
+	if err != nil {
+		return fmt.Errorf("failed to attribute click to referral log %s: %w", referralLog.ID, err)
+	}
+	return nil
+}
+
 // GetReferralCodeUsageCount gets the usage count for a general referral code
 func (r *referralCodeRepository) GetReferralCodeUsageCount(ctx context.Context, referralCode string) (int64, error) {
 	// First get the referral code ID by the code string
@@ -162,3 +299,160 @@ func (r *referralCodeRepository) GetReferralCodeUsageCount(ctx context.Context,
 	}
 	return count, nil
 }
+
+// GetReferralLogsByUserID gets the referral codes a user redeemed, sorted by created_at
+func (r *referralCodeRepository) GetReferralLogsByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.ReferralLog, error) {
+	var referralLogs []*referral_code.ReferralLog
+	if err := r.db.WithContext(ctx).Where("redeemed_by_user_id = ?", userID).Order("created_at ASC").Find(&referralLogs); err != nil {
+		return nil, err
+	}
+	return referralLogs, nil
+}
+
+// WithTx runs fn against a copy of the repository that shares one transaction
+func (r *referralCodeRepository) WithTx(ctx context.Context, fn func(tx referralCodePorts.RepositoryInterface) error) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin referral code transaction: %w", err)
+	}
+	defer func() {
+		// Release the row locks before the panic carries on up
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&referralCodeRepository{db: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit referral code transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByReferralCodeForUpdate gets a general referral code and holds a row lock on it, so
+// redemptions of the same code are counted against its cap one at a time
+func (r *referralCodeRepository) GetByReferralCodeForUpdate(ctx context.Context, code string) (*referral_code.ReferralCode, error) {
+	var referralCodes []*referral_code.ReferralCode
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM astroneko_general_referral_codes WHERE "+WhereClauseReferralCodeEqual+" LIMIT 1 FOR UPDATE", referral_code.NormalizeCode(code)).
+		Scan(&referralCodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(referralCodes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return referralCodes[0], nil
+}
+
+// CountRedemptions counts how many times a general referral code was redeemed
+func (r *referralCodeRepository) CountRedemptions(ctx context.Context, referralCodeID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&referral_code.ReferralLog{}).
+		Where("referral_code_id = ? AND code_type = ?", referralCodeID, "general").
+		Count(&count); err != nil {
+		return 0, fmt.Errorf("failed to count redemptions of referral code %s: %w", referralCodeID, err)
+	}
+	return count, nil
+}
+
+// GetUserReferralCodeByCodeForUpdate gets a user referral code and holds a row lock on it,
+// so a concurrent redemption of the same code waits until this transaction ends
+func (r *referralCodeRepository) GetUserReferralCodeByCodeForUpdate(ctx context.Context, code string) (*referral_code.UserReferralCode, error) {
+	var userReferralCodes []*referral_code.UserReferralCode
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM astroneko_user_referral_codes WHERE "+WhereClauseReferralCodeEqual+" LIMIT 1 FOR UPDATE", referral_code.NormalizeCode(code)).
+		Scan(&userReferralCodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(userReferralCodes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return userReferralCodes[0], nil
+}
+
+// MarkUserReferralActivated sets is_activated_referral only if it is still false, so a
+// user redeeming two codes at once gets exactly one of them
+func (r *referralCodeRepository) MarkUserReferralActivated(ctx context.Context, userID uuid.UUID, tier string, bonusQuota int) (bool, error) {
+	var updated []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw(`UPDATE astroneko_auth_users
+			SET is_activated_referral = true, referral_tier = ?, bonus_quota = bonus_quota + ?, updated_at = ?
+			WHERE id = ? AND is_activated_referral = false
+			RETURNING id`, tier, bonusQuota, time.Now(), userID).
+		Scan(&updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark referral activated for user %s: %w", userID, err)
+	}
+	return len(updated) > 0, nil
+}
+
+// LockCodeOwner holds a row lock on the user, so their codes are issued or rewarded by
+// one transaction at a time
+func (r *referralCodeRepository) LockCodeOwner(ctx context.Context, userID uuid.UUID) error {
+	var locked []uuid.UUID
+	if err := r.db.WithContext(ctx).Raw("SELECT id FROM astroneko_auth_users WHERE id = ? FOR UPDATE", userID).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to lock user %s: %w", userID, err)
+	}
+	if len(locked) == 0 {
+		return fmt.Errorf("failed to lock user %s: %w", userID, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// CountInvitesForUpdate locks the inviter's user row, so redemptions of different codes of
+// the same inviter are rewarded one after another, and counts the inviter's invites
+func (r *referralCodeRepository) CountInvitesForUpdate(ctx context.Context, inviterID uuid.UUID) (int64, error) {
+	if err := r.LockCodeOwner(ctx, inviterID); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(countInvitesQuery, inviterID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count invites of user %s: %w", inviterID, err)
+	}
+	return count, nil
+}
+
+// CreditReward adds the reward to the ledger and applies it to the inviter. A reward the
+// ledger already holds is skipped and reported as false, so crediting twice is harmless.
+func (r *referralCodeRepository) CreditReward(ctx context.Context, reward *referral_reward.Reward) (bool, error) {
+	now := time.Now()
+	var inserted []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw(`INSERT INTO astroneko_referral_rewards (user_id, referral_log_id, type, amount, badge, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT DO NOTHING
+			RETURNING id`, reward.UserID, reward.ReferralLogID, reward.Type, reward.Amount, reward.Badge, now).
+		Scan(&inserted)
+	if err != nil {
+		return false, fmt.Errorf("failed to record %s reward for user %s: %w", reward.Type, reward.UserID, err)
+	}
+	if len(inserted) == 0 {
+		return false, nil
+	}
+	reward.ID = inserted[0]
+	reward.CreatedAt = now
+
+	var column string
+	switch reward.Type {
+	case referral_reward.TypeBonusReplies:
+		column = "bonus_quota"
+	case referral_reward.TypeDailyReplies:
+		column = "reward_daily_replies"
+	default:
+		return true, nil
+	}
+	err = r.db.WithContext(ctx).
+		Exec("UPDATE astroneko_auth_users SET "+column+" = "+column+" + ?, updated_at = ? WHERE id = ?", reward.Amount, now, reward.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply %s reward to user %s: %w", reward.Type, reward.UserID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
+	"astroneko-backend/testings/mock_ports"
+)
+
+func TestReferralCodeRepository_WithTx_CommitsOnSuccess(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().WithContext(ctx).Return(mockTx)
+	mockTx.EXPECT().Create(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit().Return(nil)
+
+	// Act
+	err := repo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		_, err := tx.CreateReferralLog(ctx, &referral_code.ReferralLog{RedeemedByUserID: uuid.New(), CodeType: "user"})
+		return err
+	})
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestReferralCodeRepository_WithTx_RollsBackOnError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+	bodyErr := errors.New("insert failed")
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act
+	err := repo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		return bodyErr
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, bodyErr))
+}
+
+func TestReferralCodeRepository_WithTx_BeginFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+	beginErr := errors.New("connection refused")
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(beginErr)
+
+	// Act
+	called := false
+	err := repo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		called = true
+		return nil
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, beginErr))
+	assert.False(t, called)
+}
+
+func TestReferralCodeRepository_WithTx_RollsBackOnPanic(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = repo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+			panic("boom")
+		})
+	})
+}
+
+func TestReferralCodeRepository_GetUserReferralCodeByCodeForUpdate_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), "ABCDEFGH").DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+		assert.Contains(t, sql, "FOR UPDATE")
+		return mockDB
+	})
+	mockDB.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	// Act
+	code, err := repo.GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH")
+
+	// Assert
+	assert.Nil(t, code)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestReferralCodeRepository_MarkUserReferralActivated(t *testing.T) {
+	tests := []struct {
+		name     string
+		returned []uuid.UUID
+		marked   bool
+	}{
+		{name: "flag was unset", returned: []uuid.UUID{uuid.New()}, marked: true},
+		{name: "flag already set", returned: nil, marked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+			repo := NewReferralCodeRepository(mockDB)
+			ctx := context.Background()
+			userID := uuid.New()
+
+			mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+			mockDB.EXPECT().Raw(gomock.Any(), "plus", 5, gomock.Any(), userID).Return(mockDB)
+			mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+				*dest.(*[]uuid.UUID) = tt.returned
+				return nil
+			})
+
+			// Act
+			marked, err := repo.MarkUserReferralActivated(ctx, userID, "plus", 5)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.marked, marked)
+		})
+	}
+}
+
+func TestReferralCodeRepository_CreditReward(t *testing.T) {
+	tests := []struct {
+		name       string
+		rewardType string
+		inserted   []uuid.UUID
+		column     string
+		credited   bool
+	}{
+		{name: "bonus replies go to the bonus quota", rewardType: referral_reward.TypeBonusReplies, inserted: []uuid.UUID{uuid.New()}, column: "bonus_quota", credited: true},
+		{name: "daily replies raise the daily limit", rewardType: referral_reward.TypeDailyReplies, inserted: []uuid.UUID{uuid.New()}, column: "reward_daily_replies", credited: true},
+		{name: "badges only go in the ledger", rewardType: referral_reward.TypeBadge, inserted: []uuid.UUID{uuid.New()}, credited: true},
+		{name: "already credited", rewardType: referral_reward.TypeBonusReplies, credited: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+			repo := NewReferralCodeRepository(mockDB)
+			ctx := context.Background()
+			reward := &referral_reward.Reward{UserID: uuid.New(), ReferralLogID: uuid.New(), Type: tt.rewardType, Amount: 5}
+
+			mockDB.EXPECT().WithContext(ctx).Return(mockDB).AnyTimes()
+			mockDB.EXPECT().Raw(gomock.Any(), reward.UserID, reward.ReferralLogID, tt.rewardType, 5, reward.Badge, gomock.Any()).Return(mockDB)
+			mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+				*dest.(*[]uuid.UUID) = tt.inserted
+				return nil
+			})
+			if tt.column != "" {
+				mockDB.EXPECT().Exec(gomock.Any(), 5, gomock.Any(), reward.UserID).DoAndReturn(func(sql string, values ...any) error {
+					assert.Contains(t, sql, tt.column+" = "+tt.column+" + ?")
+					return nil
+				})
+			}
+
+			// Act
+			credited, err := repo.CreditReward(ctx, reward)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.credited, credited)
+		})
+	}
+}
+
+func TestReferralCodeRepository_CreateUserReferralCodeIfAbsent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+	code := &referral_code.UserReferralCode{UserID: uuid.New(), ReferralCode: "k7mpq 2xzb"}
+	var reservedID uuid.UUID
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB).Times(2)
+	gomock.InOrder(
+		mockDB.EXPECT().Raw(gomock.Any(), "K7MPQ2XZB", gomock.Any()).DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+			assert.Contains(t, sql, "astroneko_referral_code_namespace")
+			reservedID = values[1].(uuid.UUID)
+			return mockDB
+		}),
+		mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+			*dest.(*[]uuid.UUID) = []uuid.UUID{reservedID}
+			return nil
+		}),
+		mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), code.UserID, "K7MPQ2XZB", code.ExpiresAt, false, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(sql string, values ...any) error {
+				assert.Equal(t, reservedID, values[0])
+				return nil
+			}),
+	)
+
+	// Act
+	created, err := repo.CreateUserReferralCodeIfAbsent(ctx, code)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, reservedID, code.ID)
+}
+
+func TestReferralCodeRepository_CreateUserReferralCodeIfAbsent_CodeTaken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+	code := &referral_code.UserReferralCode{UserID: uuid.New(), ReferralCode: "K7MPQ2XZB"}
+
+	// A general or user code already holds the code, so nothing is inserted
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), "K7MPQ2XZB", gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	// Act
+	created, err := repo.CreateUserReferralCodeIfAbsent(ctx, code)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestReferralCodeRepository_Create_CodeTaken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Create(gomock.Any()).Return(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "astroneko_referral_code_namespace_pkey"}))
+
+	// Act
+	created, err := repo.Create(ctx, &referral_code.ReferralCode{ReferralCode: "NEKOLOVER"})
+
+	// Assert
+	assert.ErrorIs(t, err, referral_code.ErrCodeTaken)
+	assert.Nil(t, created)
+}
+
+func TestReferralCodeRepository_IsValidReferralCode_NormalizesCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Model(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Where(WhereClauseReferralCodeEqual, "ASTRONEKO!").Return(mockDB)
+	mockDB.EXPECT().Count(gomock.Any()).DoAndReturn(func(count *int64) error {
+		*count = 1
+		return nil
+	})
+
+	// Act
+	valid, err := repo.IsValidReferralCode(ctx, " astroneko! ")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestReferralCodeRepository_ExistingReferralCodes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repo := NewReferralCodeRepository(mockDB)
+	ctx := context.Background()
+	lowered := []string{"summer1", "summer2"}
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lowered, lowered).Return(mockDB)
+	mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+		*dest.(*[]string) = []string{"summer2"}
+		return nil
+	})
+
+	// Act
+	existing, err := repo.ExistingReferralCodes(ctx, []string{"Summer1", "summer 2"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SUMMER2"}, existing)
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+	"astroneko-backend/internal/core/ports"
+	referralGraphPorts "astroneko-backend/internal/core/ports/referral_graph"
+
+	"github.com/google/uuid"
+)
+
+type referralGraphRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewReferralGraphRepository creates a new referral graph repository instance
+func NewReferralGraphRepository(db ports.DatabaseInterface) referralGraphPorts.RepositoryInterface {
+	return &referralGraphRepository{
+		db: db,
+	}
+}
+
+// Only user codes have an owner, so only their redemptions are invites
+const (
+	referralEdgesFrom = `FROM astroneko_referral_logs l
+	JOIN astroneko_user_referral_codes c ON c.id = l.referral_code_id
+	LEFT JOIN astroneko_auth_users u ON u.id = l.redeemed_by_user_id`
+	referralEdgesWhere = "l.code_type = 'user'"
+)
+
+func (r *referralGraphRepository) ListInvitees(ctx context.Context, inviterIDs []uuid.UUID) ([]referral_graph.Edge, error) {
+	if len(inviterIDs) == 0 {
+		return nil, nil
+	}
+
+	var edges []referral_graph.Edge
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT c.user_id AS inviter_id, l.redeemed_by_user_id AS invitee_id, l.created_at AS redeemed_at,
+				u.email AS invitee_email, u.display_name AS invitee_display_name, u.latest_login_at AS invitee_latest_login_at
+			`+referralEdgesFrom+`
+			WHERE `+referralEdgesWhere+` AND c.user_id IN ?
+			ORDER BY l.created_at DESC`, inviterIDs).
+		Scan(&edges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitees of %d users: %w", len(inviterIDs), err)
+	}
+
+	return edges, nil
+}
+
+func (r *referralGraphRepository) Leaderboard(ctx context.Context, filter referral_graph.LeaderboardFilter) ([]referral_graph.LeaderboardRow, int64, error) {
+	conditions := []string{referralEdgesWhere}
+	var args []any
+	if filter.From != nil {
+		conditions = append(conditions, "l.created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "l.created_at < ?")
+		args = append(args, *filter.To)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(DISTINCT c.user_id) "+referralEdgesFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count referral leaderboard: %w", err)
+	}
+
+	var rows []referral_graph.LeaderboardRow
+	pageArgs := append([]any{filter.ActiveSince}, args...)
+	pageArgs = append(pageArgs, filter.Limit, filter.Offset)
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT c.user_id AS inviter_id, inviter.email AS inviter_email, COUNT(*) AS invites,
+				COUNT(*) FILTER (WHERE u.latest_login_at >= ?) AS active_invites,
+				MIN(l.created_at) AS first_invite_at, MAX(l.created_at) AS last_invite_at
+			`+referralEdgesFrom+`
+			LEFT JOIN astroneko_auth_users inviter ON inviter.id = c.user_id`+where+`
+			GROUP BY c.user_id, inviter.email
+			ORDER BY invites DESC, active_invites DESC, first_invite_at ASC
+			LIMIT ? OFFSET ?`, pageArgs...).
+		Scan(&rows)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list referral leaderboard: %w", err)
+	}
+
+	return rows, total, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/ports"
+	referralLinkPorts "astroneko-backend/internal/core/ports/referral_link"
+
+	"github.com/google/uuid"
+)
+
+type referralLinkRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewReferralLinkRepository creates a new referral link repository instance
+func NewReferralLinkRepository(db ports.DatabaseInterface) referralLinkPorts.RepositoryInterface {
+	return &referralLinkRepository{
+		db: db,
+	}
+}
+
+func (r *referralLinkRepository) CreateClick(ctx context.Context, click *referral_link.Click) error {
+	click.ID = uuid.New()
+	if err := r.db.WithContext(ctx).Create(click); err != nil {
+		return fmt.Errorf("failed to record click on %s referral code %s: %w", click.CodeType, click.ReferralCodeID, err)
+	}
+	return nil
+}
+
+func (r *referralLinkRepository) ClaimClick(ctx context.Context, clickID, userID uuid.UUID, notBefore time.Time) (bool, error) {
+	var claimed []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw(`UPDATE astroneko_referral_clicks k
+			SET user_id = u.id, claimed_at = ?, is_new_user = u.created_at >= k.created_at
+			FROM astroneko_auth_users u
+			WHERE k.id = ? AND k.user_id IS NULL AND k.created_at >= ? AND u.id = ?
+			RETURNING k.id`, time.Now(), clickID, notBefore, userID).
+		Scan(&claimed)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim referral click %s: %w", clickID, err)
+	}
+	return len(claimed) > 0, nil
+}
+
+func (r *referralLinkRepository) ListClicksByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_link.Click, error) {
+	var clicks []*referral_link.Click
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&clicks); err != nil {
+		return nil, fmt.Errorf("failed to list referral clicks of user %s: %w", userID, err)
+	}
+	return clicks, nil
+}
+
+const referralClicksFrom = `FROM astroneko_referral_clicks k
+	LEFT JOIN astroneko_general_referral_codes g ON k.code_type = 'general' AND g.id = k.referral_code_id
+	LEFT JOIN astroneko_user_referral_codes uc ON k.code_type = 'user' AND uc.id = k.referral_code_id`
+
+// funnelCountColumns count clicks, the claims of users who signed up after their click,
+// and the clicks attributed to a redemption
+const funnelCountColumns = `COUNT(*) AS clicks,
+	COUNT(*) FILTER (WHERE k.is_new_user) AS signups,
+	COUNT(k.referral_log_id) AS activations`
+
+func (r *referralLinkRepository) Funnel(ctx context.Context, filter referral_link.FunnelFilter) ([]referral_link.FunnelRow, *referral_link.FunnelTotals, error) {
+	conditions := []string{"TRUE"}
+	var args []any
+	if filter.CodeType != "" {
+		conditions = append(conditions, "k.code_type = ?")
+		args = append(args, filter.CodeType)
+	}
+	if filter.UTMSource != "" {
+		conditions = append(conditions, "k.utm_source = ?")
+		args = append(args, filter.UTMSource)
+	}
+	if filter.UTMCampaign != "" {
+		conditions = append(conditions, "k.utm_campaign = ?")
+		args = append(args, filter.UTMCampaign)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "k.created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "k.created_at < ?")
+		args = append(args, *filter.To)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var totals referral_link.FunnelTotals
+	err := r.db.WithContext(ctx).
+		Raw("SELECT COUNT(DISTINCT (k.code_type, k.referral_code_id)) AS codes, "+funnelCountColumns+" FROM astroneko_referral_clicks k"+where, args...).
+		Scan(&totals)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count referral funnel: %w", err)
+	}
+
+	var rows []referral_link.FunnelRow
+	pageArgs := append(args, filter.Limit, filter.Offset)
+	err = r.db.WithContext(ctx).
+		Raw(`SELECT k.code_type, k.referral_code_id, COALESCE(g.referral_code, uc.referral_code) AS referral_code, `+funnelCountColumns+`
+			`+referralClicksFrom+where+`
+			GROUP BY k.code_type, k.referral_code_id, g.referral_code, uc.referral_code
+			ORDER BY clicks DESC, k.code_type, k.referral_code_id
+			LIMIT ? OFFSET ?`, pageArgs...).
+		Scan(&rows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list referral funnel: %w", err)
+	}
+
+	return rows, &totals, nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"astroneko-backend/testings/mock_ports"
+)
+
+func TestReferralLinkRepository_ClaimClick(t *testing.T) {
+	tests := []struct {
+		name     string
+		returned []uuid.UUID
+		claimed  bool
+	}{
+		{name: "unclaimed click", returned: []uuid.UUID{uuid.New()}, claimed: true},
+		{name: "claimed, expired or unknown click", returned: nil, claimed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+			repo := NewReferralLinkRepository(mockDB)
+			ctx := context.Background()
+			clickID, userID := uuid.New(), uuid.New()
+			notBefore := time.Now().Add(-time.Hour)
+
+			mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+			mockDB.EXPECT().Raw(gomock.Any(), gomock.Any(), clickID, notBefore, userID).DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+				assert.Contains(t, sql, "k.user_id IS NULL")
+				return mockDB
+			})
+			mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+				*dest.(*[]uuid.UUID) = tt.returned
+				return nil
+			})
+
+			// Act
+			claimed, err := repo.ClaimClick(ctx, clickID, userID, notBefore)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.claimed, claimed)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/ports"
+	referralRewardPorts "astroneko-backend/internal/core/ports/referral_reward"
+
+	"github.com/google/uuid"
+)
+
+// countInvitesQuery counts the redemptions of one user's referral codes
+const countInvitesQuery = `SELECT COUNT(*) FROM astroneko_referral_logs l
+	JOIN astroneko_user_referral_codes c ON c.id = l.referral_code_id
+	WHERE l.code_type = 'user' AND c.user_id = ?`
+
+type referralRewardRepository struct {
+	db ports.DatabaseInterface
+}
+
+// NewReferralRewardRepository creates a new referral reward repository instance
+func NewReferralRewardRepository(db ports.DatabaseInterface) referralRewardPorts.RepositoryInterface {
+	return &referralRewardRepository{
+		db: db,
+	}
+}
+
+func (r *referralRewardRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_reward.Reward, error) {
+	var rewards []*referral_reward.Reward
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&rewards); err != nil {
+		return nil, fmt.Errorf("failed to list referral rewards of user %s: %w", userID, err)
+	}
+	return rewards, nil
+}
+
+func (r *referralRewardRepository) CountInvites(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(countInvitesQuery, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count invites of user %s: %w", userID, err)
+	}
+	return count, nil
+}
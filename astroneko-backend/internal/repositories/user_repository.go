@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"astroneko-backend/internal/core/domain/user"
 	"astroneko-backend/internal/core/ports"
@@ -56,8 +58,13 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	return &user, nil
 }
 
-func (r *userRepository) Update(ctx context.Context, user *user.User) (*user.User, error) {
-	if err := r.db.WithContext(ctx).Save(user); err != nil {
+func (r *userRepository) Update(ctx context.Context, user *user.User, columns []string) (*user.User, error) {
+	if len(columns) == 0 {
+		return user, nil
+	}
+
+	selected := append(append([]string{}, columns...), "updated_at")
+	if err := r.db.WithContext(ctx).Model(user).Select(selected).Updates(user); err != nil {
 		return nil, err
 	}
 	return user, nil
@@ -94,3 +101,16 @@ func (r *userRepository) GetTotalUsers(ctx context.Context) (int64, error) {
 	}
 	return count, nil
 }
+
+// ConsumeBonusQuota decrements the quota in one conditional statement so parallel
+// requests cannot spend the same bonus request twice
+func (r *userRepository) ConsumeBonusQuota(ctx context.Context, id uuid.UUID) (bool, error) {
+	var updated []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE astroneko_auth_users SET bonus_quota = bonus_quota - 1, updated_at = ? WHERE id = ? AND bonus_quota > 0 RETURNING id", time.Now(), id).
+		Scan(&updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume bonus quota of user %s: %w", id, err)
+	}
+	return len(updated) > 0, nil
+}
@@ -306,26 +306,37 @@ func TestUserRepository_Update_Success(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB).AnyTimes()
-	mockDB.EXPECT().Save(gomock.Any()).DoAndReturn(func(value interface{}) error {
-		if user, ok := value.(*user.User); ok {
-			user.IsActivatedReferral = updatedUser.IsActivatedReferral
-			user.LatestLoginAt = updatedUser.LatestLoginAt
-			user.ProfileImageURL = updatedUser.ProfileImageURL
-			user.DisplayName = updatedUser.DisplayName
-		}
-		return nil
-	})
+	mockDB.EXPECT().Model(updatedUser).Return(mockDB)
+	mockDB.EXPECT().Select([]string{user.ColumnDisplayName, "updated_at"}).Return(mockDB)
+	mockDB.EXPECT().Updates(updatedUser).Return(nil)
 
 	// Act
-	result, err := repository.Update(ctx, updatedUser)
+	result, err := repository.Update(ctx, updatedUser, []string{user.ColumnDisplayName})
 
 	// Assert
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.Equal(t, updatedUser.IsActivatedReferral, result.IsActivatedReferral)
 	assert.Equal(t, updatedUser.DisplayName, result.DisplayName)
 }
 
+func TestUserRepository_Update_NoColumns(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewUserRepository(mockDB)
+	ctx := context.Background()
+	testUser := buildTestUser()
+
+	// Act
+	result, err := repository.Update(ctx, testUser, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testUser, result)
+}
+
 func TestUserRepository_Update_DatabaseError(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -341,10 +352,12 @@ func TestUserRepository_Update_DatabaseError(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB).AnyTimes()
-	mockDB.EXPECT().Save(gomock.Any()).Return(dbError)
+	mockDB.EXPECT().Model(testUser).Return(mockDB)
+	mockDB.EXPECT().Select(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Updates(testUser).Return(dbError)
 
 	// Act
-	result, err := repository.Update(ctx, testUser)
+	result, err := repository.Update(ctx, testUser, user.BirthProfileColumns)
 
 	// Assert
 	assert.Error(t, err)
@@ -2,10 +2,15 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"astroneko-backend/internal/core/domain/waiting_list"
 	"astroneko-backend/internal/core/ports"
 	waitingListPorts "astroneko-backend/internal/core/ports/waiting_list"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type waitingListRepository struct {
@@ -21,13 +26,104 @@ func NewWaitingListRepository(db ports.DatabaseInterface) waitingListPorts.Repos
 	}
 }
 
+func (r *waitingListRepository) WithTx(ctx context.Context, fn func(tx waitingListPorts.RepositoryInterface) error) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return fmt.Errorf("failed to begin waiting list transaction: %w", err)
+	}
+	defer rollbackOnPanic(tx)
+
+	if err := fn(NewWaitingListRepository(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit waiting list transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *waitingListRepository) GetListBySlug(ctx context.Context, slug string) (*waiting_list.WaitingList, error) {
+	var list waiting_list.WaitingList
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&list); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, waiting_list.ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to get waiting list %s: %w", slug, err)
+	}
+	return &list, nil
+}
+
+func (r *waitingListRepository) GetListByID(ctx context.Context, id uuid.UUID) (*waiting_list.WaitingList, error) {
+	var list waiting_list.WaitingList
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&list); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, waiting_list.ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to get waiting list %s: %w", id, err)
+	}
+	return &list, nil
+}
+
+func (r *waitingListRepository) GetListBySlugForUpdate(ctx context.Context, slug string) (*waiting_list.WaitingList, error) {
+	var lists []*waiting_list.WaitingList
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM astroneko_waiting_lists WHERE slug = ? LIMIT 1 FOR UPDATE", slug).
+		Scan(&lists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock waiting list %s: %w", slug, err)
+	}
+	if len(lists) == 0 {
+		return nil, waiting_list.ErrListNotFound
+	}
+	return lists[0], nil
+}
+
+func (r *waitingListRepository) ListLists(ctx context.Context) ([]*waiting_list.WaitingList, error) {
+	var lists []*waiting_list.WaitingList
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&lists); err != nil {
+		return nil, fmt.Errorf("failed to list waiting lists: %w", err)
+	}
+	return lists, nil
+}
+
+func (r *waitingListRepository) CreateList(ctx context.Context, list *waiting_list.WaitingList) error {
+	list.ID = uuid.New()
+	if err := r.db.WithContext(ctx).Create(list); err != nil {
+		return fmt.Errorf("failed to create waiting list %s: %w", list.Slug, err)
+	}
+	return nil
+}
+
+func (r *waitingListRepository) UpdateList(ctx context.Context, list *waiting_list.WaitingList, columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	selected := append(append([]string{}, columns...), "updated_at")
+	if err := r.db.WithContext(ctx).Model(list).Select(selected).Updates(list); err != nil {
+		return fmt.Errorf("failed to update waiting list %s: %w", list.Slug, err)
+	}
+	return nil
+}
+
+func (r *waitingListRepository) CountEntries(ctx context.Context, listID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&waiting_list.WaitingListUser{}).Where("list_id = ?", listID).Count(&count); err != nil {
+		return 0, fmt.Errorf("failed to count entries of waiting list %s: %w", listID, err)
+	}
+	return count, nil
+}
+
 func (r *waitingListRepository) Create(ctx context.Context, waitingListUser *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error) {
 	return r.GenericRepository.Create(ctx, waitingListUser)
 }
 
-func (r *waitingListRepository) GetByEmail(ctx context.Context, email string) (*waiting_list.WaitingListUser, error) {
+// GetByEmail finds the list's entry for the email. Emails are unique per list regardless of case.
+func (r *waitingListRepository) GetByEmail(ctx context.Context, listID uuid.UUID, email string) (*waiting_list.WaitingListUser, error) {
 	var waitingListUser waiting_list.WaitingListUser
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&waitingListUser); err != nil {
+	if err := r.db.WithContext(ctx).Where("list_id = ? AND LOWER(email) = LOWER(?)", listID, email).First(&waitingListUser); err != nil {
 		return nil, err
 	}
 	return &waitingListUser, nil
@@ -37,15 +133,15 @@ func (r *waitingListRepository) GetByID(ctx context.Context, id string) (*waitin
 	return r.GenericRepository.GetByID(ctx, id)
 }
 
-func (r *waitingListRepository) List(ctx context.Context, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
+func (r *waitingListRepository) List(ctx context.Context, listID uuid.UUID, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
 	var waitingListUsers []*waiting_list.WaitingListUser
 	var count int64
 
-	if err := r.db.WithContext(ctx).Model(&waiting_list.WaitingListUser{}).Count(&count); err != nil {
+	if err := r.db.WithContext(ctx).Model(&waiting_list.WaitingListUser{}).Where("list_id = ?", listID).Count(&count); err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Order("created_at DESC").Find(&waitingListUsers); err != nil {
+	if err := r.db.WithContext(ctx).Where("list_id = ?", listID).Limit(limit).Offset(offset).Order("created_at DESC").Find(&waitingListUsers); err != nil {
 		return nil, 0, err
 	}
 
@@ -55,3 +151,65 @@ func (r *waitingListRepository) List(ctx context.Context, limit, offset int) ([]
 func (r *waitingListRepository) Delete(ctx context.Context, id string) error {
 	return r.GenericRepository.Delete(ctx, id)
 }
+
+func (r *waitingListRepository) NextQueueNumber(ctx context.Context, listID uuid.UUID) (int64, error) {
+	var next int64
+	err := r.db.WithContext(ctx).
+		Raw("SELECT COALESCE(MAX(queue_number), 0) + 1 FROM astroneko_waiting_list_users WHERE list_id = ?", listID).
+		Scan(&next)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next queue number of waiting list %s: %w", listID, err)
+	}
+	return next, nil
+}
+
+func (r *waitingListRepository) GetByInviteCode(ctx context.Context, listID uuid.UUID, inviteCode string) (*waiting_list.WaitingListUser, error) {
+	var waitingListUser waiting_list.WaitingListUser
+	if err := r.db.WithContext(ctx).Where("list_id = ? AND invite_code = ?", listID, inviteCode).First(&waitingListUser); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, waiting_list.ErrEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get waiting list entry by invite code: %w", err)
+	}
+	return &waitingListUser, nil
+}
+
+func (r *waitingListRepository) HasReferralFromFingerprint(ctx context.Context, referrerID uuid.UUID, fingerprint string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&waiting_list.WaitingListUser{}).
+		Where("referred_by_id = ? AND fingerprint = ?", referrerID, fingerprint).
+		Count(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to count referrals of waiting list entry %s: %w", referrerID, err)
+	}
+	return count > 0, nil
+}
+
+func (r *waitingListRepository) CreditReferral(ctx context.Context, referrerID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Exec("UPDATE astroneko_waiting_list_users SET referral_count = referral_count + 1, updated_at = NOW() WHERE id = ?", referrerID)
+	if err != nil {
+		return fmt.Errorf("failed to credit referral to waiting list entry %s: %w", referrerID, err)
+	}
+	return nil
+}
+
+// GetPosition counts the entries ahead of this one. An entry's rank is its queue number less
+// boost places per referral, with ties going to whoever joined first.
+func (r *waitingListRepository) GetPosition(ctx context.Context, entry *waiting_list.WaitingListUser, boost int) (*waiting_list.QueuePosition, error) {
+	rank := entry.QueueNumber - int64(entry.ReferralCount*boost)
+	var position waiting_list.QueuePosition
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT COUNT(*) FILTER (
+				WHERE queue_number - referral_count * ? < ?
+				   OR (queue_number - referral_count * ? = ? AND queue_number < ?)
+			) + 1 AS position, COUNT(*) AS total
+			FROM astroneko_waiting_list_users
+			WHERE list_id = ?`, boost, rank, boost, rank, entry.QueueNumber, entry.ListID).
+		Scan(&position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position of waiting list entry %s: %w", entry.ID, err)
+	}
+	return &position, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,11 +13,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 
 	"astroneko-backend/internal/core/domain/waiting_list"
+	waitingListPorts "astroneko-backend/internal/core/ports/waiting_list"
 	"astroneko-backend/testings/mock_ports"
 )
 
+// testWaitingListID is the list the entries of these tests are on
+var testWaitingListID = uuid.MustParse("0b6f8e2a-4c1d-4f7e-9a3b-5d2c8e1f6a70")
+
 // Test data builders for consistent test data
 func buildWaitingListUser() *waiting_list.WaitingListUser {
 	return &waiting_list.WaitingListUser{
@@ -111,7 +117,7 @@ func TestWaitingListRepository_GetByEmail_Success(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-	mockDB.EXPECT().Where("email = ?", email).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, email).Return(mockDB)
 	mockDB.EXPECT().First(gomock.Any()).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
 		if user, ok := dest.(*waiting_list.WaitingListUser); ok {
 			*user = *expectedUser
@@ -120,7 +126,7 @@ func TestWaitingListRepository_GetByEmail_Success(t *testing.T) {
 	})
 
 	// Act
-	result, err := repository.GetByEmail(ctx, email)
+	result, err := repository.GetByEmail(ctx, testWaitingListID, email)
 
 	// Assert
 	require.NoError(t, err)
@@ -141,11 +147,11 @@ func TestWaitingListRepository_GetByEmail_NotFound(t *testing.T) {
 
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-	mockDB.EXPECT().Where("email = ?", email).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, email).Return(mockDB)
 	mockDB.EXPECT().First(gomock.Any()).Return(gorm.ErrRecordNotFound)
 
 	// Act
-	result, err := repository.GetByEmail(ctx, email)
+	result, err := repository.GetByEmail(ctx, testWaitingListID, email)
 
 	// Assert
 	assert.Error(t, err)
@@ -246,10 +252,12 @@ func TestWaitingListRepository_List_Success(t *testing.T) {
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB).AnyTimes()
 	mockDB.EXPECT().Model(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
 	mockDB.EXPECT().Count(gomock.Any()).DoAndReturn(func(count *int64) error {
 		*count = expectedCount
 		return nil
 	})
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
 	mockDB.EXPECT().Limit(limit).Return(mockDB)
 	mockDB.EXPECT().Offset(offset).Return(mockDB)
 	mockDB.EXPECT().Order("created_at DESC").Return(mockDB)
@@ -261,7 +269,7 @@ func TestWaitingListRepository_List_Success(t *testing.T) {
 	})
 
 	// Act
-	users, count, err := repository.List(ctx, limit, offset)
+	users, count, err := repository.List(ctx, testWaitingListID, limit, offset)
 
 	// Assert
 	require.NoError(t, err)
@@ -286,10 +294,11 @@ func TestWaitingListRepository_List_CountError(t *testing.T) {
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
 	mockDB.EXPECT().Model(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
 	mockDB.EXPECT().Count(gomock.Any()).Return(dbError)
 
 	// Act
-	users, count, err := repository.List(ctx, limit, offset)
+	users, count, err := repository.List(ctx, testWaitingListID, limit, offset)
 
 	// Assert
 	assert.Error(t, err)
@@ -314,17 +323,19 @@ func TestWaitingList_List_FindError(t *testing.T) {
 	// Setup expectations
 	mockDB.EXPECT().WithContext(ctx).Return(mockDB).AnyTimes()
 	mockDB.EXPECT().Model(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
 	mockDB.EXPECT().Count(gomock.Any()).DoAndReturn(func(count *int64) error {
 		*count = expectedCount
 		return nil
 	})
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
 	mockDB.EXPECT().Limit(limit).Return(mockDB)
 	mockDB.EXPECT().Offset(offset).Return(mockDB)
 	mockDB.EXPECT().Order("created_at DESC").Return(mockDB)
 	mockDB.EXPECT().Find(gomock.Any()).Return(dbError)
 
 	// Act
-	users, count, err := repository.List(ctx, limit, offset)
+	users, count, err := repository.List(ctx, testWaitingListID, limit, offset)
 
 	// Assert
 	assert.Error(t, err)
@@ -437,13 +448,13 @@ func TestWaitingListRepository_GetByEmail_InvalidEmails(t *testing.T) {
 			if tc.email == "nonexistent@example.com" {
 				// Setup expectations for not found case
 				mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-				mockDB.EXPECT().Where("email = ?", tc.email).Return(mockDB)
+				mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, tc.email).Return(mockDB)
 				mockDB.EXPECT().First(gomock.Any()).Return(gorm.ErrRecordNotFound)
 			} else {
 				// Setup expectations for success case
 				expectedUser := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", tc.email)
 				mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-				mockDB.EXPECT().Where("email = ?", tc.email).Return(mockDB)
+				mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, tc.email).Return(mockDB)
 				mockDB.EXPECT().First(gomock.Any()).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
 					if user, ok := dest.(*waiting_list.WaitingListUser); ok {
 						*user = *expectedUser
@@ -453,7 +464,7 @@ func TestWaitingListRepository_GetByEmail_InvalidEmails(t *testing.T) {
 			}
 
 			// Act
-			result, err := repository.GetByEmail(ctx, tc.email)
+			result, err := repository.GetByEmail(ctx, testWaitingListID, tc.email)
 
 			// Assert
 			if tc.email == "nonexistent@example.com" {
@@ -633,7 +644,7 @@ func TestWaitingListRepository_EmailFormats(t *testing.T) {
 				// Setup expectations for existing user
 				expectedUser := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", tc.email)
 				mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-				mockDB.EXPECT().Where("email = ?", tc.email).Return(mockDB)
+				mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, tc.email).Return(mockDB)
 				mockDB.EXPECT().First(gomock.Any()).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
 					if user, ok := dest.(*waiting_list.WaitingListUser); ok {
 						*user = *expectedUser
@@ -643,12 +654,12 @@ func TestWaitingListRepository_EmailFormats(t *testing.T) {
 			} else {
 				// Setup expectations for not found
 				mockDB.EXPECT().WithContext(ctx).Return(mockDB)
-				mockDB.EXPECT().Where("email = ?", tc.email).Return(mockDB)
+				mockDB.EXPECT().Where("list_id = ? AND LOWER(email) = LOWER(?)", testWaitingListID, tc.email).Return(mockDB)
 				mockDB.EXPECT().First(gomock.Any()).Return(gorm.ErrRecordNotFound)
 			}
 
 			// Act
-			result, err := repository.GetByEmail(ctx, tc.email)
+			result, err := repository.GetByEmail(ctx, testWaitingListID, tc.email)
 
 			// Assert
 			if tc.shouldExist {
@@ -663,3 +674,310 @@ func TestWaitingListRepository_EmailFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitingListRepository_GetListBySlug_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Where("slug = ?", "tarot-deck").Return(mockDB)
+	mockDB.EXPECT().First(gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	// Act
+	list, err := repository.GetListBySlug(ctx, "tarot-deck")
+
+	// Assert
+	assert.Nil(t, list)
+	assert.ErrorIs(t, err, waiting_list.ErrListNotFound)
+}
+
+func TestWaitingListRepository_CreateList_Closed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+	list := &waiting_list.WaitingList{Slug: "tarot-deck", Name: "Tarot Deck", IsOpen: false}
+
+	// The migration defaults is_open to true; a gorm default on the field would drop the false from the insert
+	listSchema, err := schema.Parse(list, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	assert.False(t, listSchema.LookUpField("IsOpen").HasDefaultValue)
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Create(gomock.Any()).DoAndReturn(func(value interface{}) error {
+		created, ok := value.(*waiting_list.WaitingList)
+		require.True(t, ok)
+		assert.False(t, created.IsOpen)
+		return nil
+	})
+
+	// Act
+	err = repository.CreateList(ctx, list)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, list.ID)
+	assert.False(t, list.IsOpen)
+}
+
+func TestWaitingListRepository_GetListBySlugForUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		returned []*waiting_list.WaitingList
+		wantErr  error
+	}{
+		{name: "locks the list", returned: []*waiting_list.WaitingList{{ID: testWaitingListID, Slug: "astro-boxing"}}},
+		{name: "unknown list", returned: nil, wantErr: waiting_list.ErrListNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+			repository := NewWaitingListRepository(mockDB)
+			ctx := context.Background()
+
+			mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+			mockDB.EXPECT().Raw(gomock.Any(), "astro-boxing").DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+				assert.Contains(t, sql, "FOR UPDATE")
+				return mockDB
+			})
+			mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+				*dest.(*[]*waiting_list.WaitingList) = tt.returned
+				return nil
+			})
+
+			// Act
+			list, err := repository.GetListBySlugForUpdate(ctx, "astro-boxing")
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testWaitingListID, list.ID)
+		})
+	}
+}
+
+func TestWaitingListRepository_WithTx_RollsBackOnError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act
+	err := repository.WithTx(ctx, func(tx waitingListPorts.RepositoryInterface) error {
+		return waiting_list.ErrListFull
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, waiting_list.ErrListFull)
+}
+
+func TestWaitingListRepository_WithTx_BeginFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+	beginErr := errors.New("connection refused")
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(beginErr)
+
+	// Act
+	called := false
+	err := repository.WithTx(ctx, func(tx waitingListPorts.RepositoryInterface) error {
+		called = true
+		return nil
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, beginErr)
+	assert.False(t, called)
+}
+
+func TestWaitingListRepository_WithTx_RollsBackOnPanic(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	mockTx := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Begin().Return(mockTx)
+	mockTx.EXPECT().Error().Return(nil)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = repository.WithTx(ctx, func(tx waitingListPorts.RepositoryInterface) error {
+			panic("boom")
+		})
+	})
+}
+
+func TestWaitingListRepository_UpdateList_WritesOnlyGivenColumns(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+	list := &waiting_list.WaitingList{ID: testWaitingListID, Slug: waiting_list.DefaultListSlug}
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Model(list).Return(mockDB)
+	mockDB.EXPECT().Select([]string{waiting_list.ColumnIsOpen, "updated_at"}).Return(mockDB)
+	mockDB.EXPECT().Updates(list).Return(nil)
+
+	// Act
+	err := repository.UpdateList(ctx, list, []string{waiting_list.ColumnIsOpen})
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestWaitingListRepository_UpdateList_NothingToWrite(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := NewWaitingListRepository(mock_ports.NewMockDatabaseInterface(ctrl))
+
+	// Act
+	err := repository.UpdateList(context.Background(), &waiting_list.WaitingList{}, nil)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestWaitingListRepository_CountEntries(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Model(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ?", testWaitingListID).Return(mockDB)
+	mockDB.EXPECT().Count(gomock.Any()).DoAndReturn(func(count *int64) error {
+		*count = 42
+		return nil
+	})
+
+	// Act
+	count, err := repository.CountEntries(ctx, testWaitingListID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+}
+
+func TestWaitingListRepository_GetPosition(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+	entry := &waiting_list.WaitingListUser{ListID: testWaitingListID, QueueNumber: 120, ReferralCount: 3}
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	// Three referrals at five places each rank the entry as if it had joined 105th
+	mockDB.EXPECT().Raw(gomock.Any(), 5, int64(105), 5, int64(105), int64(120), testWaitingListID).DoAndReturn(func(sql string, values ...any) *mock_ports.MockDatabaseInterface {
+		assert.Contains(t, sql, "queue_number - referral_count * ?")
+		return mockDB
+	})
+	mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+		*dest.(*waiting_list.QueuePosition) = waiting_list.QueuePosition{Position: 98, Total: 300}
+		return nil
+	})
+
+	// Act
+	position, err := repository.GetPosition(ctx, entry, 5)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(98), position.Position)
+	assert.Equal(t, int64(300), position.Total)
+}
+
+func TestWaitingListRepository_NextQueueNumber(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), testWaitingListID).Return(mockDB)
+	mockDB.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest any) error {
+		*dest.(*int64) = 43
+		return nil
+	})
+
+	// Act
+	next, err := repository.NextQueueNumber(ctx, testWaitingListID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(43), next)
+}
+
+func TestWaitingListRepository_GetByInviteCode_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mock_ports.NewMockDatabaseInterface(ctrl)
+	repository := NewWaitingListRepository(mockDB)
+	ctx := context.Background()
+
+	mockDB.EXPECT().WithContext(ctx).Return(mockDB)
+	mockDB.EXPECT().Where("list_id = ? AND invite_code = ?", testWaitingListID, "ABCDEFGH23").Return(mockDB)
+	mockDB.EXPECT().First(gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	// Act
+	entry, err := repository.GetByInviteCode(ctx, testWaitingListID, "ABCDEFGH23")
+
+	// Assert
+	assert.Nil(t, entry)
+	assert.ErrorIs(t, err, waiting_list.ErrEntryNotFound)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAccountDeletionRoutes configures self-service account deletion routes
+func SetupAccountDeletionRoutes(api fiber.Router, accountDeletionHandler *handlers.AccountDeletionHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := api.Group("/me")
+
+	// Protected routes - require authentication
+	me.Delete("/", authMiddleware.RequireAuth, accountDeletionHandler.DeleteMe)
+	me.Get("/deletion", authMiddleware.RequireAuth, accountDeletionHandler.GetMyDeletion)
+	me.Delete("/deletion", authMiddleware.RequireAuth, accountDeletionHandler.CancelMyDeletion)
+}
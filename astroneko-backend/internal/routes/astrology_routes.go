@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAstrologyRoutes configures birth profile and natal chart routes
+func SetupAstrologyRoutes(api fiber.Router, astrologyHandler *handlers.AstrologyHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := api.Group("/me")
+
+	// Protected routes - require authentication
+	me.Put("/birth-profile", authMiddleware.RequireAuth, astrologyHandler.UpdateMyBirthProfile)
+	me.Get("/chart", authMiddleware.RequireAuth, astrologyHandler.GetMyChart)
+}
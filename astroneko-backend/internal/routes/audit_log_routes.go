@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAuditLogRoutes configures the read-only CRM audit log routes
+func SetupAuditLogRoutes(api fiber.Router, auditLogHandler *handlers.AuditLogHTTPHandler, crmAuthMiddleware *middleware.CRMAuthMiddleware) {
+	auditLog := api.Group("/crm/audit-log", crmAuthMiddleware.RequireAuth, crmAuthMiddleware.RequirePermission(crm_user.PermissionAuditLogRead))
+
+	auditLog.Get("/", auditLogHandler.ListAuditLog)
+	auditLog.Get("/verify", auditLogHandler.VerifyAuditLog)
+}
@@ -22,4 +22,5 @@ func SetupAuthRoutes(api fiber.Router, userHandler *handlers.UserHTTPHandler, au
 	// Referral code endpoints
 	auth.Get("/referral/codes", authMiddleware.RequireAuth, userHandler.GetUserReferralCodes)
 	auth.Post("/referral/activate", authMiddleware.RequireAuth, userHandler.ActivateReferral)
+	auth.Post("/referral/vanity", authMiddleware.RequireAuth, userHandler.ClaimVanityCode)
 }
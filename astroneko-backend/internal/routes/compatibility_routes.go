@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCompatibilityRoutes configures compatibility reading routes
+func SetupCompatibilityRoutes(api fiber.Router, compatibilityHandler *handlers.CompatibilityHTTPHandler, authMiddleware *middleware.AuthMiddleware, guestRateLimit *middleware.GuestRateLimitMiddleware) {
+	readings := api.Group("/readings")
+
+	// Readings call the agent, so they share the agent reply quota
+	readings.Post("/compatibility",
+		authMiddleware.RequireAuthWithReferralCheck,
+		guestRateLimit.GuestOrAuthRateLimit("/api/v1/agent/reply", 3),
+		middleware.SetupAgentReplyRateLimitMiddleware(),
+		compatibilityHandler.CreateCompatibilityReading,
+	)
+}
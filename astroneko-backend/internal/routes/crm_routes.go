@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/handlers"
 	"astroneko-backend/pkg/middleware"
 
@@ -12,14 +13,38 @@ func SetupCRMRoutes(api fiber.Router, crmUserHandler *handlers.CRMUserHTTPHandle
 	// CRM group
 	crm := api.Group("/crm")
 
+	// First admin bootstrap - guarded by the configured bootstrap token
+	crm.Post("/bootstrap", crmUserHandler.BootstrapAdmin)
+
 	// User management
-	crm.Post("/users", crmUserHandler.CreateCRMUser)
-	crm.Get("/users/total", crmAuthMiddleware.RequireAuth, userHandler.GetTotalUsers)
+	manageCRMUsers := crmAuthMiddleware.RequirePermission(crm_user.PermissionCRMUsersManage)
+	crm.Get("/users", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.ListCRMUsers)
+	crm.Post("/users", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.CreateCRMUser)
+	crm.Get("/users/total", crmAuthMiddleware.RequireAuth, crmAuthMiddleware.RequirePermission(crm_user.PermissionAppUsersRead), userHandler.GetTotalUsers)
+	crm.Get("/users/:id", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.GetCRMUser)
+	crm.Put("/users/:id/roles", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.AssignRoles)
+	crm.Post("/users/:id/disable", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.DisableCRMUser)
+	crm.Post("/users/:id/enable", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.EnableCRMUser)
+	crm.Post("/users/:id/password-reset", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.IssuePasswordReset)
+	crm.Post("/users/:id/2fa/reset", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.ResetTwoFactor)
+	crm.Post("/users/:id/unlock", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.UnlockCRMUser)
+	crm.Get("/roles", crmAuthMiddleware.RequireAuth, manageCRMUsers, crmUserHandler.ListRoles)
 
 	// Authentication
 	crmAuth := crm.Group("/auth")
 	crmAuth.Post("/login", crmUserHandler.CRMLogin)
+	crmAuth.Post("/login/2fa", crmUserHandler.VerifyTwoFactorLogin)
+	crmAuth.Post("/login/2fa/setup", crmUserHandler.SetupTwoFactorForLogin)
+	crmAuth.Post("/login/2fa/enable", crmUserHandler.EnableTwoFactorForLogin)
+	crmAuth.Post("/password-reset", crmUserHandler.ResetPassword)
+	crmAuth.Post("/refresh", crmUserHandler.RefreshCRMToken)
 
 	// Protected routes
 	crmAuth.Get("/me", crmAuthMiddleware.RequireAuth, crmUserHandler.GetCRMMe)
+	crmAuth.Put("/password", crmAuthMiddleware.RequireAuth, crmUserHandler.ChangeMyPassword)
+	crmAuth.Post("/logout", crmAuthMiddleware.RequireAuth, crmUserHandler.CRMLogout)
+	crmAuth.Post("/2fa/setup", crmAuthMiddleware.RequireAuth, crmUserHandler.SetupTwoFactor)
+	crmAuth.Post("/2fa/enable", crmAuthMiddleware.RequireAuth, crmUserHandler.EnableTwoFactor)
+	crmAuth.Post("/2fa/disable", crmAuthMiddleware.RequireAuth, crmUserHandler.DisableTwoFactor)
+	crmAuth.Post("/2fa/recovery-codes", crmAuthMiddleware.RequireAuth, crmUserHandler.RegenerateRecoveryCodes)
 }
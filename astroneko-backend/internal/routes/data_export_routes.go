@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupDataExportRoutes configures personal data export routes
+func SetupDataExportRoutes(api fiber.Router, dataExportHandler *handlers.DataExportHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	export := api.Group("/me/export")
+
+	// Protected routes - require authentication
+	export.Post("/", authMiddleware.RequireAuth, dataExportHandler.RequestMyExport)
+	export.Get("/", authMiddleware.RequireAuth, dataExportHandler.ListMyExports)
+	export.Get("/:id", authMiddleware.RequireAuth, dataExportHandler.GetMyExport)
+
+	// Public route - the signed link is the credential
+	export.Get("/:id/download", dataExportHandler.DownloadExport)
+}
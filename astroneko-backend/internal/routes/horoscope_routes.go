@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupHoroscopeRoutes configures public daily horoscope routes
+func SetupHoroscopeRoutes(api fiber.Router, horoscopeHandler *handlers.HoroscopeHTTPHandler) {
+	horoscope := api.Group("/horoscope")
+
+	// Public routes
+	horoscope.Get("/:sign", horoscopeHandler.GetDailyHoroscope)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupInsightRoutes configures personal reading insight routes
+func SetupInsightRoutes(api fiber.Router, insightHandler *handlers.InsightHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := api.Group("/me")
+
+	// Protected routes - require authentication
+	me.Get("/insights", authMiddleware.RequireAuth, insightHandler.GetMyInsights)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupNumerologyRoutes configures public numerology routes
+func SetupNumerologyRoutes(api fiber.Router, numerologyHandler *handlers.NumerologyHTTPHandler) {
+	numerology := api.Group("/numerology")
+
+	// Public routes
+	numerology.Post("/name", numerologyHandler.AnalyzeName)
+	numerology.Post("/life-path", numerologyHandler.AnalyzeLifePath)
+	numerology.Post("/phone", numerologyHandler.AnalyzePhone)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupPersonRoutes configures saved people routes
+func SetupPersonRoutes(api fiber.Router, personHandler *handlers.PersonHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	people := api.Group("/me/people")
+
+	// Protected routes - require authentication
+	people.Get("/", authMiddleware.RequireAuth, personHandler.ListMyPeople)
+	people.Post("/", authMiddleware.RequireAuth, personHandler.CreateMyPerson)
+	people.Put("/:id", authMiddleware.RequireAuth, personHandler.UpdateMyPerson)
+	people.Delete("/:id", authMiddleware.RequireAuth, personHandler.DeleteMyPerson)
+}
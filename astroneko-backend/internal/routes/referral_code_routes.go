@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/handlers"
 	"astroneko-backend/pkg/middleware"
 
@@ -15,14 +16,22 @@ func SetupReferralCodeRoutes(api fiber.Router, handler *handlers.ReferralCodeHTT
 	// Apply CRM authentication middleware to all routes
 	referralCodesGroup.Use(crmAuthMiddleware.RequireAuth)
 
+	canRead := crmAuthMiddleware.RequirePermission(crm_user.PermissionReferralCodesRead)
+	canWrite := crmAuthMiddleware.RequirePermission(crm_user.PermissionReferralCodesWrite)
+
+	// Bulk operations, registered before /:id so their paths are not read as IDs
+	referralCodesGroup.Post("/bulk", canWrite, handler.GenerateReferralCodes)
+	referralCodesGroup.Post("/import", canWrite, handler.ImportReferralCodes)
+	referralCodesGroup.Get("/export", canRead, handler.ExportReferralCodes)
+
 	// CRUD operations for general referral codes
-	referralCodesGroup.Post("/", handler.CreateReferralCode)
-	referralCodesGroup.Get("/", handler.ListReferralCodes)
-	referralCodesGroup.Get("/:id", handler.GetReferralCodeByID)
-	referralCodesGroup.Put("/:id", handler.UpdateReferralCode)
-	referralCodesGroup.Delete("/:id", handler.DeleteReferralCode)
+	referralCodesGroup.Post("/", canWrite, handler.CreateReferralCode)
+	referralCodesGroup.Get("/", canRead, handler.ListReferralCodes)
+	referralCodesGroup.Get("/:id", canRead, handler.GetReferralCodeByID)
+	referralCodesGroup.Put("/:id", canWrite, handler.UpdateReferralCode)
+	referralCodesGroup.Delete("/:id", canWrite, handler.DeleteReferralCode)
 
 	// Additional utility endpoints
-	referralCodesGroup.Get("/code/:code", handler.GetReferralCodeByCode)
-	referralCodesGroup.Get("/validate/:code", handler.ValidateReferralCode)
+	referralCodesGroup.Get("/code/:code", canRead, handler.GetReferralCodeByCode)
+	referralCodesGroup.Get("/validate/:code", canRead, handler.ValidateReferralCode)
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupReferralGraphRoutes configures the user's own referral list and the CRM referral leaderboard
+func SetupReferralGraphRoutes(api fiber.Router, referralGraphHandler *handlers.ReferralGraphHTTPHandler, authMiddleware *middleware.AuthMiddleware, crmAuthMiddleware *middleware.CRMAuthMiddleware) {
+	me := api.Group("/me")
+	me.Get("/referrals", authMiddleware.RequireAuth, referralGraphHandler.GetMyReferrals)
+
+	// The leaderboard shows inviter emails, so it also needs app user read access
+	referrals := api.Group("/crm/referrals", crmAuthMiddleware.RequireAuth,
+		crmAuthMiddleware.RequirePermission(crm_user.PermissionReferralCodesRead, crm_user.PermissionAppUsersRead))
+	referrals.Get("/leaderboard", referralGraphHandler.GetLeaderboard)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupReferralLinkRoutes configures the public deep link redirect, the user's QR codes and
+// click claims, and the CRM links and funnel
+func SetupReferralLinkRoutes(app *fiber.App, api fiber.Router, referralLinkHandler *handlers.ReferralLinkHTTPHandler, authMiddleware *middleware.AuthMiddleware, crmAuthMiddleware *middleware.CRMAuthMiddleware) {
+	// Short links are shared publicly, so they live outside /v1/api
+	app.Get("/r/:code", middleware.SetupReferralClickRateLimitMiddleware(), referralLinkHandler.FollowLink)
+
+	auth := api.Group("/auth")
+	auth.Get("/referral/codes/:code/qr", authMiddleware.RequireAuth, referralLinkHandler.GetMyCodeQR)
+	auth.Post("/referral/clicks/claim", authMiddleware.RequireAuth, referralLinkHandler.ClaimClick)
+
+	referralLinks := api.Group("/referral-links", crmAuthMiddleware.RequireAuth,
+		crmAuthMiddleware.RequirePermission(crm_user.PermissionReferralCodesRead))
+	referralLinks.Get("/funnel", referralLinkHandler.GetFunnel)
+	referralLinks.Get("/general/:id", referralLinkHandler.GetGeneralCodeLink)
+	referralLinks.Get("/general/:id/qr", referralLinkHandler.GetGeneralCodeQR)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupReferralRewardRoutes configures the user's referral rewards route
+func SetupReferralRewardRoutes(api fiber.Router, referralRewardHandler *handlers.ReferralRewardHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := api.Group("/me")
+
+	// Protected routes - require authentication
+	me.Get("/rewards", authMiddleware.RequireAuth, referralRewardHandler.GetMyRewards)
+}
@@ -1,16 +1,25 @@
 package routes
 
 import (
+	"context"
 	"log"
 
+	"astroneko-backend/configs"
 	"astroneko-backend/internal/adapters"
+	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/waiting_list"
 	"astroneko-backend/internal/handlers"
 	"astroneko-backend/internal/repositories"
 	"astroneko-backend/internal/services"
 	"astroneko-backend/pkg/databases/gorm"
 	"astroneko-backend/pkg/firebase"
 	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/mailer"
 	"astroneko-backend/pkg/middleware"
+	"astroneko-backend/pkg/utils"
 	"astroneko-backend/pkg/validator"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
@@ -70,44 +79,177 @@ func setupApplicationRoutes(app *fiber.App, api fiber.Router, db *gorm.DB, healt
 	userRepo := repositories.NewUserRepository(dbAdapter)
 	referralCodeRepo := repositories.NewReferralCodeRepository(dbAdapter)
 	firebaseAdapter := firebase.NewFirebaseClientAdapter(firebaseClient)
-	userService := services.NewUserService(userRepo, firebaseAdapter, "", appLogger, referralCodeRepo)
-	referralCodeService := services.NewReferralCodeService(referralCodeRepo, userRepo, appLogger)
+	credentialAttemptRepo := repositories.NewCredentialAttemptRepository(dbAdapter)
+	credentialAttemptService := services.NewCredentialAttemptService(credentialAttemptRepo, appLogger)
+	userService := services.NewUserService(userRepo, credentialAttemptService, firebaseAdapter, "", appLogger, referralCodeRepo)
+	referralRewardPolicy := referral_reward.DefaultPolicy
+	if rewardsConfig := configs.GetViper().Referral.Rewards; rewardsConfig != nil {
+		referralRewardPolicy = referral_reward.Policy{
+			BonusReplies:    rewardsConfig.BonusReplies,
+			DailyReplies:    rewardsConfig.DailyReplies,
+			MaxDailyReplies: rewardsConfig.MaxDailyReplies,
+		}
+		for _, badge := range rewardsConfig.Badges {
+			referralRewardPolicy.Badges = append(referralRewardPolicy.Badges, referral_reward.BadgeRule{Badge: badge.Badge, Invites: badge.Invites})
+		}
+	}
+	if err := referralRewardPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid referral.rewards configuration: %v", err)
+	}
+	userCodeAllotment := referral_code.DefaultAllotmentPolicy
+	if userCodesConfig := configs.GetViper().Referral.UserCodes; userCodesConfig != nil {
+		userCodeAllotment = referral_code.AllotmentPolicy{
+			CodesPerTier: userCodesConfig.CodesPerTier,
+			DefaultCodes: userCodesConfig.DefaultCodes,
+			TopUpAfter:   userCodesConfig.TopUpAfter,
+			ExpiresIn:    userCodesConfig.ExpiresIn,
+			Length:       userCodesConfig.Length,
+		}
+	}
+	if err := userCodeAllotment.Validate(); err != nil {
+		log.Fatalf("Invalid referral.user_codes configuration: %v", err)
+	}
+	vanityPolicy := referral_code.DefaultVanityPolicy
+	if vanityConfig := configs.GetViper().Referral.Vanity; vanityConfig != nil {
+		vanityPolicy = referral_code.VanityPolicy{
+			MinInvites:        vanityConfig.MinInvites,
+			MinLength:         vanityConfig.MinLength,
+			MaxLength:         vanityConfig.MaxLength,
+			ExtraBlockedWords: vanityConfig.BlockedWords,
+		}
+	}
+	if err := vanityPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid referral.vanity configuration: %v", err)
+	}
+	referralCodeService := services.NewReferralCodeService(referralCodeRepo, userRepo, credentialAttemptService, referralRewardPolicy, userCodeAllotment, vanityPolicy, appLogger)
+	var referralLinkPolicy referral_link.LinkPolicy
+	if linksConfig := configs.GetViper().Referral.Links; linksConfig != nil {
+		referralLinkPolicy = referral_link.LinkPolicy{BaseURL: linksConfig.BaseURL, LandingURL: linksConfig.LandingURL}
+	}
+	if err := referralLinkPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid referral.links configuration: %v", err)
+	}
+	if !referralLinkPolicy.Enabled() {
+		log.Printf("Warning: referral.links not set, referral deep links and QR codes are disabled")
+	}
+	referralLinkRepo := repositories.NewReferralLinkRepository(dbAdapter)
+	referralLinkService := services.NewReferralLinkService(referralLinkRepo, referralCodeRepo, referralLinkPolicy, appLogger)
 	userValidator := validator.New()
-	userHandler := handlers.NewUserHTTPHandler(userService, referralCodeService, userValidator)
+	userHandler := handlers.NewUserHTTPHandler(userService, referralCodeService, referralLinkService, userValidator)
+	referralLinkHandler := handlers.NewReferralLinkHTTPHandler(referralLinkService, validator.New())
+
+	// Audit log dependencies
+	auditRepo := repositories.NewAuditRepository(dbAdapter)
+	auditService := services.NewAuditService(auditRepo, appLogger)
+	auditLogHandler := handlers.NewAuditLogHTTPHandler(auditService)
+
+	// Mail dependencies
+	var appMailer mailer.Mailer
+	if mailConfig := configs.GetViper().Mail; mailConfig.Host != "" {
+		smtpMailer, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     mailConfig.Host,
+			Port:     mailConfig.Port,
+			Username: mailConfig.Username,
+			Password: mailConfig.Password,
+			From:     mailConfig.From,
+		})
+		if err != nil {
+			log.Fatalf("Invalid mail configuration: %v", err)
+		}
+		appMailer = smtpMailer
+	} else {
+		log.Printf("Warning: mail.host not set, emails are logged instead of sent")
+		appMailer = mailer.NewLogMailer(appLogger)
+	}
 
 	// Waiting list dependencies
+	waitingListConfig := configs.GetViper().WaitingList
+	waitingListPolicy := waiting_list.Policy{
+		StatusTokenKey:    []byte(waitingListConfig.StatusTokenKey),
+		StatusTokenTTL:    waitingListConfig.StatusTokenTTL,
+		DisposableDomains: waitingListConfig.DisposableDomains,
+	}
+	if waitingListPolicy.StatusTokenTTL == 0 {
+		waitingListPolicy.StatusTokenTTL = waiting_list.DefaultStatusTokenTTL
+	}
+	if len(waitingListPolicy.StatusTokenKey) == 0 {
+		log.Printf("Warning: waiting_list.status_token_key not set, waiting list status links only work on this instance until restart")
+		randomKey, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			log.Printf("Warning: failed to generate waiting list status token key: %v", err)
+		}
+		waitingListPolicy.StatusTokenKey = []byte(randomKey)
+	}
+	if err := waitingListPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid waiting_list configuration: %v", err)
+	}
 	waitingListRepo := repositories.NewWaitingListRepository(dbAdapter)
-	waitingListService := services.NewWaitingListService(waitingListRepo, appLogger)
+	waitingListService := services.NewWaitingListService(waitingListRepo, waitingListPolicy, appMailer, appLogger)
 	waitingListValidator := validator.New()
-	waitingListHandler := handlers.NewWaitingListHTTPHandler(waitingListService, waitingListValidator)
+	waitingListHandler := handlers.NewWaitingListHTTPHandler(waitingListService, auditService, waitingListValidator)
+
+	// Astrology dependencies
+	astrologyService := services.NewAstrologyService(userRepo, appLogger)
+	astrologyValidator := validator.New()
+	astrologyHandler := handlers.NewAstrologyHTTPHandler(astrologyService, astrologyValidator)
+
+	// Numerology dependencies
+	numerologyService := services.NewNumerologyService()
+	numerologyValidator := validator.New()
+	numerologyHandler := handlers.NewNumerologyHTTPHandler(numerologyService, numerologyValidator)
 
 	// Agent dependencies
 	agentRepo := repositories.NewAgentRepository()
 	agentService := services.NewAgentService(agentRepo, appLogger)
 	agentValidator := validator.New()
-	agentHandler := handlers.NewAgentHTTPHandler(agentService, agentValidator)
+	agentHandler := handlers.NewAgentHTTPHandler(agentService, astrologyService, numerologyService, agentValidator)
+
+	// Horoscope dependencies
+	horoscopeRepo := repositories.NewHoroscopeRepository(dbAdapter)
+	horoscopeService := services.NewHoroscopeService(horoscopeRepo, agentRepo, appLogger)
+	horoscopeHandler := handlers.NewHoroscopeHTTPHandler(horoscopeService)
+
+	// Thai astrology dependencies
+	thaiAstrologyService := services.NewThaiAstrologyService()
+	thaiAstrologyHandler := handlers.NewThaiAstrologyHTTPHandler(thaiAstrologyService)
+
+	// Generate daily horoscopes in the background
+	go horoscopeService.RunDailySchedule(context.Background())
 
 	// Referral code dependencies
 	referralCodeValidator := validator.New()
-	referralCodeHandler := handlers.NewReferralCodeHTTPHandler(referralCodeService, referralCodeValidator)
+	referralCodeHandler := handlers.NewReferralCodeHTTPHandler(referralCodeService, auditService, referralCodeValidator)
 
 	// CRM user dependencies
 	crmUserRepo := repositories.NewCRMUserRepository(dbAdapter)
-	crmUserService := services.NewCRMUserService(crmUserRepo, appLogger, "your-jwt-secret-key") // Use environment variable in production
+	crmConfig := configs.GetViper().CRM
+	crmActiveKeyID := crmConfig.JWT.ActiveKeyID
+	crmSigningKeys := make([]crm_user.SigningKey, 0, len(crmConfig.JWT.Keys))
+	for _, key := range crmConfig.JWT.Keys {
+		crmSigningKeys = append(crmSigningKeys, crm_user.SigningKey{ID: key.ID, Secret: key.Secret})
+	}
+	if len(crmSigningKeys) == 0 {
+		log.Printf("Warning: crm.jwt.keys not set, CRM access tokens only work on this instance until restart")
+		randomKey, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			log.Printf("Warning: failed to generate CRM signing key: %v", err)
+		}
+		crmActiveKeyID = "ephemeral"
+		crmSigningKeys = append(crmSigningKeys, crm_user.SigningKey{ID: crmActiveKeyID, Secret: randomKey})
+	}
+	crmKeyRing, err := crm_user.NewKeyRing(crmActiveKeyID, crmSigningKeys)
+	if err != nil {
+		log.Fatalf("Invalid crm.jwt configuration: %v", err)
+	}
+	crmUserService := services.NewCRMUserService(crmUserRepo, credentialAttemptService, appLogger, crmKeyRing, crmConfig.BootstrapToken, crmConfig.RequireTwoFactor)
 	crmUserValidator := validator.New()
-	crmUserHandler := handlers.NewCRMUserHTTPHandler(crmUserService, crmUserValidator)
+	crmUserHandler := handlers.NewCRMUserHTTPHandler(crmUserService, auditService, crmUserValidator)
 
 	// User limit dependencies
 	userLimitRepo := repositories.NewUserLimitRepository(dbAdapter)
 	userLimitService := services.NewUserLimitService(userLimitRepo, userRepo, appLogger)
 	userLimitValidator := validator.New()
-	userLimitHandler := handlers.NewUserLimitHTTPHandler(userLimitService, userLimitValidator)
-
-	// Astro boxing waiting list dependencies
-	astroBoxingWaitingListRepo := repositories.NewAstroBoxingWaitingListRepository(dbAdapter)
-	astroBoxingWaitingListService := services.NewAstroBoxingWaitingListService(astroBoxingWaitingListRepo, appLogger)
-	astroBoxingWaitingListValidator := validator.New()
-	astroBoxingWaitingListHandler := handlers.NewAstroBoxingWaitingListHTTPHandler(astroBoxingWaitingListService, astroBoxingWaitingListValidator)
+	userLimitHandler := handlers.NewUserLimitHTTPHandler(userLimitService, auditService, userLimitValidator)
 
 	// Guest usage tracking dependencies
 	guestUsageRepo := repositories.NewGuestUsageRepository(dbAdapter, appLogger)
@@ -117,20 +259,92 @@ func setupApplicationRoutes(app *fiber.App, api fiber.Router, db *gorm.DB, healt
 	historyService := services.NewHistoryService(historyRepo, appLogger)
 	historyHandler := handlers.NewHistoryHTTPHandler(historyService)
 
+	// Insight dependencies
+	insightService := services.NewInsightService(historyRepo, appLogger)
+	insightHandler := handlers.NewInsightHTTPHandler(insightService)
+
+	// Referral graph dependencies
+	referralGraphRepo := repositories.NewReferralGraphRepository(dbAdapter)
+	referralGraphService := services.NewReferralGraphService(referralGraphRepo, appLogger)
+	referralGraphHandler := handlers.NewReferralGraphHTTPHandler(referralGraphService)
+
+	// Referral reward dependencies
+	referralRewardRepo := repositories.NewReferralRewardRepository(dbAdapter)
+	referralRewardService := services.NewReferralRewardService(referralRewardRepo, referralRewardPolicy, appLogger)
+	referralRewardHandler := handlers.NewReferralRewardHTTPHandler(referralRewardService)
+
+	// Saved people and compatibility dependencies
+	personRepo := repositories.NewPersonRepository(dbAdapter)
+	personService := services.NewPersonService(personRepo, appLogger)
+	personValidator := validator.New()
+	personHandler := handlers.NewPersonHTTPHandler(personService, personValidator)
+	compatibilityService := services.NewCompatibilityService(personRepo, historyRepo, agentRepo, astrologyService, appLogger)
+	compatibilityValidator := validator.New()
+	compatibilityHandler := handlers.NewCompatibilityHTTPHandler(compatibilityService, compatibilityValidator)
+
+	// Account deletion dependencies
+	var accountDeletionFirebase firebase.FirebaseClientInterface
+	if firebaseClient != nil {
+		accountDeletionFirebase = firebaseAdapter
+	}
+	accountDeletionRepo := repositories.NewAccountDeletionRepository(dbAdapter)
+	accountDeletionService := services.NewAccountDeletionService(accountDeletionRepo, accountDeletionFirebase, appLogger)
+	accountDeletionValidator := validator.New()
+	accountDeletionHandler := handlers.NewAccountDeletionHTTPHandler(accountDeletionService, accountDeletionValidator)
+
+	// Purge accounts whose deletion grace period ended in the background
+	go accountDeletionService.RunPurgeSchedule(context.Background())
+
+	// Data export dependencies
+	dataExportSigningKey := []byte(configs.GetViper().DataExport.SigningKey)
+	if len(dataExportSigningKey) == 0 {
+		log.Printf("Warning: data_export.signing_key not set, download links only work on this instance until restart")
+		randomKey, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			log.Printf("Warning: failed to generate data export signing key: %v", err)
+		}
+		dataExportSigningKey = []byte(randomKey)
+	}
+	dataExportRepo := repositories.NewDataExportRepository(dbAdapter)
+	dataExportService := services.NewDataExportService(dataExportRepo, userRepo, historyRepo, personRepo, referralCodeRepo, referralRewardRepo, referralLinkRepo, guestUsageRepo, dataExportSigningKey, appLogger)
+	dataExportHandler := handlers.NewDataExportHTTPHandler(dataExportService)
+
+	// Build queued data exports in the background
+	go dataExportService.RunExportWorker(context.Background())
+
+	// CRM app user management dependencies
+	userAdminService := services.NewUserAdminService(userRepo, userService, accountDeletionService, credentialAttemptService, auditService, appLogger)
+	userAdminValidator := validator.New()
+	userAdminHandler := handlers.NewUserAdminHTTPHandler(userAdminService, userAdminValidator)
+
 	// Initialize middleware
 	authMiddleware := middleware.NewFirebaseAuthMiddleware(firebaseClient, userService, appLogger)
 	crmAuthMiddleware := middleware.NewCRMAuthMiddleware(crmUserService, appLogger)
-	guestRateLimitMiddleware := middleware.NewGuestRateLimitMiddleware(guestUsageRepo, appLogger)
+	guestRateLimitMiddleware := middleware.NewGuestRateLimitMiddleware(guestUsageRepo, userRepo, appLogger)
+	authorizer := middleware.NewAuthorizer(appLogger)
 
 	// Setup all route modules
 	SetupHealthRoutes(app, api, healthHandler, authMiddleware)
-	SetupUserRoutes(api, userHandler, authMiddleware)
+	SetupUserRoutes(api, userHandler, authMiddleware, authorizer)
 	SetupAuthRoutes(api, userHandler, authMiddleware)
-	SetupWaitingListRoutes(api, waitingListHandler)
+	SetupWaitingListRoutes(api, waitingListHandler, crmAuthMiddleware)
 	SetupAgentRoutes(api, agentHandler, authMiddleware, guestRateLimitMiddleware)
 	SetupCRMRoutes(api, crmUserHandler, userHandler, crmAuthMiddleware)
 	SetupReferralCodeRoutes(api, referralCodeHandler, crmAuthMiddleware)
 	SetupUserLimitRoutes(api, userLimitHandler, crmAuthMiddleware, authMiddleware)
-	SetupAstroBoxingWaitingListRoutes(api, astroBoxingWaitingListHandler)
 	SetupHistoryRoutes(api, historyHandler, authMiddleware)
+	SetupInsightRoutes(api, insightHandler, authMiddleware)
+	SetupAstrologyRoutes(api, astrologyHandler, authMiddleware)
+	SetupHoroscopeRoutes(api, horoscopeHandler)
+	SetupThaiAstrologyRoutes(api, thaiAstrologyHandler, authMiddleware)
+	SetupNumerologyRoutes(api, numerologyHandler)
+	SetupPersonRoutes(api, personHandler, authMiddleware)
+	SetupCompatibilityRoutes(api, compatibilityHandler, authMiddleware, guestRateLimitMiddleware)
+	SetupAccountDeletionRoutes(api, accountDeletionHandler, authMiddleware)
+	SetupDataExportRoutes(api, dataExportHandler, authMiddleware)
+	SetupUserAdminRoutes(api, userAdminHandler, crmAuthMiddleware)
+	SetupAuditLogRoutes(api, auditLogHandler, crmAuthMiddleware)
+	SetupReferralGraphRoutes(api, referralGraphHandler, authMiddleware, crmAuthMiddleware)
+	SetupReferralRewardRoutes(api, referralRewardHandler, authMiddleware)
+	SetupReferralLinkRoutes(app, api, referralLinkHandler, authMiddleware, crmAuthMiddleware)
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupThaiAstrologyRoutes configures Thai lunar calendar, day colour and auspicious day routes
+func SetupThaiAstrologyRoutes(api fiber.Router, thaiAstrologyHandler *handlers.ThaiAstrologyHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
+	thaiAstrology := api.Group("/thai-astrology")
+
+	// Public routes
+	thaiAstrology.Get("/lunar-date", thaiAstrologyHandler.GetLunarDate)
+	thaiAstrology.Get("/day-colors", thaiAstrologyHandler.GetDayColors)
+	thaiAstrology.Get("/wan-phra", thaiAstrologyHandler.ListWanPhra)
+
+	// Protected routes - require authentication
+	me := api.Group("/me")
+	me.Get("/auspicious-days", authMiddleware.RequireAuth, thaiAstrologyHandler.GetMyAuspiciousDays)
+}
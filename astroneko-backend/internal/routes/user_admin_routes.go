@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupUserAdminRoutes configures CRM management routes for app users
+func SetupUserAdminRoutes(api fiber.Router, userAdminHandler *handlers.UserAdminHTTPHandler, crmAuthMiddleware *middleware.CRMAuthMiddleware) {
+	appUsers := api.Group("/crm/app-users", crmAuthMiddleware.RequireAuth)
+	canRead := crmAuthMiddleware.RequirePermission(crm_user.PermissionAppUsersRead)
+	canWrite := crmAuthMiddleware.RequirePermission(crm_user.PermissionAppUsersWrite)
+
+	appUsers.Get("/", canRead, userAdminHandler.ListUsers)
+	appUsers.Post("/", canWrite, userAdminHandler.CreateUser)
+	appUsers.Get("/:id", canRead, userAdminHandler.GetUser)
+	appUsers.Put("/:id", canWrite, userAdminHandler.UpdateUser)
+	appUsers.Delete("/:id", canWrite, userAdminHandler.DeleteUser)
+	appUsers.Post("/:id/unlock", canWrite, userAdminHandler.UnlockLogin)
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/handlers"
 	"astroneko-backend/pkg/middleware"
 
@@ -16,5 +17,5 @@ func SetupUserLimitRoutes(api fiber.Router, userLimitHandler *handlers.UserLimit
 
 	// CRM protected route for updating user limit
 	crm := api.Group("/crm")
-	crm.Put("/user-limit", crmAuthMiddleware.RequireAuth, userLimitHandler.UpdateUserLimit)
+	crm.Put("/user-limit", crmAuthMiddleware.RequireAuth, crmAuthMiddleware.RequirePermission(crm_user.PermissionUserLimitWrite), userLimitHandler.UpdateUserLimit)
 }
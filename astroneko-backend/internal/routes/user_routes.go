@@ -7,11 +7,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupUserRoutes(api fiber.Router, userHandler *handlers.UserHTTPHandler, authMiddleware *middleware.AuthMiddleware) {
-	// User routes
+func SetupUserRoutes(api fiber.Router, userHandler *handlers.UserHTTPHandler, authMiddleware *middleware.AuthMiddleware, authorizer *middleware.Authorizer) {
+	// User routes - users may only read and update their own record
 	users := api.Group("/users")
-	users.Post("/", userHandler.CreateUser)
-	users.Get("/:id", userHandler.GetUserByID)
-	users.Put("/:id", userHandler.UpdateUser)
-	users.Delete("/:id", userHandler.DeleteUser)
+	users.Get("/:id", authMiddleware.RequireAuth, authorizer.RequireSelf("id"), userHandler.GetUserByID)
+	users.Put("/:id", authMiddleware.RequireAuth, authorizer.RequireSelf("id"), userHandler.UpdateUser)
 }
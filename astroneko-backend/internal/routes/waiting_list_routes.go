@@ -1,14 +1,37 @@
 package routes
 
 import (
+	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/handlers"
+	"astroneko-backend/pkg/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupWaitingListRoutes(api fiber.Router, waitingListHandler *handlers.WaitingListHTTPHandler) {
+// SetupWaitingListRoutes configures the public waiting list routes, the original per-product
+// routes kept as aliases of their lists, and the CRM list management
+func SetupWaitingListRoutes(api fiber.Router, waitingListHandler *handlers.WaitingListHTTPHandler, crmAuthMiddleware *middleware.CRMAuthMiddleware) {
+	waitingLists := api.Group("/waiting-lists")
+	waitingLists.Get("/:slug", waitingListHandler.GetListInfo)
+	waitingLists.Post("/:slug/join", waitingListHandler.JoinList)
+	waitingLists.Get("/:slug/check", waitingListHandler.CheckList)
+
 	// Waiting list routes
 	waitingList := api.Group("/waiting-list")
 	waitingList.Post("/join", waitingListHandler.JoinWaitingList)
 	waitingList.Post("/check", waitingListHandler.IsInWaitingListByEmail)
+	waitingList.Get("/status", waitingListHandler.GetStatus)
+
+	astroBoxingWaitingList := api.Group("/astro-boxing-waiting-list")
+	astroBoxingWaitingList.Post("/join", waitingListHandler.JoinAstroBoxingWaitingList)
+	astroBoxingWaitingList.Get("/check", waitingListHandler.IsInAstroBoxingWaitingListByEmail)
+
+	crmWaitingLists := api.Group("/crm/waiting-lists", crmAuthMiddleware.RequireAuth)
+	canRead := crmAuthMiddleware.RequirePermission(crm_user.PermissionWaitingListsRead)
+	canWrite := crmAuthMiddleware.RequirePermission(crm_user.PermissionWaitingListsWrite)
+	crmWaitingLists.Get("/", canRead, waitingListHandler.ListLists)
+	crmWaitingLists.Post("/", canWrite, waitingListHandler.CreateList)
+	crmWaitingLists.Put("/:slug", canWrite, waitingListHandler.UpdateList)
+	crmWaitingLists.Get("/:slug/entries", canRead, waitingListHandler.GetListEntries)
+	crmWaitingLists.Delete("/:slug/entries/:id", canWrite, waitingListHandler.DeleteListEntry)
 }
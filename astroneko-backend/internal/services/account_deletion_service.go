@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	accountDeletionPorts "astroneko-backend/internal/core/ports/account_deletion"
+	"astroneko-backend/pkg/firebase"
+	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/utils"
+
+	"firebase.google.com/go/v4/auth"
+)
+
+const (
+	// accountDeletionInterval is how often the background purge looks for due deletions
+	accountDeletionInterval = time.Hour
+	// accountDeletionBatchSize caps the deletions processed per run
+	accountDeletionBatchSize = 50
+)
+
+// AccountDeletionService handles self-service account deletion: a confirmation step,
+// a grace period during which the user can cancel, and a background purge of all owned
+// data followed by the Firebase account
+type AccountDeletionService struct {
+	deletionRepo accountDeletionPorts.RepositoryInterface
+	firebase     firebase.FirebaseClientInterface
+	logger       logger.Logger
+	now          func() time.Time
+}
+
+// NewAccountDeletionService creates a new account deletion service instance.
+// firebaseClient may be nil when Firebase is not configured; purged accounts then
+// wait until it is.
+func NewAccountDeletionService(deletionRepo accountDeletionPorts.RepositoryInterface, firebaseClient firebase.FirebaseClientInterface, log logger.Logger) *AccountDeletionService {
+	return &AccountDeletionService{
+		deletionRepo: deletionRepo,
+		firebase:     firebaseClient,
+		logger:       log,
+		now:          time.Now,
+	}
+}
+
+// RequestDeletion issues a confirmation token when called without one, and schedules
+// the deletion after account_deletion.GracePeriod when the token is sent back
+func (s *AccountDeletionService) RequestDeletion(ctx context.Context, u *user.User, req *account_deletion.DeleteAccountRequest) (*account_deletion.DeletionStatusResponse, error) {
+	active, err := s.deletionRepo.GetActiveByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeating a confirmed request is harmless
+	if active != nil && active.Status == account_deletion.StatusScheduled {
+		return active.ToStatusResponse(), nil
+	}
+
+	if req.ConfirmationToken == "" {
+		return s.issueConfirmation(ctx, u, active)
+	}
+	return s.confirm(ctx, active, req.ConfirmationToken)
+}
+
+// issueConfirmation starts a deletion request, or replaces the token of one awaiting confirmation
+func (s *AccountDeletionService) issueConfirmation(ctx context.Context, u *user.User, active *account_deletion.DeletionRequest) (*account_deletion.DeletionStatusResponse, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	tokenHash := utils.HashString(token)
+	expiresAt := s.now().Add(account_deletion.ConfirmationTTL)
+
+	request := active
+	if request == nil {
+		firebaseUID := u.FirebaseUID
+		request = &account_deletion.DeletionRequest{
+			UserID:      u.ID,
+			FirebaseUID: &firebaseUID,
+			Status:      account_deletion.StatusAwaitingConfirmation,
+		}
+	}
+	request.ConfirmationTokenHash = &tokenHash
+	request.ConfirmationExpiresAt = &expiresAt
+
+	if active == nil {
+		err = s.deletionRepo.Create(ctx, request)
+	} else {
+		err = s.deletionRepo.Update(ctx, request)
+	}
+	if err != nil {
+		s.logger.Error("Failed to save account deletion request",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	s.audit(ctx, request, account_deletion.EventRequested, "")
+
+	response := request.ToStatusResponse()
+	response.ConfirmationToken = token
+	return response, nil
+}
+
+// confirm checks the confirmation token and schedules the deletion
+func (s *AccountDeletionService) confirm(ctx context.Context, active *account_deletion.DeletionRequest, token string) (*account_deletion.DeletionStatusResponse, error) {
+	if active == nil || active.ConfirmationTokenHash == nil || active.ConfirmationExpiresAt == nil {
+		return nil, shared.ErrInvalidDeletionConfirmation
+	}
+
+	now := s.now()
+	tokenHash := utils.HashString(token)
+	if now.After(*active.ConfirmationExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(tokenHash), []byte(*active.ConfirmationTokenHash)) != 1 {
+		return nil, shared.ErrInvalidDeletionConfirmation
+	}
+
+	scheduledFor := now.Add(account_deletion.GracePeriod)
+	active.Status = account_deletion.StatusScheduled
+	active.ConfirmedAt = &now
+	active.ScheduledFor = &scheduledFor
+	active.ConfirmationTokenHash = nil
+	active.ConfirmationExpiresAt = nil
+
+	if err := s.deletionRepo.Update(ctx, active); err != nil {
+		s.logger.Error("Failed to schedule account deletion",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: active.UserID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	s.audit(ctx, active, account_deletion.EventConfirmed, "")
+
+	s.logger.Info("Account deletion scheduled",
+		logger.Field{Key: "module", Value: "account_deletion_service"},
+		logger.Field{Key: "user_id", Value: active.UserID.String()},
+		logger.Field{Key: "scheduled_for", Value: scheduledFor.Format(time.RFC3339)})
+
+	return active.ToStatusResponse(), nil
+}
+
+// ScheduleImmediateDeletion schedules the user's deletion for the next purge run without a
+// confirmation or grace period. It is used by administrators; detail names who asked for it.
+func (s *AccountDeletionService) ScheduleImmediateDeletion(ctx context.Context, u *user.User, detail string) (*account_deletion.DeletionStatusResponse, error) {
+	active, err := s.deletionRepo.GetActiveByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	request := active
+	if request == nil {
+		firebaseUID := u.FirebaseUID
+		request = &account_deletion.DeletionRequest{
+			UserID:      u.ID,
+			FirebaseUID: &firebaseUID,
+		}
+	}
+	request.Status = account_deletion.StatusScheduled
+	request.ConfirmedAt = &now
+	request.ScheduledFor = &now
+	request.ConfirmationTokenHash = nil
+	request.ConfirmationExpiresAt = nil
+
+	if active == nil {
+		err = s.deletionRepo.Create(ctx, request)
+	} else {
+		err = s.deletionRepo.Update(ctx, request)
+	}
+	if err != nil {
+		s.logger.Error("Failed to schedule account deletion",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	s.audit(ctx, request, account_deletion.EventAdminScheduled, detail)
+
+	return request.ToStatusResponse(), nil
+}
+
+// GetDeletionStatus returns the user's pending deletion
+func (s *AccountDeletionService) GetDeletionStatus(ctx context.Context, u *user.User) (*account_deletion.DeletionStatusResponse, error) {
+	active, err := s.deletionRepo.GetActiveByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, shared.ErrDeletionRequestNotFound
+	}
+
+	return active.ToStatusResponse(), nil
+}
+
+// CancelDeletion cancels a deletion that is awaiting confirmation or still in its grace period
+func (s *AccountDeletionService) CancelDeletion(ctx context.Context, u *user.User) error {
+	active, err := s.deletionRepo.GetActiveByUserID(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if active == nil {
+		return shared.ErrDeletionRequestNotFound
+	}
+
+	now := s.now()
+	active.Status = account_deletion.StatusCancelled
+	active.CancelledAt = &now
+	active.ConfirmationTokenHash = nil
+	active.FirebaseUID = nil
+
+	if err := s.deletionRepo.Update(ctx, active); err != nil {
+		return err
+	}
+	s.audit(ctx, active, account_deletion.EventCancelled, "")
+
+	return nil
+}
+
+// ProcessDueDeletions purges every account whose grace period has ended, then deletes
+// the Firebase accounts of purged users, including ones that failed on an earlier run
+func (s *AccountDeletionService) ProcessDueDeletions(ctx context.Context) error {
+	due, err := s.deletionRepo.ListDue(ctx, s.now(), accountDeletionBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var failures int
+	for i := range due {
+		if err := s.purge(ctx, &due[i]); err != nil {
+			failures++
+		}
+	}
+
+	awaitingFirebase, err := s.deletionRepo.ListAwaitingFirebase(ctx, accountDeletionBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range awaitingFirebase {
+		if err := s.deleteFirebaseUser(ctx, &awaitingFirebase[i]); err != nil {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return errors.New("some account deletions failed and will be retried")
+	}
+	return nil
+}
+
+// RunPurgeSchedule processes due deletions now and then every hour until the context is cancelled
+func (s *AccountDeletionService) RunPurgeSchedule(ctx context.Context) {
+	ticker := time.NewTicker(accountDeletionInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ProcessDueDeletions(ctx); err != nil {
+			s.logger.Error("Scheduled account deletion incomplete",
+				logger.Field{Key: "module", Value: "account_deletion_service"},
+				logger.Field{Key: "error", Value: err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes the user's data; the repository records the audit event in the same transaction
+func (s *AccountDeletionService) purge(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	summary, err := s.deletionRepo.PurgeUserData(ctx, request)
+	if err != nil {
+		s.recordFailure(ctx, request, account_deletion.EventPurgeFailed, err)
+		return err
+	}
+
+	s.logger.Info("Account data purged",
+		logger.Field{Key: "module", Value: "account_deletion_service"},
+		logger.Field{Key: "user_id", Value: request.UserID.String()},
+		logger.Field{Key: "sessions", Value: summary.Sessions},
+		logger.Field{Key: "messages", Value: summary.Messages})
+
+	return nil
+}
+
+// deleteFirebaseUser removes the Firebase account and completes the deletion.
+// An account that no longer exists in Firebase counts as deleted.
+func (s *AccountDeletionService) deleteFirebaseUser(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	if request.FirebaseUID != nil {
+		if s.firebase == nil {
+			s.recordFailure(ctx, request, account_deletion.EventFirebaseFailed, shared.ErrFirebaseNotInitialized)
+			return shared.ErrFirebaseNotInitialized
+		}
+
+		if err := s.firebase.DeleteUser(ctx, *request.FirebaseUID); err != nil && !auth.IsUserNotFound(err) {
+			s.recordFailure(ctx, request, account_deletion.EventFirebaseFailed, err)
+			return err
+		}
+	}
+
+	now := s.now()
+	request.Status = account_deletion.StatusCompleted
+	request.CompletedAt = &now
+	request.FirebaseUID = nil
+	request.LastError = nil
+
+	if err := s.deletionRepo.Update(ctx, request); err != nil {
+		s.logger.Error("Failed to complete account deletion",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: request.UserID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return err
+	}
+	s.audit(ctx, request, account_deletion.EventFirebaseDeleted, "")
+
+	return nil
+}
+
+// recordFailure counts a failed attempt so the next run retries it
+func (s *AccountDeletionService) recordFailure(ctx context.Context, request *account_deletion.DeletionRequest, event string, cause error) {
+	s.logger.Error("Account deletion step failed",
+		logger.Field{Key: "module", Value: "account_deletion_service"},
+		logger.Field{Key: "user_id", Value: request.UserID.String()},
+		logger.Field{Key: "event", Value: event},
+		logger.Field{Key: "error", Value: cause.Error()})
+
+	message := cause.Error()
+	request.Attempts++
+	request.LastError = &message
+	if err := s.deletionRepo.Update(ctx, request); err != nil {
+		s.logger.Error("Failed to record account deletion failure",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: request.UserID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+
+	detail, _ := json.Marshal(map[string]string{"error": message})
+	s.audit(ctx, request, event, string(detail))
+}
+
+// audit appends an audit event. A failed write is logged and does not undo the step.
+func (s *AccountDeletionService) audit(ctx context.Context, request *account_deletion.DeletionRequest, event string, detail string) {
+	err := s.deletionRepo.AddAuditEvent(ctx, &account_deletion.AuditEvent{
+		DeletionRequestID: request.ID,
+		UserID:            request.UserID,
+		Event:             event,
+		Detail:            detail,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record account deletion audit event",
+			logger.Field{Key: "module", Value: "account_deletion_service"},
+			logger.Field{Key: "user_id", Value: request.UserID.String()},
+			logger.Field{Key: "event", Value: event},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/pkg/utils"
+	"astroneko-backend/testings/mock_firebase"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var accountDeletionTestNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAccountDeletionService(ctrl *gomock.Controller) (*AccountDeletionService, *mock_ports.MockAccountDeletionRepositoryInterface, *mock_firebase.MockFirebaseClientInterface, *mock_logger.MockLoggerInterface) {
+	mockDeletionRepo := mock_ports.NewMockAccountDeletionRepositoryInterface(ctrl)
+	mockFirebase := mock_firebase.NewMockFirebaseClientInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewAccountDeletionService(mockDeletionRepo, mockFirebase, mockLogger)
+	service.now = func() time.Time { return accountDeletionTestNow }
+
+	return service, mockDeletionRepo, mockFirebase, mockLogger
+}
+
+func buildTestDeletionRequest(status account_deletion.Status) *account_deletion.DeletionRequest {
+	firebaseUID := "firebase-uid"
+	request := &account_deletion.DeletionRequest{
+		UserID:      uuid.New(),
+		FirebaseUID: &firebaseUID,
+		Status:      status,
+	}
+	request.ID = uuid.New()
+	return request
+}
+
+func TestAccountDeletionService_RequestDeletion_IssuesConfirmation(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, _, _ := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	var created *account_deletion.DeletionRequest
+	mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(nil, nil)
+	mockDeletionRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *account_deletion.DeletionRequest) error {
+			created = request
+			return nil
+		})
+	mockDeletionRepo.EXPECT().AddAuditEvent(ctx, gomock.Any()).Return(nil)
+
+	// Act
+	response, err := service.RequestDeletion(ctx, u, &account_deletion.DeleteAccountRequest{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, account_deletion.StatusAwaitingConfirmation, response.Status)
+	assert.Len(t, response.ConfirmationToken, 64)
+	assert.Equal(t, accountDeletionTestNow.Add(account_deletion.ConfirmationTTL), *response.ConfirmationExpiresAt)
+	assert.Equal(t, utils.HashString(response.ConfirmationToken), *created.ConfirmationTokenHash)
+	assert.Equal(t, "firebase-uid", *created.FirebaseUID)
+}
+
+func TestAccountDeletionService_RequestDeletion_ConfirmSchedules(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, _, mockLogger := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	token := strings.Repeat("ab", 32)
+	tokenHash := utils.HashString(token)
+	expiresAt := accountDeletionTestNow.Add(time.Minute)
+	pending := buildTestDeletionRequest(account_deletion.StatusAwaitingConfirmation)
+	pending.ConfirmationTokenHash = &tokenHash
+	pending.ConfirmationExpiresAt = &expiresAt
+
+	mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(pending, nil)
+	mockDeletionRepo.EXPECT().Update(ctx, pending).Return(nil)
+	mockDeletionRepo.EXPECT().AddAuditEvent(ctx, gomock.Any()).Return(nil)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	response, err := service.RequestDeletion(ctx, u, &account_deletion.DeleteAccountRequest{ConfirmationToken: token})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, account_deletion.StatusScheduled, response.Status)
+	assert.Equal(t, accountDeletionTestNow.Add(account_deletion.GracePeriod), *response.ScheduledFor)
+	assert.Empty(t, response.ConfirmationToken)
+	assert.Nil(t, pending.ConfirmationTokenHash)
+}
+
+func TestAccountDeletionService_RequestDeletion_InvalidConfirmation(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, _, _ := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	tokenHash := utils.HashString("right")
+	expired := accountDeletionTestNow.Add(-time.Minute)
+	valid := accountDeletionTestNow.Add(time.Minute)
+
+	wrongToken := buildTestDeletionRequest(account_deletion.StatusAwaitingConfirmation)
+	wrongToken.ConfirmationTokenHash = &tokenHash
+	wrongToken.ConfirmationExpiresAt = &valid
+
+	expiredToken := buildTestDeletionRequest(account_deletion.StatusAwaitingConfirmation)
+	expiredToken.ConfirmationTokenHash = &tokenHash
+	expiredToken.ConfirmationExpiresAt = &expired
+
+	gomock.InOrder(
+		mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(nil, nil),
+		mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(wrongToken, nil),
+		mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(expiredToken, nil),
+	)
+
+	// Act & Assert
+	_, err := service.RequestDeletion(ctx, u, &account_deletion.DeleteAccountRequest{ConfirmationToken: "right"})
+	assert.True(t, errors.Is(err, shared.ErrInvalidDeletionConfirmation))
+
+	_, err = service.RequestDeletion(ctx, u, &account_deletion.DeleteAccountRequest{ConfirmationToken: "wrong"})
+	assert.True(t, errors.Is(err, shared.ErrInvalidDeletionConfirmation))
+
+	_, err = service.RequestDeletion(ctx, u, &account_deletion.DeleteAccountRequest{ConfirmationToken: "right"})
+	assert.True(t, errors.Is(err, shared.ErrInvalidDeletionConfirmation))
+}
+
+func TestAccountDeletionService_CancelDeletion(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, _, _ := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	scheduled := buildTestDeletionRequest(account_deletion.StatusScheduled)
+
+	mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(scheduled, nil)
+	mockDeletionRepo.EXPECT().Update(ctx, scheduled).Return(nil)
+	mockDeletionRepo.EXPECT().
+		AddAuditEvent(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *account_deletion.AuditEvent) error {
+			assert.Equal(t, account_deletion.EventCancelled, event.Event)
+			return nil
+		})
+
+	// Act
+	err := service.CancelDeletion(ctx, u)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, account_deletion.StatusCancelled, scheduled.Status)
+	assert.Nil(t, scheduled.FirebaseUID)
+}
+
+func TestAccountDeletionService_CancelDeletion_NothingPending(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, _, _ := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	mockDeletionRepo.EXPECT().GetActiveByUserID(ctx, u.ID).Return(nil, nil)
+
+	// Act
+	err := service.CancelDeletion(ctx, u)
+
+	// Assert
+	assert.True(t, errors.Is(err, shared.ErrDeletionRequestNotFound))
+}
+
+func TestAccountDeletionService_ProcessDueDeletions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, mockFirebase, mockLogger := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	due := buildTestDeletionRequest(account_deletion.StatusScheduled)
+	purged := *due
+	purged.Status = account_deletion.StatusPurged
+
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockDeletionRepo.EXPECT().
+		ListDue(ctx, accountDeletionTestNow, accountDeletionBatchSize).
+		Return([]account_deletion.DeletionRequest{*due}, nil)
+	mockDeletionRepo.EXPECT().
+		PurgeUserData(ctx, gomock.Any()).
+		Return(&account_deletion.PurgeSummary{Sessions: 2, Messages: 10, Users: 1}, nil)
+	mockDeletionRepo.EXPECT().
+		ListAwaitingFirebase(ctx, accountDeletionBatchSize).
+		Return([]account_deletion.DeletionRequest{purged}, nil)
+	mockFirebase.EXPECT().DeleteUser(ctx, "firebase-uid").Return(nil)
+	mockDeletionRepo.EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *account_deletion.DeletionRequest) error {
+			assert.Equal(t, account_deletion.StatusCompleted, request.Status)
+			assert.Nil(t, request.FirebaseUID)
+			assert.Equal(t, accountDeletionTestNow, *request.CompletedAt)
+			return nil
+		})
+	mockDeletionRepo.EXPECT().
+		AddAuditEvent(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *account_deletion.AuditEvent) error {
+			assert.Equal(t, account_deletion.EventFirebaseDeleted, event.Event)
+			return nil
+		})
+
+	// Act
+	err := service.ProcessDueDeletions(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestAccountDeletionService_ProcessDueDeletions_FirebaseFailureIsRetried(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockDeletionRepo, mockFirebase, mockLogger := newTestAccountDeletionService(ctrl)
+	ctx := context.Background()
+	purged := buildTestDeletionRequest(account_deletion.StatusPurged)
+
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	mockDeletionRepo.EXPECT().ListDue(ctx, accountDeletionTestNow, accountDeletionBatchSize).Return(nil, nil)
+	mockDeletionRepo.EXPECT().
+		ListAwaitingFirebase(ctx, accountDeletionBatchSize).
+		Return([]account_deletion.DeletionRequest{*purged}, nil)
+	mockFirebase.EXPECT().DeleteUser(ctx, "firebase-uid").Return(errors.New("firebase unavailable"))
+	mockDeletionRepo.EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *account_deletion.DeletionRequest) error {
+			// Stays purged so the next run retries
+			assert.Equal(t, account_deletion.StatusPurged, request.Status)
+			assert.Equal(t, 1, request.Attempts)
+			assert.Equal(t, "firebase unavailable", *request.LastError)
+			return nil
+		})
+	mockDeletionRepo.EXPECT().
+		AddAuditEvent(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *account_deletion.AuditEvent) error {
+			assert.Equal(t, account_deletion.EventFirebaseFailed, event.Event)
+			return nil
+		})
+
+	// Act
+	err := service.ProcessDueDeletions(ctx)
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	userPorts "astroneko-backend/internal/core/ports/user"
+	"astroneko-backend/pkg/logger"
+)
+
+// AstrologyService manages birth profiles and natal chart computation
+type AstrologyService struct {
+	userRepo userPorts.RepositoryInterface
+	logger   logger.Logger
+	now      func() time.Time
+}
+
+// NewAstrologyService creates a new astrology service instance
+func NewAstrologyService(userRepo userPorts.RepositoryInterface, log logger.Logger) *AstrologyService {
+	return &AstrologyService{
+		userRepo: userRepo,
+		logger:   log,
+		now:      time.Now,
+	}
+}
+
+// UpdateBirthProfile replaces the user's birth profile.
+// Optional fields that are omitted are cleared so the profile always reflects the request.
+func (s *AstrologyService) UpdateBirthProfile(ctx context.Context, userID string, req *user.UpdateBirthProfileRequest) (*user.User, error) {
+	birthDate, timezone, err := parseBirthProfile(s.now(), req.BirthDate, req.BirthTime, req.BirthLatitude, req.BirthLongitude, req.BirthTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser.BirthDate = &birthDate
+	existingUser.BirthTime = req.BirthTime
+	existingUser.BirthPlace = req.BirthPlace
+	existingUser.BirthLatitude = req.BirthLatitude
+	existingUser.BirthLongitude = req.BirthLongitude
+	existingUser.BirthTimezone = &timezone
+
+	updatedUser, err := s.userRepo.Update(ctx, existingUser, user.BirthProfileColumns)
+	if err != nil {
+		s.logger.Error("Failed to update birth profile",
+			logger.Field{Key: "module", Value: "astrology_service"},
+			logger.Field{Key: "user_id", Value: userID},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, shared.ErrUserUpdateFailed
+	}
+
+	return updatedUser, nil
+}
+
+// GetNatalChart computes the natal chart of a user from their birth profile
+func (s *AstrologyService) GetNatalChart(u *user.User) (*astrology.NatalChart, error) {
+	if !u.HasBirthProfile() {
+		return nil, shared.ErrBirthProfileNotSet
+	}
+
+	birth := astrology.BirthData{
+		Date:      *u.BirthDate,
+		Time:      u.BirthTime,
+		Latitude:  u.BirthLatitude,
+		Longitude: u.BirthLongitude,
+	}
+	if u.BirthTimezone != nil {
+		birth.Timezone = *u.BirthTimezone
+	}
+
+	chart, err := astrology.BuildNatalChart(birth)
+	if err != nil {
+		s.logger.Error("Failed to compute natal chart",
+			logger.Field{Key: "module", Value: "astrology_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("%w: %s", shared.ErrInvalidBirthProfile, err.Error())
+	}
+
+	return chart, nil
+}
+
+// BuildAgentContext summarizes the user's natal chart for the agent.
+// Returns nil when the user has no usable birth profile.
+func (s *AstrologyService) BuildAgentContext(u *user.User) *agent.AstrologyContext {
+	if u == nil || !u.HasBirthProfile() {
+		return nil
+	}
+
+	chart, err := s.GetNatalChart(u)
+	if err != nil {
+		return nil
+	}
+
+	return newAstrologyContext(chart)
+}
+
+// newAstrologyContext summarizes a natal chart for the agent
+func newAstrologyContext(chart *astrology.NatalChart) *agent.AstrologyContext {
+	astrologyContext := &agent.AstrologyContext{
+		SunSign:    chart.SunSign.Key,
+		MoonSign:   chart.MoonSign.Key,
+		Placements: make([]agent.AstrologyPlacement, 0, len(chart.Planets)),
+	}
+	if chart.RisingSign != nil {
+		astrologyContext.RisingSign = chart.RisingSign.Key
+	}
+
+	for _, planet := range chart.Planets {
+		placement := agent.AstrologyPlacement{
+			Body:       string(planet.Body),
+			Sign:       planet.Sign.Key,
+			Retrograde: planet.Retrograde,
+		}
+		if planet.House != nil {
+			placement.House = *planet.House
+		}
+		astrologyContext.Placements = append(astrologyContext.Placements, placement)
+	}
+
+	return astrologyContext
+}
+
+// parseBirthProfile validates a birth profile from a request and returns the birth date
+// and timezone to store, defaulting to Asia/Bangkok. The profile is rejected unless a
+// natal chart can be computed from it.
+func parseBirthProfile(now time.Time, rawBirthDate string, birthTime *string, latitude *float64, longitude *float64, birthTimezone *string) (time.Time, string, error) {
+	birthDate, err := time.Parse("2006-01-02", rawBirthDate)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: birth date must be YYYY-MM-DD", shared.ErrInvalidBirthProfile)
+	}
+	if birthDate.After(now) {
+		return time.Time{}, "", fmt.Errorf("%w: birth date cannot be in the future", shared.ErrInvalidBirthProfile)
+	}
+
+	timezone := astrology.DefaultTimezone
+	if birthTimezone != nil && *birthTimezone != "" {
+		timezone = *birthTimezone
+	}
+
+	if _, err := astrology.BuildNatalChart(astrology.BirthData{
+		Date:      birthDate,
+		Time:      birthTime,
+		Timezone:  timezone,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}); err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: %s", shared.ErrInvalidBirthProfile, err.Error())
+	}
+
+	return birthDate, timezone, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestBirthProfileUser() *user.User {
+	birthDate := time.Date(1990, 8, 15, 0, 0, 0, 0, time.UTC)
+	birthTime := "08:30"
+	timezone := "Asia/Bangkok"
+	latitude, longitude := 13.7563, 100.5018
+
+	u := &user.User{
+		Email:          "stargazer@example.com",
+		FirebaseUID:    "firebase-uid",
+		BirthDate:      &birthDate,
+		BirthTime:      &birthTime,
+		BirthTimezone:  &timezone,
+		BirthLatitude:  &latitude,
+		BirthLongitude: &longitude,
+	}
+	u.ID = uuid.New()
+	return u
+}
+
+func TestAstrologyService_UpdateBirthProfile_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewAstrologyService(mockUserRepo, mockLogger)
+
+	ctx := context.Background()
+	existingUser := &user.User{Email: "stargazer@example.com"}
+	existingUser.ID = uuid.New()
+	birthTime := "23:45"
+	place := "Chiang Mai"
+
+	req := &user.UpdateBirthProfileRequest{
+		BirthDate:  "1995-02-28",
+		BirthTime:  &birthTime,
+		BirthPlace: &place,
+	}
+
+	mockUserRepo.EXPECT().GetByID(ctx, existingUser.ID.String()).Return(existingUser, nil)
+	mockUserRepo.EXPECT().
+		Update(ctx, gomock.Any(), user.BirthProfileColumns).
+		DoAndReturn(func(ctx context.Context, u *user.User, _ []string) (*user.User, error) {
+			return u, nil
+		})
+
+	// Act
+	updatedUser, err := service.UpdateBirthProfile(ctx, existingUser.ID.String(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "1995-02-28", updatedUser.BirthDate.Format("2006-01-02"))
+	assert.Equal(t, "23:45", *updatedUser.BirthTime)
+	assert.Equal(t, "Chiang Mai", *updatedUser.BirthPlace)
+	assert.Equal(t, "Asia/Bangkok", *updatedUser.BirthTimezone)
+	assert.Nil(t, updatedUser.BirthLatitude)
+}
+
+func TestAstrologyService_UpdateBirthProfile_FutureDate(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewAstrologyService(mockUserRepo, mockLogger)
+	service.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	// Act
+	updatedUser, err := service.UpdateBirthProfile(context.Background(), uuid.New().String(), &user.UpdateBirthProfileRequest{BirthDate: "2026-06-01"})
+
+	// Assert
+	assert.Nil(t, updatedUser)
+	assert.True(t, errors.Is(err, shared.ErrInvalidBirthProfile))
+}
+
+func TestAstrologyService_UpdateBirthProfile_RepositoryError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewAstrologyService(mockUserRepo, mockLogger)
+
+	ctx := context.Background()
+	existingUser := &user.User{Email: "stargazer@example.com"}
+	existingUser.ID = uuid.New()
+
+	mockUserRepo.EXPECT().GetByID(ctx, existingUser.ID.String()).Return(existingUser, nil)
+	mockUserRepo.EXPECT().Update(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+	mockLogger.EXPECT().Error("Failed to update birth profile", gomock.Any()).AnyTimes()
+
+	// Act
+	updatedUser, err := service.UpdateBirthProfile(ctx, existingUser.ID.String(), &user.UpdateBirthProfileRequest{BirthDate: "1990-01-01"})
+
+	// Assert
+	assert.Nil(t, updatedUser)
+	assert.Equal(t, shared.ErrUserUpdateFailed, err)
+}
+
+func TestAstrologyService_GetNatalChart_NoProfile(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewAstrologyService(mock_ports.NewMockUserRepositoryInterface(ctrl), mock_logger.NewMockLoggerInterface(ctrl))
+
+	// Act
+	chart, err := service.GetNatalChart(&user.User{})
+
+	// Assert
+	assert.Nil(t, chart)
+	assert.Equal(t, shared.ErrBirthProfileNotSet, err)
+}
+
+func TestAstrologyService_GetNatalChart_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewAstrologyService(mock_ports.NewMockUserRepositoryInterface(ctrl), mock_logger.NewMockLoggerInterface(ctrl))
+
+	// Act
+	chart, err := service.GetNatalChart(buildTestBirthProfileUser())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "leo", chart.SunSign.Key)
+	assert.NotNil(t, chart.RisingSign)
+	assert.Len(t, chart.Houses, 12)
+}
+
+func TestAstrologyService_BuildAgentContext(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewAstrologyService(mock_ports.NewMockUserRepositoryInterface(ctrl), mock_logger.NewMockLoggerInterface(ctrl))
+
+	// Act
+	withProfile := service.BuildAgentContext(buildTestBirthProfileUser())
+	withoutProfile := service.BuildAgentContext(&user.User{})
+
+	// Assert
+	assert.NotNil(t, withProfile)
+	assert.Equal(t, "leo", withProfile.SunSign)
+	assert.NotEmpty(t, withProfile.RisingSign)
+	assert.Len(t, withProfile.Placements, 10)
+	assert.Nil(t, withoutProfile)
+}
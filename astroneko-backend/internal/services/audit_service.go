@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/audit"
+	auditPorts "astroneko-backend/internal/core/ports/audit"
+	"astroneko-backend/pkg/logger"
+)
+
+// auditChainBatchSize bounds how many entries VerifyChain holds in memory at once
+const auditChainBatchSize = 500
+
+// AuditService records administrative changes made from the CRM and lets admins
+// browse the log and check that it has not been edited
+type AuditService struct {
+	auditRepo auditPorts.RepositoryInterface
+	logger    logger.Logger
+}
+
+// NewAuditService creates a new audit log service instance
+func NewAuditService(auditRepo auditPorts.RepositoryInterface, log logger.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    log,
+	}
+}
+
+// Record appends an entry for a change that has already been made. A failure is
+// logged rather than returned so that the change itself is still reported as done.
+func (s *AuditService) Record(ctx context.Context, actor audit.Actor, action, targetType, targetID string, before, after any) {
+	entry, err := audit.NewEntry(actor, action, targetType, targetID, before, after)
+	if err == nil {
+		err = s.auditRepo.Create(ctx, entry)
+	}
+	if err != nil {
+		s.logger.Error("Failed to record audit entry",
+			logger.Field{Key: "module", Value: "audit_service"},
+			logger.Field{Key: "action", Value: action},
+			logger.Field{Key: "target_type", Value: targetType},
+			logger.Field{Key: "target_id", Value: targetID},
+			logger.Field{Key: "actor_id", Value: actor.ID},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+}
+
+// List returns a page of entries matching the filter, newest first
+func (s *AuditService) List(ctx context.Context, filter audit.ListFilter) ([]*audit.Entry, int64, error) {
+	return s.auditRepo.List(ctx, filter)
+}
+
+// VerifyChain recomputes every hash from the first entry and stops at the first one
+// that does not match. Entries written before the log was hashed are skipped.
+func (s *AuditService) VerifyChain(ctx context.Context) (*audit.ChainVerification, error) {
+	result := &audit.ChainVerification{Verified: true}
+
+	var prev *audit.Entry
+	var after int64
+	for {
+		entries, err := s.auditRepo.ListChain(ctx, after, auditChainBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			after = entry.Sequence
+			if prev == nil && entry.Hash == "" {
+				continue
+			}
+
+			result.Checked++
+			if reason := entry.CheckLink(prev); reason != "" {
+				sequence := entry.Sequence
+				result.Verified = false
+				result.BrokenAtSequence = &sequence
+				result.Reason = reason
+				s.logger.Error("Audit log hash chain is broken",
+					logger.Field{Key: "module", Value: "audit_service"},
+					logger.Field{Key: "security_event", Value: "audit_chain_broken"},
+					logger.Field{Key: "sequence", Value: sequence},
+					logger.Field{Key: "reason", Value: reason})
+				return result, nil
+			}
+
+			prev = entry
+			result.HeadSequence = entry.Sequence
+			result.HeadHash = entry.Hash
+		}
+
+		if len(entries) < auditChainBatchSize {
+			return result, nil
+		}
+	}
+}
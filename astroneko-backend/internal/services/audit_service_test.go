@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/audit"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildTestAuditChain returns n linked entries, starting at sequence offset+1
+func buildTestAuditChain(t *testing.T, offset int64, n int) []*audit.Entry {
+	t.Helper()
+	// Entries from before hashing have a sequence but no hash
+	var prev *audit.Entry
+	if offset > 0 {
+		prev = &audit.Entry{Sequence: offset}
+	}
+	var chain []*audit.Entry
+	for i := 0; i < n; i++ {
+		entry, err := audit.NewEntry(buildTestAuditActor(), audit.ActionUserLimitUpdated, audit.TargetUserLimit, "limit-id",
+			map[string]int{"limit": 300 + i}, map[string]int{"limit": 301 + i})
+		assert.NoError(t, err)
+		entry.Chain(prev, time.Date(2026, 3, 1, 0, 0, i, 0, time.UTC))
+		chain = append(chain, entry)
+		prev = entry
+	}
+	return chain
+}
+
+func TestAuditService_Record(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditRepo := mock_ports.NewMockAuditRepositoryInterface(ctrl)
+	service := NewAuditService(auditRepo, mock_logger.NewMockLoggerInterface(ctrl))
+
+	var recorded *audit.Entry
+	auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *audit.Entry) error {
+		recorded = entry
+		return nil
+	})
+
+	// Act
+	service.Record(context.Background(), buildTestAuditActor(), audit.ActionReferralCodeDeleted, audit.TargetReferralCode, "code-id",
+		map[string]string{"referral_code": "SUMMER"}, nil)
+
+	// Assert
+	assert.Equal(t, audit.ActionReferralCodeDeleted, recorded.Action)
+	assert.Equal(t, "crm-user-id", recorded.ActorID)
+	assert.Equal(t, "10.0.0.1", recorded.IPAddress)
+	assert.Equal(t, "request-id", recorded.RequestID)
+	assert.JSONEq(t, `{"referral_code":{"before":"SUMMER","after":null}}`, recorded.Changes)
+}
+
+func TestAuditService_Record_FailureIsLogged(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditRepo := mock_ports.NewMockAuditRepositoryInterface(ctrl)
+	log := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewAuditService(auditRepo, log)
+
+	auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+	log.EXPECT().Error("Failed to record audit entry", gomock.Any()).Times(1)
+
+	// Act & Assert
+	service.Record(context.Background(), buildTestAuditActor(), audit.ActionCRMUserCreated, audit.TargetCRMUser, "crm-id", nil, map[string]string{"username": "ploy"})
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  func(t *testing.T) []*audit.Entry
+		verified bool
+		checked  int64
+		broken   *int64
+	}{
+		{
+			name:     "empty log",
+			entries:  func(t *testing.T) []*audit.Entry { return nil },
+			verified: true,
+		},
+		{
+			name:     "intact chain",
+			entries:  func(t *testing.T) []*audit.Entry { return buildTestAuditChain(t, 0, 3) },
+			verified: true,
+			checked:  3,
+		},
+		{
+			name: "entries written before hashing are skipped",
+			entries: func(t *testing.T) []*audit.Entry {
+				legacy := []*audit.Entry{{Sequence: 1}, {Sequence: 2}}
+				return append(legacy, buildTestAuditChain(t, 2, 2)...)
+			},
+			verified: true,
+			checked:  2,
+		},
+		{
+			name: "edited entry",
+			entries: func(t *testing.T) []*audit.Entry {
+				chain := buildTestAuditChain(t, 0, 3)
+				chain[1].ActorName = "admin"
+				return chain
+			},
+			verified: false,
+			checked:  2,
+			broken:   func() *int64 { s := int64(2); return &s }(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			auditRepo := mock_ports.NewMockAuditRepositoryInterface(ctrl)
+			log := mock_logger.NewMockLoggerInterface(ctrl)
+			log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			service := NewAuditService(auditRepo, log)
+
+			entries := tt.entries(t)
+			auditRepo.EXPECT().ListChain(gomock.Any(), int64(0), auditChainBatchSize).Return(entries, nil)
+
+			// Act
+			result, err := service.VerifyChain(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.verified, result.Verified)
+			assert.Equal(t, tt.checked, result.Checked)
+			assert.Equal(t, tt.broken, result.BrokenAtSequence)
+			if tt.verified && len(entries) > 0 {
+				assert.Equal(t, entries[len(entries)-1].Hash, result.HeadHash)
+			}
+		})
+	}
+}
+
+func TestAuditService_VerifyChain_ReadsInBatches(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditRepo := mock_ports.NewMockAuditRepositoryInterface(ctrl)
+	service := NewAuditService(auditRepo, mock_logger.NewMockLoggerInterface(ctrl))
+
+	chain := buildTestAuditChain(t, 0, auditChainBatchSize+1)
+	gomock.InOrder(
+		auditRepo.EXPECT().ListChain(gomock.Any(), int64(0), auditChainBatchSize).Return(chain[:auditChainBatchSize], nil),
+		auditRepo.EXPECT().ListChain(gomock.Any(), int64(auditChainBatchSize), auditChainBatchSize).Return(chain[auditChainBatchSize:], nil),
+	)
+
+	// Act
+	result, err := service.VerifyChain(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, int64(auditChainBatchSize+1), result.Checked)
+	assert.Equal(t, int64(auditChainBatchSize+1), result.HeadSequence)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/compatibility"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/numerology"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	agentPorts "astroneko-backend/internal/core/ports/agent"
+	historyPorts "astroneko-backend/internal/core/ports/history"
+	personPorts "astroneko-backend/internal/core/ports/person"
+	"astroneko-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// CompatibilityService compares the user with a saved person and has the agent interpret the result
+type CompatibilityService struct {
+	personRepo       personPorts.RepositoryInterface
+	historyRepo      historyPorts.RepositoryInterface
+	agentRepo        agentPorts.RepositoryInterface
+	astrologyService *AstrologyService
+	logger           logger.Logger
+}
+
+// NewCompatibilityService creates a new compatibility service instance
+func NewCompatibilityService(
+	personRepo personPorts.RepositoryInterface,
+	historyRepo historyPorts.RepositoryInterface,
+	agentRepo agentPorts.RepositoryInterface,
+	astrologyService *AstrologyService,
+	log logger.Logger,
+) *CompatibilityService {
+	return &CompatibilityService{
+		personRepo:       personRepo,
+		historyRepo:      historyRepo,
+		agentRepo:        agentRepo,
+		astrologyService: astrologyService,
+		logger:           log,
+	}
+}
+
+// CreateReading computes the compatibility breakdown between the user and a saved person,
+// sends it to the agent and stores the exchange as a history session.
+// A failure to store the session is logged and does not discard the reading.
+func (s *CompatibilityService) CreateReading(ctx context.Context, u *user.User, req *compatibility.ReadingRequest) (*compatibility.ReadingResponse, error) {
+	personID, err := uuid.Parse(req.PersonID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: person_id must be a UUID", shared.ErrInvalidRequest)
+	}
+
+	userChart, err := s.astrologyService.GetNatalChart(u)
+	if err != nil {
+		return nil, err
+	}
+
+	savedPerson, err := s.personRepo.GetByID(ctx, u.ID, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	personChart, err := astrology.BuildNatalChart(savedPerson.BirthData())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", shared.ErrInvalidBirthProfile, err.Error())
+	}
+
+	userName := ""
+	if u.DisplayName != nil {
+		userName = *u.DisplayName
+	}
+	breakdown := compatibility.Compare(
+		compatibilityProfile(userName, userChart, *u.BirthDate),
+		compatibilityProfile(savedPerson.Name, personChart, savedPerson.BirthDate),
+	)
+
+	question := req.Question
+	if question == "" {
+		question = fmt.Sprintf("ช่วยดูดวงความเข้ากันระหว่างฉันกับ %s หน่อย", savedPerson.Name)
+	}
+
+	sessionID := uuid.New()
+	reply, err := s.agentRepo.Reply(ctx, agent.ReplyRequest{
+		Text:             question,
+		UserID:           u.ID.String(),
+		SessionID:        sessionID.String(),
+		AstrologyContext: newAstrologyContext(userChart),
+		CompatibilityContext: &agent.CompatibilityContext{
+			PersonName:      savedPerson.Name,
+			Relationship:    savedPerson.Relationship,
+			PersonAstrology: newAstrologyContext(personChart),
+			Breakdown:       breakdown,
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to get compatibility reading",
+			logger.Field{Key: "module", Value: "compatibility_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	session := &history.Session{
+		ID:          sessionID,
+		UserID:      u.ID,
+		HistoryName: fmt.Sprintf("ดูดวงคู่กับ %s", savedPerson.Name),
+	}
+	messages := []history.Message{
+		{Message: question, Role: history.MessageRoleUser},
+		{Message: history.EmbedCardInMessage(reply.Message, reply.Card, reply.Meaning), Role: history.MessageRoleAssistant},
+	}
+	if err := s.historyRepo.CreateSessionWithMessages(ctx, session, messages); err != nil {
+		s.logger.Error("Failed to save compatibility reading",
+			logger.Field{Key: "module", Value: "compatibility_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "session_id", Value: sessionID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+
+	return &compatibility.ReadingResponse{
+		SessionID: sessionID,
+		Person:    savedPerson.ToResponse(),
+		Breakdown: breakdown,
+		Message:   reply.Message,
+		Card:      reply.Card,
+		Meaning:   reply.Meaning,
+	}, nil
+}
+
+// compatibilityProfile gathers the chart and numerology numbers of one side of a reading.
+// Names without letters numerology can read are left out of the comparison.
+func compatibilityProfile(name string, chart *astrology.NatalChart, birthDate time.Time) compatibility.Profile {
+	profile := compatibility.Profile{
+		Name:     name,
+		Chart:    chart,
+		LifePath: numerology.AnalyzeLifePath(birthDate),
+	}
+	if nameNumbers, err := numerology.AnalyzeName(name); err == nil {
+		profile.NameNumbers = nameNumbers
+	}
+	return profile
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/compatibility"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type compatibilityServiceMocks struct {
+	personRepo  *mock_ports.MockPersonRepositoryInterface
+	historyRepo *mock_ports.HistoryRepositoryInterface
+	agentRepo   *mock_ports.MockAgentRepositoryInterface
+	logger      *mock_logger.MockLoggerInterface
+}
+
+func newTestCompatibilityService(ctrl *gomock.Controller) (*CompatibilityService, compatibilityServiceMocks) {
+	mocks := compatibilityServiceMocks{
+		personRepo:  mock_ports.NewMockPersonRepositoryInterface(ctrl),
+		historyRepo: mock_ports.NewHistoryRepositoryInterface(ctrl),
+		agentRepo:   mock_ports.NewMockAgentRepositoryInterface(ctrl),
+		logger:      mock_logger.NewMockLoggerInterface(ctrl),
+	}
+	astrologyService := NewAstrologyService(mock_ports.NewMockUserRepositoryInterface(ctrl), mocks.logger)
+
+	service := NewCompatibilityService(mocks.personRepo, mocks.historyRepo, mocks.agentRepo, astrologyService, mocks.logger)
+	return service, mocks
+}
+
+func buildTestPerson(userID uuid.UUID) *person.Person {
+	p := &person.Person{
+		UserID:       userID,
+		Name:         "Mali",
+		Relationship: person.RelationshipPartner,
+		BirthDate:    time.Date(1992, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+	p.ID = uuid.New()
+	return p
+}
+
+func TestCompatibilityService_CreateReading_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestCompatibilityService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	displayName := "Somchai"
+	u.DisplayName = &displayName
+	savedPerson := buildTestPerson(u.ID)
+
+	mocks.personRepo.EXPECT().GetByID(ctx, u.ID, savedPerson.ID).Return(savedPerson, nil)
+	mocks.agentRepo.EXPECT().
+		Reply(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, request agent.ReplyRequest) (*agent.ReplyResponse, error) {
+			assert.Equal(t, "Are we a good match?", request.Text)
+			assert.Equal(t, u.ID.String(), request.UserID)
+			assert.Equal(t, "leo", request.AstrologyContext.SunSign)
+			assert.Equal(t, "pisces", request.CompatibilityContext.PersonAstrology.SunSign)
+			assert.Equal(t, "Somchai", request.CompatibilityContext.Breakdown.PersonA)
+			assert.Equal(t, "Mali", request.CompatibilityContext.Breakdown.PersonB)
+			return &agent.ReplyResponse{Message: "A gentle match", Card: "THE_LOVERS", Meaning: "Harmony"}, nil
+		})
+	mocks.historyRepo.EXPECT().
+		CreateSessionWithMessages(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *history.Session, messages []history.Message) error {
+			assert.Equal(t, u.ID, session.UserID)
+			assert.Equal(t, "ดูดวงคู่กับ Mali", session.HistoryName)
+			assert.Len(t, messages, 2)
+			assert.Equal(t, history.MessageRoleUser, messages[0].Role)
+			assert.Equal(t, history.MessageRoleAssistant, messages[1].Role)
+			assert.Contains(t, messages[1].Message, "THE_LOVERS")
+			return nil
+		})
+
+	// Act
+	reading, err := service.CreateReading(ctx, u, &compatibility.ReadingRequest{
+		PersonID: savedPerson.ID.String(),
+		Question: "Are we a good match?",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, reading.SessionID)
+	assert.Equal(t, "Mali", reading.Person.Name)
+	assert.Equal(t, "A gentle match", reading.Message)
+	assert.Equal(t, "THE_LOVERS", reading.Card)
+	assert.NotEmpty(t, reading.Breakdown.Signs)
+	assert.NotEmpty(t, reading.Breakdown.Numbers)
+	assert.GreaterOrEqual(t, reading.Breakdown.Score, 0)
+	assert.LessOrEqual(t, reading.Breakdown.Score, 100)
+}
+
+func TestCompatibilityService_CreateReading_SaveFailureKeepsReading(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestCompatibilityService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	savedPerson := buildTestPerson(u.ID)
+
+	mocks.personRepo.EXPECT().GetByID(ctx, u.ID, savedPerson.ID).Return(savedPerson, nil)
+	mocks.agentRepo.EXPECT().Reply(ctx, gomock.Any()).Return(&agent.ReplyResponse{Message: "reading"}, nil)
+	mocks.historyRepo.EXPECT().CreateSessionWithMessages(ctx, gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+	mocks.logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	reading, err := service.CreateReading(ctx, u, &compatibility.ReadingRequest{PersonID: savedPerson.ID.String()})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "reading", reading.Message)
+}
+
+func TestCompatibilityService_CreateReading_NoBirthProfile(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestCompatibilityService(ctrl)
+
+	// Act
+	reading, err := service.CreateReading(context.Background(), &user.User{}, &compatibility.ReadingRequest{PersonID: uuid.New().String()})
+
+	// Assert
+	assert.Nil(t, reading)
+	assert.True(t, errors.Is(err, shared.ErrBirthProfileNotSet))
+}
+
+func TestCompatibilityService_CreateReading_PersonNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestCompatibilityService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	personID := uuid.New()
+
+	mocks.personRepo.EXPECT().GetByID(ctx, u.ID, personID).Return(nil, shared.ErrPersonNotFound)
+
+	// Act
+	_, err := service.CreateReading(ctx, u, &compatibility.ReadingRequest{PersonID: personID.String()})
+
+	// Assert
+	assert.True(t, errors.Is(err, shared.ErrPersonNotFound))
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
+	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/utils"
+)
+
+// CredentialAttemptService slows down and locks out repeated failures on endpoints that
+// check a secret. Failures count against the account and the client IP separately, so
+// spraying one password over many accounts is caught as well as guessing many passwords
+// for one account.
+type CredentialAttemptService struct {
+	attemptRepo credentialAttemptPorts.RepositoryInterface
+	logger      logger.Logger
+	now         func() time.Time
+}
+
+// NewCredentialAttemptService creates a new credential attempt tracker
+func NewCredentialAttemptService(attemptRepo credentialAttemptPorts.RepositoryInterface, log logger.Logger) *CredentialAttemptService {
+	return &CredentialAttemptService{
+		attemptRepo: attemptRepo,
+		logger:      log,
+		now:         time.Now,
+	}
+}
+
+type attemptKey struct {
+	keyType string
+	hash    string
+}
+
+// keys lists the counters an attempt touches, skipping a missing account or IP
+func (s *CredentialAttemptService) keys(account, clientIP string) []attemptKey {
+	var keys []attemptKey
+	if account = credential_attempt.NormalizeAccount(account); account != "" {
+		keys = append(keys, attemptKey{keyType: credential_attempt.KeyAccount, hash: utils.HashString(account)})
+	}
+	if clientIP != "" {
+		keys = append(keys, attemptKey{keyType: credential_attempt.KeyIP, hash: utils.HashString(clientIP)})
+	}
+	return keys
+}
+
+// Check refuses the attempt while the account or the IP is blocked, reporting the longer wait
+func (s *CredentialAttemptService) Check(ctx context.Context, scope, account, clientIP string) error {
+	now := s.now()
+	var retryAfter time.Duration
+	for _, key := range s.keys(account, clientIP) {
+		attempt, err := s.attemptRepo.Get(ctx, scope, key.keyType, key.hash)
+		if err != nil {
+			return err
+		}
+		if wait := attempt.RetryAfter(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		s.logger.Warn("Blocked credential attempt",
+			logger.Field{Key: "module", Value: "credential_attempt_service"},
+			logger.Field{Key: "security_event", Value: "credential_blocked"},
+			logger.Field{Key: "scope", Value: scope},
+			logger.Field{Key: "client_ip", Value: clientIP},
+			logger.Field{Key: "retry_after", Value: retryAfter.String()})
+		return &credential_attempt.BlockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and blocks the keys that went over their policy
+func (s *CredentialAttemptService) RecordFailure(ctx context.Context, scope, account, clientIP string) error {
+	now := s.now()
+	for _, key := range s.keys(account, clientIP) {
+		policy := credential_attempt.PolicyFor(key.keyType)
+		attempt, err := s.attemptRepo.RecordFailure(ctx, scope, key.keyType, key.hash, now, now.Add(-policy.Window))
+		if err != nil {
+			return err
+		}
+
+		fields := []logger.Field{
+			{Key: "module", Value: "credential_attempt_service"},
+			{Key: "security_event", Value: "credential_failure"},
+			{Key: "scope", Value: scope},
+			{Key: "key_type", Value: key.keyType},
+			{Key: "key_hash", Value: key.hash},
+			{Key: "client_ip", Value: clientIP},
+			{Key: "failures", Value: attempt.Failures},
+		}
+
+		delay, lockout := policy.Block(attempt.Failures)
+		if delay == 0 {
+			s.logger.Info("Failed credential attempt", fields...)
+			continue
+		}
+		if err := s.attemptRepo.LockUntil(ctx, attempt.ID, now.Add(delay)); err != nil {
+			return err
+		}
+
+		fields = append(fields, logger.Field{Key: "blocked_for", Value: delay.String()})
+		if lockout {
+			fields[1].Value = "credential_lockout"
+			s.logger.Warn("Locked out after repeated failed credential attempts", fields...)
+		} else {
+			s.logger.Warn("Failed credential attempt, backing off", fields...)
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the account's counter. The IP counter is kept, otherwise an
+// attacker could reset it by logging in to an account of their own between guesses.
+func (s *CredentialAttemptService) RecordSuccess(ctx context.Context, scope, account string) error {
+	for _, key := range s.keys(account, "") {
+		if _, err := s.attemptRepo.Reset(ctx, scope, key.keyType, key.hash); err != nil {
+			return err
+		}
+	}
+
+	now := s.now()
+	window := max(credential_attempt.AccountPolicy.Window, credential_attempt.IPPolicy.Window)
+	return s.attemptRepo.DeleteStale(ctx, now.Add(-window), now)
+}
+
+// Unlock lets an administrator lift an account's backoff or lockout early
+func (s *CredentialAttemptService) Unlock(ctx context.Context, scope, account string) (bool, error) {
+	unlocked := false
+	for _, key := range s.keys(account, "") {
+		reset, err := s.attemptRepo.Reset(ctx, scope, key.keyType, key.hash)
+		if err != nil {
+			return false, err
+		}
+		unlocked = unlocked || reset
+	}
+
+	if unlocked {
+		s.logger.Warn("Unlocked account after failed credential attempts",
+			logger.Field{Key: "module", Value: "credential_attempt_service"},
+			logger.Field{Key: "security_event", Value: "credential_unlock"},
+			logger.Field{Key: "scope", Value: scope})
+	}
+	return unlocked, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/pkg/utils"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var credentialAttemptTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// allowCredentialAttempts is a tracker that never blocks, for tests that are not about attempt tracking
+func allowCredentialAttempts(ctrl *gomock.Controller) *mock_ports.MockCredentialAttemptServiceInterface {
+	attempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	attempts.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	attempts.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	attempts.EXPECT().RecordSuccess(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return attempts
+}
+
+func newTestCredentialAttemptService(ctrl *gomock.Controller) (*CredentialAttemptService, *mock_ports.MockCredentialAttemptRepositoryInterface, *mock_logger.MockLoggerInterface) {
+	repo := mock_ports.NewMockCredentialAttemptRepositoryInterface(ctrl)
+	log := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewCredentialAttemptService(repo, log)
+	service.now = func() time.Time { return credentialAttemptTestNow }
+	return service, repo, log
+}
+
+func TestCredentialAttemptService_Check(t *testing.T) {
+	accountHash := utils.HashString("admin")
+	ipHash := utils.HashString("10.0.0.1")
+	lockedFor := func(d time.Duration) *credential_attempt.Attempt {
+		until := credentialAttemptTestNow.Add(d)
+		return &credential_attempt.Attempt{LockedUntil: &until}
+	}
+
+	tests := []struct {
+		name           string
+		account        *credential_attempt.Attempt
+		ip             *credential_attempt.Attempt
+		wantRetryAfter time.Duration
+	}{
+		{name: "no failures"},
+		{name: "backoff over", account: lockedFor(-time.Second)},
+		{name: "account blocked", account: lockedFor(8 * time.Second), wantRetryAfter: 8 * time.Second},
+		{name: "longer block wins", account: lockedFor(8 * time.Second), ip: lockedFor(time.Minute), wantRetryAfter: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, repo, log := newTestCredentialAttemptService(ctrl)
+			ctx := context.Background()
+			repo.EXPECT().Get(ctx, credential_attempt.ScopeCRMLogin, credential_attempt.KeyAccount, accountHash).Return(tt.account, nil)
+			repo.EXPECT().Get(ctx, credential_attempt.ScopeCRMLogin, credential_attempt.KeyIP, ipHash).Return(tt.ip, nil)
+			log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+			// Act
+			err := service.Check(ctx, credential_attempt.ScopeCRMLogin, " Admin", "10.0.0.1")
+
+			// Assert
+			if tt.wantRetryAfter == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var blocked *credential_attempt.BlockedError
+			require.True(t, errors.As(err, &blocked))
+			assert.ErrorIs(t, err, shared.ErrTooManyAttempts)
+			assert.Equal(t, tt.wantRetryAfter, blocked.RetryAfter)
+		})
+	}
+}
+
+func TestCredentialAttemptService_RecordFailure(t *testing.T) {
+	tests := []struct {
+		name            string
+		accountFailures int
+		ipFailures      int
+		wantAccountLock time.Duration
+		wantIPLock      time.Duration
+	}{
+		{name: "free attempt", accountFailures: 2, ipFailures: 2},
+		{name: "account backs off", accountFailures: 5, ipFailures: 5, wantAccountLock: 2 * time.Second},
+		{name: "account locked out", accountFailures: 10, ipFailures: 10, wantAccountLock: 15 * time.Minute},
+		{name: "ip backs off", accountFailures: 1, ipFailures: 21, wantIPLock: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, repo, log := newTestCredentialAttemptService(ctrl)
+			ctx := context.Background()
+			accountAttempt := &credential_attempt.Attempt{ID: uuid.New(), Failures: tt.accountFailures}
+			ipAttempt := &credential_attempt.Attempt{ID: uuid.New(), Failures: tt.ipFailures}
+
+			repo.EXPECT().
+				RecordFailure(ctx, credential_attempt.ScopeUserLogin, credential_attempt.KeyAccount, utils.HashString("user@example.com"),
+					credentialAttemptTestNow, credentialAttemptTestNow.Add(-credential_attempt.AccountPolicy.Window)).
+				Return(accountAttempt, nil)
+			repo.EXPECT().
+				RecordFailure(ctx, credential_attempt.ScopeUserLogin, credential_attempt.KeyIP, utils.HashString("10.0.0.1"),
+					credentialAttemptTestNow, credentialAttemptTestNow.Add(-credential_attempt.IPPolicy.Window)).
+				Return(ipAttempt, nil)
+			if tt.wantAccountLock > 0 {
+				repo.EXPECT().LockUntil(ctx, accountAttempt.ID, credentialAttemptTestNow.Add(tt.wantAccountLock)).Return(nil)
+			}
+			if tt.wantIPLock > 0 {
+				repo.EXPECT().LockUntil(ctx, ipAttempt.ID, credentialAttemptTestNow.Add(tt.wantIPLock)).Return(nil)
+			}
+			log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+			// Act
+			err := service.RecordFailure(ctx, credential_attempt.ScopeUserLogin, "User@example.com", "10.0.0.1")
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCredentialAttemptService_RecordSuccess_KeepsIPCount(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, _ := newTestCredentialAttemptService(ctrl)
+	ctx := context.Background()
+
+	repo.EXPECT().Reset(ctx, credential_attempt.ScopeCRMLogin, credential_attempt.KeyAccount, utils.HashString("admin")).Return(true, nil)
+	repo.EXPECT().DeleteStale(ctx, credentialAttemptTestNow.Add(-time.Hour), credentialAttemptTestNow).Return(nil)
+
+	// Act
+	err := service.RecordSuccess(ctx, credential_attempt.ScopeCRMLogin, "admin")
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestCredentialAttemptService_Unlock(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo, log := newTestCredentialAttemptService(ctrl)
+	ctx := context.Background()
+
+	repo.EXPECT().Reset(ctx, credential_attempt.ScopeUserLogin, credential_attempt.KeyAccount, utils.HashString("user@example.com")).Return(true, nil)
+	log.EXPECT().Warn(gomock.Any(), gomock.Any())
+
+	// Act
+	unlocked, err := service.Unlock(ctx, credential_attempt.ScopeUserLogin, "user@example.com")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, unlocked)
+}
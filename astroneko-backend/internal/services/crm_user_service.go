@@ -2,28 +2,53 @@ package services
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/core/domain/shared"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
 	crmUserPorts "astroneko-backend/internal/core/ports/crm_user"
 	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/utils"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// JWT typ claims, so a token issued for one purpose is refused for another
+const (
+	crmAccessTokenType    = "access"
+	crmChallengeTokenType = "two_factor_challenge"
+)
+
 type CRMUserService struct {
-	crmUserRepo crmUserPorts.RepositoryInterface
-	logger      logger.Logger
-	jwtSecret   string
+	crmUserRepo      crmUserPorts.RepositoryInterface
+	attempts         credentialAttemptPorts.ServiceInterface
+	logger           logger.Logger
+	signingKeys      *crm_user.KeyRing
+	bootstrapToken   string
+	requireTwoFactor bool
+	now              func() time.Time
 }
 
-func NewCRMUserService(crmUserRepo crmUserPorts.RepositoryInterface, logger logger.Logger, jwtSecret string) *CRMUserService {
+// NewCRMUserService creates a new CRM user service. Access tokens are signed with the
+// active key of signingKeys. An empty bootstrapToken disables BootstrapAdmin. When
+// requireTwoFactor is set, users without an authenticator must enroll one to log in.
+// Failed passwords and two-factor codes are counted by attempts.
+func NewCRMUserService(crmUserRepo crmUserPorts.RepositoryInterface, attempts credentialAttemptPorts.ServiceInterface, logger logger.Logger, signingKeys *crm_user.KeyRing, bootstrapToken string, requireTwoFactor bool) *CRMUserService {
 	return &CRMUserService{
-		crmUserRepo: crmUserRepo,
-		logger:      logger,
-		jwtSecret:   jwtSecret,
+		crmUserRepo:      crmUserRepo,
+		attempts:         attempts,
+		logger:           logger,
+		signingKeys:      signingKeys,
+		bootstrapToken:   bootstrapToken,
+		requireTwoFactor: requireTwoFactor,
+		now:              time.Now,
 	}
 }
 
@@ -33,6 +58,16 @@ func (s *CRMUserService) CreateUser(ctx context.Context, req *crm_user.CreateCRM
 		return nil, fmt.Errorf("username already exists")
 	}
 
+	if err := crm_user.CheckPasswordPolicy(req.Password, req.Username); err != nil {
+		return nil, err
+	}
+
+	if len(req.Roles) > 0 {
+		if err := s.validateRoles(ctx, req.Roles); err != nil {
+			return nil, err
+		}
+	}
+
 	newUser := &crm_user.CRMUser{
 		Username: req.Username,
 	}
@@ -46,41 +81,79 @@ func (s *CRMUserService) CreateUser(ctx context.Context, req *crm_user.CreateCRM
 		return nil, fmt.Errorf("failed to create CRM user: %w", err)
 	}
 
+	if len(req.Roles) > 0 {
+		if err := s.crmUserRepo.SetRoles(ctx, createdUser.ID, req.Roles, nil); err != nil {
+			return nil, fmt.Errorf("failed to assign roles: %w", err)
+		}
+		return s.crmUserRepo.GetByID(ctx, createdUser.ID.String())
+	}
+
 	return createdUser, nil
 }
 
+// Login checks the password. Unknown usernames and wrong passwords get the same error,
+// and both count against the username and the client IP.
 func (s *CRMUserService) Login(ctx context.Context, req *crm_user.CRMLoginRequest) (*crm_user.CRMLoginResponse, error) {
+	if err := s.attempts.Check(ctx, credential_attempt.ScopeCRMLogin, req.Username, req.ClientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.crmUserRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		crm_user.CheckDummyPassword(req.Password)
+		s.recordLoginFailure(ctx, req.Username, req.ClientIP)
+		return nil, shared.ErrInvalidCredentials
 	}
 
 	if !user.CheckPassword(req.Password) {
-		return nil, fmt.Errorf("invalid credentials")
+		s.recordLoginFailure(ctx, req.Username, req.ClientIP)
+		return nil, shared.ErrInvalidCredentials
 	}
 
-	token, err := s.generateJWT(user)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	if user.IsDisabled() {
+		s.logger.Warn("Disabled CRM user attempted to log in",
+			logger.Field{Key: "module", Value: "crm_user_service"},
+			logger.Field{Key: "crm_user_id", Value: user.ID.String()})
+		return nil, shared.ErrCRMUserDisabled
 	}
 
-	return &crm_user.CRMLoginResponse{
-		User:  user.ToResponse(),
-		Token: token,
-	}, nil
+	if user.IsTwoFactorEnabled() {
+		return s.challenge(user, crm_user.TwoFactorStepVerify)
+	}
+	if s.requireTwoFactor {
+		return s.challenge(user, crm_user.TwoFactorStepEnroll)
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// completeLogin clears the failure count once every factor has been checked. Clearing
+// it after the password alone would give a fresh set of two-factor guesses per login.
+func (s *CRMUserService) completeLogin(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMLoginResponse, error) {
+	if err := s.attempts.RecordSuccess(ctx, credential_attempt.ScopeCRMLogin, user.Username); err != nil {
+		return nil, err
+	}
+	return s.issueSession(ctx, user)
+}
+
+// recordLoginFailure counts a failed login. The caller answers with the generic error
+// either way, so a tracker failure is only logged.
+func (s *CRMUserService) recordLoginFailure(ctx context.Context, username, clientIP string) {
+	if err := s.attempts.RecordFailure(ctx, credential_attempt.ScopeCRMLogin, username, clientIP); err != nil {
+		s.logger.Error("Failed to record failed CRM login",
+			logger.Field{Key: "module", Value: "crm_user_service"},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
 }
 
 func (s *CRMUserService) GetUserByID(ctx context.Context, id string) (*crm_user.CRMUser, error) {
 	return s.crmUserRepo.GetByID(ctx, id)
 }
 
-func (s *CRMUserService) ValidateToken(tokenString string) (*crm_user.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
+// ValidateToken verifies the access token's signature with the key named in its kid
+// header and rejects tokens on the jti denylist
+func (s *CRMUserService) ValidateToken(ctx context.Context, tokenString string) (*crm_user.JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, s.verificationKey, jwt.WithExpirationRequired())
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -95,6 +168,10 @@ func (s *CRMUserService) ValidateToken(tokenString string) (*crm_user.JWTClaims,
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
+	if typ, ok := (*claims)["typ"]; ok && typ != crmAccessTokenType {
+		return nil, fmt.Errorf("invalid token type")
+	}
+
 	userIDStr, ok := (*claims)["user_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("invalid user_id in token")
@@ -110,20 +187,848 @@ func (s *CRMUserService) ValidateToken(tokenString string) (*crm_user.JWTClaims,
 		return nil, fmt.Errorf("invalid username in token")
 	}
 
+	jti, ok := (*claims)["jti"].(string)
+	if !ok || jti == "" {
+		return nil, fmt.Errorf("invalid jti in token")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("invalid exp in token")
+	}
+
+	revoked, err := s.crmUserRepo.IsAccessTokenRevoked(ctx, jti)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, shared.ErrCRMTokenRevoked
+	}
+
+	// Tokens issued before session versions existed carry none and count as version 0
+	sessionVersion, _ := (*claims)["session_version"].(float64)
+
 	return &crm_user.JWTClaims{
-		UserID:   userID,
-		Username: username,
+		ID:             jti,
+		UserID:         userID,
+		Username:       username,
+		Roles:          claimStrings((*claims)["roles"]),
+		Permissions:    claimStrings((*claims)["permissions"]),
+		SessionVersion: int(sessionVersion),
+		ExpiresAt:      expiresAt.Time,
 	}, nil
 }
 
-func (s *CRMUserService) generateJWT(user *crm_user.CRMUser) (string, error) {
+// Authenticate validates the token and loads its user. Tokens of disabled users and
+// tokens issued before the user's last password change or disable are rejected.
+func (s *CRMUserService) Authenticate(ctx context.Context, tokenString string) (*crm_user.CRMUser, *crm_user.JWTClaims, error) {
+	claims, err := s.ValidateToken(ctx, tokenString)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.crmUserRepo.GetByID(ctx, claims.UserID.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	if user.IsDisabled() {
+		return nil, nil, shared.ErrCRMUserDisabled
+	}
+	if claims.SessionVersion != user.SessionVersion {
+		return nil, nil, shared.ErrCRMSessionRevoked
+	}
+
+	return user, claims, nil
+}
+
+// claimStrings reads a string array claim; tokens issued before roles existed have none
+func claimStrings(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// generateJWT signs a short-lived access token with the active key
+func (s *CRMUserService) generateJWT(user *crm_user.CRMUser, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(crm_user.AccessTokenTTL)
 	claims := jwt.MapClaims{
-		"user_id":  user.ID.String(),
-		"username": user.Username,
-		"exp":      time.Now().Add(7 * 24 * time.Hour).Unix(),
-		"iat":      time.Now().Unix(),
+		"typ":             crmAccessTokenType,
+		"jti":             uuid.NewString(),
+		"user_id":         user.ID.String(),
+		"username":        user.Username,
+		"roles":           nonNilStrings(user.Roles),
+		"permissions":     nonNilStrings(user.Permissions),
+		"session_version": user.SessionVersion,
+		"exp":             expiresAt.Unix(),
+		"iat":             now.Unix(),
+	}
+
+	signed, err := s.sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
 	}
+	return signed, expiresAt, nil
+}
 
+// sign signs the claims with the active key and names the key in the kid header
+func (s *CRMUserService) sign(claims jwt.MapClaims) (string, error) {
+	kid, secret := s.signingKeys.Active()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	token.Header["kid"] = kid
+	return token.SignedString(secret)
+}
+
+// verificationKey looks up the key named in the token's kid header
+func (s *CRMUserService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	secret, ok := s.signingKeys.Secret(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return secret, nil
+}
+
+// newRefreshToken creates a refresh token in the family. The plain token is returned
+// to hand to the client; only its hash is stored.
+func (s *CRMUserService) newRefreshToken(user *crm_user.CRMUser, familyID uuid.UUID, now time.Time) (*crm_user.RefreshToken, string, error) {
+	plain, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &crm_user.RefreshToken{
+		ID:             uuid.New(),
+		CRMUserID:      user.ID,
+		FamilyID:       familyID,
+		TokenHash:      utils.HashString(plain),
+		SessionVersion: user.SessionVersion,
+		ExpiresAt:      now.Add(crm_user.RefreshTokenTTL),
+	}, plain, nil
+}
+
+// issueSession starts a new refresh token family and returns it with an access token
+func (s *CRMUserService) issueSession(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMLoginResponse, error) {
+	now := s.now()
+	refresh, refreshToken, err := s.newRefreshToken(user, uuid.New(), now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.crmUserRepo.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+
+	return s.loginResponse(user, refresh, refreshToken, now)
+}
+
+func (s *CRMUserService) loginResponse(user *crm_user.CRMUser, refresh *crm_user.RefreshToken, refreshToken string, now time.Time) (*crm_user.CRMLoginResponse, error) {
+	token, expiresAt, err := s.generateJWT(user, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &crm_user.CRMLoginResponse{
+		User:                  user.ToResponse(),
+		Token:                 token,
+		ExpiresAt:             &expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: &refresh.ExpiresAt,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// A token that was already exchanged is evidence of theft, so presenting one revokes
+// every token in its family and both the thief and the user have to log in again.
+func (s *CRMUserService) Refresh(ctx context.Context, req *crm_user.RefreshTokenRequest) (*crm_user.CRMLoginResponse, error) {
+	now := s.now()
+	current, err := s.crmUserRepo.GetRefreshTokenByHash(ctx, utils.HashString(req.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, shared.ErrInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil && current.ReplacedBy != nil {
+		s.logger.Warn("CRM refresh token reused, revoking its family",
+			logger.Field{Key: "module", Value: "crm_user_service"},
+			logger.Field{Key: "crm_user_id", Value: current.CRMUserID.String()},
+			logger.Field{Key: "family_id", Value: current.FamilyID.String()})
+		if err := s.crmUserRepo.RevokeRefreshTokenFamily(ctx, current.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, shared.ErrInvalidRefreshToken
+	}
+	if !current.IsActive(now) {
+		return nil, shared.ErrInvalidRefreshToken
+	}
+
+	user, err := s.crmUserRepo.GetByID(ctx, current.CRMUserID.String())
+	if err != nil {
+		return nil, shared.ErrInvalidRefreshToken
+	}
+	if user.IsDisabled() {
+		return nil, shared.ErrCRMUserDisabled
+	}
+	// The password changed since login
+	if current.SessionVersion != user.SessionVersion {
+		return nil, shared.ErrInvalidRefreshToken
+	}
+
+	next, refreshToken, err := s.newRefreshToken(user, current.FamilyID, now)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.crmUserRepo.RotateRefreshToken(ctx, current.ID, next, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, shared.ErrInvalidRefreshToken
+	}
+
+	return s.loginResponse(user, next, refreshToken, now)
+}
+
+// Logout denies the access token for the rest of its lifetime and, when given the
+// session's refresh token, revokes the refresh token family too
+func (s *CRMUserService) Logout(ctx context.Context, claims *crm_user.JWTClaims, refreshToken string) error {
+	now := s.now()
+	err := s.crmUserRepo.RevokeAccessToken(ctx, &crm_user.RevokedAccessToken{
+		JTI:       claims.ID,
+		CRMUserID: claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		refresh, err := s.crmUserRepo.GetRefreshTokenByHash(ctx, utils.HashString(refreshToken))
+		if err != nil {
+			return err
+		}
+		// Other users' tokens are ignored rather than reported, so logout cannot be used to probe them
+		if refresh != nil && refresh.CRMUserID == claims.UserID {
+			if err := s.crmUserRepo.RevokeRefreshTokenFamily(ctx, refresh.FamilyID, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.logger.Info("CRM user logged out",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: claims.UserID.String()})
+
+	return nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// ListRoles returns every role with the permissions it grants
+func (s *CRMUserService) ListRoles(ctx context.Context) ([]crm_user.Role, error) {
+	return s.crmUserRepo.ListRoles(ctx)
+}
+
+// AssignRoles replaces the roles of a CRM user. The last admin cannot lose the admin
+// role, so there is always someone left who can manage roles.
+func (s *CRMUserService) AssignRoles(ctx context.Context, actorID uuid.UUID, userID string, roles []string) (*crm_user.CRMUser, error) {
+	target, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	roles = uniqueStrings(roles)
+	if err := s.validateRoles(ctx, roles); err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(target.Roles, crm_user.RoleAdmin) && !slices.Contains(roles, crm_user.RoleAdmin) {
+		admins, err := s.crmUserRepo.CountByRole(ctx, crm_user.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, shared.ErrLastCRMAdmin
+		}
+	}
+
+	if err := s.crmUserRepo.SetRoles(ctx, target.ID, roles, &actorID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("CRM user roles changed",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: target.ID.String()},
+		logger.Field{Key: "actor_id", Value: actorID.String()},
+		logger.Field{Key: "old_roles", Value: target.Roles},
+		logger.Field{Key: "new_roles", Value: roles})
+
+	return s.crmUserRepo.GetByID(ctx, userID)
+}
+
+// BootstrapAdmin makes the first CRM admin. It only works while no admin exists and the
+// caller knows the configured bootstrap token. An existing CRM user is promoted when the
+// password matches; otherwise a new user is created.
+func (s *CRMUserService) BootstrapAdmin(ctx context.Context, req *crm_user.BootstrapAdminRequest) (*crm_user.CRMUser, error) {
+	if s.bootstrapToken == "" {
+		return nil, shared.ErrCRMBootstrapClosed
+	}
+	if subtle.ConstantTimeCompare([]byte(req.BootstrapToken), []byte(s.bootstrapToken)) != 1 {
+		s.logger.Warn("CRM bootstrap attempted with an invalid token",
+			logger.Field{Key: "module", Value: "crm_user_service"},
+			logger.Field{Key: "username", Value: req.Username})
+		return nil, shared.ErrForbidden
+	}
+
+	admins, err := s.crmUserRepo.CountByRole(ctx, crm_user.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if admins > 0 {
+		return nil, shared.ErrCRMBootstrapClosed
+	}
+
+	admin, err := s.crmUserRepo.GetByUsername(ctx, req.Username)
+	if err == nil && admin != nil {
+		if !admin.CheckPassword(req.Password) {
+			return nil, shared.ErrForbidden
+		}
+	} else {
+		if err := crm_user.CheckPasswordPolicy(req.Password, req.Username); err != nil {
+			return nil, err
+		}
+		admin = &crm_user.CRMUser{Username: req.Username}
+		if err := admin.HashPassword(req.Password); err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		if admin, err = s.crmUserRepo.Create(ctx, admin); err != nil {
+			return nil, fmt.Errorf("failed to create CRM user: %w", err)
+		}
+	}
+
+	roles := uniqueStrings(append(slices.Clone(admin.Roles), crm_user.RoleAdmin))
+	if err := s.crmUserRepo.SetRoles(ctx, admin.ID, roles, nil); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("CRM admin bootstrapped",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: admin.ID.String()},
+		logger.Field{Key: "username", Value: admin.Username})
+
+	return s.crmUserRepo.GetByID(ctx, admin.ID.String())
+}
+
+// ListUsers returns a page of CRM users with their roles
+func (s *CRMUserService) ListUsers(ctx context.Context, limit, offset int) ([]*crm_user.CRMUser, int64, error) {
+	return s.crmUserRepo.List(ctx, limit, offset)
+}
+
+// SetDisabled disables or re-enables a CRM user. Disabling revokes the user's tokens.
+// Users cannot disable themselves, and the last enabled admin cannot be disabled.
+func (s *CRMUserService) SetDisabled(ctx context.Context, actorID uuid.UUID, userID string, disabled bool) (*crm_user.CRMUser, error) {
+	target, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	if target.IsDisabled() == disabled {
+		return target, nil
+	}
+
+	var updated *crm_user.CRMUser
+	if disabled {
+		if target.ID == actorID {
+			return nil, fmt.Errorf("%w: CRM users cannot disable themselves", shared.ErrForbidden)
+		}
+		if slices.Contains(target.Roles, crm_user.RoleAdmin) {
+			admins, err := s.crmUserRepo.CountByRole(ctx, crm_user.RoleAdmin)
+			if err != nil {
+				return nil, err
+			}
+			if admins <= 1 {
+				return nil, shared.ErrLastCRMAdmin
+			}
+		}
+
+		now := s.now()
+		target.DisabledAt = &now
+		updated, err = s.crmUserRepo.UpdateAndRevokeSessions(ctx, target, []string{crm_user.ColumnDisabledAt})
+	} else {
+		target.DisabledAt = nil
+		updated, err = s.crmUserRepo.Update(ctx, target, []string{crm_user.ColumnDisabledAt})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update CRM user: %w", err)
+	}
+
+	s.logger.Info("CRM user access changed",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: target.ID.String()},
+		logger.Field{Key: "actor_id", Value: actorID.String()},
+		logger.Field{Key: "disabled", Value: disabled})
+
+	return updated, nil
+}
+
+// ChangePassword sets a new password for the user after checking the current one.
+// Every other session is revoked; the returned token belongs to the new session.
+func (s *CRMUserService) ChangePassword(ctx context.Context, userID string, req *crm_user.ChangePasswordRequest) (*crm_user.CRMLoginResponse, error) {
+	user, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	if !user.CheckPassword(req.CurrentPassword) {
+		return nil, shared.ErrInvalidPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, fmt.Errorf("%w: must differ from the current password", shared.ErrWeakPassword)
+	}
+	if err := crm_user.CheckPasswordPolicy(req.NewPassword, user.Username); err != nil {
+		return nil, err
+	}
+
+	if err := user.SetPassword(req.NewPassword, s.now()); err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if _, err := s.crmUserRepo.UpdateAndRevokeSessions(ctx, user, crm_user.PasswordColumns); err != nil {
+		return nil, fmt.Errorf("failed to update CRM user: %w", err)
+	}
+	// The user may have been disabled while the new password was hashed
+	if user.IsDisabled() {
+		return nil, shared.ErrCRMUserDisabled
+	}
+
+	return s.issueSession(ctx, user)
+}
+
+// IssuePasswordReset creates a one-time reset token for the user and voids older ones.
+// The plain token is returned once for the admin to hand over.
+func (s *CRMUserService) IssuePasswordReset(ctx context.Context, actorID uuid.UUID, userID string) (*crm_user.PasswordResetResponse, error) {
+	target, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	reset := &crm_user.PasswordReset{
+		CRMUserID: target.ID,
+		TokenHash: utils.HashString(token),
+		ExpiresAt: s.now().Add(crm_user.PasswordResetTTL),
+		CreatedBy: &actorID,
+	}
+	if err := s.crmUserRepo.CreatePasswordReset(ctx, reset); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("CRM password reset issued",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: target.ID.String()},
+		logger.Field{Key: "actor_id", Value: actorID.String()})
+
+	return &crm_user.PasswordResetResponse{
+		Token:     token,
+		ExpiresAt: reset.ExpiresAt,
+	}, nil
+}
+
+// ResetPassword redeems a reset token and sets the new password, revoking every session
+func (s *CRMUserService) ResetPassword(ctx context.Context, req *crm_user.ResetPasswordRequest) error {
+	now := s.now()
+	reset, err := s.crmUserRepo.GetPasswordResetByTokenHash(ctx, utils.HashString(req.Token))
+	if err != nil {
+		return err
+	}
+	if reset == nil || !reset.IsUsable(now) {
+		return shared.ErrInvalidResetToken
+	}
+
+	user, err := s.crmUserRepo.GetByID(ctx, reset.CRMUserID.String())
+	if err != nil {
+		return shared.ErrInvalidResetToken
+	}
+	if err := crm_user.CheckPasswordPolicy(req.NewPassword, user.Username); err != nil {
+		return err
+	}
+
+	consumed, err := s.crmUserRepo.ConsumePasswordReset(ctx, reset.ID, now)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return shared.ErrInvalidResetToken
+	}
+
+	if err := user.SetPassword(req.NewPassword, now); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if _, err := s.crmUserRepo.UpdateAndRevokeSessions(ctx, user, crm_user.PasswordColumns); err != nil {
+		return fmt.Errorf("failed to update CRM user: %w", err)
+	}
+
+	s.logger.Info("CRM password reset redeemed",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: user.ID.String()})
+
+	return nil
+}
+
+// validateRoles rejects role names that are not in the roles table
+func (s *CRMUserService) validateRoles(ctx context.Context, roles []string) error {
+	known, err := s.crmUserRepo.ListRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if !slices.ContainsFunc(known, func(r crm_user.Role) bool { return r.Name == role }) {
+			return fmt.Errorf("%w: %s", shared.ErrUnknownCRMRole, role)
+		}
+	}
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// challenge returns a login response that asks for the second factor instead of a session
+func (s *CRMUserService) challenge(user *crm_user.CRMUser, step string) (*crm_user.CRMLoginResponse, error) {
+	now := s.now()
+	expiresAt := now.Add(crm_user.TwoFactorChallengeTTL)
+	token, err := s.sign(jwt.MapClaims{
+		"typ":             crmChallengeTokenType,
+		"jti":             uuid.NewString(),
+		"user_id":         user.ID.String(),
+		"step":            step,
+		"session_version": user.SessionVersion,
+		"exp":             expiresAt.Unix(),
+		"iat":             now.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+
+	return &crm_user.CRMLoginResponse{
+		User: user.ToResponse(),
+		TwoFactor: &crm_user.TwoFactorChallenge{
+			Step:           step,
+			ChallengeToken: token,
+			ExpiresAt:      expiresAt,
+		},
+	}, nil
+}
+
+// challengeUser checks a challenge token issued by Login for the step and loads its user
+func (s *CRMUserService) challengeUser(ctx context.Context, tokenString string, step string) (*crm_user.CRMUser, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.MapClaims{}, s.verificationKey, jwt.WithExpirationRequired(), jwt.WithTimeFunc(s.now))
+	if err != nil || !token.Valid {
+		return nil, shared.ErrInvalidTwoFactorChallenge
+	}
+
+	claims, ok := token.Claims.(*jwt.MapClaims)
+	if !ok || (*claims)["typ"] != crmChallengeTokenType || (*claims)["step"] != step {
+		return nil, shared.ErrInvalidTwoFactorChallenge
+	}
+	userID, _ := (*claims)["user_id"].(string)
+	sessionVersion, _ := (*claims)["session_version"].(float64)
+
+	user, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, shared.ErrInvalidTwoFactorChallenge
+	}
+	if user.IsDisabled() {
+		return nil, shared.ErrCRMUserDisabled
+	}
+	// The password changed or two-factor was reset since the password step
+	if int(sessionVersion) != user.SessionVersion {
+		return nil, shared.ErrInvalidTwoFactorChallenge
+	}
+
+	return user, nil
+}
+
+// checkTOTP accepts the code once; a replayed code is refused even inside its time window
+func (s *CRMUserService) checkTOTP(ctx context.Context, user *crm_user.CRMUser, code string) error {
+	step, ok := crm_user.VerifyTOTP(user.TOTPSecret, code, s.now(), user.TOTPLastStep)
+	if !ok {
+		return shared.ErrInvalidTwoFactorCode
+	}
+
+	recorded, err := s.crmUserRepo.RecordTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return shared.ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPLastStep = step
+	return nil
+}
+
+// VerifyTwoFactorLogin completes a login with a TOTP code or a recovery code
+func (s *CRMUserService) VerifyTwoFactorLogin(ctx context.Context, req *crm_user.TwoFactorLoginRequest) (*crm_user.CRMLoginResponse, error) {
+	user, err := s.challengeUser(ctx, req.ChallengeToken, crm_user.TwoFactorStepVerify)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempts.Check(ctx, credential_attempt.ScopeCRMLogin, user.Username, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	if req.Code != "" {
+		err = s.checkTOTP(ctx, user, req.Code)
+	} else {
+		err = s.checkRecoveryCode(ctx, user, req.RecoveryCode)
+	}
+	if errors.Is(err, shared.ErrInvalidTwoFactorCode) {
+		s.recordLoginFailure(ctx, user.Username, req.ClientIP)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// checkRecoveryCode uses up one of the user's recovery codes
+func (s *CRMUserService) checkRecoveryCode(ctx context.Context, user *crm_user.CRMUser, code string) error {
+	codeHash := utils.HashString(crm_user.NormalizeRecoveryCode(code))
+	consumed, err := s.crmUserRepo.ConsumeRecoveryCode(ctx, user.ID, codeHash, s.now())
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return shared.ErrInvalidTwoFactorCode
+	}
+
+	s.logger.Warn("CRM user logged in with a recovery code",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: user.ID.String()})
+	return nil
+}
+
+// SetupTwoFactor generates a new TOTP secret for the user to scan. It takes effect
+// once EnableTwoFactor confirms a code from the authenticator.
+func (s *CRMUserService) SetupTwoFactor(ctx context.Context, user *crm_user.CRMUser) (*crm_user.TwoFactorSetupResponse, error) {
+	if user.IsTwoFactorEnabled() {
+		return nil, shared.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := crm_user.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if _, err := s.crmUserRepo.Update(ctx, user, []string{crm_user.ColumnTOTPSecret}); err != nil {
+		return nil, fmt.Errorf("failed to update CRM user: %w", err)
+	}
+
+	uri := crm_user.TOTPProvisioningURI(secret, user.Username)
+	qrCode, err := utils.QRCodePNG(uri, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crm_user.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRCodePNG:       qrCode,
+	}, nil
+}
+
+// EnableTwoFactor turns two-factor authentication on after checking a code from the
+// authenticator set up by SetupTwoFactor, and returns a fresh set of recovery codes
+func (s *CRMUserService) EnableTwoFactor(ctx context.Context, user *crm_user.CRMUser, code string) ([]string, error) {
+	if user.IsTwoFactorEnabled() {
+		return nil, shared.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, shared.ErrTwoFactorNotSetUp
+	}
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	user.TOTPEnabledAt = &now
+	if _, err := s.crmUserRepo.Update(ctx, user, []string{crm_user.ColumnTOTPEnabledAt}); err != nil {
+		return nil, fmt.Errorf("failed to update CRM user: %w", err)
+	}
+
+	s.logger.Info("CRM user enabled two-factor authentication",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: user.ID.String()})
+
+	return s.replaceRecoveryCodes(ctx, user)
+}
+
+// SetupTwoFactorForLogin starts enrollment for a user who must enroll before logging in
+func (s *CRMUserService) SetupTwoFactorForLogin(ctx context.Context, req *crm_user.TwoFactorChallengeRequest) (*crm_user.TwoFactorSetupResponse, error) {
+	user, err := s.challengeUser(ctx, req.ChallengeToken, crm_user.TwoFactorStepEnroll)
+	if err != nil {
+		return nil, err
+	}
+	return s.SetupTwoFactor(ctx, user)
+}
+
+// EnableTwoFactorForLogin finishes enrollment and the login it interrupted. The recovery
+// codes are returned with the new session.
+func (s *CRMUserService) EnableTwoFactorForLogin(ctx context.Context, req *crm_user.TwoFactorEnrollRequest) (*crm_user.CRMLoginResponse, error) {
+	user, err := s.challengeUser(ctx, req.ChallengeToken, crm_user.TwoFactorStepEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.EnableTwoFactor(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.issueSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	session.RecoveryCodes = codes
+	return session, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off for the user. It needs both the
+// password and a current code, and is refused while two-factor authentication is required.
+func (s *CRMUserService) DisableTwoFactor(ctx context.Context, user *crm_user.CRMUser, req *crm_user.DisableTwoFactorRequest) error {
+	if s.requireTwoFactor {
+		return fmt.Errorf("%w: two-factor authentication is required for CRM users", shared.ErrForbidden)
+	}
+	if !user.IsTwoFactorEnabled() {
+		return shared.ErrTwoFactorNotSetUp
+	}
+	if !user.CheckPassword(req.Password) {
+		return shared.ErrInvalidPassword
+	}
+	if err := s.checkTOTP(ctx, user, req.Code); err != nil {
+		return err
+	}
+
+	user.ClearTwoFactor()
+	if _, err := s.crmUserRepo.Update(ctx, user, crm_user.TwoFactorColumns); err != nil {
+		return fmt.Errorf("failed to update CRM user: %w", err)
+	}
+	if err := s.crmUserRepo.ReplaceRecoveryCodes(ctx, user.ID, nil); err != nil {
+		return err
+	}
+
+	s.logger.Info("CRM user disabled two-factor authentication",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: user.ID.String()})
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a current code
+func (s *CRMUserService) RegenerateRecoveryCodes(ctx context.Context, user *crm_user.CRMUser, code string) ([]string, error) {
+	if !user.IsTwoFactorEnabled() {
+		return nil, shared.ErrTwoFactorNotSetUp
+	}
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, user)
+}
+
+// ResetTwoFactor lets an admin recover a user who lost their authenticator and their
+// recovery codes. The user's sessions are revoked; if two-factor authentication is
+// required they enroll again at their next login.
+func (s *CRMUserService) ResetTwoFactor(ctx context.Context, actorID uuid.UUID, userID string) (*crm_user.CRMUser, error) {
+	target, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	target.ClearTwoFactor()
+	updated, err := s.crmUserRepo.UpdateAndRevokeSessions(ctx, target, crm_user.TwoFactorColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update CRM user: %w", err)
+	}
+	if err := s.crmUserRepo.ReplaceRecoveryCodes(ctx, target.ID, nil); err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("CRM user two-factor authentication reset by admin",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: target.ID.String()},
+		logger.Field{Key: "actor_id", Value: actorID.String()})
+
+	return updated, nil
+}
+
+// UnlockUser clears the failed login count of a CRM user, ending a backoff or lockout
+func (s *CRMUserService) UnlockUser(ctx context.Context, actorID uuid.UUID, userID string) (*credential_attempt.UnlockResponse, error) {
+	target, err := s.crmUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrCRMUserNotFound, err)
+	}
+
+	unlocked, err := s.attempts.Unlock(ctx, credential_attempt.ScopeCRMLogin, target.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("CRM user login unlocked by admin",
+		logger.Field{Key: "module", Value: "crm_user_service"},
+		logger.Field{Key: "crm_user_id", Value: target.ID.String()},
+		logger.Field{Key: "actor_id", Value: actorID.String()},
+		logger.Field{Key: "unlocked", Value: unlocked})
+
+	return &credential_attempt.UnlockResponse{Unlocked: unlocked}, nil
+}
+
+func (s *CRMUserService) replaceRecoveryCodes(ctx context.Context, user *crm_user.CRMUser) ([]string, error) {
+	codes, err := crm_user.GenerateRecoveryCodes(crm_user.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]crm_user.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, crm_user.RecoveryCode{
+			CRMUserID: user.ID,
+			CodeHash:  utils.HashString(crm_user.NormalizeRecoveryCode(code)),
+		})
+	}
+	if err := s.crmUserRepo.ReplaceRecoveryCodes(ctx, user.ID, records); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
 }
@@ -15,16 +15,22 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/crm_user"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/pkg/utils"
 	"astroneko-backend/testings/mock_logger"
 	"astroneko-backend/testings/mock_ports"
 )
 
 // Test data builders for consistent test data
+// testStrongPassword satisfies crm_user.CheckPasswordPolicy
+const testStrongPassword = "Nebula-Orbit-2048"
+
 func buildCreateCRMUserRequest() *crm_user.CreateCRMUserRequest {
 	return &crm_user.CreateCRMUserRequest{
 		Username: "testuser",
-		Password: "password123",
+		Password: testStrongPassword,
 	}
 }
 
@@ -35,6 +41,19 @@ func buildCreateCRMUserRequestWithUsernameAndPassword(username, password string)
 	}
 }
 
+const (
+	testSigningKeyID  = "test-key"
+	testSigningSecret = "test-secret-of-at-least-32-bytes"
+)
+
+func buildCRMKeyRing() *crm_user.KeyRing {
+	ring, err := crm_user.NewKeyRing(testSigningKeyID, []crm_user.SigningKey{{ID: testSigningKeyID, Secret: testSigningSecret}})
+	if err != nil {
+		panic(err)
+	}
+	return ring
+}
+
 func buildCRMLoginRequest() *crm_user.CRMLoginRequest {
 	return &crm_user.CRMLoginRequest{
 		Username: "testuser",
@@ -49,6 +68,12 @@ func buildCRMLoginRequestWithUsernameAndPassword(username, password string) *crm
 	}
 }
 
+// bumpSessionVersion stands in for UpdateAndRevokeSessions, which bumps the version in SQL
+func bumpSessionVersion(_ context.Context, user *crm_user.CRMUser, _ []string) (*crm_user.CRMUser, error) {
+	user.SessionVersion++
+	return user, nil
+}
+
 func buildCRMUser() *crm_user.CRMUser {
 	return &crm_user.CRMUser{
 		Username: "testuser",
@@ -83,8 +108,7 @@ func TestCRMUserService_CreateUser_Success(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCreateCRMUserRequest()
 	expectedUser := buildCRMUserWithID("123e4567-e89b-12d3-a456-426614174000")
@@ -112,8 +136,7 @@ func TestCRMUserService_CreateUser_UsernameAlreadyExists(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCreateCRMUserRequest()
 	existingUser := buildCRMUserWithIDAndUsername("123e4567-e89b-12d3-a456-426614174000", req.Username)
@@ -138,10 +161,9 @@ func TestCRMUserService_CreateUser_PasswordHashingError(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
-	// Use a password that will cause bcrypt to fail
+	// Use a password that bcrypt cannot hash; the password policy rejects it before hashing
 	req := buildCreateCRMUserRequestWithUsernameAndPassword("testuser", string(make([]byte, 73))) // Password too long for bcrypt
 
 	// Setup expectations - service should check for existing user first
@@ -153,7 +175,7 @@ func TestCRMUserService_CreateUser_PasswordHashingError(t *testing.T) {
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to hash password")
+	assert.ErrorIs(t, err, shared.ErrWeakPassword)
 }
 
 func TestCRMUserService_CreateUser_RepositoryError(t *testing.T) {
@@ -164,8 +186,7 @@ func TestCRMUserService_CreateUser_RepositoryError(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCreateCRMUserRequest()
 	dbError := errors.New("database constraint violation")
@@ -191,14 +212,14 @@ func TestCRMUserService_Login_Success(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCRMLoginRequest()
 	existingUser := buildCRMUserWithIDAndUsername("123e4567-e89b-12d3-a456-426614174000", req.Username)
 
 	// Setup expectations
 	mockCRMUserRepo.EXPECT().GetByUsername(ctx, req.Username).Return(existingUser, nil)
+	mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
 
 	// Act
 	result, err := service.Login(ctx, req)
@@ -220,8 +241,7 @@ func TestCRMUserService_Login_UserNotFound(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCRMLoginRequest()
 
@@ -245,8 +265,7 @@ func TestCRMUserService_Login_InvalidPassword(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCRMLoginRequestWithUsernameAndPassword("testuser", "wrongpassword")
 	existingUser := buildCRMUserWithIDAndUsername("123e4567-e89b-12d3-a456-426614174000", req.Username)
@@ -271,8 +290,7 @@ func TestCRMUserService_Login_TokenValidation(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCRMLoginRequest()
 	existingUser := &crm_user.CRMUser{
@@ -282,6 +300,7 @@ func TestCRMUserService_Login_TokenValidation(t *testing.T) {
 
 	// Setup expectations
 	mockCRMUserRepo.EXPECT().GetByUsername(ctx, req.Username).Return(existingUser, nil)
+	mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
 
 	// Act
 	result, err := service.Login(ctx, req)
@@ -293,7 +312,8 @@ func TestCRMUserService_Login_TokenValidation(t *testing.T) {
 	assert.NotEmpty(t, result.Token)
 
 	// Verify token can be validated
-	claims, err := service.ValidateToken(result.Token)
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(ctx, gomock.Any()).Return(false, nil)
+	claims, err := service.ValidateToken(ctx, result.Token)
 	require.NoError(t, err)
 	assert.Equal(t, existingUser.Username, claims.Username)
 }
@@ -306,8 +326,7 @@ func TestCRMUserService_GetUserByID_Success(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	testID := "123e4567-e89b-12d3-a456-426614174000"
 	expectedUser := buildCRMUserWithID(testID)
@@ -333,8 +352,7 @@ func TestCRMUserService_GetUserByID_NotFound(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	testID := "123e4567-e89b-12d3-a456-426614174000"
 
@@ -358,24 +376,27 @@ func TestCRMUserService_ValidateToken_Success(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 
 	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
 	username := "testuser"
 
 	// Generate a valid token
 	claims := jwt.MapClaims{
+		"jti":      "token-id",
 		"user_id":  userID.String(),
 		"username": username,
-		"exp":      time.Now().Add(7 * 24 * time.Hour).Unix(),
+		"exp":      time.Now().Add(crm_user.AccessTokenTTL).Unix(),
 		"iat":      time.Now().Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(jwtSecret))
+	token.Header["kid"] = testSigningKeyID
+	tokenString, _ := token.SignedString([]byte(testSigningSecret))
+
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(gomock.Any(), "token-id").Return(false, nil)
 
 	// Act
-	result, err := service.ValidateToken(tokenString)
+	result, err := service.ValidateToken(context.Background(), tokenString)
 
 	// Assert
 	require.NoError(t, err)
@@ -392,11 +413,10 @@ func TestCRMUserService_ValidateToken_InvalidToken(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 
 	// Act
-	result, err := service.ValidateToken("invalid-token")
+	result, err := service.ValidateToken(context.Background(), "invalid-token")
 
 	// Assert
 	assert.Error(t, err)
@@ -412,14 +432,13 @@ func TestCRMUserService_ValidateToken_WrongSigningMethod(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 
 	// For testing, we'll just create an invalid token string
 	tokenString := "invalid.signing.method.token"
 
 	// Act
-	result, err := service.ValidateToken(tokenString)
+	result, err := service.ValidateToken(context.Background(), tokenString)
 
 	// Assert
 	assert.Error(t, err)
@@ -435,8 +454,7 @@ func TestCRMUserService_ValidateToken_ExpiredToken(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 
 	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
 	username := "testuser"
@@ -449,10 +467,11 @@ func TestCRMUserService_ValidateToken_ExpiredToken(t *testing.T) {
 		"iat":      time.Now().Add(-2 * time.Hour).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(jwtSecret))
+	token.Header["kid"] = testSigningKeyID
+	tokenString, _ := token.SignedString([]byte(testSigningSecret))
 
 	// Act
-	result, err := service.ValidateToken(tokenString)
+	result, err := service.ValidateToken(context.Background(), tokenString)
 
 	// Assert
 	assert.Error(t, err)
@@ -468,8 +487,7 @@ func TestCRMUserService_ValidateToken_InvalidClaims(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 
 	// Generate a token with missing claims
 	claims := jwt.MapClaims{
@@ -478,10 +496,11 @@ func TestCRMUserService_ValidateToken_InvalidClaims(t *testing.T) {
 		// Missing user_id and username
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(jwtSecret))
+	token.Header["kid"] = testSigningKeyID
+	tokenString, _ := token.SignedString([]byte(testSigningSecret))
 
 	// Act
-	result, err := service.ValidateToken(tokenString)
+	result, err := service.ValidateToken(context.Background(), tokenString)
 
 	// Assert
 	assert.Error(t, err)
@@ -497,13 +516,14 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 		password      string
 		existingUser  *crm_user.CRMUser
 		repoError     error
+		weakPassword  bool
 		expectedError bool
 		description   string
 	}{
 		{
 			name:          "Valid new user",
 			username:      "newuser",
-			password:      "password123",
+			password:      testStrongPassword,
 			existingUser:  nil,
 			repoError:     nil,
 			expectedError: false,
@@ -512,7 +532,7 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 		{
 			name:          "Existing username",
 			username:      "existinguser",
-			password:      "password123",
+			password:      testStrongPassword,
 			existingUser:  buildCRMUserWithIDAndUsername("123e4567-e89b-12d3-a456-426614174000", "existinguser"),
 			repoError:     nil,
 			expectedError: true,
@@ -521,7 +541,7 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 		{
 			name:          "Database error on creation",
 			username:      "erroruser",
-			password:      "password123",
+			password:      testStrongPassword,
 			existingUser:  nil,
 			repoError:     errors.New("database connection failed"),
 			expectedError: true,
@@ -530,7 +550,7 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 		{
 			name:          "Short username",
 			username:      "ab",
-			password:      "password123",
+			password:      testStrongPassword,
 			existingUser:  nil,
 			repoError:     nil,
 			expectedError: false,
@@ -542,8 +562,9 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 			password:      "123",
 			existingUser:  nil,
 			repoError:     nil,
-			expectedError: false,
-			description:   "Service doesn't validate password length",
+			weakPassword:  true,
+			expectedError: true,
+			description:   "Password policy rejects short passwords",
 		},
 	}
 
@@ -556,8 +577,7 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-			jwtSecret := "test-secret"
-			service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 			ctx := context.Background()
 
 			req := buildCreateCRMUserRequestWithUsernameAndPassword(tc.username, tc.password)
@@ -568,7 +588,9 @@ func TestCRMUserService_CreateUser_BusinessLogic(t *testing.T) {
 			} else {
 				mockCRMUserRepo.EXPECT().GetByUsername(ctx, tc.username).Return(nil, gorm.ErrRecordNotFound)
 
-				if tc.repoError != nil {
+				if tc.weakPassword {
+					// Rejected before anything is written
+				} else if tc.repoError != nil {
 					mockCRMUserRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, tc.repoError)
 				} else {
 					expectedUser := buildCRMUserWithID("123e4567-e89b-12d3-a456-426614174000")
@@ -660,8 +682,7 @@ func TestCRMUserService_Login_BusinessLogic(t *testing.T) {
 			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-			jwtSecret := "test-secret"
-			service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 			ctx := context.Background()
 
 			req := buildCRMLoginRequestWithUsernameAndPassword(tc.username, tc.password)
@@ -672,6 +693,9 @@ func TestCRMUserService_Login_BusinessLogic(t *testing.T) {
 			} else {
 				mockCRMUserRepo.EXPECT().GetByUsername(ctx, tc.username).Return(nil, tc.repoError)
 			}
+			if !tc.expectedError {
+				mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			}
 
 			// Act
 			result, err := service.Login(ctx, req)
@@ -698,8 +722,7 @@ func BenchmarkCRMUserService_CreateUser(b *testing.B) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 	req := buildCreateCRMUserRequest()
 	expectedUser := buildCRMUserWithID("123e4567-e89b-12d3-a456-426614174000")
@@ -727,8 +750,7 @@ func TestCRMUserService_CreateUser_Concurrent(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx := context.Background()
 
 	const numGoroutines = 10
@@ -752,7 +774,7 @@ func TestCRMUserService_CreateUser_Concurrent(t *testing.T) {
 	// Act
 	for i := 0; i < numGoroutines; i++ {
 		go func(id int) {
-			req := buildCreateCRMUserRequestWithUsernameAndPassword(fmt.Sprintf("user%d", id), "password123")
+			req := buildCreateCRMUserRequestWithUsernameAndPassword(fmt.Sprintf("user%d", id), testStrongPassword)
 			result, err := service.CreateUser(ctx, req)
 			results <- result
 			errors <- err
@@ -777,8 +799,7 @@ func TestCRMUserService_Timeout(t *testing.T) {
 	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	jwtSecret := "test-secret"
-	service := NewCRMUserService(mockCRMUserRepo, mockLogger, jwtSecret)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
 	defer cancel()
 
@@ -795,3 +816,1124 @@ func TestCRMUserService_Timeout(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+func buildCRMRoles() []crm_user.Role {
+	return []crm_user.Role{
+		{Name: crm_user.RoleAdmin, Permissions: []string{crm_user.PermissionCRMUsersManage}},
+		{Name: crm_user.RoleSupport, Permissions: []string{crm_user.PermissionAppUsersRead}},
+		{Name: crm_user.RoleViewer, Permissions: []string{crm_user.PermissionAppUsersRead}},
+	}
+}
+
+func TestCRMUserService_Login_TokenCarriesRoles(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	req := buildCRMLoginRequest()
+	existingUser := buildCRMUser()
+	existingUser.Roles = []string{crm_user.RoleSupport}
+	existingUser.Permissions = []string{crm_user.PermissionAppUsersRead, crm_user.PermissionUserLimitWrite}
+
+	mockCRMUserRepo.EXPECT().GetByUsername(ctx, req.Username).Return(existingUser, nil)
+	mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+
+	// Act
+	result, err := service.Login(ctx, req)
+	require.NoError(t, err)
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(ctx, gomock.Any()).Return(false, nil)
+	claims, err := service.ValidateToken(ctx, result.Token)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{crm_user.RoleSupport}, claims.Roles)
+	assert.True(t, claims.HasPermission(crm_user.PermissionUserLimitWrite))
+	assert.False(t, claims.HasPermission(crm_user.PermissionCRMUsersManage))
+}
+
+func TestCRMUserService_CreateUser_UnknownRole(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	req := buildCreateCRMUserRequest()
+	req.Roles = []string{"superuser"}
+
+	mockCRMUserRepo.EXPECT().GetByUsername(ctx, req.Username).Return(nil, gorm.ErrRecordNotFound)
+	mockCRMUserRepo.EXPECT().ListRoles(ctx).Return(buildCRMRoles(), nil)
+
+	// Act
+	result, err := service.CreateUser(ctx, req)
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrUnknownCRMRole)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_AssignRoles_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	actorID := uuid.New()
+	target := buildCRMUser()
+	target.ID = uuid.New()
+	target.Roles = []string{crm_user.RoleViewer}
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, target.ID.String()).Return(target, nil).Times(2)
+	mockCRMUserRepo.EXPECT().ListRoles(ctx).Return(buildCRMRoles(), nil)
+	mockCRMUserRepo.EXPECT().SetRoles(ctx, target.ID, []string{crm_user.RoleSupport}, &actorID).Return(nil)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	result, err := service.AssignRoles(ctx, actorID, target.ID.String(), []string{crm_user.RoleSupport, crm_user.RoleSupport})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, target.ID, result.ID)
+}
+
+func TestCRMUserService_AssignRoles_LastAdmin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	admin := buildCRMUser()
+	admin.ID = uuid.New()
+	admin.Roles = []string{crm_user.RoleAdmin}
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, admin.ID.String()).Return(admin, nil)
+	mockCRMUserRepo.EXPECT().ListRoles(ctx).Return(buildCRMRoles(), nil)
+	mockCRMUserRepo.EXPECT().CountByRole(ctx, crm_user.RoleAdmin).Return(int64(1), nil)
+
+	// Act
+	result, err := service.AssignRoles(ctx, admin.ID, admin.ID.String(), []string{crm_user.RoleViewer})
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrLastCRMAdmin)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_BootstrapAdmin(t *testing.T) {
+	tests := []struct {
+		name           string
+		configured     string
+		token          string
+		existingAdmins int64
+		wantErr        error
+	}{
+		{"disabled without a configured token", "", "anything", 0, shared.ErrCRMBootstrapClosed},
+		{"wrong token", "bootstrap-secret", "guess", 0, shared.ErrForbidden},
+		{"admin already exists", "bootstrap-secret", "bootstrap-secret", 1, shared.ErrCRMBootstrapClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+			mockCRMUserRepo.EXPECT().CountByRole(gomock.Any(), crm_user.RoleAdmin).Return(tt.existingAdmins, nil).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), tt.configured, false)
+			req := &crm_user.BootstrapAdminRequest{Username: "admin", Password: "password123", BootstrapToken: tt.token}
+
+			// Act
+			result, err := service.BootstrapAdmin(context.Background(), req)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestCRMUserService_BootstrapAdmin_CreatesFirstAdmin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "bootstrap-secret", false)
+	ctx := context.Background()
+	req := &crm_user.BootstrapAdminRequest{Username: "admin", Password: testStrongPassword, BootstrapToken: "bootstrap-secret"}
+	adminID := uuid.New()
+
+	mockCRMUserRepo.EXPECT().CountByRole(ctx, crm_user.RoleAdmin).Return(int64(0), nil)
+	mockCRMUserRepo.EXPECT().GetByUsername(ctx, "admin").Return(nil, shared.ErrCRMUserNotFound)
+	mockCRMUserRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *crm_user.CRMUser) (*crm_user.CRMUser, error) {
+			assert.True(t, u.CheckPassword(testStrongPassword))
+			u.ID = adminID
+			return u, nil
+		})
+	mockCRMUserRepo.EXPECT().SetRoles(ctx, adminID, []string{crm_user.RoleAdmin}, nil).Return(nil)
+	mockCRMUserRepo.EXPECT().GetByID(ctx, adminID.String()).Return(&crm_user.CRMUser{Username: "admin", Roles: []string{crm_user.RoleAdmin}}, nil)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	result, err := service.BootstrapAdmin(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{crm_user.RoleAdmin}, result.Roles)
+}
+
+func TestCRMUserService_Login_DisabledUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	disabledAt := time.Now()
+	existing := buildCRMUser()
+	existing.DisabledAt = &disabledAt
+
+	mockCRMUserRepo.EXPECT().GetByUsername(ctx, "testuser").Return(existing, nil)
+
+	// Act
+	result, err := service.Login(ctx, buildCRMLoginRequest())
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrCRMUserDisabled)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_Authenticate(t *testing.T) {
+	disabledAt := time.Now()
+
+	tests := []struct {
+		name           string
+		sessionVersion int
+		disabledAt     *time.Time
+		wantErr        error
+	}{
+		{"current session", 0, nil, nil},
+		{"password changed since login", 1, nil, shared.ErrCRMSessionRevoked},
+		{"user disabled", 1, &disabledAt, shared.ErrCRMUserDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			ctx := context.Background()
+			issued := buildCRMUser()
+			issued.ID = uuid.New()
+			token, _, err := service.generateJWT(issued, time.Now())
+			require.NoError(t, err)
+			mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(ctx, gomock.Any()).Return(false, nil)
+
+			current := *issued
+			current.SessionVersion = tt.sessionVersion
+			current.DisabledAt = tt.disabledAt
+			mockCRMUserRepo.EXPECT().GetByID(ctx, issued.ID.String()).Return(&current, nil)
+
+			// Act
+			user, claims, err := service.Authenticate(ctx, token)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, user)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, issued.ID, user.ID)
+			assert.Equal(t, issued.ID, claims.UserID)
+		})
+	}
+}
+
+func TestCRMUserService_SetDisabled(t *testing.T) {
+	actorID := uuid.New()
+
+	tests := []struct {
+		name    string
+		self    bool
+		roles   []string
+		admins  int64
+		wantErr error
+	}{
+		{"disables another user", false, []string{crm_user.RoleSupport}, 0, nil},
+		{"cannot disable self", true, []string{crm_user.RoleAdmin}, 2, shared.ErrForbidden},
+		{"cannot disable last admin", false, []string{crm_user.RoleAdmin}, 1, shared.ErrLastCRMAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			ctx := context.Background()
+			target := buildCRMUser()
+			target.ID = uuid.New()
+			if tt.self {
+				target.ID = actorID
+			}
+			target.Roles = tt.roles
+
+			mockCRMUserRepo.EXPECT().GetByID(ctx, target.ID.String()).Return(target, nil)
+			mockCRMUserRepo.EXPECT().CountByRole(ctx, crm_user.RoleAdmin).Return(tt.admins, nil).AnyTimes()
+			if tt.wantErr == nil {
+				mockCRMUserRepo.EXPECT().
+					UpdateAndRevokeSessions(ctx, target, []string{crm_user.ColumnDisabledAt}).
+					DoAndReturn(bumpSessionVersion)
+			}
+
+			// Act
+			result, err := service.SetDisabled(ctx, actorID, target.ID.String(), true)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, result.IsDisabled())
+			assert.Equal(t, 1, result.SessionVersion)
+		})
+	}
+}
+
+func TestCRMUserService_ChangePassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+	mockCRMUserRepo.EXPECT().UpdateAndRevokeSessions(ctx, user, crm_user.PasswordColumns).DoAndReturn(bumpSessionVersion)
+	mockCRMUserRepo.EXPECT().
+		CreateRefreshToken(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *crm_user.RefreshToken) error {
+			assert.Equal(t, 1, token.SessionVersion)
+			return nil
+		})
+
+	// Act
+	result, err := service.ChangePassword(ctx, user.ID.String(), &crm_user.ChangePasswordRequest{
+		CurrentPassword: "password123",
+		NewPassword:     testStrongPassword,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, user.CheckPassword(testStrongPassword))
+	assert.Equal(t, 1, user.SessionVersion)
+	assert.NotNil(t, user.PasswordChangedAt)
+
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(ctx, gomock.Any()).Return(false, nil)
+	claims, err := service.ValidateToken(ctx, result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claims.SessionVersion)
+}
+
+func TestCRMUserService_ChangePassword_DisabledMeanwhile(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+	mockCRMUserRepo.EXPECT().
+		UpdateAndRevokeSessions(ctx, user, crm_user.PasswordColumns).
+		DoAndReturn(func(_ context.Context, user *crm_user.CRMUser, _ []string) (*crm_user.CRMUser, error) {
+			// An admin disabled the user while the new password was hashed
+			disabledAt := time.Now()
+			user.DisabledAt = &disabledAt
+			user.SessionVersion += 2
+			return user, nil
+		})
+
+	// Act
+	result, err := service.ChangePassword(ctx, user.ID.String(), &crm_user.ChangePasswordRequest{
+		CurrentPassword: "password123",
+		NewPassword:     testStrongPassword,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrCRMUserDisabled)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+
+	// Act
+	result, err := service.ChangePassword(ctx, user.ID.String(), &crm_user.ChangePasswordRequest{
+		CurrentPassword: "not-the-password",
+		NewPassword:     testStrongPassword,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrInvalidPassword)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_ResetPassword(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	usedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		reset     *crm_user.PasswordReset
+		consumed  bool
+		wantErr   error
+		wantWrite bool
+	}{
+		{"redeems a usable token", &crm_user.PasswordReset{ExpiresAt: now.Add(time.Hour)}, true, nil, true},
+		{"unknown token", nil, false, shared.ErrInvalidResetToken, false},
+		{"expired token", &crm_user.PasswordReset{ExpiresAt: now.Add(-time.Second)}, false, shared.ErrInvalidResetToken, false},
+		{"used token", &crm_user.PasswordReset{ExpiresAt: now.Add(time.Hour), UsedAt: &usedAt}, false, shared.ErrInvalidResetToken, false},
+		{"redeemed concurrently", &crm_user.PasswordReset{ExpiresAt: now.Add(time.Hour)}, false, shared.ErrInvalidResetToken, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			user := buildCRMUser()
+			user.ID = uuid.New()
+
+			mockCRMUserRepo.EXPECT().GetPasswordResetByTokenHash(ctx, gomock.Any()).Return(tt.reset, nil)
+			if tt.reset != nil && tt.reset.IsUsable(now) {
+				tt.reset.CRMUserID = user.ID
+				mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+				mockCRMUserRepo.EXPECT().ConsumePasswordReset(ctx, tt.reset.ID, now).Return(tt.consumed, nil)
+			}
+			if tt.wantWrite {
+				mockCRMUserRepo.EXPECT().UpdateAndRevokeSessions(ctx, user, crm_user.PasswordColumns).DoAndReturn(bumpSessionVersion)
+			}
+
+			// Act
+			err := service.ResetPassword(ctx, &crm_user.ResetPasswordRequest{Token: "reset-token", NewPassword: testStrongPassword})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, user.CheckPassword(testStrongPassword))
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, user.CheckPassword(testStrongPassword))
+		})
+	}
+}
+
+func TestCRMUserService_ValidateToken_KeyRotation(t *testing.T) {
+	oldSecret := "old-secret-of-at-least-32-bytes!"
+	newSecret := "new-secret-of-at-least-32-bytes!"
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	// Tokens signed before the rotation are issued by a service whose active key is "old"
+	before, err := crm_user.NewKeyRing("old", []crm_user.SigningKey{{ID: "old", Secret: oldSecret}})
+	require.NoError(t, err)
+	rotated, err := crm_user.NewKeyRing("new", []crm_user.SigningKey{{ID: "old", Secret: oldSecret}, {ID: "new", Secret: newSecret}})
+	require.NoError(t, err)
+	retired, err := crm_user.NewKeyRing("new", []crm_user.SigningKey{{ID: "new", Secret: newSecret}})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	oldToken, _, err := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, before, "", false).generateJWT(user, time.Now())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		keys    *crm_user.KeyRing
+		wantErr bool
+	}{
+		{"old key still listed", rotated, false},
+		{"old key removed", retired, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, tt.keys, "", false)
+
+			claims, err := service.ValidateToken(context.Background(), oldToken)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, claims)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+		})
+	}
+}
+
+func TestCRMUserService_ValidateToken_Revoked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+	token, _, err := service.generateJWT(user, time.Now())
+	require.NoError(t, err)
+
+	mockCRMUserRepo.EXPECT().IsAccessTokenRevoked(ctx, gomock.Any()).Return(true, nil)
+
+	// Act
+	claims, err := service.ValidateToken(ctx, token)
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrCRMTokenRevoked)
+	assert.Nil(t, claims)
+}
+
+func TestCRMUserService_Refresh(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := now.Add(-time.Minute)
+	replacedBy := uuid.New()
+	familyID := uuid.New()
+
+	tests := []struct {
+		name           string
+		current        *crm_user.RefreshToken
+		sessionVersion int
+		rotated        bool
+		wantErr        error
+		wantFamilyKill bool
+	}{
+		{
+			name:    "rotates an active token",
+			current: &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(time.Hour)},
+			rotated: true,
+		},
+		{
+			name:    "unknown token",
+			wantErr: shared.ErrInvalidRefreshToken,
+		},
+		{
+			name:           "reused token revokes the family",
+			current:        &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt, ReplacedBy: &replacedBy},
+			wantErr:        shared.ErrInvalidRefreshToken,
+			wantFamilyKill: true,
+		},
+		{
+			name:    "token revoked by logout",
+			current: &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+			wantErr: shared.ErrInvalidRefreshToken,
+		},
+		{
+			name:    "expired token",
+			current: &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(-time.Second)},
+			wantErr: shared.ErrInvalidRefreshToken,
+		},
+		{
+			name:           "password changed since login",
+			current:        &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(time.Hour)},
+			sessionVersion: 1,
+			wantErr:        shared.ErrInvalidRefreshToken,
+		},
+		{
+			name:    "rotated concurrently",
+			current: &crm_user.RefreshToken{FamilyID: familyID, ExpiresAt: now.Add(time.Hour)},
+			rotated: false,
+			wantErr: shared.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			user := buildCRMUser()
+			user.ID = uuid.New()
+			user.SessionVersion = tt.sessionVersion
+
+			mockCRMUserRepo.EXPECT().GetRefreshTokenByHash(ctx, utils.HashString("refresh-token")).Return(tt.current, nil)
+			if tt.wantFamilyKill {
+				mockCRMUserRepo.EXPECT().RevokeRefreshTokenFamily(ctx, familyID, now).Return(nil)
+			}
+			if tt.current != nil && tt.current.IsActive(now) {
+				tt.current.CRMUserID = user.ID
+				mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+			}
+			if tt.current != nil && tt.current.IsActive(now) && tt.sessionVersion == 0 {
+				mockCRMUserRepo.EXPECT().
+					RotateRefreshToken(ctx, tt.current.ID, gomock.Any(), now).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, next *crm_user.RefreshToken, _ time.Time) (bool, error) {
+						assert.Equal(t, familyID, next.FamilyID)
+						assert.Equal(t, user.ID, next.CRMUserID)
+						return tt.rotated, nil
+					})
+			}
+
+			// Act
+			result, err := service.Refresh(ctx, &crm_user.RefreshTokenRequest{RefreshToken: "refresh-token"})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, result.Token)
+			assert.NotEqual(t, "refresh-token", result.RefreshToken)
+			assert.Equal(t, now.Add(crm_user.AccessTokenTTL), *result.ExpiresAt)
+		})
+	}
+}
+
+func TestCRMUserService_Logout(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	familyID := uuid.New()
+
+	tests := []struct {
+		name           string
+		refreshToken   string
+		refresh        *crm_user.RefreshToken
+		wantFamilyKill bool
+	}{
+		{"access token only", "", nil, false},
+		{"own refresh token", "refresh-token", &crm_user.RefreshToken{CRMUserID: userID, FamilyID: familyID}, true},
+		{"another user's refresh token", "refresh-token", &crm_user.RefreshToken{CRMUserID: uuid.New(), FamilyID: familyID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			claims := &crm_user.JWTClaims{ID: "token-id", UserID: userID, ExpiresAt: now.Add(crm_user.AccessTokenTTL)}
+
+			mockCRMUserRepo.EXPECT().RevokeAccessToken(ctx, &crm_user.RevokedAccessToken{
+				JTI:       "token-id",
+				CRMUserID: userID,
+				ExpiresAt: claims.ExpiresAt,
+				RevokedAt: now,
+			}).Return(nil)
+			if tt.refreshToken != "" {
+				mockCRMUserRepo.EXPECT().GetRefreshTokenByHash(ctx, utils.HashString(tt.refreshToken)).Return(tt.refresh, nil)
+			}
+			if tt.wantFamilyKill {
+				mockCRMUserRepo.EXPECT().RevokeRefreshTokenFamily(ctx, familyID, now).Return(nil)
+			}
+
+			// Act
+			err := service.Logout(ctx, claims, tt.refreshToken)
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// testTOTPSecret is a fixed base32 secret for two-factor tests
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func buildTwoFactorCRMUser() *crm_user.CRMUser {
+	enabledAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := buildCRMUser()
+	user.ID = uuid.New()
+	user.TOTPSecret = testTOTPSecret
+	user.TOTPEnabledAt = &enabledAt
+	return user
+}
+
+func TestCRMUserService_Login_TwoFactorChallenge(t *testing.T) {
+	tests := []struct {
+		name             string
+		enrolled         bool
+		requireTwoFactor bool
+		wantStep         string
+	}{
+		{"enrolled user must verify", true, false, crm_user.TwoFactorStepVerify},
+		{"unenrolled user must enroll when required", false, true, crm_user.TwoFactorStepEnroll},
+		{"unenrolled user logs in when optional", false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", tt.requireTwoFactor)
+			ctx := context.Background()
+			user := buildCRMUser()
+			user.ID = uuid.New()
+			if tt.enrolled {
+				user = buildTwoFactorCRMUser()
+			}
+
+			mockCRMUserRepo.EXPECT().GetByUsername(ctx, "testuser").Return(user, nil)
+			if tt.wantStep == "" {
+				mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			// Act
+			result, err := service.Login(ctx, buildCRMLoginRequest())
+
+			// Assert
+			require.NoError(t, err)
+			if tt.wantStep == "" {
+				assert.Nil(t, result.TwoFactor)
+				assert.NotEmpty(t, result.Token)
+				return
+			}
+			require.NotNil(t, result.TwoFactor)
+			assert.Equal(t, tt.wantStep, result.TwoFactor.Step)
+			assert.NotEmpty(t, result.TwoFactor.ChallengeToken)
+			assert.Empty(t, result.Token)
+			assert.Empty(t, result.RefreshToken)
+
+			// The challenge token is not an access token
+			claims, err := service.ValidateToken(ctx, result.TwoFactor.ChallengeToken)
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		})
+	}
+}
+
+func TestCRMUserService_VerifyTwoFactorLogin(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := crm_user.TOTPCode(testTOTPSecret, crm_user.TOTPStep(now))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		code         string
+		recoveryCode string
+		stepRecorded bool
+		consumed     bool
+		wantErr      error
+	}{
+		{name: "valid code", code: code, stepRecorded: true},
+		{name: "wrong code", code: "000000", wantErr: shared.ErrInvalidTwoFactorCode},
+		{name: "code used concurrently", code: code, stepRecorded: false, wantErr: shared.ErrInvalidTwoFactorCode},
+		{name: "valid recovery code", recoveryCode: "ABCDE-FGHJK", consumed: true},
+		{name: "unknown recovery code", recoveryCode: "abcde-fghjk", consumed: false, wantErr: shared.ErrInvalidTwoFactorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			user := buildTwoFactorCRMUser()
+			challenge, err := service.challenge(user, crm_user.TwoFactorStepVerify)
+			require.NoError(t, err)
+
+			mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+			if tt.code == code {
+				mockCRMUserRepo.EXPECT().RecordTOTPStep(ctx, user.ID, crm_user.TOTPStep(now)).Return(tt.stepRecorded, nil)
+			}
+			if tt.recoveryCode != "" {
+				mockCRMUserRepo.EXPECT().ConsumeRecoveryCode(ctx, user.ID, utils.HashString("abcdefghjk"), now).Return(tt.consumed, nil)
+			}
+			if tt.wantErr == nil {
+				mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			// Act
+			result, err := service.VerifyTwoFactorLogin(ctx, &crm_user.TwoFactorLoginRequest{
+				ChallengeToken: challenge.TwoFactor.ChallengeToken,
+				Code:           tt.code,
+				RecoveryCode:   tt.recoveryCode,
+			})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, result.Token)
+		})
+	}
+}
+
+func TestCRMUserService_VerifyTwoFactorLogin_InvalidChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildTwoFactorCRMUser()
+
+	enroll, err := service.challenge(user, crm_user.TwoFactorStepEnroll)
+	require.NoError(t, err)
+	access, _, err := service.generateJWT(user, time.Now())
+	require.NoError(t, err)
+
+	revoked := *user
+	revoked.SessionVersion = 1
+	verify, err := service.challenge(user, crm_user.TwoFactorStepVerify)
+	require.NoError(t, err)
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(&revoked, nil)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"enroll challenge used to verify", enroll.TwoFactor.ChallengeToken},
+		{"access token used as challenge", access},
+		{"garbage", "not-a-token"},
+		{"two-factor reset since password step", verify.TwoFactor.ChallengeToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.VerifyTwoFactorLogin(ctx, &crm_user.TwoFactorLoginRequest{ChallengeToken: tt.token, Code: "123456"})
+
+			assert.ErrorIs(t, err, shared.ErrInvalidTwoFactorChallenge)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestCRMUserService_EnableTwoFactor(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	now := time.Unix(1234567890, 0)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", false)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	mockCRMUserRepo.EXPECT().Update(ctx, user, []string{crm_user.ColumnTOTPSecret}).Return(user, nil)
+	mockCRMUserRepo.EXPECT().Update(ctx, user, []string{crm_user.ColumnTOTPEnabledAt}).Return(user, nil)
+	setup, err := service.SetupTwoFactor(ctx, user)
+	require.NoError(t, err)
+	assert.Contains(t, setup.ProvisioningURI, "secret="+setup.Secret)
+	assert.Equal(t, []byte("\x89PNG"), setup.QRCodePNG[:4])
+	assert.False(t, user.IsTwoFactorEnabled())
+
+	code, err := crm_user.TOTPCode(setup.Secret, crm_user.TOTPStep(now))
+	require.NoError(t, err)
+	mockCRMUserRepo.EXPECT().RecordTOTPStep(ctx, user.ID, crm_user.TOTPStep(now)).Return(true, nil)
+
+	var stored []crm_user.RecoveryCode
+	mockCRMUserRepo.EXPECT().
+		ReplaceRecoveryCodes(ctx, user.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, codes []crm_user.RecoveryCode) error {
+			stored = codes
+			return nil
+		})
+
+	// Act
+	codes, err := service.EnableTwoFactor(ctx, user, code)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, user.IsTwoFactorEnabled())
+	require.Len(t, codes, crm_user.RecoveryCodeCount)
+	require.Len(t, stored, crm_user.RecoveryCodeCount)
+	assert.Equal(t, utils.HashString(crm_user.NormalizeRecoveryCode(codes[0])), stored[0].CodeHash)
+	assert.NotContains(t, stored[0].CodeHash, codes[0])
+}
+
+func TestCRMUserService_DisableTwoFactor_Required(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", true)
+
+	err := service.DisableTwoFactor(context.Background(), buildTwoFactorCRMUser(), &crm_user.DisableTwoFactorRequest{Password: "password123", Code: "123456"})
+
+	assert.ErrorIs(t, err, shared.ErrForbidden)
+}
+
+func TestCRMUserService_ResetTwoFactor(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+	service := NewCRMUserService(mockCRMUserRepo, allowCredentialAttempts(ctrl), mockLogger, buildCRMKeyRing(), "", true)
+	ctx := context.Background()
+	user := buildTwoFactorCRMUser()
+	adminID := uuid.New()
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+	mockCRMUserRepo.EXPECT().UpdateAndRevokeSessions(ctx, user, crm_user.TwoFactorColumns).DoAndReturn(bumpSessionVersion)
+	mockCRMUserRepo.EXPECT().ReplaceRecoveryCodes(ctx, user.ID, nil).Return(nil)
+
+	// Act
+	result, err := service.ResetTwoFactor(ctx, adminID, user.ID.String())
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, result.IsTwoFactorEnabled())
+	assert.Empty(t, result.TOTPSecret)
+	assert.Equal(t, 1, result.SessionVersion)
+}
+
+func TestCRMUserService_Login_CredentialAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		password    string
+		userExists  bool
+		wantErr     error
+		wantFailure bool
+	}{
+		{name: "unknown username", username: "nobody", password: "password123", wantErr: shared.ErrInvalidCredentials, wantFailure: true},
+		{name: "wrong password", username: "testuser", password: "wrong", userExists: true, wantErr: shared.ErrInvalidCredentials, wantFailure: true},
+		{name: "success clears failures", username: "testuser", password: "password123", userExists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+			mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+			service := NewCRMUserService(mockCRMUserRepo, mockAttempts, mockLogger, buildCRMKeyRing(), "", false)
+			ctx := context.Background()
+			req := buildCRMLoginRequestWithUsernameAndPassword(tt.username, tt.password)
+			req.ClientIP = "10.0.0.1"
+
+			mockAttempts.EXPECT().Check(ctx, credential_attempt.ScopeCRMLogin, tt.username, "10.0.0.1").Return(nil)
+			if tt.userExists {
+				mockCRMUserRepo.EXPECT().GetByUsername(ctx, tt.username).Return(buildCRMUser(), nil)
+			} else {
+				mockCRMUserRepo.EXPECT().GetByUsername(ctx, tt.username).Return(nil, errors.New("record not found"))
+			}
+			if tt.wantFailure {
+				mockAttempts.EXPECT().RecordFailure(ctx, credential_attempt.ScopeCRMLogin, tt.username, "10.0.0.1").Return(nil)
+			} else {
+				mockAttempts.EXPECT().RecordSuccess(ctx, credential_attempt.ScopeCRMLogin, tt.username).Return(nil)
+				mockCRMUserRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			// Act
+			result, err := service.Login(ctx, req)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, result.Token)
+		})
+	}
+}
+
+func TestCRMUserService_Login_Blocked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, mockAttempts, mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+
+	mockAttempts.EXPECT().Check(ctx, credential_attempt.ScopeCRMLogin, "testuser", "").
+		Return(&credential_attempt.BlockedError{RetryAfter: 30 * time.Second})
+
+	// Act
+	result, err := service.Login(ctx, buildCRMLoginRequest())
+
+	// Assert
+	var blocked *credential_attempt.BlockedError
+	assert.ErrorAs(t, err, &blocked)
+	assert.Equal(t, 30*time.Second, blocked.RetryAfter)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_VerifyTwoFactorLogin_CountsWrongCodes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewCRMUserService(mockCRMUserRepo, mockAttempts, mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildTwoFactorCRMUser()
+	challenge, err := service.challenge(user, crm_user.TwoFactorStepVerify)
+	require.NoError(t, err)
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+	mockAttempts.EXPECT().Check(ctx, credential_attempt.ScopeCRMLogin, user.Username, "10.0.0.1").Return(nil)
+	mockAttempts.EXPECT().RecordFailure(ctx, credential_attempt.ScopeCRMLogin, user.Username, "10.0.0.1").Return(nil)
+
+	// Act
+	result, err := service.VerifyTwoFactorLogin(ctx, &crm_user.TwoFactorLoginRequest{
+		ChallengeToken: challenge.TwoFactor.ChallengeToken,
+		Code:           "000000",
+		ClientIP:       "10.0.0.1",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrInvalidTwoFactorCode)
+	assert.Nil(t, result)
+}
+
+func TestCRMUserService_UnlockUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCRMUserRepo := mock_ports.NewMockCRMUserRepositoryInterface(ctrl)
+	mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	service := NewCRMUserService(mockCRMUserRepo, mockAttempts, mockLogger, buildCRMKeyRing(), "", false)
+	ctx := context.Background()
+	user := buildCRMUser()
+	user.ID = uuid.New()
+
+	mockCRMUserRepo.EXPECT().GetByID(ctx, user.ID.String()).Return(user, nil)
+	mockAttempts.EXPECT().Unlock(ctx, credential_attempt.ScopeCRMLogin, "testuser").Return(true, nil)
+
+	// Act
+	result, err := service.UnlockUser(ctx, uuid.New(), user.ID.String())
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Unlocked)
+}
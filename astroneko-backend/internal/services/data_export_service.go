@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/data_export"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+	dataExportPorts "astroneko-backend/internal/core/ports/data_export"
+	guestUsagePorts "astroneko-backend/internal/core/ports/guest_usage"
+	historyPorts "astroneko-backend/internal/core/ports/history"
+	personPorts "astroneko-backend/internal/core/ports/person"
+	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
+	referralLinkPorts "astroneko-backend/internal/core/ports/referral_link"
+	referralRewardPorts "astroneko-backend/internal/core/ports/referral_reward"
+	userPorts "astroneko-backend/internal/core/ports/user"
+	"astroneko-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dataExportInterval is how often the worker looks for queued exports when nothing wakes it
+	dataExportInterval = time.Minute
+	// dataExportBatchSize caps the exports built per run
+	dataExportBatchSize = 5
+	// dataExportStaleAfter is when a job left in processing by a stopped instance is picked up again
+	dataExportStaleAfter = 30 * time.Minute
+	// dataExportListLimit caps the jobs returned by ListExports
+	dataExportListLimit = 10
+	// dataExportDownloadPath is the signed download route, relative to the API host
+	dataExportDownloadPath = "/v1/api/me/export/%s/download?expires=%d&signature=%s"
+)
+
+// DataExportService builds ZIP copies of a user's personal data in the background
+// and hands them out through short-lived signed links
+type DataExportService struct {
+	exportRepo       dataExportPorts.RepositoryInterface
+	userRepo         userPorts.RepositoryInterface
+	historyRepo      historyPorts.RepositoryInterface
+	personRepo       personPorts.RepositoryInterface
+	referralCodeRepo referralCodePorts.RepositoryInterface
+	rewardRepo       referralRewardPorts.RepositoryInterface
+	linkRepo         referralLinkPorts.RepositoryInterface
+	guestUsageRepo   guestUsagePorts.Repository
+	signingKey       []byte
+	logger           logger.Logger
+	now              func() time.Time
+	wake             chan struct{}
+}
+
+// NewDataExportService creates a new data export service instance.
+// signingKey signs download links and must be shared by every instance.
+func NewDataExportService(
+	exportRepo dataExportPorts.RepositoryInterface,
+	userRepo userPorts.RepositoryInterface,
+	historyRepo historyPorts.RepositoryInterface,
+	personRepo personPorts.RepositoryInterface,
+	referralCodeRepo referralCodePorts.RepositoryInterface,
+	rewardRepo referralRewardPorts.RepositoryInterface,
+	linkRepo referralLinkPorts.RepositoryInterface,
+	guestUsageRepo guestUsagePorts.Repository,
+	signingKey []byte,
+	log logger.Logger,
+) *DataExportService {
+	return &DataExportService{
+		exportRepo:       exportRepo,
+		userRepo:         userRepo,
+		historyRepo:      historyRepo,
+		personRepo:       personRepo,
+		referralCodeRepo: referralCodeRepo,
+		rewardRepo:       rewardRepo,
+		linkRepo:         linkRepo,
+		guestUsageRepo:   guestUsageRepo,
+		signingKey:       signingKey,
+		logger:           log,
+		now:              time.Now,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// RequestExport queues a new export. A job already queued or running is returned
+// instead, and at most data_export.MaxExportsPerWindow jobs are accepted per window.
+// The checks and the insert run under a per-user lock, so concurrent requests cannot
+// all pass the checks.
+func (s *DataExportService) RequestExport(ctx context.Context, u *user.User) (*data_export.ExportJobResponse, error) {
+	var (
+		job    *data_export.ExportJob
+		queued bool
+	)
+	err := s.exportRepo.WithUserLock(ctx, u.ID, func(tx dataExportPorts.RepositoryInterface) error {
+		recent, err := tx.ListByUserID(ctx, u.ID, 1)
+		if err != nil {
+			return err
+		}
+		if len(recent) > 0 && recent[0].IsActive() {
+			job = &recent[0]
+			return nil
+		}
+
+		count, err := tx.CountCreatedSince(ctx, u.ID, s.now().Add(-data_export.RateLimitWindow))
+		if err != nil {
+			return err
+		}
+		if count >= data_export.MaxExportsPerWindow {
+			return shared.ErrExportRateLimited
+		}
+
+		job = &data_export.ExportJob{
+			UserID: u.ID,
+			Status: data_export.StatusPending,
+		}
+		if err := tx.Create(ctx, job); err != nil {
+			s.logger.Error("Failed to queue data export",
+				logger.Field{Key: "module", Value: "data_export_service"},
+				logger.Field{Key: "user_id", Value: u.ID.String()},
+				logger.Field{Key: "error", Value: err.Error()})
+			return err
+		}
+		queued = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !queued {
+		response := job.ToResponse()
+		return &response, nil
+	}
+
+	// Start the worker now rather than at its next tick
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	response := job.ToResponse()
+	return &response, nil
+}
+
+// GetExport returns one of the user's export jobs, with a fresh download link once it is ready
+func (s *DataExportService) GetExport(ctx context.Context, u *user.User, jobID uuid.UUID) (*data_export.ExportJobResponse, error) {
+	job, err := s.exportRepo.GetByID(ctx, u.ID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.toResponse(job)
+	return &response, nil
+}
+
+// ListExports returns the user's most recent export jobs
+func (s *DataExportService) ListExports(ctx context.Context, u *user.User) (*data_export.ListExportJobsResponse, error) {
+	jobs, err := s.exportRepo.ListByUserID(ctx, u.ID, dataExportListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]data_export.ExportJobResponse, 0, len(jobs))
+	for i := range jobs {
+		exports = append(exports, s.toResponse(&jobs[i]))
+	}
+
+	return &data_export.ListExportJobsResponse{Exports: exports}, nil
+}
+
+// OpenDownload verifies a signed link and returns the job with its archive
+func (s *DataExportService) OpenDownload(ctx context.Context, jobID uuid.UUID, expires int64, signature string) (*data_export.ExportJob, error) {
+	now := s.now()
+	if !data_export.VerifyDownload(s.signingKey, jobID, expires, signature, now) {
+		return nil, shared.ErrInvalidDownloadLink
+	}
+
+	job, err := s.exportRepo.GetWithArchive(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsDownloadable(now) || len(job.Archive) == 0 {
+		return nil, shared.ErrExportNotFound
+	}
+
+	return job, nil
+}
+
+// ProcessPendingExports builds every queued export and drops archives past their retention
+func (s *DataExportService) ProcessPendingExports(ctx context.Context) error {
+	now := s.now()
+
+	expired, err := s.exportRepo.ExpireArchives(ctx, now)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		s.logger.Info("Expired data export archives",
+			logger.Field{Key: "module", Value: "data_export_service"},
+			logger.Field{Key: "count", Value: expired})
+	}
+
+	jobs, err := s.exportRepo.ClaimPending(ctx, now, now.Add(-dataExportStaleAfter), dataExportBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var failures int
+	for i := range jobs {
+		if err := s.build(ctx, &jobs[i]); err != nil {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return errors.New("some data exports failed and will be retried")
+	}
+	return nil
+}
+
+// RunExportWorker processes queued exports every minute, or as soon as one is requested,
+// until the context is cancelled
+func (s *DataExportService) RunExportWorker(ctx context.Context) {
+	ticker := time.NewTicker(dataExportInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ProcessPendingExports(ctx); err != nil {
+			s.logger.Error("Data export run incomplete",
+				logger.Field{Key: "module", Value: "data_export_service"},
+				logger.Field{Key: "error", Value: err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// build collects the user's data and stores the finished archive with the job
+func (s *DataExportService) build(ctx context.Context, job *data_export.ExportJob) error {
+	contents, err := s.collect(ctx, job.UserID)
+	if err != nil {
+		s.recordFailure(ctx, job, err)
+		return err
+	}
+
+	archive, err := data_export.BuildArchive(contents)
+	if err != nil {
+		s.recordFailure(ctx, job, err)
+		return err
+	}
+
+	completedAt := s.now()
+	expiresAt := completedAt.Add(data_export.RetentionPeriod)
+	job.Status = data_export.StatusReady
+	job.Archive = archive
+	job.ArchiveSize = int64(len(archive))
+	job.CompletedAt = &completedAt
+	job.ExpiresAt = &expiresAt
+	job.LastError = nil
+
+	if err := s.exportRepo.SaveArchive(ctx, job); err != nil {
+		s.recordFailure(ctx, job, err)
+		return err
+	}
+
+	s.logger.Info("Data export ready",
+		logger.Field{Key: "module", Value: "data_export_service"},
+		logger.Field{Key: "user_id", Value: job.UserID.String()},
+		logger.Field{Key: "export_id", Value: job.ID.String()},
+		logger.Field{Key: "archive_size", Value: job.ArchiveSize})
+
+	return nil
+}
+
+// collect gathers everything the user owns for the archive
+func (s *DataExportService) collect(ctx context.Context, userID uuid.UUID) (*data_export.Contents, error) {
+	u, err := s.userRepo.GetByID(ctx, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	sessions, err := s.historyRepo.GetSessionsByUserID(ctx, userID, "created_at", "asc", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	sessionMessages := make([]data_export.SessionMessages, 0, len(sessions))
+	for _, session := range sessions {
+		messages, err := s.historyRepo.GetMessagesBySessionID(ctx, session.ID, "asc")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load messages of session %s: %w", session.ID, err)
+		}
+		sessionMessages = append(sessionMessages, data_export.SessionMessages{Session: session, Messages: messages})
+	}
+
+	people, err := s.personRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved people: %w", err)
+	}
+
+	referralCodes, err := s.referralCodeRepo.GetUserReferralCodesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral codes: %w", err)
+	}
+
+	redemptions, err := s.referralCodeRepo.GetReferralLogsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral redemptions: %w", err)
+	}
+
+	rewards, err := s.rewardRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral rewards: %w", err)
+	}
+
+	clicks, err := s.linkRepo.ListClicksByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral clicks: %w", err)
+	}
+
+	// Logged-in quota is tracked under the same key GuestOrAuthRateLimit uses
+	quotaUsage, err := s.guestUsageRepo.ListByCompositeKey(ctx, "user_"+u.FirebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quota usage: %w", err)
+	}
+
+	return &data_export.Contents{
+		GeneratedAt:   s.now(),
+		User:          u,
+		Sessions:      sessionMessages,
+		People:        people,
+		ReferralCodes: referralCodes,
+		Redemptions:   redemptions,
+		Rewards:       rewards,
+		Clicks:        clicks,
+		QuotaUsage:    quotaUsage,
+	}, nil
+}
+
+// recordFailure puts the job back in the queue, or marks it failed after data_export.MaxAttempts
+func (s *DataExportService) recordFailure(ctx context.Context, job *data_export.ExportJob, cause error) {
+	s.logger.Error("Failed to build data export",
+		logger.Field{Key: "module", Value: "data_export_service"},
+		logger.Field{Key: "user_id", Value: job.UserID.String()},
+		logger.Field{Key: "export_id", Value: job.ID.String()},
+		logger.Field{Key: "error", Value: cause.Error()})
+
+	message := cause.Error()
+	job.Attempts++
+	job.LastError = &message
+	job.Archive = nil
+	job.Status = data_export.StatusPending
+	if job.Attempts >= data_export.MaxAttempts {
+		job.Status = data_export.StatusFailed
+	}
+
+	if err := s.exportRepo.Update(ctx, job); err != nil {
+		s.logger.Error("Failed to record data export failure",
+			logger.Field{Key: "module", Value: "data_export_service"},
+			logger.Field{Key: "export_id", Value: job.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+}
+
+// toResponse adds a signed download link to ready jobs
+func (s *DataExportService) toResponse(job *data_export.ExportJob) data_export.ExportJobResponse {
+	response := job.ToResponse()
+
+	now := s.now()
+	if !job.IsDownloadable(now) {
+		return response
+	}
+
+	linkExpiresAt := now.Add(data_export.DownloadLinkTTL)
+	if linkExpiresAt.After(*job.ExpiresAt) {
+		linkExpiresAt = *job.ExpiresAt
+	}
+	signature := data_export.SignDownload(s.signingKey, job.ID, linkExpiresAt)
+	response.DownloadURL = fmt.Sprintf(dataExportDownloadPath, job.ID, linkExpiresAt.Unix(), signature)
+	response.DownloadExpiresAt = &linkExpiresAt
+
+	return response
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/data_export"
+	"astroneko-backend/internal/core/domain/guest_usage"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/shared"
+	dataExportPorts "astroneko-backend/internal/core/ports/data_export"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dataExportTestNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type dataExportTestMocks struct {
+	exportRepo       *mock_ports.MockDataExportRepositoryInterface
+	userRepo         *mock_ports.MockUserRepositoryInterface
+	historyRepo      *mock_ports.HistoryRepositoryInterface
+	personRepo       *mock_ports.MockPersonRepositoryInterface
+	referralCodeRepo *mock_ports.ReferralCodeRepositoryInterface
+	rewardRepo       *mock_ports.MockReferralRewardRepositoryInterface
+	linkRepo         *mock_ports.MockReferralLinkRepositoryInterface
+	guestUsageRepo   *mock_ports.MockGuestUsageRepository
+	logger           *mock_logger.MockLoggerInterface
+}
+
+func newTestDataExportService(ctrl *gomock.Controller) (*DataExportService, *dataExportTestMocks) {
+	mocks := &dataExportTestMocks{
+		exportRepo:       mock_ports.NewMockDataExportRepositoryInterface(ctrl),
+		userRepo:         mock_ports.NewMockUserRepositoryInterface(ctrl),
+		historyRepo:      mock_ports.NewHistoryRepositoryInterface(ctrl),
+		personRepo:       mock_ports.NewMockPersonRepositoryInterface(ctrl),
+		referralCodeRepo: mock_ports.NewReferralCodeRepositoryInterface(ctrl),
+		rewardRepo:       mock_ports.NewMockReferralRewardRepositoryInterface(ctrl),
+		linkRepo:         mock_ports.NewMockReferralLinkRepositoryInterface(ctrl),
+		guestUsageRepo:   mock_ports.NewMockGuestUsageRepository(ctrl),
+		logger:           mock_logger.NewMockLoggerInterface(ctrl),
+	}
+
+	service := NewDataExportService(mocks.exportRepo, mocks.userRepo, mocks.historyRepo, mocks.personRepo,
+		mocks.referralCodeRepo, mocks.rewardRepo, mocks.linkRepo, mocks.guestUsageRepo, []byte("test-signing-key"), mocks.logger)
+	service.now = func() time.Time { return dataExportTestNow }
+
+	return service, mocks
+}
+
+func buildTestExportJob(status data_export.Status) *data_export.ExportJob {
+	job := &data_export.ExportJob{
+		UserID: uuid.New(),
+		Status: status,
+	}
+	job.ID = uuid.New()
+	job.CreatedAt = dataExportTestNow.Add(-time.Hour)
+	return job
+}
+
+// expectExportLock runs the locked body against the export repository mock itself, as WithUserLock does
+func expectExportLock(mocks *dataExportTestMocks, userID uuid.UUID) {
+	mocks.exportRepo.EXPECT().WithUserLock(gomock.Any(), userID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, fn func(dataExportPorts.RepositoryInterface) error) error {
+			return fn(mocks.exportRepo)
+		})
+}
+
+func TestDataExportService_RequestExport_QueuesJob(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	expectExportLock(mocks, u.ID)
+	mocks.exportRepo.EXPECT().ListByUserID(ctx, u.ID, 1).Return(nil, nil)
+	mocks.exportRepo.EXPECT().
+		CountCreatedSince(ctx, u.ID, dataExportTestNow.Add(-data_export.RateLimitWindow)).
+		Return(int64(1), nil)
+	mocks.exportRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, job *data_export.ExportJob) error {
+			assert.Equal(t, u.ID, job.UserID)
+			assert.Equal(t, data_export.StatusPending, job.Status)
+			return nil
+		})
+
+	// Act
+	response, err := service.RequestExport(ctx, u)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, data_export.StatusPending, response.Status)
+	assert.Empty(t, response.DownloadURL)
+	assert.Len(t, service.wake, 1)
+}
+
+func TestDataExportService_RequestExport_ReturnsQueuedJob(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	queued := buildTestExportJob(data_export.StatusProcessing)
+
+	expectExportLock(mocks, u.ID)
+	mocks.exportRepo.EXPECT().ListByUserID(ctx, u.ID, 1).Return([]data_export.ExportJob{*queued}, nil)
+
+	// Act
+	response, err := service.RequestExport(ctx, u)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, queued.ID, response.ID)
+	assert.Equal(t, data_export.StatusProcessing, response.Status)
+}
+
+func TestDataExportService_RequestExport_RateLimited(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+
+	expectExportLock(mocks, u.ID)
+	mocks.exportRepo.EXPECT().ListByUserID(ctx, u.ID, 1).Return([]data_export.ExportJob{*buildTestExportJob(data_export.StatusReady)}, nil)
+	mocks.exportRepo.EXPECT().CountCreatedSince(ctx, u.ID, gomock.Any()).Return(int64(data_export.MaxExportsPerWindow), nil)
+
+	// Act
+	response, err := service.RequestExport(ctx, u)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, shared.ErrExportRateLimited)
+}
+
+func TestDataExportService_ProcessPendingExports_BuildsArchive(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	job := buildTestExportJob(data_export.StatusProcessing)
+	job.UserID = u.ID
+	session := history.Session{ID: uuid.New(), UserID: u.ID, HistoryName: "ดูดวง"}
+
+	mocks.logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mocks.exportRepo.EXPECT().ExpireArchives(ctx, dataExportTestNow).Return(int64(0), nil)
+	mocks.exportRepo.EXPECT().
+		ClaimPending(ctx, dataExportTestNow, dataExportTestNow.Add(-dataExportStaleAfter), dataExportBatchSize).
+		Return([]data_export.ExportJob{*job}, nil)
+	mocks.userRepo.EXPECT().GetByID(ctx, u.ID.String()).Return(u, nil)
+	mocks.historyRepo.EXPECT().GetSessionsByUserID(ctx, u.ID, "created_at", "asc", "").Return([]history.Session{session}, nil)
+	mocks.historyRepo.EXPECT().GetMessagesBySessionID(ctx, session.ID, "asc").Return([]history.Message{
+		{ID: uuid.New(), SessionID: session.ID, Role: history.MessageRoleUser, Message: "สวัสดี"},
+	}, nil)
+	mocks.personRepo.EXPECT().ListByUserID(ctx, u.ID).Return([]person.Person{}, nil)
+	mocks.referralCodeRepo.EXPECT().GetUserReferralCodesByUserID(ctx, u.ID).Return([]*referral_code.UserReferralCode{}, nil)
+	mocks.referralCodeRepo.EXPECT().GetReferralLogsByUserID(ctx, u.ID).Return([]*referral_code.ReferralLog{}, nil)
+	mocks.rewardRepo.EXPECT().ListByUserID(ctx, u.ID).Return([]*referral_reward.Reward{}, nil)
+	mocks.linkRepo.EXPECT().ListClicksByUserID(ctx, u.ID).Return([]*referral_link.Click{}, nil)
+	mocks.guestUsageRepo.EXPECT().ListByCompositeKey(ctx, "user_"+u.FirebaseUID).Return([]*guest_usage.GuestAPIUsage{}, nil)
+	mocks.exportRepo.EXPECT().
+		SaveArchive(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, saved *data_export.ExportJob) error {
+			assert.Equal(t, data_export.StatusReady, saved.Status)
+			assert.NotEmpty(t, saved.Archive)
+			assert.Equal(t, int64(len(saved.Archive)), saved.ArchiveSize)
+			require.NotNil(t, saved.ExpiresAt)
+			assert.Equal(t, dataExportTestNow.Add(data_export.RetentionPeriod), *saved.ExpiresAt)
+			return nil
+		})
+
+	// Act
+	err := service.ProcessPendingExports(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestDataExportService_ProcessPendingExports_RequeuesFailedJob(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	job := buildTestExportJob(data_export.StatusProcessing)
+
+	mocks.logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	mocks.exportRepo.EXPECT().ExpireArchives(ctx, dataExportTestNow).Return(int64(0), nil)
+	mocks.exportRepo.EXPECT().ClaimPending(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return([]data_export.ExportJob{*job}, nil)
+	mocks.userRepo.EXPECT().GetByID(ctx, job.UserID.String()).Return(nil, errors.New("connection reset"))
+	mocks.exportRepo.EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, saved *data_export.ExportJob) error {
+			assert.Equal(t, data_export.StatusPending, saved.Status)
+			assert.Equal(t, 1, saved.Attempts)
+			require.NotNil(t, saved.LastError)
+			assert.Contains(t, *saved.LastError, "connection reset")
+			return nil
+		})
+
+	// Act
+	err := service.ProcessPendingExports(ctx)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestDataExportService_GetExport_SignsDownloadLink(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	u := buildTestBirthProfileUser()
+	job := buildTestExportJob(data_export.StatusReady)
+	job.UserID = u.ID
+	expiresAt := dataExportTestNow.Add(24 * time.Hour)
+	job.ExpiresAt = &expiresAt
+
+	mocks.exportRepo.EXPECT().GetByID(ctx, u.ID, job.ID).Return(job, nil)
+
+	// Act
+	response, err := service.GetExport(ctx, u, job.ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, response.DownloadExpiresAt)
+	assert.Equal(t, dataExportTestNow.Add(data_export.DownloadLinkTTL), *response.DownloadExpiresAt)
+	assert.True(t, strings.HasPrefix(response.DownloadURL, "/v1/api/me/export/"+job.ID.String()+"/download?"))
+
+	link, err := url.Parse(response.DownloadURL)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, data_export.VerifyDownload([]byte("test-signing-key"), job.ID, expires, link.Query().Get("signature"), dataExportTestNow))
+}
+
+func TestDataExportService_OpenDownload_RejectsBadSignature(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	expires := dataExportTestNow.Add(data_export.DownloadLinkTTL)
+	signature := data_export.SignDownload([]byte("another-key"), uuid.New(), expires)
+
+	// Act
+	job, err := service.OpenDownload(ctx, uuid.New(), expires.Unix(), signature)
+
+	// Assert
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, shared.ErrInvalidDownloadLink)
+}
+
+func TestDataExportService_OpenDownload_ExpiredArchive(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestDataExportService(ctrl)
+	ctx := context.Background()
+	job := buildTestExportJob(data_export.StatusExpired)
+	expires := dataExportTestNow.Add(data_export.DownloadLinkTTL)
+	signature := data_export.SignDownload([]byte("test-signing-key"), job.ID, expires)
+
+	mocks.exportRepo.EXPECT().GetWithArchive(ctx, job.ID).Return(job, nil)
+
+	// Act
+	opened, err := service.OpenDownload(ctx, job.ID, expires.Unix(), signature)
+
+	// Assert
+	assert.Nil(t, opened)
+	assert.ErrorIs(t, err, shared.ErrExportNotFound)
+}
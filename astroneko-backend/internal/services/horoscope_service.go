@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/astrology"
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/horoscope"
+	"astroneko-backend/internal/core/domain/shared"
+	agentPorts "astroneko-backend/internal/core/ports/agent"
+	horoscopePorts "astroneko-backend/internal/core/ports/horoscope"
+	"astroneko-backend/pkg/logger"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// horoscopeAgentUserID identifies generation requests sent to the agent upstream
+	horoscopeAgentUserID = "system_horoscope"
+
+	// horoscopeOnDemandPastDays limits how far back missing horoscopes are generated on demand
+	horoscopeOnDemandPastDays = 30
+
+	// horoscopeOnDemandFutureDays limits how far ahead horoscopes are generated on demand
+	horoscopeOnDemandFutureDays = 1
+
+	// horoscopeScheduleOffset is the time after local midnight when the daily job runs
+	horoscopeScheduleOffset = 5 * time.Minute
+
+	// horoscopeGenerationTimeout bounds a shared generation, which outlives the request that started it
+	horoscopeGenerationTimeout = 2 * time.Minute
+)
+
+// HoroscopeService generates, stores and serves daily horoscopes per zodiac sign
+type HoroscopeService struct {
+	horoscopeRepo horoscopePorts.RepositoryInterface
+	agentRepo     agentPorts.RepositoryInterface
+	logger        logger.Logger
+	location      *time.Location
+	now           func() time.Time
+
+	generation singleflight.Group
+}
+
+// NewHoroscopeService creates a new horoscope service instance
+func NewHoroscopeService(horoscopeRepo horoscopePorts.RepositoryInterface, agentRepo agentPorts.RepositoryInterface, log logger.Logger) *HoroscopeService {
+	location, err := time.LoadLocation(horoscope.DefaultTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &HoroscopeService{
+		horoscopeRepo: horoscopeRepo,
+		agentRepo:     agentRepo,
+		logger:        log,
+		location:      location,
+		now:           time.Now,
+	}
+}
+
+// Today returns the current horoscope calendar date
+func (s *HoroscopeService) Today() time.Time {
+	now := s.now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetDailyHoroscope serves a stored horoscope for the sign and date.
+// Missing horoscopes within the on-demand window are generated once, even when
+// many requests ask for the same sign and date concurrently.
+func (s *HoroscopeService) GetDailyHoroscope(ctx context.Context, signKey string, date time.Time) (*horoscope.DailyHoroscopeResponse, error) {
+	sign, ok := astrology.SignByKey(signKey)
+	if !ok {
+		return nil, shared.ErrInvalidZodiacSign
+	}
+
+	stored, err := s.horoscopeRepo.GetBySignAndDate(ctx, sign.Key, date)
+	if err != nil {
+		s.logger.Error("Failed to retrieve horoscope",
+			logger.Field{Key: "module", Value: "horoscope_service"},
+			logger.Field{Key: "sign", Value: sign.Key},
+			logger.Field{Key: "date", Value: date.Format(horoscope.DateLayout)},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to retrieve horoscope: %w", err)
+	}
+	if stored != nil {
+		return stored.ToResponse(sign), nil
+	}
+
+	today := s.Today()
+	if date.Before(today.AddDate(0, 0, -horoscopeOnDemandPastDays)) || date.After(today.AddDate(0, 0, horoscopeOnDemandFutureDays)) {
+		return nil, shared.ErrHoroscopeNotFound
+	}
+
+	generated, err := s.generateOnce(ctx, sign, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return generated.ToResponse(sign), nil
+}
+
+// GenerateDailyHoroscopes generates every sign that has no horoscope for the date yet
+func (s *HoroscopeService) GenerateDailyHoroscopes(ctx context.Context, date time.Time) error {
+	existing, err := s.horoscopeRepo.ListByDate(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to list horoscopes: %w", err)
+	}
+
+	generated := make(map[string]bool, len(existing))
+	for _, dailyHoroscope := range existing {
+		generated[dailyHoroscope.Sign] = true
+	}
+
+	var errs []error
+	for _, sign := range astrology.Signs {
+		if generated[sign.Key] {
+			continue
+		}
+		if _, err := s.generateOnce(ctx, sign, date); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sign.Key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunDailySchedule generates today's horoscopes immediately and then shortly after
+// every local midnight until the context is cancelled
+func (s *HoroscopeService) RunDailySchedule(ctx context.Context) {
+	for {
+		today := s.Today()
+		if err := s.GenerateDailyHoroscopes(ctx, today); err != nil {
+			s.logger.Error("Scheduled horoscope generation incomplete",
+				logger.Field{Key: "module", Value: "horoscope_service"},
+				logger.Field{Key: "date", Value: today.Format(horoscope.DateLayout)},
+				logger.Field{Key: "error", Value: err.Error()})
+		} else {
+			s.logger.Info("Scheduled horoscope generation completed",
+				logger.Field{Key: "module", Value: "horoscope_service"},
+				logger.Field{Key: "date", Value: today.Format(horoscope.DateLayout)})
+		}
+
+		timer := time.NewTimer(s.untilNextRun())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// untilNextRun returns the duration until the next scheduled run after local midnight
+func (s *HoroscopeService) untilNextRun() time.Duration {
+	now := s.now().In(s.location)
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location).Add(horoscopeScheduleOffset)
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, s.location).Add(horoscopeScheduleOffset)
+	}
+	return next.Sub(now)
+}
+
+// generateOnce collapses concurrent generations for the same sign and date into one upstream call.
+// The generation runs detached from the caller that started it, so a client that disconnects
+// does not fail everyone waiting on the same horoscope; each caller still stops waiting when
+// its own context ends.
+func (s *HoroscopeService) generateOnce(ctx context.Context, sign astrology.Sign, date time.Time) (*horoscope.DailyHoroscope, error) {
+	key := sign.Key + ":" + date.Format(horoscope.DateLayout)
+
+	flight := s.generation.DoChan(key, func() (interface{}, error) {
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), horoscopeGenerationTimeout)
+		defer cancel()
+
+		// Another instance may have generated it while we were waiting
+		stored, err := s.horoscopeRepo.GetBySignAndDate(flightCtx, sign.Key, date)
+		if err == nil && stored != nil {
+			return stored, nil
+		}
+		return s.generate(flightCtx, sign, date)
+	})
+
+	select {
+	case result := <-flight:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*horoscope.DailyHoroscope), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// generate asks the agent upstream for the Thai and English horoscope and stores them
+func (s *HoroscopeService) generate(ctx context.Context, sign astrology.Sign, date time.Time) (*horoscope.DailyHoroscope, error) {
+	dateText := date.Format(horoscope.DateLayout)
+
+	contentTH, err := s.askAgent(ctx, sign, date, "th",
+		fmt.Sprintf("ช่วยทำนายดวงรายวันของชาวราศี%s (%s) ประจำวันที่ %s ทั้งเรื่องงาน การเงิน ความรัก และสุขภาพ พร้อมคำแนะนำสั้น ๆ", sign.NameTH, sign.NameEN, dateText))
+	if err != nil {
+		return nil, err
+	}
+
+	contentEN, err := s.askAgent(ctx, sign, date, "en",
+		fmt.Sprintf("Please write the daily horoscope for %s for %s in English, covering work, money, love and health with a short piece of advice.", sign.NameEN, dateText))
+	if err != nil {
+		return nil, err
+	}
+
+	dailyHoroscope := &horoscope.DailyHoroscope{
+		Sign:          sign.Key,
+		HoroscopeDate: date,
+		ContentTH:     contentTH,
+		ContentEN:     contentEN,
+	}
+	if err := s.horoscopeRepo.Upsert(ctx, dailyHoroscope); err != nil {
+		s.logger.Error("Failed to store horoscope",
+			logger.Field{Key: "module", Value: "horoscope_service"},
+			logger.Field{Key: "sign", Value: sign.Key},
+			logger.Field{Key: "date", Value: dateText},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to store horoscope: %w", err)
+	}
+
+	stored, err := s.horoscopeRepo.GetBySignAndDate(ctx, sign.Key, date)
+	if err != nil || stored == nil {
+		// Fall back to what was just written
+		dailyHoroscope.UpdatedAt = s.now()
+		return dailyHoroscope, nil
+	}
+
+	s.logger.Info("Horoscope generated",
+		logger.Field{Key: "module", Value: "horoscope_service"},
+		logger.Field{Key: "sign", Value: sign.Key},
+		logger.Field{Key: "date", Value: dateText})
+
+	return stored, nil
+}
+
+func (s *HoroscopeService) askAgent(ctx context.Context, sign astrology.Sign, date time.Time, language string, prompt string) (string, error) {
+	response, err := s.agentRepo.Reply(ctx, agent.ReplyRequest{
+		Text:      prompt,
+		UserID:    horoscopeAgentUserID,
+		SessionID: fmt.Sprintf("horoscope-%s-%s-%s", sign.Key, date.Format(horoscope.DateLayout), language),
+	})
+	if err != nil {
+		s.logger.Error("Failed to generate horoscope",
+			logger.Field{Key: "module", Value: "horoscope_service"},
+			logger.Field{Key: "sign", Value: sign.Key},
+			logger.Field{Key: "language", Value: language},
+			logger.Field{Key: "error", Value: err.Error()})
+		return "", fmt.Errorf("%w: %s", shared.ErrHoroscopeGenerationFailed, err.Error())
+	}
+
+	// Drop the tarot card JSON block the agent may append
+	content, _, _ := history.ExtractJSONFromMessage(response.Message)
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("%w: empty %s response", shared.ErrHoroscopeGenerationFailed, language)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/horoscope"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHoroscopeService(ctrl *gomock.Controller) (*HoroscopeService, *mock_ports.MockHoroscopeRepositoryInterface, *mock_ports.MockAgentRepositoryInterface, *mock_logger.MockLoggerInterface) {
+	mockHoroscopeRepo := mock_ports.NewMockHoroscopeRepositoryInterface(ctrl)
+	mockAgentRepo := mock_ports.NewMockAgentRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewHoroscopeService(mockHoroscopeRepo, mockAgentRepo, mockLogger)
+	// 2026-03-11 08:00 in Asia/Bangkok
+	service.now = func() time.Time { return time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC) }
+
+	return service, mockHoroscopeRepo, mockAgentRepo, mockLogger
+}
+
+func TestHoroscopeService_GetDailyHoroscope_Stored(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, _, _ := newTestHoroscopeService(ctrl)
+	ctx := context.Background()
+	date := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	mockHoroscopeRepo.EXPECT().
+		GetBySignAndDate(ctx, "leo", date).
+		Return(&horoscope.DailyHoroscope{Sign: "leo", HoroscopeDate: date, ContentTH: "ดวงดี", ContentEN: "Good day"}, nil)
+
+	// Act
+	response, err := service.GetDailyHoroscope(ctx, "Leo", date)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "leo", response.Sign.Key)
+	assert.Equal(t, "2026-03-11", response.Date)
+	assert.Equal(t, "ดวงดี", response.ContentTH)
+	assert.Equal(t, "Good day", response.ContentEN)
+}
+
+func TestHoroscopeService_GetDailyHoroscope_InvalidSign(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _, _ := newTestHoroscopeService(ctrl)
+
+	// Act
+	response, err := service.GetDailyHoroscope(context.Background(), "ophiuchus", service.Today())
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, shared.ErrInvalidZodiacSign, err)
+}
+
+func TestHoroscopeService_GetDailyHoroscope_OutsideOnDemandWindow(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, _, _ := newTestHoroscopeService(ctrl)
+	ctx := context.Background()
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockHoroscopeRepo.EXPECT().GetBySignAndDate(ctx, "aries", date).Return(nil, nil)
+
+	// Act
+	response, err := service.GetDailyHoroscope(ctx, "aries", date)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, shared.ErrHoroscopeNotFound, err)
+}
+
+func TestHoroscopeService_GetDailyHoroscope_GeneratesOnceForConcurrentRequests(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, mockAgentRepo, mockLogger := newTestHoroscopeService(ctrl)
+	ctx := context.Background()
+	date := service.Today()
+
+	var saved atomic.Bool
+	mockHoroscopeRepo.EXPECT().
+		GetBySignAndDate(gomock.Any(), "virgo", date).
+		DoAndReturn(func(ctx context.Context, sign string, date time.Time) (*horoscope.DailyHoroscope, error) {
+			if !saved.Load() {
+				return nil, nil
+			}
+			return &horoscope.DailyHoroscope{Sign: sign, HoroscopeDate: date, ContentTH: "ไทย", ContentEN: "English"}, nil
+		}).
+		AnyTimes()
+	mockAgentRepo.EXPECT().
+		Reply(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request agent.ReplyRequest) (*agent.ReplyResponse, error) {
+			time.Sleep(20 * time.Millisecond)
+			if request.SessionID == "horoscope-virgo-2026-03-11-th" {
+				return &agent.ReplyResponse{Message: "ไทย"}, nil
+			}
+			return &agent.ReplyResponse{Message: "English\n```json\n{\"card\": \"THE_SUN\", \"meaning\": \"joy\"}\n```"}, nil
+		}).
+		Times(2)
+	mockHoroscopeRepo.EXPECT().
+		Upsert(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error {
+			assert.Equal(t, "ไทย", dailyHoroscope.ContentTH)
+			assert.Equal(t, "English", dailyHoroscope.ContentEN)
+			saved.Store(true)
+			return nil
+		}).
+		Times(1)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	var wg sync.WaitGroup
+	results := make([]error, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			_, results[index] = service.GetDailyHoroscope(ctx, "virgo", date)
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	for _, err := range results {
+		assert.NoError(t, err)
+	}
+}
+
+func TestHoroscopeService_GetDailyHoroscope_GenerationOutlivesCancelledCaller(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, mockAgentRepo, mockLogger := newTestHoroscopeService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	date := service.Today()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stored := make(chan error, 1)
+	mockHoroscopeRepo.EXPECT().GetBySignAndDate(gomock.Any(), "gemini", date).Return(nil, nil).Times(3)
+	mockAgentRepo.EXPECT().
+		Reply(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request agent.ReplyRequest) (*agent.ReplyResponse, error) {
+			if request.SessionID == "horoscope-gemini-2026-03-11-th" {
+				close(started)
+				<-release
+			}
+			return &agent.ReplyResponse{Message: "content"}, nil
+		}).
+		Times(2)
+	mockHoroscopeRepo.EXPECT().
+		Upsert(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error {
+			stored <- ctx.Err()
+			return nil
+		}).
+		Times(1)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	errs := make(chan error, 1)
+	go func() {
+		_, err := service.GetDailyHoroscope(ctx, "gemini", date)
+		errs <- err
+	}()
+	<-started
+	cancel()
+	callerErr := <-errs
+	close(release)
+
+	// Assert
+	assert.ErrorIs(t, callerErr, context.Canceled)
+	select {
+	case err := <-stored:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("generation did not finish after the caller gave up")
+	}
+}
+
+func TestHoroscopeService_GetDailyHoroscope_UpstreamError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, mockAgentRepo, mockLogger := newTestHoroscopeService(ctrl)
+	ctx := context.Background()
+	date := service.Today()
+
+	mockHoroscopeRepo.EXPECT().GetBySignAndDate(gomock.Any(), "pisces", date).Return(nil, nil).Times(2)
+	mockAgentRepo.EXPECT().Reply(gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to make request"))
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	response, err := service.GetDailyHoroscope(ctx, "pisces", date)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.True(t, errors.Is(err, shared.ErrHoroscopeGenerationFailed))
+}
+
+func TestHoroscopeService_GenerateDailyHoroscopes_SkipsExisting(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockHoroscopeRepo, mockAgentRepo, mockLogger := newTestHoroscopeService(ctrl)
+	ctx := context.Background()
+	date := service.Today()
+
+	existing := make([]horoscope.DailyHoroscope, 0, 11)
+	for _, sign := range []string{"aries", "taurus", "gemini", "cancer", "leo", "virgo", "libra", "scorpio", "sagittarius", "capricorn", "aquarius"} {
+		existing = append(existing, horoscope.DailyHoroscope{Sign: sign, HoroscopeDate: date})
+	}
+
+	mockHoroscopeRepo.EXPECT().ListByDate(ctx, date).Return(existing, nil)
+	mockHoroscopeRepo.EXPECT().GetBySignAndDate(gomock.Any(), "pisces", date).Return(nil, nil).Times(2)
+	mockAgentRepo.EXPECT().Reply(gomock.Any(), gomock.Any()).Return(&agent.ReplyResponse{Message: "content"}, nil).Times(2)
+	mockHoroscopeRepo.EXPECT().
+		Upsert(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error {
+			assert.Equal(t, "pisces", dailyHoroscope.Sign)
+			return nil
+		})
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	err := service.GenerateDailyHoroscopes(ctx, date)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestHoroscopeService_UntilNextRun(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _, _ := newTestHoroscopeService(ctrl)
+
+	// Act: 08:00 local time, next run is 00:05 tomorrow
+	wait := service.untilNextRun()
+
+	// Assert
+	assert.Equal(t, 16*time.Hour+5*time.Minute, wait)
+}
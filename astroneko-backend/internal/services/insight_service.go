@@ -0,0 +1,146 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/internal/core/domain/insight"
+	historyPorts "astroneko-backend/internal/core/ports/history"
+	"astroneko-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Bounds of the insights cache. Entries also expire because the recent-weeks breakdown
+// moves with the clock even when no new messages arrive.
+const (
+	insightCacheSize = 10000
+	insightCacheTTL  = time.Hour
+)
+
+type cachedInsights struct {
+	userID    uuid.UUID
+	watermark insight.Watermark
+	insights  *insight.UserInsightsResponse
+	cachedAt  time.Time
+}
+
+// InsightService provides aggregated reading statistics for users
+type InsightService struct {
+	historyRepo historyPorts.RepositoryInterface
+	logger      logger.Logger
+	now         func() time.Time
+
+	// cache is a least recently used list of *cachedInsights, indexed by user in entries
+	mu      sync.Mutex
+	cache   *list.List
+	entries map[uuid.UUID]*list.Element
+}
+
+// NewInsightService creates a new insight service instance
+func NewInsightService(historyRepo historyPorts.RepositoryInterface, log logger.Logger) *InsightService {
+	return &InsightService{
+		historyRepo: historyRepo,
+		logger:      log,
+		now:         time.Now,
+		cache:       list.New(),
+		entries:     make(map[uuid.UUID]*list.Element),
+	}
+}
+
+// GetUserInsights returns the user's reading insights.
+// The cached result is reused while the user's message watermark (count and latest
+// message time) is unchanged, so new messages or deleted sessions invalidate it.
+func (s *InsightService) GetUserInsights(ctx context.Context, userID uuid.UUID) (*insight.UserInsightsResponse, error) {
+	stats, err := s.historyRepo.GetMessageStatsByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to retrieve message stats",
+			logger.Field{Key: "module", Value: "insight_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to retrieve insights: %w", err)
+	}
+
+	watermark := insight.Watermark{
+		MessageCount:    stats.MessageCount,
+		LatestMessageAt: stats.LatestMessageAt,
+	}
+
+	if insights := s.cached(userID, watermark); insights != nil {
+		return insights, nil
+	}
+
+	messages, err := s.historyRepo.GetMessagesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to retrieve user messages",
+			logger.Field{Key: "module", Value: "insight_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to retrieve insights: %w", err)
+	}
+
+	insights := insight.BuildUserInsights(extractCardReadings(messages), s.now(), insight.DefaultTopCards, insight.DefaultRecentWeeks)
+
+	s.store(userID, watermark, insights)
+
+	return insights, nil
+}
+
+// cached returns the user's cached insights if they are fresh and match the watermark
+func (s *InsightService) cached(userID uuid.UUID, watermark insight.Watermark) *insight.UserInsightsResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, exists := s.entries[userID]
+	if !exists {
+		return nil
+	}
+	entry := element.Value.(*cachedInsights)
+	if s.now().Sub(entry.cachedAt) > insightCacheTTL || !entry.watermark.Equal(watermark) {
+		s.cache.Remove(element)
+		delete(s.entries, userID)
+		return nil
+	}
+	s.cache.MoveToFront(element)
+	return entry.insights
+}
+
+// store caches the user's insights, evicting the least recently used user when full
+func (s *InsightService) store(userID uuid.UUID, watermark insight.Watermark, insights *insight.UserInsightsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := &cachedInsights{userID: userID, watermark: watermark, insights: insights, cachedAt: s.now()}
+	if element, exists := s.entries[userID]; exists {
+		element.Value = entry
+		s.cache.MoveToFront(element)
+		return
+	}
+	s.entries[userID] = s.cache.PushFront(entry)
+	for s.cache.Len() > insightCacheSize {
+		oldest := s.cache.Back()
+		s.cache.Remove(oldest)
+		delete(s.entries, oldest.Value.(*cachedInsights).userID)
+	}
+}
+
+// extractCardReadings returns one reading per message that carries a card JSON block
+func extractCardReadings(messages []history.Message) []insight.CardReading {
+	readings := make([]insight.CardReading, 0, len(messages))
+	for _, message := range messages {
+		_, card, _ := history.ExtractJSONFromMessage(message.Message)
+		if strings.TrimSpace(card) == "" {
+			continue
+		}
+		readings = append(readings, insight.CardReading{
+			Card:   card,
+			ReadAt: message.CreatedAt,
+		})
+	}
+	return readings
+}
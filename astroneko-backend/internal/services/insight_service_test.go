@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/history"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestCardMessage(sessionID uuid.UUID, card string, createdAt time.Time) history.Message {
+	return history.Message{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		Message:   "Your card is here\n```json\n{\"card\": \"" + card + "\", \"meaning\": \"test meaning\"}\n```",
+		Role:      "assistant",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestInsightService_GetUserInsights_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_ports.NewHistoryRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewInsightService(mockHistoryRepo, mockLogger)
+	service.now = func() time.Time { return time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	userID := uuid.New()
+	sessionID := uuid.New()
+	latest := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+
+	messages := []history.Message{
+		*buildTestHistoryMessage(sessionID),
+		buildTestCardMessage(sessionID, "THE_FOOL", time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)),
+		buildTestCardMessage(sessionID, "ACE_OF_CUPS", latest),
+	}
+
+	mockHistoryRepo.EXPECT().
+		GetMessageStatsByUserID(ctx, userID).
+		Return(&history.MessageStats{MessageCount: 3, LatestMessageAt: &latest}, nil)
+	mockHistoryRepo.EXPECT().
+		GetMessagesByUserID(ctx, userID).
+		Return(messages, nil)
+
+	// Act
+	response, err := service.GetUserInsights(ctx, userID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, 2, response.TotalReadings)
+	assert.Equal(t, 1, response.Arcana.Major)
+	assert.Equal(t, 1, response.Arcana.Minor)
+	assert.Equal(t, 2, response.Streaks.CurrentDays)
+}
+
+func TestInsightService_GetUserInsights_UsesCacheUntilNewMessages(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_ports.NewHistoryRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewInsightService(mockHistoryRepo, mockLogger)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	sessionID := uuid.New()
+	first := time.Now().Add(-time.Hour)
+	second := time.Now()
+
+	firstMessages := []history.Message{buildTestCardMessage(sessionID, "THE_SUN", first)}
+	secondMessages := append(firstMessages, buildTestCardMessage(sessionID, "THE_MOON", second))
+
+	gomock.InOrder(
+		mockHistoryRepo.EXPECT().
+			GetMessageStatsByUserID(ctx, userID).
+			Return(&history.MessageStats{MessageCount: 1, LatestMessageAt: &first}, nil),
+		mockHistoryRepo.EXPECT().
+			GetMessagesByUserID(ctx, userID).
+			Return(firstMessages, nil),
+		mockHistoryRepo.EXPECT().
+			GetMessageStatsByUserID(ctx, userID).
+			Return(&history.MessageStats{MessageCount: 1, LatestMessageAt: &first}, nil),
+		mockHistoryRepo.EXPECT().
+			GetMessageStatsByUserID(ctx, userID).
+			Return(&history.MessageStats{MessageCount: 2, LatestMessageAt: &second}, nil),
+		mockHistoryRepo.EXPECT().
+			GetMessagesByUserID(ctx, userID).
+			Return(secondMessages, nil),
+	)
+
+	// Act
+	initial, err1 := service.GetUserInsights(ctx, userID)
+	cached, err2 := service.GetUserInsights(ctx, userID)
+	refreshed, err3 := service.GetUserInsights(ctx, userID)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Same(t, initial, cached)
+	assert.Equal(t, 1, initial.TotalReadings)
+	assert.Equal(t, 2, refreshed.TotalReadings)
+}
+
+func TestInsightService_GetUserInsights_CacheExpires(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_ports.NewHistoryRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewInsightService(mockHistoryRepo, mockLogger)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockHistoryRepo.EXPECT().
+		GetMessageStatsByUserID(ctx, userID).
+		Return(&history.MessageStats{}, nil).
+		Times(3)
+	mockHistoryRepo.EXPECT().
+		GetMessagesByUserID(ctx, userID).
+		Return([]history.Message{}, nil).
+		Times(2)
+
+	// Act
+	initial, err1 := service.GetUserInsights(ctx, userID)
+	cached, err2 := service.GetUserInsights(ctx, userID)
+	now = now.Add(insightCacheTTL + time.Second)
+	expired, err3 := service.GetUserInsights(ctx, userID)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Same(t, initial, cached)
+	assert.NotSame(t, initial, expired)
+}
+
+func TestInsightService_GetUserInsights_CacheEvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_ports.NewHistoryRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewInsightService(mockHistoryRepo, mockLogger)
+	ctx := context.Background()
+
+	mockHistoryRepo.EXPECT().
+		GetMessageStatsByUserID(ctx, gomock.Any()).
+		Return(&history.MessageStats{}, nil).
+		AnyTimes()
+	mockHistoryRepo.EXPECT().
+		GetMessagesByUserID(ctx, gomock.Any()).
+		Return([]history.Message{}, nil).
+		AnyTimes()
+
+	first := uuid.New()
+	_, err := service.GetUserInsights(ctx, first)
+	require.NoError(t, err)
+
+	// Act
+	for i := 0; i < insightCacheSize; i++ {
+		_, err := service.GetUserInsights(ctx, uuid.New())
+		require.NoError(t, err)
+	}
+
+	// Assert
+	assert.Equal(t, insightCacheSize, service.cache.Len())
+	assert.NotContains(t, service.entries, first)
+}
+
+func TestInsightService_GetUserInsights_RepositoryError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_ports.NewHistoryRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewInsightService(mockHistoryRepo, mockLogger)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	dbError := errors.New("database connection error")
+
+	mockHistoryRepo.EXPECT().
+		GetMessageStatsByUserID(ctx, userID).
+		Return(nil, dbError)
+	mockLogger.EXPECT().
+		Error("Failed to retrieve message stats", gomock.Any()).
+		AnyTimes()
+
+	// Act
+	response, err := service.GetUserInsights(ctx, userID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "failed to retrieve insights")
+}
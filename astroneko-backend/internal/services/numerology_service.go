@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/numerology"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+)
+
+// NumerologyService computes name, birth date and phone number numerology
+type NumerologyService struct {
+	now func() time.Time
+}
+
+// NewNumerologyService creates a new numerology service instance
+func NewNumerologyService() *NumerologyService {
+	return &NumerologyService{
+		now: time.Now,
+	}
+}
+
+// AnalyzeName computes the letter sums of a Thai or English name
+func (s *NumerologyService) AnalyzeName(name string) (*numerology.NameAnalysis, error) {
+	return numerology.AnalyzeName(name)
+}
+
+// AnalyzeLifePath computes the life path number of a YYYY-MM-DD birth date
+func (s *NumerologyService) AnalyzeLifePath(birthDate string) (*numerology.LifePathAnalysis, error) {
+	date, err := time.Parse(numerology.DateLayout, birthDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: birth date must be YYYY-MM-DD", shared.ErrInvalidNumerologyInput)
+	}
+	if date.After(s.now()) {
+		return nil, fmt.Errorf("%w: birth date cannot be in the future", shared.ErrInvalidNumerologyInput)
+	}
+
+	return numerology.AnalyzeLifePath(date), nil
+}
+
+// AnalyzePhone computes the digit sum and pairs of a phone number
+func (s *NumerologyService) AnalyzePhone(phone string) (*numerology.PhoneAnalysis, error) {
+	return numerology.AnalyzePhone(phone)
+}
+
+// BuildAgentContext computes the numbers requested for an agent reply.
+// The life path comes from the user's birth profile, never from the request.
+// Returns nil when nothing was requested.
+func (s *NumerologyService) BuildAgentContext(u *user.User, input *agent.NumerologyInput) (*agent.NumerologyContext, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	numerologyContext := &agent.NumerologyContext{}
+
+	if strings.TrimSpace(input.Name) != "" {
+		name, err := numerology.AnalyzeName(input.Name)
+		if err != nil {
+			return nil, err
+		}
+		numerologyContext.Name = name
+	}
+
+	if strings.TrimSpace(input.Phone) != "" {
+		phone, err := numerology.AnalyzePhone(input.Phone)
+		if err != nil {
+			return nil, err
+		}
+		numerologyContext.Phone = phone
+	}
+
+	if u != nil && u.HasBirthProfile() {
+		numerologyContext.LifePath = numerology.AnalyzeLifePath(*u.BirthDate)
+	}
+
+	if numerologyContext.Name == nil && numerologyContext.Phone == nil && numerologyContext.LifePath == nil {
+		return nil, nil
+	}
+	return numerologyContext, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/agent"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumerologyService_AnalyzeLifePath(t *testing.T) {
+	// Arrange
+	service := NewNumerologyService()
+	service.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	// Act
+	analysis, err := service.AnalyzeLifePath("1990-08-15")
+	_, invalidErr := service.AnalyzeLifePath("15/08/1990")
+	_, futureErr := service.AnalyzeLifePath("2026-06-01")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 33, analysis.LifePath)
+	assert.True(t, errors.Is(invalidErr, shared.ErrInvalidNumerologyInput))
+	assert.True(t, errors.Is(futureErr, shared.ErrInvalidNumerologyInput))
+}
+
+func TestNumerologyService_BuildAgentContext(t *testing.T) {
+	// Arrange
+	service := NewNumerologyService()
+
+	// Act
+	numerologyContext, err := service.BuildAgentContext(buildTestBirthProfileUser(), &agent.NumerologyInput{
+		Name:  "สมชาย ใจดี",
+		Phone: "0812345678",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 43, numerologyContext.Name.Total)
+	assert.Equal(t, 44, numerologyContext.Phone.Sum)
+	assert.Equal(t, 33, numerologyContext.LifePath.LifePath)
+}
+
+func TestNumerologyService_BuildAgentContext_NotRequested(t *testing.T) {
+	service := NewNumerologyService()
+
+	// Nothing requested
+	numerologyContext, err := service.BuildAgentContext(buildTestBirthProfileUser(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, numerologyContext)
+
+	// Requested by a guest without name or phone
+	numerologyContext, err = service.BuildAgentContext(nil, &agent.NumerologyInput{})
+	assert.NoError(t, err)
+	assert.Nil(t, numerologyContext)
+
+	// A user without birth profile only gets what they asked for
+	numerologyContext, err = service.BuildAgentContext(&user.User{}, &agent.NumerologyInput{Name: "Somchai"})
+	assert.NoError(t, err)
+	assert.NotNil(t, numerologyContext.Name)
+	assert.Nil(t, numerologyContext.LifePath)
+}
+
+func TestNumerologyService_BuildAgentContext_InvalidPhone(t *testing.T) {
+	// Act
+	numerologyContext, err := NewNumerologyService().BuildAgentContext(nil, &agent.NumerologyInput{Phone: "call me"})
+
+	// Assert
+	assert.Nil(t, numerologyContext)
+	assert.True(t, errors.Is(err, shared.ErrInvalidNumerologyInput))
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/shared"
+	personPorts "astroneko-backend/internal/core/ports/person"
+	"astroneko-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// PersonService manages the people a user saves for compatibility readings
+type PersonService struct {
+	personRepo personPorts.RepositoryInterface
+	logger     logger.Logger
+	now        func() time.Time
+}
+
+// NewPersonService creates a new saved people service instance
+func NewPersonService(personRepo personPorts.RepositoryInterface, log logger.Logger) *PersonService {
+	return &PersonService{
+		personRepo: personRepo,
+		logger:     log,
+		now:        time.Now,
+	}
+}
+
+// ListPeople returns the people saved by the user
+func (s *PersonService) ListPeople(ctx context.Context, userID uuid.UUID) (*person.ListPeopleResponse, error) {
+	people, err := s.personRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list people",
+			logger.Field{Key: "module", Value: "person_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	response := &person.ListPeopleResponse{
+		People: make([]person.PersonResponse, 0, len(people)),
+		Total:  len(people),
+	}
+	for i := range people {
+		response.People = append(response.People, people[i].ToResponse())
+	}
+
+	return response, nil
+}
+
+// CreatePerson saves a new person for the user, up to person.MaxPeoplePerUser
+func (s *PersonService) CreatePerson(ctx context.Context, userID uuid.UUID, req *person.SavePersonRequest) (*person.PersonResponse, error) {
+	count, err := s.personRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= person.MaxPeoplePerUser {
+		return nil, shared.ErrPeopleLimitReached
+	}
+
+	newPerson := &person.Person{UserID: userID}
+	if err := s.applyRequest(newPerson, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.personRepo.Create(ctx, newPerson); err != nil {
+		s.logger.Error("Failed to create person",
+			logger.Field{Key: "module", Value: "person_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	response := newPerson.ToResponse()
+	return &response, nil
+}
+
+// UpdatePerson replaces a saved person's name, relationship and birth profile
+func (s *PersonService) UpdatePerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, req *person.SavePersonRequest) (*person.PersonResponse, error) {
+	existingPerson, err := s.personRepo.GetByID(ctx, userID, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(existingPerson, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.personRepo.Update(ctx, existingPerson); err != nil {
+		s.logger.Error("Failed to update person",
+			logger.Field{Key: "module", Value: "person_service"},
+			logger.Field{Key: "person_id", Value: personID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	response := existingPerson.ToResponse()
+	return &response, nil
+}
+
+// DeletePerson removes a saved person
+func (s *PersonService) DeletePerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID) error {
+	if _, err := s.personRepo.GetByID(ctx, userID, personID); err != nil {
+		return err
+	}
+
+	return s.personRepo.Delete(ctx, userID, personID)
+}
+
+// applyRequest validates the birth profile of a request and copies it onto the person
+func (s *PersonService) applyRequest(p *person.Person, req *person.SavePersonRequest) error {
+	birthDate, timezone, err := parseBirthProfile(s.now(), req.BirthDate, req.BirthTime, req.BirthLatitude, req.BirthLongitude, req.BirthTimezone)
+	if err != nil {
+		return err
+	}
+
+	p.Name = req.Name
+	p.Relationship = req.Relationship
+	p.BirthDate = birthDate
+	p.BirthTime = req.BirthTime
+	p.BirthPlace = req.BirthPlace
+	p.BirthLatitude = req.BirthLatitude
+	p.BirthLongitude = req.BirthLongitude
+	p.BirthTimezone = &timezone
+
+	return nil
+}
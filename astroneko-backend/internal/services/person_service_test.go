@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/person"
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestSavePersonRequest() *person.SavePersonRequest {
+	birthTime := "21:15"
+	return &person.SavePersonRequest{
+		Name:         "มะลิ",
+		Relationship: person.RelationshipPartner,
+		BirthDate:    "1992-03-04",
+		BirthTime:    &birthTime,
+	}
+}
+
+func newTestPersonService(ctrl *gomock.Controller) (*PersonService, *mock_ports.MockPersonRepositoryInterface) {
+	mockPersonRepo := mock_ports.NewMockPersonRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewPersonService(mockPersonRepo, mockLogger)
+	service.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	return service, mockPersonRepo
+}
+
+func TestPersonService_CreatePerson_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersonRepo := newTestPersonService(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockPersonRepo.EXPECT().CountByUserID(ctx, userID).Return(int64(2), nil)
+	mockPersonRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *person.Person) error {
+			assert.Equal(t, userID, p.UserID)
+			assert.Equal(t, time.Date(1992, 3, 4, 0, 0, 0, 0, time.UTC), p.BirthDate)
+			return nil
+		})
+
+	// Act
+	response, err := service.CreatePerson(ctx, userID, buildTestSavePersonRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "มะลิ", response.Name)
+	assert.Equal(t, "1992-03-04", response.BirthDate)
+	assert.Equal(t, "Asia/Bangkok", *response.BirthTimezone)
+}
+
+func TestPersonService_CreatePerson_LimitReached(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersonRepo := newTestPersonService(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockPersonRepo.EXPECT().CountByUserID(ctx, userID).Return(int64(person.MaxPeoplePerUser), nil)
+
+	// Act
+	response, err := service.CreatePerson(ctx, userID, buildTestSavePersonRequest())
+
+	// Assert
+	assert.Nil(t, response)
+	assert.True(t, errors.Is(err, shared.ErrPeopleLimitReached))
+}
+
+func TestPersonService_CreatePerson_FutureBirthDate(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersonRepo := newTestPersonService(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+	req := buildTestSavePersonRequest()
+	req.BirthDate = "2030-01-01"
+
+	mockPersonRepo.EXPECT().CountByUserID(ctx, userID).Return(int64(0), nil)
+
+	// Act
+	_, err := service.CreatePerson(ctx, userID, req)
+
+	// Assert
+	assert.True(t, errors.Is(err, shared.ErrInvalidBirthProfile))
+}
+
+func TestPersonService_UpdatePerson_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersonRepo := newTestPersonService(ctrl)
+	ctx := context.Background()
+	userID, personID := uuid.New(), uuid.New()
+
+	mockPersonRepo.EXPECT().GetByID(ctx, userID, personID).Return(nil, shared.ErrPersonNotFound)
+
+	// Act
+	_, err := service.UpdatePerson(ctx, userID, personID, buildTestSavePersonRequest())
+
+	// Assert
+	assert.True(t, errors.Is(err, shared.ErrPersonNotFound))
+}
+
+func TestPersonService_DeletePerson_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersonRepo := newTestPersonService(ctrl)
+	ctx := context.Background()
+	userID, personID := uuid.New(), uuid.New()
+
+	mockPersonRepo.EXPECT().GetByID(ctx, userID, personID).Return(&person.Person{UserID: userID}, nil)
+	mockPersonRepo.EXPECT().Delete(ctx, userID, personID).Return(nil)
+
+	// Act
+	err := service.DeletePerson(ctx, userID, personID)
+
+	// Assert
+	assert.NoError(t, err)
+}
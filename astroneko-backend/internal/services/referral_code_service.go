@@ -3,10 +3,18 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"math/big"
+	"sort"
+	"time"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
 	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
 	userPorts "astroneko-backend/internal/core/ports/user"
 	"astroneko-backend/pkg/logger"
@@ -17,14 +25,24 @@ import (
 type ReferralCodeService struct {
 	referralCodeRepo referralCodePorts.RepositoryInterface
 	userRepo         userPorts.RepositoryInterface
+	attempts         credentialAttemptPorts.ServiceInterface
+	rewardPolicy     referral_reward.Policy
+	allotment        referral_code.AllotmentPolicy
+	vanity           referral_code.VanityPolicy
 	logger           logger.Logger
+	now              func() time.Time
 }
 
-func NewReferralCodeService(referralCodeRepo referralCodePorts.RepositoryInterface, userRepo userPorts.RepositoryInterface, log logger.Logger) *ReferralCodeService {
+func NewReferralCodeService(referralCodeRepo referralCodePorts.RepositoryInterface, userRepo userPorts.RepositoryInterface, attempts credentialAttemptPorts.ServiceInterface, rewardPolicy referral_reward.Policy, allotment referral_code.AllotmentPolicy, vanity referral_code.VanityPolicy, log logger.Logger) *ReferralCodeService {
 	return &ReferralCodeService{
 		referralCodeRepo: referralCodeRepo,
 		userRepo:         userRepo,
+		attempts:         attempts,
+		rewardPolicy:     rewardPolicy,
+		allotment:        allotment,
+		vanity:           vanity,
 		logger:           log,
+		now:              time.Now,
 	}
 }
 
@@ -32,10 +50,35 @@ func (s *ReferralCodeService) IsValidReferralCode(ctx context.Context, code stri
 	return s.referralCodeRepo.IsValidReferralCode(ctx, code)
 }
 
-func (s *ReferralCodeService) CreateReferralCode(ctx context.Context, code string) (*referral_code.ReferralCode, error) {
+// ValidateReferralCode checks a code for the validate endpoint. Invalid codes count as
+// failed attempts for the caller and their IP, so the endpoint cannot be used to walk
+// the code space. Valid codes do not clear the count, otherwise mixing in a known code
+// now and then would keep a scan going.
+func (s *ReferralCodeService) ValidateReferralCode(ctx context.Context, code, account, clientIP string) (bool, error) {
+	if err := s.attempts.Check(ctx, credential_attempt.ScopeReferralValidate, account, clientIP); err != nil {
+		return false, err
+	}
+
+	isValid, err := s.referralCodeRepo.IsValidReferralCode(ctx, code)
+	if err != nil {
+		return false, err
+	}
+
+	if !isValid {
+		if err := s.attempts.RecordFailure(ctx, credential_attempt.ScopeReferralValidate, account, clientIP); err != nil {
+			s.logger.Error("Failed to record invalid referral code attempt",
+				logger.Field{Key: "module", Value: "referral_code_service"},
+				logger.Field{Key: "error", Value: err.Error()})
+		}
+	}
+	return isValid, nil
+}
+
+func (s *ReferralCodeService) CreateReferralCode(ctx context.Context, req *referral_code.CreateReferralCodeRequest) (*referral_code.ReferralCode, error) {
 	newReferralCode := &referral_code.ReferralCode{
-		ReferralCode: code,
+		ReferralCode: req.ReferralCode,
 	}
+	req.ApplyTo(newReferralCode)
 
 	return s.referralCodeRepo.Create(ctx, newReferralCode)
 }
@@ -64,12 +107,9 @@ func (s *ReferralCodeService) ListReferralCodes(ctx context.Context, limit, offs
 	return s.referralCodeRepo.List(ctx, limit, offset)
 }
 
-// generateRandomCode generates a random 8-character alphanumeric code
-func (s *ReferralCodeService) generateRandomCode() (string, error) {
-	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	const codeLength = 8
-
-	code := make([]byte, codeLength)
+// randomCode draws length characters from charset with crypto/rand
+func randomCode(charset string, length int) (string, error) {
+	code := make([]byte, length)
 	for i := range code {
 		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
 		if err != nil {
@@ -80,71 +120,264 @@ func (s *ReferralCodeService) generateRandomCode() (string, error) {
 	return string(code), nil
 }
 
-// GetOrGenerateUserReferralCodes gets existing user referral codes or generates codes to ensure user has exactly 5
+// maxCodeGenerationRounds bounds how often codes that collided are drawn again
+const maxCodeGenerationRounds = 5
+
+// GenerateReferralCodes creates req.Count general codes with the request's settings in one
+// transaction. Codes that collide with an existing general or user code, or with one drawn
+// earlier in the batch, are drawn again.
+func (s *ReferralCodeService) GenerateReferralCodes(ctx context.Context, req *referral_code.GenerateReferralCodesRequest) ([]*referral_code.ReferralCode, error) {
+	var created []*referral_code.ReferralCode
+	err := s.referralCodeRepo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		codes, err := drawUniqueCodes(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+
+		created = make([]*referral_code.ReferralCode, 0, len(codes))
+		for _, code := range codes {
+			referralCode := &referral_code.ReferralCode{ReferralCode: code}
+			req.ApplyTo(referralCode)
+			created = append(created, referralCode)
+		}
+		return tx.CreateReferralCodes(ctx, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func drawUniqueCodes(ctx context.Context, tx referralCodePorts.RepositoryInterface, req *referral_code.GenerateReferralCodesRequest) ([]string, error) {
+	charset := req.CharsetChars()
+	drawn := make(map[string]bool, req.Count)
+	codes := make([]string, 0, req.Count)
+
+	for round := 0; round < maxCodeGenerationRounds && len(codes) < req.Count; round++ {
+		candidates := make([]string, 0, req.Count-len(codes))
+		for len(codes)+len(candidates) < req.Count {
+			random, err := randomCode(charset, req.Length)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate random code: %w", err)
+			}
+			code := referral_code.NormalizeCode(req.Prefix + random)
+			if drawn[code] {
+				continue
+			}
+			drawn[code] = true
+			candidates = append(candidates, code)
+		}
+
+		existing, err := tx.ExistingReferralCodes(ctx, candidates)
+		if err != nil {
+			return nil, err
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			taken[code] = true
+		}
+		for _, code := range candidates {
+			if !taken[code] {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	if len(codes) < req.Count {
+		return nil, referral_code.ErrCodeSpaceExhausted
+	}
+	return codes, nil
+}
+
+// ImportReferralCodes creates the codes of an uploaded CSV. rowErrors holds the rows the
+// caller already rejected; codes repeated in the file once normalized, or already taken,
+// are added to them. Nothing is created unless every row is valid, so a file can be fixed
+// and uploaded again.
+func (s *ReferralCodeService) ImportReferralCodes(ctx context.Context, rows []referral_code.ImportRow, rowErrors []referral_code.RowError) (*referral_code.ImportResult, error) {
+	result := &referral_code.ImportResult{Errors: rowErrors}
+	firstRow := make(map[string]referral_code.ImportRow, len(rows))
+	codes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		key := referral_code.NormalizeCode(row.ReferralCode)
+		if first, ok := firstRow[key]; ok {
+			result.Errors = append(result.Errors, referral_code.RowError{
+				Line:         row.Line,
+				ReferralCode: row.ReferralCode,
+				Error:        fmt.Sprintf("referral code repeats line %d", first.Line),
+			})
+			continue
+		}
+		firstRow[key] = row
+		codes = append(codes, key)
+	}
+
+	err := s.referralCodeRepo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		if len(codes) > 0 {
+			existing, err := tx.ExistingReferralCodes(ctx, codes)
+			if err != nil {
+				return err
+			}
+			for _, code := range existing {
+				row := firstRow[code]
+				result.Errors = append(result.Errors, referral_code.RowError{
+					Line:         row.Line,
+					ReferralCode: row.ReferralCode,
+					Error:        "referral code already exists",
+				})
+			}
+		}
+		if len(result.Errors) > 0 {
+			return nil
+		}
+
+		referralCodes := make([]*referral_code.ReferralCode, 0, len(rows))
+		for _, row := range rows {
+			referralCode := &referral_code.ReferralCode{ReferralCode: row.ReferralCode}
+			row.ApplyTo(referralCode)
+			referralCodes = append(referralCodes, referralCode)
+		}
+		if err := tx.CreateReferralCodes(ctx, referralCodes); err != nil {
+			return err
+		}
+		result.Imported = len(referralCodes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+	if result.Errors == nil {
+		result.Errors = []referral_code.RowError{}
+	}
+	return result, nil
+}
+
+// exportPageSize is how many codes an export holds in memory at a time
+const exportPageSize = 500
+
+// ExportReferralCodes writes every general code with its usage count to w as CSV, one
+// page at a time, flushing after each page so the response streams to the client
+func (s *ReferralCodeService) ExportReferralCodes(ctx context.Context, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(referral_code.ExportCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	var cursor *referral_code.ExportCursor
+	for {
+		page, err := s.referralCodeRepo.ListUsageAfter(ctx, cursor, exportPageSize)
+		if err != nil {
+			s.logger.Error("Failed to export referral codes",
+				logger.Field{Key: "module", Value: "referral_code_service"},
+				logger.Field{Key: "error", Value: err.Error()})
+			return err
+		}
+		for _, usage := range page {
+			if err := writer.Write(usage.CSVRecord()); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+
+		if len(page) < exportPageSize {
+			return nil
+		}
+		last := page[len(page)-1]
+		cursor = &referral_code.ExportCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// maxUserCodeAttempts bounds how often a new user code is drawn again after it collided
+// with an existing one
+const maxUserCodeAttempts = 5
+
+// GetOrGenerateUserReferralCodes returns the user's codes that are used or still usable,
+// first issuing new ones when the allotment policy says the user is due some. Codes are
+// issued with the user's row locked, so concurrent calls cannot both top up.
 func (s *ReferralCodeService) GetOrGenerateUserReferralCodes(ctx context.Context, userID uuid.UUID) ([]*referral_code.UserReferralCode, error) {
 	// First check if user has activated referral feature
-	user, err := s.userRepo.GetByID(ctx, userID.String())
+	owner, err := s.userRepo.GetByID(ctx, userID.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	if !user.IsActivatedReferral {
+	if !owner.IsActivatedReferral {
 		return nil, fmt.Errorf("user has not activated referral feature")
 	}
 
-	// Check if user already has referral codes
+	now := s.now()
 	existingCodes, err := s.referralCodeRepo.GetUserReferralCodesByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing user referral codes: %w", err)
 	}
 
-	// Calculate how many more codes we need to generate
-	codesNeeded := 5 - len(existingCodes)
-
-	// If user already has 5 or more codes, return existing ones
-	if codesNeeded <= 0 {
-		return existingCodes, nil
-	}
-
-	// Generate the remaining codes needed
-	var newCodes []*referral_code.UserReferralCode
-	for range codesNeeded {
-		var code string
-		var isUnique bool
-
-		// Keep generating until we get a unique code
-		for !isUnique {
-			code, err = s.generateRandomCode()
+	if s.allotment.CodesToIssue(existingCodes, owner.Tier(), now) > 0 {
+		err = s.referralCodeRepo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+			if err := tx.LockCodeOwner(ctx, userID); err != nil {
+				return err
+			}
+			// Read the codes again under the lock, another request may have issued them already
+			codes, err := tx.GetUserReferralCodesByUserID(ctx, userID)
 			if err != nil {
-				return nil, fmt.Errorf("failed to generate random code: %w", err)
+				return fmt.Errorf("failed to get existing user referral codes: %w", err)
 			}
-
-			// Only check if code exists in user referral codes (any user)
-			_, err := s.referralCodeRepo.GetUserReferralCodeByCode(ctx, code)
-			isUnique = err != nil // Code is unique if not found
+			for range s.allotment.CodesToIssue(codes, owner.Tier(), now) {
+				created, err := s.issueUserReferralCode(ctx, tx, userID, now)
+				if err != nil {
+					return err
+				}
+				codes = append(codes, created)
+			}
+			existingCodes = codes
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Create new user referral code
-		newUserCode := &referral_code.UserReferralCode{
-			UserID:       userID,
-			ReferralCode: code,
-			IsActivated:  false,
+	// Expired codes that were never used are of no use to the user
+	codes := make([]*referral_code.UserReferralCode, 0, len(existingCodes))
+	for _, code := range existingCodes {
+		if code.IsActivated || !code.IsExpired(now) {
+			codes = append(codes, code)
 		}
+	}
+	return codes, nil
+}
 
-		createdCode, err := s.referralCodeRepo.CreateUserReferralCode(ctx, newUserCode)
+// issueUserReferralCode creates a code drawn from the user code alphabet with a check
+// character, drawing again when a general or user code already holds it
+func (s *ReferralCodeService) issueUserReferralCode(ctx context.Context, tx referralCodePorts.RepositoryInterface, userID uuid.UUID, now time.Time) (*referral_code.UserReferralCode, error) {
+	for range maxUserCodeAttempts {
+		body, err := randomCode(referral_code.UserCodeAlphabet, s.allotment.Length)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create user referral code: %w", err)
+			return nil, fmt.Errorf("failed to generate random code: %w", err)
 		}
 
-		newCodes = append(newCodes, createdCode)
+		code := &referral_code.UserReferralCode{
+			UserID:       userID,
+			ReferralCode: referral_code.WithCheckChar(body),
+			ExpiresAt:    s.allotment.ExpiryFrom(now),
+		}
+		created, err := tx.CreateUserReferralCodeIfAbsent(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return code, nil
+		}
 	}
-
-	// Return all codes (existing + newly created)
-	allCodes := append(existingCodes, newCodes...)
-	return allCodes, nil
+	return nil, fmt.Errorf("failed to create a unique user referral code after %d attempts", maxUserCodeAttempts)
 }
 
-// ActivateReferralCode activates a referral code and logs the usage
+// ActivateReferralCode activates a referral code and logs the usage. The user flag, the
+// code and the log are written in one transaction with the single-use code row locked,
+// so concurrent redemptions of the same code succeed exactly once.
 func (s *ReferralCodeService) ActivateReferralCode(ctx context.Context, userID uuid.UUID, code string) (*referral_code.ActivateReferralResponse, error) {
 	// First check if user has already activated a referral code
 	user, err := s.userRepo.GetByID(ctx, userID.String())
@@ -153,30 +386,56 @@ func (s *ReferralCodeService) ActivateReferralCode(ctx context.Context, userID u
 	}
 
 	if user.IsActivatedReferral {
-		return &referral_code.ActivateReferralResponse{
-			Success: false,
-			Message: "User has already activated referral code",
-		}, nil
+		return alreadyActivatedReferralResponse(), nil
 	}
 
+	code = referral_code.NormalizeCode(code)
+	var response *referral_code.ActivateReferralResponse
+	err = s.referralCodeRepo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		var txErr error
+		response, txErr = s.activateReferralCodeTx(ctx, tx, userID, code)
+		return txErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *ReferralCodeService) activateReferralCodeTx(ctx context.Context, tx referralCodePorts.RepositoryInterface, userID uuid.UUID, code string) (*referral_code.ActivateReferralResponse, error) {
 	// Check if it's a valid general referral code (unlimited use)
-	isValidGeneral, err := s.referralCodeRepo.IsValidReferralCode(ctx, code)
+	isValidGeneral, err := tx.IsValidReferralCode(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check general referral code: %w", err)
 	}
 
 	if isValidGeneral {
-		// Update user's is_activated_referral to true
-		user.IsActivatedReferral = true
-		_, err = s.userRepo.Update(ctx, user)
+		// Lock the code so that concurrent redemptions are counted against its cap in turn
+		generalReferralCode, err := tx.GetByReferralCodeForUpdate(ctx, code)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update user referral status: %w", err)
+			return nil, fmt.Errorf("failed to get general referral code: %w", err)
 		}
 
-		// Get the general referral code to obtain its ID
-		generalReferralCode, err := s.referralCodeRepo.GetByReferralCode(ctx, code)
+		redemptions, err := tx.CountRedemptions(ctx, generalReferralCode.ID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get general referral code: %w", err)
+			return nil, err
+		}
+		if err := generalReferralCode.CheckRedeemable(s.now(), redemptions); err != nil {
+			return &referral_code.ActivateReferralResponse{
+				Success: false,
+				Message: err.Error(),
+				Reason:  referral_code.UnavailableReason(err),
+			}, nil
+		}
+
+		tier, bonusQuota := generalReferralCode.Grant()
+		marked, err := tx.MarkUserReferralActivated(ctx, userID, tier, bonusQuota)
+		if err != nil {
+			return nil, err
+		}
+		if !marked {
+			return alreadyActivatedReferralResponse(), nil
 		}
 
 		// Log the general referral code usage
@@ -186,46 +445,51 @@ func (s *ReferralCodeService) ActivateReferralCode(ctx context.Context, userID u
 			ReferralCodeID:   &generalReferralCode.ID,
 		}
 
-		_, err = s.referralCodeRepo.CreateReferralLog(ctx, referralLog)
+		createdLog, err := tx.CreateReferralLog(ctx, referralLog)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create referral log: %w", err)
 		}
+		if err := tx.AttributeClick(ctx, createdLog); err != nil {
+			return nil, err
+		}
 
 		return &referral_code.ActivateReferralResponse{
-			Success: true,
-			Message: "General referral code activated successfully",
+			Success:    true,
+			Message:    "General referral code activated successfully",
+			Tier:       tier,
+			BonusQuota: bonusQuota,
 		}, nil
 	}
 
-	// Check if it's a valid user referral code (single use)
-	userReferralCode, err := s.referralCodeRepo.GetUserReferralCodeByCode(ctx, code)
+	// Check if it's a valid user referral code (single use). The row stays locked until
+	// commit, so a second redemption waits here and then sees IsActivated.
+	userReferralCode, err := tx.GetUserReferralCodeByCodeForUpdate(ctx, code)
 	if err != nil {
-		return &referral_code.ActivateReferralResponse{
-			Success: false,
-			Message: "Referral code is invalid",
-		}, nil
+		if referral_code.IsMistypedUserCode(code) {
+			return mistypedReferralResponse(), nil
+		}
+		return invalidReferralResponse(), nil
 	}
 
-	// Check if the code is already activated
-	if userReferralCode.IsActivated {
-		return &referral_code.ActivateReferralResponse{
-			Success: false,
-			Message: "Referral code already activated",
-		}, nil
+	// A used or expired code gets the same answer as an unknown one, so codes cannot be probed
+	if !userReferralCode.IsUsable(s.now()) {
+		return invalidReferralResponse(), nil
 	}
 
-	// Update user's is_activated_referral to true
-	user.IsActivatedReferral = true
-	_, err = s.userRepo.Update(ctx, user)
+	marked, err := tx.MarkUserReferralActivated(ctx, userID, user.TierUnlimited, 0)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user referral status: %w", err)
+		return nil, err
+	}
+	if !marked {
+		return alreadyActivatedReferralResponse(), nil
 	}
 
-	// Activate the user referral code
-	userReferralCode.IsActivated = true
-	_, err = s.referralCodeRepo.UpdateUserReferralCode(ctx, userReferralCode)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update user referral code: %w", err)
+	// Activate the user referral code. Vanity codes stay open for further redemptions.
+	if !userReferralCode.IsVanity {
+		userReferralCode.IsActivated = true
+		if _, err := tx.UpdateUserReferralCode(ctx, userReferralCode); err != nil {
+			return nil, fmt.Errorf("failed to update user referral code: %w", err)
+		}
 	}
 
 	// Log the user referral code usage
@@ -235,13 +499,144 @@ func (s *ReferralCodeService) ActivateReferralCode(ctx context.Context, userID u
 		ReferralCodeID:   &userReferralCode.ID,
 	}
 
-	_, err = s.referralCodeRepo.CreateReferralLog(ctx, referralLog)
+	createdLog, err := tx.CreateReferralLog(ctx, referralLog)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create referral log: %w", err)
 	}
+	if err := tx.AttributeClick(ctx, createdLog); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewardInviter(ctx, tx, userReferralCode.UserID, createdLog.ID); err != nil {
+		return nil, err
+	}
 
 	return &referral_code.ActivateReferralResponse{
 		Success: true,
 		Message: "User referral code activated successfully",
+		Tier:    user.TierUnlimited,
 	}, nil
 }
+
+// ClaimVanityCode gives the user a code of their choosing once enough people have redeemed
+// their codes. The code is normalized and checked against the vanity policy first. The
+// user's row stays locked while the claim is checked and written, so a user ends up with
+// at most one vanity code however often they ask.
+func (s *ReferralCodeService) ClaimVanityCode(ctx context.Context, userID uuid.UUID, code string) (*referral_code.UserReferralCode, error) {
+	owner, err := s.userRepo.GetByID(ctx, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !owner.IsActivatedReferral {
+		return nil, referral_code.ErrVanityNotEligible
+	}
+
+	code = referral_code.NormalizeCode(code)
+	if err := s.vanity.CheckCode(code); err != nil {
+		return nil, err
+	}
+
+	vanityCode := &referral_code.UserReferralCode{
+		UserID:       userID,
+		ReferralCode: code,
+		IsVanity:     true,
+	}
+	err = s.referralCodeRepo.WithTx(ctx, func(tx referralCodePorts.RepositoryInterface) error {
+		invites, err := tx.CountInvitesForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if invites < int64(s.vanity.MinInvites) {
+			return referral_code.ErrVanityNotEligible
+		}
+
+		codes, err := tx.GetUserReferralCodesByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get existing user referral codes: %w", err)
+		}
+		for _, existing := range codes {
+			if existing.IsVanity {
+				return referral_code.ErrVanityAlreadyClaimed
+			}
+		}
+
+		// The insert reserves the code in the namespace general codes share, so a general code
+		// created at the same time cannot take it too
+		created, err := tx.CreateUserReferralCodeIfAbsent(ctx, vanityCode)
+		if err != nil {
+			return err
+		}
+		if !created {
+			return referral_code.ErrVanityCodeTaken
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Vanity referral code claimed",
+		logger.Field{Key: "module", Value: "referral_code_service"},
+		logger.Field{Key: "user_id", Value: userID.String()},
+		logger.Field{Key: "referral_code", Value: code})
+	return vanityCode, nil
+}
+
+// rewardInviter credits the owner of a redeemed user code with what the reward policy
+// grants for their new invite count. Inviters with unlimited access only earn badges.
+// The ledger is keyed by the redemption's log, so the same redemption never pays out twice.
+func (s *ReferralCodeService) rewardInviter(ctx context.Context, tx referralCodePorts.RepositoryInterface, inviterID, referralLogID uuid.UUID) error {
+	inviter, err := s.userRepo.GetByID(ctx, inviterID.String())
+	if err != nil {
+		return fmt.Errorf("failed to load inviter: %w", err)
+	}
+	policy := s.rewardPolicy
+	if inviter.HasUnlimitedAccess() {
+		policy = policy.BadgesOnly()
+	}
+
+	invites, err := tx.CountInvitesForUpdate(ctx, inviterID)
+	if err != nil {
+		return err
+	}
+
+	for _, reward := range policy.RewardsFor(inviterID, referralLogID, invites) {
+		credited, err := tx.CreditReward(ctx, reward)
+		if err != nil {
+			return err
+		}
+		if credited {
+			s.logger.Info("Credited referral reward",
+				logger.Field{Key: "module", Value: "referral_code_service"},
+				logger.Field{Key: "user_id", Value: inviterID.String()},
+				logger.Field{Key: "referral_log_id", Value: referralLogID.String()},
+				logger.Field{Key: "reward_type", Value: reward.Type},
+				logger.Field{Key: "amount", Value: reward.Amount})
+		}
+	}
+	return nil
+}
+
+// mistypedReferralResponse tells the user to check the code; the check character is
+// public, so this reveals nothing about which codes exist
+func mistypedReferralResponse() *referral_code.ActivateReferralResponse {
+	return &referral_code.ActivateReferralResponse{
+		Success: false,
+		Message: "Referral code looks mistyped, please check it and try again",
+		Reason:  referral_code.ReasonMistyped,
+	}
+}
+
+func alreadyActivatedReferralResponse() *referral_code.ActivateReferralResponse {
+	return &referral_code.ActivateReferralResponse{
+		Success: false,
+		Message: "User has already activated referral code",
+	}
+}
+
+func invalidReferralResponse() *referral_code.ActivateReferralResponse {
+	return &referral_code.ActivateReferralResponse{
+		Success: false,
+		Message: "Referral code is invalid",
+	}
+}
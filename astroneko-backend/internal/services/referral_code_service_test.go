@@ -0,0 +1,1056 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type referralCodeTestMocks struct {
+	referralRepo *mock_ports.ReferralCodeRepositoryInterface
+	tx           *mock_ports.ReferralCodeRepositoryInterface
+	userRepo     *mock_ports.MockUserRepositoryInterface
+	logger       *mock_logger.MockLoggerInterface
+}
+
+func newTestReferralCodeService(ctrl *gomock.Controller) (*ReferralCodeService, *referralCodeTestMocks) {
+	mocks := &referralCodeTestMocks{
+		referralRepo: mock_ports.NewReferralCodeRepositoryInterface(ctrl),
+		tx:           mock_ports.NewReferralCodeRepositoryInterface(ctrl),
+		userRepo:     mock_ports.NewMockUserRepositoryInterface(ctrl),
+		logger:       mock_logger.NewMockLoggerInterface(ctrl),
+	}
+	service := NewReferralCodeService(mocks.referralRepo, mocks.userRepo, mock_ports.NewMockCredentialAttemptServiceInterface(ctrl), referral_reward.DefaultPolicy, referral_code.DefaultAllotmentPolicy, referral_code.DefaultVanityPolicy, mocks.logger)
+	return service, mocks
+}
+
+// expectReferralTx runs the transaction body against mocks.tx and returns its error, as WithTx does
+func expectReferralTx(mocks *referralCodeTestMocks) {
+	mocks.referralRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(tx referralCodePorts.RepositoryInterface) error) error {
+			return fn(mocks.tx)
+		})
+}
+
+func buildTestReferralUser(activated bool) *user.User {
+	u := &user.User{Email: "redeemer@example.com", IsActivatedReferral: activated}
+	u.ID = uuid.New()
+	return u
+}
+
+// expectInviter makes the owner of code an inviter on tier
+func expectInviter(mocks *referralCodeTestMocks, code *referral_code.UserReferralCode, tier string) {
+	inviter := &user.User{Email: "inviter@example.com", IsActivatedReferral: true, ReferralTier: &tier}
+	inviter.ID = code.UserID
+	mocks.userRepo.EXPECT().GetByID(gomock.Any(), code.UserID.String()).Return(inviter, nil)
+}
+
+func buildTestUserReferralCode(activated bool) *referral_code.UserReferralCode {
+	code := &referral_code.UserReferralCode{UserID: uuid.New(), ReferralCode: "ABCDEFGH", IsActivated: activated}
+	code.ID = uuid.New()
+	return code
+}
+
+func TestReferralCodeService_ActivateReferralCode_UserCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+	logID := uuid.New()
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectInviter(mocks, code, user.TierPlus)
+	expectReferralTx(mocks)
+	gomock.InOrder(
+		mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil),
+		mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(code, nil),
+		mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(true, nil),
+		mocks.tx.EXPECT().UpdateUserReferralCode(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, updated *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error) {
+				assert.True(t, updated.IsActivated)
+				return updated, nil
+			}),
+		mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+				assert.Equal(t, redeemer.ID, log.RedeemedByUserID)
+				assert.Equal(t, "user", log.CodeType)
+				assert.Equal(t, code.ID, *log.ReferralCodeID)
+				log.ID = logID
+				return log, nil
+			}),
+		mocks.tx.EXPECT().AttributeClick(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, log *referral_code.ReferralLog) error {
+				assert.Equal(t, logID, log.ID)
+				return nil
+			}),
+		mocks.tx.EXPECT().CountInvitesForUpdate(ctx, code.UserID).Return(int64(1), nil),
+	)
+	var credited []referral_reward.Reward
+	mocks.tx.EXPECT().CreditReward(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, reward *referral_reward.Reward) (bool, error) {
+			credited = append(credited, *reward)
+			return true, nil
+		}).Times(3)
+	mocks.logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	require.Len(t, credited, 3)
+	for _, reward := range credited {
+		assert.Equal(t, code.UserID, reward.UserID)
+		assert.Equal(t, logID, reward.ReferralLogID)
+	}
+	assert.Equal(t, referral_reward.TypeBonusReplies, credited[0].Type)
+	assert.Equal(t, referral_reward.TypeDailyReplies, credited[1].Type)
+	assert.Equal(t, "first_invite", *credited[2].Badge)
+}
+
+func TestReferralCodeService_ActivateReferralCode_VanityCodeStaysOpen(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	service.rewardPolicy = referral_reward.Policy{}
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+	code.ReferralCode = "NEKOLOVER"
+	code.IsVanity = true
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectInviter(mocks, code, user.TierPlus)
+	expectReferralTx(mocks)
+	gomock.InOrder(
+		mocks.tx.EXPECT().IsValidReferralCode(ctx, "NEKOLOVER").Return(false, nil),
+		mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "NEKOLOVER").Return(code, nil),
+		mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(true, nil),
+		mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+				log.ID = uuid.New()
+				return log, nil
+			}),
+		mocks.tx.EXPECT().AttributeClick(ctx, gomock.Any()).Return(nil),
+		mocks.tx.EXPECT().CountInvitesForUpdate(ctx, code.UserID).Return(int64(4), nil),
+	)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, " neko lover ")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.False(t, code.IsActivated)
+}
+
+func TestReferralCodeService_ActivateReferralCode_RewardAlreadyCredited(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	service.rewardPolicy = referral_reward.Policy{BonusReplies: 5}
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectInviter(mocks, code, user.TierPlus)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(code, nil)
+	mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(true, nil)
+	mocks.tx.EXPECT().UpdateUserReferralCode(ctx, gomock.Any()).Return(code, nil)
+	mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+			return log, nil
+		})
+	mocks.tx.EXPECT().AttributeClick(ctx, gomock.Any()).Return(nil)
+	mocks.tx.EXPECT().CountInvitesForUpdate(ctx, code.UserID).Return(int64(2), nil)
+	mocks.tx.EXPECT().CreditReward(ctx, gomock.Any()).Return(false, nil)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+}
+
+func TestReferralCodeService_ActivateReferralCode_UnlimitedInviterEarnsBadgesOnly(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectInviter(mocks, code, user.TierUnlimited)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(code, nil)
+	mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(true, nil)
+	mocks.tx.EXPECT().UpdateUserReferralCode(ctx, gomock.Any()).Return(code, nil)
+	mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+			return log, nil
+		})
+	mocks.tx.EXPECT().AttributeClick(ctx, gomock.Any()).Return(nil)
+	mocks.tx.EXPECT().CountInvitesForUpdate(ctx, code.UserID).Return(int64(1), nil)
+	var credited []referral_reward.Reward
+	mocks.tx.EXPECT().CreditReward(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, reward *referral_reward.Reward) (bool, error) {
+			credited = append(credited, *reward)
+			return true, nil
+		})
+	mocks.logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	require.Len(t, credited, 1)
+	assert.Equal(t, referral_reward.TypeBadge, credited[0].Type)
+	assert.Equal(t, "first_invite", *credited[0].Badge)
+}
+
+func TestReferralCodeService_ActivateReferralCode_UsedCodeIsInvalid(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(buildTestUserReferralCode(true), nil)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Equal(t, "Referral code is invalid", response.Message)
+}
+
+func TestReferralCodeService_ActivateReferralCode_ExpiredCodeIsInvalid(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+	code.ExpiresAt = &now
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(code, nil)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Equal(t, "Referral code is invalid", response.Message)
+}
+
+func TestReferralCodeService_ActivateReferralCode_MistypedCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	issued := referral_code.WithCheckChar("K7MPQ2XZ")
+	typed := issued[:2] + "N" + issued[3:]
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, typed).Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, typed).Return(nil, errors.New("record not found"))
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, typed)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Equal(t, referral_code.ReasonMistyped, response.Reason)
+}
+
+func TestReferralCodeService_ActivateReferralCode_UserAlreadyActivatedConcurrently(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	general := &referral_code.ReferralCode{ReferralCode: "SUMMER"}
+	general.ID = uuid.New()
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "SUMMER").Return(true, nil)
+	mocks.tx.EXPECT().GetByReferralCodeForUpdate(ctx, "SUMMER").Return(general, nil)
+	mocks.tx.EXPECT().CountRedemptions(ctx, general.ID).Return(int64(0), nil)
+	mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(false, nil)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "SUMMER")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Equal(t, "User has already activated referral code", response.Message)
+}
+
+func buildTestGeneralReferralCode(settings referral_code.CodeSettings) *referral_code.ReferralCode {
+	code := &referral_code.ReferralCode{ReferralCode: "SUMMER"}
+	code.ID = uuid.New()
+	settings.ApplyTo(code)
+	return code
+}
+
+func TestReferralCodeService_ActivateReferralCode_GeneralCodeGrantsBonusQuota(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	maxRedemptions := 100
+	general := buildTestGeneralReferralCode(referral_code.CodeSettings{
+		MaxRedemptions: &maxRedemptions,
+		GrantType:      referral_code.GrantBonusQuota,
+		BonusQuota:     25,
+	})
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	expectReferralTx(mocks)
+	gomock.InOrder(
+		mocks.tx.EXPECT().IsValidReferralCode(ctx, "SUMMER").Return(true, nil),
+		mocks.tx.EXPECT().GetByReferralCodeForUpdate(ctx, "SUMMER").Return(general, nil),
+		mocks.tx.EXPECT().CountRedemptions(ctx, general.ID).Return(int64(99), nil),
+		mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierFree, 25).Return(true, nil),
+		mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+				assert.Equal(t, "general", log.CodeType)
+				assert.Equal(t, general.ID, *log.ReferralCodeID)
+				return log, nil
+			}),
+		mocks.tx.EXPECT().AttributeClick(ctx, gomock.Any()).Return(nil),
+	)
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "SUMMER")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, user.TierFree, response.Tier)
+	assert.Equal(t, 25, response.BonusQuota)
+}
+
+func TestReferralCodeService_ActivateReferralCode_GeneralCodeUnavailable(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+	maxRedemptions := 10
+
+	tests := []struct {
+		name        string
+		settings    referral_code.CodeSettings
+		redemptions int64
+		reason      string
+	}{
+		{name: "not started", settings: referral_code.CodeSettings{StartsAt: &tomorrow}, reason: "not_started"},
+		{name: "expired", settings: referral_code.CodeSettings{EndsAt: &yesterday}, reason: "expired"},
+		{name: "exhausted", settings: referral_code.CodeSettings{MaxRedemptions: &maxRedemptions}, redemptions: 10, reason: "exhausted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mocks := newTestReferralCodeService(ctrl)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+			redeemer := buildTestReferralUser(false)
+			general := buildTestGeneralReferralCode(tt.settings)
+
+			mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+			expectReferralTx(mocks)
+			mocks.tx.EXPECT().IsValidReferralCode(ctx, "SUMMER").Return(true, nil)
+			mocks.tx.EXPECT().GetByReferralCodeForUpdate(ctx, "SUMMER").Return(general, nil)
+			mocks.tx.EXPECT().CountRedemptions(ctx, general.ID).Return(tt.redemptions, nil)
+
+			// Act
+			response, err := service.ActivateReferralCode(ctx, redeemer.ID, "SUMMER")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.False(t, response.Success)
+			assert.Equal(t, tt.reason, response.Reason)
+		})
+	}
+}
+
+func TestReferralCodeService_ActivateReferralCode_LogFailureRollsBack(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	redeemer := buildTestReferralUser(false)
+	code := buildTestUserReferralCode(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, redeemer.ID.String()).Return(redeemer, nil)
+	var txErr error
+	mocks.referralRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(tx referralCodePorts.RepositoryInterface) error) error {
+			txErr = fn(mocks.tx)
+			return txErr
+		})
+	mocks.tx.EXPECT().IsValidReferralCode(ctx, "ABCDEFGH").Return(false, nil)
+	mocks.tx.EXPECT().GetUserReferralCodeByCodeForUpdate(ctx, "ABCDEFGH").Return(code, nil)
+	mocks.tx.EXPECT().MarkUserReferralActivated(ctx, redeemer.ID, user.TierUnlimited, 0).Return(true, nil)
+	mocks.tx.EXPECT().UpdateUserReferralCode(ctx, gomock.Any()).Return(code, nil)
+	mocks.tx.EXPECT().CreateReferralLog(ctx, gomock.Any()).Return(nil, errors.New("connection reset"))
+
+	// Act
+	response, err := service.ActivateReferralCode(ctx, redeemer.ID, "ABCDEFGH")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Error(t, txErr, "the transaction body must fail so that the user flag and code are rolled back")
+}
+
+// lockingReferralStore is an in-memory stand-in for the referral tables. Writes are
+// staged per transaction and applied on commit, and ForUpdate reads hold a row lock
+// until the transaction ends, as in PostgreSQL.
+type lockingReferralStore struct {
+	referralCodePorts.RepositoryInterface
+	mu        sync.Mutex
+	rowLocks  map[string]*sync.Mutex
+	codes     map[string]referral_code.UserReferralCode
+	activated map[uuid.UUID]bool
+	logs      []referral_code.ReferralLog
+}
+
+func newLockingReferralStore(codes ...referral_code.UserReferralCode) *lockingReferralStore {
+	store := &lockingReferralStore{
+		rowLocks:  make(map[string]*sync.Mutex),
+		codes:     make(map[string]referral_code.UserReferralCode),
+		activated: make(map[uuid.UUID]bool),
+	}
+	for _, code := range codes {
+		store.codes[code.ReferralCode] = code
+	}
+	return store
+}
+
+func (s *lockingReferralStore) rowLock(key string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rowLocks[key] == nil {
+		s.rowLocks[key] = &sync.Mutex{}
+	}
+	return s.rowLocks[key]
+}
+
+// lockingReferralTx implements only the repository methods used during activation
+type lockingReferralTx struct {
+	referralCodePorts.RepositoryInterface
+	store     *lockingReferralStore
+	held      []*sync.Mutex
+	codes     []referral_code.UserReferralCode
+	activated []uuid.UUID
+	logs      []referral_code.ReferralLog
+}
+
+func (s *lockingReferralStore) WithTx(ctx context.Context, fn func(tx referralCodePorts.RepositoryInterface) error) error {
+	tx := &lockingReferralTx{store: s}
+	defer func() {
+		for _, lock := range tx.held {
+			lock.Unlock()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, code := range tx.codes {
+		s.codes[code.ReferralCode] = code
+	}
+	for _, userID := range tx.activated {
+		s.activated[userID] = true
+	}
+	s.logs = append(s.logs, tx.logs...)
+	return nil
+}
+
+func (tx *lockingReferralTx) lock(key string) {
+	lock := tx.store.rowLock(key)
+	lock.Lock()
+	tx.held = append(tx.held, lock)
+}
+
+func (tx *lockingReferralTx) IsValidReferralCode(ctx context.Context, code string) (bool, error) {
+	return false, nil
+}
+
+func (tx *lockingReferralTx) GetUserReferralCodeByCodeForUpdate(ctx context.Context, code string) (*referral_code.UserReferralCode, error) {
+	tx.lock("code:" + code)
+	tx.store.mu.Lock()
+	found, ok := tx.store.codes[code]
+	tx.store.mu.Unlock()
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+
+	// Query latency gives concurrent redemptions time to overlap
+	time.Sleep(time.Millisecond)
+	return &found, nil
+}
+
+func (tx *lockingReferralTx) MarkUserReferralActivated(ctx context.Context, userID uuid.UUID, tier string, bonusQuota int) (bool, error) {
+	tx.lock("user:" + userID.String())
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+	if tx.store.activated[userID] {
+		return false, nil
+	}
+	tx.activated = append(tx.activated, userID)
+	return true, nil
+}
+
+func (tx *lockingReferralTx) UpdateUserReferralCode(ctx context.Context, code *referral_code.UserReferralCode) (*referral_code.UserReferralCode, error) {
+	tx.codes = append(tx.codes, *code)
+	return code, nil
+}
+
+func (tx *lockingReferralTx) CreateReferralLog(ctx context.Context, log *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
+	tx.logs = append(tx.logs, *log)
+	return log, nil
+}
+
+func (tx *lockingReferralTx) AttributeClick(ctx context.Context, log *referral_code.ReferralLog) error {
+	return nil
+}
+
+func (tx *lockingReferralTx) CountInvitesForUpdate(ctx context.Context, inviterID uuid.UUID) (int64, error) {
+	tx.lock("user:" + inviterID.String())
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+	return int64(len(tx.store.logs) + len(tx.logs)), nil
+}
+
+// The store's row locks stand in for PostgreSQL, so this shows that activation reads the
+// code through the ForUpdate methods inside one transaction and redeems it once when they
+// serialize. That the repository really issues FOR UPDATE is checked in its own tests.
+func TestReferralCodeService_ActivateReferralCode_SingleUseCodeUnderRowLocks(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	code := *buildTestUserReferralCode(false)
+	store := newLockingReferralStore(code)
+	userRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	userRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (*user.User, error) {
+		u := &user.User{}
+		u.ID = uuid.MustParse(id)
+		return u, nil
+	}).AnyTimes()
+	service := &ReferralCodeService{referralCodeRepo: store, userRepo: userRepo, now: time.Now}
+
+	const redeemers = 20
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make(chan *referral_code.ActivateReferralResponse, redeemers)
+	for i := 0; i < redeemers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			response, err := service.ActivateReferralCode(context.Background(), uuid.New(), code.ReferralCode)
+			assert.NoError(t, err)
+			results <- response
+		}()
+	}
+
+	// Act
+	close(start)
+	wg.Wait()
+	close(results)
+
+	// Assert
+	successes := 0
+	for response := range results {
+		require.NotNil(t, response)
+		if response.Success {
+			successes++
+		} else {
+			assert.Equal(t, "Referral code is invalid", response.Message)
+		}
+	}
+	assert.Equal(t, 1, successes)
+	assert.Len(t, store.logs, 1)
+	assert.Len(t, store.activated, 1)
+	assert.True(t, store.codes[code.ReferralCode].IsActivated)
+}
+
+func TestReferralCodeService_GenerateReferralCodes_RedrawsCollisions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	maxRedemptions := 1
+	req := &referral_code.GenerateReferralCodesRequest{
+		Count:        3,
+		Prefix:       "INF",
+		Charset:      "digits",
+		Length:       6,
+		CodeSettings: referral_code.CodeSettings{MaxRedemptions: &maxRedemptions},
+	}
+
+	expectReferralTx(mocks)
+	gomock.InOrder(
+		mocks.tx.EXPECT().ExistingReferralCodes(ctx, gomock.Len(3)).DoAndReturn(
+			func(_ context.Context, codes []string) ([]string, error) {
+				return []string{codes[1]}, nil
+			}),
+		mocks.tx.EXPECT().ExistingReferralCodes(ctx, gomock.Len(1)).Return(nil, nil),
+		mocks.tx.EXPECT().CreateReferralCodes(ctx, gomock.Len(3)).Return(nil),
+	)
+
+	// Act
+	created, err := service.GenerateReferralCodes(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+	seen := map[string]bool{}
+	for _, code := range created {
+		assert.Regexp(t, `^INF[0-9]{6}$`, code.ReferralCode)
+		assert.False(t, seen[code.ReferralCode])
+		seen[code.ReferralCode] = true
+		assert.Equal(t, &maxRedemptions, code.MaxRedemptions)
+		assert.Equal(t, referral_code.GrantUnlimited, code.GrantType)
+	}
+}
+
+func TestReferralCodeService_GenerateReferralCodes_GivesUpAfterRetries(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	req := &referral_code.GenerateReferralCodesRequest{Count: 1, Length: 8}
+
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().ExistingReferralCodes(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, codes []string) ([]string, error) {
+			return []string{codes[0]}, nil
+		}).Times(maxCodeGenerationRounds)
+
+	// Act
+	created, err := service.GenerateReferralCodes(ctx, req)
+
+	// Assert
+	assert.ErrorIs(t, err, referral_code.ErrCodeSpaceExhausted)
+	assert.Nil(t, created)
+}
+
+func TestReferralCodeService_ImportReferralCodes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	campaign := "songkran"
+	rows := []referral_code.ImportRow{
+		{Line: 2, CreateReferralCodeRequest: referral_code.CreateReferralCodeRequest{ReferralCode: "SUMMER1", CodeSettings: referral_code.CodeSettings{Campaign: &campaign}}},
+		{Line: 3, CreateReferralCodeRequest: referral_code.CreateReferralCodeRequest{ReferralCode: "SUMMER2"}},
+	}
+
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().ExistingReferralCodes(ctx, []string{"SUMMER1", "SUMMER2"}).Return(nil, nil)
+	mocks.tx.EXPECT().CreateReferralCodes(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, codes []*referral_code.ReferralCode) error {
+			require.Len(t, codes, 2)
+			assert.Equal(t, "SUMMER1", codes[0].ReferralCode)
+			assert.Equal(t, &campaign, codes[0].Campaign)
+			assert.Equal(t, referral_code.GrantUnlimited, codes[1].GrantType)
+			return nil
+		})
+
+	// Act
+	result, err := service.ImportReferralCodes(ctx, rows, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Empty(t, result.Errors)
+}
+
+func TestReferralCodeService_ImportReferralCodes_RejectsWholeFile(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	rows := []referral_code.ImportRow{
+		{Line: 2, CreateReferralCodeRequest: referral_code.CreateReferralCodeRequest{ReferralCode: "Taken"}},
+		{Line: 4, CreateReferralCodeRequest: referral_code.CreateReferralCodeRequest{ReferralCode: "FRESH"}},
+		{Line: 5, CreateReferralCodeRequest: referral_code.CreateReferralCodeRequest{ReferralCode: "fresh"}},
+	}
+	rowErrors := []referral_code.RowError{{Line: 3, Error: "max_redemptions \"x\" is not a number"}}
+
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().ExistingReferralCodes(ctx, []string{"TAKEN", "FRESH"}).Return([]string{"TAKEN"}, nil)
+
+	// Act
+	result, err := service.ImportReferralCodes(ctx, rows, rowErrors)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, []referral_code.RowError{
+		{Line: 2, ReferralCode: "Taken", Error: "referral code already exists"},
+		{Line: 3, Error: "max_redemptions \"x\" is not a number"},
+		{Line: 5, ReferralCode: "fresh", Error: "referral code repeats line 4"},
+	}, result.Errors)
+}
+
+func TestReferralCodeService_ExportReferralCodes_Pages(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+
+	firstPage := make([]*referral_code.CodeUsage, exportPageSize)
+	for i := range firstPage {
+		usage := &referral_code.CodeUsage{ReferralCode: referral_code.ReferralCode{ReferralCode: "CODE", GrantType: referral_code.GrantUnlimited}}
+		usage.ID = uuid.New()
+		usage.CreatedAt = time.Date(2026, 7, 1, 0, 0, i, 0, time.UTC)
+		firstPage[i] = usage
+	}
+	last := firstPage[len(firstPage)-1]
+	lastUsage := &referral_code.CodeUsage{ReferralCode: referral_code.ReferralCode{ReferralCode: "LAST", GrantType: referral_code.GrantUnlimited}, UsedCount: 7}
+
+	gomock.InOrder(
+		mocks.referralRepo.EXPECT().ListUsageAfter(ctx, nil, exportPageSize).Return(firstPage, nil),
+		mocks.referralRepo.EXPECT().ListUsageAfter(ctx, &referral_code.ExportCursor{CreatedAt: last.CreatedAt, ID: last.ID}, exportPageSize).
+			Return([]*referral_code.CodeUsage{lastUsage}, nil),
+	)
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportReferralCodes(ctx, &out)
+
+	// Assert
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, exportPageSize+2)
+	assert.Equal(t, strings.Join(referral_code.ExportCSVHeader, ","), lines[0])
+	assert.Contains(t, lines[len(lines)-1], ",LAST,")
+	assert.Contains(t, lines[len(lines)-1], ",7,")
+}
+
+func TestReferralCodeService_GetOrGenerateUserReferralCodes_IssuesAllotment(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	service.allotment = referral_code.AllotmentPolicy{
+		CodesPerTier: map[string]int{user.TierPlus: 2},
+		DefaultCodes: 5,
+		ExpiresIn:    30 * 24 * time.Hour,
+		Length:       8,
+	}
+	ctx := context.Background()
+	plus := user.TierPlus
+	owner := buildTestReferralUser(true)
+	owner.ReferralTier = &plus
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+	mocks.referralRepo.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return(nil, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().LockCodeOwner(ctx, owner.ID).Return(nil)
+	mocks.tx.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return(nil, nil)
+	// The first draw collides with an existing code and is drawn again
+	gomock.InOrder(
+		mocks.tx.EXPECT().CreateUserReferralCodeIfAbsent(ctx, gomock.Any()).Return(false, nil),
+		mocks.tx.EXPECT().CreateUserReferralCodeIfAbsent(ctx, gomock.Any()).Return(true, nil).Times(2),
+	)
+
+	// Act
+	codes, err := service.GetOrGenerateUserReferralCodes(ctx, owner.ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+	for _, code := range codes {
+		assert.Equal(t, owner.ID, code.UserID)
+		assert.Len(t, code.ReferralCode, 9)
+		assert.False(t, referral_code.IsMistypedUserCode(code.ReferralCode))
+		assert.NotContains(t, code.ReferralCode, "0")
+		assert.Equal(t, now.AddDate(0, 0, 30), *code.ExpiresAt)
+	}
+}
+
+func TestReferralCodeService_GetOrGenerateUserReferralCodes_TopsUpAndHidesExpired(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	service.allotment = referral_code.AllotmentPolicy{DefaultCodes: 3, TopUpAfter: 2, Length: 8}
+	ctx := context.Background()
+	owner := buildTestReferralUser(true)
+	yesterday := now.AddDate(0, 0, -1)
+	existing := []*referral_code.UserReferralCode{
+		{ReferralCode: "USED", IsActivated: true},
+		{ReferralCode: "EXPIRED", ExpiresAt: &yesterday},
+		{ReferralCode: "USABLE"},
+	}
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+	mocks.referralRepo.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return(existing, nil)
+	expectReferralTx(mocks)
+	mocks.tx.EXPECT().LockCodeOwner(ctx, owner.ID).Return(nil)
+	mocks.tx.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return(existing, nil)
+	mocks.tx.EXPECT().CreateUserReferralCodeIfAbsent(ctx, gomock.Any()).Return(true, nil).Times(2)
+
+	// Act
+	codes, err := service.GetOrGenerateUserReferralCodes(ctx, owner.ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, codes, 4)
+	assert.Equal(t, "USED", codes[0].ReferralCode)
+	assert.Equal(t, "USABLE", codes[1].ReferralCode)
+}
+
+func TestReferralCodeService_GetOrGenerateUserReferralCodes_NothingDue(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	owner := buildTestReferralUser(true)
+	existing := make([]*referral_code.UserReferralCode, 5)
+	for i := range existing {
+		existing[i] = &referral_code.UserReferralCode{IsActivated: i < 3}
+	}
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+	mocks.referralRepo.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return(existing, nil)
+
+	// Act
+	codes, err := service.GetOrGenerateUserReferralCodes(ctx, owner.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, codes, 5)
+}
+
+func TestReferralCodeService_GetOrGenerateUserReferralCodes_NotActivated(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	owner := buildTestReferralUser(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+
+	// Act
+	codes, err := service.GetOrGenerateUserReferralCodes(ctx, owner.ID)
+
+	// Assert
+	assert.EqualError(t, err, "user has not activated referral feature")
+	assert.Nil(t, codes)
+}
+
+func TestReferralCodeService_ClaimVanityCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	owner := buildTestReferralUser(true)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+	expectReferralTx(mocks)
+	gomock.InOrder(
+		mocks.tx.EXPECT().CountInvitesForUpdate(ctx, owner.ID).Return(int64(3), nil),
+		mocks.tx.EXPECT().GetUserReferralCodesByUserID(ctx, owner.ID).Return([]*referral_code.UserReferralCode{buildTestUserReferralCode(true)}, nil),
+		mocks.tx.EXPECT().CreateUserReferralCodeIfAbsent(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, code *referral_code.UserReferralCode) (bool, error) {
+				assert.Equal(t, owner.ID, code.UserID)
+				assert.True(t, code.IsVanity)
+				assert.Nil(t, code.ExpiresAt)
+				return true, nil
+			}),
+	)
+	mocks.logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	code, err := service.ClaimVanityCode(ctx, owner.ID, "Neko Lover")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "NEKOLOVER", code.ReferralCode)
+	assert.True(t, code.IsVanity)
+}
+
+func TestReferralCodeService_ClaimVanityCode_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		arrange func(mocks *referralCodeTestMocks, ownerID uuid.UUID)
+		wantErr error
+	}{
+		{
+			name:    "blocked word",
+			code:    "adm1n",
+			wantErr: referral_code.ErrVanityCodeRejected,
+		},
+		{
+			name: "too few invites",
+			code: "NEKOLOVER",
+			arrange: func(mocks *referralCodeTestMocks, ownerID uuid.UUID) {
+				mocks.tx.EXPECT().CountInvitesForUpdate(gomock.Any(), ownerID).Return(int64(2), nil)
+			},
+			wantErr: referral_code.ErrVanityNotEligible,
+		},
+		{
+			name: "already claimed",
+			code: "NEKOLOVER",
+			arrange: func(mocks *referralCodeTestMocks, ownerID uuid.UUID) {
+				mocks.tx.EXPECT().CountInvitesForUpdate(gomock.Any(), ownerID).Return(int64(5), nil)
+				mocks.tx.EXPECT().GetUserReferralCodesByUserID(gomock.Any(), ownerID).Return([]*referral_code.UserReferralCode{{ReferralCode: "MOONCAT", IsVanity: true}}, nil)
+			},
+			wantErr: referral_code.ErrVanityAlreadyClaimed,
+		},
+		{
+			name: "taken by a general or user code",
+			code: "NEKOLOVER",
+			arrange: func(mocks *referralCodeTestMocks, ownerID uuid.UUID) {
+				mocks.tx.EXPECT().CountInvitesForUpdate(gomock.Any(), ownerID).Return(int64(5), nil)
+				mocks.tx.EXPECT().GetUserReferralCodesByUserID(gomock.Any(), ownerID).Return(nil, nil)
+				mocks.tx.EXPECT().CreateUserReferralCodeIfAbsent(gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			wantErr: referral_code.ErrVanityCodeTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mocks := newTestReferralCodeService(ctrl)
+			ctx := context.Background()
+			owner := buildTestReferralUser(true)
+
+			mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+			if tt.arrange != nil {
+				expectReferralTx(mocks)
+				tt.arrange(mocks, owner.ID)
+			}
+
+			// Act
+			code, err := service.ClaimVanityCode(ctx, owner.ID, tt.code)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, code)
+		})
+	}
+}
+
+func TestReferralCodeService_ClaimVanityCode_ReferralNotActivated(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralCodeService(ctrl)
+	ctx := context.Background()
+	owner := buildTestReferralUser(false)
+
+	mocks.userRepo.EXPECT().GetByID(ctx, owner.ID.String()).Return(owner, nil)
+
+	// Act
+	code, err := service.ClaimVanityCode(ctx, owner.ID, "NEKOLOVER")
+
+	// Assert
+	assert.ErrorIs(t, err, referral_code.ErrVanityNotEligible)
+	assert.Nil(t, code)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+	referralGraphPorts "astroneko-backend/internal/core/ports/referral_graph"
+	"astroneko-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// ReferralGraphService resolves who invited whom from user referral code redemptions
+type ReferralGraphService struct {
+	graphRepo referralGraphPorts.RepositoryInterface
+	logger    logger.Logger
+	now       func() time.Time
+}
+
+// NewReferralGraphService creates a new referral graph service instance
+func NewReferralGraphService(graphRepo referralGraphPorts.RepositoryInterface, log logger.Logger) *ReferralGraphService {
+	return &ReferralGraphService{
+		graphRepo: graphRepo,
+		logger:    log,
+		now:       time.Now,
+	}
+}
+
+// loadTrees fetches the invite trees below the roots one level at a time, so the
+// number of queries grows with the tree depth rather than the number of users
+func (s *ReferralGraphService) loadTrees(ctx context.Context, roots []uuid.UUID) (referral_graph.Children, error) {
+	children := referral_graph.Children{}
+	seen := make(map[uuid.UUID]bool, len(roots))
+	for _, root := range roots {
+		seen[root] = true
+	}
+
+	level := roots
+	for depth := 1; depth <= referral_graph.MaxTreeDepth && len(level) > 0; depth++ {
+		edges, err := s.graphRepo.ListInvitees(ctx, level)
+		if err != nil {
+			return nil, err
+		}
+		children.Add(edges)
+
+		var next []uuid.UUID
+		for _, edge := range edges {
+			if !seen[edge.InviteeID] {
+				seen[edge.InviteeID] = true
+				next = append(next, edge.InviteeID)
+			}
+		}
+		level = next
+	}
+
+	return children, nil
+}
+
+// GetMyReferrals returns the user's direct invitees, anonymized, with their activity and
+// own invite counts, and the size and depth of the user's whole invite tree
+func (s *ReferralGraphService) GetMyReferrals(ctx context.Context, userID uuid.UUID) (*referral_graph.MyReferralsResponse, error) {
+	children, err := s.loadTrees(ctx, []uuid.UUID{userID})
+	if err != nil {
+		s.logger.Error("Failed to load referral tree",
+			logger.Field{Key: "module", Value: "referral_graph_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to load referrals: %w", err)
+	}
+
+	now := s.now()
+	direct := children[userID]
+	invitees := make([]referral_graph.InviteeResponse, 0, len(direct))
+	active := 0
+	for _, edge := range direct {
+		isActive := edge.IsActive(now)
+		if isActive {
+			active++
+		}
+		invitees = append(invitees, referral_graph.InviteeResponse{
+			Name:        referral_graph.AnonymizeInvitee(edge.InviteeDisplayName, edge.InviteeEmail),
+			JoinedAt:    edge.RedeemedAt,
+			IsActive:    isActive,
+			InviteCount: len(children[edge.InviteeID]),
+		})
+	}
+
+	downline := children.Downline(userID)
+	return &referral_graph.MyReferralsResponse{
+		InviteCount:       len(direct),
+		ActiveInviteCount: active,
+		ConversionRate:    referral_graph.ConversionRate(int64(active), int64(len(direct))),
+		DownlineCount:     downline.Size,
+		TreeDepth:         downline.Depth,
+		Invitees:          invitees,
+	}, nil
+}
+
+// GetLeaderboard ranks inviters by direct invites in the filter's date range. An invitee
+// is active when they logged in within referral_graph.ActiveWindow of now.
+func (s *ReferralGraphService) GetLeaderboard(ctx context.Context, filter referral_graph.LeaderboardFilter) (*referral_graph.LeaderboardResponse, error) {
+	filter.ActiveSince = s.now().Add(-referral_graph.ActiveWindow)
+	rows, total, err := s.graphRepo.Leaderboard(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		roots = append(roots, row.InviterID)
+	}
+	children, err := s.loadTrees(ctx, roots)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]referral_graph.LeaderboardEntryResponse, 0, len(rows))
+	for i, row := range rows {
+		downline := children.Downline(row.InviterID)
+		entries = append(entries, referral_graph.LeaderboardEntryResponse{
+			Rank:              filter.Offset + i + 1,
+			UserID:            row.InviterID,
+			Email:             row.InviterEmail,
+			InviteCount:       row.Invites,
+			ActiveInviteCount: row.ActiveInvites,
+			ConversionRate:    referral_graph.ConversionRate(row.ActiveInvites, row.Invites),
+			DownlineCount:     downline.Size,
+			TreeDepth:         downline.Depth,
+			FirstInviteAt:     row.FirstInviteAt,
+			LastInviteAt:      row.LastInviteAt,
+		})
+	}
+
+	return &referral_graph.LeaderboardResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_graph"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReferralGraphService(ctrl *gomock.Controller, now time.Time) (*ReferralGraphService, *mock_ports.MockReferralGraphRepositoryInterface, *mock_logger.MockLoggerInterface) {
+	graphRepo := mock_ports.NewMockReferralGraphRepositoryInterface(ctrl)
+	log := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewReferralGraphService(graphRepo, log)
+	service.now = func() time.Time { return now }
+	return service, graphRepo, log
+}
+
+func TestReferralGraphService_GetMyReferrals(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service, graphRepo, _ := newTestReferralGraphService(ctrl, now)
+	ctx := context.Background()
+
+	me, alice, bob, carol := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	aliceName := "alice"
+	bobEmail := "bob@example.com"
+	lastWeek := now.AddDate(0, 0, -7)
+
+	gomock.InOrder(
+		graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{me}).Return([]referral_graph.Edge{
+			{InviterID: me, InviteeID: alice, InviteeDisplayName: &aliceName, InviteeLatestLoginAt: &lastWeek, RedeemedAt: now.AddDate(0, -1, 0)},
+			{InviterID: me, InviteeID: bob, InviteeEmail: &bobEmail, RedeemedAt: now.AddDate(0, -2, 0)},
+		}, nil),
+		graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{alice, bob}).Return([]referral_graph.Edge{
+			{InviterID: alice, InviteeID: carol},
+		}, nil),
+		graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{carol}).Return(nil, nil),
+	)
+
+	// Act
+	referrals, err := service.GetMyReferrals(ctx, me)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, referrals.InviteCount)
+	assert.Equal(t, 1, referrals.ActiveInviteCount)
+	assert.Equal(t, 0.5, referrals.ConversionRate)
+	assert.Equal(t, 3, referrals.DownlineCount)
+	assert.Equal(t, 2, referrals.TreeDepth)
+	require.Len(t, referrals.Invitees, 2)
+	assert.Equal(t, referral_graph.InviteeResponse{Name: "A***", JoinedAt: now.AddDate(0, -1, 0), IsActive: true, InviteCount: 1}, referrals.Invitees[0])
+	assert.Equal(t, referral_graph.InviteeResponse{Name: "B***", JoinedAt: now.AddDate(0, -2, 0), IsActive: false, InviteCount: 0}, referrals.Invitees[1])
+}
+
+func TestReferralGraphService_GetMyReferrals_NoInvitees(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, graphRepo, _ := newTestReferralGraphService(ctrl, time.Now())
+	ctx := context.Background()
+	me := uuid.New()
+
+	graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{me}).Return(nil, nil)
+
+	// Act
+	referrals, err := service.GetMyReferrals(ctx, me)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, referrals.InviteCount)
+	assert.NotNil(t, referrals.Invitees)
+}
+
+func TestReferralGraphService_GetMyReferrals_RepositoryError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, graphRepo, log := newTestReferralGraphService(ctrl, time.Now())
+	ctx := context.Background()
+
+	graphRepo.EXPECT().ListInvitees(ctx, gomock.Any()).Return(nil, errors.New("connection reset"))
+	log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	referrals, err := service.GetMyReferrals(ctx, uuid.New())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, referrals)
+}
+
+func TestReferralGraphService_GetLeaderboard(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service, graphRepo, _ := newTestReferralGraphService(ctrl, now)
+	ctx := context.Background()
+
+	top, second, invitee := uuid.New(), uuid.New(), uuid.New()
+	from := now.AddDate(0, -1, 0)
+	filter := referral_graph.LeaderboardFilter{From: &from, Limit: 20, Offset: 20}
+
+	graphRepo.EXPECT().Leaderboard(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, got referral_graph.LeaderboardFilter) ([]referral_graph.LeaderboardRow, int64, error) {
+			assert.Equal(t, now.Add(-referral_graph.ActiveWindow), got.ActiveSince)
+			assert.Equal(t, &from, got.From)
+			return []referral_graph.LeaderboardRow{
+				{InviterID: top, Invites: 4, ActiveInvites: 3},
+				{InviterID: second, Invites: 1, ActiveInvites: 0},
+			}, 22, nil
+		})
+	// The second inviter was invited by the top one, whose downline includes them
+	gomock.InOrder(
+		graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{top, second}).Return([]referral_graph.Edge{
+			{InviterID: top, InviteeID: second},
+			{InviterID: second, InviteeID: invitee},
+		}, nil),
+		graphRepo.EXPECT().ListInvitees(ctx, []uuid.UUID{invitee}).Return(nil, nil),
+	)
+
+	// Act
+	leaderboard, err := service.GetLeaderboard(ctx, filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(22), leaderboard.Total)
+	require.Len(t, leaderboard.Entries, 2)
+	assert.Equal(t, 21, leaderboard.Entries[0].Rank)
+	assert.Equal(t, 0.75, leaderboard.Entries[0].ConversionRate)
+	assert.Equal(t, 2, leaderboard.Entries[0].DownlineCount)
+	assert.Equal(t, 2, leaderboard.Entries[0].TreeDepth)
+	assert.Equal(t, 22, leaderboard.Entries[1].Rank)
+	assert.Equal(t, 1, leaderboard.Entries[1].DownlineCount)
+	assert.Equal(t, 1, leaderboard.Entries[1].TreeDepth)
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
+	referralLinkPorts "astroneko-backend/internal/core/ports/referral_link"
+	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// ReferralLinkService builds deep links and QR codes for referral codes and tracks the
+// clicks on them through signup to redemption
+type ReferralLinkService struct {
+	linkRepo         referralLinkPorts.RepositoryInterface
+	referralCodeRepo referralCodePorts.RepositoryInterface
+	links            referral_link.LinkPolicy
+	logger           logger.Logger
+	now              func() time.Time
+}
+
+// NewReferralLinkService creates a new referral link service instance
+func NewReferralLinkService(linkRepo referralLinkPorts.RepositoryInterface, referralCodeRepo referralCodePorts.RepositoryInterface, links referral_link.LinkPolicy, log logger.Logger) *ReferralLinkService {
+	return &ReferralLinkService{
+		linkRepo:         linkRepo,
+		referralCodeRepo: referralCodeRepo,
+		links:            links,
+		logger:           log,
+		now:              time.Now,
+	}
+}
+
+// FollowLink records a click on the code's deep link and returns the landing page to
+// redirect the visitor to. Unknown codes are not recorded but still reach the landing
+// page, and so does a click whose code could not be looked up or that could not be
+// saved: the link itself must keep working.
+func (s *ReferralLinkService) FollowLink(ctx context.Context, code string, utm referral_link.UTMParams, referrer, userAgent string) (string, error) {
+	if !s.links.Enabled() {
+		return "", referral_link.ErrLinksDisabled
+	}
+
+	code = referral_code.NormalizeCode(code)
+	click, err := s.newClick(ctx, code, utm, referrer, userAgent)
+	if err != nil {
+		s.logger.Error("Failed to look up referral link code",
+			logger.Field{Key: "module", Value: "referral_link_service"},
+			logger.Field{Key: "referral_code", Value: code},
+			logger.Field{Key: "error", Value: err.Error()})
+		return s.links.LandingLink(code, nil, utm), nil
+	}
+	if click == nil {
+		return s.links.LandingLink(code, nil, utm), nil
+	}
+
+	if err := s.linkRepo.CreateClick(ctx, click); err != nil {
+		s.logger.Error("Failed to record referral link click",
+			logger.Field{Key: "module", Value: "referral_link_service"},
+			logger.Field{Key: "referral_code", Value: code},
+			logger.Field{Key: "error", Value: err.Error()})
+		return s.links.LandingLink(code, nil, utm), nil
+	}
+	return s.links.LandingLink(code, &click.ID, utm), nil
+}
+
+// LandingLink returns the landing page for a visit that is not recorded as a click,
+// such as a repeat visit over the click rate limit
+func (s *ReferralLinkService) LandingLink(code string, utm referral_link.UTMParams) (string, error) {
+	if !s.links.Enabled() {
+		return "", referral_link.ErrLinksDisabled
+	}
+	return s.links.LandingLink(referral_code.NormalizeCode(code), nil, utm), nil
+}
+
+// newClick resolves the code to a general or user code, or returns nil when it is neither
+func (s *ReferralLinkService) newClick(ctx context.Context, code string, utm referral_link.UTMParams, referrer, userAgent string) (*referral_link.Click, error) {
+	isGeneral, err := s.referralCodeRepo.IsValidReferralCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if isGeneral {
+		generalCode, err := s.referralCodeRepo.GetByReferralCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		return referral_link.NewClick(referral_link.CodeTypeGeneral, generalCode.ID, utm, referrer, userAgent), nil
+	}
+
+	userCode, err := s.referralCodeRepo.GetUserReferralCodeByCode(ctx, code)
+	if err != nil {
+		return nil, nil
+	}
+	return referral_link.NewClick(referral_link.CodeTypeUser, userCode.ID, utm, referrer, userAgent), nil
+}
+
+// DeepLink is the code's short link, or empty when links are not configured
+func (s *ReferralLinkService) DeepLink(code string, utm referral_link.UTMParams) string {
+	return s.links.DeepLink(code, utm)
+}
+
+// GetUserCodeQR renders the deep link of one of the user's own codes. The link is tagged
+// utm_medium=qr so scans show up separately in the funnel.
+func (s *ReferralLinkService) GetUserCodeQR(ctx context.Context, userID uuid.UUID, code, format string, size int) (*referral_link.QRCode, error) {
+	if !s.links.Enabled() {
+		return nil, referral_link.ErrLinksDisabled
+	}
+
+	userCode, err := s.referralCodeRepo.GetUserReferralCodeByCode(ctx, code)
+	if err != nil || userCode.UserID != userID {
+		return nil, referral_link.ErrCodeNotFound
+	}
+	return renderQRCode(s.links.DeepLink(userCode.ReferralCode, referral_link.UTMParams{Medium: "qr"}), format, size)
+}
+
+// GetGeneralCodeLink returns the deep link of a general code for the CRM
+func (s *ReferralLinkService) GetGeneralCodeLink(ctx context.Context, id string) (*referral_link.LinkResponse, error) {
+	if !s.links.Enabled() {
+		return nil, referral_link.ErrLinksDisabled
+	}
+
+	generalCode, err := s.referralCodeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, referral_link.ErrCodeNotFound
+	}
+	return &referral_link.LinkResponse{
+		ReferralCode: generalCode.ReferralCode,
+		Link:         s.links.DeepLink(generalCode.ReferralCode, referral_link.UTMParams{}),
+	}, nil
+}
+
+// GetGeneralCodeQR renders the deep link of a general code, tagged utm_medium=qr
+func (s *ReferralLinkService) GetGeneralCodeQR(ctx context.Context, id, format string, size int) (*referral_link.QRCode, error) {
+	if !s.links.Enabled() {
+		return nil, referral_link.ErrLinksDisabled
+	}
+
+	generalCode, err := s.referralCodeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, referral_link.ErrCodeNotFound
+	}
+	return renderQRCode(s.links.DeepLink(generalCode.ReferralCode, referral_link.UTMParams{Medium: "qr"}), format, size)
+}
+
+// renderQRCode encodes the link as SVG or, by default, PNG. Sizes outside the bounds are clamped.
+func renderQRCode(link, format string, size int) (*referral_link.QRCode, error) {
+	if size == 0 {
+		size = referral_link.DefaultQRSize
+	}
+	size = min(max(size, referral_link.MinQRSize), referral_link.MaxQRSize)
+
+	if format == referral_link.QRFormatSVG {
+		svg, err := utils.QRCodeSVG(link, size)
+		if err != nil {
+			return nil, err
+		}
+		return &referral_link.QRCode{ContentType: "image/svg+xml", Image: svg}, nil
+	}
+
+	png, err := utils.QRCodePNG(link, size)
+	if err != nil {
+		return nil, err
+	}
+	return &referral_link.QRCode{ContentType: "image/png", Image: png}, nil
+}
+
+// ClaimClick records that the user followed the link of the click, which the app received
+// on the landing page. Clicks older than ClaimWindow or already claimed are left alone and
+// reported as false.
+func (s *ReferralLinkService) ClaimClick(ctx context.Context, userID, clickID uuid.UUID) (bool, error) {
+	claimed, err := s.linkRepo.ClaimClick(ctx, clickID, userID, s.now().Add(-referral_link.ClaimWindow))
+	if err != nil {
+		s.logger.Error("Failed to claim referral link click",
+			logger.Field{Key: "module", Value: "referral_link_service"},
+			logger.Field{Key: "user_id", Value: userID.String()},
+			logger.Field{Key: "click_id", Value: clickID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return false, err
+	}
+	return claimed, nil
+}
+
+// GetFunnel returns click, signup and activation counts per code with the conversion
+// rates between them
+func (s *ReferralLinkService) GetFunnel(ctx context.Context, filter referral_link.FunnelFilter) (*referral_link.FunnelResponse, error) {
+	rows, totals, err := s.linkRepo.Funnel(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to load referral funnel",
+			logger.Field{Key: "module", Value: "referral_link_service"},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	entries := make([]referral_link.FunnelEntryResponse, 0, len(rows))
+	for _, row := range rows {
+		entry := referral_link.FunnelEntryResponse{
+			CodeType:       row.CodeType,
+			ReferralCodeID: row.ReferralCodeID,
+			Clicks:         row.Clicks,
+			Signups:        row.Signups,
+			Activations:    row.Activations,
+			SignupRate:     referral_link.Rate(row.Signups, row.Clicks),
+			ActivationRate: referral_link.Rate(row.Activations, row.Clicks),
+		}
+		if row.ReferralCode != nil {
+			entry.ReferralCode = *row.ReferralCode
+		}
+		entries = append(entries, entry)
+	}
+
+	return &referral_link.FunnelResponse{
+		Entries: entries,
+		Totals:  totals.FunnelCounts,
+		Total:   totals.Codes,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}, nil
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_code"
+	"astroneko-backend/internal/core/domain/referral_link"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLinkPolicy = referral_link.LinkPolicy{BaseURL: "https://api.astroneko.com", LandingURL: "https://astroneko.com/join"}
+
+type referralLinkTestMocks struct {
+	linkRepo         *mock_ports.MockReferralLinkRepositoryInterface
+	referralCodeRepo *mock_ports.ReferralCodeRepositoryInterface
+	logger           *mock_logger.MockLoggerInterface
+}
+
+func newTestReferralLinkService(ctrl *gomock.Controller, links referral_link.LinkPolicy) (*ReferralLinkService, *referralLinkTestMocks) {
+	mocks := &referralLinkTestMocks{
+		linkRepo:         mock_ports.NewMockReferralLinkRepositoryInterface(ctrl),
+		referralCodeRepo: mock_ports.NewReferralCodeRepositoryInterface(ctrl),
+		logger:           mock_logger.NewMockLoggerInterface(ctrl),
+	}
+	return NewReferralLinkService(mocks.linkRepo, mocks.referralCodeRepo, links, mocks.logger), mocks
+}
+
+func TestReferralLinkService_FollowLink_RecordsUserCodeClick(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	ctx := context.Background()
+	userCode := &referral_code.UserReferralCode{ReferralCode: "NEKO2026"}
+	userCode.ID = uuid.New()
+	clickID := uuid.New()
+
+	mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "NEKO2026").Return(false, nil)
+	mocks.referralCodeRepo.EXPECT().GetUserReferralCodeByCode(ctx, "NEKO2026").Return(userCode, nil)
+	mocks.linkRepo.EXPECT().CreateClick(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, click *referral_link.Click) error {
+		assert.Equal(t, referral_link.CodeTypeUser, click.CodeType)
+		assert.Equal(t, userCode.ID, click.ReferralCodeID)
+		require.NotNil(t, click.UTMSource)
+		assert.Equal(t, "tiktok", *click.UTMSource)
+		require.NotNil(t, click.Referrer)
+		assert.Equal(t, "https://www.tiktok.com/", *click.Referrer)
+		click.ID = clickID
+		return nil
+	})
+
+	// Act
+	landing, err := service.FollowLink(ctx, " neko2026", referral_link.UTMParams{Source: "tiktok"}, "https://www.tiktok.com/", "Mozilla/5.0")
+
+	// Assert
+	require.NoError(t, err)
+	parsed, err := url.Parse(landing)
+	require.NoError(t, err)
+	assert.Equal(t, "NEKO2026", parsed.Query().Get("ref"))
+	assert.Equal(t, clickID.String(), parsed.Query().Get("click_id"))
+	assert.Equal(t, "tiktok", parsed.Query().Get("utm_source"))
+}
+
+func TestReferralLinkService_LandingLink_DoesNotRecordClick(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestReferralLinkService(ctrl, testLinkPolicy)
+
+	// Act
+	landing, err := service.LandingLink(" neko2026", referral_link.UTMParams{Source: "tiktok"})
+
+	// Assert
+	require.NoError(t, err)
+	parsed, err := url.Parse(landing)
+	require.NoError(t, err)
+	assert.Equal(t, "NEKO2026", parsed.Query().Get("ref"))
+	assert.Empty(t, parsed.Query().Get("click_id"))
+	assert.Equal(t, "tiktok", parsed.Query().Get("utm_source"))
+}
+
+func TestReferralLinkService_FollowLink_GeneralCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	ctx := context.Background()
+	generalCode := &referral_code.ReferralCode{ReferralCode: "LAUNCH"}
+	generalCode.ID = uuid.New()
+
+	mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "LAUNCH").Return(true, nil)
+	mocks.referralCodeRepo.EXPECT().GetByReferralCode(ctx, "LAUNCH").Return(generalCode, nil)
+	mocks.linkRepo.EXPECT().CreateClick(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, click *referral_link.Click) error {
+		assert.Equal(t, referral_link.CodeTypeGeneral, click.CodeType)
+		assert.Equal(t, generalCode.ID, click.ReferralCodeID)
+		click.ID = uuid.New()
+		return nil
+	})
+
+	// Act
+	landing, err := service.FollowLink(ctx, "LAUNCH", referral_link.UTMParams{}, "", "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, landing, "click_id=")
+}
+
+func TestReferralLinkService_FollowLink_UnknownCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	ctx := context.Background()
+
+	mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "NOPE").Return(false, nil)
+	mocks.referralCodeRepo.EXPECT().GetUserReferralCodeByCode(ctx, "NOPE").Return(nil, errors.New("record not found"))
+
+	// Act
+	landing, err := service.FollowLink(ctx, "NOPE", referral_link.UTMParams{}, "", "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "https://astroneko.com/join?ref=NOPE", landing)
+}
+
+func TestReferralLinkService_FollowLink_ClickNotSaved(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	ctx := context.Background()
+
+	mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "NEKO2026").Return(false, nil)
+	mocks.referralCodeRepo.EXPECT().GetUserReferralCodeByCode(ctx, "NEKO2026").Return(&referral_code.UserReferralCode{UserID: uuid.New()}, nil)
+	mocks.linkRepo.EXPECT().CreateClick(ctx, gomock.Any()).Return(errors.New("connection reset"))
+	mocks.logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	landing, err := service.FollowLink(ctx, "NEKO2026", referral_link.UTMParams{}, "", "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "https://astroneko.com/join?ref=NEKO2026", landing)
+}
+
+func TestReferralLinkService_FollowLink_LookupFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrange func(mocks *referralLinkTestMocks, ctx context.Context)
+	}{
+		{
+			name: "general code check",
+			arrange: func(mocks *referralLinkTestMocks, ctx context.Context) {
+				mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "NEKO2026").Return(false, errors.New("connection reset"))
+			},
+		},
+		{
+			name: "general code load",
+			arrange: func(mocks *referralLinkTestMocks, ctx context.Context) {
+				mocks.referralCodeRepo.EXPECT().IsValidReferralCode(ctx, "NEKO2026").Return(true, nil)
+				mocks.referralCodeRepo.EXPECT().GetByReferralCode(ctx, "NEKO2026").Return(nil, errors.New("connection reset"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+			ctx := context.Background()
+			tt.arrange(mocks, ctx)
+			mocks.logger.EXPECT().Error(gomock.Any(), gomock.Any())
+
+			// Act
+			landing, err := service.FollowLink(ctx, "NEKO2026", referral_link.UTMParams{Source: "line"}, "", "")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "https://astroneko.com/join?ref=NEKO2026&utm_source=line", landing)
+		})
+	}
+}
+
+func TestReferralLinkService_LinksDisabled(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestReferralLinkService(ctrl, referral_link.LinkPolicy{})
+	ctx := context.Background()
+
+	// Act
+	_, followErr := service.FollowLink(ctx, "NEKO2026", referral_link.UTMParams{}, "", "")
+	_, qrErr := service.GetUserCodeQR(ctx, uuid.New(), "NEKO2026", referral_link.QRFormatPNG, 0)
+	_, linkErr := service.GetGeneralCodeLink(ctx, uuid.New().String())
+
+	// Assert
+	assert.ErrorIs(t, followErr, referral_link.ErrLinksDisabled)
+	assert.ErrorIs(t, qrErr, referral_link.ErrLinksDisabled)
+	assert.ErrorIs(t, linkErr, referral_link.ErrLinksDisabled)
+	assert.Empty(t, service.DeepLink("NEKO2026", referral_link.UTMParams{}))
+}
+
+func TestReferralLinkService_GetUserCodeQR(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		format      string
+		contentType string
+		wantErr     error
+	}{
+		{name: "png", userID: ownerID, format: referral_link.QRFormatPNG, contentType: "image/png"},
+		{name: "svg", userID: ownerID, format: referral_link.QRFormatSVG, contentType: "image/svg+xml"},
+		{name: "someone else's code", userID: uuid.New(), format: referral_link.QRFormatPNG, wantErr: referral_link.ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+			ctx := context.Background()
+
+			mocks.referralCodeRepo.EXPECT().GetUserReferralCodeByCode(ctx, "NEKO2026").
+				Return(&referral_code.UserReferralCode{UserID: ownerID, ReferralCode: "NEKO2026"}, nil)
+
+			// Act
+			qrCode, err := service.GetUserCodeQR(ctx, tt.userID, "NEKO2026", tt.format, 64)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, qrCode.ContentType)
+			assert.NotEmpty(t, qrCode.Image)
+			if tt.format == referral_link.QRFormatSVG {
+				assert.Contains(t, string(qrCode.Image), `width="128"`)
+			}
+		})
+	}
+}
+
+func TestReferralLinkService_ClaimClick(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+	userID, clickID := uuid.New(), uuid.New()
+
+	mocks.linkRepo.EXPECT().ClaimClick(ctx, clickID, userID, now.Add(-referral_link.ClaimWindow)).Return(true, nil)
+
+	// Act
+	claimed, err := service.ClaimClick(ctx, userID, clickID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestReferralLinkService_GetFunnel(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestReferralLinkService(ctrl, testLinkPolicy)
+	ctx := context.Background()
+	filter := referral_link.FunnelFilter{CodeType: referral_link.CodeTypeUser, Limit: 20}
+	code := "NEKO2026"
+	codeID, deletedID := uuid.New(), uuid.New()
+
+	mocks.linkRepo.EXPECT().Funnel(ctx, filter).Return([]referral_link.FunnelRow{
+		{CodeType: referral_link.CodeTypeUser, ReferralCodeID: codeID, ReferralCode: &code, FunnelCounts: referral_link.FunnelCounts{Clicks: 8, Signups: 4, Activations: 2}},
+		{CodeType: referral_link.CodeTypeUser, ReferralCodeID: deletedID, FunnelCounts: referral_link.FunnelCounts{Clicks: 2}},
+	}, &referral_link.FunnelTotals{Codes: 2, FunnelCounts: referral_link.FunnelCounts{Clicks: 10, Signups: 4, Activations: 2}}, nil)
+
+	// Act
+	funnel, err := service.GetFunnel(ctx, filter)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, funnel.Entries, 2)
+	assert.Equal(t, "NEKO2026", funnel.Entries[0].ReferralCode)
+	assert.Equal(t, 0.5, funnel.Entries[0].SignupRate)
+	assert.Equal(t, 0.25, funnel.Entries[0].ActivationRate)
+	assert.Empty(t, funnel.Entries[1].ReferralCode)
+	assert.Equal(t, int64(2), funnel.Total)
+	assert.Equal(t, int64(10), funnel.Totals.Clicks)
+	assert.Equal(t, 20, funnel.Limit)
+}
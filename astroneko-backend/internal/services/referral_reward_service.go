@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+	referralRewardPorts "astroneko-backend/internal/core/ports/referral_reward"
+	"astroneko-backend/pkg/logger"
+)
+
+// ReferralRewardService shows users what inviting others has earned them. Rewards are
+// credited by ReferralCodeService when a code is redeemed.
+type ReferralRewardService struct {
+	rewardRepo referralRewardPorts.RepositoryInterface
+	policy     referral_reward.Policy
+	logger     logger.Logger
+}
+
+// NewReferralRewardService creates a new referral reward service instance
+func NewReferralRewardService(rewardRepo referralRewardPorts.RepositoryInterface, policy referral_reward.Policy, log logger.Logger) *ReferralRewardService {
+	return &ReferralRewardService{
+		rewardRepo: rewardRepo,
+		policy:     policy,
+		logger:     log,
+	}
+}
+
+// GetMyRewards returns the user's reward ledger with their badges, the bonus and daily
+// requests they have from it, and what the next invite earns. Users with unlimited access
+// earn only badges, so the next invite earns them no replies.
+func (s *ReferralRewardService) GetMyRewards(ctx context.Context, u *user.User) (*referral_reward.MyRewardsResponse, error) {
+	rewards, err := s.rewardRepo.ListByUserID(ctx, u.ID)
+	if err != nil {
+		s.logger.Error("Failed to list referral rewards",
+			logger.Field{Key: "module", Value: "referral_reward_service"},
+			logger.Field{Key: "user_id", Value: u.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to get rewards: %w", err)
+	}
+
+	invites, err := s.rewardRepo.CountInvites(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rewards: %w", err)
+	}
+
+	history := make([]referral_reward.RewardResponse, 0, len(rewards))
+	badges := []string{}
+	for _, reward := range rewards {
+		history = append(history, reward.ToResponse())
+		if reward.Type == referral_reward.TypeBadge && reward.Badge != nil {
+			badges = append(badges, *reward.Badge)
+		}
+	}
+
+	policy := s.policy
+	if u.HasUnlimitedAccess() {
+		policy = policy.BadgesOnly()
+	}
+
+	response := &referral_reward.MyRewardsResponse{
+		InviteCount:           invites,
+		UnlimitedReplies:      u.HasUnlimitedAccess(),
+		BonusRepliesRemaining: u.BonusQuota,
+		ExtraDailyReplies:     u.RewardDailyReplies,
+		Badges:                badges,
+		PerInvite: referral_reward.PerInviteResponse{
+			BonusReplies: policy.BonusReplies,
+			DailyReplies: min(policy.DailyReplies, max(policy.MaxDailyReplies-u.RewardDailyReplies, 0)),
+		},
+		History: history,
+	}
+	if next := policy.NextBadge(invites); next != nil {
+		response.NextBadge = &referral_reward.NextBadgeResponse{
+			Badge:       next.Badge,
+			Invites:     next.Invites,
+			InvitesToGo: int64(next.Invites) - invites,
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/referral_reward"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferralRewardService_GetMyRewards(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rewardRepo := mock_ports.NewMockReferralRewardRepositoryInterface(ctrl)
+	service := NewReferralRewardService(rewardRepo, referral_reward.DefaultPolicy, mock_logger.NewMockLoggerInterface(ctrl))
+	ctx := context.Background()
+
+	u := &user.User{BonusQuota: 7, RewardDailyReplies: 9}
+	u.ID = uuid.New()
+	badge := "first_invite"
+	createdAt := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	rewardRepo.EXPECT().ListByUserID(ctx, u.ID).Return([]*referral_reward.Reward{
+		{Type: referral_reward.TypeBadge, Badge: &badge, CreatedAt: createdAt},
+		{Type: referral_reward.TypeBonusReplies, Amount: 5, CreatedAt: createdAt},
+	}, nil)
+	rewardRepo.EXPECT().CountInvites(ctx, u.ID).Return(int64(3), nil)
+
+	// Act
+	rewards, err := service.GetMyRewards(ctx, u)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rewards.InviteCount)
+	assert.Equal(t, 7, rewards.BonusRepliesRemaining)
+	assert.Equal(t, 9, rewards.ExtraDailyReplies)
+	assert.Equal(t, []string{"first_invite"}, rewards.Badges)
+	assert.Len(t, rewards.History, 2)
+	assert.Equal(t, referral_reward.PerInviteResponse{BonusReplies: 5, DailyReplies: 1}, rewards.PerInvite)
+	require.NotNil(t, rewards.NextBadge)
+	assert.Equal(t, "connector", rewards.NextBadge.Badge)
+	assert.Equal(t, int64(2), rewards.NextBadge.InvitesToGo)
+}
+
+func TestReferralRewardService_GetMyRewards_UnlimitedUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rewardRepo := mock_ports.NewMockReferralRewardRepositoryInterface(ctrl)
+	service := NewReferralRewardService(rewardRepo, referral_reward.DefaultPolicy, mock_logger.NewMockLoggerInterface(ctrl))
+	ctx := context.Background()
+
+	u := &user.User{IsActivatedReferral: true}
+	u.ID = uuid.New()
+
+	rewardRepo.EXPECT().ListByUserID(ctx, u.ID).Return(nil, nil)
+	rewardRepo.EXPECT().CountInvites(ctx, u.ID).Return(int64(0), nil)
+
+	// Act
+	rewards, err := service.GetMyRewards(ctx, u)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, rewards.UnlimitedReplies)
+	assert.Equal(t, referral_reward.PerInviteResponse{}, rewards.PerInvite)
+	require.NotNil(t, rewards.NextBadge)
+	assert.Equal(t, "first_invite", rewards.NextBadge.Badge)
+}
+
+func TestReferralRewardService_GetMyRewards_DailyRepliesCapped(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rewardRepo := mock_ports.NewMockReferralRewardRepositoryInterface(ctrl)
+	service := NewReferralRewardService(rewardRepo, referral_reward.DefaultPolicy, mock_logger.NewMockLoggerInterface(ctrl))
+	ctx := context.Background()
+
+	u := &user.User{RewardDailyReplies: referral_reward.DefaultPolicy.MaxDailyReplies}
+	u.ID = uuid.New()
+
+	rewardRepo.EXPECT().ListByUserID(ctx, u.ID).Return(nil, nil)
+	rewardRepo.EXPECT().CountInvites(ctx, u.ID).Return(int64(25), nil)
+
+	// Act
+	rewards, err := service.GetMyRewards(ctx, u)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, rewards.PerInvite.DailyReplies)
+	assert.Nil(t, rewards.NextBadge)
+	assert.NotNil(t, rewards.Badges)
+}
+
+func TestReferralRewardService_GetMyRewards_RepositoryError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rewardRepo := mock_ports.NewMockReferralRewardRepositoryInterface(ctrl)
+	log := mock_logger.NewMockLoggerInterface(ctrl)
+	service := NewReferralRewardService(rewardRepo, referral_reward.DefaultPolicy, log)
+	ctx := context.Background()
+	u := &user.User{}
+
+	rewardRepo.EXPECT().ListByUserID(ctx, u.ID).Return(nil, errors.New("connection reset"))
+	log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	rewards, err := service.GetMyRewards(ctx, u)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, rewards)
+}
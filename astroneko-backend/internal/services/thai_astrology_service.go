@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/thai_astrology"
+	"astroneko-backend/internal/core/domain/user"
+)
+
+const (
+	// DefaultAuspiciousDays is the number of days scored when no range is requested
+	DefaultAuspiciousDays = 30
+
+	// MaxAuspiciousDays caps the number of days scored per request
+	MaxAuspiciousDays = 90
+)
+
+// ThaiAstrologyService serves the Thai lunar calendar, day colours, holy days and
+// auspicious-day scores. Everything is computed locally from calendar tables.
+type ThaiAstrologyService struct {
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewThaiAstrologyService creates a new Thai astrology service instance
+func NewThaiAstrologyService() *ThaiAstrologyService {
+	location, err := time.LoadLocation(thai_astrology.DefaultTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &ThaiAstrologyService{
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// Today returns the current calendar date in Thailand
+func (s *ThaiAstrologyService) Today() time.Time {
+	now := s.now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetLunarDate converts a Gregorian date to the Thai lunar calendar
+func (s *ThaiAstrologyService) GetLunarDate(date time.Time) (*thai_astrology.LunarDate, error) {
+	return thai_astrology.ToLunarDate(date)
+}
+
+// GetDayColors returns the auspicious colour table of every weekday
+func (s *ThaiAstrologyService) GetDayColors() []thai_astrology.DayColorTable {
+	return thai_astrology.DayColorTables()
+}
+
+// ListWanPhra lists the Buddhist holy days of a Gregorian month
+func (s *ThaiAstrologyService) ListWanPhra(year int, month time.Month) ([]thai_astrology.WanPhraDay, error) {
+	return thai_astrology.WanPhraDays(year, month)
+}
+
+// GetAuspiciousDays scores each day from the start date for the user's birth day
+func (s *ThaiAstrologyService) GetAuspiciousDays(u *user.User, from time.Time, days int) (*thai_astrology.AuspiciousDaysResponse, error) {
+	if u == nil || !u.HasBirthProfile() {
+		return nil, shared.ErrBirthProfileNotSet
+	}
+	if days < 1 || days > MaxAuspiciousDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", shared.ErrInvalidRequest, MaxAuspiciousDays)
+	}
+
+	birthDay := thai_astrology.BirthDay(*u.BirthDate, u.BirthTime)
+	response := &thai_astrology.AuspiciousDaysResponse{
+		BirthDay: birthDay,
+		Colors:   thai_astrology.ThaksaOf(birthDay),
+		From:     from.Format(thai_astrology.DateLayout),
+		To:       from.AddDate(0, 0, days-1).Format(thai_astrology.DateLayout),
+		Days:     make([]thai_astrology.AuspiciousDay, 0, days),
+	}
+
+	for offset := 0; offset < days; offset++ {
+		day, err := thai_astrology.ScoreDay(birthDay, from.AddDate(0, 0, offset))
+		if err != nil {
+			return nil, err
+		}
+		response.Days = append(response.Days, *day)
+	}
+
+	return response, nil
+}
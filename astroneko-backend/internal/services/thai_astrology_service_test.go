@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/shared"
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThaiAstrologyService_GetAuspiciousDays_Success(t *testing.T) {
+	// Arrange
+	service := NewThaiAstrologyService()
+	from := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	response, err := service.GetAuspiciousDays(buildTestBirthProfileUser(), from, 7)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "wednesday", response.BirthDay.Key)
+	assert.Equal(t, "2025-05-10", response.From)
+	assert.Equal(t, "2025-05-16", response.To)
+	assert.Len(t, response.Days, 7)
+	assert.Len(t, response.Colors, 8)
+	assert.Equal(t, "tuesday", response.Colors[7].Day)
+
+	// Tuesday is กาลกิณี for someone born on Wednesday
+	assert.Equal(t, "2025-05-13", response.Days[3].Date)
+	assert.Equal(t, "kalakini", response.Days[3].Position.Key)
+}
+
+func TestThaiAstrologyService_GetAuspiciousDays_WednesdayNight(t *testing.T) {
+	// Arrange
+	service := NewThaiAstrologyService()
+	u := buildTestBirthProfileUser()
+	evening := "20:15"
+	u.BirthTime = &evening
+
+	// Act
+	response, err := service.GetAuspiciousDays(u, service.Today(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "wednesday_night", response.BirthDay.Key)
+}
+
+func TestThaiAstrologyService_GetAuspiciousDays_NoBirthProfile(t *testing.T) {
+	// Act
+	response, err := NewThaiAstrologyService().GetAuspiciousDays(&user.User{}, time.Now(), 7)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, shared.ErrBirthProfileNotSet, err)
+}
+
+func TestThaiAstrologyService_GetAuspiciousDays_InvalidRange(t *testing.T) {
+	service := NewThaiAstrologyService()
+
+	_, err := service.GetAuspiciousDays(buildTestBirthProfileUser(), time.Now(), 0)
+	assert.True(t, errors.Is(err, shared.ErrInvalidRequest))
+
+	_, err = service.GetAuspiciousDays(buildTestBirthProfileUser(), time.Now(), MaxAuspiciousDays+1)
+	assert.True(t, errors.Is(err, shared.ErrInvalidRequest))
+
+	_, err = service.GetAuspiciousDays(buildTestBirthProfileUser(), time.Date(2199, 12, 30, 0, 0, 0, 0, time.UTC), 5)
+	assert.Equal(t, shared.ErrLunarDateOutOfRange, err)
+}
+
+func TestThaiAstrologyService_Today(t *testing.T) {
+	// Arrange
+	service := NewThaiAstrologyService()
+	service.now = func() time.Time { return time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC) }
+
+	// Act: 01:30 on March 11 in Bangkok
+	today := service.Today()
+
+	// Assert
+	assert.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), today)
+}
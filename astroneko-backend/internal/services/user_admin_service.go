@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/audit"
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	"astroneko-backend/internal/core/domain/user"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
+	userPorts "astroneko-backend/internal/core/ports/user"
+	"astroneko-backend/pkg/logger"
+)
+
+// UserAdminService manages app users on behalf of CRM staff. Every change is
+// recorded in the audit log with the staff member who made it.
+type UserAdminService struct {
+	userRepo               userPorts.RepositoryInterface
+	userService            *UserService
+	accountDeletionService *AccountDeletionService
+	attempts               credentialAttemptPorts.ServiceInterface
+	auditService           *AuditService
+	logger                 logger.Logger
+}
+
+// NewUserAdminService creates a new user administration service instance
+func NewUserAdminService(userRepo userPorts.RepositoryInterface, userService *UserService, accountDeletionService *AccountDeletionService, attempts credentialAttemptPorts.ServiceInterface, auditService *AuditService, log logger.Logger) *UserAdminService {
+	return &UserAdminService{
+		userRepo:               userRepo,
+		userService:            userService,
+		accountDeletionService: accountDeletionService,
+		attempts:               attempts,
+		auditService:           auditService,
+		logger:                 log,
+	}
+}
+
+// ListUsers returns a page of app users
+func (s *UserAdminService) ListUsers(ctx context.Context, limit, offset int) ([]*user.User, int64, error) {
+	return s.userRepo.List(ctx, limit, offset)
+}
+
+// GetUser returns any app user
+func (s *UserAdminService) GetUser(ctx context.Context, id string) (*user.User, error) {
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// CreateUser creates an app user with a Firebase email and password account
+func (s *UserAdminService) CreateUser(ctx context.Context, actor audit.Actor, req *user.CreateUserRequest) (*user.User, error) {
+	created, err := s.userService.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, actor, audit.ActionUserCreated, audit.TargetUser, created.ID.String(), nil, created)
+	return created, nil
+}
+
+// UpdateUser changes any field of UpdateUserRequest, including the referral activation
+func (s *UserAdminService) UpdateUser(ctx context.Context, actor audit.Actor, id string, req *user.UpdateUserRequest) (*user.User, error) {
+	existing, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *existing
+
+	updated, err := s.userService.UpdateUser(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, actor, audit.ActionUserUpdated, audit.TargetUser, id, &before, updated)
+	return updated, nil
+}
+
+// DeleteUser schedules the user's account for the next purge run, which removes all
+// owned data and the Firebase account the same way a self-service deletion does
+func (s *UserAdminService) DeleteUser(ctx context.Context, actor audit.Actor, id string) (*account_deletion.DeletionStatusResponse, error) {
+	existing, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion, err := s.accountDeletionService.ScheduleImmediateDeletion(ctx, existing, "requested by CRM user "+actor.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, actor, audit.ActionUserDeletionSchedule, audit.TargetUser, id, existing, nil)
+	return deletion, nil
+}
+
+// UnlockLogin clears the user's failed login count, ending a backoff or lockout early
+func (s *UserAdminService) UnlockLogin(ctx context.Context, actor audit.Actor, id string) (*credential_attempt.UnlockResponse, error) {
+	existing, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := s.attempts.Unlock(ctx, credential_attempt.ScopeUserLogin, existing.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &credential_attempt.UnlockResponse{Unlocked: unlocked}
+	s.auditService.Record(ctx, actor, audit.ActionUserLoginUnlocked, audit.TargetUser, id, nil, result)
+	return result, nil
+}
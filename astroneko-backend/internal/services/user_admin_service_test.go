@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"astroneko-backend/internal/core/domain/account_deletion"
+	"astroneko-backend/internal/core/domain/audit"
+	"astroneko-backend/internal/core/domain/credential_attempt"
+	"astroneko-backend/internal/core/domain/user"
+	"astroneko-backend/testings/mock_firebase"
+	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_ports"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type userAdminTestMocks struct {
+	userRepo     *mock_ports.MockUserRepositoryInterface
+	deletionRepo *mock_ports.MockAccountDeletionRepositoryInterface
+	auditRepo    *mock_ports.MockAuditRepositoryInterface
+	attempts     *mock_ports.MockCredentialAttemptServiceInterface
+	logger       *mock_logger.MockLoggerInterface
+}
+
+func newTestUserAdminService(ctrl *gomock.Controller) (*UserAdminService, *userAdminTestMocks) {
+	mocks := &userAdminTestMocks{
+		userRepo:     mock_ports.NewMockUserRepositoryInterface(ctrl),
+		deletionRepo: mock_ports.NewMockAccountDeletionRepositoryInterface(ctrl),
+		auditRepo:    mock_ports.NewMockAuditRepositoryInterface(ctrl),
+		attempts:     mock_ports.NewMockCredentialAttemptServiceInterface(ctrl),
+		logger:       mock_logger.NewMockLoggerInterface(ctrl),
+	}
+
+	userService := NewUserService(mocks.userRepo, mocks.attempts, nil, "test-api-key", mocks.logger, mock_ports.NewReferralCodeRepositoryInterface(ctrl))
+	accountDeletionService := NewAccountDeletionService(mocks.deletionRepo, mock_firebase.NewMockFirebaseClientInterface(ctrl), mocks.logger)
+	accountDeletionService.now = func() time.Time { return accountDeletionTestNow }
+
+	service := NewUserAdminService(mocks.userRepo, userService, accountDeletionService, mocks.attempts, NewAuditService(mocks.auditRepo, mocks.logger), mocks.logger)
+	return service, mocks
+}
+
+func buildTestAuditActor() audit.Actor {
+	return audit.Actor{Type: audit.ActorCRMUser, ID: "crm-user-id", Name: "support", IPAddress: "10.0.0.1", RequestID: "request-id"}
+}
+
+func TestUserAdminService_UpdateUser_RecordsAuditEntry(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+	existing := buildTestBirthProfileUser()
+	activated := true
+
+	mocks.userRepo.EXPECT().GetByID(ctx, existing.ID.String()).Return(existing, nil).Times(2)
+	mocks.userRepo.EXPECT().
+		Update(ctx, gomock.Any(), []string{user.ColumnIsActivatedReferral}).
+		DoAndReturn(func(_ context.Context, u *user.User, _ []string) (*user.User, error) { return u, nil })
+
+	var recorded *audit.Entry
+	mocks.auditRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *audit.Entry) error {
+			recorded = entry
+			return nil
+		})
+
+	// Act
+	updated, err := service.UpdateUser(ctx, buildTestAuditActor(), existing.ID.String(), &user.UpdateUserRequest{IsActivatedReferral: &activated})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, updated.IsActivatedReferral)
+	assert.Equal(t, audit.ActionUserUpdated, recorded.Action)
+	assert.Equal(t, existing.ID.String(), recorded.TargetID)
+	assert.Equal(t, "crm-user-id", recorded.ActorID)
+	assert.Equal(t, "request-id", recorded.RequestID)
+
+	var changes map[string]audit.Change
+	assert.NoError(t, json.Unmarshal([]byte(recorded.Changes), &changes))
+	assert.Equal(t, map[string]audit.Change{"is_activated_referral": {Before: false, After: true}}, changes)
+}
+
+func TestUserAdminService_UpdateUser_RedactsPersonalData(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+	existing := buildTestBirthProfileUser()
+	displayName := "Mali"
+
+	mocks.userRepo.EXPECT().GetByID(ctx, existing.ID.String()).Return(existing, nil).Times(2)
+	mocks.userRepo.EXPECT().
+		Update(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *user.User, _ []string) (*user.User, error) { return u, nil })
+
+	var recorded *audit.Entry
+	mocks.auditRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *audit.Entry) error {
+			recorded = entry
+			return nil
+		})
+
+	// Act
+	_, err := service.UpdateUser(ctx, buildTestAuditActor(), existing.ID.String(), &user.UpdateUserRequest{DisplayName: &displayName})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotContains(t, recorded.Changes, "Mali")
+	assert.NotContains(t, recorded.Changes, existing.Email)
+
+	var changes map[string]audit.Change
+	assert.NoError(t, json.Unmarshal([]byte(recorded.Changes), &changes))
+	assert.Equal(t, audit.RedactedValue, changes["display_name"].After)
+}
+
+func TestUserAdminService_UpdateUser_AuditFailureDoesNotFailUpdate(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+	existing := buildTestBirthProfileUser()
+	displayName := "Mali"
+
+	mocks.userRepo.EXPECT().GetByID(ctx, existing.ID.String()).Return(existing, nil).Times(2)
+	mocks.userRepo.EXPECT().
+		Update(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *user.User, _ []string) (*user.User, error) { return u, nil })
+	mocks.auditRepo.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("database unavailable"))
+	mocks.logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// Act
+	updated, err := service.UpdateUser(ctx, buildTestAuditActor(), existing.ID.String(), &user.UpdateUserRequest{DisplayName: &displayName})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mali", *updated.DisplayName)
+}
+
+func TestUserAdminService_DeleteUser_SchedulesImmediatePurge(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+	existing := buildTestBirthProfileUser()
+
+	mocks.userRepo.EXPECT().GetByID(ctx, existing.ID.String()).Return(existing, nil)
+	mocks.deletionRepo.EXPECT().GetActiveByUserID(ctx, existing.ID).Return(nil, nil)
+
+	var scheduled *account_deletion.DeletionRequest
+	mocks.deletionRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *account_deletion.DeletionRequest) error {
+			scheduled = request
+			return nil
+		})
+	mocks.deletionRepo.EXPECT().AddAuditEvent(ctx, gomock.Any()).Return(nil)
+
+	var recorded *audit.Entry
+	mocks.auditRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *audit.Entry) error {
+			recorded = entry
+			return nil
+		})
+
+	// Act
+	response, err := service.DeleteUser(ctx, buildTestAuditActor(), existing.ID.String())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, account_deletion.StatusScheduled, response.Status)
+	assert.Equal(t, accountDeletionTestNow, *scheduled.ScheduledFor)
+	assert.Nil(t, scheduled.ConfirmationTokenHash)
+	assert.Equal(t, audit.ActionUserDeletionSchedule, recorded.Action)
+}
+
+func TestUserAdminService_DeleteUser_UserNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+
+	mocks.userRepo.EXPECT().GetByID(ctx, "missing").Return(nil, errors.New("record not found"))
+
+	// Act
+	response, err := service.DeleteUser(ctx, buildTestAuditActor(), "missing")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, response)
+}
+
+func TestUserAdminService_UnlockLogin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mocks := newTestUserAdminService(ctrl)
+	ctx := context.Background()
+	existing := buildTestBirthProfileUser()
+
+	mocks.userRepo.EXPECT().GetByID(ctx, existing.ID.String()).Return(existing, nil)
+	mocks.attempts.EXPECT().Unlock(ctx, credential_attempt.ScopeUserLogin, existing.Email).Return(true, nil)
+
+	var recorded *audit.Entry
+	mocks.auditRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *audit.Entry) error {
+			recorded = entry
+			return nil
+		})
+
+	// Act
+	result, err := service.UnlockLogin(ctx, buildTestAuditActor(), existing.ID.String())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.Unlocked)
+	assert.Equal(t, audit.ActionUserLoginUnlocked, recorded.Action)
+	assert.Equal(t, existing.ID.String(), recorded.TargetID)
+}
@@ -6,8 +6,10 @@ import (
 	"strconv"
 	"time"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/user"
+	credentialAttemptPorts "astroneko-backend/internal/core/ports/credential_attempt"
 	referralCodePorts "astroneko-backend/internal/core/ports/referral_code"
 	userPorts "astroneko-backend/internal/core/ports/user"
 	"astroneko-backend/pkg/firebase"
@@ -18,15 +20,17 @@ import (
 
 type UserService struct {
 	userRepo         userPorts.RepositoryInterface
+	attempts         credentialAttemptPorts.ServiceInterface
 	firebaseApp      firebase.FirebaseClientInterface
 	firebaseAPIKey   string
 	logger           logger.Logger
 	referralCodeRepo referralCodePorts.RepositoryInterface
 }
 
-func NewUserService(userRepo userPorts.RepositoryInterface, firebaseApp firebase.FirebaseClientInterface, firebaseAPIKey string, log logger.Logger, referralCodeRepo referralCodePorts.RepositoryInterface) *UserService {
+func NewUserService(userRepo userPorts.RepositoryInterface, attempts credentialAttemptPorts.ServiceInterface, firebaseApp firebase.FirebaseClientInterface, firebaseAPIKey string, log logger.Logger, referralCodeRepo referralCodePorts.RepositoryInterface) *UserService {
 	return &UserService{
 		userRepo:         userRepo,
+		attempts:         attempts,
 		firebaseApp:      firebaseApp,
 		firebaseAPIKey:   firebaseAPIKey,
 		logger:           log,
@@ -117,20 +121,45 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, req *user.Updat
 		return nil, err
 	}
 
+	var columns []string
 	if req.IsActivatedReferral != nil {
 		existingUser.IsActivatedReferral = *req.IsActivatedReferral
+		columns = append(columns, user.ColumnIsActivatedReferral)
 	}
 	if req.LatestLoginAt != nil {
 		existingUser.LatestLoginAt = req.LatestLoginAt
+		columns = append(columns, user.ColumnLatestLoginAt)
 	}
 	if req.ProfileImageURL != nil {
 		existingUser.ProfileImageURL = req.ProfileImageURL
+		columns = append(columns, user.ColumnProfileImageURL)
 	}
 	if req.DisplayName != nil {
 		existingUser.DisplayName = req.DisplayName
+		columns = append(columns, user.ColumnDisplayName)
 	}
 
-	return s.userRepo.Update(ctx, existingUser)
+	return s.userRepo.Update(ctx, existingUser, columns)
+}
+
+// UpdateProfile applies a self-service profile change; referral and login fields are left alone
+func (s *UserService) UpdateProfile(ctx context.Context, id string, req *user.UpdateProfileRequest) (*user.User, error) {
+	existingUser, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	if req.ProfileImageURL != nil {
+		existingUser.ProfileImageURL = req.ProfileImageURL
+		columns = append(columns, user.ColumnProfileImageURL)
+	}
+	if req.DisplayName != nil {
+		existingUser.DisplayName = req.DisplayName
+		columns = append(columns, user.ColumnDisplayName)
+	}
+
+	return s.userRepo.Update(ctx, existingUser, columns)
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
@@ -351,7 +380,10 @@ func (s *UserService) GoogleAuth(ctx context.Context, req *user.GoogleLoginReque
 }
 
 func (s *UserService) Login(ctx context.Context, req *user.LoginRequest) (*user.AuthResponse, error) {
-	// Processing login - no logging needed for routine operation
+	// Blocked after repeated failures for this email or IP
+	if err := s.attempts.Check(ctx, credential_attempt.ScopeUserLogin, req.Email, req.ClientIP); err != nil {
+		return nil, err
+	}
 
 	// Check if user exists in our database
 	dbUser, err := s.userRepo.GetByEmail(ctx, req.Email)
@@ -360,6 +392,7 @@ func (s *UserService) Login(ctx context.Context, req *user.LoginRequest) (*user.
 			logger.Field{Key: "module", Value: "user_service"},
 			logger.Field{Key: "email", Value: req.Email},
 			logger.Field{Key: "error", Value: err.Error()})
+		s.recordLoginFailure(ctx, req)
 		return nil, shared.ErrUserNotFound
 	}
 
@@ -370,6 +403,7 @@ func (s *UserService) Login(ctx context.Context, req *user.LoginRequest) (*user.
 			logger.Field{Key: "module", Value: "user_service"},
 			logger.Field{Key: "email", Value: req.Email},
 			logger.Field{Key: "error", Value: err.Error()})
+		s.recordLoginFailure(ctx, req)
 		return nil, shared.ErrFirebaseAuthFailed
 	}
 
@@ -384,6 +418,9 @@ func (s *UserService) Login(ctx context.Context, req *user.LoginRequest) (*user.
 	}
 
 	// Firebase auth successful - no logging needed
+	if err := s.attempts.RecordSuccess(ctx, credential_attempt.ScopeUserLogin, req.Email); err != nil {
+		return nil, err
+	}
 
 	// Update latest login time
 	now := time.Now()
@@ -413,6 +450,16 @@ func (s *UserService) Login(ctx context.Context, req *user.LoginRequest) (*user.
 	}, nil
 }
 
+// recordLoginFailure counts a failed login against the email and IP. The login
+// fails either way, so a tracker error is only logged.
+func (s *UserService) recordLoginFailure(ctx context.Context, req *user.LoginRequest) {
+	if err := s.attempts.RecordFailure(ctx, credential_attempt.ScopeUserLogin, req.Email, req.ClientIP); err != nil {
+		s.logger.Error("Failed to record failed login",
+			logger.Field{Key: "module", Value: "user_service"},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+}
+
 func (s *UserService) GetTotalUsers(ctx context.Context) (int64, error) {
 	totalUsers, err := s.userRepo.GetTotalUsers(ctx)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"astroneko-backend/internal/core/domain/credential_attempt"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/user"
 	"astroneko-backend/testings/mock_firebase"
@@ -102,7 +103,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildCreateUserRequest()
 	expectedUser := buildTestUser()
@@ -143,7 +144,7 @@ func TestUserService_CreateUser_UserAlreadyExists(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildCreateUserRequest()
 	existingUser := buildTestUser()
@@ -172,7 +173,7 @@ func TestUserService_CreateUser_FirebaseNotConfigured(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, nil, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), nil, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildCreateUserRequest()
 
@@ -201,7 +202,7 @@ func TestUserService_CreateUser_FirebaseUserCreationFails(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildCreateUserRequest()
 	firebaseError := errors.New("firebase user creation failed")
@@ -234,7 +235,7 @@ func TestUserService_CreateUser_DatabaseCreationFailsWithCleanup(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildCreateUserRequest()
 	dbError := errors.New("database constraint violation")
@@ -274,7 +275,7 @@ func TestUserService_GetUserByFirebaseUID_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	firebaseUID := "firebase_123"
 	expectedUser := buildTestUser()
@@ -302,7 +303,7 @@ func TestUserService_UpdateUser_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	userID, _ := uuid.Parse("test-user-id")
 	updateReq := buildUpdateUserRequest()
@@ -317,7 +318,9 @@ func TestUserService_UpdateUser_Success(t *testing.T) {
 
 	// Mock repository calls
 	mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
-	mockUserRepo.EXPECT().Update(ctx, gomock.Any()).Return(updatedUser, nil)
+	mockUserRepo.EXPECT().
+		Update(ctx, gomock.Any(), []string{user.ColumnIsActivatedReferral, user.ColumnLatestLoginAt, user.ColumnProfileImageURL, user.ColumnDisplayName}).
+		Return(updatedUser, nil)
 
 	// Act
 	result, err := service.UpdateUser(ctx, userID.String(), updateReq)
@@ -340,7 +343,7 @@ func TestUserService_UpdateUser_UserNotFound(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	userID := "nonexistent-user"
 	updateReq := buildUpdateUserRequest()
@@ -367,7 +370,7 @@ func TestUserService_VerifyFirebaseToken_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	idToken := "valid_id_token"
 	expectedToken := buildFirebaseToken()
@@ -394,7 +397,7 @@ func TestUserService_VerifyFirebaseToken_FirebaseNotConfigured(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, nil, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), nil, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	idToken := "id_token"
 
@@ -420,7 +423,7 @@ func TestUserService_VerifyFirebaseToken_InvalidToken(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	idToken := "invalid_token"
 	firebaseError := errors.New("invalid token")
@@ -450,7 +453,7 @@ func TestUserService_CreateUserFromToken_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	token := buildFirebaseToken()
 	expectedUser := buildTestUser()
@@ -479,7 +482,7 @@ func TestUserService_CreateUserFromToken_EmailNotFoundInToken(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	token := &auth.Token{
 		UID: "firebase_123",
@@ -511,7 +514,7 @@ func TestUserService_GoogleAuth_ExistingUser(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildGoogleLoginRequest()
 	token := buildFirebaseToken()
@@ -525,7 +528,7 @@ func TestUserService_GoogleAuth_ExistingUser(t *testing.T) {
 
 	// Mock repository calls
 	mockUserRepo.EXPECT().GetByEmail(ctx, token.Claims["email"].(string)).Return(existingUser, nil)
-	mockUserRepo.EXPECT().Update(ctx, gomock.Any()).Return(updatedUser, nil)
+	mockUserRepo.EXPECT().Update(ctx, gomock.Any(), gomock.Any()).Return(updatedUser, nil)
 
 	// Act
 	result, err := service.GoogleAuth(ctx, req)
@@ -549,7 +552,7 @@ func TestUserService_GoogleAuth_NewUser(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildGoogleLoginRequest()
 	token := buildFirebaseToken()
@@ -586,7 +589,7 @@ func TestUserService_GoogleAuth_InvalidToken(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildGoogleLoginRequest()
 	firebaseError := errors.New("invalid token")
@@ -616,7 +619,7 @@ func TestUserService_GoogleAuth_EmailMissingInToken(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildGoogleLoginRequest()
 	token := &auth.Token{
@@ -652,7 +655,7 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	req := buildLoginRequest()
 
@@ -671,6 +674,57 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	assert.Equal(t, shared.ErrUserNotFound, err)
 }
 
+func TestUserService_Login_CountsFailedAttempts(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewUserService(mockUserRepo, mockAttempts, nil, "test-api-key", mockLogger, mock_ports.NewReferralCodeRepositoryInterface(ctrl))
+	ctx := context.Background()
+	req := buildLoginRequest()
+	req.ClientIP = "10.0.0.1"
+
+	mockAttempts.EXPECT().Check(ctx, credential_attempt.ScopeUserLogin, req.Email, "10.0.0.1").Return(nil)
+	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockLogger.EXPECT().Error("User not found in database", gomock.Any())
+	mockAttempts.EXPECT().RecordFailure(ctx, credential_attempt.ScopeUserLogin, req.Email, "10.0.0.1").Return(nil)
+
+	// Act
+	result, err := service.Login(ctx, req)
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrUserNotFound)
+	assert.Nil(t, result)
+}
+
+func TestUserService_Login_Blocked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_ports.NewMockUserRepositoryInterface(ctrl)
+	mockAttempts := mock_ports.NewMockCredentialAttemptServiceInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewUserService(mockUserRepo, mockAttempts, nil, "test-api-key", mockLogger, mock_ports.NewReferralCodeRepositoryInterface(ctrl))
+	ctx := context.Background()
+	req := buildLoginRequest()
+
+	mockAttempts.EXPECT().Check(ctx, credential_attempt.ScopeUserLogin, req.Email, "").
+		Return(&credential_attempt.BlockedError{RetryAfter: time.Minute})
+
+	// Act
+	result, err := service.Login(ctx, req)
+
+	// Assert
+	assert.ErrorIs(t, err, shared.ErrTooManyAttempts)
+	assert.Nil(t, result)
+}
+
 func TestUserService_GetTotalUsers_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
@@ -681,7 +735,7 @@ func TestUserService_GetTotalUsers_Success(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	expectedCount := int64(100)
 
@@ -709,7 +763,7 @@ func TestUserService_GetTotalUsers_DatabaseError(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 	dbError := errors.New("database connection failed")
 
@@ -763,7 +817,7 @@ func TestUserService_CreateUser_InvalidEmails(t *testing.T) {
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 			mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-			service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+			service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 			ctx := context.Background()
 			req := &user.CreateUserRequest{
 				Email:    tc.email,
@@ -811,7 +865,7 @@ func TestUserService_CreateUser_Concurrent(t *testing.T) {
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 	mockReferralCodeRepo := mock_ports.NewReferralCodeRepositoryInterface(ctrl)
 
-	service := NewUserService(mockUserRepo, mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
+	service := NewUserService(mockUserRepo, allowCredentialAttempts(ctrl), mockFirebaseApp, "test-api-key", mockLogger, mockReferralCodeRepo)
 	ctx := context.Background()
 
 	const numGoroutines = 10
@@ -2,44 +2,145 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/waiting_list"
 	waitingListPorts "astroneko-backend/internal/core/ports/waiting_list"
 	"astroneko-backend/pkg/logger"
+	"astroneko-backend/pkg/mailer"
 )
 
+// statusEmailTimeout bounds sending a status email, which runs after the join has returned
+const statusEmailTimeout = 30 * time.Second
+
+// WaitingListService runs every product's waiting list. Lists are addressed by slug and
+// carry their own open state, capacity and required fields. Entries are queued in joining
+// order and move up for each person who joins through their invite link.
 type WaitingListService struct {
 	waitingListRepo waitingListPorts.RepositoryInterface
+	policy          waiting_list.Policy
+	mailer          mailer.Mailer
 	logger          logger.Logger
+	now             func() time.Time
 }
 
-func NewWaitingListService(waitingListRepo waitingListPorts.RepositoryInterface, log logger.Logger) *WaitingListService {
+func NewWaitingListService(waitingListRepo waitingListPorts.RepositoryInterface, policy waiting_list.Policy, mail mailer.Mailer, log logger.Logger) *WaitingListService {
 	return &WaitingListService{
 		waitingListRepo: waitingListRepo,
+		policy:          policy,
+		mailer:          mail,
 		logger:          log,
+		now:             time.Now,
 	}
 }
 
-func (s *WaitingListService) JoinWaitingList(ctx context.Context, email string) (*waiting_list.WaitingListUser, error) {
-	// Check if user already exists in waiting list
-	existingUser, err := s.waitingListRepo.GetByEmail(ctx, email)
-	if err == nil && existingUser != nil {
-		s.logger.Warn("User already exists in waiting list",
+// JoinWaitingList adds the email to the list. The list is locked for the duration, so the
+// duplicate and capacity checks and the queue number hold under concurrent joins. Once the
+// entry is saved, its status link is emailed to the joiner.
+func (s *WaitingListService) JoinWaitingList(ctx context.Context, slug string, join *waiting_list.Join) (*waiting_list.JoinedWaitingListResponse, error) {
+	email := join.Email
+	if s.policy.IsDisposableEmail(email) {
+		s.logger.Warn("Disposable email refused from waiting list",
 			logger.Field{Key: "module", Value: "waiting_list_service"},
+			logger.Field{Key: "list", Value: slug},
 			logger.Field{Key: "email", Value: email})
-		return nil, shared.ErrWaitingListUserAlreadyExists
+		return nil, waiting_list.ErrDisposableEmail
 	}
 
-	// Create new waiting list user
-	newWaitingListUser := &waiting_list.WaitingListUser{
-		Email: email,
-	}
+	var (
+		list            *waiting_list.WaitingList
+		waitingListUser *waiting_list.WaitingListUser
+		position        *waiting_list.QueuePosition
+	)
+	err := s.waitingListRepo.WithTx(ctx, func(tx waitingListPorts.RepositoryInterface) error {
+		var err error
+		list, err = tx.GetListBySlugForUpdate(ctx, slug)
+		if err != nil {
+			return err
+		}
+		if !list.IsOpen {
+			return waiting_list.ErrListClosed
+		}
+		cleaned, err := list.CleanFields(join.Fields)
+		if err != nil {
+			return err
+		}
+
+		// Check if user already exists in waiting list
+		existingUser, err := tx.GetByEmail(ctx, list.ID, email)
+		if err == nil && existingUser != nil {
+			s.logger.Warn("User already exists in waiting list",
+				logger.Field{Key: "module", Value: "waiting_list_service"},
+				logger.Field{Key: "list", Value: slug},
+				logger.Field{Key: "email", Value: email})
+			return shared.ErrWaitingListUserAlreadyExists
+		}
+
+		if list.Capacity != nil {
+			count, err := tx.CountEntries(ctx, list.ID)
+			if err != nil {
+				return err
+			}
+			if count >= int64(*list.Capacity) {
+				return waiting_list.ErrListFull
+			}
+		}
+
+		queueNumber, err := tx.NextQueueNumber(ctx, list.ID)
+		if err != nil {
+			return err
+		}
+		inviteCode, err := waiting_list.NewInviteCode()
+		if err != nil {
+			return err
+		}
+		referrer, err := s.creditableReferrer(ctx, tx, list, join)
+		if err != nil {
+			return err
+		}
+
+		// Create new waiting list user
+		newWaitingListUser := &waiting_list.WaitingListUser{
+			ListID:      list.ID,
+			Email:       strings.TrimSpace(email),
+			Fields:      cleaned,
+			QueueNumber: queueNumber,
+			InviteCode:  inviteCode,
+			Fingerprint: join.Fingerprint,
+		}
+		if referrer != nil {
+			newWaitingListUser.ReferredByID = &referrer.ID
+		}
+
+		waitingListUser, err = tx.Create(ctx, newWaitingListUser)
+		if err != nil {
+			s.logger.Error("Failed to add user to waiting list",
+				logger.Field{Key: "module", Value: "waiting_list_service"},
+				logger.Field{Key: "list", Value: slug},
+				logger.Field{Key: "email", Value: email},
+				logger.Field{Key: "error", Value: err.Error()})
+			return shared.ErrWaitingListUserCreationFailed
+		}
+		if referrer != nil {
+			if err := tx.CreditReferral(ctx, referrer.ID); err != nil {
+				return err
+			}
+		}
 
-	waitingListUser, err := s.waitingListRepo.Create(ctx, newWaitingListUser)
+		position, err = tx.GetPosition(ctx, waitingListUser, list.ReferralBoost)
+		return err
+	})
 	if err != nil {
-		s.logger.Error("Failed to add user to waiting list",
+		if isWaitingListRefusal(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to join waiting list",
 			logger.Field{Key: "module", Value: "waiting_list_service"},
+			logger.Field{Key: "list", Value: slug},
 			logger.Field{Key: "email", Value: email},
 			logger.Field{Key: "error", Value: err.Error()})
 		return nil, shared.ErrWaitingListUserCreationFailed
@@ -47,22 +148,165 @@ func (s *WaitingListService) JoinWaitingList(ctx context.Context, email string)
 
 	s.logger.Info("User successfully added to waiting list",
 		logger.Field{Key: "module", Value: "waiting_list_service"},
+		logger.Field{Key: "list", Value: slug},
 		logger.Field{Key: "user_id", Value: waitingListUser.ID.String()},
 		logger.Field{Key: "email", Value: email})
 
-	return waitingListUser, nil
+	s.sendStatusEmail(ctx, list, waitingListUser, position)
+
+	return &waiting_list.JoinedWaitingListResponse{
+		WaitingListUserResponse: *waitingListUser.ToResponse(),
+		Position:                position.Position,
+		Total:                   position.Total,
+		InviteLink:              list.InviteLink(waitingListUser.InviteCode),
+	}, nil
 }
 
-func (s *WaitingListService) GetWaitingListUsers(ctx context.Context, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
-	return s.waitingListRepo.List(ctx, limit, offset)
+// creditableReferrer returns the entry whose invite code the joiner used, or nil when there
+// is none or the referral does not count. A referral does not count when it comes from the
+// referrer's own device, or from a device that already earned the referrer a boost: both
+// point at one person signing up several addresses.
+func (s *WaitingListService) creditableReferrer(ctx context.Context, tx waitingListPorts.RepositoryInterface, list *waiting_list.WaitingList, join *waiting_list.Join) (*waiting_list.WaitingListUser, error) {
+	inviteCode := waiting_list.NormalizeInviteCode(join.InviteCode)
+	if inviteCode == "" {
+		return nil, nil
+	}
+	referrer, err := tx.GetByInviteCode(ctx, list.ID, inviteCode)
+	if errors.Is(err, waiting_list.ErrEntryNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if join.Fingerprint == "" {
+		return referrer, nil
+	}
+
+	selfReferral := referrer.Fingerprint == join.Fingerprint
+	if !selfReferral {
+		selfReferral, err = tx.HasReferralFromFingerprint(ctx, referrer.ID, join.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if selfReferral {
+		s.logger.Warn("Waiting list referral not credited, same device as an earlier entry",
+			logger.Field{Key: "module", Value: "waiting_list_service"},
+			logger.Field{Key: "list", Value: list.Slug},
+			logger.Field{Key: "referrer_id", Value: referrer.ID.String()},
+			logger.Field{Key: "email", Value: join.Email})
+		return nil, nil
+	}
+	return referrer, nil
 }
 
-func (s *WaitingListService) GetWaitingListUserByEmail(ctx context.Context, email string) (*waiting_list.WaitingListUser, error) {
-	return s.waitingListRepo.GetByEmail(ctx, email)
+// sendStatusEmail mails the joiner their place, invite code and status link in the
+// background, so a slow mail server does not hold up the join. The join stands when the
+// email cannot be sent.
+func (s *WaitingListService) sendStatusEmail(ctx context.Context, list *waiting_list.WaitingList, entry *waiting_list.WaitingListUser, position *waiting_list.QueuePosition) {
+	token := waiting_list.SignStatusToken(s.policy.StatusTokenKey, entry.ID, s.now().Add(s.policy.StatusTokenTTL))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "You're on the %s waiting list at position %d of %d.\n\n", list.Name, position.Position, position.Total)
+	if inviteLink := list.InviteLink(entry.InviteCode); inviteLink != "" {
+		fmt.Fprintf(&body, "Move up the list by sharing your invite link:\n%s\n\n", inviteLink)
+	} else {
+		fmt.Fprintf(&body, "Move up the list by sharing your invite code: %s\n\n", entry.InviteCode)
+	}
+	if statusLink := list.StatusLink(token); statusLink != "" {
+		fmt.Fprintf(&body, "Check your place at any time:\n%s\n", statusLink)
+	} else {
+		fmt.Fprintf(&body, "Your status token, to check your place at any time:\n%s\n", token)
+	}
+
+	msg := mailer.Message{
+		To:      entry.Email,
+		Subject: fmt.Sprintf("You're on the %s waiting list", list.Name),
+		Body:    body.String(),
+	}
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusEmailTimeout)
+	go func() {
+		defer cancel()
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			s.logger.Error("Failed to send waiting list status email",
+				logger.Field{Key: "module", Value: "waiting_list_service"},
+				logger.Field{Key: "list", Value: list.Slug},
+				logger.Field{Key: "user_id", Value: entry.ID.String()},
+				logger.Field{Key: "error", Value: err.Error()})
+		}
+	}()
 }
 
-func (s *WaitingListService) IsInWaitingListByEmail(ctx context.Context, email string) (bool, error) {
-	_, err := s.waitingListRepo.GetByEmail(ctx, email)
+// GetStatus returns the place of the entry a status token was issued for
+func (s *WaitingListService) GetStatus(ctx context.Context, token string) (*waiting_list.WaitingListStatusResponse, error) {
+	entryID, err := waiting_list.VerifyStatusToken(s.policy.StatusTokenKey, token, s.now())
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.waitingListRepo.GetByID(ctx, entryID.String())
+	if err != nil {
+		return nil, waiting_list.ErrEntryNotFound
+	}
+	list, err := s.waitingListRepo.GetListByID(ctx, entry.ListID)
+	if err != nil {
+		return nil, err
+	}
+	position, err := s.waitingListRepo.GetPosition(ctx, entry, list.ReferralBoost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &waiting_list.WaitingListStatusResponse{
+		List:          list.Slug,
+		Position:      position.Position,
+		Total:         position.Total,
+		ReferralCount: entry.ReferralCount,
+		InviteCode:    entry.InviteCode,
+		InviteLink:    list.InviteLink(entry.InviteCode),
+	}, nil
+}
+
+// isWaitingListRefusal reports whether a join failed for a reason the caller is told about
+// rather than a database failure
+func isWaitingListRefusal(err error) bool {
+	for _, refusal := range []error{
+		shared.ErrWaitingListUserAlreadyExists,
+		shared.ErrWaitingListUserCreationFailed,
+		waiting_list.ErrListNotFound,
+		waiting_list.ErrListClosed,
+		waiting_list.ErrListFull,
+		waiting_list.ErrMissingFields,
+		waiting_list.ErrInvalidFields,
+	} {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WaitingListService) GetWaitingListUsers(ctx context.Context, slug string, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.waitingListRepo.List(ctx, list.ID, limit, offset)
+}
+
+func (s *WaitingListService) GetWaitingListUserByEmail(ctx context.Context, slug, email string) (*waiting_list.WaitingListUser, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.waitingListRepo.GetByEmail(ctx, list.ID, email)
+}
+
+func (s *WaitingListService) IsInWaitingListByEmail(ctx context.Context, slug, email string) (bool, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return false, err
+	}
+	_, err = s.waitingListRepo.GetByEmail(ctx, list.ID, email)
 	if err != nil {
 		// If user not found, they're not in the waiting list
 		return false, nil
@@ -70,3 +314,183 @@ func (s *WaitingListService) IsInWaitingListByEmail(ctx context.Context, email s
 	// If no error, user exists in waiting list
 	return true, nil
 }
+
+// DeleteUser removes an entry, provided it is on the given list, and returns what was removed
+func (s *WaitingListService) DeleteUser(ctx context.Context, slug, id string) (*waiting_list.WaitingListUser, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.waitingListRepo.GetByID(ctx, id)
+	if err != nil || entry.ListID != list.ID {
+		return nil, waiting_list.ErrEntryNotFound
+	}
+	if err := s.waitingListRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetListInfo describes a list to someone about to join it
+func (s *WaitingListService) GetListInfo(ctx context.Context, slug string) (*waiting_list.WaitingListInfoResponse, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &waiting_list.WaitingListInfoResponse{
+		Slug:           list.Slug,
+		Name:           list.Name,
+		IsOpen:         list.IsOpen,
+		RequiredFields: list.RequiredFields,
+	}
+	if list.Capacity != nil {
+		count, err := s.waitingListRepo.CountEntries(ctx, list.ID)
+		if err != nil {
+			return nil, err
+		}
+		info.SpotsLeft = list.SpotsLeft(count)
+	}
+	return info, nil
+}
+
+// GetList returns a list with its settings and entry count
+func (s *WaitingListService) GetList(ctx context.Context, slug string) (*waiting_list.WaitingListResponse, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.toListResponse(ctx, list)
+}
+
+func (s *WaitingListService) ListLists(ctx context.Context) ([]*waiting_list.WaitingListResponse, error) {
+	lists, err := s.waitingListRepo.ListLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*waiting_list.WaitingListResponse, 0, len(lists))
+	for _, list := range lists {
+		response, err := s.toListResponse(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *WaitingListService) CreateList(ctx context.Context, req *waiting_list.CreateWaitingListRequest) (*waiting_list.WaitingListResponse, error) {
+	if err := waiting_list.ValidateSlug(req.Slug); err != nil {
+		return nil, err
+	}
+	requiredFields := req.RequiredFields
+	if requiredFields == nil {
+		requiredFields = []string{}
+	}
+	if err := waiting_list.ValidateRequiredFields(requiredFields); err != nil {
+		return nil, err
+	}
+	referralBoost := waiting_list.DefaultReferralBoost
+	if req.ReferralBoost != nil {
+		referralBoost = *req.ReferralBoost
+	}
+	if err := waiting_list.ValidateReferralBoost(referralBoost); err != nil {
+		return nil, err
+	}
+	if err := waiting_list.ValidateLandingURL(req.LandingURL); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.waitingListRepo.GetListBySlug(ctx, req.Slug); err == nil {
+		return nil, waiting_list.ErrListAlreadyExists
+	}
+
+	list := &waiting_list.WaitingList{
+		Slug:           req.Slug,
+		Name:           strings.TrimSpace(req.Name),
+		IsOpen:         req.IsOpen == nil || *req.IsOpen,
+		Capacity:       req.Capacity,
+		RequiredFields: requiredFields,
+		ReferralBoost:  referralBoost,
+		LandingURL:     req.LandingURL,
+	}
+	if err := s.waitingListRepo.CreateList(ctx, list); err != nil {
+		s.logger.Error("Failed to create waiting list",
+			logger.Field{Key: "module", Value: "waiting_list_service"},
+			logger.Field{Key: "list", Value: req.Slug},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	s.logger.Info("Waiting list created",
+		logger.Field{Key: "module", Value: "waiting_list_service"},
+		logger.Field{Key: "list", Value: list.Slug})
+	return &waiting_list.WaitingListResponse{WaitingList: *list, SpotsLeft: list.SpotsLeft(0)}, nil
+}
+
+func (s *WaitingListService) UpdateList(ctx context.Context, slug string, req *waiting_list.UpdateWaitingListRequest) (*waiting_list.WaitingListResponse, error) {
+	list, err := s.waitingListRepo.GetListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	if req.Name != nil {
+		list.Name = strings.TrimSpace(*req.Name)
+		columns = append(columns, waiting_list.ColumnName)
+	}
+	if req.IsOpen != nil {
+		list.IsOpen = *req.IsOpen
+		columns = append(columns, waiting_list.ColumnIsOpen)
+	}
+	if req.Capacity != nil {
+		list.Capacity = req.Capacity
+		if *req.Capacity == 0 {
+			list.Capacity = nil
+		}
+		columns = append(columns, waiting_list.ColumnCapacity)
+	}
+	if req.RequiredFields != nil {
+		if err := waiting_list.ValidateRequiredFields(*req.RequiredFields); err != nil {
+			return nil, err
+		}
+		list.RequiredFields = *req.RequiredFields
+		columns = append(columns, waiting_list.ColumnRequiredFields)
+	}
+	if req.ReferralBoost != nil {
+		if err := waiting_list.ValidateReferralBoost(*req.ReferralBoost); err != nil {
+			return nil, err
+		}
+		list.ReferralBoost = *req.ReferralBoost
+		columns = append(columns, waiting_list.ColumnReferralBoost)
+	}
+	if req.LandingURL != nil {
+		if err := waiting_list.ValidateLandingURL(*req.LandingURL); err != nil {
+			return nil, err
+		}
+		list.LandingURL = *req.LandingURL
+		columns = append(columns, waiting_list.ColumnLandingURL)
+	}
+
+	if err := s.waitingListRepo.UpdateList(ctx, list, columns); err != nil {
+		s.logger.Error("Failed to update waiting list",
+			logger.Field{Key: "module", Value: "waiting_list_service"},
+			logger.Field{Key: "list", Value: slug},
+			logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	return s.toListResponse(ctx, list)
+}
+
+func (s *WaitingListService) toListResponse(ctx context.Context, list *waiting_list.WaitingList) (*waiting_list.WaitingListResponse, error) {
+	count, err := s.waitingListRepo.CountEntries(ctx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &waiting_list.WaitingListResponse{
+		WaitingList: *list,
+		EntryCount:  count,
+		SpotsLeft:   list.SpotsLeft(count),
+	}, nil
+}
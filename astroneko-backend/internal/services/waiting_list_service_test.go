@@ -4,20 +4,75 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/core/domain/waiting_list"
+	waitingListPorts "astroneko-backend/internal/core/ports/waiting_list"
+	"astroneko-backend/pkg/mailer"
 	"astroneko-backend/testings/mock_logger"
+	"astroneko-backend/testings/mock_mailer"
 	"astroneko-backend/testings/mock_ports"
 )
 
+// testWaitingListID is the ID of the list returned by buildTestWaitingList
+var testWaitingListID = uuid.MustParse("0b6f8e2a-4c1d-4f7e-9a3b-5d2c8e1f6a70")
+
+// buildTestWaitingList is the open, uncapped default list
+func buildTestWaitingList() *waiting_list.WaitingList {
+	return &waiting_list.WaitingList{
+		ID:             testWaitingListID,
+		Slug:           waiting_list.DefaultListSlug,
+		Name:           "Astroneko",
+		IsOpen:         true,
+		RequiredFields: []string{},
+		ReferralBoost:  waiting_list.DefaultReferralBoost,
+	}
+}
+
+// runWaitingListTx runs a transaction body against the repository mock itself
+func runWaitingListTx(repo *mock_ports.MockWaitingListRepositoryInterface) func(context.Context, func(waitingListPorts.RepositoryInterface) error) error {
+	return func(_ context.Context, fn func(tx waitingListPorts.RepositoryInterface) error) error {
+		return fn(repo)
+	}
+}
+
+// expectJoinTx expects a join's transaction, which locks the default list and, when the
+// join gets that far, queues the entry at the back of the list
+func expectJoinTx(repo *mock_ports.MockWaitingListRepositoryInterface) {
+	repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runWaitingListTx(repo))
+	repo.EXPECT().GetListBySlugForUpdate(gomock.Any(), waiting_list.DefaultListSlug).Return(buildTestWaitingList(), nil)
+	repo.EXPECT().NextQueueNumber(gomock.Any(), testWaitingListID).Return(int64(1), nil).AnyTimes()
+	repo.EXPECT().GetPosition(gomock.Any(), gomock.Any(), waiting_list.DefaultReferralBoost).Return(&waiting_list.QueuePosition{Position: 1, Total: 1}, nil).AnyTimes()
+}
+
+// testWaitingListPolicy signs status tokens with a fixed key and refuses the built-in
+// disposable domains
+var testWaitingListPolicy = waiting_list.Policy{
+	StatusTokenKey: []byte("test-waiting-list-status-token-key"),
+	StatusTokenTTL: waiting_list.DefaultStatusTokenTTL,
+}
+
+// discardMailer accepts every email without sending it
+type discardMailer struct{}
+
+func (discardMailer) Send(context.Context, mailer.Message) error {
+	return nil
+}
+
+// expectWaitingList expects the default list to be looked up
+func expectWaitingList(repo *mock_ports.MockWaitingListRepositoryInterface) {
+	repo.EXPECT().GetListBySlug(gomock.Any(), waiting_list.DefaultListSlug).Return(buildTestWaitingList(), nil)
+}
+
 // Test data builders for consistent test data
 func buildWaitingListUser() *waiting_list.WaitingListUser {
 	return &waiting_list.WaitingListUser{
@@ -45,13 +100,14 @@ func TestWaitingListService_JoinWaitingList_Success(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "test@example.com"
 	expectedUser := buildWaitingListUserWithID("123e4567-e89b-12d3-a456-426614174000")
 
 	// Setup expectations - user not found, then creation succeeds
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	expectJoinTx(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil)
 
 	// Mock logger calls
@@ -60,12 +116,12 @@ func TestWaitingListService_JoinWaitingList_Success(t *testing.T) {
 	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
 
 	// Act
-	result, err := service.JoinWaitingList(ctx, email)
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 
 	// Assert
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.Equal(t, expectedUser.ID, result.ID)
+	assert.Equal(t, expectedUser.ID.String(), result.ID)
 	assert.Equal(t, email, result.Email)
 }
 
@@ -77,13 +133,14 @@ func TestWaitingListService_JoinWaitingList_UserAlreadyExists(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "existing@example.com"
 	existingUser := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", email)
 
 	// Setup expectations - user already exists
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(existingUser, nil)
+	expectJoinTx(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(existingUser, nil)
 
 	// Mock logger calls
 	mockLogger.EXPECT().Warn("User already exists in waiting list", gomock.Any())
@@ -91,7 +148,7 @@ func TestWaitingListService_JoinWaitingList_UserAlreadyExists(t *testing.T) {
 	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any()).Times(0) // Should not be called
 
 	// Act
-	result, err := service.JoinWaitingList(ctx, email)
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 
 	// Assert
 	assert.Error(t, err)
@@ -107,13 +164,14 @@ func TestWaitingListService_JoinWaitingList_CreationFailed(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "fail@example.com"
 	dbError := errors.New("database constraint violation")
 
 	// Setup expectations - user not found, but creation fails
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	expectJoinTx(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, dbError)
 
 	// Mock logger calls
@@ -122,7 +180,7 @@ func TestWaitingListService_JoinWaitingList_CreationFailed(t *testing.T) {
 	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any()).Times(0) // Should not be called
 
 	// Act
-	result, err := service.JoinWaitingList(ctx, email)
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 
 	// Assert
 	assert.Error(t, err)
@@ -138,25 +196,26 @@ func TestWaitingListService_JoinWaitingList_EmptyEmail(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := ""
 	expectedUser := &waiting_list.WaitingListUser{Email: email}
 
 	// Setup expectations - empty email should be processed normally
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	expectJoinTx(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil)
 
 	// Mock logger calls
 	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
 
 	// Act
-	result, err := service.JoinWaitingList(ctx, email)
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 
 	// Assert
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.Equal(t, expectedUser.ID, result.ID)
+	assert.Equal(t, expectedUser.ID.String(), result.ID)
 	assert.Equal(t, email, result.Email)
 }
 
@@ -168,7 +227,7 @@ func TestWaitingListService_GetWaitingListUsers_Success(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	limit := 10
 	offset := 0
@@ -179,10 +238,11 @@ func TestWaitingListService_GetWaitingListUsers_Success(t *testing.T) {
 	expectedCount := int64(2)
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().List(ctx, limit, offset).Return(expectedUsers, expectedCount, nil)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().List(ctx, testWaitingListID, limit, offset).Return(expectedUsers, expectedCount, nil)
 
 	// Act
-	users, count, err := service.GetWaitingListUsers(ctx, limit, offset)
+	users, count, err := service.GetWaitingListUsers(ctx, waiting_list.DefaultListSlug, limit, offset)
 
 	// Assert
 	require.NoError(t, err)
@@ -200,17 +260,18 @@ func TestWaitingListService_GetWaitingListUsers_RepositoryError(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	limit := 10
 	offset := 0
 	dbError := errors.New("database connection failed")
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().List(ctx, limit, offset).Return(nil, int64(0), dbError)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().List(ctx, testWaitingListID, limit, offset).Return(nil, int64(0), dbError)
 
 	// Act
-	users, count, err := service.GetWaitingListUsers(ctx, limit, offset)
+	users, count, err := service.GetWaitingListUsers(ctx, waiting_list.DefaultListSlug, limit, offset)
 
 	// Assert
 	assert.Error(t, err)
@@ -227,7 +288,7 @@ func TestWaitingListService_GetWaitingListUsers_Pagination(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 
 	testCases := []struct {
@@ -267,10 +328,11 @@ func TestWaitingListService_GetWaitingListUsers_Pagination(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup expectations
-			mockWaitingListRepo.EXPECT().List(ctx, tc.limit, tc.offset).Return(tc.expectedUsers, tc.expectedCount, nil)
+			expectWaitingList(mockWaitingListRepo)
+			mockWaitingListRepo.EXPECT().List(ctx, testWaitingListID, tc.limit, tc.offset).Return(tc.expectedUsers, tc.expectedCount, nil)
 
 			// Act
-			users, count, err := service.GetWaitingListUsers(ctx, tc.limit, tc.offset)
+			users, count, err := service.GetWaitingListUsers(ctx, waiting_list.DefaultListSlug, tc.limit, tc.offset)
 
 			// Assert
 			require.NoError(t, err)
@@ -288,16 +350,17 @@ func TestWaitingListService_GetWaitingListUserByEmail_Success(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "test@example.com"
 	expectedUser := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", email)
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(expectedUser, nil)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(expectedUser, nil)
 
 	// Act
-	result, err := service.GetWaitingListUserByEmail(ctx, email)
+	result, err := service.GetWaitingListUserByEmail(ctx, waiting_list.DefaultListSlug, email)
 
 	// Assert
 	require.NoError(t, err)
@@ -314,15 +377,16 @@ func TestWaitingListService_GetWaitingListUserByEmail_NotFound(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "nonexistent@example.com"
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 
 	// Act
-	result, err := service.GetWaitingListUserByEmail(ctx, email)
+	result, err := service.GetWaitingListUserByEmail(ctx, waiting_list.DefaultListSlug, email)
 
 	// Assert
 	assert.Error(t, err)
@@ -338,16 +402,17 @@ func TestWaitingListService_IsInWaitingListByEmail_UserExists(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "existing@example.com"
 	existingUser := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", email)
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(existingUser, nil)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(existingUser, nil)
 
 	// Act
-	isInList, err := service.IsInWaitingListByEmail(ctx, email)
+	isInList, err := service.IsInWaitingListByEmail(ctx, waiting_list.DefaultListSlug, email)
 
 	// Assert
 	require.NoError(t, err)
@@ -362,15 +427,16 @@ func TestWaitingListService_IsInWaitingListByEmail_UserNotExists(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "nonexistent@example.com"
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 
 	// Act
-	isInList, err := service.IsInWaitingListByEmail(ctx, email)
+	isInList, err := service.IsInWaitingListByEmail(ctx, waiting_list.DefaultListSlug, email)
 
 	// Assert
 	require.NoError(t, err)
@@ -385,16 +451,17 @@ func TestWaitingListService_IsInWaitingListByEmail_DatabaseError(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	email := "error@example.com"
 	dbError := errors.New("database connection failed")
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, dbError)
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, dbError)
 
 	// Act
-	isInList, err := service.IsInWaitingListByEmail(ctx, email)
+	isInList, err := service.IsInWaitingListByEmail(ctx, waiting_list.DefaultListSlug, email)
 
 	// Assert
 	require.NoError(t, err) // Service should handle error and return false
@@ -468,28 +535,31 @@ func TestWaitingListService_JoinWaitingList_BusinessLogic(t *testing.T) {
 			mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-			service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+			service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 			ctx := context.Background()
 
 			// Setup repository expectations
 			if tc.existingUser != nil {
-				mockWaitingListRepo.EXPECT().GetByEmail(ctx, tc.email).Return(tc.existingUser, nil)
+				expectJoinTx(mockWaitingListRepo)
+				mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, tc.email).Return(tc.existingUser, nil)
 				mockLogger.EXPECT().Warn("User already exists in waiting list", gomock.Any())
 			} else if tc.repoError != nil {
 				if tc.repoError.Error() == "connection failed" {
-					mockWaitingListRepo.EXPECT().GetByEmail(ctx, tc.email).Return(nil, tc.repoError)
+					expectJoinTx(mockWaitingListRepo)
+					mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, tc.email).Return(nil, tc.repoError)
 					mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, tc.repoError)
 					mockLogger.EXPECT().Error("Failed to add user to waiting list", gomock.Any())
 				}
 			} else {
-				mockWaitingListRepo.EXPECT().GetByEmail(ctx, tc.email).Return(nil, gorm.ErrRecordNotFound)
+				expectJoinTx(mockWaitingListRepo)
+				mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, tc.email).Return(nil, gorm.ErrRecordNotFound)
 				expectedUser := &waiting_list.WaitingListUser{Email: tc.email}
 				mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil)
 				mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
 			}
 
 			// Act
-			result, err := service.JoinWaitingList(ctx, tc.email)
+			result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: tc.email})
 
 			// Assert
 			if tc.expectedError != nil {
@@ -513,18 +583,20 @@ func BenchmarkWaitingListService_JoinWaitingList(b *testing.B) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 	expectedUser := buildWaitingListUserWithID("123e4567-e89b-12d3-a456-426614174000")
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockWaitingListRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runWaitingListTx(mockWaitingListRepo)).AnyTimes()
+	mockWaitingListRepo.EXPECT().GetListBySlugForUpdate(ctx, waiting_list.DefaultListSlug).Return(buildTestWaitingList(), nil).AnyTimes()
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
 	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil).AnyTimes()
 	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any()).AnyTimes()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := service.JoinWaitingList(ctx, fmt.Sprintf("user%d@example.com", i))
+		_, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: fmt.Sprintf("user%d@example.com", i)})
 		if err != nil {
 			b.Fatalf("unexpected error: %v", err)
 		}
@@ -540,11 +612,11 @@ func TestWaitingListService_JoinWaitingList_Concurrent(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx := context.Background()
 
 	const numGoroutines = 10
-	results := make(chan *waiting_list.WaitingListUser, numGoroutines)
+	results := make(chan *waiting_list.JoinedWaitingListResponse, numGoroutines)
 	errors := make(chan error, numGoroutines)
 
 	// Mock expectations for concurrent calls
@@ -552,7 +624,8 @@ func TestWaitingListService_JoinWaitingList_Concurrent(t *testing.T) {
 		email := fmt.Sprintf("user%d@example.com", i)
 		expectedUser := buildWaitingListUserWithID(fmt.Sprintf("123e4567-e89b-12d3-a456-426614%03d", i))
 
-		mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, gorm.ErrRecordNotFound)
+		expectJoinTx(mockWaitingListRepo)
+		mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, gorm.ErrRecordNotFound)
 		mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil)
 		mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
 	}
@@ -561,7 +634,7 @@ func TestWaitingListService_JoinWaitingList_Concurrent(t *testing.T) {
 	for i := 0; i < numGoroutines; i++ {
 		go func(id int) {
 			email := fmt.Sprintf("user%d@example.com", id)
-			result, err := service.JoinWaitingList(ctx, email)
+			result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 			results <- result
 			errors <- err
 		}(i)
@@ -585,7 +658,7 @@ func TestWaitingListService_Timeout(t *testing.T) {
 	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-	service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
 	defer cancel()
 
@@ -593,12 +666,13 @@ func TestWaitingListService_Timeout(t *testing.T) {
 	timeoutError := errors.New("context deadline exceeded")
 
 	// Setup expectations
-	mockWaitingListRepo.EXPECT().GetByEmail(ctx, email).Return(nil, timeoutError)
+	expectJoinTx(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, email).Return(nil, timeoutError)
 	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, timeoutError)
 	mockLogger.EXPECT().Error("Failed to add user to waiting list", gomock.Any())
 
 	// Act
-	result, err := service.JoinWaitingList(ctx, email)
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: email})
 
 	// Assert
 	assert.Error(t, err)
@@ -661,19 +735,20 @@ func TestWaitingListService_EmailFormats(t *testing.T) {
 			mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
 
-			service := NewWaitingListService(mockWaitingListRepo, mockLogger)
+			service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
 			ctx := context.Background()
 
 			if tc.shouldSucceed {
 				expectedUser := &waiting_list.WaitingListUser{Email: tc.email}
 
-				mockWaitingListRepo.EXPECT().GetByEmail(ctx, tc.email).Return(nil, gorm.ErrRecordNotFound)
+				expectJoinTx(mockWaitingListRepo)
+				mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, tc.email).Return(nil, gorm.ErrRecordNotFound)
 				mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).Return(expectedUser, nil)
 				mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
 			}
 
 			// Act
-			result, err := service.JoinWaitingList(ctx, tc.email)
+			result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: tc.email})
 
 			// Assert
 			if tc.shouldSucceed {
@@ -684,3 +759,478 @@ func TestWaitingListService_EmailFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitingListService_JoinWaitingList_ListSettings(t *testing.T) {
+	capacity := 2
+
+	testCases := []struct {
+		name          string
+		list          *waiting_list.WaitingList
+		listErr       error
+		fields        map[string]string
+		entries       int64
+		expectedError error
+	}{
+		{
+			name:          "Unknown list",
+			listErr:       waiting_list.ErrListNotFound,
+			expectedError: waiting_list.ErrListNotFound,
+		},
+		{
+			name:          "Closed list",
+			list:          &waiting_list.WaitingList{ID: testWaitingListID, Slug: "astro-boxing", IsOpen: false},
+			expectedError: waiting_list.ErrListClosed,
+		},
+		{
+			name:          "Missing required field",
+			list:          &waiting_list.WaitingList{ID: testWaitingListID, Slug: "astro-boxing", IsOpen: true, RequiredFields: []string{"name", "country"}},
+			fields:        map[string]string{"name": "Nok", "country": "  "},
+			expectedError: waiting_list.ErrMissingFields,
+		},
+		{
+			name:          "Full list",
+			list:          &waiting_list.WaitingList{ID: testWaitingListID, Slug: "astro-boxing", IsOpen: true, Capacity: &capacity},
+			entries:       2,
+			expectedError: waiting_list.ErrListFull,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+			service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+			ctx := context.Background()
+
+			mockWaitingListRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runWaitingListTx(mockWaitingListRepo))
+			mockWaitingListRepo.EXPECT().GetListBySlugForUpdate(ctx, "astro-boxing").Return(tc.list, tc.listErr)
+			if tc.list != nil && tc.list.Capacity != nil {
+				mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, "fan@example.com").Return(nil, gorm.ErrRecordNotFound)
+				mockWaitingListRepo.EXPECT().CountEntries(ctx, testWaitingListID).Return(tc.entries, nil)
+			}
+
+			// Act
+			result, err := service.JoinWaitingList(ctx, "astro-boxing", &waiting_list.Join{Email: "fan@example.com", Fields: tc.fields})
+
+			// Assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestWaitingListService_JoinWaitingList_StoresFields(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+	capacity := 100
+	list := &waiting_list.WaitingList{ID: testWaitingListID, Slug: "astro-boxing", IsOpen: true, Capacity: &capacity, RequiredFields: []string{"name"}}
+
+	mockWaitingListRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runWaitingListTx(mockWaitingListRepo))
+	mockWaitingListRepo.EXPECT().GetListBySlugForUpdate(ctx, "astro-boxing").Return(list, nil)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, " fan@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockWaitingListRepo.EXPECT().CountEntries(ctx, testWaitingListID).Return(int64(99), nil)
+	mockWaitingListRepo.EXPECT().NextQueueNumber(ctx, testWaitingListID).Return(int64(100), nil)
+	var created *waiting_list.WaitingListUser
+	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error) {
+		created = user
+		return user, nil
+	})
+	mockWaitingListRepo.EXPECT().GetPosition(ctx, gomock.Any(), 0).Return(&waiting_list.QueuePosition{Position: 100, Total: 100}, nil)
+	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
+
+	// Act
+	result, err := service.JoinWaitingList(ctx, "astro-boxing", &waiting_list.Join{Email: " fan@example.com", Fields: map[string]string{"name": " Nok ", "instagram": ""}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testWaitingListID, created.ListID)
+	assert.Equal(t, int64(100), created.QueueNumber)
+	assert.Len(t, created.InviteCode, waiting_list.InviteCodeLength)
+	assert.Equal(t, "fan@example.com", result.Email)
+	assert.Equal(t, map[string]string{"name": "Nok"}, result.Fields)
+	assert.Equal(t, int64(100), result.Position)
+	assert.Empty(t, result.InviteLink)
+}
+
+func TestWaitingListService_CreateList(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+
+	mockWaitingListRepo.EXPECT().GetListBySlug(ctx, "tarot-deck").Return(nil, waiting_list.ErrListNotFound)
+	mockWaitingListRepo.EXPECT().CreateList(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, list *waiting_list.WaitingList) error {
+		list.ID = uuid.New()
+		return nil
+	})
+	mockLogger.EXPECT().Info("Waiting list created", gomock.Any())
+
+	// Act
+	list, err := service.CreateList(ctx, &waiting_list.CreateWaitingListRequest{Slug: "tarot-deck", Name: " Tarot Deck "})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Tarot Deck", list.Name)
+	assert.True(t, list.IsOpen)
+	assert.Nil(t, list.Capacity)
+	assert.Empty(t, list.RequiredFields)
+	assert.NotNil(t, list.RequiredFields)
+}
+
+func TestWaitingListService_CreateList_Closed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+	isOpen := false
+
+	var persisted *waiting_list.WaitingList
+	mockWaitingListRepo.EXPECT().GetListBySlug(ctx, "tarot-deck").Return(nil, waiting_list.ErrListNotFound)
+	mockWaitingListRepo.EXPECT().CreateList(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, list *waiting_list.WaitingList) error {
+		list.ID = uuid.New()
+		persisted = list
+		return nil
+	})
+	mockLogger.EXPECT().Info("Waiting list created", gomock.Any())
+
+	// Act
+	list, err := service.CreateList(ctx, &waiting_list.CreateWaitingListRequest{Slug: "tarot-deck", Name: "Tarot Deck", IsOpen: &isOpen})
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, persisted)
+	assert.False(t, persisted.IsOpen)
+	assert.False(t, list.IsOpen)
+}
+
+func TestWaitingListService_CreateList_Refused(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+
+	mockWaitingListRepo.EXPECT().GetListBySlug(ctx, waiting_list.DefaultListSlug).Return(buildTestWaitingList(), nil)
+
+	// Act
+	_, invalidSlugErr := service.CreateList(ctx, &waiting_list.CreateWaitingListRequest{Slug: "Tarot Deck", Name: "Tarot Deck"})
+	_, invalidFieldErr := service.CreateList(ctx, &waiting_list.CreateWaitingListRequest{Slug: "tarot-deck", Name: "Tarot Deck", RequiredFields: []string{"Full Name"}})
+	_, existsErr := service.CreateList(ctx, &waiting_list.CreateWaitingListRequest{Slug: waiting_list.DefaultListSlug, Name: "Astroneko"})
+
+	// Assert
+	assert.ErrorIs(t, invalidSlugErr, waiting_list.ErrInvalidSettings)
+	assert.ErrorIs(t, invalidFieldErr, waiting_list.ErrInvalidSettings)
+	assert.ErrorIs(t, existsErr, waiting_list.ErrListAlreadyExists)
+}
+
+func TestWaitingListService_UpdateList(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+	capacity := 500
+	list := buildTestWaitingList()
+	list.Capacity = &capacity
+	closed, noCap := false, 0
+
+	mockWaitingListRepo.EXPECT().GetListBySlug(ctx, waiting_list.DefaultListSlug).Return(list, nil)
+	mockWaitingListRepo.EXPECT().UpdateList(ctx, gomock.Any(), []string{waiting_list.ColumnIsOpen, waiting_list.ColumnCapacity}).DoAndReturn(func(_ context.Context, updated *waiting_list.WaitingList, _ []string) error {
+		assert.False(t, updated.IsOpen)
+		assert.Nil(t, updated.Capacity)
+		return nil
+	})
+	mockWaitingListRepo.EXPECT().CountEntries(ctx, testWaitingListID).Return(int64(640), nil)
+
+	// Act
+	response, err := service.UpdateList(ctx, waiting_list.DefaultListSlug, &waiting_list.UpdateWaitingListRequest{IsOpen: &closed, Capacity: &noCap})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(640), response.EntryCount)
+	assert.Nil(t, response.SpotsLeft)
+}
+
+func TestWaitingListService_DeleteUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+	entry := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", "fan@example.com")
+	entry.ListID = testWaitingListID
+
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByID(ctx, "123e4567-e89b-12d3-a456-426614174000").Return(entry, nil)
+	mockWaitingListRepo.EXPECT().Delete(ctx, "123e4567-e89b-12d3-a456-426614174000").Return(nil)
+
+	// Act
+	deleted, err := service.DeleteUser(ctx, waiting_list.DefaultListSlug, "123e4567-e89b-12d3-a456-426614174000")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entry, deleted)
+}
+
+func TestWaitingListService_DeleteUser_OtherList(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+	entry := buildWaitingListUserWithIDAndEmail("123e4567-e89b-12d3-a456-426614174000", "fan@example.com")
+	entry.ListID = uuid.New()
+
+	expectWaitingList(mockWaitingListRepo)
+	mockWaitingListRepo.EXPECT().GetByID(ctx, "123e4567-e89b-12d3-a456-426614174000").Return(entry, nil)
+
+	// Act
+	_, err := service.DeleteUser(ctx, waiting_list.DefaultListSlug, "123e4567-e89b-12d3-a456-426614174000")
+
+	// Assert
+	assert.ErrorIs(t, err, waiting_list.ErrEntryNotFound)
+}
+
+func TestWaitingListService_JoinWaitingList_DisposableEmail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	policy := testWaitingListPolicy
+	policy.DisposableDomains = []string{"burner.example"}
+	service := NewWaitingListService(mockWaitingListRepo, policy, discardMailer{}, mockLogger)
+	ctx := context.Background()
+
+	mockLogger.EXPECT().Warn("Disposable email refused from waiting list", gomock.Any()).Times(2)
+
+	// Act
+	_, builtInErr := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: "fan@Mailinator.com"})
+	_, configuredErr := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: "fan@eu.burner.example"})
+
+	// Assert
+	assert.ErrorIs(t, builtInErr, waiting_list.ErrDisposableEmail)
+	assert.ErrorIs(t, configuredErr, waiting_list.ErrDisposableEmail)
+}
+
+func TestWaitingListService_JoinWaitingList_Referral(t *testing.T) {
+	referrerID := uuid.New()
+
+	testCases := []struct {
+		name            string
+		inviteCode      string
+		referrer        *waiting_list.WaitingListUser
+		referrerErr     error
+		seenFingerprint bool
+		credited        bool
+	}{
+		{
+			name:       "Credited",
+			inviteCode: " abcdefgh23 ",
+			referrer:   &waiting_list.WaitingListUser{ListID: testWaitingListID, InviteCode: "ABCDEFGH23", Fingerprint: "referrer-device"},
+			credited:   true,
+		},
+		{
+			name:       "Same device as the referrer",
+			inviteCode: "ABCDEFGH23",
+			referrer:   &waiting_list.WaitingListUser{ListID: testWaitingListID, InviteCode: "ABCDEFGH23", Fingerprint: "joiner-device"},
+		},
+		{
+			name:            "Device already credited to the referrer",
+			inviteCode:      "ABCDEFGH23",
+			referrer:        &waiting_list.WaitingListUser{ListID: testWaitingListID, InviteCode: "ABCDEFGH23", Fingerprint: "referrer-device"},
+			seenFingerprint: true,
+		},
+		{
+			name:        "Unknown invite code",
+			inviteCode:  "ZZZZZZZZZZ",
+			referrerErr: waiting_list.ErrEntryNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+			service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+			ctx := context.Background()
+			if tc.referrer != nil {
+				tc.referrer.ID = referrerID
+			}
+
+			expectJoinTx(mockWaitingListRepo)
+			mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, "friend@example.com").Return(nil, gorm.ErrRecordNotFound)
+			mockWaitingListRepo.EXPECT().GetByInviteCode(ctx, testWaitingListID, strings.TrimSpace(strings.ToUpper(tc.inviteCode))).Return(tc.referrer, tc.referrerErr)
+			if tc.referrer != nil && tc.referrer.Fingerprint != "joiner-device" {
+				mockWaitingListRepo.EXPECT().HasReferralFromFingerprint(ctx, referrerID, "joiner-device").Return(tc.seenFingerprint, nil)
+			}
+			var created *waiting_list.WaitingListUser
+			mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error) {
+				created = user
+				return user, nil
+			})
+			if tc.credited {
+				mockWaitingListRepo.EXPECT().CreditReferral(ctx, referrerID).Return(nil)
+			} else if tc.referrer != nil {
+				mockLogger.EXPECT().Warn("Waiting list referral not credited, same device as an earlier entry", gomock.Any())
+			}
+			mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
+
+			// Act
+			_, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{
+				Email:       "friend@example.com",
+				InviteCode:  tc.inviteCode,
+				Fingerprint: "joiner-device",
+			})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "joiner-device", created.Fingerprint)
+			if tc.credited {
+				require.NotNil(t, created.ReferredByID)
+				assert.Equal(t, referrerID, *created.ReferredByID)
+			} else {
+				assert.Nil(t, created.ReferredByID)
+			}
+		})
+	}
+}
+
+func TestWaitingListService_JoinWaitingList_EmailsStatusLink(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, mockMailer, mockLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	list := buildTestWaitingList()
+	list.LandingURL = "https://astroneko.com/waitlist"
+
+	mockWaitingListRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runWaitingListTx(mockWaitingListRepo))
+	mockWaitingListRepo.EXPECT().GetListBySlugForUpdate(ctx, waiting_list.DefaultListSlug).Return(list, nil)
+	mockWaitingListRepo.EXPECT().GetByEmail(ctx, testWaitingListID, "fan@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockWaitingListRepo.EXPECT().NextQueueNumber(ctx, testWaitingListID).Return(int64(42), nil)
+	mockWaitingListRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error) {
+		user.ID = uuid.New()
+		return user, nil
+	})
+	mockWaitingListRepo.EXPECT().GetPosition(ctx, gomock.Any(), waiting_list.DefaultReferralBoost).Return(&waiting_list.QueuePosition{Position: 42, Total: 42}, nil)
+	mockLogger.EXPECT().Info("User successfully added to waiting list", gomock.Any())
+
+	var (
+		sent    mailer.Message
+		sendErr error
+	)
+	release := make(chan struct{})
+	logged := make(chan struct{})
+	mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(sendCtx context.Context, msg mailer.Message) error {
+		<-release
+		sent = msg
+		sendErr = sendCtx.Err()
+		return errors.New("smtp unavailable")
+	})
+	mockLogger.EXPECT().Error("Failed to send waiting list status email", gomock.Any()).Do(func(string, ...interface{}) {
+		close(logged)
+	})
+
+	// Act
+	result, err := service.JoinWaitingList(ctx, waiting_list.DefaultListSlug, &waiting_list.Join{Email: "fan@example.com"})
+	cancel()
+	close(release)
+	<-logged
+
+	// Assert
+	require.NoError(t, err, "a failed email must not fail the join")
+	assert.NoError(t, sendErr, "the email must outlive the request that joined")
+	assert.Equal(t, int64(42), result.Position)
+	assert.Equal(t, "https://astroneko.com/waitlist?invite="+result.InviteCode, result.InviteLink)
+	assert.Equal(t, "fan@example.com", sent.To)
+	assert.Contains(t, sent.Body, "position 42 of 42")
+	assert.Contains(t, sent.Body, result.InviteLink)
+	assert.Contains(t, sent.Body, "https://astroneko.com/waitlist?status_token="+result.ID+".")
+}
+
+func TestWaitingListService_GetStatus(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitingListRepo := mock_ports.NewMockWaitingListRepositoryInterface(ctrl)
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+
+	service := NewWaitingListService(mockWaitingListRepo, testWaitingListPolicy, discardMailer{}, mockLogger)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	entry := &waiting_list.WaitingListUser{ListID: testWaitingListID, QueueNumber: 120, ReferralCount: 3, InviteCode: "ABCDEFGH23"}
+	entry.ID = uuid.New()
+	token := waiting_list.SignStatusToken(testWaitingListPolicy.StatusTokenKey, entry.ID, now.Add(time.Hour))
+
+	mockWaitingListRepo.EXPECT().GetByID(ctx, entry.ID.String()).Return(entry, nil)
+	mockWaitingListRepo.EXPECT().GetListByID(ctx, testWaitingListID).Return(buildTestWaitingList(), nil)
+	mockWaitingListRepo.EXPECT().GetPosition(ctx, entry, waiting_list.DefaultReferralBoost).Return(&waiting_list.QueuePosition{Position: 105, Total: 300}, nil)
+
+	// Act
+	status, err := service.GetStatus(ctx, token)
+	_, forgedErr := service.GetStatus(ctx, waiting_list.SignStatusToken([]byte("another key"), entry.ID, now.Add(time.Hour)))
+	_, expiredErr := service.GetStatus(ctx, waiting_list.SignStatusToken(testWaitingListPolicy.StatusTokenKey, entry.ID, now.Add(-time.Second)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, waiting_list.DefaultListSlug, status.List)
+	assert.Equal(t, int64(105), status.Position)
+	assert.Equal(t, int64(300), status.Total)
+	assert.Equal(t, 3, status.ReferralCount)
+	assert.ErrorIs(t, forgedErr, waiting_list.ErrInvalidStatusToken)
+	assert.ErrorIs(t, expiredErr, waiting_list.ErrInvalidStatusToken)
+}
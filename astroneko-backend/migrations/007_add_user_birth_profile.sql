@@ -0,0 +1,10 @@
+-- Migration: Add astrology birth profile to users
+-- Description: Stores birth date, time, place and timezone used to compute natal charts
+
+ALTER TABLE astroneko_auth_users
+    ADD COLUMN IF NOT EXISTS birth_date DATE,
+    ADD COLUMN IF NOT EXISTS birth_time VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS birth_place VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS birth_latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS birth_longitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS birth_timezone VARCHAR(64);
@@ -0,0 +1,15 @@
+-- Migration: Create astroneko_daily_horoscopes table
+-- Description: Stores one generated horoscope per zodiac sign per day in Thai and English
+
+CREATE TABLE IF NOT EXISTS astroneko_daily_horoscopes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sign VARCHAR(20) NOT NULL,
+    horoscope_date DATE NOT NULL,
+    content_th TEXT NOT NULL,
+    content_en TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_daily_horoscopes_sign_date UNIQUE (sign, horoscope_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_horoscopes_date ON astroneko_daily_horoscopes(horoscope_date);
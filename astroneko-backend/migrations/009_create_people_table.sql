@@ -0,0 +1,19 @@
+-- Migration: Create astroneko_people table
+-- Description: Stores birth profiles users save for other people (partner, friend, family) for compatibility readings
+
+CREATE TABLE IF NOT EXISTS astroneko_people (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES astroneko_auth_users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    relationship VARCHAR(20) NOT NULL,
+    birth_date DATE NOT NULL,
+    birth_time VARCHAR(5),
+    birth_place VARCHAR(255),
+    birth_latitude DOUBLE PRECISION,
+    birth_longitude DOUBLE PRECISION,
+    birth_timezone VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_people_user_id ON astroneko_people(user_id);
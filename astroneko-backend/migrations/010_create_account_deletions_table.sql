@@ -0,0 +1,35 @@
+-- Migration: Create account deletion tables
+-- Description: Tracks account deletion requests through the grace period and keeps an append-only audit trail of each purge.
+-- Neither table references astroneko_auth_users so the records survive the deletion they describe.
+
+CREATE TABLE IF NOT EXISTS astroneko_account_deletions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    firebase_uid VARCHAR(128),
+    status VARCHAR(32) NOT NULL,
+    confirmation_token_hash VARCHAR(64),
+    confirmation_expires_at TIMESTAMP WITH TIME ZONE,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    scheduled_for TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    purged_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_user_id ON astroneko_account_deletions(user_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_status_scheduled_for ON astroneko_account_deletions(status, scheduled_for);
+
+CREATE TABLE IF NOT EXISTS astroneko_account_deletion_audit (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    deletion_request_id UUID NOT NULL REFERENCES astroneko_account_deletions(id),
+    user_id UUID NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletion_audit_request_id ON astroneko_account_deletion_audit(deletion_request_id);
@@ -0,0 +1,20 @@
+-- Migration: Create data exports table
+-- Description: Queue of personal data export jobs. The finished ZIP is kept in the archive column until expires_at.
+
+CREATE TABLE IF NOT EXISTS astroneko_data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES astroneko_auth_users(id) ON DELETE CASCADE,
+    status VARCHAR(32) NOT NULL,
+    archive BYTEA,
+    archive_size BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON astroneko_data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON astroneko_data_exports(status, created_at);
@@ -0,0 +1,19 @@
+-- Migration: Create audit logs table
+-- Description: Append-only record of administrative changes made from the CRM, with the actor, the changed fields and where the request came from.
+
+CREATE TABLE IF NOT EXISTS astroneko_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_type VARCHAR(32) NOT NULL,
+    actor_id VARCHAR(64),
+    actor_name VARCHAR(100),
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    changes TEXT,
+    ip_address VARCHAR(64),
+    request_id VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON astroneko_audit_logs(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON astroneko_audit_logs(created_at);
@@ -0,0 +1,50 @@
+-- Migration: Create CRM roles and permissions
+-- Description: Adds roles, the permissions each role grants and role assignments for CRM users.
+-- Existing CRM users get no roles. Use POST /v1/api/crm/bootstrap with the configured crm.bootstrap_token
+-- to promote the first admin, who can then assign roles to everyone else.
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_roles (
+    name VARCHAR(32) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_role_permissions (
+    role VARCHAR(32) NOT NULL REFERENCES astroneko_crm_roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_user_roles (
+    crm_user_id UUID NOT NULL REFERENCES astroneko_crm_users(id) ON DELETE CASCADE,
+    role VARCHAR(32) NOT NULL REFERENCES astroneko_crm_roles(name),
+    granted_by UUID REFERENCES astroneko_crm_users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (crm_user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_crm_user_roles_role ON astroneko_crm_user_roles(role);
+
+INSERT INTO astroneko_crm_roles (name, description) VALUES
+    ('viewer', 'Read-only access to app users and referral codes'),
+    ('support', 'Helps app users: edits accounts and usage limits'),
+    ('marketing', 'Manages referral codes'),
+    ('admin', 'Full access, including CRM user and role management')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO astroneko_crm_role_permissions (role, permission) VALUES
+    ('viewer', 'app_users:read'),
+    ('viewer', 'referral_codes:read'),
+    ('support', 'app_users:read'),
+    ('support', 'app_users:write'),
+    ('support', 'user_limit:write'),
+    ('support', 'referral_codes:read'),
+    ('marketing', 'app_users:read'),
+    ('marketing', 'referral_codes:read'),
+    ('marketing', 'referral_codes:write'),
+    ('admin', 'app_users:read'),
+    ('admin', 'app_users:write'),
+    ('admin', 'user_limit:write'),
+    ('admin', 'referral_codes:read'),
+    ('admin', 'referral_codes:write'),
+    ('admin', 'crm_users:manage')
+ON CONFLICT (role, permission) DO NOTHING;
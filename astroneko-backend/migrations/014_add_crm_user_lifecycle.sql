@@ -0,0 +1,21 @@
+-- Migration: CRM user lifecycle
+-- Description: Lets admins disable CRM users and issue one-time password reset tokens.
+-- session_version is embedded in every CRM token; bumping it on password change or disable revokes older tokens.
+
+ALTER TABLE astroneko_crm_users
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS session_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_password_resets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    crm_user_id UUID NOT NULL REFERENCES astroneko_crm_users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES astroneko_crm_users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_crm_password_resets_token_hash ON astroneko_crm_password_resets(token_hash);
+CREATE INDEX IF NOT EXISTS idx_crm_password_resets_user ON astroneko_crm_password_resets(crm_user_id);
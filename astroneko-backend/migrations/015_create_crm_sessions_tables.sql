@@ -0,0 +1,28 @@
+-- Migration: CRM sessions
+-- Description: Rotating refresh tokens and the access token denylist behind CRM logout.
+-- Only SHA-256 hashes of refresh tokens are stored. Denylist rows can be deleted once expires_at has passed.
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    crm_user_id UUID NOT NULL REFERENCES astroneko_crm_users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    session_version INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_crm_refresh_tokens_token_hash ON astroneko_crm_refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_crm_refresh_tokens_user ON astroneko_crm_refresh_tokens(crm_user_id);
+CREATE INDEX IF NOT EXISTS idx_crm_refresh_tokens_family ON astroneko_crm_refresh_tokens(family_id);
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    crm_user_id UUID NOT NULL REFERENCES astroneko_crm_users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_crm_revoked_access_tokens_expires_at ON astroneko_crm_revoked_access_tokens(expires_at);
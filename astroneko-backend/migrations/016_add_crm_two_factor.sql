@@ -0,0 +1,18 @@
+-- Migration: CRM two-factor authentication
+-- Description: TOTP (RFC 6238) secrets on CRM users and their single-use recovery codes.
+-- totp_last_step holds the time step of the last accepted code so a code cannot be replayed.
+
+ALTER TABLE astroneko_crm_users
+    ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS astroneko_crm_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    crm_user_id UUID NOT NULL REFERENCES astroneko_crm_users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_crm_recovery_codes_user ON astroneko_crm_recovery_codes(crm_user_id);
@@ -0,0 +1,19 @@
+-- Migration: Credential attempt counters
+-- Description: Consecutive failed logins and referral code checks per account and per client IP,
+-- behind the backoff and lockout on credential endpoints. Keys are SHA-256 hashes.
+-- Rows with no failure in the last hour that are not locked can be deleted at any time.
+
+CREATE TABLE IF NOT EXISTS astroneko_credential_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope VARCHAR(32) NOT NULL,
+    key_type VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credential_attempts_key ON astroneko_credential_attempts(scope, key_type, key_hash);
+CREATE INDEX IF NOT EXISTS idx_credential_attempts_last_failure_at ON astroneko_credential_attempts(last_failure_at);
@@ -0,0 +1,44 @@
+-- Migration: Hash chain over the audit log
+-- Description: Numbers audit entries and stores a SHA-256 over each entry and the hash of the one before it,
+-- so that an edited or removed entry breaks the chain (GET /v1/api/crm/audit-log/verify).
+-- Existing entries keep an empty hash and are skipped by verification; the chain starts at the next entry.
+-- Triggers reject UPDATE, DELETE and TRUNCATE so the table can only be appended to.
+
+ALTER TABLE astroneko_audit_logs
+    ADD COLUMN IF NOT EXISTS sequence BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS hash VARCHAR(64);
+
+UPDATE astroneko_audit_logs AS logs
+SET sequence = numbered.sequence
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS sequence
+    FROM astroneko_audit_logs
+) AS numbered
+WHERE logs.id = numbered.id AND logs.sequence IS NULL;
+
+ALTER TABLE astroneko_audit_logs ALTER COLUMN sequence SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_sequence ON astroneko_audit_logs(sequence);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON astroneko_audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON astroneko_audit_logs(action);
+
+CREATE OR REPLACE FUNCTION astroneko_audit_logs_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'astroneko_audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_update_delete ON astroneko_audit_logs;
+CREATE TRIGGER trg_audit_logs_no_update_delete
+    BEFORE UPDATE OR DELETE ON astroneko_audit_logs
+    FOR EACH ROW EXECUTE FUNCTION astroneko_audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_truncate ON astroneko_audit_logs;
+CREATE TRIGGER trg_audit_logs_no_truncate
+    BEFORE TRUNCATE ON astroneko_audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION astroneko_audit_logs_append_only();
+
+INSERT INTO astroneko_crm_role_permissions (role, permission) VALUES
+    ('admin', 'audit_log:read')
+ON CONFLICT (role, permission) DO NOTHING;
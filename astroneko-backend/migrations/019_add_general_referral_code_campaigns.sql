@@ -0,0 +1,40 @@
+-- Migration: Campaign settings for general referral codes
+-- Description: General codes get an optional redemption window and cap, campaign metadata and a
+-- grant (unlimited access, a tier or bonus quota). Users record the tier and bonus quota they were
+-- granted. Existing codes keep granting unlimited access, and users who already activated one are
+-- backfilled to the unlimited tier.
+
+ALTER TABLE astroneko_general_referral_codes
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS ends_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS max_redemptions INTEGER,
+    ADD COLUMN IF NOT EXISTS campaign VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS channel VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS notes TEXT,
+    ADD COLUMN IF NOT EXISTS grant_type VARCHAR(32) NOT NULL DEFAULT 'unlimited',
+    ADD COLUMN IF NOT EXISTS grant_tier VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS bonus_quota INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE astroneko_general_referral_codes
+    ADD CONSTRAINT chk_general_referral_codes_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at),
+    ADD CONSTRAINT chk_general_referral_codes_max_redemptions CHECK (max_redemptions IS NULL OR max_redemptions > 0),
+    ADD CONSTRAINT chk_general_referral_codes_grant CHECK (
+        grant_type = 'unlimited'
+        OR (grant_type = 'tier' AND grant_tier IS NOT NULL)
+        OR (grant_type = 'bonus_quota' AND bonus_quota > 0)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_general_referral_codes_campaign ON astroneko_general_referral_codes(campaign);
+
+ALTER TABLE astroneko_auth_users
+    ADD COLUMN IF NOT EXISTS referral_tier VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS bonus_quota INTEGER NOT NULL DEFAULT 0;
+
+UPDATE astroneko_auth_users SET referral_tier = 'unlimited' WHERE is_activated_referral = true AND referral_tier IS NULL;
+
+-- Logs of general code redemptions point at general codes, which the foreign key from
+-- migration 006 to astroneko_user_referral_codes rejected
+ALTER TABLE astroneko_referral_logs DROP CONSTRAINT IF EXISTS astroneko_referral_logs_referral_code_id_fkey;
+
+-- Redemptions are counted per code inside activation
+CREATE INDEX IF NOT EXISTS idx_referral_logs_referral_code_id_code_type ON astroneko_referral_logs(referral_code_id, code_type);
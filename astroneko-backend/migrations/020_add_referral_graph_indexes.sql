@@ -0,0 +1,6 @@
+-- Migration: Referral graph indexes
+-- Description: The referral graph walks from code owners to the users who redeemed their codes,
+-- one tree level per query, and the CRM leaderboard aggregates those redemptions by date.
+
+CREATE INDEX IF NOT EXISTS idx_user_referral_codes_user_id ON astroneko_user_referral_codes(user_id);
+CREATE INDEX IF NOT EXISTS idx_referral_logs_user_code_created_at ON astroneko_referral_logs(created_at) WHERE code_type = 'user';
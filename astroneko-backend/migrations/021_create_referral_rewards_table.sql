@@ -0,0 +1,22 @@
+-- Migration: Referral rewards ledger
+-- Description: Inviters are credited when one of their user referral codes is redeemed. Each
+-- redemption credits a reward type at most once and each badge is earned once per user, so
+-- retried activations never double-credit. Users keep a running total of the daily replies earned.
+
+CREATE TABLE IF NOT EXISTS astroneko_referral_rewards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES astroneko_auth_users(id) ON DELETE CASCADE,
+    referral_log_id UUID NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    amount INTEGER NOT NULL DEFAULT 0,
+    badge VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_referral_rewards_badge CHECK ((type = 'badge') = (badge IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_rewards_log_type ON astroneko_referral_rewards(referral_log_id, type) WHERE type <> 'badge';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_rewards_user_badge ON astroneko_referral_rewards(user_id, badge) WHERE type = 'badge';
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_user_created_at ON astroneko_referral_rewards(user_id, created_at DESC);
+
+ALTER TABLE astroneko_auth_users
+    ADD COLUMN IF NOT EXISTS reward_daily_replies INTEGER NOT NULL DEFAULT 0;
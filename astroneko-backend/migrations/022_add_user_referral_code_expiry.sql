@@ -0,0 +1,9 @@
+-- Migration: User referral code allotment
+-- Description: User codes can expire, and a unique index on the lowercased code replaces the
+-- look-up-then-insert loop, so code generation retries on conflict instead. Codes have always
+-- been looked up case-insensitively, so existing codes are already unique under this index.
+
+ALTER TABLE astroneko_user_referral_codes
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_referral_codes_code_lower ON astroneko_user_referral_codes(LOWER(referral_code));
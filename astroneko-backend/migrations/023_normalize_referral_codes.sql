@@ -0,0 +1,68 @@
+-- Migration: Normalized referral codes and vanity codes
+-- Description: Codes are now stored normalized (NFKC, whitespace and invisible characters
+-- removed, upper case) and looked up the same way. Codes that would become equal under the
+-- normalization are listed in astroneko_referral_code_collisions and left as they are for an
+-- operator to rename; every other code is normalized in place. General codes get the same
+-- unique index on the lowercased code that user codes already have, unless that table still
+-- holds colliding codes. User codes gain the vanity flag. The SQL expression below matches
+-- referral_code.NormalizeCode for the characters codes are made of in practice.
+
+CREATE TABLE IF NOT EXISTS astroneko_referral_code_collisions (
+    id SERIAL PRIMARY KEY,
+    normalized_code VARCHAR(255) NOT NULL,
+    source_table VARCHAR(16) NOT NULL CHECK (source_table IN ('general', 'user')),
+    code_id UUID NOT NULL,
+    referral_code VARCHAR(255) NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source_table, code_id)
+);
+
+DO $$
+DECLARE
+    collisions INTEGER;
+BEGIN
+    WITH all_codes AS (
+        SELECT 'general' AS source_table, id, referral_code,
+               UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g')) AS normalized_code
+        FROM astroneko_general_referral_codes
+        UNION ALL
+        SELECT 'user', id, referral_code,
+               UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'))
+        FROM astroneko_user_referral_codes
+    ), colliding AS (
+        SELECT normalized_code FROM all_codes GROUP BY normalized_code HAVING COUNT(*) > 1
+    )
+    INSERT INTO astroneko_referral_code_collisions (normalized_code, source_table, code_id, referral_code)
+    SELECT all_codes.normalized_code, all_codes.source_table, all_codes.id, all_codes.referral_code
+    FROM all_codes JOIN colliding USING (normalized_code)
+    ON CONFLICT (source_table, code_id) DO NOTHING;
+
+    GET DIAGNOSTICS collisions = ROW_COUNT;
+    IF collisions > 0 THEN
+        RAISE NOTICE '% referral codes collide under normalization, see astroneko_referral_code_collisions', collisions;
+    END IF;
+END $$;
+
+UPDATE astroneko_general_referral_codes
+SET referral_code = UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'))
+WHERE id NOT IN (SELECT code_id FROM astroneko_referral_code_collisions WHERE source_table = 'general')
+  AND referral_code <> UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'));
+
+UPDATE astroneko_user_referral_codes
+SET referral_code = UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'))
+WHERE id NOT IN (SELECT code_id FROM astroneko_referral_code_collisions WHERE source_table = 'user')
+  AND referral_code <> UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'));
+
+ALTER TABLE astroneko_user_referral_codes
+    ADD COLUMN IF NOT EXISTS is_vanity BOOLEAN NOT NULL DEFAULT FALSE;
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM astroneko_general_referral_codes GROUP BY LOWER(referral_code) HAVING COUNT(*) > 1
+    ) THEN
+        RAISE WARNING 'general referral codes collide case-insensitively; rename them, then create idx_general_referral_codes_code_lower by hand';
+    ELSE
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_general_referral_codes_code_lower ON astroneko_general_referral_codes(LOWER(referral_code));
+    END IF;
+END $$;
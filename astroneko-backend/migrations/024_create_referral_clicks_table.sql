@@ -0,0 +1,28 @@
+-- Migration: Referral link clicks
+-- Description: Every visit to a referral deep link (/r/{code}) is stored with its UTM parameters.
+-- A click is claimed by the user who followed it, which marks a signup when their account is
+-- newer than the click, and is attributed to the referral log of their redemption of the same
+-- code. A redemption is attributed to at most one click.
+
+CREATE TABLE IF NOT EXISTS astroneko_referral_clicks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code_type VARCHAR(16) NOT NULL CHECK (code_type IN ('general', 'user')),
+    referral_code_id UUID NOT NULL,
+    utm_source VARCHAR(255),
+    utm_medium VARCHAR(255),
+    utm_campaign VARCHAR(255),
+    utm_term VARCHAR(255),
+    utm_content VARCHAR(255),
+    referrer VARCHAR(512),
+    user_agent VARCHAR(512),
+    user_id UUID REFERENCES astroneko_auth_users(id) ON DELETE SET NULL,
+    is_new_user BOOLEAN NOT NULL DEFAULT FALSE,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    referral_log_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_clicks_code_created_at ON astroneko_referral_clicks(code_type, referral_code_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_referral_clicks_created_at ON astroneko_referral_clicks(created_at);
+CREATE INDEX IF NOT EXISTS idx_referral_clicks_user_id ON astroneko_referral_clicks(user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_clicks_referral_log_id ON astroneko_referral_clicks(referral_log_id) WHERE referral_log_id IS NOT NULL;
@@ -0,0 +1,66 @@
+-- Migration: Multi-list waiting lists
+-- Description: Waiting lists become rows of astroneko_waiting_lists, addressed by slug, each with
+-- its own open state, capacity and required join fields. Every entry of astroneko_waiting_list_users
+-- belongs to a list and an email can join each list once. Existing entries move to the 'astroneko'
+-- list, and the entries of astro_boxing_waiting_list_users are copied into the 'astro-boxing' list.
+-- That table is no longer read or written and is left in place to be dropped later.
+
+CREATE TABLE IF NOT EXISTS astroneko_waiting_lists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    is_open BOOLEAN NOT NULL DEFAULT TRUE,
+    capacity INTEGER CHECK (capacity IS NULL OR capacity > 0),
+    required_fields JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO astroneko_waiting_lists (slug, name) VALUES
+    ('astroneko', 'Astroneko'),
+    ('astro-boxing', 'Astro Boxing')
+ON CONFLICT (slug) DO NOTHING;
+
+ALTER TABLE astroneko_waiting_list_users
+    ADD COLUMN IF NOT EXISTS list_id UUID REFERENCES astroneko_waiting_lists(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS fields JSONB;
+
+UPDATE astroneko_waiting_list_users
+SET list_id = (SELECT id FROM astroneko_waiting_lists WHERE slug = 'astroneko')
+WHERE list_id IS NULL;
+
+DO $$
+BEGIN
+    IF to_regclass('astro_boxing_waiting_list_users') IS NOT NULL THEN
+        INSERT INTO astroneko_waiting_list_users (id, list_id, email, created_at, updated_at)
+        SELECT a.id, l.id, TRIM(a.email), a.created_at, a.updated_at
+        FROM astro_boxing_waiting_list_users a
+        CROSS JOIN astroneko_waiting_lists l
+        WHERE l.slug = 'astro-boxing'
+        ON CONFLICT (id) DO NOTHING;
+    END IF;
+END $$;
+
+-- Neither old table enforced unique emails: keep the earliest entry of each email per list
+DELETE FROM astroneko_waiting_list_users w
+USING astroneko_waiting_list_users earlier
+WHERE earlier.list_id = w.list_id
+  AND LOWER(TRIM(earlier.email)) = LOWER(TRIM(w.email))
+  AND (earlier.created_at, earlier.id) < (w.created_at, w.id);
+
+UPDATE astroneko_waiting_list_users SET email = TRIM(email) WHERE email <> TRIM(email);
+
+ALTER TABLE astroneko_waiting_list_users ALTER COLUMN list_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_astroneko_waiting_list_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_astroneko_waiting_list_users_list_email ON astroneko_waiting_list_users(list_id, LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_astroneko_waiting_list_users_list_created_at ON astroneko_waiting_list_users(list_id, created_at);
+
+INSERT INTO astroneko_crm_role_permissions (role, permission) VALUES
+    ('viewer', 'waiting_lists:read'),
+    ('support', 'waiting_lists:read'),
+    ('marketing', 'waiting_lists:read'),
+    ('marketing', 'waiting_lists:write'),
+    ('admin', 'waiting_lists:read'),
+    ('admin', 'waiting_lists:write')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,45 @@
+-- Migration: Waiting list queue positions and invite links
+-- Description: Entries are queued by queue_number, their order of joining within the list. Each
+-- entry has an invite code; people who join through its invite link credit it with a referral,
+-- and every referral moves it up referral_boost places. Referrals from the referrer's own device,
+-- or from a device already credited to the referrer, are not credited: fingerprint holds a hash
+-- of the joiner's IP address and browser to tell. Existing entries are numbered in joining order
+-- and get an invite code derived from their ID.
+
+ALTER TABLE astroneko_waiting_lists
+    ADD COLUMN IF NOT EXISTS referral_boost INTEGER NOT NULL DEFAULT 5 CHECK (referral_boost BETWEEN 0 AND 1000),
+    ADD COLUMN IF NOT EXISTS landing_url VARCHAR(512) NOT NULL DEFAULT '';
+
+ALTER TABLE astroneko_waiting_list_users
+    ADD COLUMN IF NOT EXISTS queue_number BIGINT,
+    ADD COLUMN IF NOT EXISTS invite_code VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS referred_by_id UUID REFERENCES astroneko_waiting_list_users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS referral_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE astroneko_waiting_list_users w
+SET queue_number = numbered.queue_number
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY created_at, id) AS queue_number
+    FROM astroneko_waiting_list_users
+) numbered
+WHERE w.id = numbered.id AND w.queue_number IS NULL;
+
+UPDATE astroneko_waiting_list_users
+SET invite_code = UPPER(SUBSTR(REPLACE(id::text, '-', ''), 1, 12))
+WHERE invite_code IS NULL;
+
+-- The rare entries whose IDs share the first twelve digits fall back to the whole ID
+UPDATE astroneko_waiting_list_users
+SET invite_code = UPPER(REPLACE(id::text, '-', ''))
+WHERE invite_code IN (
+    SELECT invite_code FROM astroneko_waiting_list_users GROUP BY invite_code HAVING COUNT(*) > 1
+);
+
+ALTER TABLE astroneko_waiting_list_users
+    ALTER COLUMN queue_number SET NOT NULL,
+    ALTER COLUMN invite_code SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_astroneko_waiting_list_users_invite_code ON astroneko_waiting_list_users(invite_code);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_astroneko_waiting_list_users_list_queue_number ON astroneko_waiting_list_users(list_id, queue_number);
+CREATE INDEX IF NOT EXISTS idx_astroneko_waiting_list_users_referred_by ON astroneko_waiting_list_users(referred_by_id, fingerprint) WHERE referred_by_id IS NOT NULL;
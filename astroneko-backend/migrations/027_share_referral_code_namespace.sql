@@ -0,0 +1,93 @@
+-- Migration: One namespace for general and user referral codes
+-- Description: Redemption looks a code up in both the general and the user code tables, so a
+-- code must be unique across the two. Each code now holds a row in
+-- astroneko_referral_code_namespace, keyed by its lowercased code, which triggers on both tables
+-- keep in step; a code taken by the other table fails with a unique violation. The migration
+-- fails while codes left behind by 023_normalize_referral_codes are still not normalized or
+-- still collide: rename them (see astroneko_referral_code_collisions) and run it again.
+
+DO $$
+DECLARE
+    unresolved INTEGER;
+BEGIN
+    WITH all_codes AS (
+        SELECT referral_code,
+               UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g')) AS normalized_code
+        FROM astroneko_general_referral_codes
+        UNION ALL
+        SELECT referral_code,
+               UPPER(regexp_replace(normalize(referral_code, NFKC), '[[:space:]\u200B-\u200F\u2060\uFEFF]', '', 'g'))
+        FROM astroneko_user_referral_codes
+    )
+    SELECT COUNT(*) INTO unresolved FROM (
+        SELECT normalized_code FROM all_codes
+        GROUP BY normalized_code
+        HAVING COUNT(*) > 1 OR bool_or(referral_code <> normalized_code)
+    ) pending;
+
+    IF unresolved > 0 THEN
+        RAISE EXCEPTION '% referral codes are not normalized or collide under normalization, rename the codes listed in astroneko_referral_code_collisions and run the migration again', unresolved;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_general_referral_codes_code_lower ON astroneko_general_referral_codes(LOWER(referral_code));
+
+CREATE TABLE IF NOT EXISTS astroneko_referral_code_namespace (
+    code VARCHAR(255) PRIMARY KEY,
+    source_table VARCHAR(16) NOT NULL CHECK (source_table IN ('general', 'user')),
+    code_id UUID NOT NULL,
+    UNIQUE (source_table, code_id)
+);
+
+INSERT INTO astroneko_referral_code_namespace (code, source_table, code_id)
+SELECT LOWER(referral_code), 'general', id FROM astroneko_general_referral_codes
+UNION ALL
+SELECT LOWER(referral_code), 'user', id FROM astroneko_user_referral_codes
+ON CONFLICT DO NOTHING;
+
+-- astroneko_reserve_referral_code keeps the namespace in step with the table named by its
+-- argument. A code already reserved for the same row, as CreateUserReferralCodeIfAbsent does
+-- before inserting, is accepted.
+CREATE OR REPLACE FUNCTION astroneko_reserve_referral_code() RETURNS TRIGGER AS $$
+DECLARE
+    holder_table VARCHAR(16);
+    holder_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM astroneko_referral_code_namespace WHERE source_table = TG_ARGV[0] AND code_id = OLD.id;
+        RETURN OLD;
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        IF LOWER(NEW.referral_code) = LOWER(OLD.referral_code) THEN
+            RETURN NEW;
+        END IF;
+        DELETE FROM astroneko_referral_code_namespace WHERE source_table = TG_ARGV[0] AND code_id = OLD.id;
+    END IF;
+
+    INSERT INTO astroneko_referral_code_namespace (code, source_table, code_id)
+    VALUES (LOWER(NEW.referral_code), TG_ARGV[0], NEW.id)
+    ON CONFLICT DO NOTHING;
+
+    IF NOT FOUND THEN
+        SELECT source_table, code_id INTO holder_table, holder_id
+        FROM astroneko_referral_code_namespace WHERE code = LOWER(NEW.referral_code);
+        IF holder_table IS DISTINCT FROM TG_ARGV[0] OR holder_id IS DISTINCT FROM NEW.id THEN
+            RAISE EXCEPTION 'referral code % is already taken', NEW.referral_code
+                USING ERRCODE = 'unique_violation', CONSTRAINT = 'astroneko_referral_code_namespace_pkey';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_general_referral_codes_namespace ON astroneko_general_referral_codes;
+CREATE TRIGGER trg_general_referral_codes_namespace
+    BEFORE INSERT OR UPDATE OF referral_code OR DELETE ON astroneko_general_referral_codes
+    FOR EACH ROW EXECUTE FUNCTION astroneko_reserve_referral_code('general');
+
+DROP TRIGGER IF EXISTS trg_user_referral_codes_namespace ON astroneko_user_referral_codes;
+CREATE TRIGGER trg_user_referral_codes_namespace
+    BEFORE INSERT OR UPDATE OF referral_code OR DELETE ON astroneko_user_referral_codes
+    FOR EACH ROW EXECUTE FUNCTION astroneko_reserve_referral_code('user');
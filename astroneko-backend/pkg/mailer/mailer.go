@@ -0,0 +1,176 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"astroneko-backend/pkg/logger"
+)
+
+// dialTimeout bounds connecting to the SMTP server
+const dialTimeout = 10 * time.Second
+
+// Message is a plain text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig is the SMTP server to send through. Username may be empty for servers that
+// do not require authentication.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Validate checks that the server and sender address are usable
+func (c SMTPConfig) Validate() error {
+	if c.Host == "" || c.Port <= 0 {
+		return errors.New("host and port are required")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("from is not a valid address: %w", err)
+	}
+	return nil
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+// NewSMTPMailer sends through the configured server, upgrading to TLS when it offers STARTTLS
+func NewSMTPMailer(config SMTPConfig) (Mailer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	from, _ := mail.ParseAddress(config.From)
+	return &smtpMailer{config: config, from: from}, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.New("subject must be a single line")
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	var body strings.Builder
+	body.WriteString("From: " + m.from.String() + "\r\n")
+	body.WriteString("To: " + to.String() + "\r\n")
+	body.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	body.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+	// Close the connection when the context ends, so a stalled server cannot hold the send
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	if err := m.send(conn, auth, to.Address, body.String()); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The connection deadline is the context's, and can fire just before ctx.Err is set
+		if _, ok := ctx.Deadline(); ok && errors.Is(err, os.ErrDeadlineExceeded) {
+			return context.DeadlineExceeded
+		}
+		return err
+	}
+	return nil
+}
+
+// send runs the SMTP conversation over conn, as smtp.SendMail does over its own connection
+func (m *smtpMailer) send(conn net.Conn, auth smtp.Auth, to, body string) error {
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+type logMailer struct {
+	logger logger.Logger
+}
+
+// NewLogMailer logs who an email was for instead of sending it, for environments without
+// an SMTP server. The body is not logged since it may hold links that act as credentials.
+func NewLogMailer(log logger.Logger) Mailer {
+	return &logMailer{logger: log}
+}
+
+func (m *logMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Warn("Mail is not configured, email not sent",
+		logger.Field{Key: "module", Value: "mailer"},
+		logger.Field{Key: "to", Value: msg.To},
+		logger.Field{Key: "subject", Value: msg.Subject})
+	return nil
+}
@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPMailer_Send_StalledServer(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		// Accept and never greet, like a server that has stopped responding
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	m, err := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@astroneko.com"})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	err = m.Send(ctx, Message{To: "fan@example.com", Subject: "Hello", Body: "Hi"})
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "the send must give up when the context ends")
+}
+
+func TestSMTPMailer_Send_Unreachable(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	m, err := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@astroneko.com"})
+	require.NoError(t, err)
+
+	// Act
+	err = m.Send(context.Background(), Message{To: "fan@example.com", Subject: "Hello", Body: "Hi"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"astroneko-backend/internal/core/domain/user"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthorizationTestApp(u *user.User, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		if u != nil {
+			c.Locals("user", u)
+		}
+		return c.Next()
+	}, guard, func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+	return app
+}
+
+func TestAuthorizer_RequireSelf(t *testing.T) {
+	u := &user.User{}
+	u.ID = uuid.New()
+	authorizer := NewAuthorizer(&MockLogger{})
+
+	tests := []struct {
+		name       string
+		user       *user.User
+		path       string
+		wantStatus int
+	}{
+		{"own record", u, "/users/" + u.ID.String(), 200},
+		{"another user's record", u, "/users/" + uuid.New().String(), 403},
+		{"unauthenticated", nil, "/users/" + u.ID.String(), 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newAuthorizationTestApp(tt.user, authorizer.RequireSelf("id"))
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAuthorizer_RequireOwnership_CheckFails(t *testing.T) {
+	u := &user.User{}
+	u.ID = uuid.New()
+	authorizer := NewAuthorizer(&MockLogger{})
+	failing := func(_ context.Context, _ *user.User, _ string) (bool, error) {
+		return false, errors.New("database unavailable")
+	}
+	app := newAuthorizationTestApp(u, authorizer.RequireOwnership("id", failing))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/some-resource", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
+	"astroneko-backend/internal/core/domain/crm_user"
 	"astroneko-backend/internal/core/domain/shared"
 	"astroneko-backend/internal/services"
 	"astroneko-backend/pkg/logger"
@@ -25,41 +27,66 @@ func NewCRMAuthMiddleware(crmUserService *services.CRMUserService, logger logger
 func (m *CRMAuthMiddleware) RequireAuth(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		status, response := shared.NewErrorResponse("ERR_401", "Authorization header required")
+		status, response := shared.NewErrorResponse("ERR_1014", "Authorization header required")
 		return c.Status(status).JSON(response)
 	}
 
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		status, response := shared.NewErrorResponse("ERR_401", "Invalid authorization header format")
+		status, response := shared.NewErrorResponse("ERR_1014", "Invalid authorization header format")
 		return c.Status(status).JSON(response)
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 	if token == "" {
-		status, response := shared.NewErrorResponse("ERR_401", "Token required")
+		status, response := shared.NewErrorResponse("ERR_1014", "Token required")
 		return c.Status(status).JSON(response)
 	}
 
-	claims, err := m.crmUserService.ValidateToken(token)
+	user, claims, err := m.crmUserService.Authenticate(c.Context(), token)
 	if err != nil {
-		m.logger.Error("Failed to validate CRM token", logger.Field{Key: "error", Value: err.Error()})
-		status, response := shared.NewErrorResponse("ERR_401", "Invalid or expired token")
-		return c.Status(status).JSON(response)
-	}
-
-	// Get full user details
-	user, err := m.crmUserService.GetUserByID(c.Context(), claims.UserID.String())
-	if err != nil {
-		m.logger.Error("Failed to get CRM user",
-			logger.Field{Key: "error", Value: err.Error()},
-			logger.Field{Key: "user_id", Value: claims.UserID.String()})
-		status, response := shared.NewErrorResponse("ERR_401", "User not found")
+		m.logger.Error("Failed to authenticate CRM token", logger.Field{Key: "error", Value: err.Error()})
+		if errors.Is(err, shared.ErrCRMUserDisabled) {
+			status, response := shared.NewErrorResponse("ERR_1041")
+			return c.Status(status).JSON(response)
+		}
+		status, response := shared.NewErrorResponse("ERR_1014", "Invalid or expired token")
 		return c.Status(status).JSON(response)
 	}
 
 	// Store user in context for handlers to use
 	c.Locals("crm_user", user)
 	c.Locals("crm_user_id", claims.UserID.String())
+	c.Locals("crm_claims", claims)
 
 	return c.Next()
 }
+
+// RequirePermission allows the request only when the caller holds every listed permission.
+// A permission must be both in the token and still granted by the user's current roles,
+// so a revoked role takes effect at once while a new role needs a fresh login.
+// It runs after RequireAuth.
+func (m *CRMAuthMiddleware) RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, _ := c.Locals("crm_claims").(*crm_user.JWTClaims)
+		user, _ := c.Locals("crm_user").(*crm_user.CRMUser)
+		if claims == nil || user == nil {
+			status, response := shared.NewErrorResponse("ERR_1014", "Authentication required")
+			return c.Status(status).JSON(response)
+		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) || !user.HasPermission(permission) {
+				m.logger.Warn("CRM user lacks permission",
+					logger.Field{Key: "module", Value: "crm_auth"},
+					logger.Field{Key: "crm_user_id", Value: user.ID.String()},
+					logger.Field{Key: "permission", Value: permission},
+					logger.Field{Key: "method", Value: c.Method()},
+					logger.Field{Key: "path", Value: c.Path()})
+				status, response := shared.NewErrorResponse("ERR_1037")
+				return c.Status(status).JSON(response)
+			}
+		}
+
+		return c.Next()
+	}
+}
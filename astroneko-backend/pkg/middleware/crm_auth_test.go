@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"astroneko-backend/internal/core/domain/crm_user"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRMAuthMiddleware_RequirePermission(t *testing.T) {
+	manage := []string{crm_user.PermissionCRMUsersManage}
+	readOnly := []string{crm_user.PermissionAppUsersRead}
+
+	tests := []struct {
+		name             string
+		tokenPermissions []string
+		userPermissions  []string
+		authenticated    bool
+		wantStatus       int
+	}{
+		{"granted by token and current roles", manage, manage, true, 200},
+		{"missing from token", readOnly, manage, true, 403},
+		{"role revoked since login", manage, readOnly, true, 403},
+		{"not authenticated", nil, nil, false, 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewCRMAuthMiddleware(nil, &MockLogger{})
+			app := fiber.New()
+			app.Get("/test", func(c *fiber.Ctx) error {
+				if tt.authenticated {
+					u := &crm_user.CRMUser{Username: "staff", Permissions: tt.userPermissions}
+					u.ID = uuid.New()
+					c.Locals("crm_user", u)
+					c.Locals("crm_claims", &crm_user.JWTClaims{UserID: u.ID, Permissions: tt.tokenPermissions})
+				}
+				return c.Next()
+			}, m.RequirePermission(crm_user.PermissionCRMUsersManage), func(c *fiber.Ctx) error {
+				return c.SendString("success")
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
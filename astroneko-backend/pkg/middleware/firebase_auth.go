@@ -83,6 +83,16 @@ func (m *AuthMiddleware) setUserContext(c *fiber.Ctx, user *user.User, token *au
 
 // RequireAuth middleware validates Firebase ID token and adds user data to context
 func (m *AuthMiddleware) RequireAuth(c *fiber.Ctx) error {
+	return m.requireAuth(c, false)
+}
+
+// RequireAuthWithReferralCheck works like RequireAuth and also sets user_type the way
+// OptionalAuthWithReferralCheck does, so agent API limits apply without verifying the token twice
+func (m *AuthMiddleware) RequireAuthWithReferralCheck(c *fiber.Ctx) error {
+	return m.requireAuth(c, true)
+}
+
+func (m *AuthMiddleware) requireAuth(c *fiber.Ctx, referralCheck bool) error {
 	if m.firebaseApp == nil {
 		m.logger.Error("Firebase not configured in middleware", logger.Field{Key: "module", Value: ModuleName})
 		status, response := shared.NewErrorResponse("ERR_1001", "Firebase not configured")
@@ -115,6 +125,13 @@ func (m *AuthMiddleware) RequireAuth(c *fiber.Ctx) error {
 	}
 
 	m.setUserContext(c, user, token)
+	if referralCheck {
+		if user.HasUnlimitedAccess() {
+			c.Locals("user_type", "logged_in_with_referral")
+		} else {
+			c.Locals("user_type", "logged_in_no_referral")
+		}
+	}
 	return c.Next()
 }
 
@@ -182,8 +199,8 @@ func (m *AuthMiddleware) OptionalAuthWithReferralCheck(c *fiber.Ctx) error {
 		return c.Next() // Continue without user data
 	}
 
-	// CRITICAL: Check if referral is activated for agent API access
-	if !user.IsActivatedReferral {
+	// CRITICAL: Only a referral that granted unlimited access skips the agent API limits
+	if !user.HasUnlimitedAccess() {
 		m.logger.Info("Logged-in user without activated referral accessing agent API",
 			logger.Field{Key: "module", Value: ModuleName},
 			logger.Field{Key: "user_id", Value: user.ID.String()},
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"astroneko-backend/internal/core/domain/guest_usage"
+	"astroneko-backend/internal/core/domain/user"
 	guestUsagePort "astroneko-backend/internal/core/ports/guest_usage"
+	userPort "astroneko-backend/internal/core/ports/user"
 	"astroneko-backend/pkg/logger"
 	"astroneko-backend/pkg/utils"
 
@@ -20,19 +22,21 @@ const (
 
 type GuestRateLimitMiddleware struct {
 	guestRepo guestUsagePort.Repository
+	userRepo  userPort.RepositoryInterface
 	logger    logger.Logger
 }
 
-func NewGuestRateLimitMiddleware(repo guestUsagePort.Repository, log logger.Logger) *GuestRateLimitMiddleware {
+func NewGuestRateLimitMiddleware(repo guestUsagePort.Repository, userRepo userPort.RepositoryInterface, log logger.Logger) *GuestRateLimitMiddleware {
 	return &GuestRateLimitMiddleware{
 		guestRepo: repo,
+		userRepo:  userRepo,
 		logger:    log,
 	}
 }
 
 // GuestOrAuthRateLimit applies rate limiting based on user type:
 // - logged_in_with_referral: Unlimited access
-// - logged_in_no_referral: 3 requests per day (daily reset)
+// - logged_in_no_referral: 3 requests per day (daily reset), more on a limited tier, then bonus quota
 // - guest: 3 requests lifetime (no reset)
 func (m *GuestRateLimitMiddleware) GuestOrAuthRateLimit(endpoint string, guestLimit int) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -70,18 +74,25 @@ func (m *GuestRateLimitMiddleware) GuestOrAuthRateLimit(endpoint string, guestLi
 	}
 }
 
-// handleLoggedInUserRequest handles rate limiting for logged-in users without referral (3 per day with daily reset)
+// handleLoggedInUserRequest handles rate limiting for logged-in users without unlimited access
+// (3 per day with daily reset, or the daily limit of their tier). Once the day's requests are
+// used up, each further request spends one request of the user's bonus quota.
 func (m *GuestRateLimitMiddleware) handleLoggedInUserRequest(c *fiber.Ctx, endpoint string, limit int) error {
 	ctx := context.Background()
 
 	// Get user from context
-	user := c.Locals("user")
-	if user == nil {
+	userLocal := c.Locals("user")
+	if userLocal == nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "User context not found",
 		})
 	}
 
+	appUser, _ := userLocal.(*user.User)
+	if appUser != nil {
+		limit = appUser.DailyRequestLimit(limit)
+	}
+
 	// Use user ID as the composite key for logged-in users
 	userID := c.Locals("firebase_uid").(string)
 	compositeKey := "user_" + userID
@@ -138,8 +149,15 @@ func (m *GuestRateLimitMiddleware) handleLoggedInUserRequest(c *fiber.Ctx, endpo
 	// - If we're in a new day, GetByCompositeKey won't find the old record (different windowResetStr)
 	// - A new record will be created automatically above (usage == nil case)
 
+	// The tier may have changed since the record was created today
+	usage.DailyLimit = limit
+
 	// Check if limit exceeded
 	if !usage.CanMakeRequest() {
+		if m.consumeBonusQuota(ctx, c, appUser) {
+			return c.Next()
+		}
+
 		m.setRateLimitHeaders(c, usage)
 
 		resetIn := time.Until(usage.WindowResetAt)
@@ -152,9 +170,14 @@ func (m *GuestRateLimitMiddleware) handleLoggedInUserRequest(c *fiber.Ctx, endpo
 			logger.Field{Key: "usage_count", Value: usage.UsageCount},
 			logger.Field{Key: "daily_limit", Value: usage.DailyLimit})
 
+		message := "You've used all your free daily requests. Please activate a referral code for unlimited access or try again tomorrow."
+		if appUser != nil && appUser.IsActivatedReferral {
+			message = "You've used all your daily requests. Please try again tomorrow."
+		}
+
 		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
 			"error":       "Daily limit exceeded",
-			"message":     "You've used all your free daily requests. Please activate a referral code for unlimited access or try again tomorrow.",
+			"message":     message,
 			"used":        usage.UsageCount,
 			"limit":       usage.DailyLimit,
 			"reset_in":    resetIn.String(),
@@ -181,6 +204,31 @@ func (m *GuestRateLimitMiddleware) handleLoggedInUserRequest(c *fiber.Ctx, endpo
 	return c.Next()
 }
 
+// consumeBonusQuota spends one bonus request of the user, reporting whether one was left
+func (m *GuestRateLimitMiddleware) consumeBonusQuota(ctx context.Context, c *fiber.Ctx, appUser *user.User) bool {
+	if appUser == nil || appUser.BonusQuota <= 0 || m.userRepo == nil {
+		return false
+	}
+
+	consumed, err := m.userRepo.ConsumeBonusQuota(ctx, appUser.ID)
+	if err != nil {
+		m.logger.Error("Failed to consume bonus quota",
+			logger.Field{Key: "user_id", Value: appUser.ID.String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return false
+	}
+	if !consumed {
+		return false
+	}
+
+	appUser.BonusQuota--
+	c.Set("X-Bonus-Quota-Remaining", fmt.Sprintf("%d", appUser.BonusQuota))
+	m.logger.Info("Logged-in user request allowed from bonus quota",
+		logger.Field{Key: "user_id", Value: appUser.ID.String()},
+		logger.Field{Key: "bonus_quota_remaining", Value: appUser.BonusQuota})
+	return true
+}
+
 // handleGuestLifetimeLimit handles rate limiting for guest users (3 lifetime, NO daily reset)
 func (m *GuestRateLimitMiddleware) handleGuestLifetimeLimit(c *fiber.Ctx, endpoint string, limit int) error {
 	ctx := context.Background()
@@ -428,16 +476,15 @@ func (m *GuestRateLimitMiddleware) setRateLimitHeaders(c *fiber.Ctx, usage *gues
 }
 
 // SetupGuestAgentReplyRateLimit creates middleware specifically for agent reply endpoint
-func SetupGuestAgentReplyRateLimit(repo guestUsagePort.Repository, log logger.Logger) fiber.Handler {
-	middleware := NewGuestRateLimitMiddleware(repo, log)
+func SetupGuestAgentReplyRateLimit(repo guestUsagePort.Repository, userRepo userPort.RepositoryInterface, log logger.Logger) fiber.Handler {
+	middleware := NewGuestRateLimitMiddleware(repo, userRepo, log)
 	return middleware.GuestOrAuthRateLimit(AgentReplyEndpoint, DefaultGuestDailyLimit)
 }
 
 // AbuseDetectionMiddleware detects and blocks suspicious patterns
 func (m *GuestRateLimitMiddleware) AbuseDetectionMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		user := c.Locals("user")
-		if user != nil {
+		if c.Locals("user") != nil {
 			return c.Next() // Skip for authenticated users
 		}
 
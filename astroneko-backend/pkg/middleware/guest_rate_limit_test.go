@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"astroneko-backend/internal/core/domain/guest_usage"
+	"astroneko-backend/internal/core/domain/user"
+	userPort "astroneko-backend/internal/core/ports/user"
 	"astroneko-backend/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -53,6 +56,14 @@ func (m *MockGuestUsageRepository) GetByIPAddress(ctx context.Context, ipAddress
 	return args.Get(0).([]*guest_usage.GuestAPIUsage), args.Error(1)
 }
 
+func (m *MockGuestUsageRepository) ListByCompositeKey(ctx context.Context, compositeKey string) ([]*guest_usage.GuestAPIUsage, error) {
+	args := m.Called(ctx, compositeKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*guest_usage.GuestAPIUsage), args.Error(1)
+}
+
 func (m *MockGuestUsageRepository) BlockGuest(ctx context.Context, compositeKey, reason string) error {
 	args := m.Called(ctx, compositeKey, reason)
 	return args.Error(0)
@@ -87,7 +98,7 @@ func TestGuestOrAuthRateLimit_LoggedInWithReferral(t *testing.T) {
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	app.Post("/test", func(c *fiber.Ctx) error {
@@ -116,7 +127,7 @@ func TestGuestOrAuthRateLimit_LoggedInNoReferral_FirstRequest(t *testing.T) {
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository to return nil (first request)
@@ -150,7 +161,7 @@ func TestGuestOrAuthRateLimit_LoggedInNoReferral_DailyLimitExceeded(t *testing.T
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository to return usage at limit
@@ -187,13 +198,111 @@ func TestGuestOrAuthRateLimit_LoggedInNoReferral_DailyLimitExceeded(t *testing.T
 	assert.Contains(t, body["message"], "activate a referral code")
 }
 
+// bonusQuotaUserRepo hands out bonus requests until none are left
+type bonusQuotaUserRepo struct {
+	userPort.RepositoryInterface
+	remaining int
+}
+
+func (r *bonusQuotaUserRepo) ConsumeBonusQuota(ctx context.Context, id uuid.UUID) (bool, error) {
+	if r.remaining == 0 {
+		return false, nil
+	}
+	r.remaining--
+	return true, nil
+}
+
+// TestGuestOrAuthRateLimit_LoggedInNoReferral_BonusQuota tests that bonus quota is spent after the daily limit
+func TestGuestOrAuthRateLimit_LoggedInNoReferral_BonusQuota(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(MockGuestUsageRepository)
+	userRepo := &bonusQuotaUserRepo{remaining: 1}
+	log := &MockLogger{}
+
+	middleware := NewGuestRateLimitMiddleware(mockRepo, userRepo, log)
+	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
+
+	existingUsage := &guest_usage.GuestAPIUsage{
+		ID:            "1",
+		CompositeKey:  "user_test_uid_123",
+		Endpoint:      "/api/v1/agent/reply",
+		UsageCount:    3,
+		DailyLimit:    3,
+		WindowResetAt: time.Now().Add(12 * time.Hour),
+	}
+	mockRepo.On("GetByCompositeKey", mock.Anything, "user_test_uid_123", mock.Anything, mock.Anything).Return(existingUsage, nil)
+
+	tier := user.TierFree
+	appUser := &user.User{IsActivatedReferral: true, ReferralTier: &tier, BonusQuota: 1}
+	appUser.ID = uuid.New()
+
+	app.Post("/test", func(c *fiber.Ctx) error {
+		c.Locals("user_type", "logged_in_no_referral")
+		c.Locals("user", appUser)
+		c.Locals("firebase_uid", "test_uid_123")
+		return c.Next()
+	}, handler, func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	// First request over the daily limit spends the only bonus request
+	resp, err := app.Test(httptest.NewRequest("POST", "/test", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-Bonus-Quota-Remaining"))
+	assert.Equal(t, 0, userRepo.remaining)
+
+	// Second request has nothing left to spend
+	resp, err = app.Test(httptest.NewRequest("POST", "/test", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.Equal(t, "You've used all your daily requests. Please try again tomorrow.", body["message"])
+	mockRepo.AssertNotCalled(t, "IncrementUsage")
+}
+
+// TestGuestOrAuthRateLimit_LoggedInNoReferral_PlusTier tests the higher daily limit of the plus tier
+func TestGuestOrAuthRateLimit_LoggedInNoReferral_PlusTier(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(MockGuestUsageRepository)
+	log := &MockLogger{}
+
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
+	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
+
+	mockRepo.On("GetByCompositeKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(usage *guest_usage.GuestAPIUsage) bool {
+		return usage.DailyLimit == 20
+	})).Return(nil)
+
+	tier := user.TierPlus
+	appUser := &user.User{IsActivatedReferral: true, ReferralTier: &tier}
+
+	app.Post("/test", func(c *fiber.Ctx) error {
+		c.Locals("user_type", "logged_in_no_referral")
+		c.Locals("user", appUser)
+		c.Locals("firebase_uid", "test_uid_123")
+		return c.Next()
+	}, handler, func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/test", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "20", resp.Header.Get("X-RateLimit-Limit"))
+}
+
 // TestGuestOrAuthRateLimit_Guest_FirstRequest tests guest lifetime limit
 func TestGuestOrAuthRateLimit_Guest_FirstRequest(t *testing.T) {
 	app := fiber.New()
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository to return nil (first request)
@@ -231,7 +340,7 @@ func TestGuestOrAuthRateLimit_Guest_LifetimeLimitExceeded(t *testing.T) {
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository to return usage at lifetime limit
@@ -275,7 +384,7 @@ func TestGuestOrAuthRateLimit_Guest_SecondRequest(t *testing.T) {
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository to return existing usage (2/3)
@@ -316,7 +425,7 @@ func TestGuestOrAuthRateLimit_LoggedInNoReferral_MultipleRequests(t *testing.T)
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Simulate 4 requests from the same logged-in user
@@ -397,7 +506,7 @@ func TestGuestOrAuthRateLimit_UnknownUserType(t *testing.T) {
 	mockRepo := new(MockGuestUsageRepository)
 	log := &MockLogger{}
 
-	middleware := NewGuestRateLimitMiddleware(mockRepo, log)
+	middleware := NewGuestRateLimitMiddleware(mockRepo, nil, log)
 	handler := middleware.GuestOrAuthRateLimit("/api/v1/agent/reply", 3)
 
 	// Mock repository
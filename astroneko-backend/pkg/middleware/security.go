@@ -5,6 +5,8 @@ import (
 	"encoding/base64"
 	"time"
 
+	"astroneko-backend/internal/core/domain/referral_code"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/csrf"
 	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
@@ -142,6 +144,27 @@ func SetupAgentReplyRateLimitMiddleware() fiber.Handler {
 	})
 }
 
+// ReferralClickThrottledKey is set in the request locals when a visit to a referral link is
+// over the click rate limit
+const ReferralClickThrottledKey = "referral_click_throttled"
+
+// SetupReferralClickRateLimitMiddleware limits how many visits from one IP to one referral
+// link are recorded as clicks. Visits over the limit are let through with
+// ReferralClickThrottledKey set, so the link keeps working but the click is not stored.
+func SetupReferralClickRateLimitMiddleware() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 10 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "referral_click:" + c.IP() + ":" + referral_code.NormalizeCode(c.Params("code"))
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			c.Locals(ReferralClickThrottledKey, true)
+			return c.Next()
+		},
+	})
+}
+
 // SetupXSSProtectionMiddleware adds additional XSS protection headers
 func SetupXSSProtectionMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferralClickRateLimit_ThrottlesRepeatVisitsPerCode(t *testing.T) {
+	app := fiber.New()
+	app.Get("/r/:code", SetupReferralClickRateLimitMiddleware(), func(c *fiber.Ctx) error {
+		if throttled, _ := c.Locals(ReferralClickThrottledKey).(bool); throttled {
+			return c.SendString("throttled")
+		}
+		return c.SendString("recorded")
+	})
+
+	visit := func(path string) string {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		body := make([]byte, 16)
+		n, _ := resp.Body.Read(body)
+		return string(body[:n])
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "recorded", visit("/r/NEKO2026"))
+	}
+	// Over the limit the visit still goes through, only without being recorded
+	assert.Equal(t, "throttled", visit("/r/neko2026"))
+	assert.Equal(t, "recorded", visit("/r/OTHER123"))
+}
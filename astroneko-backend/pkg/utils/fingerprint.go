@@ -48,7 +48,17 @@ func GenerateGuestFingerprint(c *fiber.Ctx) *GuestFingerprint {
 	}
 }
 
-// GetRealIP extracts the real client IP, handling proxies and load balancers
+// DeviceFingerprint hashes the client's IP address and normalized user agent. Unlike the
+// composite key of GenerateGuestFingerprint it does not change daily, so it can be stored
+// and compared later. The address is c.IP(), which only takes a forwarded address from a
+// trusted proxy, so a client cannot vary its fingerprint by sending its own headers.
+func DeviceFingerprint(c *fiber.Ctx) string {
+	return HashString(c.IP() + "|" + NormalizeUserAgent(c.Get("User-Agent")))
+}
+
+// GetRealIP extracts the real client IP, handling proxies and load balancers. Clients can
+// set these headers themselves, so anything that limits or blocks by address must use
+// c.IP(), which only reads the configured proxy header from trusted proxies.
 func GetRealIP(c *fiber.Ctx) string {
 	// Priority order for IP detection:
 	// 1. CF-Connecting-IP (Cloudflare)
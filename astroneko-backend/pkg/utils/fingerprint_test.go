@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fingerprintFor returns the device fingerprint app computes for a request with headers
+func fingerprintFor(t *testing.T, app *fiber.App, headers map[string]string) string {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh) Chrome/120.0.0.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func newFingerprintApp(trustedProxies []string) *fiber.App {
+	app := fiber.New(fiber.Config{
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
+		ProxyHeader:             "X-Real-IP",
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(DeviceFingerprint(c))
+	})
+	return app
+}
+
+func TestDeviceFingerprint_IgnoresClientHeaders(t *testing.T) {
+	// Arrange
+	app := newFingerprintApp(nil)
+
+	// Act
+	direct := fingerprintFor(t, app, nil)
+	spoofed := fingerprintFor(t, app, map[string]string{
+		"X-Real-IP":        "203.0.113.7",
+		"X-Forwarded-For":  "203.0.113.8",
+		"CF-Connecting-IP": "203.0.113.9",
+	})
+
+	// Assert
+	assert.Equal(t, direct, spoofed)
+}
+
+func TestDeviceFingerprint_TrustedProxy(t *testing.T) {
+	// Arrange
+	// app.Test connects from 0.0.0.0, which stands in for the load balancer here
+	app := newFingerprintApp([]string{"0.0.0.0"})
+
+	// Act
+	first := fingerprintFor(t, app, map[string]string{"X-Real-IP": "203.0.113.7"})
+	second := fingerprintFor(t, app, map[string]string{"X-Real-IP": "203.0.113.8"})
+
+	// Assert
+	assert.NotEqual(t, first, second)
+}
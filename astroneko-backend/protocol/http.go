@@ -17,6 +17,7 @@ import (
 
 	fiber "github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"go.uber.org/zap"
 )
 
@@ -29,7 +30,7 @@ type Server struct {
 	csrfManager *middleware.CSRFManager
 }
 
-func NewServer() *Server {
+func NewServer(config *configs.Config) *Server {
 	zapLogger, _ := logger.NewZapLogger()
 	app := fiber.New(fiber.Config{
 		BodyLimit:                 10 * 1024 * 1024, // 10MB
@@ -43,17 +44,26 @@ func NewServer() *Server {
 		DisableDefaultContentType: false,
 		DisableHeaderNormalizing:  false,
 		DisableStartupMessage:     false,
+		// c.IP() reads the proxy header only on requests from a trusted proxy, so clients
+		// cannot choose the address that rate limits and fingerprints are keyed on
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          config.Proxy.TrustedProxies,
+		ProxyHeader:             config.Proxy.Header,
+		EnableIPValidation:      true,
 	})
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "https://staging.astro-boxing-next.pages.dev,https://astro-boxing-next.pages.dev,http://localhost:3000,http://localhost:5173,http://localhost:5174,http://localhost:5175,https://astroneko.com,https://astroneko.net,https://staging.luckycat-frontend.pages.dev,https://luckycat-frontend.pages.dev,https://fix-login.luckycat-frontend.pages.dev,https://dev.astroneko-crm-frontend.pages.dev,https://staging.astroneko-crm-frontend.pages.dev,https://astroneko-crm-frontend.pages.dev,https://astrofight.ai",
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
 		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Csrf-Token,X-Requested-With",
-		ExposeHeaders:    "Content-Length",
+		ExposeHeaders:    "Content-Length,X-Request-ID",
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}))
 
+	// Tag every request with an ID that is echoed back and recorded in the audit log
+	app.Use(requestid.New())
+
 	app.Use(logger.ZapLoggerMiddleware(zapLogger))
 	app.Use(logger.ZapRecoveryMiddleware(zapLogger))
 
@@ -110,18 +120,12 @@ func NewServer() *Server {
 
 	return &Server{
 		app:         app,
-		config:      nil,
+		config:      config,
 		zapLogger:   zapLogger,
 		csrfManager: csrfManager,
 	}
 }
 
-func (s *Server) Initialize(configPath string) error {
-	configs.InitViper(configPath)
-	s.config = configs.GetViper()
-	return nil
-}
-
 func (s *Server) setupDatabase() (*gorm.DB, error) {
 	if s.config.App.Env == "local" {
 		return gorm.ConnectToPostgreSQL(
@@ -178,12 +182,9 @@ func (s *Server) swagger() {
 }
 
 func ServeHTTP() error {
-	server := NewServer()
-
-	// Initialize configuration - will load prod-config.yml
-	if err := server.Initialize("./configs"); err != nil {
-		log.Printf("Warning: Could not load config: %v, using default configuration", err)
-	}
+	// Load prod-config.yml first; the server's proxy settings come from it
+	configs.InitViper("./configs")
+	server := NewServer(configs.GetViper())
 
 	server.swagger()
 
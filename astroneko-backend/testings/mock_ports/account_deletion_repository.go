@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/account_deletion/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	account_deletion "astroneko-backend/internal/core/domain/account_deletion"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAccountDeletionRepositoryInterface is a mock of RepositoryInterface interface.
+type MockAccountDeletionRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionRepositoryInterfaceMockRecorder
+}
+
+// MockAccountDeletionRepositoryInterfaceMockRecorder is the mock recorder for MockAccountDeletionRepositoryInterface.
+type MockAccountDeletionRepositoryInterfaceMockRecorder struct {
+	mock *MockAccountDeletionRepositoryInterface
+}
+
+// NewMockAccountDeletionRepositoryInterface creates a new mock instance.
+func NewMockAccountDeletionRepositoryInterface(ctrl *gomock.Controller) *MockAccountDeletionRepositoryInterface {
+	mock := &MockAccountDeletionRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionRepositoryInterface) EXPECT() *MockAccountDeletionRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddAuditEvent mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) AddAuditEvent(ctx context.Context, event *account_deletion.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAuditEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuditEvent indicates an expected call of AddAuditEvent.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) AddAuditEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditEvent", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).AddAuditEvent), ctx, event)
+}
+
+// Create mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) Create(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) Create(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).Create), ctx, request)
+}
+
+// GetActiveByUserID mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*account_deletion.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByUserID", ctx, userID)
+	ret0, _ := ret[0].(*account_deletion.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByUserID indicates an expected call of GetActiveByUserID.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) GetActiveByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByUserID", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).GetActiveByUserID), ctx, userID)
+}
+
+// ListAwaitingFirebase mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) ListAwaitingFirebase(ctx context.Context, limit int) ([]account_deletion.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAwaitingFirebase", ctx, limit)
+	ret0, _ := ret[0].([]account_deletion.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAwaitingFirebase indicates an expected call of ListAwaitingFirebase.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) ListAwaitingFirebase(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAwaitingFirebase", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).ListAwaitingFirebase), ctx, limit)
+}
+
+// ListDue mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) ListDue(ctx context.Context, now time.Time, limit int) ([]account_deletion.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]account_deletion.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) ListDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).ListDue), ctx, now, limit)
+}
+
+// PurgeUserData mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) PurgeUserData(ctx context.Context, request *account_deletion.DeletionRequest) (*account_deletion.PurgeSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUserData", ctx, request)
+	ret0, _ := ret[0].(*account_deletion.PurgeSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUserData indicates an expected call of PurgeUserData.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) PurgeUserData(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUserData", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).PurgeUserData), ctx, request)
+}
+
+// Update mocks base method.
+func (m *MockAccountDeletionRepositoryInterface) Update(ctx context.Context, request *account_deletion.DeletionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAccountDeletionRepositoryInterfaceMockRecorder) Update(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccountDeletionRepositoryInterface)(nil).Update), ctx, request)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/audit/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	audit "astroneko-backend/internal/core/domain/audit"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditRepositoryInterface is a mock of RepositoryInterface interface.
+type MockAuditRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryInterfaceMockRecorder
+}
+
+// MockAuditRepositoryInterfaceMockRecorder is the mock recorder for MockAuditRepositoryInterface.
+type MockAuditRepositoryInterfaceMockRecorder struct {
+	mock *MockAuditRepositoryInterface
+}
+
+// NewMockAuditRepositoryInterface creates a new mock instance.
+func NewMockAuditRepositoryInterface(ctrl *gomock.Controller) *MockAuditRepositoryInterface {
+	mock := &MockAuditRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepositoryInterface) EXPECT() *MockAuditRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepositoryInterface) Create(ctx context.Context, entry *audit.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryInterfaceMockRecorder) Create(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepositoryInterface)(nil).Create), ctx, entry)
+}
+
+// List mocks base method.
+func (m *MockAuditRepositoryInterface) List(ctx context.Context, filter audit.ListFilter) ([]*audit.Entry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*audit.Entry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditRepositoryInterfaceMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditRepositoryInterface)(nil).List), ctx, filter)
+}
+
+// ListChain mocks base method.
+func (m *MockAuditRepositoryInterface) ListChain(ctx context.Context, afterSequence int64, limit int) ([]*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChain", ctx, afterSequence, limit)
+	ret0, _ := ret[0].([]*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChain indicates an expected call of ListChain.
+func (mr *MockAuditRepositoryInterfaceMockRecorder) ListChain(ctx, afterSequence, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChain", reflect.TypeOf((*MockAuditRepositoryInterface)(nil).ListChain), ctx, afterSequence, limit)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/credential_attempt/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	credential_attempt "astroneko-backend/internal/core/domain/credential_attempt"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockCredentialAttemptRepositoryInterface is a mock of RepositoryInterface interface.
+type MockCredentialAttemptRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialAttemptRepositoryInterfaceMockRecorder
+}
+
+// MockCredentialAttemptRepositoryInterfaceMockRecorder is the mock recorder for MockCredentialAttemptRepositoryInterface.
+type MockCredentialAttemptRepositoryInterfaceMockRecorder struct {
+	mock *MockCredentialAttemptRepositoryInterface
+}
+
+// NewMockCredentialAttemptRepositoryInterface creates a new mock instance.
+func NewMockCredentialAttemptRepositoryInterface(ctrl *gomock.Controller) *MockCredentialAttemptRepositoryInterface {
+	mock := &MockCredentialAttemptRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCredentialAttemptRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialAttemptRepositoryInterface) EXPECT() *MockCredentialAttemptRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockCredentialAttemptRepositoryInterface) DeleteStale(ctx context.Context, before, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, before, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockCredentialAttemptRepositoryInterfaceMockRecorder) DeleteStale(ctx, before, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockCredentialAttemptRepositoryInterface)(nil).DeleteStale), ctx, before, now)
+}
+
+// Get mocks base method.
+func (m *MockCredentialAttemptRepositoryInterface) Get(ctx context.Context, scope, keyType, keyHash string) (*credential_attempt.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, scope, keyType, keyHash)
+	ret0, _ := ret[0].(*credential_attempt.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCredentialAttemptRepositoryInterfaceMockRecorder) Get(ctx, scope, keyType, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCredentialAttemptRepositoryInterface)(nil).Get), ctx, scope, keyType, keyHash)
+}
+
+// LockUntil mocks base method.
+func (m *MockCredentialAttemptRepositoryInterface) LockUntil(ctx context.Context, id uuid.UUID, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUntil", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUntil indicates an expected call of LockUntil.
+func (mr *MockCredentialAttemptRepositoryInterfaceMockRecorder) LockUntil(ctx, id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUntil", reflect.TypeOf((*MockCredentialAttemptRepositoryInterface)(nil).LockUntil), ctx, id, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockCredentialAttemptRepositoryInterface) RecordFailure(ctx context.Context, scope, keyType, keyHash string, now, windowStart time.Time) (*credential_attempt.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, scope, keyType, keyHash, now, windowStart)
+	ret0, _ := ret[0].(*credential_attempt.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockCredentialAttemptRepositoryInterfaceMockRecorder) RecordFailure(ctx, scope, keyType, keyHash, now, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockCredentialAttemptRepositoryInterface)(nil).RecordFailure), ctx, scope, keyType, keyHash, now, windowStart)
+}
+
+// Reset mocks base method.
+func (m *MockCredentialAttemptRepositoryInterface) Reset(ctx context.Context, scope, keyType, keyHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, scope, keyType, keyHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockCredentialAttemptRepositoryInterfaceMockRecorder) Reset(ctx, scope, keyType, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockCredentialAttemptRepositoryInterface)(nil).Reset), ctx, scope, keyType, keyHash)
+}
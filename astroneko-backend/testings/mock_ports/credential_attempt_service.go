@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/credential_attempt/service.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCredentialAttemptServiceInterface is a mock of ServiceInterface interface.
+type MockCredentialAttemptServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialAttemptServiceInterfaceMockRecorder
+}
+
+// MockCredentialAttemptServiceInterfaceMockRecorder is the mock recorder for MockCredentialAttemptServiceInterface.
+type MockCredentialAttemptServiceInterfaceMockRecorder struct {
+	mock *MockCredentialAttemptServiceInterface
+}
+
+// NewMockCredentialAttemptServiceInterface creates a new mock instance.
+func NewMockCredentialAttemptServiceInterface(ctrl *gomock.Controller) *MockCredentialAttemptServiceInterface {
+	mock := &MockCredentialAttemptServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCredentialAttemptServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialAttemptServiceInterface) EXPECT() *MockCredentialAttemptServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockCredentialAttemptServiceInterface) Check(ctx context.Context, scope, account, clientIP string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, scope, account, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockCredentialAttemptServiceInterfaceMockRecorder) Check(ctx, scope, account, clientIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockCredentialAttemptServiceInterface)(nil).Check), ctx, scope, account, clientIP)
+}
+
+// RecordFailure mocks base method.
+func (m *MockCredentialAttemptServiceInterface) RecordFailure(ctx context.Context, scope, account, clientIP string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, scope, account, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockCredentialAttemptServiceInterfaceMockRecorder) RecordFailure(ctx, scope, account, clientIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockCredentialAttemptServiceInterface)(nil).RecordFailure), ctx, scope, account, clientIP)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockCredentialAttemptServiceInterface) RecordSuccess(ctx context.Context, scope, account string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", ctx, scope, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockCredentialAttemptServiceInterfaceMockRecorder) RecordSuccess(ctx, scope, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockCredentialAttemptServiceInterface)(nil).RecordSuccess), ctx, scope, account)
+}
+
+// Unlock mocks base method.
+func (m *MockCredentialAttemptServiceInterface) Unlock(ctx context.Context, scope, account string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, scope, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockCredentialAttemptServiceInterfaceMockRecorder) Unlock(ctx, scope, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockCredentialAttemptServiceInterface)(nil).Unlock), ctx, scope, account)
+}
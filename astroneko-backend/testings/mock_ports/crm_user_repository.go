@@ -5,11 +5,13 @@
 package mock_ports
 
 import (
-	"context"
-	"reflect"
+	crm_user "astroneko-backend/internal/core/domain/crm_user"
+	context "context"
+	reflect "reflect"
+	time "time"
 
-	"astroneko-backend/internal/core/domain/crm_user"
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockCRMUserRepositoryInterface is a mock of RepositoryInterface interface.
@@ -35,6 +37,51 @@ func (m *MockCRMUserRepositoryInterface) EXPECT() *MockCRMUserRepositoryInterfac
 	return m.recorder
 }
 
+// ConsumePasswordReset mocks base method.
+func (m *MockCRMUserRepositoryInterface) ConsumePasswordReset(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumePasswordReset", ctx, id, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumePasswordReset indicates an expected call of ConsumePasswordReset.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) ConsumePasswordReset(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumePasswordReset", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).ConsumePasswordReset), ctx, id, now)
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockCRMUserRepositoryInterface) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", ctx, userID, codeHash, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) ConsumeRecoveryCode(ctx, userID, codeHash, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).ConsumeRecoveryCode), ctx, userID, codeHash, now)
+}
+
+// CountByRole mocks base method.
+func (m *MockCRMUserRepositoryInterface) CountByRole(ctx context.Context, role string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByRole", ctx, role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByRole indicates an expected call of CountByRole.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) CountByRole(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByRole", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).CountByRole), ctx, role)
+}
+
 // Create mocks base method.
 func (m *MockCRMUserRepositoryInterface) Create(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMUser, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +97,48 @@ func (mr *MockCRMUserRepositoryInterfaceMockRecorder) Create(ctx, user interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).Create), ctx, user)
 }
 
+// CreatePasswordReset mocks base method.
+func (m *MockCRMUserRepositoryInterface) CreatePasswordReset(ctx context.Context, reset *crm_user.PasswordReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", ctx, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) CreatePasswordReset(ctx, reset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).CreatePasswordReset), ctx, reset)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockCRMUserRepositoryInterface) CreateRefreshToken(ctx context.Context, token *crm_user.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) CreateRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).CreateRefreshToken), ctx, token)
+}
+
+// Delete mocks base method.
+func (m *MockCRMUserRepositoryInterface) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).Delete), ctx, id)
+}
+
 // GetByID mocks base method.
 func (m *MockCRMUserRepositoryInterface) GetByID(ctx context.Context, id string) (*crm_user.CRMUser, error) {
 	m.ctrl.T.Helper()
@@ -80,31 +169,194 @@ func (mr *MockCRMUserRepositoryInterfaceMockRecorder) GetByUsername(ctx, usernam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).GetByUsername), ctx, username)
 }
 
+// GetPasswordResetByTokenHash mocks base method.
+func (m *MockCRMUserRepositoryInterface) GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*crm_user.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*crm_user.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetByTokenHash indicates an expected call of GetPasswordResetByTokenHash.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) GetPasswordResetByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetByTokenHash", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).GetPasswordResetByTokenHash), ctx, tokenHash)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockCRMUserRepositoryInterface) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*crm_user.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*crm_user.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) GetRefreshTokenByHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).GetRefreshTokenByHash), ctx, tokenHash)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockCRMUserRepositoryInterface) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) IsAccessTokenRevoked(ctx, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).IsAccessTokenRevoked), ctx, jti)
+}
+
+// List mocks base method.
+func (m *MockCRMUserRepositoryInterface) List(ctx context.Context, limit, offset int) ([]*crm_user.CRMUser, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*crm_user.CRMUser)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).List), ctx, limit, offset)
+}
+
+// ListRoles mocks base method.
+func (m *MockCRMUserRepositoryInterface) ListRoles(ctx context.Context) ([]crm_user.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].([]crm_user.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) ListRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).ListRoles), ctx)
+}
+
+// RecordTOTPStep mocks base method.
+func (m *MockCRMUserRepositoryInterface) RecordTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordTOTPStep indicates an expected call of RecordTOTPStep.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) RecordTOTPStep(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTOTPStep", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).RecordTOTPStep), ctx, userID, step)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockCRMUserRepositoryInterface) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []crm_user.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) ReplaceRecoveryCodes(ctx, userID, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).ReplaceRecoveryCodes), ctx, userID, codes)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockCRMUserRepositoryInterface) RevokeAccessToken(ctx context.Context, revoked *crm_user.RevokedAccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, revoked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) RevokeAccessToken(ctx, revoked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).RevokeAccessToken), ctx, revoked)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockCRMUserRepositoryInterface) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) RevokeRefreshTokenFamily(ctx, familyID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).RevokeRefreshTokenFamily), ctx, familyID, now)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockCRMUserRepositoryInterface) RotateRefreshToken(ctx context.Context, currentID uuid.UUID, next *crm_user.RefreshToken, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, currentID, next, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) RotateRefreshToken(ctx, currentID, next, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).RotateRefreshToken), ctx, currentID, next, now)
+}
+
+// SetRoles mocks base method.
+func (m *MockCRMUserRepositoryInterface) SetRoles(ctx context.Context, userID uuid.UUID, roles []string, grantedBy *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRoles", ctx, userID, roles, grantedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRoles indicates an expected call of SetRoles.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) SetRoles(ctx, userID, roles, grantedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRoles", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).SetRoles), ctx, userID, roles, grantedBy)
+}
+
 // Update mocks base method.
-func (m *MockCRMUserRepositoryInterface) Update(ctx context.Context, user *crm_user.CRMUser) (*crm_user.CRMUser, error) {
+func (m *MockCRMUserRepositoryInterface) Update(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, user)
+	ret := m.ctrl.Call(m, "Update", ctx, user, columns)
 	ret0, _ := ret[0].(*crm_user.CRMUser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockCRMUserRepositoryInterfaceMockRecorder) Update(ctx, user interface{}) *gomock.Call {
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) Update(ctx, user, columns interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).Update), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).Update), ctx, user, columns)
 }
 
-// Delete mocks base method.
-func (m *MockCRMUserRepositoryInterface) Delete(ctx context.Context, id string) error {
+// UpdateAndRevokeSessions mocks base method.
+func (m *MockCRMUserRepositoryInterface) UpdateAndRevokeSessions(ctx context.Context, user *crm_user.CRMUser, columns []string) (*crm_user.CRMUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateAndRevokeSessions", ctx, user, columns)
+	ret0, _ := ret[0].(*crm_user.CRMUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockCRMUserRepositoryInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+// UpdateAndRevokeSessions indicates an expected call of UpdateAndRevokeSessions.
+func (mr *MockCRMUserRepositoryInterfaceMockRecorder) UpdateAndRevokeSessions(ctx, user, columns interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).Delete), ctx, id)
-}
\ No newline at end of file
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAndRevokeSessions", reflect.TypeOf((*MockCRMUserRepositoryInterface)(nil).UpdateAndRevokeSessions), ctx, user, columns)
+}
@@ -0,0 +1,185 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/data_export/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	data_export "astroneko-backend/internal/core/domain/data_export"
+	data_export0 "astroneko-backend/internal/core/ports/data_export"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDataExportRepositoryInterface is a mock of RepositoryInterface interface.
+type MockDataExportRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryInterfaceMockRecorder
+}
+
+// MockDataExportRepositoryInterfaceMockRecorder is the mock recorder for MockDataExportRepositoryInterface.
+type MockDataExportRepositoryInterfaceMockRecorder struct {
+	mock *MockDataExportRepositoryInterface
+}
+
+// NewMockDataExportRepositoryInterface creates a new mock instance.
+func NewMockDataExportRepositoryInterface(ctrl *gomock.Controller) *MockDataExportRepositoryInterface {
+	mock := &MockDataExportRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepositoryInterface) EXPECT() *MockDataExportRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimPending mocks base method.
+func (m *MockDataExportRepositoryInterface) ClaimPending(ctx context.Context, now, staleBefore time.Time, limit int) ([]data_export.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPending", ctx, now, staleBefore, limit)
+	ret0, _ := ret[0].([]data_export.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPending indicates an expected call of ClaimPending.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) ClaimPending(ctx, now, staleBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPending", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).ClaimPending), ctx, now, staleBefore, limit)
+}
+
+// CountCreatedSince mocks base method.
+func (m *MockDataExportRepositoryInterface) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCreatedSince", ctx, userID, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCreatedSince indicates an expected call of CountCreatedSince.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) CountCreatedSince(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCreatedSince", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).CountCreatedSince), ctx, userID, since)
+}
+
+// Create mocks base method.
+func (m *MockDataExportRepositoryInterface) Create(ctx context.Context, job *data_export.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) Create(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).Create), ctx, job)
+}
+
+// ExpireArchives mocks base method.
+func (m *MockDataExportRepositoryInterface) ExpireArchives(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireArchives", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireArchives indicates an expected call of ExpireArchives.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) ExpireArchives(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireArchives", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).ExpireArchives), ctx, now)
+}
+
+// GetByID mocks base method.
+func (m *MockDataExportRepositoryInterface) GetByID(ctx context.Context, userID, jobID uuid.UUID) (*data_export.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, jobID)
+	ret0, _ := ret[0].(*data_export.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) GetByID(ctx, userID, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).GetByID), ctx, userID, jobID)
+}
+
+// GetWithArchive mocks base method.
+func (m *MockDataExportRepositoryInterface) GetWithArchive(ctx context.Context, jobID uuid.UUID) (*data_export.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithArchive", ctx, jobID)
+	ret0, _ := ret[0].(*data_export.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithArchive indicates an expected call of GetWithArchive.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) GetWithArchive(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithArchive", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).GetWithArchive), ctx, jobID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockDataExportRepositoryInterface) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]data_export.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID, limit)
+	ret0, _ := ret[0].([]data_export.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) ListByUserID(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).ListByUserID), ctx, userID, limit)
+}
+
+// SaveArchive mocks base method.
+func (m *MockDataExportRepositoryInterface) SaveArchive(ctx context.Context, job *data_export.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveArchive", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveArchive indicates an expected call of SaveArchive.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) SaveArchive(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveArchive", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).SaveArchive), ctx, job)
+}
+
+// Update mocks base method.
+func (m *MockDataExportRepositoryInterface) Update(ctx context.Context, job *data_export.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) Update(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).Update), ctx, job)
+}
+
+// WithUserLock mocks base method.
+func (m *MockDataExportRepositoryInterface) WithUserLock(ctx context.Context, userID uuid.UUID, fn func(data_export0.RepositoryInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithUserLock", ctx, userID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithUserLock indicates an expected call of WithUserLock.
+func (mr *MockDataExportRepositoryInterfaceMockRecorder) WithUserLock(ctx, userID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithUserLock", reflect.TypeOf((*MockDataExportRepositoryInterface)(nil).WithUserLock), ctx, userID, fn)
+}
@@ -131,6 +131,20 @@ func (mr *MockDatabaseInterfaceMockRecorder) Exec(sql interface{}, values ...int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockDatabaseInterface)(nil).Exec), varargs...)
 }
 
+// Error mocks base method.
+func (m *MockDatabaseInterface) Error() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockDatabaseInterfaceMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockDatabaseInterface)(nil).Error))
+}
+
 // Find mocks base method.
 func (m *MockDatabaseInterface) Find(dest interface{}, conds ...interface{}) error {
 	m.ctrl.T.Helper()
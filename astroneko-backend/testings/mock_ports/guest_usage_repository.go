@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/guest_usage/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	guest_usage "astroneko-backend/internal/core/domain/guest_usage"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGuestUsageRepository is a mock of Repository interface.
+type MockGuestUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestUsageRepositoryMockRecorder
+}
+
+// MockGuestUsageRepositoryMockRecorder is the mock recorder for MockGuestUsageRepository.
+type MockGuestUsageRepositoryMockRecorder struct {
+	mock *MockGuestUsageRepository
+}
+
+// NewMockGuestUsageRepository creates a new mock instance.
+func NewMockGuestUsageRepository(ctrl *gomock.Controller) *MockGuestUsageRepository {
+	mock := &MockGuestUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockGuestUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestUsageRepository) EXPECT() *MockGuestUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BlockGuest mocks base method.
+func (m *MockGuestUsageRepository) BlockGuest(ctx context.Context, compositeKey, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockGuest", ctx, compositeKey, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockGuest indicates an expected call of BlockGuest.
+func (mr *MockGuestUsageRepositoryMockRecorder) BlockGuest(ctx, compositeKey, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockGuest", reflect.TypeOf((*MockGuestUsageRepository)(nil).BlockGuest), ctx, compositeKey, reason)
+}
+
+// Create mocks base method.
+func (m *MockGuestUsageRepository) Create(ctx context.Context, usage *guest_usage.GuestAPIUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockGuestUsageRepositoryMockRecorder) Create(ctx, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockGuestUsageRepository)(nil).Create), ctx, usage)
+}
+
+// DeleteOldRecords mocks base method.
+func (m *MockGuestUsageRepository) DeleteOldRecords(ctx context.Context, olderThan string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldRecords", ctx, olderThan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldRecords indicates an expected call of DeleteOldRecords.
+func (mr *MockGuestUsageRepositoryMockRecorder) DeleteOldRecords(ctx, olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldRecords", reflect.TypeOf((*MockGuestUsageRepository)(nil).DeleteOldRecords), ctx, olderThan)
+}
+
+// GetByCompositeKey mocks base method.
+func (m *MockGuestUsageRepository) GetByCompositeKey(ctx context.Context, compositeKey, endpoint, windowResetAt string) (*guest_usage.GuestAPIUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCompositeKey", ctx, compositeKey, endpoint, windowResetAt)
+	ret0, _ := ret[0].(*guest_usage.GuestAPIUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCompositeKey indicates an expected call of GetByCompositeKey.
+func (mr *MockGuestUsageRepositoryMockRecorder) GetByCompositeKey(ctx, compositeKey, endpoint, windowResetAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCompositeKey", reflect.TypeOf((*MockGuestUsageRepository)(nil).GetByCompositeKey), ctx, compositeKey, endpoint, windowResetAt)
+}
+
+// GetByIPAddress mocks base method.
+func (m *MockGuestUsageRepository) GetByIPAddress(ctx context.Context, ipAddress, since string) ([]*guest_usage.GuestAPIUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIPAddress", ctx, ipAddress, since)
+	ret0, _ := ret[0].([]*guest_usage.GuestAPIUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIPAddress indicates an expected call of GetByIPAddress.
+func (mr *MockGuestUsageRepositoryMockRecorder) GetByIPAddress(ctx, ipAddress, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIPAddress", reflect.TypeOf((*MockGuestUsageRepository)(nil).GetByIPAddress), ctx, ipAddress, since)
+}
+
+// IncrementUsage mocks base method.
+func (m *MockGuestUsageRepository) IncrementUsage(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementUsage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementUsage indicates an expected call of IncrementUsage.
+func (mr *MockGuestUsageRepositoryMockRecorder) IncrementUsage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementUsage", reflect.TypeOf((*MockGuestUsageRepository)(nil).IncrementUsage), ctx, id)
+}
+
+// ListByCompositeKey mocks base method.
+func (m *MockGuestUsageRepository) ListByCompositeKey(ctx context.Context, compositeKey string) ([]*guest_usage.GuestAPIUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCompositeKey", ctx, compositeKey)
+	ret0, _ := ret[0].([]*guest_usage.GuestAPIUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCompositeKey indicates an expected call of ListByCompositeKey.
+func (mr *MockGuestUsageRepositoryMockRecorder) ListByCompositeKey(ctx, compositeKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCompositeKey", reflect.TypeOf((*MockGuestUsageRepository)(nil).ListByCompositeKey), ctx, compositeKey)
+}
+
+// ResetExpiredWindows mocks base method.
+func (m *MockGuestUsageRepository) ResetExpiredWindows(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetExpiredWindows", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetExpiredWindows indicates an expected call of ResetExpiredWindows.
+func (mr *MockGuestUsageRepositoryMockRecorder) ResetExpiredWindows(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetExpiredWindows", reflect.TypeOf((*MockGuestUsageRepository)(nil).ResetExpiredWindows), ctx)
+}
@@ -36,6 +36,20 @@ func (m *HistoryRepositoryInterface) EXPECT() *HistoryRepositoryInterfaceMockRec
 	return m.recorder
 }
 
+// CreateSessionWithMessages mocks base method.
+func (m *HistoryRepositoryInterface) CreateSessionWithMessages(ctx context.Context, session *history.Session, messages []history.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSessionWithMessages", ctx, session, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSessionWithMessages indicates an expected call of CreateSessionWithMessages.
+func (mr *HistoryRepositoryInterfaceMockRecorder) CreateSessionWithMessages(ctx, session, messages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionWithMessages", reflect.TypeOf((*HistoryRepositoryInterface)(nil).CreateSessionWithMessages), ctx, session, messages)
+}
+
 // DeleteSession mocks base method.
 func (m *HistoryRepositoryInterface) DeleteSession(ctx context.Context, sessionID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -50,6 +64,21 @@ func (mr *HistoryRepositoryInterfaceMockRecorder) DeleteSession(ctx, sessionID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*HistoryRepositoryInterface)(nil).DeleteSession), ctx, sessionID)
 }
 
+// GetMessageStatsByUserID mocks base method.
+func (m *HistoryRepositoryInterface) GetMessageStatsByUserID(ctx context.Context, userID uuid.UUID) (*history.MessageStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageStatsByUserID", ctx, userID)
+	ret0, _ := ret[0].(*history.MessageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageStatsByUserID indicates an expected call of GetMessageStatsByUserID.
+func (mr *HistoryRepositoryInterfaceMockRecorder) GetMessageStatsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageStatsByUserID", reflect.TypeOf((*HistoryRepositoryInterface)(nil).GetMessageStatsByUserID), ctx, userID)
+}
+
 // GetMessagesBySessionID mocks base method.
 func (m *HistoryRepositoryInterface) GetMessagesBySessionID(ctx context.Context, sessionID uuid.UUID, sortOrder string) ([]history.Message, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +94,21 @@ func (mr *HistoryRepositoryInterfaceMockRecorder) GetMessagesBySessionID(ctx, se
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesBySessionID", reflect.TypeOf((*HistoryRepositoryInterface)(nil).GetMessagesBySessionID), ctx, sessionID, sortOrder)
 }
 
+// GetMessagesByUserID mocks base method.
+func (m *HistoryRepositoryInterface) GetMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]history.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]history.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByUserID indicates an expected call of GetMessagesByUserID.
+func (mr *HistoryRepositoryInterfaceMockRecorder) GetMessagesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByUserID", reflect.TypeOf((*HistoryRepositoryInterface)(nil).GetMessagesByUserID), ctx, userID)
+}
+
 // GetSessionByID mocks base method.
 func (m *HistoryRepositoryInterface) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*history.Session, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/horoscope/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	horoscope "astroneko-backend/internal/core/domain/horoscope"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHoroscopeRepositoryInterface is a mock of RepositoryInterface interface.
+type MockHoroscopeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoroscopeRepositoryInterfaceMockRecorder
+}
+
+// MockHoroscopeRepositoryInterfaceMockRecorder is the mock recorder for MockHoroscopeRepositoryInterface.
+type MockHoroscopeRepositoryInterfaceMockRecorder struct {
+	mock *MockHoroscopeRepositoryInterface
+}
+
+// NewMockHoroscopeRepositoryInterface creates a new mock instance.
+func NewMockHoroscopeRepositoryInterface(ctrl *gomock.Controller) *MockHoroscopeRepositoryInterface {
+	mock := &MockHoroscopeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockHoroscopeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoroscopeRepositoryInterface) EXPECT() *MockHoroscopeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetBySignAndDate mocks base method.
+func (m *MockHoroscopeRepositoryInterface) GetBySignAndDate(ctx context.Context, sign string, date time.Time) (*horoscope.DailyHoroscope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySignAndDate", ctx, sign, date)
+	ret0, _ := ret[0].(*horoscope.DailyHoroscope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySignAndDate indicates an expected call of GetBySignAndDate.
+func (mr *MockHoroscopeRepositoryInterfaceMockRecorder) GetBySignAndDate(ctx, sign, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySignAndDate", reflect.TypeOf((*MockHoroscopeRepositoryInterface)(nil).GetBySignAndDate), ctx, sign, date)
+}
+
+// ListByDate mocks base method.
+func (m *MockHoroscopeRepositoryInterface) ListByDate(ctx context.Context, date time.Time) ([]horoscope.DailyHoroscope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDate", ctx, date)
+	ret0, _ := ret[0].([]horoscope.DailyHoroscope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDate indicates an expected call of ListByDate.
+func (mr *MockHoroscopeRepositoryInterfaceMockRecorder) ListByDate(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDate", reflect.TypeOf((*MockHoroscopeRepositoryInterface)(nil).ListByDate), ctx, date)
+}
+
+// Upsert mocks base method.
+func (m *MockHoroscopeRepositoryInterface) Upsert(ctx context.Context, dailyHoroscope *horoscope.DailyHoroscope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, dailyHoroscope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockHoroscopeRepositoryInterfaceMockRecorder) Upsert(ctx, dailyHoroscope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockHoroscopeRepositoryInterface)(nil).Upsert), ctx, dailyHoroscope)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/person/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	person "astroneko-backend/internal/core/domain/person"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPersonRepositoryInterface is a mock of RepositoryInterface interface.
+type MockPersonRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonRepositoryInterfaceMockRecorder
+}
+
+// MockPersonRepositoryInterfaceMockRecorder is the mock recorder for MockPersonRepositoryInterface.
+type MockPersonRepositoryInterfaceMockRecorder struct {
+	mock *MockPersonRepositoryInterface
+}
+
+// NewMockPersonRepositoryInterface creates a new mock instance.
+func NewMockPersonRepositoryInterface(ctrl *gomock.Controller) *MockPersonRepositoryInterface {
+	mock := &MockPersonRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPersonRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonRepositoryInterface) EXPECT() *MockPersonRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountByUserID mocks base method.
+func (m *MockPersonRepositoryInterface) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUserID", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUserID indicates an expected call of CountByUserID.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) CountByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUserID", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).CountByUserID), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockPersonRepositoryInterface) Create(ctx context.Context, p *person.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) Create(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).Create), ctx, p)
+}
+
+// Delete mocks base method.
+func (m *MockPersonRepositoryInterface) Delete(ctx context.Context, userID, personID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, personID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) Delete(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).Delete), ctx, userID, personID)
+}
+
+// GetByID mocks base method.
+func (m *MockPersonRepositoryInterface) GetByID(ctx context.Context, userID, personID uuid.UUID) (*person.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, personID)
+	ret0, _ := ret[0].(*person.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) GetByID(ctx, userID, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).GetByID), ctx, userID, personID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockPersonRepositoryInterface) ListByUserID(ctx context.Context, userID uuid.UUID) ([]person.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]person.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).ListByUserID), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockPersonRepositoryInterface) Update(ctx context.Context, p *person.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPersonRepositoryInterfaceMockRecorder) Update(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPersonRepositoryInterface)(nil).Update), ctx, p)
+}
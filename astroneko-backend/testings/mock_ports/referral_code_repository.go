@@ -6,6 +6,8 @@ package mock_ports
 
 import (
 	referral_code "astroneko-backend/internal/core/domain/referral_code"
+	referral_reward "astroneko-backend/internal/core/domain/referral_reward"
+	referral_code0 "astroneko-backend/internal/core/ports/referral_code"
 	context "context"
 	reflect "reflect"
 
@@ -36,6 +38,50 @@ func (m *ReferralCodeRepositoryInterface) EXPECT() *ReferralCodeRepositoryInterf
 	return m.recorder
 }
 
+// AttributeClick mocks base method.
+func (m *ReferralCodeRepositoryInterface) AttributeClick(ctx context.Context, referralLog *referral_code.ReferralLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttributeClick", ctx, referralLog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttributeClick indicates an expected call of AttributeClick.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) AttributeClick(ctx, referralLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttributeClick", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).AttributeClick), ctx, referralLog)
+}
+
+// CountInvitesForUpdate mocks base method.
+func (m *ReferralCodeRepositoryInterface) CountInvitesForUpdate(ctx context.Context, inviterID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountInvitesForUpdate", ctx, inviterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountInvitesForUpdate indicates an expected call of CountInvitesForUpdate.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CountInvitesForUpdate(ctx, inviterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInvitesForUpdate", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CountInvitesForUpdate), ctx, inviterID)
+}
+
+// CountRedemptions mocks base method.
+func (m *ReferralCodeRepositoryInterface) CountRedemptions(ctx context.Context, referralCodeID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRedemptions", ctx, referralCodeID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRedemptions indicates an expected call of CountRedemptions.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CountRedemptions(ctx, referralCodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRedemptions", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CountRedemptions), ctx, referralCodeID)
+}
+
 // Create mocks base method.
 func (m *ReferralCodeRepositoryInterface) Create(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +97,20 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) Create(ctx, referralCode
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).Create), ctx, referralCode)
 }
 
+// CreateReferralCodes mocks base method.
+func (m *ReferralCodeRepositoryInterface) CreateReferralCodes(ctx context.Context, referralCodes []*referral_code.ReferralCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReferralCodes", ctx, referralCodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReferralCodes indicates an expected call of CreateReferralCodes.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CreateReferralCodes(ctx, referralCodes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReferralCodes", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CreateReferralCodes), ctx, referralCodes)
+}
+
 // CreateReferralLog mocks base method.
 func (m *ReferralCodeRepositoryInterface) CreateReferralLog(ctx context.Context, referralLog *referral_code.ReferralLog) (*referral_code.ReferralLog, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +141,36 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CreateUserReferralCode(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserReferralCode", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CreateUserReferralCode), ctx, userReferralCode)
 }
 
+// CreateUserReferralCodeIfAbsent mocks base method.
+func (m *ReferralCodeRepositoryInterface) CreateUserReferralCodeIfAbsent(ctx context.Context, userReferralCode *referral_code.UserReferralCode) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserReferralCodeIfAbsent", ctx, userReferralCode)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserReferralCodeIfAbsent indicates an expected call of CreateUserReferralCodeIfAbsent.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CreateUserReferralCodeIfAbsent(ctx, userReferralCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserReferralCodeIfAbsent", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CreateUserReferralCodeIfAbsent), ctx, userReferralCode)
+}
+
+// CreditReward mocks base method.
+func (m *ReferralCodeRepositoryInterface) CreditReward(ctx context.Context, reward *referral_reward.Reward) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditReward", ctx, reward)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditReward indicates an expected call of CreditReward.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) CreditReward(ctx, reward interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditReward", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).CreditReward), ctx, reward)
+}
+
 // Delete mocks base method.
 func (m *ReferralCodeRepositoryInterface) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -95,6 +185,21 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) Delete(ctx, id interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).Delete), ctx, id)
 }
 
+// ExistingReferralCodes mocks base method.
+func (m *ReferralCodeRepositoryInterface) ExistingReferralCodes(ctx context.Context, codes []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingReferralCodes", ctx, codes)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingReferralCodes indicates an expected call of ExistingReferralCodes.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) ExistingReferralCodes(ctx, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingReferralCodes", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).ExistingReferralCodes), ctx, codes)
+}
+
 // GetByID mocks base method.
 func (m *ReferralCodeRepositoryInterface) GetByID(ctx context.Context, id string) (*referral_code.ReferralCode, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +230,21 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetByReferralCode(ctx, co
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReferralCode", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetByReferralCode), ctx, code)
 }
 
+// GetByReferralCodeForUpdate mocks base method.
+func (m *ReferralCodeRepositoryInterface) GetByReferralCodeForUpdate(ctx context.Context, code string) (*referral_code.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByReferralCodeForUpdate", ctx, code)
+	ret0, _ := ret[0].(*referral_code.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByReferralCodeForUpdate indicates an expected call of GetByReferralCodeForUpdate.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetByReferralCodeForUpdate(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReferralCodeForUpdate", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetByReferralCodeForUpdate), ctx, code)
+}
+
 // GetReferralCodeUsageCount mocks base method.
 func (m *ReferralCodeRepositoryInterface) GetReferralCodeUsageCount(ctx context.Context, referralCode string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +260,21 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetReferralCodeUsageCount
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCodeUsageCount", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetReferralCodeUsageCount), ctx, referralCode)
 }
 
+// GetReferralLogsByUserID mocks base method.
+func (m *ReferralCodeRepositoryInterface) GetReferralLogsByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.ReferralLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralLogsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*referral_code.ReferralLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralLogsByUserID indicates an expected call of GetReferralLogsByUserID.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetReferralLogsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralLogsByUserID", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetReferralLogsByUserID), ctx, userID)
+}
+
 // GetUserReferralCodeByCode mocks base method.
 func (m *ReferralCodeRepositoryInterface) GetUserReferralCodeByCode(ctx context.Context, code string) (*referral_code.UserReferralCode, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +290,21 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetUserReferralCodeByCode
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReferralCodeByCode", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetUserReferralCodeByCode), ctx, code)
 }
 
+// GetUserReferralCodeByCodeForUpdate mocks base method.
+func (m *ReferralCodeRepositoryInterface) GetUserReferralCodeByCodeForUpdate(ctx context.Context, code string) (*referral_code.UserReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReferralCodeByCodeForUpdate", ctx, code)
+	ret0, _ := ret[0].(*referral_code.UserReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReferralCodeByCodeForUpdate indicates an expected call of GetUserReferralCodeByCodeForUpdate.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) GetUserReferralCodeByCodeForUpdate(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReferralCodeByCodeForUpdate", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).GetUserReferralCodeByCodeForUpdate), ctx, code)
+}
+
 // GetUserReferralCodesByUserID mocks base method.
 func (m *ReferralCodeRepositoryInterface) GetUserReferralCodesByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_code.UserReferralCode, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +366,50 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) List(ctx, limit, offset i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).List), ctx, limit, offset)
 }
 
+// ListUsageAfter mocks base method.
+func (m *ReferralCodeRepositoryInterface) ListUsageAfter(ctx context.Context, after *referral_code.ExportCursor, limit int) ([]*referral_code.CodeUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsageAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*referral_code.CodeUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsageAfter indicates an expected call of ListUsageAfter.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) ListUsageAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsageAfter", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).ListUsageAfter), ctx, after, limit)
+}
+
+// LockCodeOwner mocks base method.
+func (m *ReferralCodeRepositoryInterface) LockCodeOwner(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCodeOwner", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockCodeOwner indicates an expected call of LockCodeOwner.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) LockCodeOwner(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCodeOwner", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).LockCodeOwner), ctx, userID)
+}
+
+// MarkUserReferralActivated mocks base method.
+func (m *ReferralCodeRepositoryInterface) MarkUserReferralActivated(ctx context.Context, userID uuid.UUID, tier string, bonusQuota int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUserReferralActivated", ctx, userID, tier, bonusQuota)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUserReferralActivated indicates an expected call of MarkUserReferralActivated.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) MarkUserReferralActivated(ctx, userID, tier, bonusQuota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUserReferralActivated", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).MarkUserReferralActivated), ctx, userID, tier, bonusQuota)
+}
+
 // Update mocks base method.
 func (m *ReferralCodeRepositoryInterface) Update(ctx context.Context, referralCode *referral_code.ReferralCode) (*referral_code.ReferralCode, error) {
 	m.ctrl.T.Helper()
@@ -245,3 +439,17 @@ func (mr *ReferralCodeRepositoryInterfaceMockRecorder) UpdateUserReferralCode(ct
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserReferralCode", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).UpdateUserReferralCode), ctx, userReferralCode)
 }
+
+// WithTx mocks base method.
+func (m *ReferralCodeRepositoryInterface) WithTx(ctx context.Context, fn func(referral_code0.RepositoryInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *ReferralCodeRepositoryInterfaceMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*ReferralCodeRepositoryInterface)(nil).WithTx), ctx, fn)
+}
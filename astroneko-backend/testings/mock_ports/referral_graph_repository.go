@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/referral_graph/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	referral_graph "astroneko-backend/internal/core/domain/referral_graph"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockReferralGraphRepositoryInterface is a mock of RepositoryInterface interface.
+type MockReferralGraphRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralGraphRepositoryInterfaceMockRecorder
+}
+
+// MockReferralGraphRepositoryInterfaceMockRecorder is the mock recorder for MockReferralGraphRepositoryInterface.
+type MockReferralGraphRepositoryInterfaceMockRecorder struct {
+	mock *MockReferralGraphRepositoryInterface
+}
+
+// NewMockReferralGraphRepositoryInterface creates a new mock instance.
+func NewMockReferralGraphRepositoryInterface(ctrl *gomock.Controller) *MockReferralGraphRepositoryInterface {
+	mock := &MockReferralGraphRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReferralGraphRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralGraphRepositoryInterface) EXPECT() *MockReferralGraphRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Leaderboard mocks base method.
+func (m *MockReferralGraphRepositoryInterface) Leaderboard(ctx context.Context, filter referral_graph.LeaderboardFilter) ([]referral_graph.LeaderboardRow, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leaderboard", ctx, filter)
+	ret0, _ := ret[0].([]referral_graph.LeaderboardRow)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Leaderboard indicates an expected call of Leaderboard.
+func (mr *MockReferralGraphRepositoryInterfaceMockRecorder) Leaderboard(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leaderboard", reflect.TypeOf((*MockReferralGraphRepositoryInterface)(nil).Leaderboard), ctx, filter)
+}
+
+// ListInvitees mocks base method.
+func (m *MockReferralGraphRepositoryInterface) ListInvitees(ctx context.Context, inviterIDs []uuid.UUID) ([]referral_graph.Edge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvitees", ctx, inviterIDs)
+	ret0, _ := ret[0].([]referral_graph.Edge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvitees indicates an expected call of ListInvitees.
+func (mr *MockReferralGraphRepositoryInterfaceMockRecorder) ListInvitees(ctx, inviterIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvitees", reflect.TypeOf((*MockReferralGraphRepositoryInterface)(nil).ListInvitees), ctx, inviterIDs)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/referral_link/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	referral_link "astroneko-backend/internal/core/domain/referral_link"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockReferralLinkRepositoryInterface is a mock of RepositoryInterface interface.
+type MockReferralLinkRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralLinkRepositoryInterfaceMockRecorder
+}
+
+// MockReferralLinkRepositoryInterfaceMockRecorder is the mock recorder for MockReferralLinkRepositoryInterface.
+type MockReferralLinkRepositoryInterfaceMockRecorder struct {
+	mock *MockReferralLinkRepositoryInterface
+}
+
+// NewMockReferralLinkRepositoryInterface creates a new mock instance.
+func NewMockReferralLinkRepositoryInterface(ctrl *gomock.Controller) *MockReferralLinkRepositoryInterface {
+	mock := &MockReferralLinkRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReferralLinkRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralLinkRepositoryInterface) EXPECT() *MockReferralLinkRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimClick mocks base method.
+func (m *MockReferralLinkRepositoryInterface) ClaimClick(ctx context.Context, clickID, userID uuid.UUID, notBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimClick", ctx, clickID, userID, notBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimClick indicates an expected call of ClaimClick.
+func (mr *MockReferralLinkRepositoryInterfaceMockRecorder) ClaimClick(ctx, clickID, userID, notBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimClick", reflect.TypeOf((*MockReferralLinkRepositoryInterface)(nil).ClaimClick), ctx, clickID, userID, notBefore)
+}
+
+// CreateClick mocks base method.
+func (m *MockReferralLinkRepositoryInterface) CreateClick(ctx context.Context, click *referral_link.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClick", ctx, click)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClick indicates an expected call of CreateClick.
+func (mr *MockReferralLinkRepositoryInterfaceMockRecorder) CreateClick(ctx, click interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClick", reflect.TypeOf((*MockReferralLinkRepositoryInterface)(nil).CreateClick), ctx, click)
+}
+
+// Funnel mocks base method.
+func (m *MockReferralLinkRepositoryInterface) Funnel(ctx context.Context, filter referral_link.FunnelFilter) ([]referral_link.FunnelRow, *referral_link.FunnelTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Funnel", ctx, filter)
+	ret0, _ := ret[0].([]referral_link.FunnelRow)
+	ret1, _ := ret[1].(*referral_link.FunnelTotals)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Funnel indicates an expected call of Funnel.
+func (mr *MockReferralLinkRepositoryInterfaceMockRecorder) Funnel(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Funnel", reflect.TypeOf((*MockReferralLinkRepositoryInterface)(nil).Funnel), ctx, filter)
+}
+
+// ListClicksByUserID mocks base method.
+func (m *MockReferralLinkRepositoryInterface) ListClicksByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_link.Click, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClicksByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*referral_link.Click)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClicksByUserID indicates an expected call of ListClicksByUserID.
+func (mr *MockReferralLinkRepositoryInterfaceMockRecorder) ListClicksByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClicksByUserID", reflect.TypeOf((*MockReferralLinkRepositoryInterface)(nil).ListClicksByUserID), ctx, userID)
+}
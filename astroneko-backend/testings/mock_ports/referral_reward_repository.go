@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/core/ports/referral_reward/repository.go
+
+// Package mock_ports is a generated GoMock package.
+package mock_ports
+
+import (
+	referral_reward "astroneko-backend/internal/core/domain/referral_reward"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockReferralRewardRepositoryInterface is a mock of RepositoryInterface interface.
+type MockReferralRewardRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralRewardRepositoryInterfaceMockRecorder
+}
+
+// MockReferralRewardRepositoryInterfaceMockRecorder is the mock recorder for MockReferralRewardRepositoryInterface.
+type MockReferralRewardRepositoryInterfaceMockRecorder struct {
+	mock *MockReferralRewardRepositoryInterface
+}
+
+// NewMockReferralRewardRepositoryInterface creates a new mock instance.
+func NewMockReferralRewardRepositoryInterface(ctrl *gomock.Controller) *MockReferralRewardRepositoryInterface {
+	mock := &MockReferralRewardRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReferralRewardRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralRewardRepositoryInterface) EXPECT() *MockReferralRewardRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountInvites mocks base method.
+func (m *MockReferralRewardRepositoryInterface) CountInvites(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountInvites", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountInvites indicates an expected call of CountInvites.
+func (mr *MockReferralRewardRepositoryInterfaceMockRecorder) CountInvites(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInvites", reflect.TypeOf((*MockReferralRewardRepositoryInterface)(nil).CountInvites), ctx, userID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockReferralRewardRepositoryInterface) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*referral_reward.Reward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*referral_reward.Reward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockReferralRewardRepositoryInterfaceMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockReferralRewardRepositoryInterface)(nil).ListByUserID), ctx, userID)
+}
@@ -10,6 +10,7 @@ import (
 
 	user "astroneko-backend/internal/core/domain/user"
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
@@ -35,6 +36,21 @@ func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// ConsumeBonusQuota mocks base method.
+func (m *MockUserRepositoryInterface) ConsumeBonusQuota(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeBonusQuota", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeBonusQuota indicates an expected call of ConsumeBonusQuota.
+func (mr *MockUserRepositoryInterfaceMockRecorder) ConsumeBonusQuota(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeBonusQuota", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ConsumeBonusQuota), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockUserRepositoryInterface) Create(ctx context.Context, userArg *user.User) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -141,16 +157,16 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) List(ctx, limit, offset inter
 }
 
 // Update mocks base method.
-func (m *MockUserRepositoryInterface) Update(ctx context.Context, userArg *user.User) (*user.User, error) {
+func (m *MockUserRepositoryInterface) Update(ctx context.Context, userArg *user.User, columns []string) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, userArg)
+	ret := m.ctrl.Call(m, "Update", ctx, userArg, columns)
 	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Update(ctx, userArg interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) Update(ctx, userArg, columns interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Update), ctx, userArg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Update), ctx, userArg, columns)
 }
\ No newline at end of file
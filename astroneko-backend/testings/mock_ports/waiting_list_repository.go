@@ -5,11 +5,13 @@
 package mock_ports
 
 import (
-	"context"
-	"reflect"
+	waiting_list "astroneko-backend/internal/core/domain/waiting_list"
+	waiting_list0 "astroneko-backend/internal/core/ports/waiting_list"
+	context "context"
+	reflect "reflect"
 
-	"astroneko-backend/internal/core/domain/waiting_list"
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockWaitingListRepositoryInterface is a mock of RepositoryInterface interface.
@@ -35,6 +37,21 @@ func (m *MockWaitingListRepositoryInterface) EXPECT() *MockWaitingListRepository
 	return m.recorder
 }
 
+// CountEntries mocks base method.
+func (m *MockWaitingListRepositoryInterface) CountEntries(ctx context.Context, listID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntries", ctx, listID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntries indicates an expected call of CountEntries.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) CountEntries(ctx, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntries", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).CountEntries), ctx, listID)
+}
+
 // Create mocks base method.
 func (m *MockWaitingListRepositoryInterface) Create(ctx context.Context, waitingListUser *waiting_list.WaitingListUser) (*waiting_list.WaitingListUser, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +67,34 @@ func (mr *MockWaitingListRepositoryInterfaceMockRecorder) Create(ctx, waitingLis
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).Create), ctx, waitingListUser)
 }
 
+// CreateList mocks base method.
+func (m *MockWaitingListRepositoryInterface) CreateList(ctx context.Context, list *waiting_list.WaitingList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) CreateList(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).CreateList), ctx, list)
+}
+
+// CreditReferral mocks base method.
+func (m *MockWaitingListRepositoryInterface) CreditReferral(ctx context.Context, referrerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditReferral", ctx, referrerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreditReferral indicates an expected call of CreditReferral.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) CreditReferral(ctx, referrerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditReferral", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).CreditReferral), ctx, referrerID)
+}
+
 // Delete mocks base method.
 func (m *MockWaitingListRepositoryInterface) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -65,18 +110,18 @@ func (mr *MockWaitingListRepositoryInterfaceMockRecorder) Delete(ctx, id interfa
 }
 
 // GetByEmail mocks base method.
-func (m *MockWaitingListRepositoryInterface) GetByEmail(ctx context.Context, email string) (*waiting_list.WaitingListUser, error) {
+func (m *MockWaitingListRepositoryInterface) GetByEmail(ctx context.Context, listID uuid.UUID, email string) (*waiting_list.WaitingListUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, listID, email)
 	ret0, _ := ret[0].(*waiting_list.WaitingListUser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByEmail indicates an expected call of GetByEmail.
-func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetByEmail(ctx, listID, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetByEmail), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetByEmail), ctx, listID, email)
 }
 
 // GetByID mocks base method.
@@ -94,10 +139,100 @@ func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetByID(ctx, id interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetByID), ctx, id)
 }
 
+// GetByInviteCode mocks base method.
+func (m *MockWaitingListRepositoryInterface) GetByInviteCode(ctx context.Context, listID uuid.UUID, inviteCode string) (*waiting_list.WaitingListUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInviteCode", ctx, listID, inviteCode)
+	ret0, _ := ret[0].(*waiting_list.WaitingListUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInviteCode indicates an expected call of GetByInviteCode.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetByInviteCode(ctx, listID, inviteCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInviteCode", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetByInviteCode), ctx, listID, inviteCode)
+}
+
+// GetListByID mocks base method.
+func (m *MockWaitingListRepositoryInterface) GetListByID(ctx context.Context, id uuid.UUID) (*waiting_list.WaitingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByID", ctx, id)
+	ret0, _ := ret[0].(*waiting_list.WaitingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByID indicates an expected call of GetListByID.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetListByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByID", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetListByID), ctx, id)
+}
+
+// GetListBySlug mocks base method.
+func (m *MockWaitingListRepositoryInterface) GetListBySlug(ctx context.Context, slug string) (*waiting_list.WaitingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListBySlug", ctx, slug)
+	ret0, _ := ret[0].(*waiting_list.WaitingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListBySlug indicates an expected call of GetListBySlug.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetListBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListBySlug", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetListBySlug), ctx, slug)
+}
+
+// GetListBySlugForUpdate mocks base method.
+func (m *MockWaitingListRepositoryInterface) GetListBySlugForUpdate(ctx context.Context, slug string) (*waiting_list.WaitingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListBySlugForUpdate", ctx, slug)
+	ret0, _ := ret[0].(*waiting_list.WaitingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListBySlugForUpdate indicates an expected call of GetListBySlugForUpdate.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetListBySlugForUpdate(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListBySlugForUpdate", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetListBySlugForUpdate), ctx, slug)
+}
+
+// GetPosition mocks base method.
+func (m *MockWaitingListRepositoryInterface) GetPosition(ctx context.Context, entry *waiting_list.WaitingListUser, boost int) (*waiting_list.QueuePosition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosition", ctx, entry, boost)
+	ret0, _ := ret[0].(*waiting_list.QueuePosition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosition indicates an expected call of GetPosition.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) GetPosition(ctx, entry, boost interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosition", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).GetPosition), ctx, entry, boost)
+}
+
+// HasReferralFromFingerprint mocks base method.
+func (m *MockWaitingListRepositoryInterface) HasReferralFromFingerprint(ctx context.Context, referrerID uuid.UUID, fingerprint string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasReferralFromFingerprint", ctx, referrerID, fingerprint)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasReferralFromFingerprint indicates an expected call of HasReferralFromFingerprint.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) HasReferralFromFingerprint(ctx, referrerID, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasReferralFromFingerprint", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).HasReferralFromFingerprint), ctx, referrerID, fingerprint)
+}
+
 // List mocks base method.
-func (m *MockWaitingListRepositoryInterface) List(ctx context.Context, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
+func (m *MockWaitingListRepositoryInterface) List(ctx context.Context, listID uuid.UUID, limit, offset int) ([]*waiting_list.WaitingListUser, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, listID, limit, offset)
 	ret0, _ := ret[0].([]*waiting_list.WaitingListUser)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -105,7 +240,65 @@ func (m *MockWaitingListRepositoryInterface) List(ctx context.Context, limit, of
 }
 
 // List indicates an expected call of List.
-func (mr *MockWaitingListRepositoryInterfaceMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) List(ctx, listID, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).List), ctx, limit, offset)
-}
\ No newline at end of file
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).List), ctx, listID, limit, offset)
+}
+
+// ListLists mocks base method.
+func (m *MockWaitingListRepositoryInterface) ListLists(ctx context.Context) ([]*waiting_list.WaitingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx)
+	ret0, _ := ret[0].([]*waiting_list.WaitingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) ListLists(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).ListLists), ctx)
+}
+
+// NextQueueNumber mocks base method.
+func (m *MockWaitingListRepositoryInterface) NextQueueNumber(ctx context.Context, listID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextQueueNumber", ctx, listID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextQueueNumber indicates an expected call of NextQueueNumber.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) NextQueueNumber(ctx, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextQueueNumber", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).NextQueueNumber), ctx, listID)
+}
+
+// UpdateList mocks base method.
+func (m *MockWaitingListRepositoryInterface) UpdateList(ctx context.Context, list *waiting_list.WaitingList, columns []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, list, columns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) UpdateList(ctx, list, columns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).UpdateList), ctx, list, columns)
+}
+
+// WithTx mocks base method.
+func (m *MockWaitingListRepositoryInterface) WithTx(ctx context.Context, fn func(waiting_list0.RepositoryInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockWaitingListRepositoryInterfaceMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockWaitingListRepositoryInterface)(nil).WithTx), ctx, fn)
+}